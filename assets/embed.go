@@ -6,3 +6,9 @@ import (
 
 //go:embed templates/*
 var Templates embed.FS
+
+// Cheatsheets holds the cheatsheets installed into the config directory on
+// first use of `aura cheat`.
+//
+//go:embed cheatsheets/*
+var Cheatsheets embed.FS
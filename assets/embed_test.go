@@ -244,3 +244,21 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCheatsheetsEmbed(t *testing.T) {
+	entries, err := Cheatsheets.ReadDir("cheatsheets")
+	if err != nil {
+		t.Fatalf("Failed to read cheatsheets directory: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+
+	for _, expected := range []string{"ffmpeg.txt", "git.txt", "kubectl.txt", "tar.txt"} {
+		if !names[expected] {
+			t.Errorf("Missing expected cheatsheet: %s", expected)
+		}
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"os"
 
 	"github.com/timfewi/aura-cli-go/internal/cmd"
+	"github.com/timfewi/aura-cli-go/internal/failure"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(failure.ExitCode(err))
 	}
 }
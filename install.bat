@@ -261,13 +261,28 @@ if exist "%PROFILE_PATH%" (
     echo         $result = ^& aura.exe $args
     echo         if ($LASTEXITCODE -eq 0 -and $result^) {
     echo             Set-Location $result
+    echo             $onEnter = ^& aura.exe on-enter ^| Out-String
+    echo             if ($onEnter.Trim(^)^) { Invoke-Expression $onEnter }
     echo         } else {
     echo             return $LASTEXITCODE
     echo         }
+    echo     } elseif ($args.Count -eq 0 -or $args[0] -eq "do" -or $args[0] -eq "session"^) {
+    echo         $evalFile = New-TemporaryFile
+    echo         $env:AURA_SHELL = "powershell"
+    echo         $env:AURA_EVAL_FILE = $evalFile.FullName
+    echo         ^& aura.exe $args
+    echo         Remove-Item Env:AURA_EVAL_FILE
+    echo         $code = Get-Content $evalFile.FullName -Raw
+    echo         Remove-Item $evalFile.FullName
+    echo         if ($code^) { Invoke-Expression $code }
+    echo     } elseif ($args[0] -eq "alias"^) {
+    echo         ^& aura.exe $args
+    echo         if ($LASTEXITCODE -eq 0^) { Invoke-Expression (^& aura.exe alias init powershell ^| Out-String^) }
     echo     } else {
     echo         ^& aura.exe $args
     echo     }
     echo }
+    echo Invoke-Expression (^& aura.exe alias init powershell 2^>$null ^| Out-String^)
 ) >> "%PROFILE_PATH%"
 
 echo [SUCCESS] PowerShell integration added to profile
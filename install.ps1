@@ -242,13 +242,30 @@ function aura {
         `$result = & aura.exe `$args
         if (`$LASTEXITCODE -eq 0 -and `$result) {
             Set-Location `$result
+            `$onEnter = & aura.exe on-enter | Out-String
+            if (`$onEnter.Trim()) { Invoke-Expression `$onEnter }
         } else {
             return `$LASTEXITCODE
         }
+    } elseif (`$args.Count -eq 0 -or `$args[0] -eq "do" -or `$args[0] -eq "session") {
+        # Commands such as activating a virtualenv must run in this shell
+        `$evalFile = New-TemporaryFile
+        `$env:AURA_SHELL = "powershell"
+        `$env:AURA_EVAL_FILE = `$evalFile.FullName
+        & aura.exe `$args
+        Remove-Item Env:AURA_EVAL_FILE
+        `$code = Get-Content `$evalFile.FullName -Raw
+        Remove-Item `$evalFile.FullName
+        if (`$code) { Invoke-Expression `$code }
+    } elseif (`$args[0] -eq "alias") {
+        # Load changed aliases into this shell
+        & aura.exe `$args
+        if (`$LASTEXITCODE -eq 0) { Invoke-Expression (& aura.exe alias init powershell | Out-String) }
     } else {
         & aura.exe `$args
     }
 }
+Invoke-Expression (& aura.exe alias init powershell 2>`$null | Out-String)
 "@
     
     Add-Content -Path $PROFILE -Value $integrationCode
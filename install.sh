@@ -288,15 +288,28 @@ setup_shell_integration() {
             print_warning "Fish shell detected. Manual setup required."
             print_status "Add this to your fish config:"
             echo "function aura"
-            echo "    if test \$argv[1] = 'go'"
-            echo "        set result (command aura \$argv)"
-            echo "        if test \$status -eq 0 -a -n \"\$result\""
+            echo "    set -lx AURA_SESSION \$fish_pid"
+            echo "    if test \"\$argv[1]\" = 'go'"
+            echo "        set result (command aura \$argv); or return"
+            echo "        if test -n \"\$result\""
             echo "            cd \"\$result\""
+            echo "            command aura on-enter | source"
             echo "        end"
+            echo "        return 0"
+            echo "    else if test (count \$argv) -eq 0; or test \"\$argv[1]\" = 'do'"
+            echo "        set eval_file (mktemp)"
+            echo "        AURA_SHELL=fish AURA_EVAL_FILE=\$eval_file command aura \$argv"
+            echo "        set ret \$status"
+            echo "        source \$eval_file"
+            echo "        rm -f \$eval_file"
+            echo "        return \$ret"
+            echo "    else if test \"\$argv[1]\" = 'alias'"
+            echo "        command aura \$argv; and command aura alias init fish | source"
             echo "    else"
             echo "        command aura \$argv"
             echo "    end"
             echo "end"
+            echo "command aura alias init fish 2>/dev/null | source"
             return
             ;;
         *)
@@ -316,18 +329,33 @@ setup_shell_integration() {
 
 # Aura CLI integration
 aura() {
+    # Quick marks belong to this shell
+    local -x AURA_SESSION=$$
     if [[ "$1" == "go" ]]; then
         local result
-        result=$(command aura "$@")
-        if [[ $? -eq 0 && -n "$result" ]]; then
+        result=$(command aura "$@") || return
+        # Nothing to enter when cloud storage was listed
+        if [[ -n "$result" ]]; then
             cd "$result"
-        else
-            return 1
+            eval "$(command aura on-enter)"
         fi
+    elif [[ "$1" == "do" || "$1" == "session" || $# -eq 0 ]]; then
+        # Commands such as activating a virtualenv must run in this shell
+        local eval_file ret
+        eval_file=$(mktemp)
+        AURA_EVAL_FILE="$eval_file" command aura "$@"
+        ret=$?
+        source "$eval_file"
+        rm -f "$eval_file"
+        return $ret
+    elif [[ "$1" == "alias" ]]; then
+        # Load changed aliases into this shell
+        command aura "$@" && eval "$(command aura alias init bash)"
     else
         command aura "$@"
     fi
 }
+eval "$(command aura alias init bash 2>/dev/null)"
 EOF
     
     print_success "Shell integration added to $shell_rc"
@@ -0,0 +1,67 @@
+package ai
+
+import "context"
+
+// Assistant is the AI assistant as the commands use it. Client implements it
+// against a chat API; tests use fakes to run command flows offline.
+type Assistant interface {
+	// Ask answers a question.
+	Ask(ctx context.Context, question string) (string, error)
+	// GenerateCommitMessage writes a commit message for a staged diff,
+	// suggesting the scope if it isn't empty.
+	GenerateCommitMessage(ctx context.Context, diff, scope, issue string) (string, error)
+	// SplitCommits groups the numbered hunks of a staged diff into commits.
+	SplitCommits(ctx context.Context, hunks string) (string, error)
+	// ExplainCode explains a piece of code.
+	ExplainCode(ctx context.Context, code string) (string, error)
+	// ExplainSchema explains a database schema of the engine.
+	ExplainSchema(ctx context.Context, engine string, schema string) (string, error)
+	// SuggestCommands suggests commands fulfilling an intent.
+	SuggestCommands(ctx context.Context, intent string, workingDir string, contextInfo map[string]interface{}) (string, error)
+	// CheatSheet writes a cheat sheet for a tool.
+	CheatSheet(ctx context.Context, tool string, query string) (string, error)
+	// TLDRPage writes a tldr page for a command.
+	TLDRPage(ctx context.Context, command string) (string, error)
+	// BookmarkIntent turns a bookmark request in natural language into JSON.
+	BookmarkIntent(ctx context.Context, action, phrase, workingDir string, bookmarks map[string]string) (string, error)
+	// DebugIssue explains why a command failed.
+	DebugIssue(ctx context.Context, errorMsg string, commandRun string, environment map[string]string) (string, error)
+	// RepoHealthPlan writes a remediation plan for a repository health report.
+	RepoHealthPlan(ctx context.Context, report string) (string, error)
+	// DescribeProject describes a project in one paragraph from its summary
+	// and the beginning of its README.
+	DescribeProject(ctx context.Context, summary, readme string) (string, error)
+	// UpgradeGuidance explains how to upgrade the vulnerable dependencies of
+	// an audit report.
+	UpgradeGuidance(ctx context.Context, report string) (string, error)
+	// GenerateTests writes tests for the source of a file following the
+	// conventions of its project.
+	GenerateTests(ctx context.Context, file, source, conventions string) (string, error)
+	// DocumentCode writes doc comments for the listed symbols of a source
+	// file as JSON.
+	DocumentCode(ctx context.Context, file, source, symbols string) (string, error)
+	// RefactorCode refactors the source of a file as instructed and returns
+	// the change as a unified diff.
+	RefactorCode(ctx context.Context, file, source, instruction, feedback string) (string, error)
+	// BuildRegex writes a regular expression matching a description.
+	BuildRegex(ctx context.Context, description, flavor string) (string, error)
+	// ExplainRegex explains the parts of a regular expression.
+	ExplainRegex(ctx context.Context, pattern, flavor string) (string, error)
+	// CronExpression writes the cron expression of a schedule description.
+	CronExpression(ctx context.Context, description string) (string, error)
+	// QueryExpression writes the jq or yq expression answering a question
+	// about data with the outlined structure.
+	QueryExpression(ctx context.Context, tool, outline, question string) (string, error)
+	// DataInsights points out what stands out in the profile of a table.
+	DataInsights(ctx context.Context, profile string) (string, error)
+	// APIRequest writes the request to an API described by a spec outline
+	// that does what the user asks, as JSON.
+	APIRequest(ctx context.Context, outline, server, task string) (string, error)
+	// Standup summarizes recent activity for a standup.
+	Standup(ctx context.Context, activity string) (string, error)
+	// AnsweredBy returns the model that answered the last request and whether
+	// it was a fallback model.
+	AnsweredBy() (string, bool)
+}
+
+var _ Assistant = (*Client)(nil)
@@ -0,0 +1,264 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/timfewi/aura-cli-go/internal/secrets"
+)
+
+// scrubbed replaces secrets in recorded requests and responses.
+const scrubbed = "<scrubbed>"
+
+// Cassette records the exchanges of the client with the API to a file and
+// replays them, so tests of the AI features run without network access or an
+// API key. Requests are matched by method, path and body, leaving out system
+// prompts, which depend on the machine they were recorded on.
+//
+// Setting AURA_AI_CASSETTE to a file makes NewClient replay it, and
+// AURA_AI_RECORD=1 records to it instead, with the API key and anything
+// looking like a secret scrubbed.
+type Cassette struct {
+	path      string
+	record    bool
+	transport http.RoundTripper
+	secrets   []string
+
+	mu           sync.Mutex
+	interactions []interaction
+	used         []bool
+}
+
+// interaction is a request and the response the API gave to it.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	// Text is the body if it isn't JSON.
+	Text string `json:"text,omitempty"`
+}
+
+// cassetteFile is the format of cassettes on disk.
+type cassetteFile struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+// LoadCassette returns the cassette stored at path. Recording starts a new
+// cassette if there is none; replaying requires one. Values in secrets, such
+// as API keys, are scrubbed from recordings.
+func LoadCassette(path string, record bool, secrets ...string) (*Cassette, error) {
+	cassette := &Cassette{path: path, record: record, transport: http.DefaultTransport}
+	for _, secret := range secrets {
+		if secret != "" {
+			cassette.secrets = append(cassette.secrets, secret)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && record {
+		return cassette, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	cassette.interactions = file.Interactions
+	cassette.used = make([]bool, len(file.Interactions))
+	return cassette, nil
+}
+
+// RoundTrip replays the recorded response to req, or sends it and records
+// the response.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("failed to read request: %w", err)
+		}
+		req.Body.Close()
+	}
+	request := recordedRequest{Method: req.Method, Path: req.URL.Path, Body: c.rawJSON(body)}
+
+	if c.record {
+		return c.recordResponse(req, body, request)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := matchKey(request)
+	found := -1
+	for i, recorded := range c.interactions {
+		if matchKey(recorded.Request) != key {
+			continue
+		}
+		// Identical requests get their responses in the recorded order, the
+		// last one repeating
+		found = i
+		if !c.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("no recorded response in %s for %s %s, record one with AURA_AI_RECORD=1", c.path, req.Method, req.URL.Path)
+	}
+	c.used[found] = true
+	return response(req, c.interactions[found].Response), nil
+}
+
+// recordResponse sends req and records the response.
+func (c *Cassette) recordResponse(req *http.Request, body []byte, request recordedRequest) (*http.Response, error) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	recorded := recordedResponse{Status: resp.StatusCode}
+	if raw := c.rawJSON(responseBody); raw != nil {
+		recorded.Body = raw
+	} else {
+		recorded.Text = c.scrub(string(responseBody))
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, interaction{Request: request, Response: recorded})
+	c.used = append(c.used, true)
+	err = c.save()
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	return resp, nil
+}
+
+// save writes the cassette to its file.
+func (c *Cassette) save() error {
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(cassetteFile{Interactions: c.interactions}); err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(c.path, data.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// rawJSON returns data scrubbed of secrets if it is JSON, otherwise nil.
+func (c *Cassette) rawJSON(data []byte) json.RawMessage {
+	if len(data) == 0 || !json.Valid(data) {
+		return nil
+	}
+	var value any
+	json.Unmarshal(data, &value)
+	var scrubbedData bytes.Buffer
+	encoder := json.NewEncoder(&scrubbedData)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(c.scrubValue(value)); err != nil {
+		return nil
+	}
+	return bytes.TrimSpace(scrubbedData.Bytes())
+}
+
+// scrubValue scrubs the strings in a decoded JSON value.
+func (c *Cassette) scrubValue(value any) any {
+	switch value := value.(type) {
+	case string:
+		return c.scrub(value)
+	case []any:
+		for i, item := range value {
+			value[i] = c.scrubValue(item)
+		}
+	case map[string]any:
+		for key, item := range value {
+			value[key] = c.scrubValue(item)
+		}
+	}
+	return value
+}
+
+// scrub replaces the secrets of the cassette and anything looking like a
+// secret in text.
+func (c *Cassette) scrub(text string) string {
+	for _, secret := range c.secrets {
+		text = strings.ReplaceAll(text, secret, scrubbed)
+	}
+	for _, rule := range secrets.Rules {
+		text = rule.Pattern.ReplaceAllString(text, scrubbed)
+	}
+	return text
+}
+
+// matchKey identifies a request for replaying: its method, path and body
+// without system messages.
+func matchKey(request recordedRequest) string {
+	body := []byte(request.Body)
+	var value map[string]any
+	if json.Unmarshal(body, &value) == nil {
+		if messages, ok := value["messages"].([]any); ok {
+			var kept []any
+			for _, message := range messages {
+				if fields, ok := message.(map[string]any); ok && fields["role"] == "system" {
+					continue
+				}
+				kept = append(kept, message)
+			}
+			value["messages"] = kept
+		}
+		body, _ = json.Marshal(value)
+	}
+	return request.Method + " " + request.Path + " " + string(body)
+}
+
+// response returns the recorded response as the answer to req.
+func response(req *http.Request, recorded recordedResponse) *http.Response {
+	body := []byte(recorded.Body)
+	if body == nil {
+		body = []byte(recorded.Text)
+	}
+	return &http.Response{
+		StatusCode:    recorded.Status,
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Request:       req,
+	}
+}
@@ -3,13 +3,26 @@ package ai
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/timfewi/aura-cli-go/internal/auditlog"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
 )
 
 // Client represents an AI client for making requests to an LLM API.
@@ -17,6 +30,14 @@ type Client struct {
 	apiKey  string
 	baseURL string
 	client  *http.Client
+
+	// Review is shown every prompt before it is sent, after the privacy
+	// settings were applied, and stops it by returning an error.
+	Review func(prompt string) error
+
+	mu           sync.Mutex
+	answeredBy   string
+	usedFallback bool
 }
 
 // Message represents a chat message.
@@ -29,8 +50,23 @@ type Message struct {
 type ChatRequest struct {
 	Model       string    `json:"model"`
 	Messages    []Message `json:"messages"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Temperature float64   `json:"temperature"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
+	// Seed makes providers that support it sample the same answer again.
+	Seed int `json:"seed,omitempty"`
+}
+
+// deterministicSeed is the seed of requests in deterministic mode.
+const deterministicSeed = 1
+
+// Deterministic reports whether answers should be reproducible, for scripts
+// and CI: AURA_DETERMINISTIC=1, set by --deterministic, or the deterministic
+// AI setting. Requests are then sent with temperature 0 and a fixed seed.
+func Deterministic() bool {
+	if env := envvar.Deterministic.Get(); env != "" {
+		return env == "1" || env == "true"
+	}
+	return config.UserSettings.AI.Deterministic
 }
 
 // ChatResponse represents a response from the chat API.
@@ -44,28 +80,53 @@ type ChatResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// NewClient creates a new AI client.
+// NewClient creates a new AI client. With AURA_AI_CASSETTE set, it replays
+// the recorded exchanges of that cassette instead of calling the API, see
+// Cassette.
 func NewClient() (*Client, error) {
-	apiKey := os.Getenv("AURA_API_KEY")
+	if err := config.RequireFeature(config.FeatureAI, "the AI assistant"); err != nil {
+		return nil, err
+	}
+	cassettePath := envvar.AICassette.Get()
+	record := envvar.AIRecord.Get() == "1"
+	if cassettePath == "" || record {
+		if err := config.RequireNetwork("the AI assistant"); err != nil {
+			return nil, err
+		}
+	}
+
+	apiKey := envvar.APIKey.Get()
 	if apiKey == "" {
 		// Try OpenAI API key as fallback
-		apiKey = os.Getenv("OPENAI_API_KEY")
-		if apiKey == "" {
-			return nil, fmt.Errorf("AURA_API_KEY or OPENAI_API_KEY environment variable is required")
+		apiKey = envvar.OpenAIAPIKey.Get()
+		if apiKey == "" && (cassettePath == "" || record) {
+			return nil, failure.New(failure.Config, "AURA_API_KEY or OPENAI_API_KEY environment variable is required")
 		}
 	}
 
-	baseURL := os.Getenv("AURA_API_URL")
+	baseURL := envvar.APIURL.Get()
 	if baseURL == "" {
 		baseURL = "https://api.openai.com/v1"
 	}
+	if pinned := config.ActivePolicy.APIURL; pinned != "" {
+		baseURL = pinned
+	}
+
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if cassettePath != "" {
+		cassette, err := LoadCassette(cassettePath, record, apiKey)
+		if err != nil {
+			return nil, failure.Wrap(failure.Config, err)
+		}
+		httpClient.Transport = cassette
+	}
 
 	return &Client{
 		apiKey:  apiKey,
 		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client:  httpClient,
 	}, nil
 }
 
@@ -106,18 +167,54 @@ COMMAND FORMAT:
 Remember: You're part of the Aura ecosystem - a CLI tool focused on intelligent navigation and context-aware actions.`, runtime.GOOS, runtime.GOARCH, runtime.GOOS)
 
 	messages := []Message{
-		{Role: "system", Content: systemPrompt},
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
 		{Role: "user", Content: question},
 	}
 
 	return c.chat(ctx, messages)
 }
 
-// GenerateCommitMessage generates a Git commit message based on the diff.
-func (c *Client) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
+// plainCommitPrompt is the system prompt of commit messages in the plain
+// commit style, without type and scope.
+const plainCommitPrompt = `You are an expert Git commit message generator that follows industry best practices.
+
+COMMIT MESSAGE RULES:
+1. Write a subject line that completes "If applied, this commit will ..."
+2. Keep it under 50 characters
+3. Use present tense, imperative mood ("Add" not "Added" or "Adds")
+4. Capitalize the first word, no period at the end
+5. No type or scope prefix such as "feat:" or "fix(db):"
+6. Be specific and descriptive, focus on user impact, not implementation details
+7. If multiple changes, prioritize the most significant
+
+EXAMPLES:
+- Add fuzzy search to bookmarks
+- Handle SQLite connection timeouts gracefully
+- Update installation instructions for Windows
+
+Generate ONE concise commit message. Do not include body or footer.`
+
+// plainSplitCommits turns the conventional commit messages of SplitCommits
+// into plain ones.
+var plainSplitCommits = strings.NewReplacer(
+	"Write each message as a conventional commit: <type>(<scope>): <description>, under 50 characters, imperative mood, no period",
+	"Write each message as a plain subject line without type or scope prefix, under 50 characters, imperative mood, capitalized, no period",
+	"refactor(db): extract path normalization", "Extract path normalization",
+	"feat(cmd): add open command", "Add open command",
+)
+
+// GenerateCommitMessage generates a Git commit message based on the diff in
+// the commit style of the settings. A non-empty scope is suggested as the
+// conventional commit scope, and a
+// non-empty issue, the title and body of the issue the changes work on, is
+// given as context.
+func (c *Client) GenerateCommitMessage(ctx context.Context, diff, scope, issue string) (string, error) {
 	if diff == "" {
 		return "", fmt.Errorf("no staged changes found")
 	}
+	if err := RequireFileContents("the staged diff"); err != nil {
+		return "", err
+	}
 
 	systemPrompt := `You are an expert Git commit message generator that follows industry best practices and conventional commit standards.
 
@@ -161,19 +258,65 @@ EXAMPLES:
 - chore(deps): update Go modules to latest versions
 
 Generate ONE concise commit message. Do not include body or footer unless it's a breaking change.`
+	if config.GetCommitStyle() == config.CommitPlain {
+		systemPrompt, scope = plainCommitPrompt, ""
+	}
 
 	prompt := fmt.Sprintf("Generate a commit message for these changes:\n\n%s", diff)
+	if scope != "" {
+		prompt = fmt.Sprintf("Generate a commit message for these changes. Most of them are in %q, use it as the scope unless the diff clearly calls for another:\n\n%s", scope, diff)
+	}
+	if issue != "" {
+		prompt += fmt.Sprintf("\n\nThe changes work on this issue, describe them in its terms where it fits:\n\n%s", issue)
+	}
 
 	messages := []Message{
-		{Role: "system", Content: systemPrompt},
+		{Role: "system", Content: withCommitLanguage(systemPrompt)},
 		{Role: "user", Content: prompt},
 	}
 
 	return c.chat(ctx, messages)
 }
 
+// SplitCommits groups the numbered hunks of a staged diff into logical
+// commits. The answer is JSON: {"commits": [{"message": "...", "hunks": [1, 2]}]}.
+func (c *Client) SplitCommits(ctx context.Context, hunks string) (string, error) {
+	if err := RequireFileContents("the staged diff"); err != nil {
+		return "", err
+	}
+	systemPrompt := `You split large staged Git changes into small, logical commits that each do one thing and leave the project working.
+
+INPUT:
+The hunks of a staged diff, each introduced by a line "### Hunk <number>: <file>".
+
+RULES:
+1. Assign every hunk to exactly one commit
+2. Group hunks by purpose, not by file: a feature with its tests and docs is one commit
+3. Order commits so each builds on the ones before, such as a refactoring before the feature using it
+4. Keep all hunks of a new, deleted or renamed file in one commit
+5. Prefer 2 to 6 commits; use one commit if the changes belong together
+6. Write each message as a conventional commit: <type>(<scope>): <description>, under 50 characters, imperative mood, no period
+
+OUTPUT:
+Only JSON, without markdown fences or explanations:
+{"commits": [{"message": "refactor(db): extract path normalization", "hunks": [1, 2]}, {"message": "feat(cmd): add open command", "hunks": [3, 4, 5]}]}`
+	if config.GetCommitStyle() == config.CommitPlain {
+		systemPrompt = plainSplitCommits.Replace(systemPrompt)
+	}
+
+	messages := []Message{
+		{Role: "system", Content: withCommitLanguage(systemPrompt)},
+		{Role: "user", Content: hunks},
+	}
+
+	return c.chat(ctx, messages)
+}
+
 // ExplainCode explains a piece of code.
 func (c *Client) ExplainCode(ctx context.Context, code string) (string, error) {
+	if err := RequireFileContents("the code"); err != nil {
+		return "", err
+	}
 	systemPrompt := `You are an expert code analysis assistant specializing in clear, educational explanations for developers of all skill levels.
 
 EXPLANATION STRUCTURE:
@@ -207,7 +350,39 @@ Use markdown formatting with headers, code blocks, and emphasis where appropriat
 	prompt := fmt.Sprintf("Explain this code:\n\n%s", code)
 
 	messages := []Message{
-		{Role: "system", Content: systemPrompt},
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: prompt},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// ExplainSchema explains a database schema.
+func (c *Client) ExplainSchema(ctx context.Context, engine string, schema string) (string, error) {
+	systemPrompt := `You are an expert database architect who explains database schemas to developers joining a project.
+
+EXPLANATION STRUCTURE:
+1. **Overview**: What domain does this schema model? (1-2 sentences)
+2. **Tables**: Purpose of each table and its most important columns
+3. **Relationships**: Foreign keys and implied relationships between tables
+4. **Indexes & Constraints**: Notable indexes, unique constraints and checks
+5. **Observations**: Potential issues such as missing indexes, denormalization or naming inconsistencies
+
+GUIDELINES:
+- Be concise and concrete, reference table and column names exactly
+- Infer relationships from naming when no foreign keys are declared, and say so
+- Mention engine-specific features when they matter
+
+FORMAT:
+Use markdown formatting with headers and bullet points.`
+
+	if err := RequireFileContents("the database schema"); err != nil {
+		return "", err
+	}
+	prompt := fmt.Sprintf("Explain this %s database schema:\n\n%s", engine, schema)
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
 		{Role: "user", Content: prompt},
 	}
 
@@ -246,6 +421,8 @@ Return 3-5 relevant commands in this format:
 
 SAFETY GUIDELINES:
 - Warn about destructive operations (rm, del, format, etc.)
+- When deleting files, offer "aura rm <path>" as an alternative: it moves
+  them to Aura's trash, from where "aura trash restore" brings them back
 - Suggest dry-run options when available
 - Recommend backups for risky operations
 - Use relative paths when appropriate
@@ -259,6 +436,101 @@ SAFETY GUIDELINES:
 
 	prompt := fmt.Sprintf("User intent: %s%s", intent, contextStr)
 
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: prompt},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// CheatSheet answers a cheatsheet lookup for a tool in the format of Aura's
+// local cheatsheets, so the answer can be saved next to them.
+func (c *Client) CheatSheet(ctx context.Context, tool string, query string) (string, error) {
+	systemPrompt := fmt.Sprintf(`You are Aura's cheatsheet assistant. Answer lookups with short, copyable command examples.
+
+SYSTEM INFO:
+- OS: %s
+
+OUTPUT FORMAT:
+Return 1-5 entries, separated by blank lines. Each entry is a comment line
+starting with "# " that describes the task, followed by the command:
+
+# Extract a gzip compressed archive
+tar -xzvf archive.tar.gz
+
+RULES:
+- Output nothing but entries, no markdown code fences or prose
+- Use realistic placeholder names for files and arguments
+- Prefer the most common, portable flags
+- Mention destructive effects in the description`, runtime.GOOS)
+
+	prompt := fmt.Sprintf("Tool: %s", tool)
+	if query != "" {
+		prompt += fmt.Sprintf("\nTask: %s", query)
+	}
+
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: prompt},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// TLDRPage writes a page in the tldr-pages format for a command that has no
+// page in the tldr-pages project.
+func (c *Client) TLDRPage(ctx context.Context, command string) (string, error) {
+	systemPrompt := fmt.Sprintf(`You write pages for the tldr-pages project: concise, example-first help for command line tools.
+
+SYSTEM INFO:
+- OS: %s
+
+OUTPUT FORMAT:
+# command
+
+> One line description of the tool.
+
+- Description of the first example:
+
+`+"`command --flag {{argument}}`"+`
+
+RULES:
+- Output only the page, no markdown code fences or prose around it
+- Give 5-8 examples of the most common tasks, simplest first
+- Put arguments the user has to fill in into {{double braces}}
+- Only use flags that exist; if you do not know the tool, output a page with a single example of its --help flag`, runtime.GOOS)
+
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: fmt.Sprintf("Write the tldr page for: %s", command)},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// BookmarkIntent interprets a bookmark command phrased in natural language,
+// such as "add the folder above this one as infra", and returns it as JSON.
+func (c *Client) BookmarkIntent(ctx context.Context, action, phrase, workingDir string, bookmarks map[string]string) (string, error) {
+	systemPrompt := fmt.Sprintf(`You translate bookmark commands of Aura, a directory bookmark tool, from natural language into JSON.
+
+SYSTEM INFO:
+- OS: %s
+- Working Directory: %s
+
+OUTPUT FORMAT:
+Return only one JSON object, no markdown code fences or prose:
+- To add a bookmark: {"action": "add", "alias": "<one word>", "path": "<absolute directory path>"}
+- To remove bookmarks: {"action": "remove", "aliases": ["<existing alias>", ...]}
+- If the phrase is unclear: {"action": "unknown"}
+
+RULES:
+- Resolve relative locations against the working directory
+- Only remove aliases that exist in the given bookmarks`, runtime.GOOS, workingDir)
+
+	bookmarksJSON, _ := json.MarshalIndent(bookmarks, "", "  ")
+	prompt := fmt.Sprintf("Command: %s %s\n\nExisting bookmarks (alias to path):\n%s", action, phrase, string(bookmarksJSON))
+
 	messages := []Message{
 		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: prompt},
@@ -267,32 +539,218 @@ SAFETY GUIDELINES:
 	return c.chat(ctx, messages)
 }
 
-// chat sends a chat request to the API and returns the response.
+// chat sends a chat request to the API and returns the response. When the
+// model is unavailable or out of quota, the configured fallback models are
+// tried in order. The context the privacy settings keep back is removed
+// first.
 func (c *Client) chat(ctx context.Context, messages []Message) (string, error) {
-	model := os.Getenv("AURA_MODEL")
-	if model == "" {
-		model = "gpt-3.5-turbo" // Use a more standard, widely available model
+	endpoints, err := c.allowedEndpoints()
+	if err != nil {
+		return "", err
+	}
+	messages = redactPrompt(messages)
+	if c.Review != nil {
+		if err := c.Review(showPrompt(ConfiguredModel(), messages)); err != nil {
+			return "", err
+		}
+	}
+	if err := auditPrompt(showPrompt(ConfiguredModel(), messages)); err != nil {
+		return "", err
+	}
+
+	var lastErr error
+	for i, endpoint := range endpoints {
+		response, err := c.chatWith(ctx, endpoint, messages)
+		if err == nil {
+			c.mu.Lock()
+			c.answeredBy = endpoint.model
+			c.usedFallback = i > 0
+			c.mu.Unlock()
+			return response, nil
+		}
+		if !isUnavailable(err) {
+			return "", err
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+// auditPrompt records prompt in the audit log before it is sent. Prompts
+// that can't be recorded are not sent.
+func auditPrompt(prompt string) error {
+	if err := auditlog.Default().Append(auditlog.Prompt, prompt, ""); err != nil {
+		return failure.New(failure.General, "the prompt was not sent: %v", err)
 	}
+	return nil
+}
 
+// AnsweredBy returns the model that answered the last request, and whether it
+// was a fallback model.
+func (c *Client) AnsweredBy() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.answeredBy, c.usedFallback
+}
+
+// endpoint is a model and the API serving it.
+type endpoint struct {
+	model   string
+	baseURL string
+	apiKey  string
+}
+
+// endpoints returns the configured model followed by the fallback models.
+// AURA_FALLBACK_MODELS, a comma separated list of models of the same provider,
+// overrides the settings file.
+func (c *Client) endpoints() []endpoint {
+	endpoints := []endpoint{{model: ConfiguredModel(), baseURL: c.baseURL, apiKey: c.apiKey}}
+
+	fallbacks := config.UserSettings.AI.Fallback
+	if env := envvar.FallbackModels.Get(); env != "" {
+		fallbacks = nil
+		for _, model := range strings.Split(env, ",") {
+			fallbacks = append(fallbacks, config.FallbackModel{Model: strings.TrimSpace(model)})
+		}
+	}
+
+	for _, fallback := range fallbacks {
+		if fallback.Model == "" || (fallback.URL != "" && !config.APIURLAllowed(fallback.URL)) {
+			continue
+		}
+		next := endpoint{model: fallback.Model, baseURL: c.baseURL, apiKey: c.apiKey}
+		if fallback.URL != "" {
+			next.baseURL = strings.TrimSuffix(fallback.URL, "/")
+			next.apiKey = ""
+		}
+		if fallback.APIKeyEnv != "" {
+			next.apiKey = os.Getenv(fallback.APIKeyEnv)
+		}
+		endpoints = append(endpoints, next)
+	}
+	return endpoints
+}
+
+// allowedEndpoints returns the endpoints whose model the team settings
+// allow, skipping fallback models that aren't. The model itself must be
+// allowed.
+func (c *Client) allowedEndpoints() ([]endpoint, error) {
+	all := c.endpoints()
+	if !config.ModelAllowed(all[0].model) {
+		return nil, failure.New(failure.Config, "the model %s is not allowed by %s, which allows %s", all[0].model, config.TeamSettingsFile, strings.Join(config.Team.AI.AllowedModels, ", "))
+	}
+	var endpoints []endpoint
+	for _, endpoint := range all {
+		if config.ModelAllowed(endpoint.model) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+// inflight coalesces identical chat requests running at the same time, such
+// as the same prompt sent by the CLI and a long-running session, so only one
+// of them reaches the API and all callers share its response.
+var inflight singleflight.Group
+
+// chatWith sends a chat request to a single endpoint.
+func (c *Client) chatWith(ctx context.Context, endpoint endpoint, messages []Message) (string, error) {
 	request := ChatRequest{
-		Model:       model,
+		Model:       endpoint.model,
 		Messages:    messages,
 		Temperature: 0.7,
 		MaxTokens:   1000,
 	}
+	if Deterministic() {
+		request.Temperature = 0
+		request.Seed = deterministicSeed
+	}
 
 	requestBody, err := json.Marshal(request)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(requestBody))
+	// The shared request must not be aborted when the caller that started it
+	// gives up while others still wait; the HTTP client timeout bounds it.
+	sharedCtx := context.WithoutCancel(ctx)
+	results := inflight.DoChan(endpoint.requestKey(requestBody), func() (interface{}, error) {
+		return c.send(sharedCtx, endpoint, requestBody)
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return "", result.Err
+		}
+		return result.Val.(string), nil
+	case <-ctx.Done():
+		return "", fmt.Errorf("failed to make request: %w", ctx.Err())
+	}
+}
+
+// requestKey identifies a chat request by its endpoint, credentials and body.
+func (e endpoint) requestKey(requestBody []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(e.baseURL))
+	hash.Write([]byte{0})
+	hash.Write([]byte(e.apiKey))
+	hash.Write([]byte{0})
+	hash.Write(requestBody)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// APIError is returned when the API answers a request with an error.
+type APIError struct {
+	StatusCode int
+	// Type is the error type reported by the API, such as "insufficient_quota".
+	Type    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error: %s", e.Message)
+}
+
+// Kind classifies API errors for the exit code.
+func (e *APIError) Kind() failure.Kind {
+	return failure.API
+}
+
+// isUnavailable reports whether err means the model cannot answer right now,
+// so another model should be tried: rate limits, exhausted quota, unknown
+// models, server errors and unreachable servers.
+func isUnavailable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Type {
+		case "insufficient_quota", "model_not_found", "rate_limit_exceeded":
+			return true
+		}
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode == http.StatusNotFound ||
+			apiErr.StatusCode >= 500
+	}
+
+	// Connection errors, but not the caller giving up
+	var netErr net.Error
+	return errors.As(err, &netErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// send posts a marshalled chat request to an endpoint and returns the response.
+func (c *Client) send(ctx context.Context, endpoint endpoint, requestBody []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint.baseURL+"/chat/completions", bytes.NewBuffer(requestBody))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if endpoint.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+endpoint.apiKey)
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -305,17 +763,23 @@ func (c *Client) chat(ctx context.Context, messages []Message) (string, error) {
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
+	var response ChatResponse
+	unmarshalErr := json.Unmarshal(body, &response)
+
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+		if unmarshalErr == nil && response.Error != nil {
+			apiErr.Type = response.Error.Type
+		}
+		return "", apiErr
 	}
 
-	var response ChatResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	if unmarshalErr != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", unmarshalErr)
 	}
 
 	if response.Error != nil {
-		return "", fmt.Errorf("API error: %s", response.Error.Message)
+		return "", &APIError{StatusCode: resp.StatusCode, Type: response.Error.Type, Message: response.Error.Message}
 	}
 
 	if len(response.Choices) == 0 {
@@ -325,6 +789,374 @@ func (c *Client) chat(ctx context.Context, messages []Message) (string, error) {
 	return response.Choices[0].Message.Content, nil
 }
 
+// RepoHealthPlan writes a remediation plan for the problems in a repository
+// health report of `aura git health`.
+func (c *Client) RepoHealthPlan(ctx context.Context, report string) (string, error) {
+	systemPrompt := `You are an expert Git maintainer who helps developers bring a repository back into good shape.
+
+INPUT:
+A health report of a repository. Lines starting with ✓ are fine, lines starting with ⚠️ are problems.
+
+PLAN STRUCTURE:
+1. Order the problems by urgency: conflicts and uncommitted work first, then divergence, then hygiene
+2. For each problem, explain in one sentence why it matters
+3. Give the exact Git commands that fix it, one per line in a code block
+4. Warn before anything that rewrites history or discards work, such as removing large files from history, and suggest a backup first
+
+GUIDELINES:
+- Only address the problems in the report
+- Prefer the safest fix, such as git stash or a new branch, over destructive ones
+- Mention tools like git filter-repo or Git LFS for large files in the history
+
+FORMAT:
+Use markdown formatting with a short heading per problem.`
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: "Write a remediation plan for this repository health report:\n\n" + report},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// DescribeProject describes a project in one paragraph from the summary of
+// `aura info` and the beginning of its README, which may be empty. The README
+// is left out when the privacy settings keep file contents back.
+func (c *Client) DescribeProject(ctx context.Context, summary, readme string) (string, error) {
+	systemPrompt := `You describe software projects to developers who see them for the first time.
+
+Write ONE paragraph of 3 to 5 sentences:
+1. What the project is and who it is for
+2. Its main technologies, from the stacks and languages
+3. Its size and maturity, from the lines of code, history and dependencies
+
+GUIDELINES:
+- Rely on the README for the purpose, on the statistics for everything else
+- Do not invent features that neither mentions
+- Plain text, no headings, lists or markdown`
+
+	prompt := "Describe this project:\n\n" + summary
+	if readme != "" && RequireFileContents("the README") == nil {
+		prompt += "\n\nThe beginning of its README:\n\n" + readme
+	}
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: prompt},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// UpgradeGuidance explains how to upgrade the vulnerable dependencies of an
+// audit report of `aura deps audit`.
+func (c *Client) UpgradeGuidance(ctx context.Context, report string) (string, error) {
+	systemPrompt := `You are an expert in dependency management who helps developers fix vulnerable dependencies safely.
+
+INPUT:
+An audit report grouped by ecosystem (Go, npm, pip). Each entry gives the severity, the advisory ID, the package with its installed version and the version that fixes it. "(major upgrade)" marks fixes that need a new major version.
+
+GUIDANCE STRUCTURE:
+1. Start with the upgrades that need no major version, with the exact commands per ecosystem (go get, npm install or npm audit fix, pip install)
+2. For each major upgrade, name the breaking changes it is known for and what to check in the code
+3. For vulnerabilities without a fix, suggest mitigations or alternative packages
+4. End with how to verify: rerun the tests and 'aura deps audit'
+
+GUIDELINES:
+- Most severe first within each step
+- Never suggest npm audit fix --force without warning that it installs breaking changes
+- Say so when you are unsure about the breaking changes of a release instead of inventing them
+
+FORMAT:
+Use markdown formatting with a short heading per step.`
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: "Explain how to upgrade the dependencies of this audit report:\n\n" + report},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// GenerateTests writes table-driven tests for the source of file, following
+// the conventions of its project: the language, framework and test file,
+// with the existing test file or an example test.
+func (c *Client) GenerateTests(ctx context.Context, file, source, conventions string) (string, error) {
+	if err := RequireFileContents("the source of " + file); err != nil {
+		return "", err
+	}
+	systemPrompt := `You are an expert software engineer who writes thorough, maintainable unit tests.
+
+Write tests for the source file you are given:
+1. Cover the exported or public functions, their edge cases and error paths
+2. Use table-driven tests: one table of cases per function, run in a loop (subtests in Go, parametrize in pytest, test.each or a loop in JavaScript)
+3. Use only the given framework and the standard library of the language, no new dependencies
+4. Follow the naming, imports and style of the existing or example test file
+5. Put the tests where the test file is: same package in Go, imports relative to the test file elsewhere
+
+GUIDELINES:
+- Do not test private helpers directly unless the language convention does
+- Avoid network, sleeps and the real file system; use temporary directories where files are needed
+- Never change or guess at the behavior of the source, test what it does
+
+FORMAT:
+Return ONLY the complete content of the test file, no explanations and no markdown code fences.`
+
+	prompt := fmt.Sprintf("Write tests for %s.\n\n%s\nThe source of %s:\n\n%s", file, conventions, file, source)
+
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: prompt},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// DocumentCode writes doc comments for the listed symbols of the source of
+// file and returns them as JSON, so they can be applied without rewriting
+// the file.
+func (c *Client) DocumentCode(ctx context.Context, file, source, symbols string) (string, error) {
+	if err := RequireFileContents("the source of " + file); err != nil {
+		return "", err
+	}
+	systemPrompt := `You are an expert software engineer who writes concise, accurate API documentation.
+
+Write the doc comments for the listed exported identifiers of a source file, following the conventions of its language:
+- Go: start with the name of the identifier and read as full sentences ("Client talks to...", "New returns...")
+- Python: a docstring summary line in the imperative mood, then details and Args/Returns sections only where they add information
+- JavaScript and TypeScript: JSDoc text with @param and @returns tags for functions
+
+GUIDELINES:
+- Describe what the identifier does and why, not how it is implemented
+- Keep existing comments that are correct; only return identifiers whose comment is missing, incomplete or wrong
+- Never invent behavior that the code doesn't have
+- Keep them short: one sentence where it suffices, wrapped at about 80 characters
+
+FORMAT:
+Return ONLY this JSON, with the comment text without comment markers (no //, # or /** */):
+{"docs": [{"symbol": "<name as listed>", "doc": "<comment text>"}]}`
+
+	prompt := fmt.Sprintf("Document these identifiers of %s:\n%s\nThe source of %s:\n\n%s", file, symbols, file, source)
+
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: prompt},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// RefactorCode refactors the source of file as instructed and returns the
+// change as a unified diff. feedback tells why a previous diff failed to
+// apply, if one did.
+func (c *Client) RefactorCode(ctx context.Context, file, source, instruction, feedback string) (string, error) {
+	if err := RequireFileContents("the source of " + file); err != nil {
+		return "", err
+	}
+	systemPrompt := `You are an expert software engineer who refactors code precisely and safely.
+
+Refactor the source file as the user instructs and answer with the change as a unified diff:
+- Headers "--- a/<file>" and "+++ b/<file>" with the path as given
+- Hunks with "@@ -<start>,<count> +<start>,<count> @@" headers and 3 lines of unchanged context
+- Context and removed lines copied exactly from the source, including indentation and blank lines
+
+GUIDELINES:
+- Change only what the instruction needs; keep the behavior, names and style otherwise
+- Keep the file compiling: update every use of what you rename or move within the file
+- Never touch other files
+
+FORMAT:
+Return ONLY the diff, without explanations and without markdown code fences.`
+
+	prompt := fmt.Sprintf("Refactor %s: %s\n\nThe source of %s, with line numbers for reference only:\n\n%s", file, instruction, file, numberLines(source))
+	if feedback != "" {
+		prompt += "\n\n" + feedback + "\n\nAnswer with a corrected diff against the source above."
+	}
+
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: prompt},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// numberLines prefixes the lines of source with their numbers.
+func numberLines(source string) string {
+	lines := strings.Split(strings.TrimSuffix(source, "\n"), "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		fmt.Fprintf(&b, "%4d| %s\n", i+1, line)
+	}
+	return b.String()
+}
+
+// regexFormat is the JSON format of the answers of BuildRegex and
+// ExplainRegex.
+const regexFormat = `FORMAT:
+Return ONLY this JSON:
+{"pattern": "<the expression, without delimiters or flags outside of it>", "parts": [{"token": "<a part of the expression, in order>", "meaning": "<what it matches, in a few words>"}], "notes": "<caveats such as false positives or flags to set, or empty>"}`
+
+// BuildRegex writes a regular expression of flavor matching description and
+// explains its parts, as JSON.
+func (c *Client) BuildRegex(ctx context.Context, description, flavor string) (string, error) {
+	systemPrompt := fmt.Sprintf(`You are an expert in regular expressions who writes precise, readable patterns.
+
+Write a %s regular expression for what the user describes:
+- Use only the syntax of %s; RE2 has no lookarounds or backreferences
+- Prefer anchors and word boundaries over matching too much
+- Use named groups for the parts a user would want to extract
+- Split the explanation into the tokens of the expression, in order, so that they concatenate to it
+
+%s`, flavor, flavor, regexFormat)
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: "Write a regular expression that matches: " + description},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// ExplainRegex explains the parts of a regular expression of flavor, as
+// JSON.
+func (c *Client) ExplainRegex(ctx context.Context, pattern, flavor string) (string, error) {
+	systemPrompt := fmt.Sprintf(`You are an expert in regular expressions who explains patterns to developers.
+
+Explain the %s regular expression the user gives:
+- Split it into its tokens, in order, so that they concatenate to it, and say what each matches
+- Return the expression unchanged as the pattern
+- Mention in the notes syntax %s doesn't support and likely mistakes
+
+%s`, flavor, flavor, regexFormat)
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: "Explain this regular expression: " + pattern},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// CronExpression writes the five field cron expression of a schedule
+// described in words, such as "every weekday at 7am".
+func (c *Client) CronExpression(ctx context.Context, description string) (string, error) {
+	systemPrompt := `You are an expert in cron who turns schedule descriptions into crontab expressions.
+
+Write the standard five field cron expression (minute, hour, day of month, month, day of week) for the schedule the user describes:
+- Use only numbers, *, ranges, lists and steps, no seconds, years or extensions such as L, W or #
+- Count days of the week from 0 for Sunday
+- Mind that cron runs on either day when both day fields are restricted
+- When the description is ambiguous, pick the most common reading
+
+FORMAT:
+Return ONLY the expression on a single line, without explanation or code fences.`
+
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: "Write the cron expression for: " + description},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// QueryExpression writes the expression of tool, jq or yq, answering
+// question about data with the structure of outline, as JSON. Only the
+// outline is sent, not the data.
+func (c *Client) QueryExpression(ctx context.Context, tool, outline, question string) (string, error) {
+	systemPrompt := fmt.Sprintf(`You are an expert in %s who answers questions about data with a single expression.
+
+Write the %s expression answering the user's question about their data:
+- The data has the structure outlined below, as paths with their types; the values are not shown
+- Use only paths of the outline and filters %s supports
+- Keep the expression on a single line, without the %s command or shell quotes around it
+- Set raw to true when the answer is plain strings to print one per line
+
+DATA OUTLINE:
+%s
+FORMAT:
+Return ONLY this JSON:
+{"expression": "<the expression>", "raw": <true or false>, "explanation": "<what the expression does, in one sentence>"}`, tool, tool, tool, tool, outline)
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: question},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// DataInsights points out what stands out in the profile of a table, its
+// columns with their types, nulls and statistics. The rows themselves are
+// never sent.
+func (c *Client) DataInsights(ctx context.Context, profile string) (string, error) {
+	systemPrompt := `You are a data analyst who reviews the profile of a dataset before it is used.
+
+You only see the profile of the table, not its rows. Point out what stands out:
+- Data quality issues such as many nulls, mixed types or suspicious ranges
+- Columns that look like identifiers, keys, dates or categories
+- What the dataset likely describes and questions worth asking of it
+- Cleanup steps to take before analysis
+
+Be concise and use a short bulleted list. Don't restate the profile.`
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: "Profile of the dataset:\n\n" + profile},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// APIRequest writes the request to the API of outline, an outline of its
+// OpenAPI spec, that does what task asks, as JSON in the item syntax of
+// 'aura http'. Credentials are left as {{VAR}} placeholders.
+func (c *Client) APIRequest(ctx context.Context, outline, server, task string) (string, error) {
+	systemPrompt := fmt.Sprintf(`You are an API expert who turns requests into calls of the API described below.
+
+Write the single HTTP request doing what the user asks:
+- Use only operations, parameters and fields of the outline; * marks required ones
+- Send it to the server %s
+- Write items in HTTPie syntax: headers as Name:value, query parameters as name==value, JSON string fields as name=value and other JSON fields as name:=json
+- Fill path parameters into the URL
+- Never invent credentials: use a placeholder such as {{API_TOKEN}} for tokens and keys, as in Authorization:Bearer {{API_TOKEN}}
+- If the request cannot be done with this API, say so in the explanation and leave the URL empty
+
+API OUTLINE:
+%s
+FORMAT:
+Return ONLY this JSON:
+{"method": "<HTTP method>", "url": "<full URL>", "items": ["<item>", ...], "explanation": "<what the request does, in one sentence>"}`, server, outline)
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: task},
+	}
+
+	return c.chat(ctx, messages)
+}
+
+// Standup writes a bullet summary for a standup or status update from
+// activity, the commits, visited directories and commands of a period.
+func (c *Client) Standup(ctx context.Context, activity string) (string, error) {
+	systemPrompt := `You are a developer writing their own standup update from what they did.
+
+Summarize the activity below as short bullets in the first person:
+- Group the work by project and describe what was achieved, not every commit
+- Use the commands and directories only to tell what else was worked on, such as reviews, debugging or deployments
+- Mention work that looks unfinished, such as work in progress commits
+- Never invent work that the activity doesn't show, and leave out trivial commands
+
+Return only the bullets, at most 8, as a markdown list.`
+
+	messages := []Message{
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
+		{Role: "user", Content: activity},
+	}
+
+	return c.chat(ctx, messages)
+}
+
 // DebugIssue helps debug errors and issues with context-aware suggestions.
 func (c *Client) DebugIssue(ctx context.Context, errorMsg string, commandRun string, environment map[string]string) (string, error) {
 	systemPrompt := fmt.Sprintf(`You are Aura's debugging assistant. Help users understand and resolve technical issues with actionable solutions.
@@ -377,7 +1209,7 @@ Error message: %s%s
 Please help me understand and fix this issue.`, commandRun, errorMsg, envStr)
 
 	messages := []Message{
-		{Role: "system", Content: systemPrompt},
+		{Role: "system", Content: withAnswerLanguage(systemPrompt)},
 		{Role: "user", Content: prompt},
 	}
 
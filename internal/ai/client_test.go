@@ -2,14 +2,34 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
 )
 
+func TestMain(m *testing.M) {
+	// Keep the audit log of prompts out of the package directory
+	tempDir, err := os.MkdirTemp("", "aura_ai_test_*")
+	if err != nil {
+		panic(err)
+	}
+	config.StateDir = tempDir
+
+	code := m.Run()
+
+	os.RemoveAll(tempDir)
+	os.Exit(code)
+}
+
 func TestNewClient(t *testing.T) {
 	// Save original environment
 	originalKey := os.Getenv("AURA_API_KEY")
@@ -198,7 +218,7 @@ index 0000000..1234567
 +}`
 
 	ctx := context.Background()
-	message, err := client.GenerateCommitMessage(ctx, testDiff)
+	message, err := client.GenerateCommitMessage(ctx, testDiff, "", "")
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -365,3 +385,234 @@ func TestClientWithInvalidJSON(t *testing.T) {
 		t.Errorf("Expected JSON error, got: %v", err)
 	}
 }
+
+func TestClientCoalescesConcurrentRequests(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(`{"choices":[{"message":{"content":"shared response"}}]}`)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		apiKey:  "sk-test-key",
+		baseURL: server.URL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	const callers = 5
+	var wg sync.WaitGroup
+	responses := make([]string, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = client.Ask(context.Background(), "same question")
+		}(i)
+	}
+
+	// Wait for the first request to arrive before letting it finish
+	for requests.Load() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 API request, got %d", got)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || responses[i] != "shared response" {
+			t.Errorf("Caller %d got %q, %v", i, responses[i], errs[i])
+		}
+	}
+
+	// Requests after the shared one finished are sent again
+	if _, err := client.Ask(context.Background(), "same question"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected a new API request, got %d requests", got)
+	}
+}
+
+func TestClientListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/models" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		response := `{"data": [
+			{"id": "gpt-4o-2024-08-06", "owned_by": "system"},
+			{"id": "llama-3-70b", "context_length": 8192},
+			{"id": "custom-model"}
+		]}`
+		if _, err := w.Write([]byte(response)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		apiKey:  "sk-test-key",
+		baseURL: server.URL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels() error = %v", err)
+	}
+
+	expected := map[string]int{
+		"custom-model":      0,
+		"gpt-4o-2024-08-06": 128000,
+		"llama-3-70b":       8192,
+	}
+	if len(models) != len(expected) || models[0].ID != "custom-model" {
+		t.Fatalf("Unexpected models %+v", models)
+	}
+	for _, model := range models {
+		if model.ContextLength != expected[model.ID] {
+			t.Errorf("ContextLength of %s = %d, want %d", model.ID, model.ContextLength, expected[model.ID])
+		}
+	}
+
+	if FindModel(models, "llama-3-70b") == nil || FindModel(models, "gpt-4o") != nil {
+		t.Error("FindModel() must match IDs exactly")
+	}
+}
+
+func TestConfiguredModel(t *testing.T) {
+	originalSettings := config.UserSettings
+	defer func() { config.UserSettings = originalSettings }()
+
+	t.Setenv("AURA_MODEL", "")
+	config.UserSettings = config.Settings{}
+	if model := ConfiguredModel(); model != DefaultModel {
+		t.Errorf("ConfiguredModel() = %s, want %s", model, DefaultModel)
+	}
+
+	config.UserSettings.AI.Model = "gpt-4o"
+	if model := ConfiguredModel(); model != "gpt-4o" {
+		t.Errorf("ConfiguredModel() = %s, want gpt-4o", model)
+	}
+
+	t.Setenv("AURA_MODEL", "gpt-4o-mini")
+	if model := ConfiguredModel(); model != "gpt-4o-mini" {
+		t.Errorf("ConfiguredModel() = %s, want gpt-4o-mini", model)
+	}
+}
+
+func TestClientFallbackModels(t *testing.T) {
+	var models []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		mu.Lock()
+		models = append(models, request.Model)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch request.Model {
+		case "primary":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"message": "quota exceeded", "type": "insufficient_quota"}}`))
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "model not found", "type": "invalid_request_error"}}`))
+		default:
+			w.Write([]byte(`{"choices":[{"message":{"content":"answer from ` + request.Model + `"}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	originalSettings := config.UserSettings
+	defer func() { config.UserSettings = originalSettings }()
+	config.UserSettings = config.Settings{AI: config.AISettings{
+		Model: "primary",
+		Fallback: []config.FallbackModel{
+			{Model: "missing"},
+			{Model: "backup"},
+			{Model: "unused"},
+		},
+	}}
+	t.Setenv("AURA_MODEL", "")
+	t.Setenv("AURA_FALLBACK_MODELS", "")
+
+	client := &Client{
+		apiKey:  "sk-test-key",
+		baseURL: server.URL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	response, err := client.Ask(context.Background(), "fallback question")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response != "answer from backup" {
+		t.Errorf("Response = %q, want answer from backup", response)
+	}
+	if model, fallback := client.AnsweredBy(); model != "backup" || !fallback {
+		t.Errorf("AnsweredBy() = %s, %v, want backup, true", model, fallback)
+	}
+	if strings.Join(models, ",") != "primary,missing,backup" {
+		t.Errorf("Unexpected models tried: %v", models)
+	}
+
+	// Errors other than unavailability are not retried
+	config.UserSettings.AI.Fallback = nil
+	t.Setenv("AURA_FALLBACK_MODELS", "backup")
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"message": "Invalid API key", "type": "authentication_error"}}`))
+	}))
+	defer unauthorized.Close()
+
+	client.baseURL = unauthorized.URL
+	if _, err := client.Ask(context.Background(), "fallback question"); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("Expected authentication error, got %v", err)
+	}
+}
+
+func TestClientDeterministic(t *testing.T) {
+	var request ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{apiKey: "sk-test-key", baseURL: server.URL, client: &http.Client{Timeout: 30 * time.Second}}
+
+	tests := []struct {
+		env             string
+		wantTemperature float64
+		wantSeed        int
+	}{
+		{"", 0.7, 0},
+		{"1", 0, deterministicSeed},
+		{"0", 0.7, 0},
+	}
+
+	for i, tt := range tests {
+		t.Setenv("AURA_DETERMINISTIC", tt.env)
+		request = ChatRequest{}
+		if _, err := client.Ask(context.Background(), fmt.Sprintf("question %d", i)); err != nil {
+			t.Fatalf("Ask() error = %v", err)
+		}
+		if request.Temperature != tt.wantTemperature || request.Seed != tt.wantSeed {
+			t.Errorf("With AURA_DETERMINISTIC=%q sent temperature %v and seed %d, want %v and %d", tt.env, request.Temperature, request.Seed, tt.wantTemperature, tt.wantSeed)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+)
+
+// DefaultEmbeddingModel is used when no embedding model is configured.
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+// EmbeddingModel returns the model used for embeddings: AURA_EMBEDDING_MODEL,
+// the model in the settings file or DefaultEmbeddingModel.
+func EmbeddingModel() string {
+	if model := envvar.EmbeddingModel.Get(); model != "" {
+		return model
+	}
+	if config.UserSettings.AI.Embeddings.Model != "" {
+		return config.UserSettings.AI.Embeddings.Model
+	}
+	return DefaultEmbeddingModel
+}
+
+// EmbeddingsConfigured reports whether an embedding model or API is set up,
+// in AURA_EMBEDDING_MODEL or the settings file, rather than only defaulted.
+func EmbeddingsConfigured() bool {
+	settings := config.UserSettings.AI.Embeddings
+	return envvar.EmbeddingModel.Get() != "" || settings.Model != "" || settings.URL != ""
+}
+
+// embeddingEndpoint returns the API serving the embedding model.
+func (c *Client) embeddingEndpoint() endpoint {
+	settings := config.UserSettings.AI.Embeddings
+	e := endpoint{model: EmbeddingModel(), baseURL: c.baseURL, apiKey: c.apiKey}
+	if settings.URL != "" && !config.APIURLAllowed(settings.URL) {
+		// The policy pins the API: embeddings use it, without the key of
+		// the other API
+		return e
+	}
+	if settings.URL != "" {
+		e.baseURL = strings.TrimSuffix(settings.URL, "/")
+		e.apiKey = ""
+	}
+	if settings.APIKeyEnv != "" {
+		e.apiKey = os.Getenv(settings.APIKeyEnv)
+	}
+	return e
+}
+
+// Embed returns the embedding vectors of the inputs, in the same order.
+func (c *Client) Embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	endpoint := c.embeddingEndpoint()
+
+	r := currentRedactor()
+	redacted := make([]string, len(inputs))
+	shown := make([]Message, len(inputs))
+	for i, input := range inputs {
+		redacted[i] = r.redact(input)
+		shown[i] = Message{Role: "input", Content: redacted[i]}
+	}
+	if c.Review != nil {
+		if err := c.Review(showPrompt(endpoint.model, shown)); err != nil {
+			return nil, err
+		}
+	}
+	if err := auditPrompt(showPrompt(endpoint.model, shown)); err != nil {
+		return nil, err
+	}
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"model": endpoint.model,
+		"input": redacted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint.baseURL+"/embeddings", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if endpoint.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+endpoint.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	vectors := make([][]float32, len(inputs))
+	for _, data := range response.Data {
+		if data.Index < 0 || data.Index >= len(inputs) {
+			return nil, fmt.Errorf("invalid embedding index %d", data.Index)
+		}
+		vectors[data.Index] = data.Embedding
+	}
+	for i, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("no embedding returned for input %d", i)
+		}
+	}
+	return vectors, nil
+}
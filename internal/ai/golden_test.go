@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// goldenDiff is the staged diff the commit message golden test sends.
+const goldenDiff = `diff --git a/internal/cmd/open.go b/internal/cmd/open.go
+new file mode 100644
+--- /dev/null
++++ b/internal/cmd/open.go
+@@ -0,0 +1,8 @@
++package cmd
++
++var openCmd = &cobra.Command{
++	Use:   "open <alias|path|url>",
++	Short: "Open a bookmark, file or URL",
++	Args:  cobra.ExactArgs(1),
++	RunE:  runOpen,
++}
+`
+
+// TestGolden replays the cassettes in testdata to check the AI features end
+// to end without an API key. After changing a prompt, record them again:
+//
+//	AURA_AI_RECORD=1 AURA_API_KEY=... go test ./internal/ai -run TestGolden
+func TestGolden(t *testing.T) {
+	record := os.Getenv("AURA_AI_RECORD") == "1"
+	if !record {
+		t.Setenv("AURA_API_KEY", "")
+		t.Setenv("OPENAI_API_KEY", "")
+		t.Setenv("AURA_API_URL", "")
+	}
+	t.Setenv("AURA_MODEL", "")
+	t.Setenv("AURA_FALLBACK_MODELS", "")
+	t.Setenv("AURA_DETERMINISTIC", "")
+
+	tests := []struct {
+		name string
+		run  func(*Client) (string, error)
+		want *regexp.Regexp
+	}{
+		{
+			name: "commit_message",
+			run: func(client *Client) (string, error) {
+				return client.GenerateCommitMessage(context.Background(), goldenDiff, "cmd", "")
+			},
+			want: regexp.MustCompile(`^(feat|fix|docs|style|refactor|test|chore|perf)(\([\w-]+\))?: \S`),
+		},
+		{
+			name: "explain_code",
+			run: func(client *Client) (string, error) {
+				return client.ExplainCode(context.Background(), "find . -name '*.go' | xargs grep -l TODO")
+			},
+			want: regexp.MustCompile(`(?s)xargs.*grep`),
+		},
+		{
+			name: "suggest_commands",
+			run: func(client *Client) (string, error) {
+				return client.SuggestCommands(context.Background(), "free up disk space", "/home/me/project", map[string]interface{}{"project_type": "go"})
+			},
+			want: regexp.MustCompile("(?s)`[^`]+`"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.name+".json")
+			if record {
+				os.Remove(path)
+			}
+			t.Setenv("AURA_AI_CASSETTE", path)
+
+			client, err := NewClient()
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			got, err := tt.run(client)
+			if err != nil {
+				t.Fatalf("Request failed, record the cassette again if the prompt changed: %v", err)
+			}
+			if !tt.want.MatchString(got) {
+				t.Errorf("Response doesn't match %s:\n%s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+)
+
+// languageNames maps language codes to the names prompts use for them.
+var languageNames = map[string]string{
+	"ar": "Arabic", "cs": "Czech", "da": "Danish", "de": "German", "el": "Greek",
+	"en": "English", "es": "Spanish", "fi": "Finnish", "fr": "French", "he": "Hebrew",
+	"hi": "Hindi", "hu": "Hungarian", "it": "Italian", "ja": "Japanese", "ko": "Korean",
+	"nl": "Dutch", "no": "Norwegian", "pl": "Polish", "pt": "Portuguese", "ro": "Romanian",
+	"ru": "Russian", "sv": "Swedish", "tr": "Turkish", "uk": "Ukrainian", "zh": "Chinese",
+}
+
+// LanguageName returns the English name of language, given as a code such as
+// "de" or "pt_BR" or as a name such as "German". Unknown languages are
+// returned unchanged.
+func LanguageName(language string) string {
+	language = strings.TrimSpace(language)
+	code := strings.ToLower(language)
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return language
+}
+
+// CommitLanguage returns the language commit messages are written in:
+// AURA_COMMIT_LANGUAGE, the commit_language AI setting or English.
+func CommitLanguage() string {
+	if language := envvar.CommitLanguage.Get(); language != "" {
+		return LanguageName(language)
+	}
+	if config.UserSettings.AI.CommitLanguage != "" {
+		return LanguageName(config.UserSettings.AI.CommitLanguage)
+	}
+	return "English"
+}
+
+// AnswerLanguage returns the language of answers: AURA_LANGUAGE, the
+// language AI setting, or "" to answer in the language of the question.
+func AnswerLanguage() string {
+	if language := envvar.Language.Get(); language != "" {
+		return LanguageName(language)
+	}
+	return LanguageName(config.UserSettings.AI.Language)
+}
+
+// withAnswerLanguage adds the answer language to a system prompt.
+func withAnswerLanguage(systemPrompt string) string {
+	language := AnswerLanguage()
+	if language == "" {
+		return systemPrompt
+	}
+	return systemPrompt + `
+
+LANGUAGE:
+Answer in ` + language + `. Keep commands, code, flags, file names and error messages as they are.`
+}
+
+// withCommitLanguage adds the commit message language to the system prompt
+// of commit messages. The conventional commit type and scope stay English,
+// tools parsing them expect it.
+func withCommitLanguage(systemPrompt string) string {
+	language := CommitLanguage()
+	if language == "English" {
+		return systemPrompt
+	}
+	if config.GetCommitStyle() == config.CommitPlain {
+		return systemPrompt + `
+
+LANGUAGE:
+Write the message in ` + language + `, following the conventions of commit messages in that language.`
+	}
+	return systemPrompt + `
+
+LANGUAGE:
+Write the description in ` + language + `, following the conventions of commit messages in that language, and keep the type and scope in English. For example: fix(db): <description in ` + language + `>`
+}
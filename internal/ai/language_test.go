@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+func TestLanguageName(t *testing.T) {
+	for language, want := range map[string]string{
+		"de":        "German",
+		"ja":        "Japanese",
+		"pt_BR":     "Portuguese",
+		"DE":        "German",
+		"German":    "German",
+		"Klingon":   "Klingon",
+		"":          "",
+		" es ":      "Spanish",
+		"zh-Hans":   "Chinese",
+		"Brazilian": "Brazilian",
+	} {
+		if got := LanguageName(language); got != want {
+			t.Errorf("LanguageName(%q) = %q, want %q", language, got, want)
+		}
+	}
+}
+
+func TestCommitLanguage(t *testing.T) {
+	original := config.UserSettings
+	defer func() { config.UserSettings = original }()
+
+	t.Setenv("AURA_COMMIT_LANGUAGE", "")
+	config.UserSettings.AI.CommitLanguage = ""
+	if got := withCommitLanguage("prompt"); got != "prompt" {
+		t.Errorf("Expected English commit messages to leave the prompt unchanged, got %q", got)
+	}
+
+	config.UserSettings.AI.CommitLanguage = "de"
+	if got := withCommitLanguage("prompt"); !strings.Contains(got, "Write the description in German") || !strings.Contains(got, "type and scope in English") {
+		t.Errorf("Expected German commit messages, got %q", got)
+	}
+
+	t.Setenv("AURA_COMMIT_LANGUAGE", "ja")
+	if got := CommitLanguage(); got != "Japanese" {
+		t.Errorf("CommitLanguage() = %q, want the environment's Japanese", got)
+	}
+}
+
+func TestAnswerLanguage(t *testing.T) {
+	original := config.UserSettings
+	defer func() { config.UserSettings = original }()
+
+	t.Setenv("AURA_LANGUAGE", "")
+	config.UserSettings.AI.Language = ""
+	if got := withAnswerLanguage("prompt"); got != "prompt" {
+		t.Errorf("Expected no language without a setting, got %q", got)
+	}
+
+	config.UserSettings.AI.Language = "ja"
+	if got := withAnswerLanguage("prompt"); !strings.Contains(got, "Answer in Japanese") {
+		t.Errorf("Expected answers in Japanese, got %q", got)
+	}
+}
+
+func TestPlainCommitStyle(t *testing.T) {
+	original := config.UserSettings
+	defer func() { config.UserSettings = original }()
+	t.Setenv("AURA_COMMIT_LANGUAGE", "de")
+	config.UserSettings.Git.CommitStyle = config.CommitPlain
+
+	var prompts []string
+	client := &Client{Review: func(prompt string) error {
+		prompts = append(prompts, prompt)
+		return errors.New("not sent")
+	}}
+	client.GenerateCommitMessage(context.Background(), "diff --git a/x b/x", "internal/db", "")
+	client.SplitCommits(context.Background(), "### Hunk 1: x")
+
+	if len(prompts) != 2 {
+		t.Fatalf("Review was shown %d prompts, want 2", len(prompts))
+	}
+	for _, prompt := range prompts {
+		if strings.Contains(prompt, "<type>") || strings.Contains(prompt, "feat(") || strings.Contains(prompt, "internal/db") || strings.Contains(prompt, "keep the type and scope") {
+			t.Errorf("the prompt asks for conventional commits:\n%s", prompt)
+		}
+		if !strings.Contains(prompt, "Write the message in German") && !strings.Contains(prompt, "Write each message as a plain subject line") {
+			t.Errorf("the prompt doesn't ask for plain messages:\n%s", prompt)
+		}
+	}
+}
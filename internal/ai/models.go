@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+)
+
+// DefaultModel is used when no model is configured.
+const DefaultModel = "gpt-3.5-turbo"
+
+// Model describes a model offered by the provider.
+type Model struct {
+	ID      string
+	OwnedBy string
+	// ContextLength is the context window in tokens, 0 if unknown.
+	ContextLength int
+}
+
+// knownContextLengths fills in the context window of common models for
+// providers that do not report it, such as OpenAI.
+var knownContextLengths = map[string]int{
+	"gpt-3.5-turbo": 16385,
+	"gpt-4":         8192,
+	"gpt-4-turbo":   128000,
+	"gpt-4o":        128000,
+	"gpt-4o-mini":   128000,
+	"gpt-4.1":       1047576,
+	"gpt-4.1-mini":  1047576,
+	"gpt-4.1-nano":  1047576,
+	"o1":            200000,
+	"o3":            200000,
+	"o3-mini":       200000,
+	"o4-mini":       200000,
+}
+
+// ConfiguredModel returns the model used for requests: AURA_MODEL, the model
+// in the settings file, the model of the team settings or DefaultModel.
+func ConfiguredModel() string {
+	if model := envvar.Model.Get(); model != "" {
+		return model
+	}
+	if config.UserSettings.AI.Model != "" {
+		return config.UserSettings.AI.Model
+	}
+	if config.Team.AI.Model != "" {
+		return config.Team.AI.Model
+	}
+	return DefaultModel
+}
+
+// ListModels returns the models offered by the provider, sorted by ID.
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	// Providers report the context window under different names.
+	var response struct {
+		Data []struct {
+			ID               string `json:"id"`
+			OwnedBy          string `json:"owned_by"`
+			ContextLength    int    `json:"context_length"`
+			ContextWindow    int    `json:"context_window"`
+			MaxContextLength int    `json:"max_context_length"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	models := make([]Model, 0, len(response.Data))
+	for _, data := range response.Data {
+		model := Model{ID: data.ID, OwnedBy: data.OwnedBy}
+		for _, length := range []int{data.ContextLength, data.ContextWindow, data.MaxContextLength, knownContextLength(data.ID)} {
+			if length > 0 {
+				model.ContextLength = length
+				break
+			}
+		}
+		models = append(models, model)
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
+// knownContextLength returns the context window of a known model, matching
+// dated snapshots such as "gpt-4o-2024-08-06" by their base name.
+func knownContextLength(id string) int {
+	best := ""
+	for name := range knownContextLengths {
+		if (id == name || strings.HasPrefix(id, name+"-")) && len(name) > len(best) {
+			best = name
+		}
+	}
+	return knownContextLengths[best]
+}
+
+// FindModel returns the model with the ID, or nil if it is not offered.
+func FindModel(models []Model, id string) *Model {
+	for i := range models {
+		if models[i].ID == id {
+			return &models[i]
+		}
+	}
+	return nil
+}
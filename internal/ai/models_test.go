@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestEnvDefaults(t *testing.T) {
+	for _, test := range []struct {
+		variable envvar.Var
+		value    string
+	}{
+		{envvar.Model, DefaultModel},
+		{envvar.EmbeddingModel, DefaultEmbeddingModel},
+	} {
+		if !strings.Contains(test.variable.Default, test.value) {
+			t.Errorf("%s default = %q, want it to name %s", test.variable.Name, test.variable.Default, test.value)
+		}
+	}
+}
+
+func TestAllowedModels(t *testing.T) {
+	original := config.Team
+	defer func() { config.Team = original }()
+	config.Team.AI.AllowedModels = []string{"gpt-4o*"}
+	t.Setenv("AURA_FALLBACK_MODELS", "gpt-3.5-turbo, gpt-4o-mini")
+
+	t.Setenv("AURA_MODEL", "gpt-4o")
+	endpoints, err := (&Client{}).allowedEndpoints()
+	if err != nil || len(endpoints) != 2 || endpoints[1].model != "gpt-4o-mini" {
+		t.Errorf("allowedEndpoints() = %+v, %v, want gpt-4o and the allowed fallback", endpoints, err)
+	}
+
+	t.Setenv("AURA_MODEL", "gpt-3.5-turbo")
+	reviewed := false
+	client := &Client{Review: func(string) error {
+		reviewed = true
+		return nil
+	}}
+	if _, err := client.Ask(context.Background(), "hi"); failure.KindOf(err) != failure.Config || reviewed {
+		t.Errorf("Ask() = %v with a model that isn't allowed, want a config error before the prompt", err)
+	}
+}
+
+func TestPolicyPinsAPI(t *testing.T) {
+	originalPolicy, originalUser := config.ActivePolicy, config.UserSettings
+	defer func() { config.ActivePolicy, config.UserSettings = originalPolicy, originalUser }()
+	config.ActivePolicy = config.Policy{APIURL: "https://llm.example.com/v1"}
+	t.Setenv("AURA_API_URL", "https://api.openai.com/v1")
+	t.Setenv("AURA_API_KEY", "sk-test")
+	t.Setenv("AURA_FALLBACK_MODELS", "")
+	t.Setenv("OTHER_KEY", "other")
+	config.UserSettings.AI.Fallback = []config.FallbackModel{
+		{Model: "llama3.1", URL: "http://localhost:11434/v1"},
+		{Model: "gpt-4o-mini"},
+		{Model: "gpt-4o", URL: "https://llm.example.com/v1/"},
+	}
+	config.UserSettings.AI.Embeddings = config.EmbeddingSettings{URL: "http://localhost:11434/v1", APIKeyEnv: "OTHER_KEY"}
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	endpoints := client.endpoints()
+	if len(endpoints) != 3 || endpoints[1].model != "gpt-4o-mini" || endpoints[2].model != "gpt-4o" {
+		t.Fatalf("endpoints() = %+v, want the fallbacks of other APIs skipped", endpoints)
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.baseURL != "https://llm.example.com/v1" {
+			t.Errorf("endpoint %s uses %s, want the pinned API", endpoint.model, endpoint.baseURL)
+		}
+	}
+	if e := client.embeddingEndpoint(); e.baseURL != "https://llm.example.com/v1" || e.apiKey != "sk-test" {
+		t.Errorf("embeddingEndpoint() = %+v, want the pinned API and its key", e)
+	}
+}
+
+func TestEmbeddingsConfigured(t *testing.T) {
+	original := config.UserSettings
+	defer func() { config.UserSettings = original }()
+	t.Setenv("AURA_EMBEDDING_MODEL", "")
+
+	config.UserSettings.AI.Embeddings = config.EmbeddingSettings{}
+	if EmbeddingsConfigured() {
+		t.Error("EmbeddingsConfigured() = true without settings")
+	}
+	config.UserSettings.AI.Embeddings = config.EmbeddingSettings{URL: "http://localhost:11434/v1"}
+	if !EmbeddingsConfigured() {
+		t.Error("EmbeddingsConfigured() = false with an embedding API")
+	}
+	config.UserSettings.AI.Embeddings = config.EmbeddingSettings{}
+	t.Setenv("AURA_EMBEDDING_MODEL", "nomic-embed-text")
+	if !EmbeddingsConfigured() {
+		t.Error("EmbeddingsConfigured() = false with AURA_EMBEDDING_MODEL")
+	}
+}
+
+func TestPolicyDisablesAI(t *testing.T) {
+	original := config.ActivePolicy
+	defer func() { config.ActivePolicy = original }()
+	config.ActivePolicy = config.Policy{Disable: []string{"ai"}, Contact: "it@example.com"}
+
+	_, err := NewClient()
+	if failure.KindOf(err) != failure.Config || !strings.Contains(err.Error(), "it@example.com") {
+		t.Errorf("NewClient() = %v, want the AI assistant disabled", err)
+	}
+}
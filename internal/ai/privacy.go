@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// RequireFileContents returns an error if the privacy settings keep file
+// contents from the AI assistant. what names the contents, such as "the
+// staged diff".
+func RequireFileContents(what string) error {
+	if config.GetPrivacy().FileContents {
+		return nil
+	}
+	return failure.New(failure.Config, "privacy.file_contents is off, so %s is not sent to the AI assistant", what)
+}
+
+// minEnvValueLength is the length below which values of environment
+// variables are too common to be told apart from other text, such as "1".
+const minEnvValueLength = 8
+
+// absolutePath matches absolute paths of Unix and Windows and paths in the
+// home directory, but neither the paths of URLs nor comments such as "// x".
+var absolutePath = regexp.MustCompile("(?m)(^|[\\s\"'`=(\\[,])((?:~?/[\\w.~-]|[A-Za-z]:[/\\\\])[^\\s\"'`()\\[\\],;]*)")
+
+// redactor replaces the context the privacy settings keep from the AI
+// assistant.
+type redactor struct {
+	// replacer replaces values of environment variables and remote URLs.
+	replacer *strings.Replacer
+	paths    bool
+}
+
+// newRedactor returns the redactor of privacy for the environment environ
+// and the remote URLs of the current repository.
+func newRedactor(privacy config.Privacy, environ, remotes []string) redactor {
+	replacements := make(map[string]string)
+	if !privacy.GitRemotes {
+		for _, remote := range remotes {
+			for _, form := range remoteForms(remote) {
+				replacements[form] = "<git remote>"
+			}
+		}
+	}
+	if !privacy.EnvValues {
+		for _, entry := range environ {
+			name, value, ok := strings.Cut(entry, "=")
+			if !ok || name == "" || len(value) < minEnvValueLength {
+				continue
+			}
+			if _, taken := replacements[value]; taken {
+				continue
+			}
+			replacements[value] = "<redacted>"
+			if privacy.EnvNames {
+				replacements[value] = "$" + name
+			}
+		}
+	}
+
+	// Longer texts first, so a value containing another is replaced whole
+	texts := make([]string, 0, len(replacements))
+	for text := range replacements {
+		texts = append(texts, text)
+	}
+	sort.Slice(texts, func(i, j int) bool {
+		if len(texts[i]) != len(texts[j]) {
+			return len(texts[i]) > len(texts[j])
+		}
+		return texts[i] < texts[j]
+	})
+	var pairs []string
+	for _, text := range texts {
+		pairs = append(pairs, text, replacements[text])
+	}
+	return redactor{replacer: strings.NewReplacer(pairs...), paths: privacy.Paths}
+}
+
+// redact returns text without the context kept from the AI assistant.
+func (r redactor) redact(text string) string {
+	text = r.replacer.Replace(text)
+	if !r.paths {
+		text = absolutePath.ReplaceAllString(text, "${1}<path>")
+	}
+	return text
+}
+
+// currentRedactor returns the redactor of the privacy settings for the
+// current environment and repository.
+func currentRedactor() redactor {
+	return newRedactor(config.GetPrivacy(), os.Environ(), gitRemoteURLs())
+}
+
+// redactPrompt returns messages without the context the privacy settings
+// keep from the AI assistant.
+func redactPrompt(messages []Message) []Message {
+	r := currentRedactor()
+	redacted := make([]Message, len(messages))
+	for i, message := range messages {
+		redacted[i] = Message{Role: message.Role, Content: r.redact(message.Content)}
+	}
+	return redacted
+}
+
+// gitRemoteURLs returns the URLs of the remotes of the repository in the
+// current directory, if any.
+func gitRemoteURLs() []string {
+	if config.GetPrivacy().GitRemotes {
+		return nil
+	}
+	output, err := exec.Command("git", "config", "--get-regexp", `^remote\..*\.url$`).Output()
+	if err != nil {
+		return nil
+	}
+	var urls []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if _, url, ok := strings.Cut(line, " "); ok {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// remoteForms returns the ways the remote URL may show up in a prompt: as
+// is, without .git and as host/path, such as github.com/owner/repo.
+func remoteForms(remote string) []string {
+	forms := []string{remote, strings.TrimSuffix(remote, ".git")}
+
+	address := remote
+	if _, rest, ok := strings.Cut(address, "://"); ok {
+		address = rest
+	} else if user, rest, ok := strings.Cut(address, "@"); ok && !strings.Contains(user, "/") {
+		// scp-like syntax, such as git@github.com:owner/repo.git
+		address = strings.Replace(rest, ":", "/", 1)
+	}
+	if _, host, ok := strings.Cut(address, "@"); ok {
+		address = host
+	}
+	if strings.Contains(address, "/") {
+		forms = append(forms, strings.TrimSuffix(address, ".git"))
+	}
+	return forms
+}
+
+// showPrompt formats messages for review before they are sent to model.
+func showPrompt(model string, messages []Message) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Prompt for %s:\n", model)
+	for _, message := range messages {
+		fmt.Fprintf(&b, "\n--- %s ---\n%s\n", message.Role, strings.TrimRight(message.Content, "\n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestRedact(t *testing.T) {
+	environ := []string{"HOME=/home/me", "PROJECT=/home/me/code/shop", "GITHUB_TOKEN=ghp_secret123", "SHLVL=1", "EMPTY="}
+	remotes := []string{"git@github.com:acme/shop.git"}
+	text := `Working Directory: /home/me/code/shop
+{"path": "C:\\Users\\me\\shop", "token": "ghp_secret123"}
+Cloned from github.com/acme/shop, see https://example.com/docs/setup
+// a comment, x /= 2, cd ~/notes and SHLVL 1`
+
+	tests := []struct {
+		name    string
+		privacy config.Privacy
+		want    string
+	}{
+		{
+			"everything sent",
+			config.Privacy{Paths: true, FileContents: true, EnvNames: true, EnvValues: true, GitRemotes: true},
+			text,
+		},
+		{
+			"no paths",
+			config.Privacy{FileContents: true, EnvNames: true, EnvValues: true, GitRemotes: true},
+			`Working Directory: <path>
+{"path": "<path>", "token": "ghp_secret123"}
+Cloned from github.com/acme/shop, see https://example.com/docs/setup
+// a comment, x /= 2, cd <path> and SHLVL 1`,
+		},
+		{
+			"no environment values",
+			config.Privacy{Paths: true, FileContents: true, EnvNames: true, GitRemotes: true},
+			`Working Directory: $PROJECT
+{"path": "C:\\Users\\me\\shop", "token": "$GITHUB_TOKEN"}
+Cloned from github.com/acme/shop, see https://example.com/docs/setup
+// a comment, x /= 2, cd ~/notes and SHLVL 1`,
+		},
+		{
+			"no environment names and values",
+			config.Privacy{Paths: true, FileContents: true, GitRemotes: true},
+			`Working Directory: <redacted>
+{"path": "C:\\Users\\me\\shop", "token": "<redacted>"}
+Cloned from github.com/acme/shop, see https://example.com/docs/setup
+// a comment, x /= 2, cd ~/notes and SHLVL 1`,
+		},
+		{
+			"no git remotes",
+			config.Privacy{Paths: true, FileContents: true, EnvNames: true, EnvValues: true},
+			`Working Directory: /home/me/code/shop
+{"path": "C:\\Users\\me\\shop", "token": "ghp_secret123"}
+Cloned from <git remote>, see https://example.com/docs/setup
+// a comment, x /= 2, cd ~/notes and SHLVL 1`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := newRedactor(test.privacy, environ, remotes).redact(text); got != test.want {
+				t.Errorf("redact() =\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRemoteForms(t *testing.T) {
+	for remote, want := range map[string]string{
+		"git@github.com:acme/shop.git":           "github.com/acme/shop",
+		"https://gitlab.com/acme/shop.git":       "gitlab.com/acme/shop",
+		"ssh://git@git.example.com:22/acme/shop": "git.example.com:22/acme/shop",
+		"https://user@bitbucket.org/acme/shop":   "bitbucket.org/acme/shop",
+	} {
+		forms := remoteForms(remote)
+		if forms[0] != remote || forms[len(forms)-1] != want {
+			t.Errorf("remoteForms(%s) = %v, want it and %s", remote, forms, want)
+		}
+	}
+}
+
+func TestRequireFileContents(t *testing.T) {
+	original := config.UserSettings
+	defer func() { config.UserSettings = original }()
+
+	if err := RequireFileContents("the staged diff"); err != nil {
+		t.Errorf("RequireFileContents() = %v by default", err)
+	}
+
+	off := false
+	config.UserSettings.Privacy.FileContents = &off
+	err := RequireFileContents("the staged diff")
+	if failure.KindOf(err) != failure.Config || !strings.Contains(err.Error(), "the staged diff") {
+		t.Errorf("RequireFileContents() = %v, want a config error naming the contents", err)
+	}
+	if _, err := (&Client{}).GenerateCommitMessage(context.Background(), "diff --git a/x b/x", "", ""); err == nil {
+		t.Error("GenerateCommitMessage() sent the diff with file contents off")
+	}
+	if _, err := (&Client{}).ExplainSchema(context.Background(), "postgres", "CREATE TABLE users (id int);"); failure.KindOf(err) != failure.Config {
+		t.Errorf("ExplainSchema() = %v, want the schema kept back with file contents off", err)
+	}
+
+	t.Setenv("AURA_MODEL", "gpt-4o-mini")
+	var shown string
+	client := &Client{Review: func(prompt string) error {
+		shown = prompt
+		return errors.New("not sent")
+	}}
+	client.DescribeProject(context.Background(), "Go, 12,000 lines", "# Ledger\nBookkeeping for small shops.")
+	if !strings.Contains(shown, "12,000 lines") || strings.Contains(shown, "Bookkeeping") {
+		t.Errorf("DescribeProject() prompt with file contents off:\n%s", shown)
+	}
+}
+
+func TestReview(t *testing.T) {
+	t.Setenv("AURA_MODEL", "gpt-4o-mini")
+	declined := errors.New("not sent")
+	var shown string
+	client := &Client{Review: func(prompt string) error {
+		shown = prompt
+		return declined
+	}}
+
+	if _, err := client.Ask(context.Background(), "how do I list files?"); !errors.Is(err, declined) {
+		t.Fatalf("Ask() = %v, want the error of Review", err)
+	}
+	if !strings.HasPrefix(shown, "Prompt for gpt-4o-mini:") || !strings.Contains(shown, "--- user ---\nhow do I list files?") {
+		t.Errorf("Review was shown:\n%s", shown)
+	}
+
+	if _, err := client.Embed(context.Background(), []string{"Directory: payments"}); !errors.Is(err, declined) {
+		t.Fatalf("Embed() = %v, want the error of Review", err)
+	}
+	if !strings.Contains(shown, "--- input ---\nDirectory: payments") {
+		t.Errorf("Review was shown for Embed():\n%s", shown)
+	}
+}
@@ -0,0 +1,115 @@
+// Package aliases renders shell aliases managed by Aura into files sourced by
+// the shell integration of bash, zsh, fish and PowerShell.
+package aliases
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Alias is a shell alias.
+type Alias struct {
+	Name    string
+	Command string
+}
+
+// versionPrefix starts the first line of generated files, followed by the
+// version of the aliases they were generated from.
+const versionPrefix = "# aura aliases version "
+
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// ValidName reports whether name can be used as an alias in all shells.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// FileName returns the name of the aliases file of shell.
+func FileName(shell string) (string, error) {
+	switch shell {
+	case "bash", "zsh", "sh":
+		return "aliases.sh", nil
+	case "fish":
+		return "aliases.fish", nil
+	case "powershell", "pwsh":
+		return "aliases.ps1", nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s'", shell)
+}
+
+// Render returns the script defining the aliases in shell.
+func Render(shell string, aliases []Alias, version int) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%d\n# Generated by 'aura alias', changes are overwritten.\n", versionPrefix, version)
+
+	for _, alias := range aliases {
+		switch shell {
+		case "bash", "zsh", "sh":
+			fmt.Fprintf(&b, "alias %s=%s\n", alias.Name, quotePOSIX(alias.Command))
+		case "fish":
+			fmt.Fprintf(&b, "alias %s %s\n", alias.Name, quoteFish(alias.Command))
+		case "powershell", "pwsh":
+			// PowerShell aliases can't take arguments, functions can
+			fmt.Fprintf(&b, "Remove-Item -Path Alias:%s -Force -ErrorAction SilentlyContinue\n", alias.Name)
+			fmt.Fprintf(&b, "function global:%s { %s @args }\n", alias.Name, alias.Command)
+		default:
+			return "", fmt.Errorf("unsupported shell '%s'", shell)
+		}
+	}
+	return b.String(), nil
+}
+
+// WriteFiles renders the aliases for all shells into dir.
+func WriteFiles(dir string, aliases []Alias, version int) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create aliases directory: %w", err)
+	}
+
+	for _, shell := range []string{"bash", "fish", "powershell"} {
+		script, err := Render(shell, aliases, version)
+		if err != nil {
+			return err
+		}
+		name, _ := FileName(shell)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0644); err != nil {
+			return fmt.Errorf("failed to write aliases file: %w", err)
+		}
+	}
+	return nil
+}
+
+// FileVersion returns the version of the aliases a generated file holds, or
+// -1 if the file is missing or was not generated by Aura.
+func FileVersion(path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return -1
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return -1
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(scanner.Text(), versionPrefix))
+	if err != nil || !strings.HasPrefix(scanner.Text(), versionPrefix) {
+		return -1
+	}
+	return version
+}
+
+// quotePOSIX quotes s for sh, bash and zsh.
+func quotePOSIX(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteFish quotes s for fish, where backslashes and single quotes are escaped
+// inside single quotes.
+func quoteFish(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,83 @@
+package aliases
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	list := []Alias{
+		{Name: "gs", Command: "git status"},
+		{Name: "say", Command: `echo 'it\'s'`},
+	}
+
+	tests := []struct {
+		shell    string
+		expected []string
+	}{
+		{"bash", []string{"alias gs='git status'", `alias say='echo '\''it\'\''s'\'''`}},
+		{"fish", []string{"alias gs 'git status'", `alias say 'echo \'it\\\'s\''`}},
+		{"powershell", []string{"function global:gs { git status @args }"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			script, err := Render(tt.shell, list, 7)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.HasPrefix(script, "# aura aliases version 7\n") {
+				t.Errorf("Expected version header, got:\n%s", script)
+			}
+			for _, line := range tt.expected {
+				if !strings.Contains(script, line+"\n") {
+					t.Errorf("Expected line %s in:\n%s", line, script)
+				}
+			}
+		})
+	}
+
+	if _, err := Render("tcsh", list, 1); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+func TestWriteFilesAndVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	if version := FileVersion(filepath.Join(dir, "aliases.sh")); version != -1 {
+		t.Errorf("FileVersion() of missing file = %d, want -1", version)
+	}
+
+	if err := WriteFiles(dir, []Alias{{Name: "ll", Command: "ls -la"}}, 3); err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	for _, name := range []string{"aliases.sh", "aliases.fish", "aliases.ps1"} {
+		if version := FileVersion(filepath.Join(dir, name)); version != 3 {
+			t.Errorf("FileVersion(%s) = %d, want 3", name, version)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, "custom.sh"), []byte("alias x=y\n"), 0644)
+	if version := FileVersion(filepath.Join(dir, "custom.sh")); version != -1 {
+		t.Errorf("FileVersion() of foreign file = %d, want -1", version)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"gs":       true,
+		"git-undo": true,
+		"_x.y":     true,
+		"1up":      false,
+		"a b":      false,
+		"rm;ls":    false,
+		"":         false,
+	} {
+		if got := ValidName(name); got != want {
+			t.Errorf("ValidName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,455 @@
+// Package archive packs directories into archives and lists and unpacks
+// archives of the common formats. Tar, gzip and zip are handled natively;
+// xz and 7z use the xz and 7-Zip tools. Entries that would land outside of
+// the directory an archive is unpacked into are refused before anything is
+// written.
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format is an archive format.
+type Format string
+
+// Formats supported.
+const (
+	TarGz    Format = "tar.gz"
+	TarXz    Format = "tar.xz"
+	Tar      Format = "tar"
+	Zip      Format = "zip"
+	SevenZip Format = "7z"
+)
+
+// Formats are the supported formats, the default first.
+var Formats = []Format{TarGz, TarXz, Tar, Zip, SevenZip}
+
+// extensions maps file name endings to formats, longer endings first.
+var extensions = []struct {
+	suffix string
+	format Format
+}{
+	{".tar.gz", TarGz},
+	{".tgz", TarGz},
+	{".tar.xz", TarXz},
+	{".txz", TarXz},
+	{".tar", Tar},
+	{".zip", Zip},
+	{".7z", SevenZip},
+}
+
+// FormatOf returns the format the name of an archive file ends in, or "" if
+// it ends in none.
+func FormatOf(name string) Format {
+	lower := strings.ToLower(name)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, ext.suffix) {
+			return ext.format
+		}
+	}
+	return ""
+}
+
+// TrimExtension returns name without the ending of its format.
+func TrimExtension(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, ext.suffix) {
+			return name[:len(name)-len(ext.suffix)]
+		}
+	}
+	return name
+}
+
+// magic numbers of the formats at the start of a file. Tar archives are
+// recognized by "ustar" at offset 257 instead.
+var magic = []struct {
+	prefix []byte
+	format Format
+}{
+	{[]byte{0x1f, 0x8b}, TarGz},
+	{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, TarXz},
+	{[]byte("PK\x03\x04"), Zip},
+	{[]byte("PK\x05\x06"), Zip},
+	{[]byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}, SevenZip},
+}
+
+// Detect returns the format of the archive at file by its content, falling
+// back to its name.
+func Detect(file string) (Format, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	header := make([]byte, 512)
+	n, _ := io.ReadFull(f, header)
+	header = header[:n]
+	for _, m := range magic {
+		if bytes.HasPrefix(header, m.prefix) {
+			return m.format, nil
+		}
+	}
+	if len(header) >= 262 && string(header[257:262]) == "ustar" {
+		return Tar, nil
+	}
+	if format := FormatOf(file); format != "" {
+		return format, nil
+	}
+	return "", fmt.Errorf("%s is not an archive of a known format (%s)", filepath.Base(file), formatList())
+}
+
+func formatList() string {
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Entry is a file, directory or link in an archive.
+type Entry struct {
+	// Name is the slash separated path of the entry within the archive.
+	Name    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+	// Link is the target of a symbolic link, or of a hard link within the
+	// archive if HardLink is set.
+	Link     string
+	HardLink bool
+}
+
+// IsDir reports whether the entry is a directory.
+func (e Entry) IsDir() bool {
+	return e.Mode.IsDir()
+}
+
+// IsSymlink reports whether the entry is a symbolic link.
+func (e Entry) IsSymlink() bool {
+	return e.Mode&os.ModeSymlink != 0
+}
+
+// List returns the entries of the archive at file.
+func List(file string) ([]Entry, error) {
+	format, err := Detect(file)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case Zip:
+		return listZip(file)
+	case SevenZip:
+		return list7z(file)
+	default:
+		var entries []Entry
+		err := walkTar(file, format, func(entry Entry, _ io.Reader) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		return entries, err
+	}
+}
+
+// ErrUnsafe is wrapped by the errors of entries that would be written
+// outside of the directory an archive is unpacked into.
+var ErrUnsafe = errors.New("unsafe archive entry")
+
+// Check returns an error for the first entry that would be written outside
+// of the directory the archive is unpacked into: absolute paths, paths
+// climbing out of it with .., and links pointing outside of it.
+func Check(entries []Entry) error {
+	for _, entry := range entries {
+		name, err := cleanName(entry.Name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsSymlink() && entry.Link == "":
+			return fmt.Errorf("%w: the target of the link %s is unknown", ErrUnsafe, entry.Name)
+		case entry.Link == "":
+		case entry.HardLink:
+			if _, err := cleanName(entry.Link); err != nil {
+				return fmt.Errorf("%w: %s links to %s", ErrUnsafe, entry.Name, entry.Link)
+			}
+		default:
+			target := strings.ReplaceAll(entry.Link, `\`, "/")
+			if isAbs(target) {
+				return fmt.Errorf("%w: %s links to %s", ErrUnsafe, entry.Name, entry.Link)
+			}
+			if _, err := cleanName(path.Join(path.Dir(name), target)); err != nil {
+				return fmt.Errorf("%w: %s links to %s", ErrUnsafe, entry.Name, entry.Link)
+			}
+		}
+	}
+	return nil
+}
+
+// cleanName returns the cleaned slash separated form of the name of an
+// entry, or an error if it leads outside of the directory it is unpacked
+// into.
+func cleanName(name string) (string, error) {
+	slashed := strings.ReplaceAll(name, `\`, "/")
+	clean := path.Clean(slashed)
+	if isAbs(slashed) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafe, name)
+	}
+	return clean, nil
+}
+
+// isAbs reports whether a slash separated name is absolute on any system,
+// such as /etc/passwd or C:/Windows.
+func isAbs(name string) bool {
+	return strings.HasPrefix(name, "/") || len(name) >= 2 && name[1] == ':'
+}
+
+// Conflicts returns the paths existing files would be replaced at when
+// unpacking entries into dir. Existing directories are merged into.
+func Conflicts(entries []Entry, dir string) []string {
+	var conflicts []string
+	for _, entry := range entries {
+		name, err := cleanName(entry.Name)
+		if err != nil || name == "." {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		info, err := os.Lstat(target)
+		if err != nil || info.IsDir() && entry.IsDir() {
+			continue
+		}
+		conflicts = append(conflicts, target)
+	}
+	return conflicts
+}
+
+// Roots returns the distinct first path elements of the entries, in the
+// order they come in.
+func Roots(entries []Entry) []string {
+	var roots []string
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name, err := cleanName(entry.Name)
+		if err != nil || name == "." {
+			continue
+		}
+		root, _, _ := strings.Cut(name, "/")
+		if !seen[root] {
+			seen[root] = true
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// Extract unpacks the archive at file into dir, creating it if needed and
+// replacing existing files. It refuses archives Check finds unsafe before
+// writing anything.
+func Extract(file, dir string) error {
+	format, err := Detect(file)
+	if err != nil {
+		return err
+	}
+	entries, err := List(file)
+	if err != nil {
+		return err
+	}
+	if err := Check(entries); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case Zip:
+		return extractZip(file, dir)
+	case SevenZip:
+		return extract7z(file, dir)
+	default:
+		w := &writer{dir: dir}
+		if err := walkTar(file, format, w.write); err != nil {
+			return err
+		}
+		return w.finish()
+	}
+}
+
+// writer writes entries into dir.
+type writer struct {
+	dir string
+	// dirs are the directories written, whose times are set at the end as
+	// writing into them changes them.
+	dirs []Entry
+}
+
+func (w *writer) write(entry Entry, content io.Reader) error {
+	name, err := cleanName(entry.Name)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(w.dir, filepath.FromSlash(name))
+	if target != w.dir && !strings.HasPrefix(target, w.dir+string(os.PathSeparator)) {
+		return fmt.Errorf("%w: %s", ErrUnsafe, entry.Name)
+	}
+	if err := w.checkParents(name); err != nil {
+		return err
+	}
+	if entry.IsDir() {
+		w.dirs = append(w.dirs, entry)
+		return os.MkdirAll(target, 0o755)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	// Existing files are replaced rather than written through, so a link in
+	// their place can't redirect the content.
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	switch {
+	case entry.HardLink:
+		link, _ := cleanName(entry.Link)
+		return os.Link(filepath.Join(w.dir, filepath.FromSlash(link)), target)
+	case entry.IsSymlink():
+		return os.Symlink(filepath.FromSlash(entry.Link), target)
+	}
+
+	mode := entry.Mode.Perm()
+	if mode == 0 {
+		mode = 0o644
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if !entry.ModTime.IsZero() {
+		os.Chtimes(target, entry.ModTime, entry.ModTime)
+	}
+	return nil
+}
+
+// checkParents returns an error if a directory above the entry name is a
+// link, as an earlier entry may have pointed it anywhere.
+func (w *writer) checkParents(name string) error {
+	current := w.dir
+	parts := strings.Split(name, "/")
+	for _, part := range parts[:len(parts)-1] {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if err != nil {
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s is inside the link %s", ErrUnsafe, name, current)
+		}
+	}
+	return nil
+}
+
+func (w *writer) finish() error {
+	for _, entry := range w.dirs {
+		if name, err := cleanName(entry.Name); err == nil && !entry.ModTime.IsZero() {
+			target := filepath.Join(w.dir, filepath.FromSlash(name))
+			os.Chtimes(target, entry.ModTime, entry.ModTime)
+		}
+	}
+	return nil
+}
+
+// Create packs dir into a new archive at file in format. The entries are
+// named after dir, so unpacking the archive brings back the directory.
+func Create(dir, file string, format Format) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	file, err = filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	if format == SevenZip {
+		return create7z(dir, file)
+	}
+
+	out, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", file, err)
+	}
+	switch format {
+	case Zip:
+		err = createZip(dir, file, out)
+	default:
+		err = createTar(dir, file, out, format)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file)
+	}
+	return err
+}
+
+// walkDir calls fn with the entries of dir named relative to its parent,
+// leaving out skip, the archive being written.
+func walkDir(dir, skip string, fn func(entry Entry, path string) error) error {
+	parent := filepath.Dir(dir)
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// Sockets, devices and the like have no place in an archive.
+		if p == skip || !info.Mode().IsRegular() && !info.IsDir() && info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(parent, p)
+		if err != nil {
+			return err
+		}
+		entry := Entry{Name: filepath.ToSlash(rel), Size: info.Size(), Mode: info.Mode(), ModTime: info.ModTime()}
+		if info.IsDir() {
+			entry.Name += "/"
+			entry.Size = 0
+		}
+		if entry.IsSymlink() {
+			if entry.Link, err = os.Readlink(p); err != nil {
+				return err
+			}
+			entry.Link = filepath.ToSlash(entry.Link)
+			entry.Size = 0
+		}
+		return fn(entry, p)
+	})
+}
+
+// tool returns the path of the first of names found, or an error naming
+// what it is needed for.
+func tool(purpose string, names ...string) (string, error) {
+	for _, name := range names {
+		if p, err := exec.LookPath(name); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("%s needs %s, install it first", purpose, names[0])
+}
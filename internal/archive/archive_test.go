@@ -0,0 +1,232 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// makeTree creates a project directory with a file, a nested file and an
+// empty directory in dir.
+func makeTree(t *testing.T, dir string) string {
+	t.Helper()
+	root := filepath.Join(dir, "project")
+	for name, content := range map[string]string{
+		"README.md":   "# Project\n",
+		"src/main.go": "package main\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			switch format {
+			case TarXz:
+				if _, err := exec.LookPath("xz"); err != nil {
+					t.Skip("xz not installed")
+				}
+			case SevenZip:
+				if _, err := tool("", sevenZipTools...); err != nil {
+					t.Skip("7-Zip not installed")
+				}
+			}
+			dir := t.TempDir()
+			root := makeTree(t, dir)
+			file := filepath.Join(dir, "project."+string(format))
+			if err := Create(root, file, format); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := Create(root, file, format); err == nil {
+				t.Error("Create() replaced an existing archive")
+			}
+
+			if detected, err := Detect(file); err != nil || detected != format {
+				t.Errorf("Detect() = %q, %v, want %q", detected, err, format)
+			}
+			entries, err := List(file)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if roots := Roots(entries); !reflect.DeepEqual(roots, []string{"project"}) {
+				t.Errorf("Roots() = %v, want [project]", roots)
+			}
+
+			out := filepath.Join(dir, "out")
+			if err := Extract(file, out); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(out, "project", "src", "main.go"))
+			if err != nil || string(content) != "package main\n" {
+				t.Errorf("unpacked main.go = %q, %v", content, err)
+			}
+			if info, err := os.Stat(filepath.Join(out, "project", "empty")); err != nil || !info.IsDir() {
+				t.Errorf("unpacked empty directory = %v, %v", info, err)
+			}
+			if conflicts := Conflicts(entries, out); len(conflicts) != 2 {
+				t.Errorf("Conflicts() = %v, want the two files", conflicts)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		safe  bool
+	}{
+		{name: "file", entry: Entry{Name: "project/main.go"}, safe: true},
+		{name: "dot prefix", entry: Entry{Name: "./project/main.go"}, safe: true},
+		{name: "climbing out", entry: Entry{Name: "project/../../etc/passwd"}},
+		{name: "absolute", entry: Entry{Name: "/etc/passwd"}},
+		{name: "drive", entry: Entry{Name: `C:\Windows\evil.dll`}},
+		{name: "backslashes", entry: Entry{Name: `..\evil.exe`}},
+		{name: "link inside", entry: Entry{Name: "project/current", Mode: os.ModeSymlink, Link: "releases/v2"}, safe: true},
+		{name: "link up inside", entry: Entry{Name: "project/a/latest", Mode: os.ModeSymlink, Link: "../b"}, safe: true},
+		{name: "link out", entry: Entry{Name: "project/secrets", Mode: os.ModeSymlink, Link: "../../.ssh"}},
+		{name: "absolute link", entry: Entry{Name: "project/passwd", Mode: os.ModeSymlink, Link: "/etc/passwd"}},
+		{name: "unknown link", entry: Entry{Name: "project/link", Mode: os.ModeSymlink}},
+		{name: "hard link inside", entry: Entry{Name: "project/copy", Link: "project/main.go", HardLink: true}, safe: true},
+		{name: "hard link out", entry: Entry{Name: "project/copy", Link: "../outside", HardLink: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check([]Entry{tt.entry})
+			if tt.safe && err != nil {
+				t.Errorf("Check() error = %v, want safe", err)
+			}
+			if !tt.safe && !errors.Is(err, ErrUnsafe) {
+				t.Errorf("Check() error = %v, want ErrUnsafe", err)
+			}
+		})
+	}
+}
+
+func TestExtractRefusesTraversal(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "evil.tar")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	for _, name := range []string{"good.txt", "../evil.txt"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 4, Typeflag: tar.TypeReg})
+		tw.Write([]byte("data"))
+	}
+	tw.Close()
+	f.Close()
+
+	out := filepath.Join(dir, "out")
+	if err := Extract(file, out); !errors.Is(err, ErrUnsafe) {
+		t.Errorf("Extract() error = %v, want ErrUnsafe", err)
+	}
+	for _, path := range []string{filepath.Join(dir, "evil.txt"), filepath.Join(out, "good.txt")} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("Extract() wrote %s", path)
+		}
+	}
+}
+
+func TestExtractRefusesWritingThroughLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need privileges on Windows")
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "evil.zip")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	// A link to the directory itself passes the check of its target, but
+	// the second link is really created one level higher than it looks.
+	link := &zip.FileHeader{Name: "a"}
+	link.SetMode(os.ModeSymlink | 0o777)
+	w, _ := zw.CreateHeader(link)
+	w.Write([]byte("."))
+	nested := &zip.FileHeader{Name: "a/b/x"}
+	nested.SetMode(0o644)
+	w, _ = zw.CreateHeader(nested)
+	w.Write([]byte("data"))
+	zw.Close()
+	f.Close()
+
+	err = Extract(file, filepath.Join(dir, "out"))
+	if !errors.Is(err, ErrUnsafe) || !strings.Contains(err.Error(), "inside the link") {
+		t.Errorf("Extract() error = %v, want refusing to write through a link", err)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]Format{
+		"backup.tar.gz": TarGz,
+		"backup.TGZ":    TarGz,
+		"backup.tar.xz": TarXz,
+		"backup.tar":    Tar,
+		"photos.zip":    Zip,
+		"photos.7z":     SevenZip,
+		"notes.txt":     "",
+	}
+	for name, want := range tests {
+		if got := FormatOf(name); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if got := TrimExtension("backup.2024.tar.gz"); got != "backup.2024" {
+		t.Errorf("TrimExtension() = %q, want backup.2024", got)
+	}
+}
+
+func TestParse7zList(t *testing.T) {
+	output := `7-Zip [64] 16.02 : Copyright (c) 1999-2016 Igor Pavlov : 2016-05-21
+
+Listing archive: project.7z
+
+--
+Path = project.7z
+Type = 7z
+Physical Size = 240
+
+----------
+Path = project
+Size = 0
+Modified = 2024-05-01 10:00:00
+Attributes = D_ drwxr-xr-x
+
+Path = project/run.sh
+Size = 12
+Modified = 2024-05-01 10:00:00.1234567
+Attributes = A_ -rwxr-xr-x
+CRC = 3A7C5F2E
+`
+	entries := parse7zList(output)
+	if len(entries) != 2 {
+		t.Fatalf("parse7zList() = %+v, want 2 entries", entries)
+	}
+	if !entries[0].IsDir() || entries[0].Name != "project" {
+		t.Errorf("parse7zList() directory = %+v", entries[0])
+	}
+	if file := entries[1]; file.Name != "project/run.sh" || file.Size != 12 || file.Mode != 0o755 || file.ModTime.IsZero() {
+		t.Errorf("parse7zList() file = %+v", file)
+	}
+}
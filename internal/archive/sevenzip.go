@@ -0,0 +1,127 @@
+package archive
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sevenZipTools are the names the 7-Zip command goes by.
+var sevenZipTools = []string{"7z", "7zz", "7za"}
+
+func list7z(file string) ([]Entry, error) {
+	sevenZip, err := tool("reading 7z archives", sevenZipTools...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := exec.Command(sevenZip, "l", "-slt", file).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", file, err)
+	}
+	return parse7zList(string(output)), nil
+}
+
+// parse7zList parses the technical listing of '7z l -slt': after a line of
+// dashes one block of "Key = value" lines per entry.
+func parse7zList(output string) []Entry {
+	var entries []Entry
+	var fields map[string]string
+	flush := func() {
+		if fields == nil || fields["Path"] == "" {
+			fields = nil
+			return
+		}
+		entry := Entry{Name: fields["Path"], Link: fields["Symbolic Link"]}
+		entry.Size, _ = strconv.ParseInt(fields["Size"], 10, 64)
+		if modified := fields["Modified"]; len(modified) >= 19 {
+			entry.ModTime, _ = time.ParseInLocation("2006-01-02 15:04:05", modified[:19], time.Local)
+		}
+		// Attributes are Windows ones, such as "D" or "A", and the Unix
+		// mode if stored, such as "A -rw-r--r--".
+		attributes := fields["Attributes"]
+		entry.Mode = 0o644
+		if unix := strings.Fields(attributes); len(unix) > 1 && len(unix[1]) == 10 {
+			entry.Mode = parseUnixMode(unix[1])
+		}
+		if fields["Folder"] == "+" || strings.HasPrefix(attributes, "D") {
+			entry.Mode |= os.ModeDir
+		}
+		entries = append(entries, entry)
+		fields = nil
+	}
+
+	started := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if !started {
+			started = strings.HasPrefix(line, "----------")
+			continue
+		}
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, " = ")
+		if !ok {
+			key, value = strings.TrimSuffix(line, " ="), ""
+		}
+		if fields == nil {
+			fields = map[string]string{}
+		}
+		fields[key] = value
+	}
+	flush()
+	return entries
+}
+
+// parseUnixMode parses a mode as ls prints it, such as "-rwxr-xr-x".
+func parseUnixMode(text string) os.FileMode {
+	var mode os.FileMode
+	for i, c := range text[1:] {
+		if c != '-' {
+			mode |= 1 << (8 - i)
+		}
+	}
+	switch text[0] {
+	case 'd':
+		mode |= os.ModeDir
+	case 'l':
+		mode |= os.ModeSymlink
+	}
+	return mode
+}
+
+func extract7z(file, dir string) error {
+	sevenZip, err := tool("unpacking 7z archives", sevenZipTools...)
+	if err != nil {
+		return err
+	}
+	output, err := exec.Command(sevenZip, "x", "-y", "-o"+dir, file).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("7z failed: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func create7z(dir, file string) error {
+	sevenZip, err := tool("packing 7z archives", sevenZipTools...)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(file); err == nil {
+		return fmt.Errorf("failed to create %s: %w", file, os.ErrExist)
+	}
+	cmd := exec.Command(sevenZip, "a", "-t7z", file, filepath.Base(dir))
+	cmd.Dir = filepath.Dir(dir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(file)
+		return fmt.Errorf("7z failed: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// walkTar calls fn with every entry of the tar archive at file and its
+// content, decompressing it as format requires.
+func walkTar(file string, format Format, fn func(Entry, io.Reader) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	switch format {
+	case TarGz:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		defer gz.Close()
+		r = gz
+	case TarXz:
+		xz, err := tool("unpacking tar.xz", "xz")
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(xz, "--decompress", "--stdout")
+		cmd.Stdin = f
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to run xz: %w", err)
+		}
+		defer func() {
+			io.Copy(io.Discard, stdout)
+			cmd.Wait()
+		}()
+		r = stdout
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		entry := Entry{Name: header.Name, Size: header.Size, Mode: header.FileInfo().Mode(), ModTime: header.ModTime}
+		switch header.Typeflag {
+		case tar.TypeSymlink:
+			entry.Link = header.Linkname
+		case tar.TypeLink:
+			entry.Link = header.Linkname
+			entry.HardLink = true
+		case tar.TypeReg, tar.TypeDir:
+		default:
+			// Devices, FIFOs and extended headers are not unpacked.
+			continue
+		}
+		if err := fn(entry, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// createTar writes a tar archive of dir to out, compressing it as format
+// requires.
+func createTar(dir, file string, out io.Writer, format Format) (err error) {
+	w := out
+	var finish func() error
+	switch format {
+	case TarGz:
+		gz := gzip.NewWriter(out)
+		w, finish = gz, gz.Close
+	case TarXz:
+		xz, err := tool("packing tar.xz", "xz")
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(xz, "--compress", "--stdout")
+		cmd.Stdout = out
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to run xz: %w", err)
+		}
+		w = stdin
+		finish = func() error {
+			stdin.Close()
+			if err := cmd.Wait(); err != nil {
+				return fmt.Errorf("xz failed: %w", err)
+			}
+			return nil
+		}
+	}
+	if finish != nil {
+		defer func() {
+			if finishErr := finish(); err == nil {
+				err = finishErr
+			}
+		}()
+	}
+
+	tw := tar.NewWriter(w)
+	err = walkDir(dir, file, func(entry Entry, path string) error {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, entry.Link)
+		if err != nil {
+			return err
+		}
+		header.Name = entry.Name
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
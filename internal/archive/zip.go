@@ -0,0 +1,121 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func listZip(file string) ([]Entry, error) {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	defer r.Close()
+
+	var entries []Entry
+	for _, f := range r.File {
+		entry, err := zipEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// zipEntry describes f, reading the target of a link from its content.
+func zipEntry(f *zip.File) (Entry, error) {
+	entry := Entry{Name: f.Name, Size: int64(f.UncompressedSize64), Mode: f.Mode(), ModTime: f.Modified}
+	if strings.HasSuffix(f.Name, "/") {
+		entry.Mode |= os.ModeDir
+	}
+	if entry.IsSymlink() {
+		rc, err := f.Open()
+		if err != nil {
+			return Entry{}, err
+		}
+		target, err := io.ReadAll(io.LimitReader(rc, 4096))
+		rc.Close()
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Link = string(target)
+		entry.Size = 0
+	}
+	return entry, nil
+}
+
+func extractZip(file, dir string) error {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	defer r.Close()
+
+	w := &writer{dir: dir}
+	for _, f := range r.File {
+		entry, err := zipEntry(f)
+		if err != nil {
+			return err
+		}
+		if err := extractZipFile(w, f, entry); err != nil {
+			return err
+		}
+	}
+	return w.finish()
+}
+
+func extractZipFile(w *writer, f *zip.File, entry Entry) error {
+	if entry.IsDir() || entry.IsSymlink() {
+		return w.write(entry, nil)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	return w.write(entry, rc)
+}
+
+func createZip(dir, file string, out io.Writer) error {
+	zw := zip.NewWriter(out)
+	err := walkDir(dir, file, func(entry Entry, path string) error {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = entry.Name
+		if !info.IsDir() {
+			header.Method = zip.Deflate
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsSymlink():
+			_, err = io.WriteString(w, entry.Link)
+			return err
+		case !info.Mode().IsRegular():
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,379 @@
+// Package audit checks the dependencies of a project for known
+// vulnerabilities with the audit tool of each ecosystem (govulncheck, npm
+// audit and pip-audit) and normalizes their results into one report.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Severities from the most to the least severe. Findings whose tool reports
+// no severity have none.
+var Severities = []string{"critical", "high", "moderate", "low"}
+
+// Vulnerability is a known vulnerability of a dependency.
+type Vulnerability struct {
+	Ecosystem string
+	Package   string
+	// Installed is the installed version, or for npm the affected range.
+	Installed string
+	// Fixed is the first version with a fix, or "" if there is none.
+	Fixed string
+	// Breaking reports whether the fix needs a major upgrade.
+	Breaking bool
+	ID       string
+	Severity string
+	Summary  string
+}
+
+// Auditor audits the dependencies of one ecosystem with an external tool.
+type Auditor struct {
+	// Ecosystem is the name of the ecosystem, such as npm.
+	Ecosystem string
+	// Manifests are the files that mark a project of the ecosystem.
+	Manifests []string
+	// Tool is the command that audits the dependencies.
+	Tool string
+	// Install tells how to install Tool.
+	Install string
+	args    func(root string) []string
+	parse   func(output []byte) ([]Vulnerability, error)
+}
+
+// Auditors are the supported ecosystems.
+var Auditors = []Auditor{
+	{
+		Ecosystem: "Go",
+		Manifests: []string{"go.mod"},
+		Tool:      "govulncheck",
+		Install:   "go install golang.org/x/vuln/cmd/govulncheck@latest",
+		args:      func(string) []string { return []string{"-json", "./..."} },
+		parse:     parseGovulncheck,
+	},
+	{
+		Ecosystem: "npm",
+		Manifests: []string{"package.json"},
+		Tool:      "npm",
+		Install:   "install Node.js from https://nodejs.org",
+		args:      func(string) []string { return []string{"audit", "--json"} },
+		parse:     parseNpmAudit,
+	},
+	{
+		Ecosystem: "pip",
+		Manifests: []string{"requirements.txt", "pyproject.toml"},
+		Tool:      "pip-audit",
+		Install:   "pip install pip-audit",
+		args: func(root string) []string {
+			if _, err := os.Stat(filepath.Join(root, "requirements.txt")); err == nil {
+				return []string{"-f", "json", "-r", "requirements.txt"}
+			}
+			return []string{"-f", "json", "."}
+		},
+		parse: parsePipAudit,
+	},
+}
+
+// Detect returns the auditors of the ecosystems of the project in root.
+func Detect(root string) []Auditor {
+	var detected []Auditor
+	for _, auditor := range Auditors {
+		for _, manifest := range auditor.Manifests {
+			if _, err := os.Stat(filepath.Join(root, manifest)); err == nil {
+				detected = append(detected, auditor)
+				break
+			}
+		}
+	}
+	return detected
+}
+
+// Installed reports whether the tool of the auditor is on the PATH.
+func (a Auditor) Installed() bool {
+	_, err := exec.LookPath(a.Tool)
+	return err == nil
+}
+
+// Run audits the project in root. The tools exit with an error when they
+// find vulnerabilities, so their output is parsed whenever there is any.
+// They fetch vulnerability data, so callers check config.RequireNetwork
+// first.
+func (a Auditor) Run(ctx context.Context, root string) ([]Vulnerability, error) {
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, a.Tool, a.args(root)...)
+	command.Dir = root
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	runErr := command.Run()
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		if runErr != nil {
+			message := strings.TrimSpace(stderr.String())
+			if message == "" {
+				message = runErr.Error()
+			}
+			return nil, fmt.Errorf("failed to audit %s dependencies: %s", a.Ecosystem, message)
+		}
+		return nil, nil
+	}
+	vulnerabilities, err := a.parse(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to audit %s dependencies: %w", a.Ecosystem, err)
+	}
+	for i := range vulnerabilities {
+		vulnerabilities[i].Ecosystem = a.Ecosystem
+	}
+	return vulnerabilities, nil
+}
+
+// SeverityRank ranks severity for sorting, 0 being the most severe and
+// unknown severities the least.
+func SeverityRank(severity string) int {
+	for i, known := range Severities {
+		if severity == known {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+// Sort sorts vulnerabilities by ecosystem, severity, package and ID.
+func Sort(vulnerabilities []Vulnerability) {
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		a, b := vulnerabilities[i], vulnerabilities[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		if SeverityRank(a.Severity) != SeverityRank(b.Severity) {
+			return SeverityRank(a.Severity) < SeverityRank(b.Severity)
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.ID < b.ID
+	})
+}
+
+// parseGovulncheck parses the stream of JSON messages of
+// `govulncheck -json`. Only vulnerabilities whose code is reachable are
+// reported, once per module.
+func parseGovulncheck(output []byte) ([]Vulnerability, error) {
+	type osvEntry struct {
+		ID      string `json:"id"`
+		Summary string `json:"summary"`
+		Details string `json:"details"`
+	}
+	var (
+		entries  = map[string]osvEntry{}
+		seen     = map[string]bool{}
+		findings []Vulnerability
+	)
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var message struct {
+			OSV     *osvEntry `json:"osv"`
+			Finding *struct {
+				OSV          string `json:"osv"`
+				FixedVersion string `json:"fixed_version"`
+				Trace        []struct {
+					Module   string `json:"module"`
+					Version  string `json:"version"`
+					Function string `json:"function"`
+				} `json:"trace"`
+			} `json:"finding"`
+		}
+		if err := decoder.Decode(&message); err != nil {
+			return nil, err
+		}
+		if message.OSV != nil {
+			entries[message.OSV.ID] = *message.OSV
+		}
+		finding := message.Finding
+		if finding == nil || len(finding.Trace) == 0 || finding.Trace[0].Function == "" {
+			continue
+		}
+		frame := finding.Trace[0]
+		if seen[finding.OSV+" "+frame.Module] {
+			continue
+		}
+		seen[finding.OSV+" "+frame.Module] = true
+		findings = append(findings, Vulnerability{
+			Package:   frame.Module,
+			Installed: frame.Version,
+			Fixed:     finding.FixedVersion,
+			Breaking:  majorUpgrade(frame.Version, finding.FixedVersion),
+			ID:        finding.OSV,
+		})
+	}
+
+	for i, finding := range findings {
+		entry := entries[finding.ID]
+		findings[i].Summary = entry.Summary
+		if findings[i].Summary == "" {
+			findings[i].Summary = firstLine(entry.Details)
+		}
+	}
+	return findings, nil
+}
+
+// parseNpmAudit parses the report of `npm audit --json` of npm 7 and later.
+// Packages vulnerable only through another vulnerable package are left out.
+func parseNpmAudit(output []byte) ([]Vulnerability, error) {
+	var report struct {
+		Error *struct {
+			Summary string `json:"summary"`
+		} `json:"error"`
+		Vulnerabilities map[string]struct {
+			Name         string            `json:"name"`
+			Severity     string            `json:"severity"`
+			Range        string            `json:"range"`
+			Via          []json.RawMessage `json:"via"`
+			FixAvailable json.RawMessage   `json:"fixAvailable"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, err
+	}
+	if report.Error != nil {
+		return nil, fmt.Errorf("%s", firstLine(report.Error.Summary))
+	}
+
+	var vulnerabilities []Vulnerability
+	for name, entry := range report.Vulnerabilities {
+		var fix struct {
+			Name          string `json:"name"`
+			Version       string `json:"version"`
+			IsSemVerMajor bool   `json:"isSemVerMajor"`
+		}
+		_ = json.Unmarshal(entry.FixAvailable, &fix)
+		fixed := fixedNpmVersion(name, fix.Name, fix.Version)
+		if string(entry.FixAvailable) == "true" {
+			// npm audit fix upgrades within the ranges of package.json.
+			fixed = "a compatible version"
+		}
+
+		for _, raw := range entry.Via {
+			var advisory struct {
+				Source any    `json:"source"`
+				Title  string `json:"title"`
+				URL    string `json:"url"`
+			}
+			// Entries of via naming another package are transitive.
+			if json.Unmarshal(raw, &advisory) != nil {
+				continue
+			}
+			id := advisory.URL
+			if i := strings.LastIndex(id, "/"); i >= 0 {
+				id = id[i+1:]
+			}
+			if id == "" {
+				id = fmt.Sprint(advisory.Source)
+			}
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				Package:   name,
+				Installed: entry.Range,
+				Fixed:     fixed,
+				Breaking:  fix.IsSemVerMajor,
+				ID:        id,
+				Severity:  entry.Severity,
+				Summary:   advisory.Title,
+			})
+		}
+	}
+	return vulnerabilities, nil
+}
+
+// fixedNpmVersion describes the fix npm audit offers for a package. The fix
+// may be an upgrade of the package depending on it.
+func fixedNpmVersion(name, fixName, fixVersion string) string {
+	switch {
+	case fixVersion == "":
+		return ""
+	case fixName == "" || fixName == name:
+		return fixVersion
+	default:
+		return fixName + "@" + fixVersion
+	}
+}
+
+// parsePipAudit parses the report of `pip-audit -f json`, both the list of
+// older versions and the object of newer ones.
+func parsePipAudit(output []byte) ([]Vulnerability, error) {
+	type dependency struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Vulns   []struct {
+			ID          string   `json:"id"`
+			FixVersions []string `json:"fix_versions"`
+			Description string   `json:"description"`
+		} `json:"vulns"`
+	}
+	var dependencies []dependency
+	if bytes.HasPrefix(bytes.TrimSpace(output), []byte("[")) {
+		if err := json.Unmarshal(output, &dependencies); err != nil {
+			return nil, err
+		}
+	} else {
+		var report struct {
+			Dependencies []dependency `json:"dependencies"`
+		}
+		if err := json.Unmarshal(output, &report); err != nil {
+			return nil, err
+		}
+		dependencies = report.Dependencies
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, dep := range dependencies {
+		for _, vuln := range dep.Vulns {
+			fixed := ""
+			if len(vuln.FixVersions) > 0 {
+				fixed = vuln.FixVersions[0]
+			}
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				Package:   dep.Name,
+				Installed: dep.Version,
+				Fixed:     fixed,
+				Breaking:  majorUpgrade(dep.Version, fixed),
+				ID:        vuln.ID,
+				Summary:   firstLine(vuln.Description),
+			})
+		}
+	}
+	return vulnerabilities, nil
+}
+
+// majorUpgrade reports whether upgrading from installed to fixed changes the
+// major version.
+func majorUpgrade(installed, fixed string) bool {
+	from, to := majorVersion(installed), majorVersion(fixed)
+	return from != "" && to != "" && from != to
+}
+
+// majorVersion returns the major version of a version such as v1.2.3 or
+// 2.31.0, or "" if version has none.
+func majorVersion(version string) string {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	if major == "" {
+		return ""
+	}
+	for _, r := range major {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return major
+}
+
+// firstLine returns the first line of text, without surrounding space.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
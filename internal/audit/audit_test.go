@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGovulncheck(t *testing.T) {
+	output := `{"config":{"scanner_name":"govulncheck"}}
+{"osv":{"id":"GO-2024-2687","summary":"HTTP/2 CONTINUATION flood in net/http","details":"An attacker may cause..."}}
+{"osv":{"id":"GO-2023-1840","details":"Unsafe behavior in setuid/setgid binaries\nin the runtime."}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.17.0"}]}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.17.0","package":"golang.org/x/net/http2","function":"Read"}]}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.17.0","package":"golang.org/x/net/http2","function":"Write"}]}}
+{"finding":{"osv":"GO-2023-1840","fixed_version":"v2.0.1","trace":[{"module":"example.com/lib","version":"v1.4.0","function":"Run"}]}}
+`
+	got, err := parseGovulncheck([]byte(output))
+	if err != nil {
+		t.Fatalf("parseGovulncheck() error = %v", err)
+	}
+	want := []Vulnerability{
+		{Package: "golang.org/x/net", Installed: "v0.17.0", Fixed: "v0.23.0", ID: "GO-2024-2687", Summary: "HTTP/2 CONTINUATION flood in net/http"},
+		{Package: "example.com/lib", Installed: "v1.4.0", Fixed: "v2.0.1", Breaking: true, ID: "GO-2023-1840", Summary: "Unsafe behavior in setuid/setgid binaries"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseGovulncheck() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseGovulncheck()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseNpmAudit(t *testing.T) {
+	output := `{
+  "auditReportVersion": 2,
+  "vulnerabilities": {
+    "lodash": {
+      "name": "lodash", "severity": "high", "range": "<4.17.21",
+      "via": [{"source": 1096305, "name": "lodash", "title": "Command Injection in lodash", "url": "https://github.com/advisories/GHSA-35jh-r3h4-6jhm", "severity": "high"}],
+      "fixAvailable": true
+    },
+    "minimist": {
+      "name": "minimist", "severity": "critical", "range": "<0.2.4",
+      "via": [{"source": 1097677, "name": "minimist", "title": "Prototype Pollution in minimist", "url": "https://github.com/advisories/GHSA-xvch-5gv4-984h", "severity": "critical"}],
+      "fixAvailable": {"name": "mkdirp", "version": "1.0.4", "isSemVerMajor": true}
+    },
+    "mkdirp": {
+      "name": "mkdirp", "severity": "critical", "range": "0.4.1 - 0.5.1",
+      "via": ["minimist"],
+      "fixAvailable": {"name": "mkdirp", "version": "1.0.4", "isSemVerMajor": true}
+    }
+  }
+}`
+	got, err := parseNpmAudit([]byte(output))
+	if err != nil {
+		t.Fatalf("parseNpmAudit() error = %v", err)
+	}
+	Sort(got)
+	want := []Vulnerability{
+		{Package: "minimist", Installed: "<0.2.4", Fixed: "mkdirp@1.0.4", Breaking: true, ID: "GHSA-xvch-5gv4-984h", Severity: "critical", Summary: "Prototype Pollution in minimist"},
+		{Package: "lodash", Installed: "<4.17.21", Fixed: "a compatible version", ID: "GHSA-35jh-r3h4-6jhm", Severity: "high", Summary: "Command Injection in lodash"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseNpmAudit() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseNpmAudit()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseNpmAuditError(t *testing.T) {
+	output := `{"error": {"code": "ENOLOCK", "summary": "This command requires an existing lockfile.\nTry creating one first."}}`
+	_, err := parseNpmAudit([]byte(output))
+	if err == nil || err.Error() != "This command requires an existing lockfile." {
+		t.Errorf("parseNpmAudit() error = %v", err)
+	}
+}
+
+func TestParsePipAudit(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"object", `{"dependencies": [
+  {"name": "flask", "version": "0.5", "vulns": [{"id": "PYSEC-2019-179", "fix_versions": ["1.0"], "aliases": ["CVE-2019-1010083"], "description": "Flask before 1.0 is affected by\nunexpected memory usage."}]},
+  {"name": "requests", "version": "2.31.0", "vulns": []}
+], "fixes": []}`},
+		{"list", `[{"name": "flask", "version": "0.5", "vulns": [{"id": "PYSEC-2019-179", "fix_versions": ["1.0"], "description": "Flask before 1.0 is affected by\nunexpected memory usage."}]}]`},
+	}
+	want := Vulnerability{Package: "flask", Installed: "0.5", Fixed: "1.0", Breaking: true, ID: "PYSEC-2019-179", Summary: "Flask before 1.0 is affected by"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePipAudit([]byte(tt.output))
+			if err != nil {
+				t.Fatalf("parsePipAudit() error = %v", err)
+			}
+			if len(got) != 1 || got[0] != want {
+				t.Errorf("parsePipAudit() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestMajorUpgrade(t *testing.T) {
+	tests := []struct {
+		installed, fixed string
+		want             bool
+	}{
+		{"v1.4.0", "v1.5.2", false},
+		{"v1.4.0", "v2.0.0", true},
+		{"0.5", "1.0", true},
+		{"2.31.0", "", false},
+		{"<4.17.21", "4.17.21", false},
+	}
+	for _, tt := range tests {
+		if got := majorUpgrade(tt.installed, tt.fixed); got != tt.want {
+			t.Errorf("majorUpgrade(%q, %q) = %v, want %v", tt.installed, tt.fixed, got, tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"go.mod", "pyproject.toml"} {
+		os.WriteFile(filepath.Join(root, name), nil, 0o644)
+	}
+	detected := Detect(root)
+	if len(detected) != 2 || detected[0].Ecosystem != "Go" || detected[1].Ecosystem != "pip" {
+		t.Errorf("Detect() = %+v, want Go and pip", detected)
+	}
+}
@@ -0,0 +1,242 @@
+// Package auditlog keeps an append-only log of the prompts Aura sends to the
+// AI assistant and the commands it runs for the user.
+//
+// The log is a file of JSON lines, one per entry. Every entry holds the hash
+// of the entry before it and its own hash over both, so changing, removing or
+// reordering entries breaks the chain, which Verify detects. Entries removed
+// from the end leave a valid chain; exports keep copies to compare with. A
+// last line that can't be read, such as one torn by a crash, is followed by
+// a Break entry so logging goes on, and Verify still reports it.
+package auditlog
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+// Kinds of entries.
+const (
+	// Prompt is a prompt sent to the AI assistant.
+	Prompt = "prompt"
+	// Command is a shell command run for the user.
+	Command = "command"
+	// Break records that the line before it is not a valid entry, and
+	// chains to the last entry that is.
+	Break = "break"
+)
+
+// Entry is an entry of the audit log.
+type Entry struct {
+	Seq  int       `json:"seq"`
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	// Source is the Aura command the entry was made by, such as "aura do".
+	Source string `json:"source"`
+	// Dir is the directory commands run in.
+	Dir string `json:"dir,omitempty"`
+	// Text is the prompt or the command.
+	Text string `json:"text"`
+	// Prev is the hash of the entry before, "" for the first.
+	Prev string `json:"prev"`
+	Hash string `json:"hash"`
+}
+
+// hash returns the hash of the entry with every field but Hash.
+func (e Entry) hash() string {
+	e.Hash = ""
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Source is the Aura command entries are made by, set when it starts.
+var Source = "aura"
+
+// Log is an audit log file.
+type Log struct {
+	Path string
+}
+
+// Default returns the audit log in config.StateDir.
+func Default() Log {
+	return Log{Path: filepath.Join(config.StateDir, "audit.log")}
+}
+
+// mu serializes appends within the process, such as commands run in
+// parallel by aura each; the file lock serializes them across processes.
+var mu sync.Mutex
+
+// Append adds an entry of kind for text, run in dir if it is a command,
+// chained to the last entry of the log.
+func (l Log) Append(kind, text, dir string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := config.LockFile(l.Path)
+	if err != nil {
+		return fmt.Errorf("failed to write the audit log: %w", err)
+	}
+	defer unlock()
+
+	last, torn, err := l.last()
+	if err != nil {
+		return fmt.Errorf("failed to read the audit log: %w", err)
+	}
+	now := time.Now().UTC()
+	var entries []Entry
+	if torn != 0 {
+		entries = append(entries, Entry{Time: now, Kind: Break, Source: Source, Text: fmt.Sprintf("line %d of the log is not a valid entry", torn)})
+	}
+	entries = append(entries, Entry{Time: now, Kind: kind, Source: Source, Dir: dir, Text: text})
+
+	var data []byte
+	if torn != 0 && !l.endsWithNewline() {
+		data = append(data, '\n')
+	}
+	for i := range entries {
+		entries[i].Seq = 1
+		if last != nil {
+			entries[i].Seq, entries[i].Prev = last.Seq+1, last.Hash
+		}
+		entries[i].Hash = entries[i].hash()
+		last = &entries[i]
+
+		line, err := json.Marshal(entries[i])
+		if err != nil {
+			return fmt.Errorf("failed to write the audit log: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	file, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the audit log: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write the audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// last returns the last valid entry of the log, or nil if there is none, and
+// the number of the last line if it is not a valid entry, 0 otherwise.
+// Invalid lines before it are left to Entries and Verify to report.
+func (l Log) last() (*Entry, int, error) {
+	file, err := os.Open(l.Path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	var last *Entry
+	torn := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			torn = line
+			continue
+		}
+		last, torn = &entry, 0
+	}
+	return last, torn, scanner.Err()
+}
+
+// endsWithNewline reports whether the log ends with a newline, which a line
+// torn while it was written doesn't.
+func (l Log) endsWithNewline() bool {
+	file, err := os.Open(l.Path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	last := make([]byte, 1)
+	_, err = file.ReadAt(last, info.Size()-1)
+	return err == nil && last[0] == '\n'
+}
+
+// Entries returns the entries of the log, oldest first. A missing log has
+// none.
+func (l Log) Entries() ([]Entry, error) {
+	var entries []Entry
+	err := l.each(func(entry Entry) {
+		entries = append(entries, entry)
+	})
+	return entries, err
+}
+
+// each calls fn with every entry of the log, oldest first.
+func (l Log) each(fn func(Entry)) error {
+	file, err := os.Open(l.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return &ChainError{Seq: line, Reason: "is not a valid entry"}
+		}
+		fn(entry)
+	}
+	return scanner.Err()
+}
+
+// ChainError reports where the hash chain of a log is broken.
+type ChainError struct {
+	// Seq is the number of the first entry that doesn't fit the chain.
+	Seq    int
+	Reason string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("the audit log was tampered with: entry %d %s", e.Seq, e.Reason)
+}
+
+// Verify checks the hash chain of entries, as returned by Entries, and
+// returns a *ChainError for the first entry that was changed, removed or
+// moved, or that records a break.
+func Verify(entries []Entry) error {
+	prev := ""
+	for i, entry := range entries {
+		switch {
+		case entry.Seq != i+1:
+			return &ChainError{Seq: i + 1, Reason: fmt.Sprintf("is missing, found entry %d in its place", entry.Seq)}
+		case entry.Prev != prev:
+			return &ChainError{Seq: entry.Seq, Reason: "does not follow the entry before it"}
+		case entry.Hash != entry.hash():
+			return &ChainError{Seq: entry.Seq, Reason: "was changed"}
+		case entry.Kind == Break:
+			return &ChainError{Seq: entry.Seq, Reason: "records a break: " + entry.Text}
+		}
+		prev = entry.Hash
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package auditlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeLog appends three entries to a new log and returns it.
+func writeLog(t *testing.T) Log {
+	t.Helper()
+	log := Log{Path: filepath.Join(t.TempDir(), "state", "audit.log")}
+	for _, entry := range []struct{ kind, text, dir string }{
+		{Prompt, "Prompt for gpt-4o:\n\n--- user ---\nhow do I list files?", ""},
+		{Command, "ls -la", "/home/me"},
+		{Command, "git status", "/home/me/shop"},
+	} {
+		if err := log.Append(entry.kind, entry.text, entry.dir); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	return log
+}
+
+func TestAppend(t *testing.T) {
+	log := writeLog(t)
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Entries() = %d entries, want 3", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Seq != i+1 || entry.Hash == "" || entry.Source != "aura" {
+			t.Errorf("entry %d = %+v", i+1, entry)
+		}
+	}
+	if entries[0].Prev != "" || entries[1].Prev != entries[0].Hash || entries[2].Prev != entries[1].Hash {
+		t.Error("entries are not chained by their hashes")
+	}
+	if entries[1].Kind != Command || entries[1].Text != "ls -la" || entries[1].Dir != "/home/me" {
+		t.Errorf("entry 2 = %+v", entries[1])
+	}
+	if err := Verify(entries); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+}
+
+func TestAppendConcurrently(t *testing.T) {
+	log := Log{Path: filepath.Join(t.TempDir(), "audit.log")}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Append(Command, "make test", "")
+		}()
+	}
+	wg.Wait()
+
+	entries, err := log.Entries()
+	if err != nil || len(entries) != 10 {
+		t.Fatalf("Entries() = %d entries, %v, want 10", len(entries), err)
+	}
+	if err := Verify(entries); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+}
+
+func TestEntriesOfMissingLog(t *testing.T) {
+	entries, err := Log{Path: filepath.Join(t.TempDir(), "audit.log")}.Entries()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Entries() = %v, %v, want none", entries, err)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		seq    int
+		reason string
+	}{
+		{
+			"changed text",
+			func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], "ls -la", "ls -l", 1)
+				return lines
+			},
+			2, "was changed",
+		},
+		{
+			"removed entry",
+			func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			2, "is missing",
+		},
+		{
+			"removed first entries",
+			func(lines []string) []string {
+				return lines[1:]
+			},
+			1, "is missing",
+		},
+		{
+			"reordered entries",
+			func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			2, "is missing",
+		},
+		{
+			"renumbered entries",
+			func(lines []string) []string {
+				lines = append(lines[:1], lines[2:]...)
+				lines[1] = strings.Replace(lines[1], `"seq":3`, `"seq":2`, 1)
+				return lines
+			},
+			2, "does not follow",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			log := writeLog(t)
+			data, _ := os.ReadFile(log.Path)
+			lines := test.tamper(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+			os.WriteFile(log.Path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+
+			entries, err := log.Entries()
+			if err != nil {
+				t.Fatalf("Entries() error = %v", err)
+			}
+			var chainErr *ChainError
+			if err := Verify(entries); !errors.As(err, &chainErr) || chainErr.Seq != test.seq || !strings.Contains(chainErr.Reason, test.reason) {
+				t.Errorf("Verify() = %v, want entry %d %s", err, test.seq, test.reason)
+			}
+		})
+	}
+}
+
+func TestEntriesRejectsInvalidLines(t *testing.T) {
+	log := writeLog(t)
+	file, _ := os.OpenFile(log.Path, os.O_APPEND|os.O_WRONLY, 0600)
+	file.WriteString("not json\n")
+	file.Close()
+
+	var chainErr *ChainError
+	if _, err := log.Entries(); !errors.As(err, &chainErr) || chainErr.Seq != 4 {
+		t.Errorf("Entries() = %v, want entry 4 to be invalid", err)
+	}
+}
+
+func TestAppendAfterTornLine(t *testing.T) {
+	log := writeLog(t)
+	file, _ := os.OpenFile(log.Path, os.O_APPEND|os.O_WRONLY, 0600)
+	file.WriteString(`{"seq":4,"time":"2024-`)
+	file.Close()
+
+	for _, command := range []string{"ls", "pwd"} {
+		if err := log.Append(Command, command, ""); err != nil {
+			t.Fatalf("Append() after a torn line error = %v", err)
+		}
+	}
+
+	// The torn line stays reported
+	var chainErr *ChainError
+	if _, err := log.Entries(); !errors.As(err, &chainErr) || chainErr.Seq != 4 {
+		t.Errorf("Entries() = %v, want entry 4 to be invalid", err)
+	}
+
+	// Without it, the entries after it chain to the ones before, and the
+	// break is reported
+	content, _ := os.ReadFile(log.Path)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("log has %d lines, want 7:\n%s", len(lines), content)
+	}
+	os.WriteFile(log.Path, []byte(strings.Join(append(lines[:3:3], lines[4:]...), "\n")+"\n"), 0600)
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 6 || entries[3].Kind != Break || entries[3].Prev != entries[2].Hash || entries[5].Text != "pwd" || entries[5].Seq != 6 {
+		t.Errorf("Entries() = %+v", entries)
+	}
+	if err := Verify(entries); !errors.As(err, &chainErr) || chainErr.Seq != 4 || !strings.Contains(chainErr.Reason, "line 4") {
+		t.Errorf("Verify() = %v, want the break at entry 4", err)
+	}
+}
@@ -0,0 +1,264 @@
+// Package cheat manages local cheatsheets, organized in packs below the
+// config directory.
+//
+// A cheatsheet is a text file named after the tool it covers, such as
+// "tar.txt". Each entry is a comment line describing a task followed by the
+// command lines performing it:
+//
+//	# Extract a gzip compressed archive
+//	tar -xzvf archive.tar.gz
+package cheat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a task described in a cheatsheet.
+type Entry struct {
+	Description string
+	Command     string
+}
+
+// Sheet is the cheatsheet of a tool in a pack.
+type Sheet struct {
+	Tool    string
+	Pack    string
+	Entries []Entry
+}
+
+// Extension is the file extension of cheatsheets.
+const Extension = ".txt"
+
+// DefaultPack is the pack the built-in cheatsheets are installed as.
+const DefaultPack = "core"
+
+// Parse reads a cheatsheet.
+func Parse(tool string, r io.Reader) (*Sheet, error) {
+	sheet := &Sheet{Tool: tool}
+
+	var description []string
+	var commands []string
+	flush := func() {
+		if len(commands) > 0 {
+			sheet.Entries = append(sheet.Entries, Entry{
+				Description: strings.Join(description, " "),
+				Command:     strings.Join(commands, "\n"),
+			})
+		}
+		description, commands = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#"):
+			if len(commands) > 0 {
+				flush()
+			}
+			description = append(description, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+		default:
+			commands = append(commands, line)
+		}
+	}
+	flush()
+
+	return sheet, scanner.Err()
+}
+
+// Search returns the entries containing all terms in their description or
+// command, ignoring case. Without terms all entries are returned.
+func (s *Sheet) Search(terms []string) []Entry {
+	var matches []Entry
+	for _, entry := range s.Entries {
+		text := strings.ToLower(entry.Description + " " + entry.Command)
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, strings.ToLower(term)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// Library is a directory holding cheatsheet packs, one subdirectory each.
+type Library struct {
+	Dir string
+}
+
+// InstallDefaults copies the cheatsheets in defaults into the default pack,
+// unless the pack already exists so edits and deletions are kept.
+func (l Library) InstallDefaults(defaults fs.FS) error {
+	packDir := filepath.Join(l.Dir, DefaultPack)
+	if _, err := os.Stat(packDir); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		return fmt.Errorf("failed to create cheatsheet directory: %w", err)
+	}
+
+	return fs.WalkDir(defaults, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != Extension {
+			return err
+		}
+		content, err := fs.ReadFile(defaults, path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(packDir, filepath.Base(path)), content, 0644)
+	})
+}
+
+// Find returns the cheatsheets of tool from all packs.
+func (l Library) Find(tool string) ([]*Sheet, error) {
+	packs, err := l.Packs()
+	if err != nil {
+		return nil, err
+	}
+
+	var sheets []*Sheet
+	for _, pack := range packs {
+		file, err := os.Open(filepath.Join(l.Dir, pack, tool+Extension))
+		if err != nil {
+			continue
+		}
+		sheet, err := Parse(tool, file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s cheatsheet of pack %s: %w", tool, pack, err)
+		}
+		sheet.Pack = pack
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// Packs returns the names of the installed packs, sorted.
+func (l Library) Packs() ([]string, error) {
+	entries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cheatsheet directory: %w", err)
+	}
+
+	var packs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			packs = append(packs, entry.Name())
+		}
+	}
+	sort.Strings(packs)
+	return packs, nil
+}
+
+// Tools returns the tools with cheatsheets, mapped to the packs covering them.
+func (l Library) Tools() (map[string][]string, error) {
+	packs, err := l.Packs()
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make(map[string][]string)
+	for _, pack := range packs {
+		files, err := filepath.Glob(filepath.Join(l.Dir, pack, "*"+Extension))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			tool := strings.TrimSuffix(filepath.Base(file), Extension)
+			tools[tool] = append(tools[tool], pack)
+		}
+	}
+	return tools, nil
+}
+
+// Import copies cheatsheets into pack. The source is a cheatsheet file, a
+// directory of cheatsheets or an http(s) URL of a cheatsheet file. It returns
+// the number of imported cheatsheets.
+func (l Library) Import(source, pack string) (int, error) {
+	if pack == "" || strings.ContainsAny(pack, `/\`) || strings.HasPrefix(pack, ".") {
+		return 0, fmt.Errorf("invalid pack name '%s'", pack)
+	}
+	packDir := filepath.Join(l.Dir, pack)
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create pack directory: %w", err)
+	}
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return 1, importURL(source, packDir)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	if !info.IsDir() {
+		return 1, copyFile(source, filepath.Join(packDir, filepath.Base(source)))
+	}
+
+	files, err := filepath.Glob(filepath.Join(source, "*"+Extension))
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		if err := copyFile(file, filepath.Join(packDir, filepath.Base(file))); err != nil {
+			return 0, err
+		}
+	}
+	return len(files), nil
+}
+
+// importURL downloads a cheatsheet into packDir, named after the last path
+// element of the URL.
+func importURL(url, packDir string) error {
+	name := filepath.Base(strings.SplitN(url, "?", 2)[0])
+	if filepath.Ext(name) != Extension {
+		return fmt.Errorf("cheatsheet URLs must end in %s", Extension)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return os.WriteFile(filepath.Join(packDir, name), content, 0644)
+}
+
+func copyFile(src, dst string) error {
+	if filepath.Ext(src) != Extension {
+		return fmt.Errorf("cheatsheets must end in %s: %s", Extension, src)
+	}
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	return os.WriteFile(dst, content, 0644)
+}
@@ -0,0 +1,112 @@
+package cheat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const tarSheet = `# Create an archive
+tar -czvf archive.tar.gz dir
+
+# Extract an archive
+# into a directory
+tar -xzvf archive.tar.gz -C dir
+# List an archive
+tar -tvf archive.tar.gz
+`
+
+func TestParseAndSearch(t *testing.T) {
+	sheet, err := Parse("tar", strings.NewReader(tarSheet))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(sheet.Entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %+v", sheet.Entries)
+	}
+	if sheet.Entries[1].Description != "Extract an archive into a directory" {
+		t.Errorf("Unexpected description %q", sheet.Entries[1].Description)
+	}
+
+	matches := sheet.Search([]string{"EXTRACT"})
+	if len(matches) != 1 || matches[0].Command != "tar -xzvf archive.tar.gz -C dir" {
+		t.Errorf("Unexpected matches %+v", matches)
+	}
+	if matches := sheet.Search([]string{"archive", "list"}); len(matches) != 1 {
+		t.Errorf("Expected all terms to match, got %+v", matches)
+	}
+	if matches := sheet.Search(nil); len(matches) != 3 {
+		t.Errorf("Expected all entries without terms, got %+v", matches)
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	library := Library{Dir: t.TempDir()}
+	defaults := fstest.MapFS{"cheatsheets/tar.txt": {Data: []byte(tarSheet)}}
+
+	if err := library.InstallDefaults(defaults); err != nil {
+		t.Fatalf("InstallDefaults() error = %v", err)
+	}
+
+	// Deleted defaults are not reinstalled
+	os.Remove(filepath.Join(library.Dir, DefaultPack, "tar.txt"))
+	if err := library.InstallDefaults(defaults); err != nil {
+		t.Fatalf("InstallDefaults() error = %v", err)
+	}
+	if sheets, _ := library.Find("tar"); len(sheets) != 0 {
+		t.Errorf("Expected deleted sheet to stay deleted, got %d sheets", len(sheets))
+	}
+
+	source := t.TempDir()
+	os.WriteFile(filepath.Join(source, "tar.txt"), []byte(tarSheet), 0644)
+	os.WriteFile(filepath.Join(source, "jq.txt"), []byte("# Pretty print\njq . file.json\n"), 0644)
+	os.WriteFile(filepath.Join(source, "notes.md"), []byte("ignored"), 0644)
+
+	count, err := library.Import(source, "community")
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Import() = %d, want 2", count)
+	}
+
+	sheets, err := library.Find("jq")
+	if err != nil || len(sheets) != 1 || sheets[0].Pack != "community" {
+		t.Errorf("Find(jq) = %v, %v", sheets, err)
+	}
+
+	tools, err := library.Tools()
+	if err != nil {
+		t.Fatalf("Tools() error = %v", err)
+	}
+	if len(tools) != 2 || tools["tar"][0] != "community" {
+		t.Errorf("Unexpected tools %v", tools)
+	}
+
+	if _, err := library.Import(source, "../escape"); err == nil {
+		t.Error("Expected error for invalid pack name")
+	}
+}
+
+func TestImportURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Pretty print\njq . file.json\n"))
+	}))
+	defer server.Close()
+
+	library := Library{Dir: t.TempDir()}
+	if _, err := library.Import(server.URL+"/sheets/jq.txt", "web"); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if sheets, _ := library.Find("jq"); len(sheets) != 1 || len(sheets[0].Entries) != 1 {
+		t.Errorf("Expected downloaded sheet, got %v", sheets)
+	}
+
+	if _, err := library.Import(server.URL+"/jq.html", "web"); err == nil {
+		t.Error("Expected error for URL without .txt extension")
+	}
+}
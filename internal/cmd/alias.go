@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/aliases"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage shell aliases",
+	Long: `Manage shell aliases stored in the Aura database. The shell integration
+sources a generated aliases file for bash, zsh, fish or PowerShell, which is
+regenerated whenever the aliases change, so every shell and every machine
+sharing the database uses the same aliases.
+
+Examples:
+  aura alias add gs git status -sb   # Add an alias
+  aura alias list                    # List aliases
+  aura alias remove gs               # Remove an alias
+  aura alias sync                    # Regenerate outdated aliases files`,
+}
+
+var aliasAddCmd = &cobra.Command{
+	Use:   "add <name> <command...>",
+	Short: "Add or replace an alias",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runAliasAdd,
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List aliases",
+	Args:    cobra.NoArgs,
+	RunE:    runAliasList,
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove an alias",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runAliasRemove,
+}
+
+var aliasSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Regenerate the aliases files if they are outdated",
+	Args:  cobra.NoArgs,
+	RunE:  runAliasSync,
+}
+
+var aliasInitCmd = &cobra.Command{
+	Use:   "init <shell>",
+	Short: "Print the shell code loading the aliases",
+	Long: `Print the shell code loading the aliases, regenerating the aliases file
+first if it is outdated. Used by the shell integration.
+
+Examples:
+  eval "$(command aura alias init bash)"                         # bash and zsh
+  command aura alias init fish | source                          # fish
+  Invoke-Expression (& aura.exe alias init powershell | Out-String)  # PowerShell`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	RunE:      runAliasInit,
+}
+
+var aliasSyncForce bool
+
+// aliasesDir returns the directory of the generated aliases files.
+func aliasesDir() string {
+	return filepath.Join(config.DataDir, "aliases")
+}
+
+func runAliasAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !aliases.ValidName(name) {
+		return fmt.Errorf("invalid alias name '%s'", name)
+	}
+	command := strings.Join(args[1:], " ")
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	if err := database.SetShellAlias(name, command); err != nil {
+		return err
+	}
+	if err := writeAliasFiles(database); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Added alias '%s' → %s\n", name, command)
+	return nil
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	list, err := database.ListShellAliases()
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Println("No aliases found. Add one with 'aura alias add <name> <command>'.")
+		return nil
+	}
+
+	width := 0
+	for _, alias := range list {
+		width = max(width, len(alias.Name))
+	}
+	for _, alias := range list {
+		fmt.Printf("  %-*s  %s\n", width, alias.Name, alias.Command)
+	}
+	return nil
+}
+
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	list, err := database.ListShellAliases()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, alias := range list {
+		found = found || alias.Name == args[0]
+	}
+	if !found {
+		return fmt.Errorf("alias '%s' not found", args[0])
+	}
+
+	if err := database.RemoveShellAlias(args[0]); err != nil {
+		return err
+	}
+	if err := writeAliasFiles(database); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Removed alias '%s'\n", args[0])
+	fmt.Println("ℹ️  Open a new shell to drop it from running shells.")
+	return nil
+}
+
+func runAliasSync(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	synced, err := syncAliasFiles(database, aliasSyncForce)
+	if err != nil {
+		return err
+	}
+	if synced {
+		fmt.Printf("✓ Regenerated aliases files in %s\n", aliasesDir())
+	} else {
+		fmt.Println("✓ Aliases files are up to date")
+	}
+	return nil
+}
+
+func runAliasInit(cmd *cobra.Command, args []string) error {
+	shell := args[0]
+	name, err := aliases.FileName(shell)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	if _, err := syncAliasFiles(database, false); err != nil {
+		return err
+	}
+
+	path := filepath.Join(aliasesDir(), name)
+	switch shell {
+	case "powershell", "pwsh":
+		fmt.Printf(". '%s'\n", strings.ReplaceAll(path, "'", "''"))
+	default:
+		fmt.Printf("source '%s'\n", strings.ReplaceAll(path, "'", `'\''`))
+	}
+	return nil
+}
+
+// syncAliasFiles regenerates the aliases files if their version differs from
+// the aliases in the database, and reports whether it did.
+func syncAliasFiles(database *db.DB, force bool) (bool, error) {
+	version, err := database.ShellAliasesVersion()
+	if err != nil {
+		return false, err
+	}
+
+	if !force {
+		current := true
+		for _, shell := range []string{"bash", "fish", "powershell"} {
+			name, _ := aliases.FileName(shell)
+			current = current && aliases.FileVersion(filepath.Join(aliasesDir(), name)) == version
+		}
+		if current {
+			return false, nil
+		}
+	}
+
+	return true, writeAliasFiles(database)
+}
+
+// writeAliasFiles regenerates the aliases files from the database.
+func writeAliasFiles(database *db.DB) error {
+	version, err := database.ShellAliasesVersion()
+	if err != nil {
+		return err
+	}
+	stored, err := database.ListShellAliases()
+	if err != nil {
+		return err
+	}
+
+	list := make([]aliases.Alias, 0, len(stored))
+	for _, alias := range stored {
+		list = append(list, aliases.Alias{Name: alias.Name, Command: alias.Command})
+	}
+	return aliases.WriteFiles(aliasesDir(), list, version)
+}
+
+func init() {
+	// Flags after the alias name belong to its command
+	aliasAddCmd.Flags().SetInterspersed(false)
+	aliasSyncCmd.Flags().BoolVar(&aliasSyncForce, "force", false, "Regenerate the files even if they are up to date")
+
+	aliasCmd.AddCommand(aliasAddCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	aliasCmd.AddCommand(aliasSyncCmd)
+	aliasCmd.AddCommand(aliasInitCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/diskusage"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze the current project",
+	Long:  `Analyze the current project, such as what takes up its disk space.`,
+}
+
+var analyzeSizeCmd = &cobra.Command{
+	Use:   "size [path]",
+	Short: "Show the biggest files and directories",
+	Long: `Show what takes up the space of a directory, the current one by default:
+a tree of its directories with their size, share and number of files, and
+the biggest files. Ignored files such as node_modules count too, since they
+take up space.
+
+With --history the biggest files in the Git history are listed as well,
+which keep the repository big even after they were deleted.
+
+Examples:
+  aura analyze size
+  aura analyze size --depth 3 --limit 5
+  aura analyze size --sort files ~/projects
+  aura analyze size --history --min-size 5`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAnalyzeSize,
+}
+
+var (
+	analyzeDepth   int
+	analyzeLimit   int
+	analyzeTop     int
+	analyzeSort    string
+	analyzeHistory bool
+	analyzeMinSize int
+)
+
+func runAnalyzeSize(cmd *cobra.Command, args []string) error {
+	order, err := diskusage.ParseOrder(analyzeSort)
+	if err != nil {
+		return failure.Wrap(failure.UserInput, err)
+	}
+	if analyzeDepth < 0 || analyzeLimit < 1 || analyzeTop < 0 || analyzeMinSize < 1 {
+		return failure.New(failure.UserInput, "--depth and --top must not be negative, --limit and --min-size must be at least 1")
+	}
+
+	root := "."
+	if len(args) == 1 {
+		root = args[0]
+	}
+	tree, err := diskusage.Scan(root)
+	if err != nil {
+		return fmt.Errorf("failed to scan '%s': %w", root, err)
+	}
+	tree.Sort(order)
+
+	printSizeTree(os.Stdout, tree, analyzeDepth, analyzeLimit)
+
+	if analyzeTop > 0 && tree.Dir {
+		fmt.Printf("\nBiggest files:\n")
+		for _, file := range tree.Largest(analyzeTop) {
+			fmt.Printf("  %10s  %s\n", formatSize(file.Size), file.Path)
+		}
+	}
+
+	if analyzeHistory {
+		if !isGitRepository() {
+			return failure.New(failure.UserInput, "--history needs a git repository")
+		}
+		threshold := int64(analyzeMinSize) << 20
+		blobs, err := historyBlobs(threshold)
+		if err != nil {
+			return fmt.Errorf("failed to read the Git history: %w", err)
+		}
+		fmt.Printf("\nBiggest files in the Git history (%s or more):\n", formatSize(threshold))
+		if len(blobs) == 0 {
+			fmt.Println("  none")
+		}
+		for i, blob := range blobs {
+			if i == analyzeTop {
+				fmt.Printf("  … %d more\n", len(blobs)-analyzeTop)
+				break
+			}
+			fmt.Printf("  %10s  %s\n", formatSize(blob.Size), blob.Path)
+		}
+	}
+	return nil
+}
+
+// printSizeTree prints tree with the size, share and number of files of each
+// entry, down to depth levels and at most limit entries per directory.
+func printSizeTree(w io.Writer, tree *diskusage.Node, depth, limit int) {
+	fmt.Fprintf(w, "📁 %s/ %s, %s\n", tree.Name, formatSize(tree.Size), fileCount(tree.Files))
+
+	var print func(node *diskusage.Node, prefix string, level int)
+	print = func(node *diskusage.Node, prefix string, level int) {
+		shown := node.Children
+		if len(shown) > limit {
+			shown = shown[:limit]
+		}
+		for i, child := range shown {
+			last := i == len(shown)-1 && len(shown) == len(node.Children)
+			branch, indent := "├── ", "│   "
+			if last {
+				branch, indent = "└── ", "    "
+			}
+			name := child.Name
+			details := ""
+			if child.Dir {
+				name += "/"
+				details = "  " + fileCount(child.Files)
+			}
+			fmt.Fprintf(w, "%s%s%-*s %10s %5s%s\n", prefix, branch, nameWidth(prefix), name, formatSize(child.Size), share(child.Size, tree.Size), details)
+			if child.Dir && level < depth {
+				print(child, prefix+indent, level+1)
+			}
+		}
+		if hidden := node.Children[len(shown):]; len(hidden) > 0 {
+			var size int64
+			for _, child := range hidden {
+				size += child.Size
+			}
+			fmt.Fprintf(w, "%s└── … %d more %s\n", prefix, len(hidden), formatSize(size))
+		}
+	}
+	if depth > 0 {
+		print(tree, "", 1)
+	}
+}
+
+// nameWidth is the width names are padded to, so sizes line up unless a
+// name is long.
+func nameWidth(prefix string) int {
+	const column = 36
+	width := column - len([]rune(prefix))
+	if width < 12 {
+		return 12
+	}
+	return width
+}
+
+// fileCount returns "1 file" or "<count> files".
+func fileCount(count int) string {
+	if count == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", count)
+}
+
+// share returns size as a percentage of total.
+func share(size, total int64) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", size*100/total)
+}
+
+func init() {
+	analyzeSizeCmd.Flags().IntVar(&analyzeDepth, "depth", 2, "Levels of directories to show")
+	analyzeSizeCmd.Flags().IntVar(&analyzeLimit, "limit", 10, "Entries to show per directory")
+	analyzeSizeCmd.Flags().IntVar(&analyzeTop, "top", 10, "Number of biggest files to list")
+	analyzeSizeCmd.Flags().StringVar(&analyzeSort, "sort", "size", "Order of entries: size, files or name")
+	analyzeSizeCmd.Flags().BoolVar(&analyzeHistory, "history", false, "List the biggest files in the Git history too")
+	analyzeSizeCmd.Flags().IntVar(&analyzeMinSize, "min-size", 1, "Size in MiB from which files in the Git history are listed")
+
+	analyzeCmd.AddCommand(analyzeSizeCmd)
+	rootCmd.AddCommand(analyzeCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/diskusage"
+)
+
+func TestPrintSizeTree(t *testing.T) {
+	tree := &diskusage.Node{Name: "app", Dir: true, Size: 4000, Files: 4, Children: []*diskusage.Node{
+		{Name: "assets", Dir: true, Size: 3000, Files: 2, Children: []*diskusage.Node{
+			{Name: "logo.png", Size: 2000, Files: 1},
+			{Name: "icon.svg", Size: 1000, Files: 1},
+		}},
+		{Name: "main.go", Size: 600, Files: 1},
+		{Name: "go.mod", Size: 400, Files: 1},
+	}}
+
+	var out bytes.Buffer
+	printSizeTree(&out, tree, 1, 2)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+	if len(lines) != 4 {
+		t.Fatalf("printSizeTree() printed %d lines:\n%s", len(lines), out.String())
+	}
+	if lines[0] != "📁 app/ 3.9 KiB, 4 files" {
+		t.Errorf("root line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "├── assets/") || !strings.Contains(lines[1], "2.9 KiB") || !strings.Contains(lines[1], "75%") || !strings.HasSuffix(lines[1], "2 files") {
+		t.Errorf("directory line = %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "├── main.go") || !strings.Contains(lines[2], "15%") {
+		t.Errorf("file line = %q", lines[2])
+	}
+	if lines[3] != "└── … 1 more 400 B" {
+		t.Errorf("summary line = %q", lines[3])
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/httpreq"
+	"github.com/timfewi/aura-cli-go/internal/openapi"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Call the API of the project from its OpenAPI spec",
+	Long:  `Work with the API described by the OpenAPI or Swagger spec of the project.`,
+}
+
+var apiCallCmd = &cobra.Command{
+	Use:   "call <request>",
+	Short: "Describe an API call and let the AI assistant write it",
+	Long: `Describe an API call in plain words and let the AI assistant write it from
+the OpenAPI or Swagger spec of the project: openapi.yaml, openapi.json,
+swagger.yaml or swagger.json in the current directory or in api/, docs/,
+spec/ or openapi/.
+
+The assistant only sees an outline of the spec, its operations with their
+parameters and body fields. The request is shown as an 'aura http' command
+and its curl equivalent and, once confirmed, sent like 'aura http' sends it.
+Credentials are left as {{VAR}} placeholders, filled from .env, or from
+.env.<profile> with --env, when the request is sent. Without a server in the
+spec, or with a relative one, the URL starts with {{API_URL}}.
+
+Examples:
+  aura api call "create a user named Bob"
+  aura api call "list the open orders of customer 42" --env staging
+  aura api call "delete pet 7" --spec ../petstore/openapi.yaml --server http://localhost:8080`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAPICall,
+}
+
+var (
+	apiSpec   string
+	apiServer string
+	apiYes    bool
+)
+
+// maxSpecOutline limits the size of the spec outline sent to the AI
+// assistant.
+const maxSpecOutline = 24000
+
+// apiAnswer is the JSON answer of the AI assistant.
+type apiAnswer struct {
+	Method      string   `json:"method"`
+	URL         string   `json:"url"`
+	Items       []string `json:"items"`
+	Explanation string   `json:"explanation"`
+}
+
+func runAPICall(cmd *cobra.Command, args []string) error {
+	path := apiSpec
+	if path == "" {
+		path = openapi.Find(".")
+		if path == "" {
+			return failure.New(failure.NotFound, "no OpenAPI spec found, pass one with --spec")
+		}
+	}
+	spec, err := openapi.Load(path)
+	if err != nil {
+		return failure.Wrap(failure.Config, fmt.Errorf("failed to read the spec: %w", err))
+	}
+	if len(spec.Operations) == 0 {
+		return failure.New(failure.Config, "%s describes no operations", path)
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	request, explanation, err := writeAPIRequest(client, spec, apiBaseURL(spec), args[0])
+	if err != nil {
+		return err
+	}
+
+	words := []string{"aura", "http", request.Method, quoteShellArg("bash", request.URL)}
+	for _, item := range request.Items {
+		words = append(words, quoteShellArg("bash", item))
+	}
+	fmt.Printf("%s %s\n", theme.Paint(theme.Heading, "aura:"), theme.Paint(theme.Command, strings.Join(words, " ")))
+	fmt.Printf("%s %s\n", theme.Paint(theme.Heading, "curl:"), theme.Paint(theme.Command, request.Curl()))
+	if explanation != "" {
+		fmt.Printf("ℹ️  %s\n", explanation)
+	}
+
+	if !confirmAction("api call", "Send it", true, apiYes) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	fmt.Println()
+	return sendHTTP(request)
+}
+
+// apiBaseURL returns the URL requests to the API of spec start with.
+func apiBaseURL(spec *openapi.Spec) string {
+	server := apiServer
+	if server == "" && len(spec.Servers) > 0 {
+		server = spec.Servers[0]
+	}
+	if server == "" || strings.HasPrefix(server, "/") {
+		server = "{{API_URL}}" + server
+	}
+	return strings.TrimSuffix(server, "/")
+}
+
+// writeAPIRequest asks the AI assistant for the request to the API of spec
+// at server doing what task asks.
+func writeAPIRequest(client ai.Assistant, spec *openapi.Spec, server, task string) (httpreq.Request, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	stopThinking := startThinking()
+	response, err := client.APIRequest(ctx, spec.Outline(maxSpecOutline), server, task)
+	stopThinking()
+
+	if err != nil {
+		return httpreq.Request{}, "", fmt.Errorf("failed to write the request: %w", err)
+	}
+	return parseAPIAnswer(response)
+}
+
+// parseAPIAnswer parses the JSON answer of the AI assistant into a request
+// and its explanation.
+func parseAPIAnswer(response string) (httpreq.Request, string, error) {
+	var answer apiAnswer
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start || json.Unmarshal([]byte(response[start:end+1]), &answer) != nil {
+		return httpreq.Request{}, "", failure.New(failure.API, "the AI assistant returned no valid request")
+	}
+	explanation := strings.TrimSpace(answer.Explanation)
+	if strings.TrimSpace(answer.URL) == "" {
+		if explanation != "" {
+			return httpreq.Request{}, "", failure.New(failure.UserInput, "%s", explanation)
+		}
+		return httpreq.Request{}, "", failure.New(failure.API, "the AI assistant returned no valid request")
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(answer.Method))
+	if method == "" {
+		method = "GET"
+	}
+	request, err := httpreq.Parse(append([]string{method, strings.TrimSpace(answer.URL)}, answer.Items...))
+	if err != nil {
+		return httpreq.Request{}, "", failure.New(failure.API, "the AI assistant returned an invalid request: %v", err)
+	}
+	return request, explanation, nil
+}
+
+func init() {
+	apiCallCmd.Flags().StringVar(&apiSpec, "spec", "", "OpenAPI or Swagger spec to use instead of the project's")
+	apiCallCmd.Flags().StringVar(&apiServer, "server", "", "Base URL of the API instead of the spec's first server")
+	apiCallCmd.Flags().StringVarP(&httpEnv, "env", "e", "", "Read variables from .env.<profile> instead of .env")
+	apiCallCmd.Flags().DurationVar(&httpTimeout, "timeout", 30*time.Second, "Give up on the request after this long")
+	apiCallCmd.Flags().BoolVarP(&apiYes, "yes", "y", false, "Send the request without asking")
+
+	apiCmd.AddCommand(apiCallCmd)
+	rootCmd.AddCommand(apiCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/openapi"
+)
+
+func TestParseAPIAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "request",
+			response: `{"method": "post", "url": "https://api.example.com/users", "items": ["name=Bob"], "explanation": "Creates Bob"}`,
+			want:     []string{"POST", "https://api.example.com/users", "name=Bob"},
+		},
+		{
+			name:     "fenced without method",
+			response: "```json\n{\"url\": \"{{API_URL}}/users\", \"items\": [\"page==2\"]}\n```",
+			want:     []string{"GET", "{{API_URL}}/users", "page==2"},
+		},
+		{name: "impossible", response: `{"url": "", "explanation": "The API has no users"}`, wantErr: true},
+		{name: "invalid item", response: `{"url": "https://api.example.com", "items": ["name"]}`, wantErr: true},
+		{name: "no JSON", response: "Sorry, I can't help.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, _, err := parseAPIAnswer(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAPIAnswer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := append([]string{request.Method, request.URL}, request.Items...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAPIAnswer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIBaseURL(t *testing.T) {
+	tests := []struct {
+		servers []string
+		flag    string
+		want    string
+	}{
+		{[]string{"https://api.example.com/v1/"}, "", "https://api.example.com/v1"},
+		{[]string{"/v1"}, "", "{{API_URL}}/v1"},
+		{nil, "", "{{API_URL}}"},
+		{[]string{"https://api.example.com"}, "http://localhost:8080", "http://localhost:8080"},
+	}
+	defer func() { apiServer = "" }()
+	for _, tt := range tests {
+		apiServer = tt.flag
+		if got := apiBaseURL(&openapi.Spec{Servers: tt.servers}); got != tt.want {
+			t.Errorf("apiBaseURL(%q, --server %q) = %q, want %q", tt.servers, tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestWriteAPIRequest(t *testing.T) {
+	spec := &openapi.Spec{Title: "Users", Operations: []openapi.Operation{{Method: "POST", Path: "/users", Body: "{name*: string}"}}}
+	client := &MockAIClient{response: `{"method": "POST", "url": "https://api.example.com/users", "items": ["name=Bob"], "explanation": "Creates the user Bob"}`}
+
+	request, explanation, err := writeAPIRequest(client, spec, "https://api.example.com", "create a user named Bob")
+	if err != nil {
+		t.Fatalf("writeAPIRequest() error = %v", err)
+	}
+	if request.Method != "POST" || explanation != "Creates the user Bob" {
+		t.Errorf("writeAPIRequest() = %+v, %q", request, explanation)
+	}
+	if len(client.questions) != 1 || !strings.Contains(client.questions[0], "Bob") {
+		t.Errorf("writeAPIRequest() asked %q", client.questions)
+	}
+}
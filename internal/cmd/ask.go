@@ -12,6 +12,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/secrets"
 )
 
 var askCmd = &cobra.Command{
@@ -27,8 +34,51 @@ Examples:
 	RunE: runAsk,
 }
 
-func runAsk(cmd *cobra.Command, args []string) error {
+// newAssistant returns the AI assistant the commands talk to. Tests replace
+// it to run command flows offline.
+var newAssistant = func() (ai.Assistant, error) {
 	client, err := ai.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	if showPrompt {
+		client.Review = reviewPrompt
+	}
+	return client, nil
+}
+
+// scrubSecrets replaces the likely secrets in text, such as "the staged
+// diff" named by what, before it is sent to the AI assistant or kept, and
+// tells on stderr which were replaced.
+func scrubSecrets(what, text string) string {
+	scrubbed, findings := secrets.Scrub(what, text)
+	if len(findings) == 0 {
+		return text
+	}
+	fmt.Fprintf(os.Stderr, "ℹ️  Redacted %d likely %s from %s:\n", len(findings), plural(int64(len(findings)), "secret", "secrets"), what)
+	for _, finding := range findings {
+		fmt.Fprintf(os.Stderr, "   line %d: %s (%s)\n", finding.Line, finding.Rule, finding.Match)
+	}
+	return scrubbed
+}
+
+// reviewPrompt shows a prompt on stderr and asks on the terminal whether to
+// send it, for --show-prompt. Without a terminal it is not sent.
+func reviewPrompt(prompt string) error {
+	fmt.Fprintf(os.Stderr, "%s\n", prompt)
+	tty, err := os.OpenFile(platform.Current().TerminalDevice(), os.O_RDWR, 0)
+	if err != nil {
+		return failure.New(failure.General, "the prompt was not sent, there is no terminal to confirm it on")
+	}
+	tty.Close()
+	if !confirmOnTerminal("Send this prompt to the AI assistant?", false) {
+		return failure.New(failure.General, "the prompt was not sent")
+	}
+	return nil
+}
+
+func runAsk(cmd *cobra.Command, args []string) error {
+	client, err := newAssistant()
 	if err != nil {
 		return fmt.Errorf("failed to initialize AI client: %w", err)
 	}
@@ -44,6 +94,10 @@ func runAsk(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to read from stdin: %w", err)
 		}
 		stdinContent := strings.TrimSpace(string(stdinBytes))
+		if err := ai.RequireFileContents("piped input"); err != nil {
+			return err
+		}
+		stdinContent = scrubSecrets("piped input", stdinContent)
 
 		if len(args) == 0 {
 			// If no question provided, use default
@@ -57,74 +111,80 @@ func runAsk(cmd *cobra.Command, args []string) error {
 		// No piped input, use command line arguments
 		if len(args) == 0 {
 			// Interactive mode
-			return runInteractiveAsk(client)
+			return runInteractiveAsk(client, os.Stdin)
 		}
 		question = strings.Join(args, " ")
 	}
 
-	// Create context with timeout
+	response, err := askAssistant(client, question)
+	if err != nil {
+		return err
+	}
+
+	// Print the response
+	pageOutput(func() error {
+		fmt.Printf("\n%s\n", response)
+		return nil
+	})
+	printFallbackNote(client)
+	saveConversation(client, question, response)
+	return nil
+}
+
+// askAssistant asks the AI assistant a question, showing a spinner while it
+// thinks.
+func askAssistant(client ai.Assistant, question string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Show thinking indicator
-	done := make(chan bool)
-	go showThinking(done)
-
-	// Get response from AI
+	stopThinking := startThinking()
 	response, err := client.Ask(ctx, question)
-	done <- true
+	stopThinking()
 
 	if err != nil {
-		return fmt.Errorf("AI request failed: %w", err)
+		return "", fmt.Errorf("AI request failed: %w", err)
 	}
-
-	// Print the response
-	fmt.Printf("\n%s\n", response)
-	return nil
+	return response, nil
 }
 
-func runInteractiveAsk(client *ai.Client) error {
+// runInteractiveAsk answers the questions read from input, one per line,
+// until "exit" or "quit".
+func runInteractiveAsk(client ai.Assistant, input io.Reader) error {
 	fmt.Println("Aura AI Assistant - Interactive Mode")
 	fmt.Println("Type your questions or 'exit' to quit.")
 	fmt.Println()
 
-	scanner := bufio.NewScanner(os.Stdin)
+	scanner := bufio.NewScanner(input)
+	reloader := config.NewReloader(config.GetSettingsFile())
 
 	for {
 		fmt.Print("❯ ")
 		if !scanner.Scan() {
 			break
 		}
+		reloadSettings(reloader)
 
-		input := strings.TrimSpace(scanner.Text())
-		if input == "" {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
 			continue
 		}
 
-		if input == "exit" || input == "quit" {
+		if line == "exit" || line == "quit" {
 			fmt.Println("Goodbye!")
 			break
 		}
 
-		// Create context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-
-		// Show thinking indicator
-		done := make(chan bool)
-		go showThinking(done)
-
-		// Get response from AI
-		response, err := client.Ask(ctx, input)
-		done <- true
-		cancel()
-
+		response, err := askAssistant(client, line)
 		if err != nil {
 			fmt.Printf("Error: %v\n\n", err)
 			continue
 		}
 
 		// Print the response
-		fmt.Printf("\n%s\n\n", response)
+		fmt.Printf("\n%s\n", response)
+		printFallbackNote(client)
+		saveConversation(client, line, response)
+		fmt.Println()
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -134,23 +194,42 @@ func runInteractiveAsk(client *ai.Client) error {
 	return nil
 }
 
-func showThinking(done chan bool) {
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
+// startThinking shows a spinner while the AI assistant works and returns the
+// function stopping it. Long requests show a desktop notification when they
+// are done. With --show-prompt nothing is shown.
+func startThinking() (stop func()) {
+	if showPrompt {
+		// The spinner would draw over the prompt under review
+		return func() {}
+	}
+	spinner := progress.NewSpinner(i18n.T("ai.thinking")).Start()
+	return func() {
+		spinner.Stop()
+		notifyFinished("ai", spinner.Elapsed().Round(time.Second), "Aura", i18n.T("ai.done"))
+	}
+}
+
+// printFallbackNote tells the user when a fallback model answered instead of
+// the configured one.
+func printFallbackNote(client ai.Assistant) {
+	if model, fallback := client.AnsweredBy(); fallback {
+		fmt.Fprintf(os.Stderr, "ℹ️  %s\n", i18n.T("ai.fallback_answered", model))
+	}
+}
 
-	chars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-	i := 0
+// saveConversation keeps the question and answer for 'aura recall'. Failing
+// to save only warns, the answer was already shown.
+func saveConversation(client ai.Assistant, question, answer string) {
+	database, err := db.New()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save conversation: %v\n", err)
+		return
+	}
+	defer database.Close()
 
-	fmt.Print("Thinking ")
-	for {
-		select {
-		case <-done:
-			fmt.Print("\r" + strings.Repeat(" ", 20) + "\r") // Clear the line
-			return
-		case <-ticker.C:
-			fmt.Printf("\rThinking %s", chars[i%len(chars)])
-			i++
-		}
+	model, _ := client.AnsweredBy()
+	if err := database.SaveConversation(question, answer, model); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save conversation: %v\n", err)
 	}
 }
 
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
 )
 
 func TestRunAsk(t *testing.T) {
@@ -72,23 +75,12 @@ func TestRunAsk(t *testing.T) {
 	}
 }
 
-func TestShowThinking(t *testing.T) {
-	// Test that showThinking doesn't panic and responds to done signal
-	done := make(chan bool)
-
-	// Start showThinking in a goroutine
-	go showThinking(done)
-
-	// Send done signal after a short delay
-	go func() {
-		time.Sleep(100 * time.Millisecond)
-		done <- true
-	}()
-
-	// Wait a bit to ensure the function completes
-	time.Sleep(200 * time.Millisecond)
-
-	// If we reach here without hanging, the test passes
+func TestStartThinking(t *testing.T) {
+	// Test that the spinner stops without hanging, also when stopped twice
+	stop := startThinking()
+	time.Sleep(100 * time.Millisecond)
+	stop()
+	stop()
 }
 
 func TestAskCommandConfiguration(t *testing.T) {
@@ -110,38 +102,36 @@ func TestAskCommandConfiguration(t *testing.T) {
 	}
 }
 
-// TestAskWithMockClient tests ask functionality with a mock AI client
+// TestAskWithMockClient runs ask against a mock AI client
 func TestAskWithMockClient(t *testing.T) {
-	// Since we can't easily mock the ai.NewClient() call in runAsk,
-	// we'll test the components that we can isolate
+	client := &MockAIClient{response: "Use ls -la"}
+	useMockAssistant(t, client)
 
-	// Test question formation
-	tests := []struct {
-		name         string
-		args         []string
-		stdinContent string
-		expected     string
-	}{
-		{
-			name:     "simple question",
-			args:     []string{"how", "to", "list", "files"},
-			expected: "how to list files",
-		},
-		{
-			name:     "single word question",
-			args:     []string{"help"},
-			expected: "help",
-		},
+	if err := runAsk(askCmd, []string{"how", "to", "list", "files"}); err != nil {
+		t.Fatalf("runAsk() error = %v", err)
+	}
+	if len(client.questions) != 1 || client.questions[0] != "how to list files" {
+		t.Errorf("Asked %q, want [how to list files]", client.questions)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Test string joining logic
-			result := strings.Join(tt.args, " ")
-			if result != tt.expected {
-				t.Errorf("Question formation = %v, want %v", result, tt.expected)
-			}
-		})
+	client.shouldError = true
+	if err := runAsk(askCmd, []string{"help"}); err == nil || !strings.Contains(err.Error(), "AI request failed") {
+		t.Errorf("runAsk() error = %v, want an AI request error", err)
+	}
+}
+
+func TestRunInteractiveAsk(t *testing.T) {
+	client := &MockAIClient{response: "An answer"}
+	useMockAssistant(t, client)
+
+	input := strings.NewReader("first question\n\n  second question  \nexit\nnever asked\n")
+	if err := runInteractiveAsk(client, input); err != nil {
+		t.Fatalf("runInteractiveAsk() error = %v", err)
+	}
+
+	want := []string{"first question", "second question"}
+	if strings.Join(client.questions, "|") != strings.Join(want, "|") {
+		t.Errorf("Asked %q, want %q", client.questions, want)
 	}
 }
 
@@ -305,19 +295,107 @@ func TestAskThinkingAnimation(t *testing.T) {
 	}
 }
 
-// MockAIClient for testing (if we want to implement more sophisticated tests)
+// MockAIClient is an AI assistant answering without a network, recording
+// what it was asked.
 type MockAIClient struct {
+	ai.Assistant
 	shouldError bool
 	response    string
+	questions   []string
+	environment map[string]string
 }
 
-func (m *MockAIClient) Ask(ctx context.Context, question string) (string, error) {
+func (m *MockAIClient) reply(question string) (string, error) {
+	m.questions = append(m.questions, question)
 	if m.shouldError {
 		return "", errors.New("mock error")
 	}
 	return m.response, nil
 }
 
+func (m *MockAIClient) Ask(ctx context.Context, question string) (string, error) {
+	return m.reply(question)
+}
+
+func (m *MockAIClient) GenerateCommitMessage(ctx context.Context, diff, scope, issue string) (string, error) {
+	return m.reply(diff)
+}
+
+func (m *MockAIClient) SplitCommits(ctx context.Context, hunks string) (string, error) {
+	return m.reply(hunks)
+}
+
+func (m *MockAIClient) DebugIssue(ctx context.Context, errorMsg string, commandRun string, environment map[string]string) (string, error) {
+	m.environment = environment
+	return m.reply(errorMsg)
+}
+
+func (m *MockAIClient) RepoHealthPlan(ctx context.Context, report string) (string, error) {
+	return m.reply(report)
+}
+
+func (m *MockAIClient) DescribeProject(ctx context.Context, summary, readme string) (string, error) {
+	return m.reply(summary)
+}
+
+func (m *MockAIClient) UpgradeGuidance(ctx context.Context, report string) (string, error) {
+	return m.reply(report)
+}
+
+func (m *MockAIClient) GenerateTests(ctx context.Context, file, source, conventions string) (string, error) {
+	return m.reply(source)
+}
+
+func (m *MockAIClient) DocumentCode(ctx context.Context, file, source, symbols string) (string, error) {
+	return m.reply(symbols)
+}
+
+func (m *MockAIClient) RefactorCode(ctx context.Context, file, source, instruction, feedback string) (string, error) {
+	return m.reply(instruction)
+}
+
+func (m *MockAIClient) BuildRegex(ctx context.Context, description, flavor string) (string, error) {
+	return m.reply(description)
+}
+
+func (m *MockAIClient) ExplainRegex(ctx context.Context, pattern, flavor string) (string, error) {
+	return m.reply(pattern)
+}
+
+func (m *MockAIClient) CronExpression(ctx context.Context, description string) (string, error) {
+	return m.reply(description)
+}
+
+func (m *MockAIClient) QueryExpression(ctx context.Context, tool, outline, question string) (string, error) {
+	return m.reply(question)
+}
+
+func (m *MockAIClient) DataInsights(ctx context.Context, profile string) (string, error) {
+	return m.reply(profile)
+}
+
+func (m *MockAIClient) APIRequest(ctx context.Context, outline, server, task string) (string, error) {
+	return m.reply(task)
+}
+
+func (m *MockAIClient) Standup(ctx context.Context, activity string) (string, error) {
+	return m.reply(activity)
+}
+
+func (m *MockAIClient) AnsweredBy() (string, bool) {
+	return "mock-model", false
+}
+
+// useMockAssistant makes the commands talk to client and keeps their
+// conversations in a temporary database.
+func useMockAssistant(t *testing.T, client *MockAIClient) {
+	t.Helper()
+	t.Setenv("AURA_DB_PATH", filepath.Join(t.TempDir(), "aura.db"))
+
+	original := newAssistant
+	newAssistant = func() (ai.Assistant, error) { return client, nil }
+	t.Cleanup(func() { newAssistant = original })
+}
 func TestMockAIClient(t *testing.T) {
 	// Test our mock client works as expected
 	client := &MockAIClient{
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/auditlog"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of prompts and commands",
+	Long: `Show and export the audit log, which records every prompt sent to the AI
+assistant and every command Aura runs for you, such as those of aura do,
+aura exec, aura each, aura schedule, aura watch and setup steps of projects,
+commands handed to your shell and commits with generated messages.
+
+Entries are recorded before the prompt is sent or the command is run; if
+that fails, neither happens. The log is append-only: each entry holds the
+hash of the entry before it, so changed, removed or reordered entries are
+detected. A last line that can't be read, such as one torn by a crash, is
+followed by a break entry so recording goes on, and stays reported. It is
+audit.log in the state directory, see 'aura config doctor'.`,
+}
+
+var auditShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the recent entries of the audit log and verify it",
+	Long: `Show the recent entries of the audit log, the oldest first, and verify its
+hash chain. Exits with an error if the log was tampered with.
+
+Examples:
+  aura audit show
+  aura audit show --kind command -n 50
+  aura audit show --full`,
+	Args: cobra.NoArgs,
+	RunE: paged(runAuditShow),
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the audit log as JSON lines or CSV",
+	Long: `Export all entries of the audit log. JSON lines are the entries as in the
+log, with their hashes, so the export can be verified elsewhere. A broken
+hash chain is reported on stderr, the entries are exported anyway.
+
+Examples:
+  aura audit export > audit.jsonl
+  aura audit export --format csv -o audit.csv`,
+	Args: cobra.NoArgs,
+	RunE: runAuditExport,
+}
+
+var (
+	auditKind   string
+	auditLimit  int
+	auditFull   bool
+	auditFormat string
+	auditOutput string
+)
+
+// checkCommand is called before Aura runs command in dir or hands it to the
+// shell. It refuses all commands if the organization policy disables them or
+// in dry-run mode, and those banned by the team settings, and records the
+// others in the audit log; commands that can't be recorded are not run.
+func checkCommand(command, dir string) error {
+	if err := config.RequireFeature(config.FeatureCommands, "running commands"); err != nil {
+		return err
+	}
+	if config.DryRun() {
+		return failure.New(failure.General, "'%s' was not run in dry-run mode", command)
+	}
+	if banned := config.BannedCommand(command); banned != "" {
+		return failure.New(failure.Config, "'%s' was not run, %s bans %s", command, displayHomePath(config.TeamSettingsFile), banned)
+	}
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	if err := auditlog.Default().Append(auditlog.Command, command, dir); err != nil {
+		return failure.New(failure.General, "'%s' was not run: %v", command, err)
+	}
+	return nil
+}
+
+// allowShellCommand checks command like checkCommand before it is handed to
+// a shell that runs it after Aura, such as the one of the user or of a tmux
+// session. If it is refused it warns that what, such as "the on-enter
+// command of 'api'", is skipped.
+func allowShellCommand(command, dir, what string) bool {
+	if err := checkCommand(command, dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", what, err)
+		return false
+	}
+	return true
+}
+
+// auditEntries returns the entries of the audit log.
+func auditEntries() ([]auditlog.Entry, error) {
+	entries, err := auditlog.Default().Entries()
+	var chainErr *auditlog.ChainError
+	if errors.As(err, &chainErr) {
+		return nil, failure.Wrap(failure.General, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the audit log: %w", err)
+	}
+	return entries, nil
+}
+
+func runAuditShow(cmd *cobra.Command, args []string) error {
+	if auditKind != "" && auditKind != auditlog.Prompt && auditKind != auditlog.Command {
+		return failure.New(failure.UserInput, "unknown kind '%s', use prompt or command", auditKind)
+	}
+	entries, err := auditEntries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("ℹ️  The audit log is empty")
+		return nil
+	}
+
+	var shown []auditlog.Entry
+	for _, entry := range entries {
+		if auditKind == "" || entry.Kind == auditKind {
+			shown = append(shown, entry)
+		}
+	}
+	if auditLimit > 0 && len(shown) > auditLimit {
+		shown = shown[len(shown)-auditLimit:]
+	}
+	for _, entry := range shown {
+		printAuditEntry(os.Stdout, entry, auditFull)
+	}
+
+	if err := auditlog.Verify(entries); err != nil {
+		return failure.Wrap(failure.General, err)
+	}
+	fmt.Printf("\n✓ %d %s, hash chain intact\n", len(entries), plural(int64(len(entries)), "entry", "entries"))
+	return nil
+}
+
+// printAuditEntry prints entry on one line, or with its whole text if full.
+func printAuditEntry(w io.Writer, entry auditlog.Entry, full bool) {
+	header := fmt.Sprintf("#%-5d %s  %-7s  %s", entry.Seq, entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Kind, entry.Source)
+	if !full {
+		fmt.Fprintf(w, "%s  %s\n", theme.Paint(theme.Muted, header), truncateLine(auditSummary(entry), 72))
+		return
+	}
+	fmt.Fprintln(w, theme.Paint(theme.Muted, header))
+	if entry.Dir != "" {
+		fmt.Fprintf(w, "       in %s\n", entry.Dir)
+	}
+	for _, line := range strings.Split(strings.TrimRight(entry.Text, "\n"), "\n") {
+		fmt.Fprintf(w, "       %s\n", line)
+	}
+	fmt.Fprintln(w)
+}
+
+// userHeader starts the user messages of prompts in the audit log.
+const userHeader = "--- user ---\n"
+
+// auditSummary returns the text of entry on one line. Prompts are summed up
+// by their last user message, which their system message precedes.
+func auditSummary(entry auditlog.Entry) string {
+	text := entry.Text
+	if entry.Kind == auditlog.Prompt {
+		if i := strings.LastIndex(text, userHeader); i >= 0 {
+			text = text[i+len(userHeader):]
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func runAuditExport(cmd *cobra.Command, args []string) error {
+	if auditFormat != "json" && auditFormat != "csv" {
+		return failure.New(failure.UserInput, "unknown format '%s', use json or csv", auditFormat)
+	}
+	entries, err := auditEntries()
+	if err != nil {
+		return err
+	}
+	if err := auditlog.Verify(entries); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if auditOutput != "" {
+		file, err := os.Create(auditOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", auditOutput, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if auditFormat == "csv" {
+		err = writeAuditCSV(out, entries)
+	} else {
+		err = writeAuditJSON(out, entries)
+	}
+	if err != nil {
+		return err
+	}
+	if auditOutput != "" {
+		fmt.Printf("✓ Exported %d %s to %s\n", len(entries), plural(int64(len(entries)), "entry", "entries"), auditOutput)
+	}
+	return nil
+}
+
+// writeAuditJSON writes entries as JSON lines, one entry per line.
+func writeAuditJSON(w io.Writer, entries []auditlog.Entry) error {
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeAuditCSV writes entries as CSV with one row per entry.
+func writeAuditCSV(w io.Writer, entries []auditlog.Entry) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"seq", "time", "kind", "source", "dir", "text", "prev", "hash"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			strconv.Itoa(entry.Seq),
+			entry.Time.Format(time.RFC3339Nano),
+			entry.Kind,
+			entry.Source,
+			entry.Dir,
+			entry.Text,
+			entry.Prev,
+			entry.Hash,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	auditShowCmd.Flags().StringVar(&auditKind, "kind", "", "Only entries of this kind: prompt or command")
+	auditShowCmd.Flags().IntVarP(&auditLimit, "limit", "n", 20, "Number of entries to show, 0 for all")
+	auditShowCmd.Flags().BoolVar(&auditFull, "full", false, "Show the whole prompts and commands")
+	auditExportCmd.Flags().StringVar(&auditFormat, "format", "json", "Format: json (JSON lines) or csv")
+	auditExportCmd.Flags().StringVarP(&auditOutput, "output", "o", "", "File to write, stdout if not set")
+	auditCmd.AddCommand(auditShowCmd)
+	auditCmd.AddCommand(auditExportCmd)
+	rootCmd.AddCommand(auditCmd)
+}
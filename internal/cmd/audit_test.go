@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/auditlog"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestCheckCommandAudits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	original := config.StateDir
+	defer func() { config.StateDir = original }()
+	config.StateDir = t.TempDir()
+	dir := t.TempDir()
+
+	if run := runScheduled("check", "touch ran", dir, io.Discard); run.ExitCode != 0 {
+		t.Fatalf("runScheduled() = %+v", run)
+	}
+	entries, err := auditlog.Default().Entries()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Entries() = %v, %v, want the command", entries, err)
+	}
+	if entries[0].Kind != auditlog.Command || entries[0].Text != "touch ran" || entries[0].Dir != dir {
+		t.Errorf("entry = %+v", entries[0])
+	}
+
+	// A command that can't be recorded is not run
+	config.StateDir = filepath.Join(dir, "ran")
+	os.Remove(config.StateDir)
+	os.WriteFile(config.StateDir, nil, 0644)
+	run := runScheduled("check", "touch again", dir, io.Discard)
+	if _, err := os.Stat(filepath.Join(dir, "again")); err == nil || run.ExitCode != -1 {
+		t.Errorf("runScheduled() = %+v, want the command not run", run)
+	}
+}
+
+func TestCheckCommandBanned(t *testing.T) {
+	originalDir, originalTeam := config.StateDir, config.Team
+	defer func() { config.StateDir, config.Team = originalDir, originalTeam }()
+	config.StateDir = t.TempDir()
+	config.Team.BannedCommands = []string{"git push --force*"}
+
+	err := checkCommand("make test && git push --force", "")
+	if failure.KindOf(err) != failure.Config || !strings.Contains(err.Error(), "bans git push --force*") {
+		t.Errorf("checkCommand() = %v, want the command banned", err)
+	}
+	if err := checkCommand("git push", ""); err != nil {
+		t.Errorf("checkCommand() = %v, want it allowed", err)
+	}
+	if entries, _ := auditlog.Default().Entries(); len(entries) != 1 || entries[0].Text != "git push" {
+		t.Errorf("audit log = %+v, want only the command run", entries)
+	}
+}
+
+func TestCheckCommandDisabledByPolicy(t *testing.T) {
+	originalDir, originalPolicy := config.StateDir, config.ActivePolicy
+	defer func() { config.StateDir, config.ActivePolicy = originalDir, originalPolicy }()
+	config.StateDir = t.TempDir()
+	config.ActivePolicy.Disable = []string{config.FeatureCommands}
+
+	if err := checkCommand("ls", ""); failure.KindOf(err) != failure.Config {
+		t.Errorf("checkCommand() = %v, want commands disabled", err)
+	}
+	if entries, _ := auditlog.Default().Entries(); len(entries) != 0 {
+		t.Errorf("audit log = %+v, want no command run", entries)
+	}
+}
+
+func TestAllowShellCommand(t *testing.T) {
+	originalDir, originalPolicy := config.StateDir, config.ActivePolicy
+	defer func() { config.StateDir, config.ActivePolicy = originalDir, originalPolicy }()
+	config.StateDir = t.TempDir()
+
+	if !allowShellCommand("nvm use", "/repos/web", "the on-enter command of 'web'") {
+		t.Error("allowShellCommand() = false, want the command allowed")
+	}
+	config.ActivePolicy.Disable = []string{config.FeatureCommands}
+	if allowShellCommand("npm run dev", "/repos/web", "the startup command of the session") {
+		t.Error("allowShellCommand() = true, want commands disabled")
+	}
+	if entries, _ := auditlog.Default().Entries(); len(entries) != 1 || entries[0].Text != "nvm use" || entries[0].Dir != "/repos/web" {
+		t.Errorf("audit log = %+v, want only the allowed command", entries)
+	}
+}
+
+func TestCheckCommandDryRun(t *testing.T) {
+	original := config.StateDir
+	defer func() { config.StateDir = original }()
+	config.StateDir = t.TempDir()
+	t.Setenv("AURA_DRY_RUN", "1")
+	dir := t.TempDir()
+
+	if run := runScheduled("check", "touch ran", dir, io.Discard); run.ExitCode != -1 || !strings.Contains(run.Output, "dry-run") {
+		t.Errorf("runScheduled() = %+v, want the command not run", run)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran")); err == nil {
+		t.Error("the command ran in dry-run mode")
+	}
+}
+
+func TestAuditSummary(t *testing.T) {
+	prompt := auditlog.Entry{Kind: auditlog.Prompt, Text: "Prompt for gpt-4o:\n\n--- system ---\nYou are Aura\n\n--- user ---\nhow do I\nlist files?\n"}
+	if got := auditSummary(prompt); got != "how do I list files?" {
+		t.Errorf("auditSummary() = %q, want the user message", got)
+	}
+	command := auditlog.Entry{Kind: auditlog.Command, Text: "git commit -m \"Fix --- user ---\nlogin\""}
+	if got := auditSummary(command); got != `git commit -m "Fix --- user --- login"` {
+		t.Errorf("auditSummary() = %q, want the whole command", got)
+	}
+}
+
+func TestWriteAudit(t *testing.T) {
+	log := auditlog.Log{Path: filepath.Join(t.TempDir(), "audit.log")}
+	log.Append(auditlog.Command, "echo \"a, b\"", "/home/me")
+	log.Append(auditlog.Prompt, "Prompt for gpt-4o:\n\n--- user ---\nhi", "")
+	entries, _ := log.Entries()
+
+	var out bytes.Buffer
+	if err := writeAuditJSON(&out, entries); err != nil {
+		t.Fatalf("writeAuditJSON() error = %v", err)
+	}
+	exported := auditlog.Log{Path: filepath.Join(t.TempDir(), "audit.jsonl")}
+	os.WriteFile(exported.Path, out.Bytes(), 0600)
+	if again, err := exported.Entries(); err != nil || auditlog.Verify(again) != nil || len(again) != 2 {
+		t.Errorf("the JSON export doesn't verify: %v", err)
+	}
+
+	out.Reset()
+	if err := writeAuditCSV(&out, entries); err != nil {
+		t.Fatalf("writeAuditCSV() error = %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("CSV = %v, %v, want a header and 2 rows", records, err)
+	}
+	if strings.Join(records[0], ",") != "seq,time,kind,source,dir,text,prev,hash" {
+		t.Errorf("CSV header = %v", records[0])
+	}
+	if records[1][5] != `echo "a, b"` || records[2][6] != entries[0].Hash {
+		t.Errorf("CSV rows = %v", records[1:])
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure the performance of Aura",
+}
+
+var benchStartupCmd = &cobra.Command{
+	Use:   "startup",
+	Short: "Measure how fast commands without the AI assistant start",
+	Long: `Run commands that use neither the AI assistant nor the network several times
+and measure each run, from starting Aura until it exits. A first run of each
+command is not counted, it loads Aura into the file cache. Fails if the
+median of a command exceeds the budget, so it can guard releases in CI.
+
+The commands run as you would run them, with your settings and database, in
+offline mode and without the pager.
+
+Examples:
+  aura bench startup
+  aura bench startup --runs 50 --budget 20ms`,
+	Args: cobra.NoArgs,
+	RunE: runBenchStartup,
+}
+
+var (
+	benchRuns   int
+	benchBudget time.Duration
+)
+
+// benchStartupCommands are the commands aura bench startup measures.
+var benchStartupCommands = [][]string{
+	{"--version"},
+	{"--help"},
+	{"config", "env"},
+	{"bookmark", "list"},
+	{"alias", "list"},
+	{"note", "list"},
+	{"policy", "show"},
+}
+
+func runBenchStartup(cmd *cobra.Command, args []string) error {
+	if benchRuns < 1 {
+		return failure.New(failure.UserInput, "--runs must be at least 1")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the aura binary: %w", err)
+	}
+
+	fmt.Printf("Startup of %d %s per command, budget %s for the median\n\n", benchRuns, plural(int64(benchRuns), "run", "runs"), benchBudget)
+	fmt.Printf("  %-22s %9s %9s\n", "Command", "Median", "p95")
+	over := 0
+	for _, command := range benchStartupCommands {
+		name := "aura " + strings.Join(command, " ")
+		durations := make([]time.Duration, 0, benchRuns)
+		for i := 0; i <= benchRuns; i++ {
+			took, err := timeCommand(executable, command)
+			if err != nil {
+				return fmt.Errorf("'%s' failed: %w", name, err)
+			}
+			if i > 0 {
+				durations = append(durations, took)
+			}
+		}
+
+		median := percentile(durations, 50)
+		status := theme.Paint(theme.Success, "✓")
+		if median > benchBudget {
+			status = theme.Paint(theme.Error, "✗")
+			over++
+		}
+		fmt.Printf("  %-22s %9s %9s  %s\n", name, formatMillis(median), formatMillis(percentile(durations, 95)), status)
+	}
+
+	if over > 0 {
+		return failure.New(failure.General, "%d of %d commands start slower than %s", over, len(benchStartupCommands), benchBudget)
+	}
+	fmt.Println(theme.Paint(theme.Success, "\n✓ All commands start within the budget"))
+	return nil
+}
+
+// timeCommand runs the aura binary at executable with args and returns how
+// long it took.
+func timeCommand(executable string, args []string) (time.Duration, error) {
+	run := exec.Command(executable, args...)
+	run.Env = append(os.Environ(), envvar.Offline.Name+"=1", envvar.Pager.Name+"=cat")
+	started := time.Now()
+	err := run.Run()
+	return time.Since(started), err
+}
+
+// percentile returns the p-th percentile of durations, by the nearest rank.
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// formatMillis formats d in milliseconds, such as 7.3ms.
+func formatMillis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
+
+func init() {
+	benchStartupCmd.Flags().IntVarP(&benchRuns, "runs", "n", 20, "Number of runs per command")
+	benchStartupCmd.Flags().DurationVar(&benchBudget, "budget", 30*time.Millisecond, "Longest median startup time allowed")
+	benchCmd.AddCommand(benchStartupCmd)
+	rootCmd.AddCommand(benchCmd)
+}
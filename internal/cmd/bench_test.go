@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var durations []time.Duration
+	for _, ms := range []int{9, 3, 7, 1, 5, 2, 8, 4, 10, 6} {
+		durations = append(durations, time.Duration(ms)*time.Millisecond)
+	}
+	for p, want := range map[int]time.Duration{50: 5 * time.Millisecond, 95: 10 * time.Millisecond, 10: time.Millisecond, 0: time.Millisecond} {
+		if got := percentile(durations, p); got != want {
+			t.Errorf("percentile(%d) = %s, want %s", p, got, want)
+		}
+	}
+	if durations[0] != 9*time.Millisecond {
+		t.Error("percentile() sorted the durations in place")
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil) = %s", got)
+	}
+}
+
+func TestFormatMillis(t *testing.T) {
+	if got := formatMillis(7340 * time.Microsecond); got != "7.3ms" {
+		t.Errorf("formatMillis() = %s, want 7.3ms", got)
+	}
+}
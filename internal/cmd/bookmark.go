@@ -8,7 +8,11 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/timfewi/aura-cli-go/internal/config"
 	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+	"github.com/timfewi/aura-cli-go/internal/remote"
 )
 
 var bookmarkCmd = &cobra.Command{
@@ -25,7 +29,12 @@ var bookmarkAddCmd = &cobra.Command{
 Examples:
   aura bookmark add notes ~/Documents/notes
   aura bookmark add proj .                    # Bookmark current directory
-  aura bookmark add this as notes             # Natural language syntax`,
+  aura bookmark add this as notes             # Natural language syntax
+  aura bookmark add the parent folder as infra
+  aura bookmark add logs s3://my-bucket/logs  # Cloud storage (s3, gs, az)
+
+Phrases the intent parser doesn't understand are interpreted by the AI
+assistant, after confirmation.`,
 	RunE: runBookmarkAdd,
 }
 
@@ -37,41 +46,101 @@ var bookmarkListCmd = &cobra.Command{
 }
 
 var bookmarkRemoveCmd = &cobra.Command{
-	Use:   "remove [alias]",
+	Use:   "remove <alias|phrase>",
 	Short: "Remove a bookmark",
-	Long:  `Remove a bookmark by its alias.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runBookmarkRemove,
+	Long: `Remove a bookmark by its alias, or the bookmarks a phrase selects.
+
+Examples:
+  aura bookmark remove notes
+  aura bookmark remove api and web
+  aura bookmark remove everything tagged old
+  aura bookmark remove all under ~/archive
+  aura bookmark remove those that no longer exist
+
+Removing several bookmarks asks for confirmation, or needs --yes without a
+terminal. Phrases the intent parser doesn't understand are interpreted by the
+AI assistant.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBookmarkRemove,
+}
+
+var bookmarkSetCmd = &cobra.Command{
+	Use:   "set [alias] [key] [value]",
+	Short: "Set bookmark metadata",
+	Long: `Set metadata of a bookmark. Supported keys:
+
+  tmux      Command run in new tmux sessions created by 'aura tmux'
+  on_enter  Command the shell integration runs after 'aura go' enters the
+            bookmark, e.g. "nvm use". You are asked to approve it once.
+  tags      Comma separated tags, e.g. "work,old", to select bookmarks with
+            'aura bookmark remove everything tagged old'
+
+Examples:
+  aura bookmark set api tmux "make dev"
+  aura bookmark set web on_enter "nvm use"
+  aura bookmark set legacy tags old`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: runBookmarkSet,
 }
 
+var bookmarkUnsetCmd = &cobra.Command{
+	Use:   "unset [alias] [key]",
+	Short: "Remove bookmark metadata",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runBookmarkUnset,
+}
+
+// bookmarkMetadataKeys lists the metadata keys bookmarks can carry.
+var bookmarkMetadataKeys = []string{"tmux", "on_enter", "tags"}
+
+// bookmarkYes confirms changes to several bookmarks without asking.
+var bookmarkYes bool
+
 func runBookmarkAdd(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("alias is required")
+		return failure.New(failure.UserInput, "alias is required")
 	}
 
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
 	var alias, path string
 
-	// Handle natural language syntax: "aura bookmark add this as notes"
-	if len(args) >= 3 && args[0] == "this" && args[1] == "as" {
-		alias = args[2]
-		var err error
-		path, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
-		}
+	// Handle natural language syntax: "aura bookmark add the parent folder as infra"
+	if phraseAlias, phrasePath, ok := parseBookmarkAddPhrase(args, cwd); ok {
+		alias, path = phraseAlias, phrasePath
 	} else if len(args) == 1 {
 		// If only alias provided, use current directory
-		alias = args[0]
-		var err error
-		path, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
-		}
-	} else if len(args) >= 2 {
+		alias, path = args[0], cwd
+	} else {
 		alias = args[0]
 		path = strings.Join(args[1:], " ")
-	} else {
-		return fmt.Errorf("invalid arguments")
+
+		// Let the AI assistant interpret phrases the parser didn't understand
+		if _, err := os.Stat(config.ExpandHome(path)); err != nil && len(args) > 2 {
+			intent, err := interpretBookmarkPhrase(database, "add", args, cwd)
+			if err != nil {
+				return err
+			}
+			fmt.Println(i18n.T("bookmark.add_intent", intent.Alias, intent.Path))
+			if intent.Alias == "" || intent.Path == "" || !config.DryRun() && !confirmChange("Add this bookmark", bookmarkYes) {
+				return nil
+			}
+			alias, path = intent.Alias, intent.Path
+		}
+	}
+	path = config.ExpandHome(path)
+	if location, ok := remote.Parse(path); ok {
+		// Cloud storage is listed by 'aura go', not checked here
+		return saveBookmark(database, alias, location.URI())
 	}
 
 	// Convert to absolute path
@@ -84,7 +153,7 @@ func runBookmarkAdd(cmd *cobra.Command, args []string) error {
 	stat, err := os.Stat(absPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("path '%s' does not exist", absPath)
+			return failure.New(failure.NotFound, "path '%s' does not exist", absPath)
 		}
 		return fmt.Errorf("failed to check path: %w", err)
 	}
@@ -92,34 +161,44 @@ func runBookmarkAdd(cmd *cobra.Command, args []string) error {
 	if !stat.IsDir() {
 		return fmt.Errorf("'%s' is not a directory", absPath)
 	}
+	return saveBookmark(database, alias, absPath)
+}
 
-	database, err := db.New()
-	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
-	}
-	defer database.Close()
-
+// saveBookmark adds the bookmark alias for absPath, or points the existing
+// bookmark alias to it.
+func saveBookmark(database *db.DB, alias, absPath string) error {
 	// Check if bookmark already exists
 	existing, err := database.GetBookmark(alias)
 	if err != nil {
 		return fmt.Errorf("database error: %w", err)
 	}
 
+	if existing != nil && skipChange("point bookmark '%s' from %s to %s: bookmarks row, entry in operations for 'aura undo'", alias, existing.Path, absPath) {
+		return nil
+	}
+	if existing == nil && skipChange("add bookmark '%s' -> %s: bookmarks row, entry in operations for 'aura undo'", alias, absPath) {
+		return nil
+	}
+
 	if existing != nil {
-		fmt.Printf("Bookmark '%s' already exists, pointing to: %s\n", alias, existing.Path)
-		fmt.Printf("Updating to point to: %s\n", absPath)
+		fmt.Println(i18n.T("bookmark.exists", alias, existing.Path))
+		fmt.Println(i18n.T("bookmark.updating", absPath))
 
-		// Remove old bookmark and add new one
-		if err := database.RemoveBookmark(alias); err != nil {
+		// Keep the metadata such as startup commands of the bookmark
+		if err := database.UpdateBookmarkPath(alias, absPath); err != nil {
 			return fmt.Errorf("failed to update bookmark: %w", err)
 		}
+		recordOperation(database, operationBookmarkAdd, fmt.Sprintf("Point bookmark '%s' to %s", alias, absPath),
+			bookmarkAddOperation{Alias: alias, Path: absPath, PreviousPath: existing.Path})
+	} else {
+		if err := database.AddBookmark(alias, absPath); err != nil {
+			return fmt.Errorf("failed to add bookmark: %w", err)
+		}
+		recordOperation(database, operationBookmarkAdd, fmt.Sprintf("Add bookmark '%s'", alias),
+			bookmarkAddOperation{Alias: alias, Path: absPath})
 	}
 
-	if err := database.AddBookmark(alias, absPath); err != nil {
-		return fmt.Errorf("failed to add bookmark: %w", err)
-	}
-
-	fmt.Printf("Bookmark '%s' added for: %s\n", alias, absPath)
+	fmt.Println(i18n.T("bookmark.added", alias, absPath))
 	return nil
 }
 
@@ -136,20 +215,168 @@ func runBookmarkList(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(bookmarks) == 0 {
-		fmt.Println("No bookmarks found. Add one with: aura bookmark add <alias> <path>")
+		fmt.Println(i18n.T("bookmark.none"))
 		return nil
 	}
 
-	fmt.Println("Saved bookmarks:")
+	fmt.Println(i18n.T("bookmark.saved"))
 	for _, bookmark := range bookmarks {
 		fmt.Printf("  %s -> %s\n", bookmark.Alias, bookmark.Path)
+
+		metadata, err := database.GetBookmarkMetadata(bookmark.Alias)
+		if err != nil {
+			return fmt.Errorf("failed to get bookmark metadata: %w", err)
+		}
+		for _, key := range bookmarkMetadataKeys {
+			if value, ok := metadata[key]; ok {
+				fmt.Printf("      %s: %s\n", key, value)
+			}
+		}
+		if count, err := database.CountNotes(bookmark.Path); err == nil && count > 0 {
+			fmt.Printf("      notes: %d, see 'aura note list -b %s'\n", count, bookmark.Alias)
+		}
 	}
 
 	return nil
 }
 
 func runBookmarkRemove(cmd *cobra.Command, args []string) error {
-	alias := args[0]
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	if len(args) == 1 {
+		if existing, err := database.GetBookmark(args[0]); err != nil || existing != nil || !strings.Contains(args[0], " ") {
+			return removeBookmarks(database, []string{args[0]})
+		}
+		args = strings.Fields(args[0])
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	aliases := make(map[string]string)
+	for _, bookmark := range bookmarks {
+		aliases[bookmark.Alias] = bookmark.Path
+	}
+
+	selector, ok := parseBookmarkRemovePhrase(args, cwd, aliases)
+	if !ok {
+		// Let the AI assistant interpret phrases the parser didn't understand
+		intent, err := interpretBookmarkPhrase(database, "remove", args, cwd)
+		if err != nil {
+			return err
+		}
+		selector = bookmarkSelector{Aliases: intent.Aliases}
+	}
+
+	selected, err := selectBookmarks(database, selector)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Println(i18n.T("bookmark.no_match", strings.Join(args, " ")))
+		return nil
+	}
+
+	fmt.Println(i18n.T("bookmark.to_remove"))
+	var selectedAliases []string
+	for _, bookmark := range selected {
+		fmt.Printf("  %s -> %s\n", bookmark.Alias, bookmark.Path)
+		selectedAliases = append(selectedAliases, bookmark.Alias)
+	}
+	if !config.DryRun() && !confirmChange(i18n.T("bookmark.confirm_remove", len(selected)), bookmarkYes) {
+		return nil
+	}
+
+	return removeBookmarks(database, selectedAliases)
+}
+
+// removeBookmarks removes the bookmarks with the given aliases and records
+// them with their metadata for 'aura undo'.
+func removeBookmarks(database *db.DB, aliases []string) error {
+	var removed []removedBookmark
+	defer func() {
+		if len(removed) == 0 {
+			return
+		}
+		description := fmt.Sprintf("Remove bookmark '%s'", removed[0].Alias)
+		if len(removed) > 1 {
+			description = fmt.Sprintf("Remove %d bookmarks", len(removed))
+		}
+		recordOperation(database, operationBookmarkRemove, description, bookmarkRemoveOperation{Bookmarks: removed})
+	}()
+
+	for _, alias := range aliases {
+		bookmark, err := database.GetBookmark(alias)
+		if err != nil {
+			return fmt.Errorf("database error: %w", err)
+		}
+		metadata, err := database.GetBookmarkMetadata(alias)
+		if err != nil {
+			return fmt.Errorf("failed to get bookmark metadata: %w", err)
+		}
+
+		if config.DryRun() {
+			if bookmark == nil {
+				return failure.New(failure.NotFound, "bookmark '%s' not found", alias)
+			}
+			skipChange("remove bookmark '%s' -> %s: bookmarks row, %d bookmark_metadata %s and its usage, entry in operations for 'aura undo'",
+				alias, bookmark.Path, len(metadata), plural(int64(len(metadata)), "row", "rows"))
+			continue
+		}
+		if err := database.RemoveBookmark(alias); err != nil {
+			return fmt.Errorf("failed to remove bookmark: %w", err)
+		}
+		removed = append(removed, removedBookmark{Alias: alias, Path: bookmark.Path, Metadata: metadata})
+		fmt.Println(i18n.T("bookmark.removed", alias))
+	}
+	return nil
+}
+
+func runBookmarkSet(cmd *cobra.Command, args []string) error {
+	alias, key, value := args[0], args[1], strings.Join(args[2:], " ")
+	if err := validateBookmarkMetadataKey(key); err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	if config.DryRun() {
+		bookmark, err := database.GetBookmark(alias)
+		if err != nil {
+			return err
+		}
+		if bookmark == nil {
+			return fmt.Errorf("bookmark '%s' not found", alias)
+		}
+		skipChange("set %s of bookmark '%s' to '%s': bookmark_metadata row", key, alias, value)
+		return nil
+	}
+	if err := database.SetBookmarkMetadata(alias, key, value); err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("bookmark.meta_set", key, alias, value))
+	return nil
+}
+
+func runBookmarkUnset(cmd *cobra.Command, args []string) error {
+	alias, key := args[0], args[1]
+	if err := validateBookmarkMetadataKey(key); err != nil {
+		return err
+	}
 
 	database, err := db.New()
 	if err != nil {
@@ -157,17 +384,34 @@ func runBookmarkRemove(cmd *cobra.Command, args []string) error {
 	}
 	defer database.Close()
 
-	if err := database.RemoveBookmark(alias); err != nil {
-		return fmt.Errorf("failed to remove bookmark: %w", err)
+	if skipChange("remove %s of bookmark '%s': bookmark_metadata row", key, alias) {
+		return nil
+	}
+	if err := database.UnsetBookmarkMetadata(alias, key); err != nil {
+		return err
 	}
 
-	fmt.Printf("Bookmark '%s' removed\n", alias)
+	fmt.Println(i18n.T("bookmark.meta_removed", key, alias))
 	return nil
 }
 
+func validateBookmarkMetadataKey(key string) error {
+	for _, known := range bookmarkMetadataKeys {
+		if key == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key '%s', supported keys: %s", key, strings.Join(bookmarkMetadataKeys, ", "))
+}
+
 func init() {
+	bookmarkAddCmd.Flags().BoolVarP(&bookmarkYes, "yes", "y", false, "Apply interpreted phrases without asking")
+	bookmarkRemoveCmd.Flags().BoolVarP(&bookmarkYes, "yes", "y", false, "Remove several bookmarks without asking")
+
 	bookmarkCmd.AddCommand(bookmarkAddCmd)
 	bookmarkCmd.AddCommand(bookmarkListCmd)
 	bookmarkCmd.AddCommand(bookmarkRemoveCmd)
+	bookmarkCmd.AddCommand(bookmarkSetCmd)
+	bookmarkCmd.AddCommand(bookmarkUnsetCmd)
 	rootCmd.AddCommand(bookmarkCmd)
 }
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/remote"
+)
+
+// bookmarkSelector selects the bookmarks of a phrase such as "everything
+// tagged old".
+type bookmarkSelector struct {
+	Tag     string
+	Under   string
+	Missing bool
+	Aliases []string
+}
+
+// parseBookmarkLocation resolves a phrase such as "the parent folder" or
+// "~/code" to a directory.
+func parseBookmarkLocation(phrase, cwd string) (string, bool) {
+	phrase = strings.TrimSpace(phrase)
+	normalized := strings.ToLower(phrase)
+	normalized = strings.TrimPrefix(normalized, "the ")
+	for _, suffix := range []string{" folder", " directory", " dir"} {
+		normalized = strings.TrimSuffix(normalized, suffix)
+	}
+
+	switch normalized {
+	case "this", "here", "current", "this one", "cwd", ".":
+		return cwd, true
+	case "parent", "..":
+		return filepath.Dir(cwd), true
+	case "grandparent":
+		return filepath.Dir(filepath.Dir(cwd)), true
+	case "home", "~":
+		home, err := os.UserHomeDir()
+		return home, err == nil
+	}
+
+	if strings.ContainsAny(phrase, `/\~`) || phrase == ".." {
+		path := config.ExpandHome(phrase)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cwd, path)
+		}
+		return filepath.Clean(path), true
+	}
+	return "", false
+}
+
+// parseBookmarkAddPhrase parses "<location> as <alias>".
+func parseBookmarkAddPhrase(args []string, cwd string) (alias, path string, ok bool) {
+	for i := len(args) - 2; i >= 1; i-- {
+		if strings.ToLower(args[i]) != "as" {
+			continue
+		}
+		if i != len(args)-2 {
+			return "", "", false
+		}
+		path, ok := parseBookmarkLocation(strings.Join(args[:i], " "), cwd)
+		return args[i+1], path, ok
+	}
+	return "", "", false
+}
+
+// parseBookmarkRemovePhrase parses phrases such as "everything tagged old",
+// "all under ~/old" or "those that no longer exist".
+func parseBookmarkRemovePhrase(args []string, cwd string, aliases map[string]string) (bookmarkSelector, bool) {
+	original := strings.Join(args, " ")
+	phrase := strings.ToLower(original)
+	for _, prefix := range []string{"everything ", "all bookmarks ", "all the bookmarks ", "every bookmark ", "all ", "bookmarks ", "those ", "the ones "} {
+		if strings.HasPrefix(phrase, prefix) {
+			phrase = strings.TrimPrefix(phrase, prefix)
+			break
+		}
+	}
+	phrase = strings.TrimPrefix(phrase, "that are ")
+
+	for _, prefix := range []string{"tagged ", "with tag ", "with the tag "} {
+		if tag, ok := strings.CutPrefix(phrase, prefix); ok && tag != "" && !strings.Contains(tag, " ") {
+			return bookmarkSelector{Tag: tag}, true
+		}
+	}
+
+	for _, prefix := range []string{"under ", "in ", "inside ", "below "} {
+		if location, ok := strings.CutPrefix(phrase, prefix); ok {
+			// Keep the case of the path
+			if len(location) <= len(original) {
+				location = original[len(original)-len(location):]
+			}
+			if path, ok := parseBookmarkLocation(location, cwd); ok {
+				return bookmarkSelector{Under: path}, true
+			}
+		}
+	}
+
+	switch phrase {
+	case "missing", "broken", "that no longer exist", "that don't exist", "that do not exist", "pointing nowhere":
+		return bookmarkSelector{Missing: true}, true
+	}
+
+	// A list of aliases: "api, web and docs"
+	var selected []string
+	for _, word := range strings.FieldsFunc(original, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if strings.EqualFold(word, "and") {
+			continue
+		}
+		if _, ok := aliases[word]; !ok {
+			return bookmarkSelector{}, false
+		}
+		selected = append(selected, word)
+	}
+	return bookmarkSelector{Aliases: selected}, len(selected) > 0
+}
+
+// selectBookmarks returns the bookmarks matching selector.
+func selectBookmarks(database *db.DB, selector bookmarkSelector) ([]*db.Bookmark, error) {
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	var selected []*db.Bookmark
+	for _, bookmark := range bookmarks {
+		match := false
+		switch {
+		case selector.Tag != "":
+			metadata, err := database.GetBookmarkMetadata(bookmark.Alias)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bookmark metadata: %w", err)
+			}
+			match = contains(bookmarkTags(metadata), selector.Tag)
+		case selector.Under != "":
+			rel, err := filepath.Rel(config.NormalizePath(selector.Under), bookmark.Path)
+			match = err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+		case selector.Missing:
+			_, err := os.Stat(bookmark.Path)
+			match = os.IsNotExist(err) && !remote.IsRemote(bookmark.Path)
+		default:
+			match = contains(selector.Aliases, bookmark.Alias)
+		}
+		if match {
+			selected = append(selected, bookmark)
+		}
+	}
+	return selected, nil
+}
+
+// bookmarkTags returns the tags in the metadata of a bookmark.
+func bookmarkTags(metadata map[string]string) []string {
+	var tags []string
+	for _, tag := range strings.Split(metadata["tags"], ",") {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// bookmarkIntent is a bookmark command interpreted by the AI assistant.
+type bookmarkIntent struct {
+	Action  string   `json:"action"`
+	Alias   string   `json:"alias"`
+	Path    string   `json:"path"`
+	Aliases []string `json:"aliases"`
+}
+
+// interpretBookmarkPhrase lets the AI assistant interpret a phrase the
+// intent parser did not understand.
+func interpretBookmarkPhrase(database *db.DB, action string, args []string, cwd string) (*bookmarkIntent, error) {
+	phrase := strings.Join(args, " ")
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("could not understand '%s'", phrase)
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return nil, fmt.Errorf("could not understand '%s' and the AI client is unavailable: %w", phrase, err)
+	}
+
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	paths := make(map[string]string)
+	for _, bookmark := range bookmarks {
+		paths[bookmark.Alias] = bookmark.Path
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+
+	response, err := client.BookmarkIntent(ctx, action, phrase, cwd, paths)
+	stopThinking()
+
+	if err != nil {
+		return nil, fmt.Errorf("AI request failed: %w", err)
+	}
+	printFallbackNote(client)
+
+	start, end := strings.Index(response, "{"), strings.LastIndex(response, "}")
+	intent := &bookmarkIntent{}
+	if start < 0 || end < start || json.Unmarshal([]byte(response[start:end+1]), intent) != nil {
+		return nil, fmt.Errorf("could not understand '%s'", phrase)
+	}
+	if intent.Action != action {
+		return nil, fmt.Errorf("could not understand '%s'", phrase)
+	}
+	return intent, nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseBookmarkLocation(t *testing.T) {
+	cwd := filepath.FromSlash("/work/aura/cli")
+
+	tests := []struct {
+		phrase   string
+		expected string
+		ok       bool
+	}{
+		{"this", cwd, true},
+		{"here", cwd, true},
+		{"the parent folder", filepath.FromSlash("/work/aura"), true},
+		{"parent directory", filepath.FromSlash("/work/aura"), true},
+		{"the grandparent dir", filepath.FromSlash("/work"), true},
+		{"..", filepath.FromSlash("/work/aura"), true},
+		{"docs/api", filepath.Join(cwd, "docs", "api"), true},
+		{"my project", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			path, ok := parseBookmarkLocation(tt.phrase, cwd)
+			if ok != tt.ok || path != tt.expected {
+				t.Errorf("parseBookmarkLocation(%q) = %q, %v, want %q, %v", tt.phrase, path, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseBookmarkAddPhrase(t *testing.T) {
+	cwd := filepath.FromSlash("/work/aura")
+
+	tests := []struct {
+		args  []string
+		alias string
+		path  string
+		ok    bool
+	}{
+		{[]string{"this", "as", "notes"}, "notes", cwd, true},
+		{[]string{"the", "parent", "folder", "as", "infra"}, "infra", filepath.FromSlash("/work"), true},
+		{[]string{"notes", "/tmp/notes"}, "", "", false},
+		{[]string{"my", "project", "as", "p"}, "p", "", false},
+		{[]string{"this", "as", "my", "notes"}, "", "", false},
+	}
+
+	for _, tt := range tests {
+		alias, path, ok := parseBookmarkAddPhrase(tt.args, cwd)
+		if ok != tt.ok || (ok && (alias != tt.alias || path != tt.path)) {
+			t.Errorf("parseBookmarkAddPhrase(%q) = %q, %q, %v, want %q, %q, %v", tt.args, alias, path, ok, tt.alias, tt.path, tt.ok)
+		}
+	}
+}
+
+func TestParseBookmarkRemovePhrase(t *testing.T) {
+	cwd := filepath.FromSlash("/work")
+	aliases := map[string]string{"api": "/work/api", "web": "/work/web"}
+
+	tests := []struct {
+		phrase   string
+		expected bookmarkSelector
+		ok       bool
+	}{
+		{"everything tagged old", bookmarkSelector{Tag: "old"}, true},
+		{"all bookmarks with tag Legacy", bookmarkSelector{Tag: "legacy"}, true},
+		{"all under ./Archive", bookmarkSelector{Under: filepath.Join(cwd, "Archive")}, true},
+		{"those that no longer exist", bookmarkSelector{Missing: true}, true},
+		{"api and web", bookmarkSelector{Aliases: []string{"api", "web"}}, true},
+		{"api, docs", bookmarkSelector{}, false},
+		{"the ones I never use", bookmarkSelector{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			selector, ok := parseBookmarkRemovePhrase(strings.Fields(tt.phrase), cwd, aliases)
+			if ok != tt.ok {
+				t.Fatalf("parseBookmarkRemovePhrase(%q) ok = %v, want %v", tt.phrase, ok, tt.ok)
+			}
+			if ok && (selector.Tag != tt.expected.Tag || selector.Under != tt.expected.Under ||
+				selector.Missing != tt.expected.Missing || len(selector.Aliases) != len(tt.expected.Aliases)) {
+				t.Errorf("parseBookmarkRemovePhrase(%q) = %+v, want %+v", tt.phrase, selector, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+)
+
+var bookmarkStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how often bookmarks are used",
+	Long: `Show how often each bookmark was used with 'aura go' and 'aura tmux' and
+when it was last used, the most used first. Bookmarks unused for --months
+months, counting from when they were added if never used, are suggested for
+removal. --clean removes them, asking whether to remove all of them or which.
+
+Examples:
+  aura bookmark stats
+  aura bookmark stats --months 3
+  aura bookmark stats --clean
+  aura bookmark stats --clean --yes      # Remove all unused bookmarks`,
+	Args: cobra.NoArgs,
+	RunE: runBookmarkStats,
+}
+
+var (
+	bookmarkStatsMonths int
+	bookmarkStatsClean  bool
+)
+
+func runBookmarkStats(cmd *cobra.Command, args []string) error {
+	if bookmarkStatsMonths < 1 {
+		return failure.New(failure.UserInput, "--months must be at least 1")
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	usage, err := database.ListBookmarkUsage()
+	if err != nil {
+		return fmt.Errorf("failed to list bookmark usage: %w", err)
+	}
+	if len(usage) == 0 {
+		fmt.Println(i18n.T("bookmark.none"))
+		return nil
+	}
+
+	fmt.Println(i18n.T("bookmark.usage"))
+	fmt.Printf("  %-16s %6s  %-10s  %s\n", "ALIAS", "HITS", "LAST USED", "PATH")
+	for _, entry := range usage {
+		fmt.Printf("  %-16s %6d  %-10s  %s\n", entry.Bookmark.Alias, entry.Hits, formatLastUsed(entry.LastUsed), entry.Bookmark.Path)
+	}
+	fmt.Println()
+
+	stale := staleBookmarks(usage, time.Now().AddDate(0, -bookmarkStatsMonths, 0))
+	if len(stale) == 0 {
+		fmt.Println(i18n.T("bookmark.no_stale", bookmarkStatsMonths))
+		return nil
+	}
+
+	var aliases []string
+	for _, entry := range stale {
+		aliases = append(aliases, entry.Bookmark.Alias)
+	}
+	fmt.Println(i18n.T("bookmark.stale", len(stale), bookmarkStatsMonths, strings.Join(aliases, ", ")))
+	if !bookmarkStatsClean {
+		fmt.Println(i18n.T("bookmark.stale_hint"))
+		return nil
+	}
+
+	selected, err := chooseStaleBookmarks(stale)
+	if err != nil || len(selected) == 0 {
+		return err
+	}
+	return removeBookmarks(database, selected)
+}
+
+// staleBookmarks returns the bookmarks last used, or added if never used,
+// before cutoff.
+func staleBookmarks(usage []db.BookmarkUsage, cutoff time.Time) []db.BookmarkUsage {
+	var stale []db.BookmarkUsage
+	for _, entry := range usage {
+		lastActive := entry.LastUsed
+		if lastActive.IsZero() {
+			lastActive = entry.Bookmark.CreatedAt
+		}
+		if lastActive.Before(cutoff) {
+			stale = append(stale, entry)
+		}
+	}
+	return stale
+}
+
+// chooseStaleBookmarks asks whether to remove all stale bookmarks, some of
+// them or none, and returns the aliases to remove.
+func chooseStaleBookmarks(stale []db.BookmarkUsage) ([]string, error) {
+	var all []string
+	for _, entry := range stale {
+		all = append(all, entry.Bookmark.Alias)
+	}
+	if bookmarkYes {
+		return all, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(i18n.T("confirm.needs_yes"))
+		return nil, nil
+	}
+
+	prompt := promptui.Select{
+		Label: i18n.T("bookmark.clean"),
+		Items: []string{i18n.T("bookmark.clean_all", len(stale)), i18n.T("bookmark.clean_choose"), i18n.T("bookmark.clean_keep")},
+	}
+	choice, _, err := prompt.Run()
+	if err != nil {
+		if errors.Is(err, promptui.ErrInterrupt) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("prompt failed: %w", err)
+	}
+
+	switch choice {
+	case 0:
+		return all, nil
+	case 1:
+		var selected []string
+		for _, entry := range stale {
+			label := i18n.T("bookmark.clean_one", entry.Bookmark.Alias, entry.Bookmark.Path, formatLastUsed(entry.LastUsed))
+			if confirmChange(label, false) {
+				selected = append(selected, entry.Bookmark.Alias)
+			}
+		}
+		return selected, nil
+	}
+	return nil, nil
+}
+
+// formatLastUsed formats when a bookmark was last used.
+func formatLastUsed(lastUsed time.Time) string {
+	if lastUsed.IsZero() {
+		return i18n.T("bookmark.never_used")
+	}
+	return lastUsed.Local().Format("2006-01-02")
+}
+
+// recordBookmarkUse counts a use of the bookmark for 'aura bookmark stats'.
+// Failures don't stop the command using the bookmark.
+func recordBookmarkUse(database *db.DB, alias string) {
+	if err := database.RecordBookmarkUse(alias); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record bookmark use: %v\n", err)
+	}
+}
+
+func init() {
+	bookmarkStatsCmd.Flags().IntVar(&bookmarkStatsMonths, "months", 6, "Suggest removing bookmarks unused for this many months")
+	bookmarkStatsCmd.Flags().BoolVar(&bookmarkStatsClean, "clean", false, "Remove bookmarks unused for --months months")
+	bookmarkStatsCmd.Flags().BoolVarP(&bookmarkYes, "yes", "y", false, "Remove all unused bookmarks without asking")
+	bookmarkCmd.AddCommand(bookmarkStatsCmd)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+func TestStaleBookmarks(t *testing.T) {
+	now := time.Now()
+	cutoff := now.AddDate(0, -6, 0)
+	usage := []db.BookmarkUsage{
+		{Bookmark: &db.Bookmark{Alias: "recent", CreatedAt: now.AddDate(-1, 0, 0)}, Hits: 3, LastUsed: now.AddDate(0, 0, -2)},
+		{Bookmark: &db.Bookmark{Alias: "old", CreatedAt: now.AddDate(-1, 0, 0)}, Hits: 9, LastUsed: now.AddDate(0, -7, 0)},
+		{Bookmark: &db.Bookmark{Alias: "new", CreatedAt: now.AddDate(0, 0, -1)}},
+		{Bookmark: &db.Bookmark{Alias: "forgotten", CreatedAt: now.AddDate(-2, 0, 0)}},
+	}
+
+	var got []string
+	for _, entry := range staleBookmarks(usage, cutoff) {
+		got = append(got, entry.Bookmark.Alias)
+	}
+	if len(got) != 2 || got[0] != "old" || got[1] != "forgotten" {
+		t.Errorf("staleBookmarks() = %v, want [old forgotten]", got)
+	}
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/assets"
+	"github.com/timfewi/aura-cli-go/internal/cheat"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var cheatCmd = &cobra.Command{
+	Use:   "cheat <tool> [query...]",
+	Short: "Show cheatsheets for command line tools",
+	Long: `Show the entries of the local cheatsheets of a tool matching the query. The
+AI assistant is only asked when no local entry matches.
+
+Cheatsheets are plain text files in the cheatsheets directory of the Aura
+config directory, one subdirectory per pack. Each entry is a '# description'
+line followed by the command.
+
+Examples:
+  aura cheat tar extract                 # Entries of the tar sheet about extracting
+  aura cheat git                         # The whole git sheet
+  aura cheat rsync resume --no-ai        # Only search local cheatsheets
+  aura cheat list                        # List available cheatsheets
+  aura cheat import ./sheets --pack team # Import a community pack`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCheat,
+}
+
+var cheatListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available cheatsheets",
+	Args:  cobra.NoArgs,
+	RunE:  runCheatList,
+}
+
+var cheatImportCmd = &cobra.Command{
+	Use:   "import <directory|file|url>",
+	Short: "Import cheatsheets as a pack",
+	Long: `Import cheatsheets from a directory, a single file or an http(s) URL into a
+pack. Importing into an existing pack replaces sheets with the same name.
+
+Examples:
+  aura cheat import ~/community-sheets --pack community
+  aura cheat import https://example.com/sheets/jq.txt --pack web`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCheatImport,
+}
+
+var (
+	cheatNoAI       bool
+	cheatImportPack string
+)
+
+// cheatLibrary returns the cheatsheet library, installing the built-in
+// cheatsheets on first use.
+func cheatLibrary() (cheat.Library, error) {
+	library := cheat.Library{Dir: filepath.Join(config.DataDir, "cheatsheets")}
+	if err := library.InstallDefaults(assets.Cheatsheets); err != nil {
+		return library, fmt.Errorf("failed to install cheatsheets: %w", err)
+	}
+	return library, nil
+}
+
+func runCheat(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	terms := args[1:]
+
+	library, err := cheatLibrary()
+	if err != nil {
+		return err
+	}
+
+	sheets, err := library.Find(tool)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, sheet := range sheets {
+		entries := sheet.Search(terms)
+		if len(entries) == 0 {
+			continue
+		}
+		found = true
+
+		fmt.Printf("📖 %s (%s)\n\n", theme.Paint(theme.Heading, sheet.Tool), sheet.Pack)
+		for _, entry := range entries {
+			fmt.Printf("  %s\n", theme.Paint(theme.Muted, "# "+entry.Description))
+			for _, line := range strings.Split(entry.Command, "\n") {
+				fmt.Printf("  %s\n", theme.Paint(theme.Command, line))
+			}
+			fmt.Println()
+		}
+	}
+	if found {
+		return nil
+	}
+
+	if cheatNoAI {
+		if len(sheets) == 0 {
+			return fmt.Errorf("no cheatsheet for '%s'", tool)
+		}
+		return fmt.Errorf("no entry of the %s cheatsheet matches '%s'", tool, strings.Join(terms, " "))
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return fmt.Errorf("no local entry matches and the AI client is unavailable: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+
+	response, err := client.CheatSheet(ctx, tool, strings.Join(terms, " "))
+	stopThinking()
+
+	if err != nil {
+		return fmt.Errorf("AI request failed: %w", err)
+	}
+
+	fmt.Printf("\n🤖 %s (AI)\n\n%s\n", tool, strings.TrimSpace(response))
+	printFallbackNote(client)
+	return nil
+}
+
+func runCheatList(cmd *cobra.Command, args []string) error {
+	library, err := cheatLibrary()
+	if err != nil {
+		return err
+	}
+
+	tools, err := library.Tools()
+	if err != nil {
+		return err
+	}
+
+	if len(tools) == 0 {
+		fmt.Println("No cheatsheets found.")
+		fmt.Printf("Add cheatsheets to %s or import a pack with 'aura cheat import'.\n", library.Dir)
+		return nil
+	}
+
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("📖 Cheatsheets in %s:\n\n", library.Dir)
+	for _, name := range names {
+		fmt.Printf("  %-15s %s\n", name, strings.Join(tools[name], ", "))
+	}
+	return nil
+}
+
+func runCheatImport(cmd *cobra.Command, args []string) error {
+	library, err := cheatLibrary()
+	if err != nil {
+		return err
+	}
+
+	count, err := library.Import(args[0], cheatImportPack)
+	if err != nil {
+		return fmt.Errorf("failed to import cheatsheets: %w", err)
+	}
+
+	fmt.Printf("✓ Imported %d cheatsheet(s) into pack '%s'\n", count, cheatImportPack)
+	return nil
+}
+
+func init() {
+	cheatCmd.Flags().BoolVar(&cheatNoAI, "no-ai", false, "Only search local cheatsheets")
+	cheatImportCmd.Flags().StringVar(&cheatImportPack, "pack", "community", "Name of the pack to import into")
+
+	cheatCmd.AddCommand(cheatListCmd)
+	cheatCmd.AddCommand(cheatImportCmd)
+	rootCmd.AddCommand(cheatCmd)
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var checksumCmd = &cobra.Command{
+	Use:   "checksum <file> [expected]",
+	Short: "Compute or verify the checksum of a file",
+	Long: `Verify a file against an expected checksum, given as a digest or as a
+checksum file, or found next to the file: file.sha256, file.sha512 or
+file.md5, or a list such as SHA256SUMS in the same directory. The algorithm
+follows the checksum: sha256, sha512 or md5.
+
+Without a checksum to verify against, the sha256 checksum is printed in the
+format of sha256sum, so it can be saved as a checksum file.
+
+Examples:
+  aura checksum ubuntu.iso                     # Verify against ubuntu.iso.sha256 or SHA256SUMS
+  aura checksum tool.zip 9f86d081884c7d65...   # Verify against a digest
+  aura checksum tool.zip checksums.txt         # Verify against a checksum file
+  aura checksum backup.tar.gz -a sha512 > backup.tar.gz.sha512`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runChecksum,
+}
+
+var checksumAlgorithm string
+
+// checksumAlgorithms are the supported algorithms by name.
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+	"md5":    md5.New,
+}
+
+// digestAlgorithms maps the length of hex digests to their algorithm.
+var digestAlgorithms = map[int]string{64: "sha256", 128: "sha512", 32: "md5"}
+
+// checksumFiles are the names of checksum files listing several files, with
+// their algorithm. Files named after the verified file are looked for first.
+var checksumFiles = []struct{ name, algorithm string }{
+	{"SHA256SUMS", "sha256"},
+	{"sha256sums.txt", "sha256"},
+	{"SHA512SUMS", "sha512"},
+	{"sha512sums.txt", "sha512"},
+	{"MD5SUMS", "md5"},
+	{"md5sums.txt", "md5"},
+	{"checksums.txt", ""},
+	{"CHECKSUMS", ""},
+}
+
+// checksumEntry is the checksum of a file in a checksum file.
+type checksumEntry struct {
+	algorithm string
+	digest    string
+	name      string
+}
+
+// bsdChecksum matches the lines of BSD style checksum files, such as
+// "SHA256 (tool.zip) = 9f86...".
+var bsdChecksum = regexp.MustCompile(`^(SHA256|SHA512|MD5) \((.+)\) = ([0-9a-fA-F]+)$`)
+
+func runChecksum(cmd *cobra.Command, args []string) error {
+	file := config.ExpandHome(args[0])
+	info, err := os.Stat(file)
+	if err != nil {
+		return failure.New(failure.NotFound, "'%s' not found", args[0])
+	}
+	if info.IsDir() {
+		return failure.New(failure.UserInput, "'%s' is a directory, pack it with 'aura pack' first", args[0])
+	}
+	algorithm := strings.ToLower(checksumAlgorithm)
+	if _, ok := checksumAlgorithms[algorithm]; algorithm != "" && !ok {
+		return failure.New(failure.UserInput, "unknown algorithm '%s', use sha256, sha512 or md5", checksumAlgorithm)
+	}
+
+	var expected checksumEntry
+	source := ""
+	switch {
+	case len(args) > 1:
+		expected, source, err = expectedChecksum(file, args[1])
+		if err != nil {
+			return err
+		}
+	default:
+		// A checksum found of another algorithm than asked for is not
+		// what the user wants to verify against.
+		if entry, found, ok := findChecksum(file); ok && (algorithm == "" || algorithm == entry.algorithm) {
+			expected, source = entry, found
+		}
+	}
+	if expected.digest != "" {
+		if algorithm != "" && expected.algorithm != "" && algorithm != expected.algorithm {
+			return failure.New(failure.UserInput, "the expected checksum is %s, not %s", expected.algorithm, algorithm)
+		}
+		if expected.algorithm == "" {
+			return failure.New(failure.UserInput, "can't tell the algorithm of %s, use --algorithm", source)
+		}
+		algorithm = expected.algorithm
+	}
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+
+	digest, err := fileDigest(file, algorithm, info.Size())
+	if err != nil {
+		return err
+	}
+	if expected.digest == "" {
+		fmt.Printf("%s  %s\n", digest, filepath.Base(file))
+		return nil
+	}
+
+	name := filepath.Base(file)
+	if strings.EqualFold(digest, expected.digest) {
+		fmt.Printf("%s %s: %s matches %s\n", theme.Paint(theme.Success, "✓"), name, algorithm, source)
+		return nil
+	}
+	fmt.Printf("%s %s: %s does not match %s\n", theme.Paint(theme.Error, "✗"), name, algorithm, source)
+	fmt.Printf("  expected  %s\n", strings.ToLower(expected.digest))
+	fmt.Printf("  actual    %s\n", digest)
+	return fmt.Errorf("checksum mismatch")
+}
+
+// expectedChecksum returns the checksum of file that value, a digest or a
+// checksum file, gives, and describes where it came from.
+func expectedChecksum(file, value string) (checksumEntry, string, error) {
+	if path := config.ExpandHome(value); isRegularFile(path) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return checksumEntry{}, "", fmt.Errorf("failed to read %s: %w", value, err)
+		}
+		entries := parseChecksumFile(string(content), checksumFileAlgorithm(path))
+		entry, ok := matchChecksum(entries, filepath.Base(file), true)
+		if !ok {
+			return checksumEntry{}, "", failure.New(failure.NotFound, "%s has no checksum of %s", value, filepath.Base(file))
+		}
+		return entry, filepath.Base(path), nil
+	}
+
+	// Digests are often copied with a prefix naming the algorithm, as in
+	// sha256:9f86...
+	digest := value
+	algorithm := ""
+	if prefix, rest, ok := strings.Cut(value, ":"); ok {
+		if _, known := checksumAlgorithms[strings.ToLower(prefix)]; known {
+			algorithm, digest = strings.ToLower(prefix), rest
+		}
+	}
+	digest = strings.TrimSpace(digest)
+	if _, err := hex.DecodeString(digest); err != nil || digestAlgorithms[len(digest)] == "" {
+		return checksumEntry{}, "", failure.New(failure.UserInput, "'%s' is neither a checksum file nor a sha256, sha512 or md5 digest", value)
+	}
+	if algorithm == "" {
+		algorithm = digestAlgorithms[len(digest)]
+	}
+	return checksumEntry{algorithm: algorithm, digest: digest}, "the expected digest", nil
+}
+
+// findChecksum looks for a checksum of file in the checksum files next to
+// it and returns it with the name of the file it was found in.
+func findChecksum(file string) (checksumEntry, string, bool) {
+	dir, name := filepath.Split(file)
+	for _, ext := range []string{".sha256", ".sha256sum", ".sha512", ".sha512sum", ".md5"} {
+		path := file + ext
+		if content, err := os.ReadFile(path); err == nil {
+			entries := parseChecksumFile(string(content), checksumFileAlgorithm(path))
+			if entry, ok := matchChecksum(entries, name, true); ok {
+				return entry, filepath.Base(path), true
+			}
+		}
+	}
+	for _, list := range checksumFiles {
+		path := filepath.Join(dir, list.name)
+		if content, err := os.ReadFile(path); err == nil {
+			entries := parseChecksumFile(string(content), list.algorithm)
+			if entry, ok := matchChecksum(entries, name, false); ok {
+				return entry, list.name, true
+			}
+		}
+	}
+	return checksumEntry{}, "", false
+}
+
+// checksumFileAlgorithm returns the algorithm the name of a checksum file
+// suggests, or "" if it suggests none.
+func checksumFileAlgorithm(path string) string {
+	lower := strings.ToLower(filepath.Base(path))
+	for _, algorithm := range []string{"sha256", "sha512", "md5"} {
+		if strings.Contains(lower, algorithm) {
+			return algorithm
+		}
+	}
+	return ""
+}
+
+// parseChecksumFile parses the lines of a checksum file: the sha256sum
+// format, "<digest>  <name>" or "<digest> *<name>", the BSD one, or just
+// a digest. Digests without an algorithm get the one the file name
+// suggests, or else the one of their length.
+func parseChecksumFile(content, algorithm string) []checksumEntry {
+	var entries []checksumEntry
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := bsdChecksum.FindStringSubmatch(line); match != nil {
+			entries = append(entries, checksumEntry{algorithm: strings.ToLower(match[1]), name: match[2], digest: match[3]})
+			continue
+		}
+		digest, name, _ := strings.Cut(line, " ")
+		if _, err := hex.DecodeString(digest); err != nil {
+			continue
+		}
+		entry := checksumEntry{algorithm: algorithm, digest: digest, name: strings.TrimPrefix(strings.TrimSpace(name), "*")}
+		if entry.algorithm == "" || len(digest) != lengthOf(entry.algorithm) {
+			entry.algorithm = digestAlgorithms[len(digest)]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func lengthOf(algorithm string) int {
+	for length, name := range digestAlgorithms {
+		if name == algorithm {
+			return length
+		}
+	}
+	return 0
+}
+
+// matchChecksum returns the entry for the file called name. With single a
+// file with one entry matches whatever name it has, as checksum files named
+// after a download often outlive renaming it.
+func matchChecksum(entries []checksumEntry, name string, single bool) (checksumEntry, bool) {
+	for _, entry := range entries {
+		if path.Base(filepath.ToSlash(entry.name)) == name {
+			return entry, true
+		}
+	}
+	if single && len(entries) == 1 {
+		return entries[0], true
+	}
+	return checksumEntry{}, false
+}
+
+// fileDigest returns the hex digest of file with algorithm, showing a
+// spinner for large files.
+func fileDigest(file, algorithm string, size int64) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	if size > 64<<20 {
+		spinner := progress.NewSpinner(fmt.Sprintf("Computing the %s checksum of %s", algorithm, formatSize(size))).Start()
+		defer spinner.Stop()
+	}
+	h := checksumAlgorithms[algorithm]()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func init() {
+	checksumCmd.Flags().StringVarP(&checksumAlgorithm, "algorithm", "a", "", "Algorithm: sha256, sha512 or md5 (default from the checksum, else sha256)")
+	rootCmd.AddCommand(checksumCmd)
+}
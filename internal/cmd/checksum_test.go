@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestParseChecksumFile(t *testing.T) {
+	content := `# release checksums
+` + helloSHA256 + `  tool-linux.tar.gz
+` + strings.ToUpper(helloSHA256) + ` *dist/tool.zip
+SHA512 (tool.exe) = ` + strings.Repeat("ab", 64) + `
+5d41402abc4b2a76b9719d911017c592  tool.txt
+not a checksum line
+`
+	entries := parseChecksumFile(content, "")
+	want := []checksumEntry{
+		{algorithm: "sha256", digest: helloSHA256, name: "tool-linux.tar.gz"},
+		{algorithm: "sha256", digest: strings.ToUpper(helloSHA256), name: "dist/tool.zip"},
+		{algorithm: "sha512", digest: strings.Repeat("ab", 64), name: "tool.exe"},
+		{algorithm: "md5", digest: "5d41402abc4b2a76b9719d911017c592", name: "tool.txt"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("parseChecksumFile() = %+v, want %d entries", entries, len(want))
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	if entry, ok := matchChecksum(entries, "tool.zip", false); !ok || entry.name != "dist/tool.zip" {
+		t.Errorf("matchChecksum(tool.zip) = %+v, %v", entry, ok)
+	}
+	if _, ok := matchChecksum(entries, "other.zip", false); ok {
+		t.Error("matchChecksum() matched a file not listed")
+	}
+	bare := parseChecksumFile(helloSHA256+"\n", "sha256")
+	if entry, ok := matchChecksum(bare, "renamed.iso", true); !ok || entry.digest != helloSHA256 {
+		t.Errorf("matchChecksum() of a bare digest = %+v, %v", entry, ok)
+	}
+}
+
+func TestFindChecksum(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "hello.txt")
+	if err := os.WriteFile(file, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := findChecksum(file); ok {
+		t.Fatal("findChecksum() found a checksum in an empty directory")
+	}
+
+	sums := filepath.Join(dir, "SHA256SUMS")
+	if err := os.WriteFile(sums, []byte(helloSHA256+"  hello.txt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if entry, source, ok := findChecksum(file); !ok || source != "SHA256SUMS" || entry.algorithm != "sha256" {
+		t.Errorf("findChecksum() = %+v, %q, %v, want the entry of SHA256SUMS", entry, source, ok)
+	}
+
+	// A checksum file named after the file comes first.
+	md5 := "5d41402abc4b2a76b9719d911017c592"
+	if err := os.WriteFile(file+".md5", []byte(md5+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if entry, source, ok := findChecksum(file); !ok || source != "hello.txt.md5" || entry.digest != md5 {
+		t.Errorf("findChecksum() = %+v, %q, %v, want the entry of hello.txt.md5", entry, source, ok)
+	}
+	if digest, err := fileDigest(file, "md5", 5); err != nil || digest != md5 {
+		t.Errorf("fileDigest() = %q, %v, want %q", digest, err, md5)
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	tests := []struct {
+		value     string
+		algorithm string
+		wantErr   bool
+	}{
+		{value: helloSHA256, algorithm: "sha256"},
+		{value: "sha256:" + helloSHA256, algorithm: "sha256"},
+		{value: "MD5:5d41402abc4b2a76b9719d911017c592", algorithm: "md5"},
+		{value: "xyz", wantErr: true},
+		{value: "abcd", wantErr: true},
+	}
+	for _, tt := range tests {
+		entry, _, err := expectedChecksum("hello.txt", tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expectedChecksum(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if entry.algorithm != tt.algorithm {
+			t.Errorf("expectedChecksum(%q) algorithm = %q, want %q", tt.value, entry.algorithm, tt.algorithm)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/editor"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change settings",
+	Long: `Read and change the settings in config.yaml using dotted keys such as
+ai.model. Changes keep the comments in the file, are written atomically and
+are serialized with other Aura processes, so concurrent writes can't corrupt
+the file. Running aura ask sessions and aura watch pick up changes without a
+restart.
+
+Unknown settings and invalid values are reported with their line in the
+file when Aura starts, and invalid values are left at their defaults.
+
+Examples:
+  aura config get ai.model
+  aura config set ai.model gpt-4o-mini
+  aura config set confirm.policy never
+  aura config path
+  aura config doctor
+  aura config env`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the settings file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(config.GetSettingsFile())
+	},
+}
+
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the settings and show where their values come from",
+	Long: `Check the settings file for unknown settings and invalid values, then
+show the effective value of each setting and its source: a flag, an
+environment variable, the settings file, the team settings in the .aura.yaml
+of the repository or the default. Environment variables override the
+settings file, except for editor and locale, and the settings file
+overrides the team settings.
+
+aura config doctor fails if the settings file or the team settings have
+problems.
+
+Examples:
+  aura config doctor`,
+	Args: cobra.NoArgs,
+	RunE: runConfigDoctor,
+}
+
+var configEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "List the environment variables Aura reads",
+	Long: `List every environment variable Aura reads with its current value, what is
+used when it is not set, the part of Aura that reads it and what it does.
+Values of secrets such as API keys are hidden.
+
+Examples:
+  aura config env
+  aura config env --set`,
+	Args: cobra.NoArgs,
+	Run:  runConfigEnv,
+}
+
+var configEnvSet bool
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	value, ok, err := config.GetSetting(config.GetSettingsFile(), args[0])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return failure.New(failure.NotFound, "setting '%s' is not set", args[0])
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	if err := config.SetSetting(config.GetSettingsFile(), args[0], args[1]); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	fmt.Printf("✓ %s set to '%s'\n", args[0], args[1])
+	return nil
+}
+
+// effectiveSetting is a line of `aura config doctor`.
+type effectiveSetting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+func runConfigDoctor(cmd *cobra.Command, args []string) error {
+	path := config.GetSettingsFile()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("%-16s %s %s\n", "Settings file:", displayHomePath(path), theme.Paint(theme.Muted, "(not created yet)"))
+	} else {
+		fmt.Printf("%-16s %s\n", "Settings file:", displayHomePath(path))
+	}
+	if config.TeamSettingsFile != "" {
+		fmt.Printf("%-16s %s\n", "Team settings:", displayHomePath(config.TeamSettingsFile))
+	}
+	if _, err := os.Stat(config.PolicyFile); err == nil {
+		fmt.Printf("%-16s %s\n", "Policy:", displayHomePath(config.PolicyFile))
+	}
+
+	for _, dir := range []struct{ name, path string }{
+		{"Data", config.DataDir},
+		{"Cache", config.CacheDir},
+		{"State", config.StateDir},
+	} {
+		fmt.Printf("%-16s %s\n", dir.name+" directory:", displayHomePath(dir.path))
+	}
+
+	problems := len(config.SettingsProblems) + len(config.TeamProblems) + len(config.PolicyProblems)
+	if problems == 0 {
+		fmt.Println(theme.Paint(theme.Success, "✓ No problems found"))
+	} else {
+		fmt.Println(theme.Paint(theme.Error, fmt.Sprintf("✗ %d %s, invalid values are left at their defaults:", problems, plural(int64(problems), "problem", "problems"))))
+		for _, problem := range config.SettingsProblems {
+			fmt.Printf("  %s  %s: %s\n", theme.Paint(theme.Muted, problem.Location(displayHomePath(path))), problem.Key, problem.Message)
+		}
+		for _, problem := range config.TeamProblems {
+			fmt.Printf("  %s  %s: %s\n", theme.Paint(theme.Muted, problem.Location(displayHomePath(config.TeamSettingsFile))), problem.Key, problem.Message)
+		}
+		for _, problem := range config.PolicyProblems {
+			fmt.Printf("  %s  %s: %s\n", theme.Paint(theme.Muted, problem.Location(displayHomePath(config.PolicyFile))), problem.Key, problem.Message)
+		}
+	}
+
+	fmt.Printf("\n%s\n", theme.Paint(theme.Heading, "Effective settings"))
+	settings := effectiveSettings()
+	keyWidth, valueWidth := 0, 0
+	for _, setting := range settings {
+		keyWidth = max(keyWidth, len(setting.Key))
+		valueWidth = max(valueWidth, len([]rune(setting.Value)))
+	}
+	for _, setting := range settings {
+		value := fmt.Sprintf("%-*s", valueWidth, setting.Value)
+		fmt.Printf("  %-*s  %s  %s\n", keyWidth, setting.Key, value, theme.Paint(theme.Muted, setting.Source))
+	}
+
+	if len(config.SettingsProblems) > 0 {
+		return failure.New(failure.Config, "%d invalid %s in %s", len(config.SettingsProblems), plural(int64(len(config.SettingsProblems)), "setting", "settings"), displayHomePath(path))
+	}
+	if len(config.TeamProblems) > 0 {
+		return failure.New(failure.Config, "%d invalid %s in %s", len(config.TeamProblems), plural(int64(len(config.TeamProblems)), "setting", "settings"), displayHomePath(config.TeamSettingsFile))
+	}
+	if len(config.PolicyProblems) > 0 {
+		return failure.New(failure.Config, "%d invalid %s in %s", len(config.PolicyProblems), plural(int64(len(config.PolicyProblems)), "setting", "settings"), displayHomePath(config.PolicyFile))
+	}
+	return nil
+}
+
+// effectiveSettings returns the settings in effect and where each comes from.
+func effectiveSettings() []effectiveSetting {
+	settings := config.UserSettings
+	none := func(value string) string {
+		if value == "" {
+			return "none"
+		}
+		return value
+	}
+
+	var fallback []string
+	for _, model := range settings.AI.Fallback {
+		fallback = append(fallback, model.Model)
+	}
+	fallbackValue := strings.Join(fallback, ", ")
+	if env := envvar.FallbackModels.Get(); env != "" {
+		fallbackValue = env
+	}
+
+	deterministicSource := settingSource(settings.AI.Deterministic, envvar.Deterministic.Name)
+	if deterministic {
+		deterministicSource = "flag --deterministic"
+	}
+
+	apiKey := "not set"
+	if envvar.APIKey.Get() != "" || envvar.OpenAIAPIKey.Get() != "" {
+		apiKey = "set"
+	}
+	apiURL := envvar.APIURL.Get()
+	if apiURL == "" {
+		apiURL = "https://api.openai.com/v1"
+	}
+	apiURLSource := settingSource(false, envvar.APIURL.Name)
+	if config.ActivePolicy.APIURL != "" {
+		apiURL, apiURLSource = config.ActivePolicy.APIURL, "policy"
+	}
+
+	notifyAfter := "off"
+	if after, ok := config.GetNotifyAfter(""); ok {
+		notifyAfter = shortDuration(after)
+	}
+
+	editorSource := settingSource(settings.Editor != "")
+	if settings.Editor == "" {
+		editorSource = settingSource(false, "VISUAL", "EDITOR")
+	}
+	if editorSource == "default" && editor.Resolve("") != "" {
+		editorSource = "installed"
+	}
+
+	themeName := settings.Theme
+	if themeName == "" {
+		themeName = theme.DefaultName
+	}
+	color, colorSource := "on", "default"
+	switch {
+	case noColor:
+		color, colorSource = "off", "flag --no-color"
+	case os.Getenv("NO_COLOR") != "":
+		color, colorSource = "off", "env NO_COLOR"
+	case !theme.ColorEnabled(false):
+		color, colorSource = "off", "not a terminal"
+	}
+
+	localeSource := settingSource(settings.Locale != "")
+	if settings.Locale == "" {
+		localeSource = settingSource(false, "LC_ALL", "LC_MESSAGES", "LANG")
+	}
+
+	privacy := config.GetPrivacy()
+	var keptBack []string
+	for _, kind := range []struct {
+		name string
+		sent bool
+	}{
+		{"paths", privacy.Paths},
+		{"file_contents", privacy.FileContents},
+		{"env_names", privacy.EnvNames},
+		{"env_values", privacy.EnvValues},
+		{"git_remotes", privacy.GitRemotes},
+	} {
+		if !kind.sent {
+			keptBack = append(keptBack, kind.name)
+		}
+	}
+	privacyValue := "all context sent"
+	if len(keptBack) > 0 {
+		privacyValue = "keeps back " + strings.Join(keptBack, ", ")
+	}
+
+	allowedModels := "all"
+	if len(config.Team.AI.AllowedModels) > 0 {
+		allowedModels = strings.Join(config.Team.AI.AllowedModels, ", ")
+	}
+
+	databaseSource := "default"
+	switch {
+	case envvar.DBPath.Get() != "":
+		databaseSource = "env AURA_DB_PATH"
+	case config.IsDockerMode():
+		databaseSource = "docker container aura-db"
+	}
+
+	return []effectiveSetting{
+		{"ai.model", ai.ConfiguredModel(), teamSource(settingSource(settings.AI.Model != "", envvar.Model.Name), config.Team.AI.Model != "")},
+		{"ai.allowed_models", allowedModels, teamSource("default", len(config.Team.AI.AllowedModels) > 0)},
+		{"ai.fallback", none(fallbackValue), settingSource(len(settings.AI.Fallback) > 0, envvar.FallbackModels.Name)},
+		{"ai.embeddings.model", ai.EmbeddingModel(), settingSource(settings.AI.Embeddings.Model != "", envvar.EmbeddingModel.Name)},
+		{"ai.language", none(ai.AnswerLanguage()), settingSource(settings.AI.Language != "", envvar.Language.Name)},
+		{"ai.commit_language", ai.CommitLanguage(), settingSource(settings.AI.CommitLanguage != "", envvar.CommitLanguage.Name)},
+		{"ai.deterministic", strconv.FormatBool(ai.Deterministic()), deterministicSource},
+		{"api url", apiURL, apiURLSource},
+		{"api key", apiKey, settingSource(false, envvar.APIKey.Name, envvar.OpenAIAPIKey.Name)},
+		{"navigation.project_roots", none(strings.Join(config.GetProjectRoots(), ", ")), settingSource(len(settings.Navigation.ProjectRoots) > 0, envvar.ProjectRoots.Name)},
+		{"navigation.scan_depth", strconv.Itoa(config.GetScanDepth()), settingSource(settings.Navigation.ScanDepth > 0)},
+		{"navigation.refresh_interval", shortDuration(config.GetIndexRefreshInterval()), settingSource(settings.Navigation.RefreshInterval != "")},
+		{"git.commit_style", config.GetCommitStyle(), teamSource(settingSource(settings.Git.CommitStyle != ""), config.Team.Git.CommitStyle != "")},
+		{"project.host", config.GetProjectHost(), settingSource(settings.Project.Host != "")},
+		{"dotfiles.repo", displayHomePath(config.GetDotfilesRepo()), settingSource(settings.Dotfiles.Repo != "")},
+		{"notifications.after", notifyAfter, settingSource(settings.Notifications.Disabled || settings.Notifications.After != "")},
+		{"confirm.policy", config.GetConfirmPolicy(""), settingSource(settings.Confirm.Policy != "")},
+		{"privacy", privacyValue, settingSource(settings.Privacy != config.PrivacySettings{})},
+		{"editor", none(editor.Resolve("")), editorSource},
+		{"theme", themeName, settingSource(settings.Theme != "")},
+		{"color", color, colorSource},
+		{"locale", i18n.Locale(), localeSource},
+		{"database", displayHomePath(config.DatabasePath), databaseSource},
+	}
+}
+
+// settingSource names where a setting comes from: the first of the
+// environment variables envs that is set, the settings file if inFile, or
+// the default. It notes when a variable overrides the settings file.
+func settingSource(inFile bool, envs ...string) string {
+	for _, env := range envs {
+		if os.Getenv(env) == "" {
+			continue
+		}
+		if inFile {
+			return "env " + env + ", overrides file"
+		}
+		return "env " + env
+	}
+	if inFile {
+		return "file"
+	}
+	return "default"
+}
+
+// teamSource returns source, or "team" if it is the default and the team
+// settings set the setting, as inTeam tells.
+func teamSource(source string, inTeam bool) string {
+	if source == "default" && inTeam {
+		return "team"
+	}
+	return source
+}
+
+func runConfigEnv(cmd *cobra.Command, args []string) {
+	width := 0
+	for _, variable := range envvar.All() {
+		width = max(width, len(variable.Name))
+	}
+	for _, variable := range envvar.All() {
+		value := envValue(variable)
+		if configEnvSet && variable.Get() == "" {
+			continue
+		}
+		fmt.Printf("%s  %s  %s\n", theme.Paint(theme.Heading, fmt.Sprintf("%-*s", width, variable.Name)), value, theme.Paint(theme.Muted, "["+variable.Subsystem+"]"))
+		fmt.Printf("%-*s  %s\n", width, "", variable.Description)
+		fmt.Printf("%-*s  %s\n", width, "", theme.Paint(theme.Muted, "Default: "+variable.Default))
+	}
+}
+
+// envValue describes the value of variable, hiding secrets.
+func envValue(variable envvar.Var) string {
+	switch value := variable.Get(); {
+	case value == "":
+		return theme.Paint(theme.Muted, "not set")
+	case variable.Secret:
+		return theme.Paint(theme.Success, "set") + theme.Paint(theme.Muted, " (hidden)")
+	default:
+		return theme.Paint(theme.Success, value)
+	}
+}
+
+// reloadSettings applies changes to the settings file made since the last
+// call, for commands that keep running. Invalid changes are reported and the
+// previous settings kept.
+func reloadSettings(reloader *config.Reloader) {
+	changed, err := reloader.Reload()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: keeping the previous settings: %v\n", err)
+		return
+	}
+	if changed {
+		applySettings()
+		fmt.Println("ℹ️  Settings reloaded")
+	}
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configDoctorCmd)
+	configEnvCmd.Flags().BoolVar(&configEnvSet, "set", false, "Only variables that are set")
+	configCmd.AddCommand(configEnvCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+func TestSettingSource(t *testing.T) {
+	t.Setenv("AURA_MODEL", "")
+	t.Setenv("AURA_LANGUAGE", "de")
+
+	tests := []struct {
+		inFile bool
+		envs   []string
+		want   string
+	}{
+		{false, nil, "default"},
+		{true, nil, "file"},
+		{true, []string{"AURA_MODEL"}, "file"},
+		{false, []string{"AURA_MODEL", "AURA_LANGUAGE"}, "env AURA_LANGUAGE"},
+		{true, []string{"AURA_LANGUAGE"}, "env AURA_LANGUAGE, overrides file"},
+	}
+	for _, test := range tests {
+		if got := settingSource(test.inFile, test.envs...); got != test.want {
+			t.Errorf("settingSource(%v, %v) = %q, want %q", test.inFile, test.envs, got, test.want)
+		}
+	}
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	originalSettings := config.UserSettings
+	defer func() { config.UserSettings = originalSettings }()
+	t.Setenv("AURA_MODEL", "gpt-4.1")
+	t.Setenv("AURA_EMBEDDING_MODEL", "")
+
+	config.UserSettings = config.Settings{
+		AI:         config.AISettings{Model: "gpt-4o", Embeddings: config.EmbeddingSettings{Model: "nomic-embed-text"}},
+		Navigation: config.NavigationSettings{ScanDepth: 5},
+	}
+	want := map[string]effectiveSetting{
+		"ai.model":              {"ai.model", "gpt-4.1", "env AURA_MODEL, overrides file"},
+		"ai.embeddings.model":   {"ai.embeddings.model", "nomic-embed-text", "file"},
+		"navigation.scan_depth": {"navigation.scan_depth", "5", "file"},
+		"confirm.policy":        {"confirm.policy", config.ConfirmDestructive, "default"},
+	}
+	for _, setting := range effectiveSettings() {
+		if w, ok := want[setting.Key]; ok && setting != w {
+			t.Errorf("effectiveSettings() %s = %+v, want %+v", setting.Key, setting, w)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// confirmChange asks whether to apply a change, unless yes is set. Without a
+// terminal it declines.
+func confirmChange(label string, yes bool) bool {
+	if yes {
+		return true
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(i18n.T("confirm.needs_yes"))
+		return false
+	}
+	prompt := promptui.Prompt{Label: label, IsConfirm: true}
+	_, err := prompt.Run()
+	return err == nil
+}
+
+// skipChange prints what a change would do, as described by format and
+// args, and reports true in dry-run mode, where the caller skips the change.
+func skipChange(format string, args ...any) bool {
+	if !config.DryRun() {
+		return false
+	}
+	fmt.Printf("🔍 Would %s\n", fmt.Sprintf(format, args...))
+	return true
+}
+
+// confirmOnTerminal asks whether to apply a change like confirmChange, on
+// the terminal rather than the standard input, for commands that read data
+// from it. Without a terminal it declines.
+func confirmOnTerminal(label string, yes bool) bool {
+	if yes {
+		return true
+	}
+	tty, err := os.OpenFile(platform.Current().TerminalDevice(), os.O_RDWR, 0)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("confirm.needs_yes"))
+		return false
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "%s [y/N]: ", label)
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// needsConfirmation reports whether command asks before it acts under its
+// confirmation policy. risky tells whether this use of it deletes data,
+// discards changes or acts on something the user hasn't reviewed, such as a
+// generated commit message.
+func needsConfirmation(command string, risky bool) bool {
+	switch config.GetConfirmPolicy(command) {
+	case config.ConfirmAlways:
+		return true
+	case config.ConfirmNever:
+		return false
+	}
+	return risky
+}
+
+// confirmAction asks whether command may act if its confirmation policy
+// requires it, unless yes is set.
+func confirmAction(command, label string, risky, yes bool) bool {
+	if !needsConfirmation(command, risky) {
+		return true
+	}
+	return confirmChange(label, yes)
+}
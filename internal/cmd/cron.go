@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/cron"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var cronCmd = &cobra.Command{
+	Use:   "cron <description>",
+	Short: "Write a cron expression from a description",
+	Long: `Write the cron expression of a schedule described in words with the AI
+assistant, along with its systemd OnCalendar equivalent.
+
+The expression is checked and read back in plain English, so you can tell
+it means what you asked for, with its next runs in local time.
+
+Use 'aura cron explain' for the reverse direction.
+
+Examples:
+  aura cron "every weekday at 7am"
+  aura cron "every 15 minutes during office hours"
+  aura cron "at midnight on the first of every month"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCron,
+}
+
+var cronExplainCmd = &cobra.Command{
+	Use:   "explain <expression>",
+	Short: "Explain a cron expression",
+	Long: `Explain a cron expression in plain English, with its systemd OnCalendar
+equivalent and its next runs in local time. The expression is read by Aura
+itself, no AI assistant is involved.
+
+Names such as MON or JAN, ranges, lists, steps and macros such as @daily are
+understood.
+
+Examples:
+  aura cron explain "0 3 * * 1"
+  aura cron explain "*/10 9-17 * * MON-FRI"
+  aura cron explain @weekly`,
+	Args: cobra.ExactArgs(1),
+	RunE: paged(runCronExplain),
+}
+
+// cronRuns is the number of next runs listed.
+const cronRuns = 5
+
+func runCron(cmd *cobra.Command, args []string) error {
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	schedule, err := writeCron(client, args[0])
+	if err != nil {
+		return err
+	}
+	printSchedule(os.Stdout, schedule, time.Now())
+	return nil
+}
+
+func runCronExplain(cmd *cobra.Command, args []string) error {
+	schedule, err := cron.Parse(args[0])
+	if err != nil {
+		return err
+	}
+	printSchedule(os.Stdout, schedule, time.Now())
+	return nil
+}
+
+// writeCron asks the AI assistant for the cron expression of description
+// and parses it.
+func writeCron(client ai.Assistant, description string) (cron.Schedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	response, err := client.CronExpression(ctx, description)
+	stopThinking()
+
+	if err != nil {
+		return cron.Schedule{}, fmt.Errorf("failed to write the expression: %w", err)
+	}
+	expression := cronAnswer(response)
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return cron.Schedule{}, failure.New(failure.API, "the AI assistant returned an invalid expression '%s': %v", expression, err)
+	}
+	return schedule, nil
+}
+
+// cronAnswer returns the expression in the answer of the AI assistant,
+// without code fences, backticks or a crontab command after it.
+func cronAnswer(response string) string {
+	for _, line := range strings.Split(stripCodeFence(response), "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "`")
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			return strings.Fields(line)[0]
+		}
+		if fields := strings.Fields(line); len(fields) > 5 {
+			line = strings.Join(fields[:5], " ")
+		}
+		return line
+	}
+	return ""
+}
+
+// printSchedule prints the expression of schedule, its meaning, its systemd
+// equivalent and its next runs after now.
+func printSchedule(w io.Writer, schedule cron.Schedule, now time.Time) {
+	fmt.Fprintf(w, "%s  %s\n", theme.Paint(theme.Heading, "Cron:   "), theme.Paint(theme.Command, schedule.Expression))
+	fmt.Fprintf(w, "%s  %s\n", theme.Paint(theme.Heading, "Meaning:"), schedule.Describe())
+	fmt.Fprintf(w, "%s  %s\n", theme.Paint(theme.Heading, "systemd:"), theme.Paint(theme.Command, "OnCalendar="+schedule.OnCalendar()))
+	if schedule.BothDaysRestricted() {
+		fmt.Fprintln(w, "\nℹ️  cron runs when either the day of month or the day of week matches, systemd only when both do")
+	}
+
+	runs := schedule.Next(now, cronRuns)
+	if len(runs) == 0 {
+		fmt.Fprintln(w, "\n⚠️  The schedule never runs")
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", theme.Paint(theme.Heading, "Next runs:"))
+	for _, run := range runs {
+		fmt.Fprintf(w, "  %s\n", run.Format("Mon 2006-01-02 15:04"))
+	}
+}
+
+func init() {
+	cronCmd.AddCommand(cronExplainCmd)
+	rootCmd.AddCommand(cronCmd)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/cron"
+)
+
+func TestCronAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{name: "plain", response: "0 7 * * 1-5\n", want: "0 7 * * 1-5"},
+		{name: "fenced", response: "```cron\n0 7 * * 1-5\n```", want: "0 7 * * 1-5"},
+		{name: "backticks", response: "`*/15 * * * *`", want: "*/15 * * * *"},
+		{name: "crontab line", response: "0 3 * * 1 /usr/local/bin/backup", want: "0 3 * * 1"},
+		{name: "macro", response: "@daily", want: "@daily"},
+		{name: "empty", response: "\n", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cronAnswer(tt.response); got != tt.want {
+				t.Errorf("cronAnswer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCron(t *testing.T) {
+	client := &MockAIClient{response: "every second"}
+	if _, err := writeCron(client, "every second"); err == nil {
+		t.Error("writeCron() accepted an invalid expression")
+	}
+
+	client = &MockAIClient{response: "0 7 * * 1-5"}
+	schedule, err := writeCron(client, "every weekday at 7am")
+	if err != nil {
+		t.Fatalf("writeCron() error = %v", err)
+	}
+	if schedule.Expression != "0 7 * * 1-5" {
+		t.Errorf("writeCron() expression = %q", schedule.Expression)
+	}
+}
+
+func TestPrintSchedule(t *testing.T) {
+	schedule, err := cron.Parse("0 0 1 * 1")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var out bytes.Buffer
+	printSchedule(&out, schedule, time.Date(2026, time.October, 16, 8, 0, 0, 0, time.UTC))
+
+	for _, want := range []string{
+		"At 00:00 on the 1st of the month or on Monday",
+		"OnCalendar=Mon *-*-01 00:00:00",
+		"systemd only when both do",
+		"Mon 2026-10-19 00:00",
+		"Sun 2026-11-01 00:00",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("printSchedule() output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
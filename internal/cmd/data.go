@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/dataprofile"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Work with data files",
+	Long:  `Work with CSV, TSV, JSON and JSON Lines data files.`,
+}
+
+var dataSummarizeCmd = &cobra.Command{
+	Use:   "summarize [file]",
+	Short: "Profile a table of data",
+	Long: `Profile a table of data locally: its rows and columns, the type of each
+column, its nulls, distinct values and simple statistics such as the range
+and mean of numbers or dates.
+
+The table is read from the file, or from the standard input without one or
+with "-". CSV, TSV, JSON arrays of objects and JSON Lines are read; the
+format is told by the extension or else by the content, and the delimiter
+of CSV by its header line.
+
+With --insights the AI assistant points out what stands out. It only sees
+the profile as shown, never the rows, so the data stays private.
+
+Examples:
+  aura data summarize sales.csv
+  aura data summarize events.jsonl --insights
+  curl -s https://example.com/export | aura data summarize`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDataSummarize,
+}
+
+var dataInsights bool
+
+func runDataSummarize(cmd *cobra.Command, args []string) error {
+	profile, err := readProfile(args)
+	if err != nil {
+		return err
+	}
+	report := profile.String()
+	fmt.Print(report)
+
+	if !dataInsights {
+		return nil
+	}
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	insights, err := dataProfileInsights(client, report)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", insights)
+	return nil
+}
+
+// readProfile profiles the file named by args, or the standard input.
+func readProfile(args []string) (*dataprofile.Profile, error) {
+	if len(args) == 0 || args[0] == "-" {
+		if len(args) == 0 && term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, failure.New(failure.UserInput, "name a file or pipe the data into aura, such as: aura data summarize sales.csv")
+		}
+		return dataprofile.Read("stdin", os.Stdin)
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return nil, failure.Wrap(failure.NotFound, fmt.Errorf("failed to open %s: %w", args[0], err))
+	}
+	defer file.Close()
+	return dataprofile.Read(args[0], file)
+}
+
+// dataProfileInsights asks the AI assistant what stands out in a profile.
+func dataProfileInsights(client ai.Assistant, profile string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	insights, err := client.DataInsights(ctx, profile)
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get insights: %w", err)
+	}
+	return insights, nil
+}
+
+func init() {
+	dataSummarizeCmd.Flags().BoolVar(&dataInsights, "insights", false, "Let the AI assistant point out what stands out in the profile")
+
+	dataCmd.AddCommand(dataSummarizeCmd)
+	rootCmd.AddCommand(dataCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadProfile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sales.csv")
+	if err := os.WriteFile(file, []byte("region,amount\nnorth,10\nsouth,\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := readProfile([]string{file})
+	if err != nil {
+		t.Fatalf("readProfile() error = %v", err)
+	}
+	if profile.Rows != 2 || len(profile.Columns) != 2 {
+		t.Errorf("readProfile() = %d rows, %d columns, want 2 and 2", profile.Rows, len(profile.Columns))
+	}
+
+	if _, err := readProfile([]string{filepath.Join(dir, "missing.csv")}); err == nil {
+		t.Error("readProfile() of a missing file succeeded")
+	}
+}
+
+func TestDataProfileInsights(t *testing.T) {
+	client := &MockAIClient{response: "- amount has nulls"}
+	profile := "sales.csv: CSV, 2 rows, 2 columns\n"
+	insights, err := dataProfileInsights(client, profile)
+	if err != nil {
+		t.Fatalf("dataProfileInsights() error = %v", err)
+	}
+	if insights != "- amount has nulls" {
+		t.Errorf("dataProfileInsights() = %q", insights)
+	}
+	if len(client.questions) != 1 || !strings.Contains(client.questions[0], "2 rows") {
+		t.Errorf("dataProfileInsights() sent %q", client.questions)
+	}
+}
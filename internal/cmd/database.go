@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	auracontext "github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/dotenv"
+)
+
+var databaseCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Work with project databases",
+	Long: `Open shells and inspect schemas of databases used by the current project.
+
+A target can be:
+  path/to/app.db        # SQLite database file
+  env:DATABASE_URL      # Connection URL from .env or the environment
+  compose:<service>     # Postgres or MySQL service in docker-compose.yml`,
+}
+
+var databaseShellCmd = &cobra.Command{
+	Use:   "shell <target>",
+	Short: "Open an interactive SQL shell",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDatabaseShell,
+}
+
+var databaseSchemaCmd = &cobra.Command{
+	Use:   "schema <target>",
+	Short: "Print the database schema",
+	Long: `Print the schema of a database, optionally followed by an AI explanation.
+
+Examples:
+  aura db schema app.db
+  aura db schema --explain compose:postgres`,
+	Args: cobra.ExactArgs(1),
+	RunE: paged(runDatabaseSchema),
+}
+
+var schemaExplain bool
+
+// databaseConnection describes how to reach a resolved database target.
+type databaseConnection struct {
+	Engine  string
+	Path    string
+	URL     *url.URL
+	Service string
+}
+
+func runDatabaseShell(cmd *cobra.Command, args []string) error {
+	conn, err := resolveDatabaseTarget(args[0])
+	if err != nil {
+		return err
+	}
+
+	shellArgs := conn.shellCommand()
+	shell := exec.Command(shellArgs[0], shellArgs[1:]...)
+	shell.Env = append(os.Environ(), conn.passwordEnv()...)
+	shell.Stdin = os.Stdin
+	shell.Stdout = os.Stdout
+	shell.Stderr = os.Stderr
+
+	if err := shell.Run(); err != nil {
+		return fmt.Errorf("SQL shell failed: %w", err)
+	}
+	return nil
+}
+
+func runDatabaseSchema(cmd *cobra.Command, args []string) error {
+	conn, err := resolveDatabaseTarget(args[0])
+	if err != nil {
+		return err
+	}
+
+	schema, err := conn.schema()
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	if strings.TrimSpace(schema) == "" {
+		fmt.Println("The database has no tables yet.")
+		return nil
+	}
+
+	if !schemaExplain {
+		fmt.Println(schema)
+		return nil
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return fmt.Errorf("failed to initialize AI client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+
+	explanation, err := client.ExplainSchema(ctx, conn.Engine, schema)
+	stopThinking()
+
+	if err != nil {
+		return fmt.Errorf("AI request failed: %w", err)
+	}
+
+	fmt.Printf("\n%s\n", explanation)
+	printFallbackNote(client)
+	return nil
+}
+
+// resolveDatabaseTarget turns a target reference into connection details.
+func resolveDatabaseTarget(ref string) (*databaseConnection, error) {
+	switch {
+	case strings.HasPrefix(ref, "compose:"):
+		service := strings.TrimPrefix(ref, "compose:")
+		for _, file := range []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"} {
+			services, err := auracontext.ParseComposeDatabaseServices(file)
+			if err != nil {
+				continue
+			}
+			for _, s := range services {
+				if s.Name == service {
+					return &databaseConnection{Engine: s.Engine, Service: s.Name}, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("no database service '%s' found in docker-compose file", service)
+
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		value := os.Getenv(name)
+		if env, err := dotenv.Read(".env"); err == nil && env[name] != "" {
+			value = env[name]
+		}
+		if value == "" {
+			return nil, fmt.Errorf("%s is not set in .env or the environment", name)
+		}
+		return databaseConnectionFromURL(value)
+
+	default:
+		if _, err := os.Stat(ref); err != nil {
+			return nil, fmt.Errorf("database file '%s' not found", ref)
+		}
+		return &databaseConnection{Engine: "sqlite", Path: ref}, nil
+	}
+}
+
+// databaseConnectionFromURL creates connection details from a database URL.
+func databaseConnectionFromURL(raw string) (*databaseConnection, error) {
+	engine := auracontext.DatabaseURLEngine(raw)
+	if engine == "" {
+		return nil, fmt.Errorf("unsupported database URL")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database URL: %w", err)
+	}
+
+	if engine == "sqlite" {
+		path := u.Opaque
+		if path == "" {
+			path = u.Host + u.Path
+		}
+		return &databaseConnection{Engine: engine, Path: path}, nil
+	}
+
+	return &databaseConnection{Engine: engine, URL: u}, nil
+}
+
+// shellCommand returns the command line of an interactive SQL shell.
+func (c *databaseConnection) shellCommand() []string {
+	switch {
+	case c.Engine == "sqlite":
+		return []string{"sqlite3", c.Path}
+	case c.Service != "" && c.Engine == "postgres":
+		return []string{"docker-compose", "exec", c.Service, "sh", "-c",
+			`exec psql -U "${POSTGRES_USER:-postgres}" "${POSTGRES_DB:-${POSTGRES_USER:-postgres}}"`}
+	case c.Service != "":
+		return []string{"docker-compose", "exec", c.Service, "sh", "-c",
+			`exec mysql -uroot -p"$MYSQL_ROOT_PASSWORD" $MYSQL_DATABASE`}
+	case c.Engine == "postgres":
+		return []string{"psql", c.postgresURL()}
+	default:
+		return append([]string{"mysql"}, c.mysqlArgs()...)
+	}
+}
+
+// schema returns the schema of the database as SQL statements.
+func (c *databaseConnection) schema() (string, error) {
+	var args []string
+	switch {
+	case c.Engine == "sqlite":
+		return db.DumpSQLiteSchema(c.Path)
+	case c.Service != "" && c.Engine == "postgres":
+		args = []string{"docker-compose", "exec", "-T", c.Service, "sh", "-c",
+			`exec pg_dump --schema-only -U "${POSTGRES_USER:-postgres}" "${POSTGRES_DB:-${POSTGRES_USER:-postgres}}"`}
+	case c.Service != "":
+		args = []string{"docker-compose", "exec", "-T", c.Service, "sh", "-c",
+			`exec mysqldump --no-data -uroot -p"$MYSQL_ROOT_PASSWORD" $MYSQL_DATABASE`}
+	case c.Engine == "postgres":
+		args = []string{"pg_dump", "--schema-only", c.postgresURL()}
+	default:
+		args = append([]string{"mysqldump", "--no-data"}, c.mysqlArgs()...)
+	}
+
+	dump := exec.Command(args[0], args[1:]...)
+	dump.Env = append(os.Environ(), c.passwordEnv()...)
+	dump.Stderr = os.Stderr
+
+	output, err := dump.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// mysqlArgs converts a MySQL URL into mysql client arguments. The password
+// is passed through the environment instead, see passwordEnv.
+func (c *databaseConnection) mysqlArgs() []string {
+	var args []string
+	if host := c.URL.Hostname(); host != "" {
+		args = append(args, "-h", host)
+	}
+	if port := c.URL.Port(); port != "" {
+		args = append(args, "-P", port)
+	}
+	if user := c.URL.User.Username(); user != "" {
+		args = append(args, "-u", user)
+	}
+	if name := strings.TrimPrefix(c.URL.Path, "/"); name != "" {
+		args = append(args, name)
+	}
+	return args
+}
+
+// postgresURL returns the Postgres URL for psql and pg_dump without its
+// password, which is passed through the environment instead, see passwordEnv.
+func (c *databaseConnection) postgresURL() string {
+	stripped := *c.URL
+	if c.URL.User != nil {
+		stripped.User = url.User(c.URL.User.Username())
+	}
+	query := stripped.Query()
+	if query.Has("password") {
+		query.Del("password")
+		stripped.RawQuery = query.Encode()
+	}
+	return stripped.String()
+}
+
+// passwordEnv returns environment variables carrying the MySQL or Postgres
+// password so it never shows up in the process list.
+func (c *databaseConnection) passwordEnv() []string {
+	if c.URL == nil {
+		return nil
+	}
+	password, ok := c.URL.User.Password()
+	if c.Engine == "postgres" && !ok {
+		password, ok = c.URL.Query().Get("password"), c.URL.Query().Has("password")
+	}
+	if !ok {
+		return nil
+	}
+	switch c.Engine {
+	case "mysql":
+		return []string{"MYSQL_PWD=" + password}
+	case "postgres":
+		return []string{"PGPASSWORD=" + password}
+	}
+	return nil
+}
+
+func init() {
+	databaseSchemaCmd.Flags().BoolVar(&schemaExplain, "explain", false, "Explain the schema with AI")
+
+	databaseCmd.AddCommand(databaseShellCmd)
+	databaseCmd.AddCommand(databaseSchemaCmd)
+	rootCmd.AddCommand(databaseCmd)
+}
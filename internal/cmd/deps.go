@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/audit"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Check the dependencies of the project",
+	Long:  `Check the dependencies of the project in the current directory.`,
+}
+
+var depsAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Find known vulnerabilities in the dependencies",
+	Long: `Find known vulnerabilities in the dependencies of the project in the
+current directory with the audit tool of each ecosystem found:
+
+- Go (go.mod):                           govulncheck
+- npm (package.json):                    npm audit
+- pip (requirements.txt, pyproject.toml): pip-audit
+
+Ecosystems whose tool isn't installed are skipped with a hint on how to
+install it. The results are merged into one report, the most severe first.
+govulncheck only reports vulnerabilities in code the project calls.
+
+With --guide the AI assistant explains how to upgrade, focusing on the fixes
+that need a new major version and may break the project.
+
+Examples:
+  aura deps audit
+  aura deps audit --guide`,
+	Args: cobra.NoArgs,
+	RunE: runDepsAudit,
+}
+
+var depsGuide bool
+
+// auditTimeout bounds each audit tool, which may download its database.
+const auditTimeout = 5 * time.Minute
+
+func runDepsAudit(cmd *cobra.Command, args []string) error {
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	auditors := audit.Detect(root)
+	if len(auditors) == 0 {
+		fmt.Println("ℹ️  No go.mod, package.json, requirements.txt or pyproject.toml found")
+		return nil
+	}
+	// The tools download vulnerability data on their own, outside the
+	// offline transport
+	if err := config.RequireNetwork("aura deps audit"); err != nil {
+		return err
+	}
+
+	var vulnerabilities []audit.Vulnerability
+	audited := 0
+	for _, auditor := range auditors {
+		if !auditor.Installed() {
+			fmt.Printf("ℹ️  Skipping %s: %s is not installed (%s)\n", auditor.Ecosystem, auditor.Tool, auditor.Install)
+			continue
+		}
+		found, err := runAuditor(auditor, root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		audited++
+		vulnerabilities = append(vulnerabilities, found...)
+	}
+	if audited == 0 {
+		return nil
+	}
+	if len(vulnerabilities) == 0 {
+		fmt.Println("✓ No known vulnerabilities found")
+		return nil
+	}
+
+	audit.Sort(vulnerabilities)
+	report := formatAuditReport(vulnerabilities)
+	fmt.Print(report)
+
+	if !depsGuide {
+		fmt.Println("\nℹ️  Run 'aura deps audit --guide' for upgrade guidance")
+		return nil
+	}
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	guidance, err := upgradeGuidance(client, report)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", guidance)
+	return nil
+}
+
+// runAuditor runs auditor in root behind a spinner.
+func runAuditor(auditor audit.Auditor, root string) ([]audit.Vulnerability, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), auditTimeout)
+	defer cancel()
+
+	spinner := progress.NewSpinner(fmt.Sprintf("Auditing %s dependencies with %s", auditor.Ecosystem, auditor.Tool)).Start()
+	vulnerabilities, err := auditor.Run(ctx, root)
+	spinner.Stop()
+	return vulnerabilities, err
+}
+
+// formatAuditReport lists vulnerabilities by ecosystem, sorted by
+// audit.Sort, followed by the counts per severity.
+func formatAuditReport(vulnerabilities []audit.Vulnerability) string {
+	var b strings.Builder
+	ecosystem := ""
+	for _, vulnerability := range vulnerabilities {
+		if vulnerability.Ecosystem != ecosystem {
+			if ecosystem != "" {
+				fmt.Fprintln(&b)
+			}
+			ecosystem = vulnerability.Ecosystem
+			fmt.Fprintf(&b, "%s\n", ecosystem)
+		}
+		writeVulnerability(&b, vulnerability)
+	}
+
+	counts := map[string]int{}
+	for _, vulnerability := range vulnerabilities {
+		counts[vulnerability.Severity]++
+	}
+	var totals []string
+	for _, severity := range audit.Severities {
+		if counts[severity] > 0 {
+			totals = append(totals, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	if counts[""] > 0 {
+		totals = append(totals, fmt.Sprintf("%d unrated", counts[""]))
+	}
+	noun := "vulnerabilities"
+	if len(vulnerabilities) == 1 {
+		noun = "vulnerability"
+	}
+	fmt.Fprintf(&b, "\n%d %s: %s\n", len(vulnerabilities), noun, strings.Join(totals, ", "))
+	return b.String()
+}
+
+// writeVulnerability writes one vulnerability of the report.
+func writeVulnerability(w io.Writer, vulnerability audit.Vulnerability) {
+	severity := vulnerability.Severity
+	if severity == "" {
+		severity = "-"
+	}
+	name := vulnerability.Package
+	if vulnerability.Installed != "" {
+		name += " " + vulnerability.Installed
+	}
+	fix := "no fix available"
+	if vulnerability.Fixed != "" {
+		fix = "fixed in " + vulnerability.Fixed
+		if vulnerability.Breaking {
+			fix += " (major upgrade)"
+		}
+	}
+	fmt.Fprintf(w, "  %-9s %s  %s, %s\n", severity, vulnerability.ID, name, fix)
+	if vulnerability.Summary != "" {
+		fmt.Fprintf(w, "            %s\n", vulnerability.Summary)
+	}
+}
+
+// upgradeGuidance asks the AI assistant how to upgrade the dependencies of
+// an audit report.
+func upgradeGuidance(client ai.Assistant, report string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	guidance, err := client.UpgradeGuidance(ctx, report)
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get upgrade guidance: %w", err)
+	}
+	return guidance, nil
+}
+
+func init() {
+	depsAuditCmd.Flags().BoolVar(&depsGuide, "guide", false, "Let the AI assistant explain how to upgrade")
+
+	depsCmd.AddCommand(depsAuditCmd)
+	rootCmd.AddCommand(depsCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/audit"
+)
+
+func TestFormatAuditReport(t *testing.T) {
+	vulnerabilities := []audit.Vulnerability{
+		{Ecosystem: "Go", Package: "golang.org/x/net", Installed: "v0.17.0", Fixed: "v0.23.0", ID: "GO-2024-2687", Summary: "HTTP/2 CONTINUATION flood"},
+		{Ecosystem: "npm", Package: "minimist", Installed: "<0.2.4", Fixed: "mkdirp@1.0.4", Breaking: true, ID: "GHSA-xvch-5gv4-984h", Severity: "critical"},
+		{Ecosystem: "npm", Package: "ws", Installed: "8.0.0 - 8.17.0", ID: "GHSA-3h5v-q93c-6h6q", Severity: "high", Summary: "ws affected by a DoS"},
+	}
+	want := `Go
+  -         GO-2024-2687  golang.org/x/net v0.17.0, fixed in v0.23.0
+            HTTP/2 CONTINUATION flood
+
+npm
+  critical  GHSA-xvch-5gv4-984h  minimist <0.2.4, fixed in mkdirp@1.0.4 (major upgrade)
+  high      GHSA-3h5v-q93c-6h6q  ws 8.0.0 - 8.17.0, no fix available
+            ws affected by a DoS
+
+3 vulnerabilities: 1 critical, 1 high, 1 unrated
+`
+	if got := formatAuditReport(vulnerabilities); got != want {
+		t.Errorf("formatAuditReport() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUpgradeGuidance(t *testing.T) {
+	client := &MockAIClient{response: "Run go get golang.org/x/net@v0.23.0"}
+	guidance, err := upgradeGuidance(client, "Go\n  GO-2024-2687 ...")
+	if err != nil {
+		t.Fatalf("upgradeGuidance() error = %v", err)
+	}
+	if guidance != "Run go get golang.org/x/net@v0.23.0" {
+		t.Errorf("upgradeGuidance() = %q", guidance)
+	}
+}
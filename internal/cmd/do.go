@@ -1,16 +1,25 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"runtime"
 	"strings"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	"github.com/timfewi/aura-cli-go/internal/config"
 	"github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/finder"
+	"github.com/timfewi/aura-cli-go/internal/open"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/theme"
 )
 
 var doCmd = &cobra.Command{
@@ -19,30 +28,26 @@ var doCmd = &cobra.Command{
 	Long: `Analyze the current directory and suggest relevant actions based on the detected context.
 	
 This command detects various project types (Git, Node.js, Python, Go, Docker, etc.)
-and presents an interactive list of common actions you might want to perform.`,
-	RunE: runDo,
-}
+and presents an interactive list of common actions you might want to perform.
 
-func runDo(cmd *cobra.Command, args []string) error {
-	// Collect actions from all detectors
-	var allActions []context.Action
+Use --fzf to pick the action in a fuzzy finder that previews its command, or
+--watch to rerun an action whenever files change.
 
-	// Run detectors in parallel for better performance
-	detectors := []func() []context.Action{
-		context.DetectGitContext,
-		context.DetectNodeContext,
-		context.DetectPythonContext,
-		context.DetectGoContext,
-		context.DetectDockerContext,
-		context.DetectMakeContext,
-	}
+Examples:
+  aura do
+  aura do --fzf
+  aura do --watch "run tests"     # By name or part of its command
+  aura do --watch build --ignore "*.log"`,
+	RunE: runDo,
+}
 
-	for _, detector := range detectors {
-		if actions := detector(); actions != nil {
-			allActions = append(allActions, actions...)
-		}
-	}
+var (
+	doFzf   bool
+	doWatch string
+)
 
+func runDo(cmd *cobra.Command, args []string) error {
+	allActions := detectActions()
 	if len(allActions) == 0 {
 		fmt.Println("No specific context detected in this directory.")
 		fmt.Println("Try running 'aura do' in a project directory (Git repo, Node.js project, etc.)")
@@ -54,27 +59,37 @@ func runDo(cmd *cobra.Command, args []string) error {
 		{Name: "Open current directory", Command: getOpenCommand()},
 		{Name: "List directory contents", Command: getListCommand()},
 		{Name: "Show disk usage", Command: getDiskUsageCommand()},
-		{Name: "Find large files", Command: getFindLargeFilesCommand()},
+		{Name: "Find large files", Command: "aura analyze size"},
 	}
 	allActions = append(allActions, generalActions...)
 
-	// Create display items for the prompt
+	if doWatch != "" {
+		action, err := findAction(allActions, doWatch)
+		if err != nil {
+			return err
+		}
+		if action.Shell {
+			return fmt.Errorf("'%s' runs in your shell and can't be watched", action.Name)
+		}
+		return watchCommand(action.Command)
+	}
+
+	if doFzf {
+		return runDoFinder(allActions)
+	}
+
+	// Create display items for the prompt, with the command each runs
 	items := make([]string, len(allActions))
 	for i, action := range allActions {
-		items[i] = action.Name
+		items[i] = action.Name + "  " + theme.Paint(theme.Muted, action.Command)
 	}
 
 	// Create interactive prompt
 	prompt := promptui.Select{
-		Label: "Select an action",
-		Items: items,
-		Size:  10,
-		Templates: &promptui.SelectTemplates{
-			Label:    "{{ . }}?",
-			Active:   "▸ {{ . | cyan }}",
-			Inactive: "  {{ . | white }}",
-			Selected: "✓ {{ . | green }}",
-		},
+		Label:     "Select an action",
+		Items:     items,
+		Size:      10,
+		Templates: theme.SelectTemplates("."),
 	}
 
 	selectedIndex, _, err := prompt.Run()
@@ -86,24 +101,234 @@ func runDo(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("prompt failed: %w", err)
 	}
 
-	selectedAction := allActions[selectedIndex]
+	return runAction(allActions[selectedIndex])
+}
+
+// findAction returns the action named query, or the only action whose name
+// or command contains it.
+func findAction(actions []context.Action, query string) (context.Action, error) {
+	query = strings.ToLower(query)
+	var matches []context.Action
+	for _, action := range actions {
+		if strings.ToLower(action.Name) == query {
+			return action, nil
+		}
+		if strings.Contains(strings.ToLower(action.Name), query) || strings.Contains(strings.ToLower(action.Command), query) {
+			matches = append(matches, action)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return context.Action{}, fmt.Errorf("no action matches '%s', run 'aura do' to see the actions", query)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, action := range matches {
+		names[i] = action.Name
+	}
+	return context.Action{}, fmt.Errorf("'%s' matches several actions: %s", query, strings.Join(names, ", "))
+}
+
+// runDoFinder lets the user pick the action in a fuzzy finder with a preview
+// of its command.
+func runDoFinder(actions []context.Action) error {
+	items := make([]finder.Item, len(actions))
+	for i, action := range actions {
+		items[i] = finder.Item{Label: action.Name, Value: action.Command}
+	}
+
+	selected, err := finder.Select(items, finder.Options{
+		Prompt: "Select an action",
+		Preview: func(item finder.Item) string {
+			return "Command: " + item.Value
+		},
+		FzfPreview: "echo {}",
+	})
+	if err != nil {
+		if errors.Is(err, finder.ErrCancelled) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+		return err
+	}
+
+	return runAction(actions[selected])
+}
+
+// detectActions returns the actions of the projects detected in the current
+// directory.
+func detectActions() []context.Action {
+	var actions []context.Action
+	detectors := []func() []context.Action{
+		detectTeamActions,
+		detectPortConflictActions,
+		context.DetectGitContext,
+		context.DetectNodeContext,
+		context.DetectPythonContext,
+		context.DetectGoContext,
+		context.DetectDockerContext,
+		context.DetectMakeContext,
+		context.DetectDatabaseContext,
+		context.DetectEnvContext,
+		context.DetectToolVersionContext,
+		detectForgeActions,
+	}
+	for _, detector := range detectors {
+		actions = append(actions, detector()...)
+	}
+	return actions
+}
+
+// detectTeamActions returns the actions of the team settings. Anyone can
+// write the settings of a repository, so its actions are offered only once
+// the user approved them, like on-enter commands. Changing an action
+// requires approving them again.
+func detectTeamActions() []context.Action {
+	var actions []context.Action
+	for _, action := range config.Team.Actions {
+		if action.Name == "" || action.Command == "" {
+			continue
+		}
+		actions = append(actions, context.Action{Name: action.Name, Command: action.Command, Shell: action.Shell})
+	}
+	if len(actions) == 0 || !teamActionsTrusted(config.TeamSettingsFile, actions) {
+		return nil
+	}
+	return actions
+}
+
+// teamActionsDigest returns a digest of actions, which changes with any of
+// their names and commands.
+func teamActionsDigest(actions []context.Action) string {
+	h := sha256.New()
+	for _, action := range actions {
+		fmt.Fprintf(h, "%q %q %t\n", action.Name, action.Command, action.Shell)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// teamActionsTrusted reports whether the user approved actions of the team
+// settings file, asking them if they haven't yet.
+func teamActionsTrusted(file string, actions []context.Action) bool {
+	database, err := db.New()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipping the actions of %s: %v\n", file, err)
+		return false
+	}
+	defer database.Close()
+
+	digest := teamActionsDigest(actions)
+	trusted, err := database.TrustedTeamActions(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipping the actions of %s: %v\n", file, err)
+		return false
+	}
+	if trusted == digest {
+		return true
+	}
+	if !approveTeamActions(file, actions) {
+		return false
+	}
+	// In read-only mode the approval holds for this run only
+	if !config.ReadOnly() {
+		if err := database.TrustTeamActions(file, digest); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remember the approval: %v\n", err)
+		}
+	}
+	return true
+}
+
+// approveTeamActions shows the commands of the actions of the team settings
+// file on stderr and asks whether they may be offered.
+func approveTeamActions(file string, actions []context.Action) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "Skipping the unapproved actions of %s, run 'aura do' in a terminal to approve them\n", displayHomePath(file))
+		return false
+	}
+
+	fmt.Fprintf(os.Stderr, "%s adds these actions, which run commands on this machine:\n", displayHomePath(file))
+	for _, action := range actions {
+		where := ""
+		if action.Shell {
+			where = " (in your shell)"
+		}
+		fmt.Fprintf(os.Stderr, "  %s%s: %s\n", action.Name, where, action.Command)
+	}
+	prompt := promptui.Prompt{
+		Label:     "Allow the actions of this file",
+		IsConfirm: true,
+		Stdout:    stderrCloser{os.Stderr},
+	}
+	_, err := prompt.Run()
+	return err == nil
+}
+
+// runAction executes the selected action. Actions that must run in the
+// calling shell are handed to the shell integration instead.
+func runAction(action context.Action) error {
+	if action.Shell && skipChange("run in your shell: %s", action.Command) {
+		return nil
+	}
+	if skipChange("run: %s", action.Command) {
+		return nil
+	}
+	if action.Shell {
+		return emitShellCommand(action.Command)
+	}
+
+	if !confirmAction("do", fmt.Sprintf("Run '%s'", action.Command), context.IsDestructive(action.Command), false) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
 
 	// Show the command that will be executed
-	fmt.Printf("Executing: %s\n", selectedAction.Command)
+	fmt.Printf("Executing: %s\n", action.Command)
 
 	// Execute the selected command
-	return executeCommand(selectedAction.Command)
+	return executeCommand(action.Command)
+}
+
+// emitShellCommand appends command to the file named by AURA_EVAL_FILE, which
+// the shell integration evaluates after Aura exits.
+func emitShellCommand(command string) error {
+	evalFile := envvar.EvalFile.Get()
+	if evalFile == "" {
+		fmt.Println("This command must run in your shell, which the Aura shell integration does")
+		fmt.Println("automatically. Run it yourself:")
+		fmt.Printf("  %s\n", command)
+		return nil
+	}
+	if err := checkCommand(command, ""); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(evalFile, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to pass command to the shell: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintln(file, command); err != nil {
+		return fmt.Errorf("failed to pass command to the shell: %w", err)
+	}
+
+	fmt.Printf("Running in your shell: %s\n", command)
+	return nil
 }
 
+// executeCommand runs command in the shell of the platform, so commands
+// joined by && or | and quoted arguments work as typed.
 func executeCommand(command string) error {
-	// Parse the command into parts
-	parts := strings.Fields(command)
-	if len(parts) == 0 {
+	if strings.TrimSpace(command) == "" {
 		return fmt.Errorf("empty command")
 	}
+	if err := checkCommand(command, ""); err != nil {
+		return err
+	}
 
-	// Handle special cases for interactive commands
-	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd := platform.Current().ShellCommand(command)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -111,23 +336,16 @@ func executeCommand(command string) error {
 	return cmd.Run()
 }
 
-// getOpenCommand returns the appropriate command to open the current directory
-// based on the operating system.
+// getOpenCommand returns the command opening the current directory in the
+// file manager.
 func getOpenCommand() string {
-	switch {
-	case isWindows():
-		return "explorer ."
-	case isMacOS():
-		return "open ."
-	default:
-		return "xdg-open ."
-	}
+	return open.ShellCommand(".")
 }
 
 // getListCommand returns the appropriate command to list directory contents
 // based on the operating system.
 func getListCommand() string {
-	if isWindows() {
+	if platform.Current().IsWindows() {
 		return "dir"
 	}
 	return "ls -la"
@@ -136,37 +354,16 @@ func getListCommand() string {
 // getDiskUsageCommand returns the appropriate command to show disk usage
 // based on the operating system.
 func getDiskUsageCommand() string {
-	if isWindows() {
+	if platform.Current().IsWindows() {
 		return "powershell -Command \"Get-ChildItem | Measure-Object -Property Length -Sum | Select-Object @{Name='Size(MB)';Expression={[math]::Round($_.Sum/1MB,2)}}\""
 	}
 	return "du -sh *"
 }
 
-// getFindLargeFilesCommand returns the appropriate command to find large files
-// based on the operating system.
-func getFindLargeFilesCommand() string {
-	if isWindows() {
-		return "powershell -Command \"Get-ChildItem -Recurse -File | Where-Object {$_.Length -gt 10MB} | Select-Object Name, @{Name='Size(MB)';Expression={[math]::Round($_.Length/1MB,2)}}, FullName | Sort-Object 'Size(MB)' -Descending\""
-	}
-	return "find . -type f -size +10M -exec ls -lh {} \\;"
-}
-
-func isWindows() bool {
-	// Check environment variable first for testing purposes
-	if os := os.Getenv("OS"); os != "" {
-		return strings.Contains(strings.ToLower(os), "windows")
-	}
-	return runtime.GOOS == "windows"
-}
-
-func isMacOS() bool {
-	// Check environment variable first for testing purposes
-	if ostype := os.Getenv("OSTYPE"); ostype != "" {
-		return strings.Contains(strings.ToLower(ostype), "darwin")
-	}
-	return runtime.GOOS == "darwin"
-}
-
 func init() {
+	doCmd.Flags().BoolVar(&doFzf, "fzf", false, "Pick the action in a fuzzy finder (uses fzf when installed)")
+	doCmd.Flags().StringVar(&doWatch, "watch", "", "Rerun the action with this name whenever files change")
+	addWatchFlags(doCmd)
+
 	rootCmd.AddCommand(doCmd)
 }
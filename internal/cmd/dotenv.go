@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/dotenv"
+)
+
+var dotenvCmd = &cobra.Command{
+	Use:   "dotenv",
+	Short: "Keep .env files in sync and out of version control",
+	Long:  `Compare .env files with their example, generate redacted examples and keep secrets out of Git.`,
+}
+
+var dotenvDiffCmd = &cobra.Command{
+	Use:   "diff [env-file] [example-file]",
+	Short: "Compare variables of a .env file with its example",
+	Long: `List variables that are documented in the example file but missing from the
+.env file, and variables that are set but not documented.
+
+Examples:
+  aura dotenv diff
+  aura dotenv diff .env.local .env.example`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runDotenvDiff,
+}
+
+var dotenvExampleCmd = &cobra.Command{
+	Use:   "example [env-file] [output-file]",
+	Short: "Generate a redacted example from a .env file",
+	Long: `Write a copy of a .env file with all secret values removed, ready to be
+committed as documentation for the required variables.
+
+Examples:
+  aura dotenv example
+  aura dotenv example .env .env.sample --force`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runDotenvExample,
+}
+
+var dotenvIgnoreCmd = &cobra.Command{
+	Use:   "ignore [env-file]",
+	Short: "Add a .env file to .gitignore",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDotenvIgnore,
+}
+
+var dotenvForce bool
+
+func runDotenvDiff(cmd *cobra.Command, args []string) error {
+	envPath, examplePath := ".env", ".env.example"
+	if len(args) > 0 {
+		envPath = args[0]
+	}
+	if len(args) > 1 {
+		examplePath = args[1]
+	}
+
+	env, err := dotenv.Read(envPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", envPath, err)
+	}
+	example, err := dotenv.Read(examplePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", examplePath, err)
+	}
+
+	missing, undocumented := dotenv.Diff(env, example)
+	if len(missing) == 0 && len(undocumented) == 0 {
+		fmt.Printf("✓ %s and %s define the same variables\n", envPath, examplePath)
+		return nil
+	}
+
+	if len(missing) > 0 {
+		fmt.Printf("Missing from %s:\n", envPath)
+		for _, key := range missing {
+			fmt.Printf("  - %s\n", key)
+		}
+	}
+	if len(undocumented) > 0 {
+		fmt.Printf("Not documented in %s:\n", examplePath)
+		for _, key := range undocumented {
+			fmt.Printf("  + %s\n", key)
+		}
+	}
+
+	return nil
+}
+
+func runDotenvExample(cmd *cobra.Command, args []string) error {
+	envPath, outputPath := ".env", ".env.example"
+	if len(args) > 0 {
+		envPath = args[0]
+	}
+	if len(args) > 1 {
+		outputPath = args[1]
+	}
+
+	if _, err := os.Stat(outputPath); err == nil && !dotenvForce {
+		return fmt.Errorf("'%s' already exists, use --force to overwrite it", outputPath)
+	}
+
+	file, err := dotenv.ParseFile(envPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", envPath, err)
+	}
+
+	if err := os.WriteFile(outputPath, []byte(file.Redacted()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+
+	fmt.Printf("✓ Wrote redacted example to '%s'\n", outputPath)
+	return nil
+}
+
+func runDotenvIgnore(cmd *cobra.Command, args []string) error {
+	envPath := ".env"
+	if len(args) > 0 {
+		envPath = args[0]
+	}
+
+	added, err := addToGitignore(envPath)
+	if err != nil {
+		return fmt.Errorf("failed to update .gitignore: %w", err)
+	}
+
+	if added {
+		fmt.Printf("✓ Added '%s' to .gitignore\n", envPath)
+	} else {
+		fmt.Printf("'%s' is already listed in .gitignore\n", envPath)
+	}
+
+	// An ignore rule has no effect on files Git already tracks
+	if exec.Command("git", "ls-files", "--error-unmatch", envPath).Run() == nil {
+		fmt.Printf("⚠ '%s' is already tracked by Git. Stop tracking it with:\n", envPath)
+		fmt.Printf("   git rm --cached %s\n", envPath)
+		fmt.Println("  Rotate any secrets it contains, they remain in the Git history.")
+	}
+
+	return nil
+}
+
+// addToGitignore appends pattern to .gitignore unless it is already listed.
+func addToGitignore(pattern string) (bool, error) {
+	content, err := os.ReadFile(".gitignore")
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == pattern || line == "/"+pattern {
+			return false, nil
+		}
+	}
+
+	var b strings.Builder
+	b.Write(content)
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(pattern + "\n")
+
+	return true, os.WriteFile(".gitignore", []byte(b.String()), 0644)
+}
+
+func init() {
+	dotenvExampleCmd.Flags().BoolVar(&dotenvForce, "force", false, "Overwrite an existing output file")
+
+	dotenvCmd.AddCommand(dotenvDiffCmd)
+	dotenvCmd.AddCommand(dotenvExampleCmd)
+	dotenvCmd.AddCommand(dotenvIgnoreCmd)
+	rootCmd.AddCommand(dotenvCmd)
+}
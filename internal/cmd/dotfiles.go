@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/dotfiles"
+)
+
+var dotfilesCmd = &cobra.Command{
+	Use:   "dotfiles",
+	Short: "Link a dotfiles repository into the home directory",
+	Long: `Link the files of a dotfiles repository into the home directory, as
+described by the repository's aura-dotfiles.yaml mapping file. Files ending in
+.tmpl are rendered with machine-specific variables and written instead of
+linked, so one repository bootstraps every machine.
+
+Examples:
+  aura dotfiles init git@github.com:me/dotfiles.git   # Clone to ~/.dotfiles
+  aura dotfiles init ~/dotfiles                       # Use an existing directory
+  aura dotfiles status                                # Show what is linked
+  aura dotfiles link                                  # Link missing files
+  aura dotfiles link --force                          # Back up and replace conflicts`,
+}
+
+var dotfilesInitCmd = &cobra.Command{
+	Use:   "init [directory|git-url]",
+	Short: "Set up the dotfiles repository",
+	Long: `Set up the dotfiles repository: clone it if a Git URL is given, create a
+mapping file if it has none, and remember its location. Without an argument,
+~/.dotfiles is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDotfilesInit,
+}
+
+var dotfilesLinkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Link and render the files of the dotfiles repository",
+	Args:  cobra.NoArgs,
+	RunE:  runDotfilesLink,
+}
+
+var dotfilesStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which dotfiles are linked",
+	Args:  cobra.NoArgs,
+	RunE:  runDotfilesStatus,
+}
+
+var dotfilesForce bool
+
+func runDotfilesInit(cmd *cobra.Command, args []string) error {
+	dir := config.GetDotfilesRepo()
+	source := ""
+	if len(args) == 1 {
+		if isGitURL(args[0]) {
+			source = args[0]
+		} else {
+			dir = config.ExpandHome(args[0])
+		}
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	if source != "" {
+		if err := config.RequireNetwork("cloning a dotfiles repository"); err != nil {
+			return err
+		}
+		if _, err := os.Stat(dir); err == nil {
+			return fmt.Errorf("'%s' already exists, remove it or pass it as directory", dir)
+		}
+		fmt.Printf("📥 Cloning %s into %s\n", source, dir)
+		clone := exec.Command("git", "clone", source, dir)
+		clone.Stdout = os.Stdout
+		clone.Stderr = os.Stderr
+		if err := clone.Run(); err != nil {
+			return fmt.Errorf("failed to clone dotfiles repository: %w", err)
+		}
+	} else if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create dotfiles directory: %w", err)
+	}
+
+	mappingPath := filepath.Join(dir, dotfiles.MappingFile)
+	if _, err := os.Stat(mappingPath); os.IsNotExist(err) {
+		if err := os.WriteFile(mappingPath, []byte(dotfiles.DefaultMapping), 0644); err != nil {
+			return fmt.Errorf("failed to create mapping file: %w", err)
+		}
+		fmt.Printf("✓ Created %s\n", mappingPath)
+	}
+
+	if err := config.SetSetting(config.GetSettingsFile(), "dotfiles.repo", dir); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	fmt.Printf("✓ Dotfiles repository: %s\n", dir)
+	fmt.Println("🚀 Next steps:")
+	fmt.Printf("   Map files in %s\n", dotfiles.MappingFile)
+	fmt.Println("   aura dotfiles status")
+	fmt.Println("   aura dotfiles link")
+	return nil
+}
+
+// loadDotfiles returns the entries of the configured dotfiles repository and
+// the machine they are rendered for.
+func loadDotfiles() ([]dotfiles.Entry, dotfiles.Machine, error) {
+	dir := config.GetDotfilesRepo()
+	mapping, err := dotfiles.LoadMapping(dir)
+	if err != nil {
+		return nil, dotfiles.Machine{}, err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, dotfiles.Machine{}, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	entries, err := dotfiles.Entries(mapping, dir, home)
+	if err != nil {
+		return nil, dotfiles.Machine{}, fmt.Errorf("invalid mapping file: %w", err)
+	}
+	return entries, dotfiles.CurrentMachine(mapping, home), nil
+}
+
+// dotfilesRendersFile returns the file remembering the templates rendered by
+// 'aura dotfiles link'.
+func dotfilesRendersFile() string {
+	return filepath.Join(config.StateDir, "dotfiles_rendered.json")
+}
+
+func runDotfilesLink(cmd *cobra.Command, args []string) error {
+	entries, machine, err := loadDotfiles()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No files mapped. Add them to %s.\n", dotfiles.MappingFile)
+		return nil
+	}
+
+	renders := dotfiles.LoadRenders(dotfilesRendersFile())
+	failed := 0
+	for _, entry := range entries {
+		if err := dotfiles.Link(entry, machine, renders, dotfilesForce); err != nil {
+			fmt.Printf("  ❌ %s: %v\n", displayHomePath(entry.Target), err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", displayHomePath(entry.Target))
+	}
+	if err := renders.Save(dotfilesRendersFile()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remember the rendered templates: %v\n", err)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to link %d of %d files", failed, len(entries))
+	}
+	fmt.Printf("✓ Linked %d files\n", len(entries))
+	return nil
+}
+
+func runDotfilesStatus(cmd *cobra.Command, args []string) error {
+	entries, machine, err := loadDotfiles()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No files mapped. Add them to %s.\n", dotfiles.MappingFile)
+		return nil
+	}
+
+	icons := map[dotfiles.State]string{
+		dotfiles.Linked:        "✓",
+		dotfiles.Missing:       "○",
+		dotfiles.Outdated:      "↻",
+		dotfiles.Conflict:      "⚠️",
+		dotfiles.SourceMissing: "❌",
+	}
+
+	renders := dotfiles.LoadRenders(dotfilesRendersFile())
+	pending := 0
+	for _, entry := range entries {
+		state, err := dotfiles.Check(entry, machine, renders)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if state != dotfiles.Linked {
+			pending++
+		}
+		fmt.Printf("  %s %-40s %s\n", icons[state], displayHomePath(entry.Target), state)
+	}
+
+	if pending > 0 {
+		fmt.Printf("ℹ️  %d of %d files need attention, run 'aura dotfiles link'.\n", pending, len(entries))
+	}
+	return nil
+}
+
+// isGitURL reports whether s looks like a Git remote rather than a path.
+func isGitURL(s string) bool {
+	return strings.Contains(s, "://") || strings.HasPrefix(s, "git@")
+}
+
+// displayHomePath shortens paths in the home directory to ~/...
+func displayHomePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+func init() {
+	dotfilesLinkCmd.Flags().BoolVar(&dotfilesForce, "force", false, "Back up and replace existing files")
+
+	dotfilesCmd.AddCommand(dotfilesInitCmd)
+	dotfilesCmd.AddCommand(dotfilesLinkCmd)
+	dotfilesCmd.AddCommand(dotfilesStatusCmd)
+	rootCmd.AddCommand(dotfilesCmd)
+}
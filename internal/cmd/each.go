@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/remote"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var eachCmd = &cobra.Command{
+	Use:   "each [flags] [--] <command>...",
+	Short: "Run a command in several bookmarks",
+	Long: `Run a command in every bookmark matching the filters, several at a time,
+then show a table with the result in each and the output of those that
+failed. Without filters it runs in all bookmarks. Bookmarks on remote hosts
+and missing directories are skipped.
+
+Commands run without input, and Git does not ask for credentials. aura each
+fails if the command failed anywhere.
+
+Examples:
+  aura each --tag work -- git pull
+  aura each --repos -j 8 git fetch --all --prune
+  aura each --under ~/code --output git status --short
+  aura each --in api,web "npm ci && npm test"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEach,
+}
+
+var (
+	eachTag    string
+	eachUnder  string
+	eachIn     []string
+	eachRepos  bool
+	eachJobs   int
+	eachOutput bool
+)
+
+// eachTailSize is how much of the end of the output is kept per bookmark.
+const eachTailSize = 16 * 1024
+
+// eachFailureLines is how many lines of output are shown per failure.
+const eachFailureLines = 10
+
+// eachResult is the outcome of running the command in a bookmark.
+type eachResult struct {
+	Alias    string
+	Dir      string
+	Skipped  string
+	ExitCode int
+	Duration time.Duration
+	Output   string
+}
+
+func runEach(cmd *cobra.Command, args []string) error {
+	if eachJobs < 1 {
+		return failure.New(failure.UserInput, "--jobs must be at least 1")
+	}
+	command := strings.Join(args, " ")
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	bookmarks, err := eachBookmarks(database)
+	if err != nil {
+		return err
+	}
+	if len(bookmarks) == 0 {
+		fmt.Println("ℹ️  No bookmarks match.")
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	spinner := progress.NewSpinner(fmt.Sprintf("Running in %d %s", len(bookmarks), plural(int64(len(bookmarks)), "bookmark", "bookmarks"))).Start()
+	var done atomic.Int32
+	results := runInBookmarks(ctx, bookmarks, command, eachJobs, func() {
+		spinner.Update(fmt.Sprintf("%d of %d done", done.Add(1), len(bookmarks)))
+	})
+	spinner.Stop()
+
+	if eachOutput {
+		for _, result := range results {
+			if result.Skipped == "" && strings.TrimSpace(result.Output) != "" {
+				fmt.Println(theme.Paint(theme.Heading, "→ "+result.Alias))
+				fmt.Println(strings.TrimRight(result.Output, "\n"))
+			}
+		}
+		fmt.Println()
+	}
+	printEachResults(results)
+
+	var failed []eachResult
+	ran := 0
+	for _, result := range results {
+		if result.Skipped == "" {
+			ran++
+			if result.ExitCode != 0 {
+				failed = append(failed, result)
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	for _, result := range failed {
+		fmt.Printf("\n%s\n", theme.Paint(theme.Error, fmt.Sprintf("✗ %s (exit code %d)", result.Alias, result.ExitCode)))
+		for _, line := range lastLines(result.Output, eachFailureLines) {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	fmt.Println()
+	return fmt.Errorf("'%s' failed in %d of %d %s", command, len(failed), ran, plural(int64(ran), "bookmark", "bookmarks"))
+}
+
+// eachBookmarks returns the bookmarks matching all filters given by flags.
+func eachBookmarks(database *db.DB) ([]*db.Bookmark, error) {
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	var selectors []bookmarkSelector
+	if eachTag != "" {
+		selectors = append(selectors, bookmarkSelector{Tag: strings.ToLower(eachTag)})
+	}
+	if eachUnder != "" {
+		under, err := filepath.Abs(eachUnder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve path: %w", err)
+		}
+		selectors = append(selectors, bookmarkSelector{Under: under})
+	}
+	if len(eachIn) > 0 {
+		for _, alias := range eachIn {
+			if bookmark, err := database.GetBookmark(alias); err != nil {
+				return nil, err
+			} else if bookmark == nil {
+				return nil, failure.New(failure.NotFound, "bookmark '%s' not found", alias)
+			}
+		}
+		selectors = append(selectors, bookmarkSelector{Aliases: eachIn})
+	}
+	for _, selector := range selectors {
+		selected, err := selectBookmarks(database, selector)
+		if err != nil {
+			return nil, err
+		}
+		matching := make(map[string]bool)
+		for _, bookmark := range selected {
+			matching[bookmark.Alias] = true
+		}
+		var kept []*db.Bookmark
+		for _, bookmark := range bookmarks {
+			if matching[bookmark.Alias] {
+				kept = append(kept, bookmark)
+			}
+		}
+		bookmarks = kept
+	}
+
+	if eachRepos {
+		var repos []*db.Bookmark
+		for _, bookmark := range bookmarks {
+			if repoRoot(bookmark.Path) != "" {
+				repos = append(repos, bookmark)
+			}
+		}
+		bookmarks = repos
+	}
+	return bookmarks, nil
+}
+
+// runInBookmarks runs command in bookmarks, at most jobs at a time, and
+// returns the results in the order of bookmarks. finished is called after
+// each bookmark.
+func runInBookmarks(ctx context.Context, bookmarks []*db.Bookmark, command string, jobs int, finished func()) []eachResult {
+	results := make([]eachResult, len(bookmarks))
+	var group errgroup.Group
+	group.SetLimit(jobs)
+	for i, bookmark := range bookmarks {
+		group.Go(func() error {
+			results[i] = runInBookmark(ctx, bookmark, command)
+			finished()
+			return nil
+		})
+	}
+	group.Wait()
+	return results
+}
+
+// runInBookmark runs command in the directory of bookmark, without input.
+func runInBookmark(ctx context.Context, bookmark *db.Bookmark, command string) eachResult {
+	result := eachResult{Alias: bookmark.Alias, Dir: bookmark.Path}
+	if remote.IsRemote(bookmark.Path) {
+		result.Skipped = "on a remote host"
+		return result
+	}
+	if info, err := os.Stat(bookmark.Path); err != nil || !info.IsDir() {
+		result.Skipped = "directory missing"
+		return result
+	}
+
+	if err := checkCommand(command, bookmark.Path); err != nil {
+		result.ExitCode, result.Output = -1, err.Error()+"\n"
+		return result
+	}
+
+	output := &outputTail{max: eachTailSize}
+	shell := platform.Current().ShellCommandContext(ctx, command)
+	shell.Dir = bookmark.Path
+	shell.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	shell.Stdout = output
+	shell.Stderr = output
+
+	started := time.Now()
+	err := shell.Run()
+	result.Duration = time.Since(started)
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.ExitCode = -1
+		fmt.Fprintf(output, "failed to run command: %v\n", err)
+	}
+	result.Output = output.String()
+	return result
+}
+
+// printEachResults prints a table with a line per result: its status, the
+// bookmark, the duration and the last line of output.
+func printEachResults(results []eachResult) {
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.Alias))
+	}
+	for _, result := range results {
+		alias := fmt.Sprintf("%-*s", width, result.Alias)
+		switch {
+		case result.Skipped != "":
+			fmt.Printf("%s %s  %6s  %s\n", theme.Paint(theme.Muted, "-"), alias, "", theme.Paint(theme.Muted, "skipped, "+result.Skipped))
+		case result.ExitCode != 0:
+			fmt.Printf("%s %s  %6s  %s\n", theme.Paint(theme.Error, "✗"), alias, shortDuration(result.Duration), truncateLine(lastLine(result.Output), 60))
+		default:
+			fmt.Printf("%s %s  %6s  %s\n", theme.Paint(theme.Success, "✓"), alias, shortDuration(result.Duration), theme.Paint(theme.Muted, truncateLine(lastLine(result.Output), 60)))
+		}
+	}
+}
+
+// lastLines returns the last n non-empty lines of output.
+func lastLines(output string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// lastLine returns the last non-empty line of output, trimmed.
+func lastLine(output string) string {
+	if lines := lastLines(output, 1); len(lines) > 0 {
+		return strings.TrimSpace(lines[0])
+	}
+	return ""
+}
+
+// truncateLine shortens line to at most width characters, ending it with an
+// ellipsis if it was cut.
+func truncateLine(line string, width int) string {
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	return string([]rune(line)[:width-1]) + "…"
+}
+
+func init() {
+	eachCmd.Flags().SetInterspersed(false)
+	eachCmd.Flags().StringVar(&eachTag, "tag", "", "Only bookmarks with this tag, see 'aura bookmark set'")
+	eachCmd.Flags().StringVar(&eachUnder, "under", "", "Only bookmarks under this directory")
+	eachCmd.Flags().StringSliceVar(&eachIn, "in", nil, "Only these bookmarks (comma-separated)")
+	eachCmd.Flags().BoolVar(&eachRepos, "repos", false, "Only bookmarks that are Git repositories")
+	eachCmd.Flags().IntVarP(&eachJobs, "jobs", "j", 4, "Number of bookmarks to run in at a time")
+	eachCmd.Flags().BoolVar(&eachOutput, "output", false, "Show the output of every bookmark")
+	rootCmd.AddCommand(eachCmd)
+}
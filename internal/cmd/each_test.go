@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+func TestRunInBookmarks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	api, web := t.TempDir(), t.TempDir()
+	bookmarks := []*db.Bookmark{
+		{Alias: "api", Path: api},
+		{Alias: "gone", Path: filepath.Join(api, "gone")},
+		{Alias: "web", Path: web},
+	}
+
+	var finished atomic.Int32
+	command := `pwd; if [ "$(pwd)" = "` + web + `" ]; then echo "merge conflict"; exit 2; fi`
+	results := runInBookmarks(context.Background(), bookmarks, command, 2, func() { finished.Add(1) })
+	if finished.Load() != 3 || len(results) != 3 {
+		t.Fatalf("runInBookmarks() finished %d and returned %d results, want 3", finished.Load(), len(results))
+	}
+	if results[0].Alias != "api" || results[0].ExitCode != 0 || lastLine(results[0].Output) != api {
+		t.Errorf("results[0] = %+v, want a success in %s", results[0], api)
+	}
+	if results[1].Skipped != "directory missing" {
+		t.Errorf("results[1] = %+v, want skipped", results[1])
+	}
+	if results[2].ExitCode != 2 || lastLine(results[2].Output) != "merge conflict" {
+		t.Errorf("results[2] = %+v, want exit code 2", results[2])
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	output := "one\r\n\ntwo\n  three  \n\n"
+	if got := lastLines(output, 2); strings.Join(got, "|") != "two|  three  " {
+		t.Errorf("lastLines() = %q", got)
+	}
+	if got := lastLine(output); got != "three" {
+		t.Errorf("lastLine() = %q, want %q", got, "three")
+	}
+	if got := truncateLine("Already up to date.", 10); got != "Already u…" {
+		t.Errorf("truncateLine() = %q", got)
+	}
+}
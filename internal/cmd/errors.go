@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// errorFormat is how errors are printed, "text" or "json". AURA_ERROR_FORMAT
+// sets it for errors found before the flags are parsed.
+var errorFormat string
+
+// usageErrorPrefixes start the messages of cobra's errors about arguments
+// and flags.
+var usageErrorPrefixes = []string{
+	"unknown command",
+	"unknown flag",
+	"unknown shorthand flag",
+	"flag needs an argument",
+	"invalid argument",
+	"accepts ",
+	"requires at least",
+	"requires at most",
+	"required flag",
+}
+
+// classifyError marks cobra's errors about arguments and flags as user input
+// errors. Other errors keep their kind.
+func classifyError(err error) error {
+	if config.IsReadOnlyError(err) && !errors.Is(err, config.ErrReadOnly) {
+		return config.ErrReadOnly
+	}
+	if failure.KindOf(err) != failure.General {
+		return err
+	}
+	for _, prefix := range usageErrorPrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return failure.Wrap(failure.UserInput, err)
+		}
+	}
+	return err
+}
+
+// reportError prints err to stderr in the error format. Text errors about
+// the usage of cmd point to its help.
+func reportError(cmd *cobra.Command, err error) {
+	kind := failure.KindOf(err)
+
+	format := errorFormat
+	if format == "" {
+		format = envvar.ErrorFormat.Get()
+	}
+	if format == "json" {
+		var report struct {
+			Error struct {
+				Kind     failure.Kind `json:"kind"`
+				Message  string       `json:"message"`
+				ExitCode int          `json:"exit_code"`
+			} `json:"error"`
+		}
+		report.Error.Kind = kind
+		report.Error.Message = err.Error()
+		report.Error.ExitCode = failure.KindExitCode(kind)
+		json.NewEncoder(os.Stderr).Encode(report)
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, rootCmd.ErrPrefix(), err.Error())
+	if kind == failure.UserInput && cmd != nil && !strings.Contains(err.Error(), "--help") {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+}
+
+// exitWithError reports err and exits with its exit code.
+func exitWithError(err error) {
+	reportError(nil, err)
+	os.Exit(failure.ExitCode(err))
+}
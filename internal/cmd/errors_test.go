@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind failure.Kind
+	}{
+		{errors.New(`unknown command "x" for "aura"`), failure.UserInput},
+		{errors.New("accepts 1 arg(s), received 2"), failure.UserInput},
+		{errors.New("requires at least 1 arg(s), only received 0"), failure.UserInput},
+		{errors.New("failed to open database: locked"), failure.General},
+		{failure.New(failure.NotFound, "unknown command in bookmark"), failure.NotFound},
+	}
+
+	for _, tt := range tests {
+		if kind := failure.KindOf(classifyError(tt.err)); kind != tt.kind {
+			t.Errorf("classifyError(%q) has kind %s, want %s", tt.err, kind, tt.kind)
+		}
+	}
+}
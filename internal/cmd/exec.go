@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	auracontext "github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/ports"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <command>...",
+	Short: "Run a command and triage its failure with AI",
+	Long: `Run a command in your shell while its output is shown and captured. When it
+fails, the AI assistant is offered the end of the output, the command and
+its environment to explain what went wrong. Likely secrets are masked before
+anything is sent.
+
+Destructive commands such as 'rm -rf' are confirmed first, see the confirm
+setting and --yes.
+
+Commands running longer than the notifications setting (10s by default) or
+--notify-after show a desktop notification when they finish. aura exec exits
+with the exit code of the command.
+
+Examples:
+  aura exec make release
+  aura exec "npm ci && npm run build"
+  aura exec --triage go test ./...          # Triage failures without asking
+  aura exec --notify-after 0 ./deploy.sh    # Always notify`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+var (
+	execTriage      bool
+	execNoTriage    bool
+	execNotifyAfter time.Duration
+	execNoNotify    bool
+	execYes         bool
+)
+
+// execTailSize is how much of the end of the output is kept for triage.
+const execTailSize = 8 * 1024
+
+func runExec(cmd *cobra.Command, args []string) error {
+	command := strings.Join(args, " ")
+	if skipChange("run: %s", command) {
+		return nil
+	}
+	if !confirmAction("exec", fmt.Sprintf("Run '%s'", command), auracontext.IsDestructive(command), execYes) {
+		return fmt.Errorf("'%s' was not run", command)
+	}
+
+	if err := checkCommand(command, ""); err != nil {
+		return err
+	}
+
+	shell := platform.Current().ShellCommand(command)
+	output := &outputTail{max: execTailSize}
+	shell.Stdin = os.Stdin
+	shell.Stdout = &teeWriter{out: os.Stdout, tail: output}
+	shell.Stderr = &teeWriter{out: os.Stderr, tail: output}
+
+	// Ctrl+C reaches the command, which decides how to stop
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	started := time.Now()
+	err := shell.Run()
+	elapsed := time.Since(started).Round(time.Millisecond)
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		return fmt.Errorf("failed to run command: %w", err)
+	}
+
+	title := "✓ Command finished"
+	if exitCode != 0 {
+		title = fmt.Sprintf("✗ Command failed (exit code %d)", exitCode)
+	}
+	message := fmt.Sprintf("%s\nafter %s", command, elapsed)
+	switch {
+	case execNoNotify:
+	case cmd.Flags().Changed("notify-after"):
+		if elapsed >= execNotifyAfter {
+			sendNotification(title, message)
+		}
+	default:
+		notifyFinished("exec", elapsed, title, message)
+	}
+
+	if exitCode == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "\n✗ Command failed with exit code %d after %s\n", exitCode, elapsed)
+	if port, ok := ports.Conflict(output.String()); ok {
+		reportPortConflict(port)
+	}
+	if shouldTriage() {
+		if err := triageFailure(command, output.String(), exitCode, elapsed); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	os.Exit(exitCode)
+	return nil
+}
+
+// shouldTriage reports whether to ask the AI assistant about a failure.
+func shouldTriage() bool {
+	switch {
+	case execNoTriage:
+		return false
+	case execTriage:
+		return true
+	case !term.IsTerminal(int(os.Stdin.Fd())):
+		fmt.Fprintln(os.Stderr, "ℹ️  Run with --triage to let the AI assistant explain the failure")
+		return false
+	}
+	prompt := promptui.Prompt{Label: "Ask the AI assistant what went wrong", IsConfirm: true}
+	_, err := prompt.Run()
+	return err == nil
+}
+
+// triageFailure asks the AI assistant to explain a failed command.
+func triageFailure(command, output string, exitCode int, elapsed time.Duration) error {
+	client, err := newAssistant()
+	if err != nil {
+		return fmt.Errorf("failed to initialize AI client: %w", err)
+	}
+
+	response, err := explainFailure(client, command, output, exitCode, elapsed)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s\n", response)
+	printFallbackNote(client)
+	return nil
+}
+
+// explainFailure asks the AI assistant why command failed, sending its output
+// and environment with likely secrets redacted.
+func explainFailure(client ai.Assistant, command, output string, exitCode int, elapsed time.Duration) (string, error) {
+	cwd, _ := os.Getwd()
+	environment := map[string]string{
+		"os":                platform.Current().OS,
+		"shell":             os.Getenv("SHELL"),
+		"working_directory": cwd,
+		"exit_code":         fmt.Sprint(exitCode),
+		"duration":          elapsed.String(),
+	}
+	for key, value := range environment {
+		environment[key] = scrubSecrets("the environment", value)
+	}
+	command = scrubSecrets("the command", command)
+	output = scrubSecrets("the command output", output)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+
+	response, err := client.DebugIssue(ctx, output, command, environment)
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("AI request failed: %w", err)
+	}
+	return response, nil
+}
+
+// outputTail keeps the last max bytes written to it, from several writers.
+type outputTail struct {
+	mu   sync.Mutex
+	max  int
+	data []byte
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.data = append(t.data, p...)
+	if len(t.data) > t.max {
+		t.data = append(t.data[:0], t.data[len(t.data)-t.max:]...)
+	}
+	return len(p), nil
+}
+
+// String returns the kept output, starting at a full line if it was cut.
+func (t *outputTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	output := string(t.data)
+	if len(t.data) == t.max {
+		if i := strings.IndexByte(output, '\n'); i >= 0 {
+			output = output[i+1:]
+		}
+	}
+	return output
+}
+
+// teeWriter writes to out and keeps a copy in tail.
+type teeWriter struct {
+	out  *os.File
+	tail *outputTail
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	w.tail.Write(p)
+	return w.out.Write(p)
+}
+
+func init() {
+	// Arguments are the command, not flags of aura
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().BoolVar(&execTriage, "triage", false, "Ask the AI assistant about failures without asking first")
+	execCmd.Flags().BoolVar(&execNoTriage, "no-triage", false, "Don't offer AI triage of failures")
+	execCmd.Flags().DurationVar(&execNotifyAfter, "notify-after", 0, "Show a desktop notification when the command ran at least this long (default from the notifications settings)")
+	execCmd.Flags().BoolVar(&execNoNotify, "no-notify", false, "Don't show a desktop notification")
+	execCmd.Flags().BoolVarP(&execYes, "yes", "y", false, "Run without asking, even if the confirm policy asks before the command")
+	rootCmd.AddCommand(execCmd)
+}
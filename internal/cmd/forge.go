@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	auracontext "github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/forge"
+	"github.com/timfewi/aura-cli-go/internal/open"
+)
+
+// forgeQueryTimeout bounds requests to the forge so a slow network never
+// stalls the action menu or a commit.
+const forgeQueryTimeout = 3 * time.Second
+
+// issueBodyLimit is the number of characters of an issue's body given to the
+// AI assistant.
+const issueBodyLimit = 2000
+
+// originForge returns a client for the forge hosting the origin remote of the
+// current repository. It reports false unless the forge is known and a token
+// for it is set, so no request is made without one.
+func originForge() (*forge.Client, bool) {
+	repo, ok := forge.Origin(config.UserSettings.Git.Forges)
+	if !ok {
+		return nil, false
+	}
+	client, err := forge.NewClient(repo)
+	if err != nil {
+		return nil, false
+	}
+	return client, true
+}
+
+// detectForgeActions returns actions for the open issues and pull requests of
+// the current repository. They are left out without a token or if the forge
+// can't be reached.
+func detectForgeActions() []auracontext.Action {
+	client, ok := originForge()
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), forgeQueryTimeout)
+	defer cancel()
+
+	issues, err := client.Open(ctx)
+	if err != nil {
+		return nil
+	}
+	return forgeActions(client.Repo(), issues)
+}
+
+// forgeActions returns actions checking out the pull requests and viewing the
+// issues of repo.
+func forgeActions(repo forge.Repo, issues []forge.Issue) []auracontext.Action {
+	var result []auracontext.Action
+	for _, issue := range issues {
+		if issue.PullRequest {
+			result = append(result, auracontext.Action{
+				Name:    fmt.Sprintf("Checkout PR %s: %s", repo.Reference(issue), issue.Title),
+				Command: repo.CheckoutCommand("origin", issue.Number),
+			})
+			continue
+		}
+		if issue.URL == "" {
+			continue
+		}
+		result = append(result, auracontext.Action{
+			Name:    fmt.Sprintf("View issue %s: %s", repo.Reference(issue), issue.Title),
+			Command: open.ShellCommand(issue.URL),
+		})
+	}
+	return result
+}
+
+// linkedIssueContext returns the title and body of the issue referenced by
+// reference, such as #42, for the AI assistant, or "" if it isn't an issue on
+// the forge or can't be fetched.
+func linkedIssueContext(reference string) string {
+	number, err := strconv.Atoi(strings.TrimPrefix(reference, "#"))
+	if err != nil || !strings.HasPrefix(reference, "#") {
+		return ""
+	}
+	client, ok := originForge()
+	if !ok {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), forgeQueryTimeout)
+	defer cancel()
+
+	linked, err := client.Issue(ctx, number)
+	if err != nil {
+		return ""
+	}
+	return issueContext(reference, linked)
+}
+
+// branchIssueContext returns the linked issue of the current branch for the
+// AI assistant, or "" if there is none.
+func branchIssueContext() string {
+	pattern, _ := issueSettings()
+	issue, err := branchIssue(currentBranch(), pattern)
+	if err != nil || issue == "" {
+		return ""
+	}
+	return linkedIssueContext(issue)
+}
+
+// issueContext formats issue for the AI assistant, cutting long bodies.
+func issueContext(reference string, issue forge.Issue) string {
+	body := strings.TrimSpace(issue.Body)
+	if len(body) > issueBodyLimit {
+		body = strings.TrimSpace(body[:issueBodyLimit]) + "\n[...]"
+	}
+	if body == "" {
+		return fmt.Sprintf("%s %s", reference, issue.Title)
+	}
+	return fmt.Sprintf("%s %s\n\n%s", reference, issue.Title, body)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/forge"
+)
+
+func TestForgeActions(t *testing.T) {
+	repo := forge.Repo{Kind: forge.GitHub, Host: "github.com", Path: "a/b"}
+	actions := forgeActions(repo, []forge.Issue{
+		{Number: 42, Title: "Add open", PullRequest: true},
+		{Number: 101, Title: "Crash", URL: "https://github.com/a/b/issues/101"},
+		{Number: 102, Title: "No URL"},
+	})
+
+	if len(actions) != 2 {
+		t.Fatalf("forgeActions() = %+v, want 2 actions", actions)
+	}
+	if actions[0].Name != "Checkout PR #42: Add open" || actions[0].Command != "git fetch origin pull/42/head:pr-42 && git checkout pr-42" {
+		t.Errorf("actions[0] = %+v", actions[0])
+	}
+	if actions[1].Name != "View issue #101: Crash" || !strings.Contains(actions[1].Command, "https://github.com/a/b/issues/101") {
+		t.Errorf("actions[1] = %+v", actions[1])
+	}
+}
+
+func TestIssueContext(t *testing.T) {
+	if got := issueContext("#7", forge.Issue{Title: "Crash"}); got != "#7 Crash" {
+		t.Errorf("issueContext() = %q", got)
+	}
+	if got := issueContext("#7", forge.Issue{Title: "Crash", Body: " Steps \n"}); got != "#7 Crash\n\nSteps" {
+		t.Errorf("issueContext() = %q", got)
+	}
+	long := issueContext("#7", forge.Issue{Title: "Crash", Body: strings.Repeat("x", issueBodyLimit+10)})
+	if !strings.HasSuffix(long, "\n[...]") || len(long) > issueBodyLimit+20 {
+		t.Errorf("issueContext() of a long body has length %d", len(long))
+	}
+}
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+	"github.com/timfewi/aura-cli-go/internal/testgen"
+)
+
+var genCmd = &cobra.Command{
+	Use:   "gen",
+	Short: "Generate code with the AI assistant",
+	Long:  `Generate code for the project in the current directory with the AI assistant.`,
+}
+
+var genTestsCmd = &cobra.Command{
+	Use:   "tests <file>",
+	Short: "Generate tests for a source file",
+	Long: `Generate table-driven tests for a source file with the AI assistant.
+
+The tests follow the conventions of the project: they are written where its
+tests live, such as foo_test.go next to foo.go, tests/test_foo.py or
+foo.test.ts, with the framework it uses (Go's testing package, pytest or
+unittest, Vitest, Jest, Mocha or node:test) and in the style of an existing
+test file. An existing test file is extended rather than replaced.
+
+The diff of the test file is shown before it is written.
+
+Examples:
+  aura gen tests internal/parser/parser.go
+  aura gen tests src/utils/format.ts --dry-run
+  aura gen tests app/models.py --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGenTests,
+}
+
+var (
+	genYes bool
+)
+
+// generationSourceLimit is the size above which source files are refused,
+// as they would not fit the context of the AI assistant.
+const generationSourceLimit = 64 << 10
+
+// exampleTestLimit is the number of bytes of the example test file given to
+// the AI assistant.
+const exampleTestLimit = 4000
+
+func runGenTests(cmd *cobra.Command, args []string) error {
+	root, file, err := projectFile(args[0])
+	if err != nil {
+		return err
+	}
+	source, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+	if err != nil {
+		return failure.Wrap(failure.NotFound, fmt.Errorf("failed to read %s: %w", args[0], err))
+	}
+	if len(source) > generationSourceLimit {
+		return failure.New(failure.UserInput, "%s is too large, split it up or pick a smaller file", args[0])
+	}
+
+	files, err := projectinfo.ListFiles(root)
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+	target, err := testgen.Plan(root, file, files)
+	if err != nil {
+		return err
+	}
+	existing, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(target.Path)))
+	exists := err == nil
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	tests, err := generateTests(client, file, string(source), testConventions(root, target, string(existing)))
+	if err != nil {
+		return err
+	}
+
+	if err := previewFileDiff(target.Path, existing, exists, tests); err != nil {
+		return err
+	}
+	if config.DryRun() {
+		return nil
+	}
+	verb := "Create"
+	if exists {
+		verb = "Update"
+	}
+	if !confirmAction("gen tests", fmt.Sprintf("%s %s", verb, target.Path), true, genYes) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	testPath := filepath.Join(root, filepath.FromSlash(target.Path))
+	if err := os.MkdirAll(filepath.Dir(testPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(testPath), err)
+	}
+	if err := os.WriteFile(testPath, []byte(tests), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target.Path, err)
+	}
+	fmt.Printf("✓ Wrote tests to %s\n", target.Path)
+	if run := testCommand(target); run != "" {
+		fmt.Printf("ℹ️  Run them with '%s'\n", run)
+	}
+	return nil
+}
+
+// projectFile returns the root of the project around the current directory,
+// the Git repository or else the directory itself, and the slash separated
+// path of name relative to it.
+func projectFile(name string) (root, file string, err error) {
+	root, err = os.Getwd()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if top, err := gitOutput("rev-parse", "--show-toplevel"); err == nil && top != "" {
+		root = top
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s: %w", name, err)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", failure.New(failure.UserInput, "%s is outside of the project in %s", name, root)
+	}
+	return root, filepath.ToSlash(rel), nil
+}
+
+// testConventions describes to the AI assistant how the tests of target are
+// written in the project in root, with the test file to extend if there is
+// one and else an example test of the project.
+func testConventions(root string, target testgen.Target, existing string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Language: %s\nFramework: %s\nTest file: %s\n", target.Language, target.Framework, target.Path)
+	if existing != "" {
+		fmt.Fprintf(&b, "\nThe test file exists. Return it complete, keeping its tests and adding the new ones:\n\n%s\n", existing)
+		return b.String()
+	}
+	if target.Example == "" {
+		return b.String()
+	}
+	example, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(target.Example)))
+	if err != nil {
+		return b.String()
+	}
+	if len(example) > exampleTestLimit {
+		example = example[:exampleTestLimit]
+	}
+	fmt.Fprintf(&b, "\nAn existing test of the project, %s, to follow the style of:\n\n%s\n", target.Example, example)
+	return b.String()
+}
+
+// generateTests asks the AI assistant for the tests of the source of file.
+func generateTests(client ai.Assistant, file, source, conventions string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	tests, err := client.GenerateTests(ctx, file, source, conventions)
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to generate tests: %w", err)
+	}
+	tests = stripCodeFence(tests)
+	if tests == "" {
+		return "", failure.New(failure.API, "the AI assistant returned no tests")
+	}
+	return tests, nil
+}
+
+// stripCodeFence removes the markdown code fence the AI assistant may wrap
+// a file in, and ends the file with a newline.
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		if _, rest, ok := strings.Cut(content, "\n"); ok {
+			content = rest
+		} else {
+			content = ""
+		}
+		content = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(content), "```"))
+	}
+	if content == "" {
+		return ""
+	}
+	return content + "\n"
+}
+
+// previewFileDiff prints the diff from the current content of the file
+// name, if it exists, to the generated content with git diff.
+func previewFileDiff(name string, current []byte, exists bool, generated string) error {
+	dir, err := os.MkdirTemp("", "aura-gen-")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// git diff treats /dev/null as a missing file on every platform.
+	before := "/dev/null"
+	if exists {
+		before = path.Join("a", name)
+		if err := writeTempFile(dir, before, current); err != nil {
+			return err
+		}
+	}
+	after := path.Join("b", name)
+	if err := writeTempFile(dir, after, []byte(generated)); err != nil {
+		return err
+	}
+
+	diff := exec.Command("git", "diff", "--no-index", "--no-prefix", "--", before, after)
+	diff.Dir = dir
+	diff.Stdout = os.Stdout
+	diff.Stderr = os.Stderr
+	// git diff exits with 1 when the files differ.
+	var exitErr *exec.ExitError
+	if err := diff.Run(); err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		fmt.Print(generated)
+	}
+	fmt.Println()
+	return nil
+}
+
+func writeTempFile(dir, name string, content []byte) error {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// testCommand returns the command that runs the tests of target.
+func testCommand(target testgen.Target) string {
+	switch target.Framework {
+	case "testing":
+		if dir := path.Dir(target.Path); dir != "." {
+			return "go test ./" + dir + "/"
+		}
+		return "go test ."
+	case "pytest":
+		return "pytest " + target.Path
+	case "unittest":
+		return "python -m unittest " + target.Path
+	case "Vitest":
+		return "npx vitest run " + target.Path
+	case "Jest":
+		return "npx jest " + target.Path
+	case "Mocha":
+		return "npx mocha " + target.Path
+	case "node:test":
+		return "node --test " + target.Path
+	}
+	return ""
+}
+
+func init() {
+	genTestsCmd.Flags().BoolVarP(&genYes, "yes", "y", false, "Write the test file without asking")
+
+	genCmd.AddCommand(genTestsCmd)
+	rootCmd.AddCommand(genCmd)
+}
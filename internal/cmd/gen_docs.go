@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/docgen"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+	"github.com/timfewi/aura-cli-go/internal/testgen"
+)
+
+var genDocsCmd = &cobra.Command{
+	Use:   "docs <file|package>",
+	Short: "Write doc comments for exported identifiers",
+	Long: `Write or update the doc comments of the exported identifiers of a source
+file, or of every source file of a package directory, with the AI assistant:
+Go doc comments, Python docstrings and JSDoc comments for JavaScript and
+TypeScript.
+
+The AI assistant only returns the comments, which Aura inserts above or
+replaces at each declaration, so the code itself is never rewritten. Existing
+comments are only updated where they are missing something or are wrong;
+with --missing only identifiers without a comment are documented.
+
+The diff of each file is shown before it is written.
+
+Examples:
+  aura gen docs internal/parser/parser.go
+  aura gen docs internal/parser --missing
+  aura gen docs src/utils/format.ts --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGenDocs,
+}
+
+var genDocsMissing bool
+
+func runGenDocs(cmd *cobra.Command, args []string) error {
+	root, target, err := projectFile(args[0])
+	if err != nil {
+		return err
+	}
+	files, err := docFiles(root, target)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	for i, file := range files {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := documentFile(client, root, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// docFiles returns the source files to document for target, a file or a
+// package directory relative to root. Test files of a directory are left
+// out.
+func docFiles(root, target string) ([]string, error) {
+	info, err := os.Stat(filepath.Join(root, filepath.FromSlash(target)))
+	if err != nil {
+		return nil, failure.Wrap(failure.NotFound, fmt.Errorf("failed to read %s: %w", target, err))
+	}
+	if !info.IsDir() {
+		return []string{target}, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(target)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", target, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		file := path.Join(target, entry.Name())
+		language := projectinfo.Language(file)
+		if entry.Type().IsRegular() && docgen.Supported(language) && !testgen.IsTestFile(language, file) {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil, failure.New(failure.UserInput, "no Go, Python, JavaScript or TypeScript files in %s", target)
+	}
+	return files, nil
+}
+
+// documentFile asks the AI assistant for the doc comments of file, shows
+// the diff and writes it once confirmed.
+func documentFile(client ai.Assistant, root, file string) error {
+	filePath := filepath.Join(root, filepath.FromSlash(file))
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if len(src) > generationSourceLimit {
+		fmt.Printf("⚠️  Skipping %s: too large to document\n", file)
+		return nil
+	}
+	symbols, err := docgen.Symbols(file, src)
+	if err != nil {
+		return err
+	}
+	if genDocsMissing {
+		symbols = undocumented(symbols)
+	}
+	if len(symbols) == 0 {
+		fmt.Printf("✓ %s: nothing to document\n", file)
+		return nil
+	}
+
+	docs, err := generateDocs(client, file, string(src), symbols)
+	if err != nil {
+		return err
+	}
+	updated := docgen.Apply(file, src, symbols, docs)
+	if string(updated) == string(src) {
+		fmt.Printf("✓ %s: doc comments are up to date\n", file)
+		return nil
+	}
+
+	if err := previewFileDiff(file, src, true, string(updated)); err != nil {
+		return err
+	}
+	if config.DryRun() {
+		return nil
+	}
+	if !confirmAction("gen docs", fmt.Sprintf("Update %s", file), true, genYes) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	if err := os.WriteFile(filePath, updated, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	fmt.Printf("✓ Updated the doc comments of %s\n", file)
+	return nil
+}
+
+// undocumented returns the symbols without a doc comment.
+func undocumented(symbols []docgen.Symbol) []docgen.Symbol {
+	var result []docgen.Symbol
+	for _, symbol := range symbols {
+		if symbol.Doc == "" {
+			result = append(result, symbol)
+		}
+	}
+	return result
+}
+
+// describeSymbols lists symbols with their current doc comments for the AI
+// assistant.
+func describeSymbols(symbols []docgen.Symbol) string {
+	var b strings.Builder
+	for _, symbol := range symbols {
+		doc := "undocumented"
+		if symbol.Doc != "" {
+			doc = fmt.Sprintf("documented as %q", symbol.Doc)
+		}
+		fmt.Fprintf(&b, "- %s (%s, line %d): %s\n", symbol.Name, symbol.Kind, symbol.Line, doc)
+	}
+	return b.String()
+}
+
+// generateDocs asks the AI assistant for the doc comments of symbols of
+// file, keyed by symbol name.
+func generateDocs(client ai.Assistant, file, source string, symbols []docgen.Symbol) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	response, err := client.DocumentCode(ctx, file, source, describeSymbols(symbols))
+	stopThinking()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate doc comments: %w", err)
+	}
+	return parseDocEdits(response, symbols)
+}
+
+// parseDocEdits parses the doc comments in the JSON answer of the AI
+// assistant, keeping those of symbols.
+func parseDocEdits(response string, symbols []docgen.Symbol) (map[string]string, error) {
+	var answer struct {
+		Docs []struct {
+			Symbol string `json:"symbol"`
+			Doc    string `json:"doc"`
+		} `json:"docs"`
+	}
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start || json.Unmarshal([]byte(response[start:end+1]), &answer) != nil {
+		return nil, failure.New(failure.API, "the AI assistant returned no valid doc comments")
+	}
+
+	known := map[string]bool{}
+	for _, symbol := range symbols {
+		known[symbol.Name] = true
+	}
+	docs := map[string]string{}
+	var unknown []string
+	for _, entry := range answer.Docs {
+		if !known[entry.Symbol] {
+			unknown = append(unknown, entry.Symbol)
+			continue
+		}
+		if doc := strings.TrimSpace(entry.Doc); doc != "" {
+			docs[entry.Symbol] = doc
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		fmt.Fprintf(os.Stderr, "Warning: ignoring doc comments for unknown identifiers: %s\n", strings.Join(unknown, ", "))
+	}
+	return docs, nil
+}
+
+func init() {
+	genDocsCmd.Flags().BoolVar(&genDocsMissing, "missing", false, "Only document identifiers without a doc comment")
+	genDocsCmd.Flags().BoolVarP(&genYes, "yes", "y", false, "Write the files without asking")
+
+	genCmd.AddCommand(genDocsCmd)
+}
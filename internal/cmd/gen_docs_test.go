@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/docgen"
+)
+
+func TestParseDocEdits(t *testing.T) {
+	symbols, err := docgen.Symbols("client.go", []byte("package client\n\ntype Client struct{}\n\nfunc New() *Client { return nil }\n"))
+	if err != nil {
+		t.Fatalf("Symbols() error = %v", err)
+	}
+	tests := []struct {
+		name     string
+		response string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "json in prose",
+			response: "Here you go:\n```json\n{\"docs\": [{\"symbol\": \"New\", \"doc\": \"New returns a Client.\"}, {\"symbol\": \"Old\", \"doc\": \"Gone.\"}]}\n```",
+			want:     map[string]string{"New": "New returns a Client."},
+		},
+		{
+			name:     "empty docs are dropped",
+			response: `{"docs": [{"symbol": "Client", "doc": "  "}]}`,
+			want:     map[string]string{},
+		},
+		{name: "no json", response: "I can't document this.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDocEdits(tt.response, symbols)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDocEdits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseDocEdits() = %v, want %v", got, tt.want)
+			}
+			for name, doc := range tt.want {
+				if got[name] != doc {
+					t.Errorf("parseDocEdits()[%s] = %q, want %q", name, got[name], doc)
+				}
+			}
+		})
+	}
+}
+
+func TestDescribeSymbols(t *testing.T) {
+	symbols, _ := docgen.Symbols("client.go", []byte("package client\n\n// Client talks to the API.\ntype Client struct{}\n\nfunc New() *Client { return nil }\n"))
+	want := "- Client (type, line 4): documented as \"Client talks to the API.\"\n- New (func, line 6): undocumented\n"
+	if got := describeSymbols(symbols); got != want {
+		t.Errorf("describeSymbols() = %q, want %q", got, want)
+	}
+	if got := undocumented(symbols); len(got) != 1 || got[0].Name != "New" {
+		t.Errorf("undocumented() = %+v", got)
+	}
+}
+
+func TestDocFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"pkg/client.go", "pkg/client_test.go", "pkg/README.md", "pkg/util.py", "pkg/sub/x.go"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, nil, 0o644)
+	}
+
+	files, err := docFiles(root, "pkg")
+	if err != nil {
+		t.Fatalf("docFiles() error = %v", err)
+	}
+	if strings.Join(files, ",") != "pkg/client.go,pkg/util.py" {
+		t.Errorf("docFiles() = %v", files)
+	}
+	if _, err := docFiles(root, "pkg/sub/missing.go"); err == nil {
+		t.Error("docFiles() of a missing file error = nil")
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/testgen"
+)
+
+func TestStripCodeFence(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain", "package foo\n\nfunc TestX(t *testing.T) {}", "package foo\n\nfunc TestX(t *testing.T) {}\n"},
+		{"fenced", "```go\npackage foo\n```\n", "package foo\n"},
+		{"fenced without language", "\n```\nimport pytest\n```", "import pytest\n"},
+		{"empty", "```go\n```", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripCodeFence(tt.content); got != tt.want {
+				t.Errorf("stripCodeFence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestCommand(t *testing.T) {
+	tests := []struct {
+		target testgen.Target
+		want   string
+	}{
+		{testgen.Target{Framework: "testing", Path: "internal/ai/client_test.go"}, "go test ./internal/ai/"},
+		{testgen.Target{Framework: "testing", Path: "main_test.go"}, "go test ."},
+		{testgen.Target{Framework: "pytest", Path: "tests/test_models.py"}, "pytest tests/test_models.py"},
+		{testgen.Target{Framework: "Vitest", Path: "src/format.test.ts"}, "npx vitest run src/format.test.ts"},
+	}
+	for _, tt := range tests {
+		if got := testCommand(tt.target); got != tt.want {
+			t.Errorf("testCommand(%+v) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateTests(t *testing.T) {
+	client := &MockAIClient{response: "```go\npackage parser\n\nfunc TestParse(t *testing.T) {}\n```"}
+	tests, err := generateTests(client, "parser.go", "package parser", "Language: Go")
+	if err != nil {
+		t.Fatalf("generateTests() error = %v", err)
+	}
+	if tests != "package parser\n\nfunc TestParse(t *testing.T) {}\n" {
+		t.Errorf("generateTests() = %q", tests)
+	}
+
+	client = &MockAIClient{response: "  "}
+	if _, err := generateTests(client, "parser.go", "package parser", "Language: Go"); err == nil {
+		t.Error("generateTests() of an empty reply error = nil")
+	}
+}
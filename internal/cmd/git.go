@@ -12,6 +12,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/editor"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/theme"
 )
 
 var gitCmd = &cobra.Command{
@@ -27,9 +30,18 @@ var gitCommitCmd = &cobra.Command{
 	
 This command will:
 1. Check for staged changes using 'git diff --staged'
-2. Send the diff to AI for commit message generation
+2. Send the diff to AI for commit message generation, suggesting the module
+   most changed lines are in, such as internal/db, as the scope
 3. Present the suggested commit message for approval
-4. Commit with the approved message`,
+4. Commit with the approved message
+
+Messages reference the issue of the branch, such as ABC-123 in
+feat/ABC-123-login or #42 in fix/42-crash, with a "Refs: ABC-123" trailer.
+Set the pattern and trailer with the git.issue_pattern and git.issue_trailer
+settings, or per repository with 'git config aura.issuePattern' and
+aura.issueTrailer. The git.commit_style setting chooses conventional
+messages, such as "fix(db): handle timeouts", or plain ones, such as
+"Handle database timeouts". A team sets them for a repository in .aura.yaml.`,
 	RunE: runGitCommit,
 }
 
@@ -51,32 +63,17 @@ func runGitCommit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize AI client
-	client, err := ai.NewClient()
+	client, err := newAssistant()
 	if err != nil {
 		return fmt.Errorf("failed to initialize AI client: %w", err)
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	// Show thinking indicator
-	done := make(chan bool)
-	go showThinking(done)
-
-	// Generate commit message
-	commitMessage, err := client.GenerateCommitMessage(ctx, diff)
-	done <- true
-
+	commitMessage, err := suggestCommitMessage(client, diff)
 	if err != nil {
-		return fmt.Errorf("failed to generate commit message: %w", err)
+		return err
 	}
-
-	// Clean up the commit message
-	commitMessage = strings.TrimSpace(commitMessage)
-
-	// Remove any markdown formatting or quotes that might be added
-	commitMessage = strings.Trim(commitMessage, "`\"'")
+	printFallbackNote(client)
+	commitMessage = referenceIssue(commitMessage)
 
 	// Present the commit message for approval
 	fmt.Printf("\nSuggested commit message:\n")
@@ -84,16 +81,20 @@ func runGitCommit(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s\n", commitMessage)
 	fmt.Printf("─────────────────────────────────────\n")
 
+	if skipChange("run: git commit -m %q", commitMessage) {
+		return nil
+	}
+
+	// The suggested message is reviewed unless confirmations are turned off
+	if !needsConfirmation("git commit", true) {
+		return commitWithMessage(commitMessage)
+	}
+
 	// Ask for approval
 	prompt := promptui.Select{
-		Label: "Do you want to use this commit message?",
-		Items: []string{"Yes, commit with this message", "No, let me edit it", "Cancel"},
-		Templates: &promptui.SelectTemplates{
-			Label:    "{{ . }}",
-			Active:   "▸ {{ . | cyan }}",
-			Inactive: "  {{ . | white }}",
-			Selected: "✓ {{ . | green }}",
-		},
+		Label:     "Do you want to use this commit message?",
+		Items:     []string{"Yes, commit with this message", "No, let me edit it", "Cancel"},
+		Templates: commitPromptTemplates(),
 	}
 
 	selectedIndex, _, err := prompt.Run()
@@ -118,6 +119,27 @@ func runGitCommit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// suggestCommitMessage asks the AI assistant for a commit message for the
+// staged diff, suggesting the scope most changes are in and giving the issue
+// of the branch as context, without the quotes or markdown it may add.
+// Likely secrets in the diff are redacted.
+func suggestCommitMessage(client ai.Assistant, diff string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	scope := commitScope(diff)
+	diff = scrubSecrets("the staged diff", diff)
+
+	stopThinking := startThinking()
+	commitMessage, err := client.GenerateCommitMessage(ctx, diff, scope, branchIssueContext())
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to generate commit message: %w", err)
+	}
+	return strings.Trim(strings.TrimSpace(commitMessage), "`\"'"), nil
+}
+
 func isGitRepository() bool {
 	cmd := exec.Command("git", "rev-parse", "--git-dir")
 	cmd.Stderr = nil
@@ -134,6 +156,9 @@ func getStagedDiff() (string, error) {
 }
 
 func commitWithMessage(message string) error {
+	if err := checkCommand(fmt.Sprintf("git commit -m %q", message), ""); err != nil {
+		return err
+	}
 	cmd := exec.Command("git", "commit", "-m", message)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -159,19 +184,8 @@ func editAndCommit(originalMessage string) error {
 	}
 	tempFile.Close()
 
-	// Get editor from environment or use default
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = getDefaultEditor()
-	}
-
-	// Open editor
-	cmd := exec.Command(editor, tempFile.Name())
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
+	// Open editor and wait until the message is edited
+	if err := editor.Open(getDefaultEditor(), tempFile.Name(), true); err != nil {
 		return fmt.Errorf("editor failed: %w", err)
 	}
 
@@ -190,26 +204,25 @@ func editAndCommit(originalMessage string) error {
 	return commitWithMessage(message)
 }
 
-func getDefaultEditor() string {
-	// Check common environment variables
-	for _, env := range []string{"VISUAL", "EDITOR"} {
-		if editor := os.Getenv(env); editor != "" {
-			return editor
-		}
-	}
+// commitPromptTemplates returns the templates of the commit message prompt,
+// whose label is a question already.
+func commitPromptTemplates() *promptui.SelectTemplates {
+	templates := theme.SelectTemplates(".")
+	templates.Label = "{{ . }}"
+	return templates
+}
 
-	// Platform-specific defaults
-	switch {
-	case isWindows():
-		return "notepad"
-	case isMacOS():
-		return "nano"
-	default:
-		return "nano"
+func getDefaultEditor() string {
+	if command := editor.Resolve(commitEditor); command != "" {
+		return command
 	}
+	return platform.Current().FallbackEditor()
 }
 
+var commitEditor string
+
 func init() {
+	gitCommitCmd.Flags().StringVar(&commitEditor, "editor", "", "Editor to edit the commit message in")
 	gitCmd.AddCommand(gitCommitCmd)
 	rootCmd.AddCommand(gitCmd)
 }
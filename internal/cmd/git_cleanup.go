@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+var gitCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete merged and stale branches",
+	Long: `List the local branches that are merged into the default branch, whose
+upstream is gone, or whose last commit is older than --stale-days, with the
+age of their last commit and the state of their upstream. Pick the branches
+to delete, merged ones are picked already, and confirm to delete them
+locally, and with --remote on their remote too.
+
+The current and the default branch are never listed.
+
+Without a terminal the branches are only listed, unless --yes deletes the
+merged ones.
+
+Examples:
+  aura git cleanup
+  aura git cleanup --remote          # Delete the branches on their remote too
+  aura git cleanup --stale-days 30
+  aura git cleanup --dry-run         # Only list the branches`,
+	Args: cobra.NoArgs,
+	RunE: runGitCleanup,
+}
+
+var (
+	cleanupRemote    bool
+	cleanupStaleDays int
+	cleanupYes       bool
+)
+
+// protectedBranches are never offered for deletion.
+var protectedBranches = []string{"main", "master", "develop", "trunk"}
+
+// cleanupBranch is a local branch that may be deleted.
+type cleanupBranch struct {
+	Name       string
+	LastCommit time.Time
+	// Age is the age of the last commit, such as "3 months ago".
+	Age string
+	// Upstream is the tracked branch, such as origin/feat/login, or "".
+	Upstream string
+	// Remote is the remote of the upstream and RemoteBranch its name there.
+	Remote       string
+	RemoteBranch string
+	// Track is the state of the upstream, such as "[gone]" or "[ahead 2]".
+	Track  string
+	Merged bool
+}
+
+// gone reports whether the upstream of the branch was deleted.
+func (b cleanupBranch) gone() bool {
+	return b.Track == "[gone]"
+}
+
+// status describes why the branch can go and the state of its upstream.
+func (b cleanupBranch) status() string {
+	var parts []string
+	if b.Merged {
+		parts = append(parts, "merged")
+	}
+	switch {
+	case b.Upstream == "":
+		parts = append(parts, "no upstream")
+	case b.gone():
+		parts = append(parts, "upstream gone")
+	case b.Track != "":
+		parts = append(parts, strings.Trim(b.Track, "[]")+" of "+b.Upstream)
+	default:
+		parts = append(parts, "in sync with "+b.Upstream)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// branchRefFormat separates the fields of `git for-each-ref` with unit
+// separators.
+const branchRefFormat = "--format=%(refname:short)%1f%(committerdate:unix)%1f%(committerdate:relative)%1f%(upstream:short)%1f%(upstream:remotename)%1f%(upstream:remoteref)%1f%(upstream:track)"
+
+func runGitCleanup(cmd *cobra.Command, args []string) error {
+	if !isGitRepository() {
+		return failure.New(failure.UserInput, "not a git repository")
+	}
+	if cleanupStaleDays < 1 {
+		return failure.New(failure.UserInput, "--stale-days must be at least 1")
+	}
+	// git push runs on its own, outside the offline transport
+	if cleanupRemote {
+		if err := config.RequireNetwork("aura git cleanup --remote"); err != nil {
+			return err
+		}
+	}
+
+	base := defaultBranch()
+	output, err := exec.Command("git", "for-each-ref", "refs/heads", branchRefFormat).Output()
+	if err != nil {
+		return fmt.Errorf("failed to list branches: %w", err)
+	}
+	merged, err := exec.Command("git", "branch", "--merged", base, "--format=%(refname:short)").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list branches merged into %s: %w", base, err)
+	}
+
+	protected := append([]string{base, currentBranch()}, protectedBranches...)
+	branches := cleanupCandidates(parseBranchRefs(string(output)), strings.Fields(string(merged)), protected, time.Now(), cleanupStaleDays)
+	if len(branches) == 0 {
+		fmt.Println("✓ No merged or stale branches, nothing to clean up")
+		return nil
+	}
+
+	interactive := term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+	if config.DryRun() || !interactive && !cleanupYes {
+		fmt.Printf("Branches merged into %s or stale:\n", base)
+		for _, branch := range branches {
+			fmt.Println("  " + cleanupLabel(branch))
+		}
+		return nil
+	}
+
+	selected := make([]bool, len(branches))
+	for i, branch := range branches {
+		selected[i] = branch.Merged
+	}
+	if interactive && !cleanupYes {
+		if selected, err = pickBranches(branches, selected); err != nil {
+			if errors.Is(err, promptui.ErrInterrupt) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+	}
+
+	var doomed []cleanupBranch
+	for i, branch := range branches {
+		if selected[i] {
+			doomed = append(doomed, branch)
+		}
+	}
+	if len(doomed) == 0 {
+		fmt.Println("No branches selected.")
+		return nil
+	}
+
+	label := fmt.Sprintf("Delete %d branches locally", len(doomed))
+	if cleanupRemote {
+		label = fmt.Sprintf("Delete %d branches locally and on their remote", len(doomed))
+	}
+	if !confirmAction("git cleanup", label, true, cleanupYes) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	return deleteBranches(doomed, cleanupRemote)
+}
+
+// defaultBranch returns the default branch: the HEAD of origin, main or
+// master, or else the current branch.
+func defaultBranch() string {
+	if output, err := exec.Command("git", "symbolic-ref", "--short", "-q", "refs/remotes/origin/HEAD").Output(); err == nil {
+		if _, branch, ok := strings.Cut(strings.TrimSpace(string(output)), "/"); ok {
+			return branch
+		}
+	}
+	for _, branch := range []string{"main", "master"} {
+		if exec.Command("git", "rev-parse", "--verify", "-q", "refs/heads/"+branch).Run() == nil {
+			return branch
+		}
+	}
+	return currentBranch()
+}
+
+// parseBranchRefs parses the output of `git for-each-ref` in branchRefFormat.
+func parseBranchRefs(output string) []cleanupBranch {
+	var branches []cleanupBranch
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 7 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(fields[1], 10, 64)
+		branches = append(branches, cleanupBranch{
+			Name:         fields[0],
+			LastCommit:   time.Unix(unix, 0),
+			Age:          fields[2],
+			Upstream:     fields[3],
+			Remote:       fields[4],
+			RemoteBranch: strings.TrimPrefix(fields[5], "refs/heads/"),
+			Track:        fields[6],
+		})
+	}
+	return branches
+}
+
+// cleanupCandidates returns the branches that are merged, whose upstream is
+// gone or whose last commit is older than staleDays, leaving out the
+// protected ones. Merged branches come first, then the oldest.
+func cleanupCandidates(branches []cleanupBranch, merged, protected []string, now time.Time, staleDays int) []cleanupBranch {
+	isMerged := map[string]bool{}
+	for _, name := range merged {
+		isMerged[name] = true
+	}
+	isProtected := map[string]bool{}
+	for _, name := range protected {
+		isProtected[name] = true
+	}
+
+	staleBefore := now.AddDate(0, 0, -staleDays)
+	var candidates []cleanupBranch
+	for _, branch := range branches {
+		if isProtected[branch.Name] {
+			continue
+		}
+		branch.Merged = isMerged[branch.Name]
+		if branch.Merged || branch.gone() || branch.LastCommit.Before(staleBefore) {
+			candidates = append(candidates, branch)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Merged != candidates[j].Merged {
+			return candidates[i].Merged
+		}
+		return candidates[i].LastCommit.Before(candidates[j].LastCommit)
+	})
+	return candidates
+}
+
+// cleanupLabel returns the line listing branch.
+func cleanupLabel(branch cleanupBranch) string {
+	return fmt.Sprintf("%-30s %-16s %s", branch.Name, branch.Age, branch.status())
+}
+
+// pickBranches lets the user toggle the branches to delete, starting with
+// selected, until they are done.
+func pickBranches(branches []cleanupBranch, selected []bool) ([]bool, error) {
+	cursor := 0
+	for {
+		items := make([]string, 0, len(branches)+2)
+		for i, branch := range branches {
+			box := "[ ]"
+			if selected[i] {
+				box = "[x]"
+			}
+			items = append(items, box+" "+cleanupLabel(branch))
+		}
+		items = append(items, "Delete selected", "Cancel")
+
+		prompt := promptui.Select{
+			Label:     "Toggle branches to delete",
+			Items:     items,
+			Size:      15,
+			CursorPos: cursor,
+		}
+		index, _, err := prompt.Run()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case index == len(branches):
+			return selected, nil
+		case index > len(branches):
+			return nil, promptui.ErrInterrupt
+		}
+		selected[index] = !selected[index]
+		cursor = index
+	}
+}
+
+// deleteBranches deletes branches locally, forcing it for unmerged ones, and
+// on their remote if remote is set and the upstream still exists. It goes on
+// after failures and returns the first.
+func deleteBranches(branches []cleanupBranch, remote bool) error {
+	var firstErr error
+	for _, branch := range branches {
+		flag := "-d"
+		if !branch.Merged {
+			flag = "-D"
+		}
+		if err := runGit(nil, "branch", "-q", flag, branch.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete %s: %v\n", branch.Name, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete %s: %w", branch.Name, err)
+			}
+			continue
+		}
+		fmt.Printf("✓ Deleted %s\n", branch.Name)
+
+		if !remote || branch.Remote == "" || branch.gone() {
+			continue
+		}
+		if err := runGit(nil, "push", "-q", branch.Remote, "--delete", branch.RemoteBranch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete %s on %s: %v\n", branch.RemoteBranch, branch.Remote, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete %s on %s: %w", branch.RemoteBranch, branch.Remote, err)
+			}
+			continue
+		}
+		fmt.Printf("✓ Deleted %s on %s\n", branch.RemoteBranch, branch.Remote)
+	}
+	return firstErr
+}
+
+func init() {
+	gitCleanupCmd.Flags().BoolVar(&cleanupRemote, "remote", false, "Delete the branches on their remote too")
+	gitCleanupCmd.Flags().IntVar(&cleanupStaleDays, "stale-days", 90, "Days without commits after which a branch is stale")
+	gitCleanupCmd.Flags().BoolVarP(&cleanupYes, "yes", "y", false, "Delete the merged branches without asking")
+	gitCmd.AddCommand(gitCleanupCmd)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCleanupCandidates(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	branches := []cleanupBranch{
+		{Name: "main", LastCommit: now.AddDate(-1, 0, 0)},
+		{Name: "feat/fresh", LastCommit: now.AddDate(0, 0, -2), Upstream: "origin/feat/fresh"},
+		{Name: "feat/old", LastCommit: now.AddDate(0, 0, -200)},
+		{Name: "feat/older", LastCommit: now.AddDate(0, 0, -300)},
+		{Name: "fix/gone", LastCommit: now.AddDate(0, 0, -1), Upstream: "origin/fix/gone", Track: "[gone]"},
+		{Name: "feat/done", LastCommit: now.AddDate(0, 0, -1), Upstream: "origin/feat/done", Track: "[behind 1]"},
+	}
+
+	candidates := cleanupCandidates(branches, []string{"main", "feat/done"}, []string{"main"}, now, 90)
+	var names []string
+	for _, branch := range candidates {
+		names = append(names, branch.Name)
+	}
+	if got, want := strings.Join(names, " "), "feat/done feat/older feat/old fix/gone"; got != want {
+		t.Errorf("cleanupCandidates() = %s, want %s", got, want)
+	}
+
+	for branch, want := range map[string]string{
+		"feat/done":  "merged, behind 1 of origin/feat/done",
+		"feat/older": "no upstream",
+		"fix/gone":   "upstream gone",
+	} {
+		for _, candidate := range candidates {
+			if candidate.Name == branch && candidate.status() != want {
+				t.Errorf("status() of %s = %q, want %q", branch, candidate.status(), want)
+			}
+		}
+	}
+}
+
+func TestParseBranchRefs(t *testing.T) {
+	output := "feat/login\x1f1717200000\x1f3 days ago\x1forigin/feat/login\x1forigin\x1frefs/heads/feat/login\x1f[ahead 2]\n" +
+		"local\x1f1717200000\x1f4 weeks ago\x1f\x1f\x1f\x1f\n"
+
+	branches := parseBranchRefs(output)
+	if len(branches) != 2 {
+		t.Fatalf("parseBranchRefs() = %+v, want 2 branches", branches)
+	}
+	login := branches[0]
+	if login.Name != "feat/login" || login.Remote != "origin" || login.RemoteBranch != "feat/login" || login.Track != "[ahead 2]" || login.LastCommit.Unix() != 1717200000 {
+		t.Errorf("branches[0] = %+v", login)
+	}
+	if branches[1].Upstream != "" || branches[1].Age != "4 weeks ago" {
+		t.Errorf("branches[1] = %+v", branches[1])
+	}
+}
+
+func TestDeleteBranches(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalDir)
+
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	git("config", "commit.gpgsign", "false")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+	git("branch", "done")
+	git("checkout", "-q", "-b", "wip")
+	git("commit", "-q", "--allow-empty", "-m", "unmerged work")
+	git("checkout", "-q", "main")
+
+	branches := parseBranchRefs(git("for-each-ref", "refs/heads", branchRefFormat))
+	merged := strings.Fields(git("branch", "--merged", defaultBranch(), "--format=%(refname:short)"))
+	candidates := cleanupCandidates(branches, merged, []string{"main"}, time.Now().AddDate(1, 0, 0), 90)
+	if len(candidates) != 2 || candidates[0].Name != "done" || !candidates[0].Merged || candidates[1].Merged {
+		t.Fatalf("cleanupCandidates() = %+v", candidates)
+	}
+
+	if err := deleteBranches(candidates, true); err != nil {
+		t.Fatalf("deleteBranches() error = %v", err)
+	}
+	if got := strings.TrimSpace(git("branch", "--format=%(refname:short)")); got != "main" {
+		t.Errorf("Branches left = %q, want main", got)
+	}
+}
@@ -0,0 +1,468 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+var gitHealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Summarize the health of the repository",
+	Long: `Report on the state of the repository in one place:
+
+- how far the branch diverged from its upstream and the default branch
+- uncommitted changes and stashes
+- large files in the history
+- files of the project types found that .gitignore doesn't ignore, such as
+  node_modules or .env
+- unsigned commits among the last ones
+
+With --plan the AI assistant writes a remediation plan for the problems found.
+
+Examples:
+  aura git health
+  aura git health --plan
+  aura git health --large-size 1`,
+	Args: cobra.NoArgs,
+	RunE: runGitHealth,
+}
+
+var (
+	healthPlan      bool
+	healthLargeSize int
+)
+
+// signatureWindow is the number of recent commits checked for signatures.
+const signatureWindow = 50
+
+// largeFileLimit is the number of large files listed.
+const largeFileLimit = 5
+
+// healthCheck is the result of one check of `aura git health`.
+type healthCheck struct {
+	Name    string
+	OK      bool
+	Summary string
+	Details []string
+}
+
+// ignoreExpectation is a path .gitignore should ignore when a project
+// marker exists in the repository root.
+type ignoreExpectation struct {
+	marker  string
+	path    string
+	pattern string
+}
+
+var ignoreExpectations = []ignoreExpectation{
+	{"package.json", "node_modules/x", "node_modules/"},
+	{"requirements.txt", "__pycache__/x.pyc", "__pycache__/"},
+	{"pyproject.toml", "__pycache__/x.pyc", "__pycache__/"},
+	{".venv", ".venv/x", ".venv/"},
+	{"Cargo.toml", "target/x", "target/"},
+	{"composer.json", "vendor/x", "vendor/"},
+	{".env", ".env", ".env"},
+	{".terraform", ".terraform/x", ".terraform/"},
+}
+
+func runGitHealth(cmd *cobra.Command, args []string) error {
+	if !isGitRepository() {
+		return failure.New(failure.UserInput, "not a git repository")
+	}
+	if healthLargeSize < 1 {
+		return failure.New(failure.UserInput, "--large-size must be at least 1")
+	}
+
+	checks := []healthCheck{
+		checkDivergence(),
+		checkWorkingTree(),
+		checkStashes(),
+		checkLargeFiles(int64(healthLargeSize) << 20),
+		checkGitignore(),
+		checkSignatures(),
+	}
+
+	report := formatHealthReport(checks)
+	fmt.Print(report)
+
+	problems := 0
+	for _, check := range checks {
+		if !check.OK {
+			problems++
+		}
+	}
+	if problems == 0 {
+		fmt.Println("\n✓ The repository is healthy")
+		return nil
+	}
+	if !healthPlan {
+		fmt.Println("\nℹ️  Run 'aura git health --plan' for a remediation plan")
+		return nil
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	plan, err := remediationPlan(client, report)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", plan)
+	return nil
+}
+
+// remediationPlan asks the AI assistant how to fix the problems of report.
+func remediationPlan(client ai.Assistant, report string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	plan, err := client.RepoHealthPlan(ctx, report)
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get a remediation plan: %w", err)
+	}
+	return plan, nil
+}
+
+// formatHealthReport lists the checks with their details.
+func formatHealthReport(checks []healthCheck) string {
+	var b strings.Builder
+	for _, check := range checks {
+		marker := "✓ "
+		if !check.OK {
+			marker = "⚠️ "
+		}
+		fmt.Fprintf(&b, "%s %-12s %s\n", marker, check.Name, check.Summary)
+		for _, detail := range check.Details {
+			fmt.Fprintf(&b, "     %s\n", detail)
+		}
+	}
+	return b.String()
+}
+
+// gitOutput runs git with args and returns its trimmed output.
+func gitOutput(args ...string) (string, error) {
+	output, err := exec.Command("git", args...).Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+// checkDivergence compares the current branch with its upstream and the
+// default branch.
+func checkDivergence() healthCheck {
+	check := healthCheck{Name: "Branch", OK: true}
+	branch := currentBranch()
+	if branch == "" {
+		check.OK = false
+		check.Summary = "HEAD is detached"
+		return check
+	}
+
+	upstream, err := gitOutput("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		check.OK = false
+		check.Summary = branch + " has no upstream, push it with 'git push -u'"
+	} else {
+		behind, ahead := aheadBehind(upstream)
+		check.Summary = divergence(branch, upstream, ahead, behind)
+		check.OK = behind == 0
+	}
+
+	if base := defaultBranch(); base != "" && base != branch {
+		behind, ahead := aheadBehind(base)
+		check.Details = append(check.Details, divergence(branch, base, ahead, behind))
+	}
+	return check
+}
+
+// aheadBehind returns how many commits ref has that HEAD lacks and the other
+// way around.
+func aheadBehind(ref string) (behind, ahead int) {
+	output, err := gitOutput("rev-list", "--left-right", "--count", ref+"...HEAD")
+	if err != nil {
+		return 0, 0
+	}
+	fmt.Sscan(output, &behind, &ahead)
+	return behind, ahead
+}
+
+// divergence describes how branch relates to ref.
+func divergence(branch, ref string, ahead, behind int) string {
+	switch {
+	case ahead == 0 && behind == 0:
+		return fmt.Sprintf("%s is in sync with %s", branch, ref)
+	case behind == 0:
+		return fmt.Sprintf("%s is %d ahead of %s", branch, ahead, ref)
+	case ahead == 0:
+		return fmt.Sprintf("%s is %d behind %s", branch, behind, ref)
+	}
+	return fmt.Sprintf("%s diverged from %s: %d ahead, %d behind", branch, ref, ahead, behind)
+}
+
+// statusCounts counts the entries of `git status --porcelain`.
+type statusCounts struct {
+	Staged, Modified, Untracked, Conflicts int
+}
+
+// parseStatusCounts counts the changes in the output of
+// `git status --porcelain`.
+func parseStatusCounts(output string) statusCounts {
+	var counts statusCounts
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		x, y := line[0], line[1]
+		switch {
+		case x == '?':
+			counts.Untracked++
+		case x == 'U' || y == 'U' || x == 'A' && y == 'A' || x == 'D' && y == 'D':
+			counts.Conflicts++
+		default:
+			if x != ' ' {
+				counts.Staged++
+			}
+			if y != ' ' {
+				counts.Modified++
+			}
+		}
+	}
+	return counts
+}
+
+// checkWorkingTree reports uncommitted changes.
+func checkWorkingTree() healthCheck {
+	check := healthCheck{Name: "Changes", OK: true}
+	output, err := exec.Command("git", "status", "--porcelain").Output()
+	if err != nil {
+		check.Summary = "unknown, git status failed"
+		return check
+	}
+
+	counts := parseStatusCounts(string(output))
+	var parts []string
+	for _, part := range []struct {
+		count int
+		label string
+	}{
+		{counts.Conflicts, "conflicted"},
+		{counts.Staged, "staged"},
+		{counts.Modified, "modified"},
+		{counts.Untracked, "untracked"},
+	} {
+		if part.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", part.count, part.label))
+		}
+	}
+	if len(parts) == 0 {
+		check.Summary = "working tree clean"
+		return check
+	}
+	check.OK = counts.Conflicts == 0 && counts.Staged+counts.Modified == 0
+	check.Summary = strings.Join(parts, ", ")
+	return check
+}
+
+// checkStashes reports the stashes and the age of the oldest.
+func checkStashes() healthCheck {
+	check := healthCheck{Name: "Stashes", OK: true}
+	output, err := exec.Command("git", "stash", "list", stashListFormat).Output()
+	if err != nil {
+		check.Summary = "unknown, git stash list failed"
+		return check
+	}
+	stashes := parseStashList(string(output))
+	if len(stashes) == 0 {
+		check.Summary = "none"
+		return check
+	}
+
+	check.OK = false
+	oldest := stashes[len(stashes)-1]
+	check.Summary = fmt.Sprintf("%d, the oldest from %s, review them with 'aura git stash'", len(stashes), oldest.Age)
+	return check
+}
+
+// largeBlob is a file in the history of the repository.
+type largeBlob struct {
+	Path string
+	Size int64
+}
+
+// parseLargeBlobs returns the blobs of at least threshold bytes in the output
+// of `git cat-file --batch-check='%(objecttype) %(objectsize) %(rest)'`,
+// largest first and each path once.
+func parseLargeBlobs(output string, threshold int64) []largeBlob {
+	largest := map[string]int64{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 || fields[0] != "blob" || fields[2] == "" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || size < threshold {
+			continue
+		}
+		if size > largest[fields[2]] {
+			largest[fields[2]] = size
+		}
+	}
+
+	blobs := make([]largeBlob, 0, len(largest))
+	for path, size := range largest {
+		blobs = append(blobs, largeBlob{Path: path, Size: size})
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		if blobs[i].Size != blobs[j].Size {
+			return blobs[i].Size > blobs[j].Size
+		}
+		return blobs[i].Path < blobs[j].Path
+	})
+	return blobs
+}
+
+// historyBlobs returns the files of at least threshold bytes in the history
+// of all branches, largest first. A repository without commits has none.
+func historyBlobs(threshold int64) ([]largeBlob, error) {
+	if exec.Command("git", "rev-parse", "--verify", "-q", "HEAD").Run() != nil {
+		return nil, nil
+	}
+	objects, err := exec.Command("git", "rev-list", "--objects", "--all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	catFile := exec.Command("git", "cat-file", "--batch-check=%(objecttype) %(objectsize) %(rest)")
+	catFile.Stdin = bytes.NewReader(objects)
+	output, err := catFile.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file failed: %w", err)
+	}
+	return parseLargeBlobs(string(output), threshold), nil
+}
+
+// checkLargeFiles reports files of at least threshold bytes in the history.
+func checkLargeFiles(threshold int64) healthCheck {
+	check := healthCheck{Name: "Large files", OK: true}
+	blobs, err := historyBlobs(threshold)
+	if err != nil {
+		check.Summary = "unknown, " + err.Error()
+		return check
+	}
+	if len(blobs) == 0 {
+		check.Summary = fmt.Sprintf("none of %s or more in the history", formatSize(threshold))
+		return check
+	}
+	check.OK = false
+	check.Summary = fmt.Sprintf("%d files of %s or more in the history", len(blobs), formatSize(threshold))
+	for i, blob := range blobs {
+		if i == largeFileLimit {
+			check.Details = append(check.Details, fmt.Sprintf("and %d more", len(blobs)-largeFileLimit))
+			break
+		}
+		check.Details = append(check.Details, fmt.Sprintf("%-8s %s", formatSize(blob.Size), blob.Path))
+	}
+	return check
+}
+
+// checkGitignore reports the patterns .gitignore lacks for the project types
+// found in the repository root.
+func checkGitignore() healthCheck {
+	check := healthCheck{Name: ".gitignore", OK: true}
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		check.Summary = "unknown, the repository root wasn't found"
+		return check
+	}
+
+	missing := missingIgnorePatterns(root)
+	if len(missing) == 0 {
+		check.Summary = "covers the project types found"
+		return check
+	}
+	check.OK = false
+	check.Summary = "missing " + strings.Join(missing, ", ")
+	return check
+}
+
+// missingIgnorePatterns returns the patterns of ignoreExpectations whose
+// marker exists in root but whose path Git doesn't ignore.
+func missingIgnorePatterns(root string) []string {
+	var missing []string
+	seen := map[string]bool{}
+	for _, expectation := range ignoreExpectations {
+		if seen[expectation.pattern] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, expectation.marker)); err != nil {
+			continue
+		}
+		seen[expectation.pattern] = true
+
+		checkIgnore := exec.Command("git", "check-ignore", "-q", "--no-index", expectation.path)
+		checkIgnore.Dir = root
+		if err := checkIgnore.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+				missing = append(missing, expectation.pattern)
+			}
+		}
+	}
+	return missing
+}
+
+// countUnsigned counts the unsigned commits in the output of
+// `git log --format=%G?`, and returns the number of commits.
+func countUnsigned(output string) (unsigned, total int) {
+	for _, status := range strings.Fields(output) {
+		total++
+		if status == "N" {
+			unsigned++
+		}
+	}
+	return unsigned, total
+}
+
+// checkSignatures reports unsigned commits among the recent ones. They are a
+// problem when commits are signed by configuration or some of them are.
+func checkSignatures() healthCheck {
+	check := healthCheck{Name: "Signatures", OK: true}
+	output, err := gitOutput("log", "-n", strconv.Itoa(signatureWindow), "--format=%G?")
+	if err != nil {
+		check.Summary = "none, the repository has no commits"
+		return check
+	}
+
+	unsigned, total := countUnsigned(output)
+	switch {
+	case unsigned == 0:
+		check.Summary = fmt.Sprintf("all of the last %d commits are signed", total)
+	case unsigned == total && gitConfig("commit.gpgsign") != "true":
+		check.Summary = "commits aren't signed"
+	default:
+		check.OK = false
+		check.Summary = fmt.Sprintf("%d of the last %d commits are unsigned", unsigned, total)
+	}
+	return check
+}
+
+func init() {
+	gitHealthCmd.Flags().BoolVar(&healthPlan, "plan", false, "Ask the AI assistant for a remediation plan")
+	gitHealthCmd.Flags().IntVar(&healthLargeSize, "large-size", 5, "Size in MiB from which files in the history are large")
+	gitCmd.AddCommand(gitHealthCmd)
+}
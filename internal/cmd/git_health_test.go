@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseStatusCounts(t *testing.T) {
+	output := "M  staged.go\n" +
+		" M modified.go\n" +
+		"MM both.go\n" +
+		"UU conflict.go\n" +
+		"AA added-twice.go\n" +
+		"?? new.txt\n" +
+		"?? other.txt\n"
+
+	want := statusCounts{Staged: 2, Modified: 2, Untracked: 2, Conflicts: 2}
+	if got := parseStatusCounts(output); got != want {
+		t.Errorf("parseStatusCounts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseLargeBlobs(t *testing.T) {
+	output := "commit 900 \n" +
+		"tree 300 internal\n" +
+		"blob 100 README.md\n" +
+		"blob 6000000 assets/video.mp4\n" +
+		"blob 8000000 assets/video.mp4\n" +
+		"blob 7000000 dump.sql\n" +
+		"blob 9000000 \n"
+
+	blobs := parseLargeBlobs(output, 5<<20)
+	if len(blobs) != 2 || blobs[0] != (largeBlob{"assets/video.mp4", 8000000}) || blobs[1] != (largeBlob{"dump.sql", 7000000}) {
+		t.Errorf("parseLargeBlobs() = %+v", blobs)
+	}
+}
+
+func TestCountUnsigned(t *testing.T) {
+	unsigned, total := countUnsigned("G\nN\nN\nU\n")
+	if unsigned != 2 || total != 4 {
+		t.Errorf("countUnsigned() = %d, %d, want 2, 4", unsigned, total)
+	}
+}
+
+func TestDivergence(t *testing.T) {
+	tests := []struct {
+		ahead, behind int
+		want          string
+	}{
+		{0, 0, "main is in sync with origin/main"},
+		{2, 0, "main is 2 ahead of origin/main"},
+		{0, 3, "main is 3 behind origin/main"},
+		{2, 3, "main diverged from origin/main: 2 ahead, 3 behind"},
+	}
+	for _, tt := range tests {
+		if got := divergence("main", "origin/main", tt.ahead, tt.behind); got != tt.want {
+			t.Errorf("divergence(%d, %d) = %q, want %q", tt.ahead, tt.behind, got, tt.want)
+		}
+	}
+}
+
+func TestMissingIgnorePatterns(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+	for name, content := range map[string]string{
+		"package.json":   "{}",
+		"pyproject.toml": "",
+		".env":           "TOKEN=x",
+		".gitignore":     "node_modules/\n",
+	} {
+		os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+	}
+
+	if got, want := strings.Join(missingIgnorePatterns(dir), " "), "__pycache__/ .env"; got != want {
+		t.Errorf("missingIgnorePatterns() = %q, want %q", got, want)
+	}
+}
+
+func TestRemediationPlan(t *testing.T) {
+	client := &MockAIClient{response: "## Commit your changes"}
+	plan, err := remediationPlan(client, "⚠️  Changes      2 modified\n")
+	if err != nil {
+		t.Fatalf("remediationPlan() error = %v", err)
+	}
+	if plan != "## Commit your changes" || client.questions[0] != "⚠️  Changes      2 modified\n" {
+		t.Errorf("remediationPlan() = %q, asked %q", plan, client.questions)
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+// jiraKeyPattern matches Jira issue keys such as ABC-123 in branch names.
+var jiraKeyPattern = regexp.MustCompile(`(?:^|[/_-])([A-Z][A-Z0-9]+-[0-9]+)(?:[/_-]|$)`)
+
+// githubIssuePattern matches GitHub issue numbers in branch names such as
+// fix/42-crash, issue-42 or gh-42.
+var githubIssuePattern = regexp.MustCompile(`(?i)(?:^|/)(?:issues?[-_]?|gh[-_]?|#)?([0-9]+)(?:[/_-]|$)`)
+
+// trailerPattern matches trailer lines such as "Signed-off-by: Me".
+var trailerPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: \S`)
+
+// issueSettings returns the issue pattern and trailer of the repository in
+// the current directory: its Git configuration, the settings, the team
+// settings or the defaults.
+func issueSettings() (pattern, trailer string) {
+	pattern = gitConfig("aura.issuePattern")
+	if pattern == "" {
+		pattern = config.UserSettings.Git.IssuePattern
+	}
+	if pattern == "" {
+		pattern = config.Team.Git.IssuePattern
+	}
+	trailer = gitConfig("aura.issueTrailer")
+	if trailer == "" {
+		trailer = config.UserSettings.Git.IssueTrailer
+	}
+	if trailer == "" {
+		trailer = config.Team.Git.IssueTrailer
+	}
+	if trailer == "" {
+		trailer = "Refs"
+	}
+	return pattern, trailer
+}
+
+// currentBranch returns the branch checked out in the current directory, or
+// "" if HEAD is detached.
+func currentBranch() string {
+	output, err := exec.Command("git", "symbolic-ref", "--short", "-q", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// branchIssue returns the issue reference in the name of branch, such as
+// ABC-123 or #42, or "" if there is none. A non-empty pattern replaces the
+// default patterns.
+func branchIssue(branch, pattern string) (string, error) {
+	if pattern == "off" || branch == "" {
+		return "", nil
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid issue pattern '%s': %w", pattern, err)
+		}
+		match := re.FindStringSubmatch(branch)
+		switch {
+		case match == nil:
+			return "", nil
+		case len(match) > 1:
+			return match[1], nil
+		}
+		return match[0], nil
+	}
+
+	if match := jiraKeyPattern.FindStringSubmatch(branch); match != nil {
+		return match[1], nil
+	}
+	if match := githubIssuePattern.FindStringSubmatch(branch); match != nil {
+		return "#" + match[1], nil
+	}
+	return "", nil
+}
+
+// withIssueReference adds a trailer referencing issue to message, unless
+// issue is empty or the message mentions it already.
+func withIssueReference(message, trailer, issue string) string {
+	if issue == "" || strings.Contains(message, issue) {
+		return message
+	}
+
+	message = strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if len(paragraphs) > 1 && isTrailerBlock(last) {
+		return message + "\n" + trailer + ": " + issue
+	}
+	return message + "\n\n" + trailer + ": " + issue
+}
+
+// isTrailerBlock reports whether every line of paragraph is a trailer.
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !trailerPattern.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// referenceIssue adds the issue of the current branch to a generated commit
+// message. An invalid pattern only warns.
+func referenceIssue(message string) string {
+	pattern, trailer := issueSettings()
+	issue, err := branchIssue(currentBranch(), pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return message
+	}
+	return withIssueReference(message, trailer, issue)
+}
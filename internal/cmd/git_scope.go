@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// scopeContainers are directories holding modules rather than being one, so
+// the scope of internal/db/db.go is "db".
+var scopeContainers = map[string]bool{
+	"internal": true, "pkg": true, "src": true, "lib": true,
+	"packages": true, "apps": true, "modules": true, "crates": true,
+}
+
+// dependencyFiles are root files whose changes have the scope "deps".
+var dependencyFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "package.json": true, "package-lock.json": true,
+	"yarn.lock": true, "pnpm-lock.yaml": true, "requirements.txt": true,
+	"pyproject.toml": true, "poetry.lock": true, "Cargo.toml": true, "Cargo.lock": true,
+	"Gemfile": true, "Gemfile.lock": true, "composer.json": true, "composer.lock": true,
+}
+
+// commitScope returns the conventional commit scope of the module most of
+// the changed lines of diff are in, or "" if no module has the majority.
+func commitScope(diff string) string {
+	weights := map[string]int{}
+	total := 0
+	scope := ""
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			scope = fileScope(diffPath(line))
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			weights[scope]++
+			total++
+		}
+	}
+
+	scopes := make([]string, 0, len(weights))
+	for scope := range weights {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		if scope != "" && weights[scope]*2 > total {
+			return scope
+		}
+	}
+	return ""
+}
+
+// diffPath returns the path of the file a "diff --git a/... b/..." header
+// is about, after renames.
+func diffPath(header string) string {
+	if i := strings.LastIndex(header, " b/"); i >= 0 {
+		return strings.Trim(header[i+3:], `"`)
+	}
+	return ""
+}
+
+// fileScope returns the scope of a changed file: the module it is in below
+// the containers, "docs" for documentation, "deps" for dependency files and
+// "" for other files at the root.
+func fileScope(file string) string {
+	dir, name := path.Split(file)
+	if dir == "" {
+		switch {
+		case dependencyFiles[name]:
+			return "deps"
+		case strings.HasPrefix(strings.ToLower(name), "readme"):
+			return "readme"
+		}
+		return ""
+	}
+
+	parts := strings.Split(strings.TrimSuffix(dir, "/"), "/")
+	for len(parts) > 1 && scopeContainers[parts[0]] {
+		parts = parts[1:]
+	}
+	switch parts[0] {
+	case "doc", "docs", "documentation":
+		return "docs"
+	case ".github", ".gitlab", ".circleci":
+		return "ci"
+	}
+	return strings.ToLower(parts[0])
+}
@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+var gitSplitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split staged changes into several AI-described commits",
+	Long: `Split a large set of staged changes into logical commits.
+
+The AI assistant groups the hunks of 'git diff --staged' by purpose and writes
+a commit message for each group. After you approve the plan, the changes are
+unstaged and committed group by group. Changes that are not staged stay
+untouched. If a commit fails, the changes not yet committed are staged again.
+
+Like 'aura git commit', the messages reference the issue of the branch.
+
+New, deleted, renamed and binary files and files whose mode changed are kept
+in one commit.
+
+Examples:
+  git add -A && aura git split
+  aura git split --dry-run      # Only show the proposed commits
+  aura git split --yes          # Commit without asking`,
+	Args: cobra.NoArgs,
+	RunE: runGitSplit,
+}
+
+var (
+	splitYes bool
+)
+
+// diffFile is a file of a diff with the header lines before its hunks.
+type diffFile struct {
+	path   string
+	header []string
+}
+
+// diffHunk is a hunk of a staged diff, or a whole file that can't be split.
+type diffHunk struct {
+	file  *diffFile
+	lines []string
+}
+
+// splitCommit is a proposed commit: its message and the numbers of its hunks,
+// counting from 1.
+type splitCommit struct {
+	Message string `json:"message"`
+	Hunks   []int  `json:"hunks"`
+}
+
+func runGitSplit(cmd *cobra.Command, args []string) error {
+	if !isGitRepository() {
+		return failure.New(failure.UserInput, "not a git repository")
+	}
+	if exec.Command("git", "rev-parse", "--verify", "-q", "HEAD").Run() != nil {
+		return failure.New(failure.UserInput, "the repository has no commits yet, make the first one with 'aura git commit'")
+	}
+
+	output, err := exec.Command("git", "diff", "--staged", "--binary").Output()
+	if err != nil {
+		return fmt.Errorf("failed to get staged changes: %w", err)
+	}
+	hunks := parseDiffHunks(string(output))
+	switch len(hunks) {
+	case 0:
+		fmt.Println("No staged changes found. Stage some changes first with 'git add'.")
+		return nil
+	case 1:
+		fmt.Println("ℹ️  Only one hunk is staged, commit it with 'aura git commit'")
+		return nil
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return fmt.Errorf("failed to initialize AI client: %w", err)
+	}
+	plan, err := proposeSplit(client, hunks)
+	if err != nil {
+		return err
+	}
+	printFallbackNote(client)
+	for i := range plan {
+		plan[i].Message = referenceIssue(plan[i].Message)
+	}
+	printSplitPlan(plan, hunks)
+
+	if config.DryRun() {
+		return nil
+	}
+	if !confirmAction("git split", fmt.Sprintf("Create these %d commits", len(plan)), true, splitYes) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	return applySplit(plan, hunks)
+}
+
+// parseDiffHunks splits a diff into its hunks. Files that can't be split by
+// hunk are one hunk each.
+func parseDiffHunks(diff string) []diffHunk {
+	var hunks []diffHunk
+	var file *diffFile
+	var fileHunks [][]string
+
+	flush := func() {
+		if file == nil {
+			return
+		}
+		if isWholeFile(file.header) || len(fileHunks) == 0 {
+			var lines []string
+			for _, hunk := range fileHunks {
+				lines = append(lines, hunk...)
+			}
+			hunks = append(hunks, diffHunk{file: file, lines: lines})
+		} else {
+			for _, hunk := range fileHunks {
+				hunks = append(hunks, diffHunk{file: file, lines: hunk})
+			}
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			file = &diffFile{path: diffPath(line), header: []string{line}}
+			fileHunks = nil
+		case file == nil:
+		case strings.HasPrefix(line, "@@"):
+			fileHunks = append(fileHunks, []string{line})
+		case len(fileHunks) > 0:
+			fileHunks[len(fileHunks)-1] = append(fileHunks[len(fileHunks)-1], line)
+		default:
+			file.header = append(file.header, line)
+		}
+	}
+	flush()
+	return hunks
+}
+
+// isWholeFile reports whether the file of a diff header must be committed at
+// once: new, deleted, renamed, copied and binary files, and mode changes.
+func isWholeFile(header []string) bool {
+	for _, line := range header {
+		for _, prefix := range []string{"new file mode", "deleted file mode", "rename from", "copy from", "old mode", "Binary files", "GIT binary patch"} {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildPatch returns the patch of hunks, numbered from 1, in diff order.
+func buildPatch(hunks []diffHunk, numbers []int) string {
+	sorted := append([]int(nil), numbers...)
+	sort.Ints(sorted)
+
+	var patch strings.Builder
+	var current *diffFile
+	for _, number := range sorted {
+		hunk := hunks[number-1]
+		if hunk.file != current {
+			current = hunk.file
+			for _, line := range current.header {
+				patch.WriteString(line + "\n")
+			}
+		}
+		for _, line := range hunk.lines {
+			patch.WriteString(line + "\n")
+		}
+	}
+	return patch.String()
+}
+
+// proposeSplit asks the AI assistant how to group the hunks into commits.
+func proposeSplit(client ai.Assistant, hunks []diffHunk) ([]splitCommit, error) {
+	var prompt strings.Builder
+	for i, hunk := range hunks {
+		fmt.Fprintf(&prompt, "### Hunk %d: %s\n", i+1, hunk.file.path)
+		if len(hunk.lines) == 0 {
+			for _, line := range hunk.file.header[1:] {
+				prompt.WriteString(line + "\n")
+			}
+		}
+		for _, line := range hunk.lines {
+			prompt.WriteString(line + "\n")
+		}
+		prompt.WriteString("\n")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	numbered := scrubSecrets("the staged diff", prompt.String())
+	stopThinking := startThinking()
+	response, err := client.SplitCommits(ctx, numbered)
+	stopThinking()
+
+	if err != nil {
+		return nil, fmt.Errorf("AI request failed: %w", err)
+	}
+
+	start, end := strings.Index(response, "{"), strings.LastIndex(response, "}")
+	var answer struct {
+		Commits []splitCommit `json:"commits"`
+	}
+	if start < 0 || end < start || json.Unmarshal([]byte(response[start:end+1]), &answer) != nil {
+		return nil, failure.New(failure.API, "the AI assistant did not propose commits, try again")
+	}
+	return normalizeSplitPlan(answer.Commits, hunks), nil
+}
+
+// normalizeSplitPlan makes every hunk part of exactly one commit: unknown and
+// repeated hunks are dropped, as are empty commits, hunks of a file that
+// can't be split stay together, and hunks left out are added to the last
+// commit.
+func normalizeSplitPlan(commits []splitCommit, hunks []diffHunk) []splitCommit {
+	assigned := make(map[int]bool)
+	var plan []splitCommit
+	for _, commit := range commits {
+		var numbers []int
+		for _, number := range commit.Hunks {
+			if number < 1 || number > len(hunks) || assigned[number] {
+				continue
+			}
+			assigned[number] = true
+			numbers = append(numbers, number)
+		}
+		message := strings.Trim(strings.TrimSpace(commit.Message), "`\"'")
+		if len(numbers) == 0 || message == "" {
+			continue
+		}
+		sort.Ints(numbers)
+		plan = append(plan, splitCommit{Message: message, Hunks: numbers})
+	}
+
+	var leftover []int
+	for number := 1; number <= len(hunks); number++ {
+		if !assigned[number] {
+			leftover = append(leftover, number)
+		}
+	}
+	if len(leftover) > 0 {
+		if len(plan) == 0 {
+			return []splitCommit{{Message: "chore: update " + hunks[0].file.path, Hunks: leftover}}
+		}
+		fmt.Fprintf(os.Stderr, "Warning: the AI assistant left out %d hunks, they are added to the last commit\n", len(leftover))
+		last := &plan[len(plan)-1]
+		last.Hunks = append(last.Hunks, leftover...)
+		sort.Ints(last.Hunks)
+	}
+	return plan
+}
+
+// printSplitPlan shows the proposed commits with the files they change.
+func printSplitPlan(plan []splitCommit, hunks []diffHunk) {
+	fmt.Printf("\nProposed commits:\n")
+	for i, commit := range plan {
+		lines := strings.Split(commit.Message, "\n")
+		fmt.Printf("\n%d. %s\n", i+1, lines[0])
+		for _, line := range lines[1:] {
+			if line != "" {
+				fmt.Printf("   %s\n", line)
+			}
+		}
+		var files []string
+		counts := map[string]int{}
+		for _, number := range commit.Hunks {
+			path := hunks[number-1].file.path
+			if counts[path] == 0 {
+				files = append(files, path)
+			}
+			counts[path]++
+		}
+		for _, path := range files {
+			if counts[path] == 1 {
+				fmt.Printf("   %s\n", path)
+			} else {
+				fmt.Printf("   %s (%d hunks)\n", path, counts[path])
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// applySplit unstages the hunks and commits them group by group. If a commit
+// fails, the hunks not committed yet are staged again.
+func applySplit(plan []splitCommit, hunks []diffHunk) error {
+	if err := runGit(nil, "reset", "-q"); err != nil {
+		return fmt.Errorf("failed to unstage changes: %w", err)
+	}
+
+	for i, commit := range plan {
+		if err := runGit(strings.NewReader(buildPatch(hunks, commit.Hunks)), "apply", "--cached", "-"); err != nil {
+			restageSplit(plan[i:], hunks)
+			return fmt.Errorf("failed to stage commit %d: %w", i+1, err)
+		}
+		if err := runGit(nil, "commit", "-q", "-m", commit.Message); err != nil {
+			runGit(nil, "reset", "-q")
+			restageSplit(plan[i:], hunks)
+			return fmt.Errorf("failed to create commit %d: %w", i+1, err)
+		}
+		fmt.Printf("✓ %s\n", strings.SplitN(commit.Message, "\n", 2)[0])
+	}
+	return nil
+}
+
+// restageSplit stages the hunks of the commits again, after a failure.
+func restageSplit(plan []splitCommit, hunks []diffHunk) {
+	var numbers []int
+	for _, commit := range plan {
+		numbers = append(numbers, commit.Hunks...)
+	}
+	if err := runGit(strings.NewReader(buildPatch(hunks, numbers)), "apply", "--cached", "-"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to stage the remaining changes again: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, "ℹ️  The changes not committed yet are staged again")
+}
+
+// runGit runs git with args and stdin, showing its errors.
+func runGit(stdin *strings.Reader, args ...string) error {
+	cmd := exec.Command("git", args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func init() {
+	gitSplitCmd.Flags().BoolVarP(&splitYes, "yes", "y", false, "Commit without asking")
+	gitCmd.AddCommand(gitSplitCmd)
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const splitTestDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-// old
++// new
+ func a() {}
+@@ -20,3 +20,4 @@ func b() {
+ 	x := 1
++	y := 2
+ 	return
+ }
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1,2 @@
++# New
++text
+diff --git a/old.txt b/renamed.txt
+similarity index 100%
+rename from old.txt
+rename to renamed.txt
+`
+
+func TestParseDiffHunks(t *testing.T) {
+	hunks := parseDiffHunks(splitTestDiff)
+	if len(hunks) != 4 {
+		t.Fatalf("Expected 4 hunks, got %d", len(hunks))
+	}
+
+	want := []struct {
+		path  string
+		first string
+	}{
+		{"main.go", "@@ -1,3 +1,3 @@"},
+		{"main.go", "@@ -20,3 +20,4 @@ func b() {"},
+		{"docs/new.md", "@@ -0,0 +1,2 @@"},
+		{"renamed.txt", ""},
+	}
+	for i, hunk := range hunks {
+		first := ""
+		if len(hunk.lines) > 0 {
+			first = hunk.lines[0]
+		}
+		if hunk.file.path != want[i].path || first != want[i].first {
+			t.Errorf("Hunk %d = %s %q, want %s %q", i+1, hunk.file.path, first, want[i].path, want[i].first)
+		}
+	}
+
+	patch := buildPatch(hunks, []int{2})
+	if !strings.HasPrefix(patch, "diff --git a/main.go b/main.go\nindex 1111111..2222222 100644\n--- a/main.go\n+++ b/main.go\n@@ -20,3") {
+		t.Errorf("Expected the patch of hunk 2 with its file header, got:\n%s", patch)
+	}
+	if strings.Contains(patch, "// new") {
+		t.Errorf("Expected only hunk 2 in the patch, got:\n%s", patch)
+	}
+}
+
+func TestNormalizeSplitPlan(t *testing.T) {
+	hunks := parseDiffHunks(splitTestDiff)
+	plan := normalizeSplitPlan([]splitCommit{
+		{Message: "`fix: a`", Hunks: []int{2, 9}},
+		{Message: "docs: add page", Hunks: []int{3, 2}},
+		{Message: "chore: nothing", Hunks: nil},
+	}, hunks)
+
+	want := []splitCommit{
+		{Message: "fix: a", Hunks: []int{2}},
+		{Message: "docs: add page", Hunks: []int{1, 3, 4}},
+	}
+	if fmt.Sprint(plan) != fmt.Sprint(want) {
+		t.Errorf("normalizeSplitPlan() = %v, want %v", plan, want)
+	}
+}
+
+func TestProposeSplit(t *testing.T) {
+	hunks := parseDiffHunks(splitTestDiff)
+	client := &MockAIClient{response: "```json\n{\"commits\": [{\"message\": \"refactor: tidy main\", \"hunks\": [1, 2]}, {\"message\": \"docs: add page\", \"hunks\": [3, 4]}]}\n```"}
+
+	plan, err := proposeSplit(client, hunks)
+	if err != nil {
+		t.Fatalf("proposeSplit() error = %v", err)
+	}
+	if len(plan) != 2 || plan[1].Message != "docs: add page" {
+		t.Errorf("Unexpected plan %v", plan)
+	}
+	if prompt := client.questions[0]; !strings.Contains(prompt, "### Hunk 4: renamed.txt\nsimilarity index 100%") {
+		t.Errorf("Expected numbered hunks in the prompt, got:\n%s", prompt)
+	}
+
+	client.response = "I can't split this"
+	if _, err := proposeSplit(client, hunks); err == nil {
+		t.Error("Expected an error for an answer without commits")
+	}
+}
+
+func TestApplySplit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalDir)
+
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	git("config", "commit.gpgsign", "false")
+
+	var lines []string
+	for i := 1; i <= 40; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	os.WriteFile("a.txt", []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+	git("add", "a.txt")
+	git("commit", "-q", "-m", "initial")
+
+	lines[1] = "line 2 changed"
+	lines[37] = "line 38 changed"
+	os.WriteFile("a.txt", []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("new\n"), 0o644)
+	git("add", "-A")
+
+	hunks := parseDiffHunks(git("diff", "--staged", "--binary"))
+	if len(hunks) != 3 {
+		t.Fatalf("Expected 3 hunks, got %d", len(hunks))
+	}
+	plan := []splitCommit{
+		{Message: "feat: change the end and add b", Hunks: []int{2, 3}},
+		{Message: "fix: change the start", Hunks: []int{1}},
+	}
+	if err := applySplit(plan, hunks); err != nil {
+		t.Fatalf("applySplit() error = %v", err)
+	}
+
+	if log := git("log", "--format=%s"); log != "fix: change the start\nfeat: change the end and add b\ninitial\n" {
+		t.Errorf("Unexpected commits:\n%s", log)
+	}
+	if status := git("status", "--porcelain"); status != "" {
+		t.Errorf("Expected everything committed, got:\n%s", status)
+	}
+	if first := git("show", "--format=", "--name-only", "HEAD~1"); first != "a.txt\nb.txt\n" {
+		t.Errorf("Expected the first commit to change a.txt and b.txt, got:\n%s", first)
+	}
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/finder"
+)
+
+var gitStashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "Browse, apply and drop stashes",
+	Long: `Browse the stashes of the repository with their messages and ages and a
+preview of their changes, then apply, pop, drop or show one, or create a
+branch from it. With uncommitted changes, the list starts with an entry
+stashing them.
+
+Without a terminal the stashes are only listed.
+
+Examples:
+  aura git stash`,
+	Args: cobra.NoArgs,
+	RunE: runGitStash,
+}
+
+// stashEntry is a stash as listed by `git stash list`.
+type stashEntry struct {
+	// Ref is the name of the stash, such as stash@{0}.
+	Ref string
+	// Age is when it was made, such as "2 hours ago".
+	Age    string
+	Branch string
+	// Message is the stash message, or the commit it was made on.
+	Message string
+}
+
+// stashListFormat separates the fields of `git stash list` with unit
+// separators.
+const stashListFormat = "--format=%gd%x1f%cr%x1f%gs"
+
+// stashActions are the actions offered for a selected stash.
+var stashActions = []string{
+	"Apply",
+	"Pop (apply and drop)",
+	"Drop",
+	"Create branch from it",
+	"Show diff",
+	"Cancel",
+}
+
+func runGitStash(cmd *cobra.Command, args []string) error {
+	if !isGitRepository() {
+		return failure.New(failure.UserInput, "not a git repository")
+	}
+
+	output, err := exec.Command("git", "stash", "list", stashListFormat).Output()
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+	stashes := parseStashList(string(output))
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		if len(stashes) == 0 {
+			fmt.Println("No stashes.")
+		}
+		for _, stash := range stashes {
+			fmt.Println(stashLabel(stash))
+		}
+		return nil
+	}
+
+	dirty := hasUncommittedChanges()
+	if len(stashes) == 0 && !dirty {
+		fmt.Println("No stashes and nothing to stash.")
+		return nil
+	}
+
+	// The entry stashing the changes comes first, so stashes start at offset
+	var items []finder.Item
+	offset := 0
+	if dirty {
+		items = append(items, finder.Item{Label: "+ Stash current changes", Value: ""})
+		offset = 1
+	}
+	for _, stash := range stashes {
+		items = append(items, finder.Item{Label: stashLabel(stash), Value: stash.Ref})
+	}
+
+	selected, err := finder.Select(items, finder.Options{
+		Prompt:     "Stash",
+		Preview:    stashPreview,
+		FzfPreview: "git stash show --stat -p --color=always {} 2>/dev/null || git status --short",
+	})
+	if errors.Is(err, finder.ErrCancelled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if selected < offset {
+		return stashChanges()
+	}
+	return runStashAction(stashes[selected-offset])
+}
+
+// parseStashList parses the output of `git stash list` in stashListFormat.
+func parseStashList(output string) []stashEntry {
+	var stashes []stashEntry
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		stash := stashEntry{Ref: fields[0], Age: fields[1], Message: fields[2]}
+		// Subjects read "On main: message" or "WIP on main: 1a2b3c4 subject"
+		for _, prefix := range []string{"WIP on ", "On "} {
+			if rest, ok := strings.CutPrefix(fields[2], prefix); ok {
+				if branch, message, ok := strings.Cut(rest, ": "); ok {
+					stash.Branch, stash.Message = branch, message
+				}
+				break
+			}
+		}
+		stashes = append(stashes, stash)
+	}
+	return stashes
+}
+
+// stashLabel returns the line listing stash.
+func stashLabel(stash stashEntry) string {
+	label := fmt.Sprintf("%-10s %-16s %s", stash.Ref, stash.Age, stash.Message)
+	if stash.Branch != "" {
+		label += " (" + stash.Branch + ")"
+	}
+	return label
+}
+
+// stashPreview returns the changed files of the stash of item, or of the
+// working tree for the entry stashing them.
+func stashPreview(item finder.Item) string {
+	args := []string{"stash", "show", "--stat", item.Value}
+	if item.Value == "" {
+		args = []string{"status", "--short"}
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return string(output)
+}
+
+// hasUncommittedChanges reports whether the working tree or index has
+// changes to tracked files.
+func hasUncommittedChanges() bool {
+	output, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
+// stashChanges stashes the uncommitted changes with a message asked for.
+func stashChanges() error {
+	prompt := promptui.Prompt{Label: "Message (empty for none)"}
+	message, err := prompt.Run()
+	if err != nil {
+		if errors.Is(err, promptui.ErrInterrupt) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+		return fmt.Errorf("prompt failed: %w", err)
+	}
+
+	args := []string{"stash", "push"}
+	if message = strings.TrimSpace(message); message != "" {
+		args = append(args, "-m", message)
+	}
+	return runGitStashCommand(args...)
+}
+
+// runStashAction asks what to do with stash and does it.
+func runStashAction(stash stashEntry) error {
+	prompt := promptui.Select{
+		Label: stash.Ref + " " + stash.Message,
+		Items: stashActions,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		if errors.Is(err, promptui.ErrInterrupt) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+		return fmt.Errorf("prompt failed: %w", err)
+	}
+
+	switch index {
+	case 0:
+		return runGitStashCommand("stash", "apply", stash.Ref)
+	case 1:
+		return runGitStashCommand("stash", "pop", stash.Ref)
+	case 2:
+		if !confirmAction("git stash", fmt.Sprintf("Drop %s", stash.Ref), true, false) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+		return runGitStashCommand("stash", "drop", stash.Ref)
+	case 3:
+		branchPrompt := promptui.Prompt{Label: "Branch name"}
+		branch, err := branchPrompt.Run()
+		if err != nil {
+			if errors.Is(err, promptui.ErrInterrupt) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		if branch = strings.TrimSpace(branch); branch == "" {
+			return failure.New(failure.UserInput, "branch name is required")
+		}
+		return runGitStashCommand("stash", "branch", branch, stash.Ref)
+	case 4:
+		return runGitStashCommand("stash", "show", "-p", stash.Ref)
+	}
+	fmt.Println("Cancelled.")
+	return nil
+}
+
+// runGitStashCommand runs git with args, showing its output.
+func runGitStashCommand(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w", strings.Join(args[:2], " "), err)
+	}
+	return nil
+}
+
+func init() {
+	gitCmd.AddCommand(gitStashCmd)
+}
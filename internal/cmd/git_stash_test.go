@@ -0,0 +1,35 @@
+package cmd
+
+import "testing"
+
+func TestParseStashList(t *testing.T) {
+	output := "stash@{0}\x1f2 hours ago\x1fOn main: half-done parser\n" +
+		"stash@{1}\x1f3 days ago\x1fWIP on feat/login: 1a2b3c4 Add login form\n" +
+		"stash@{2}\x1f5 weeks ago\x1fautostash\n"
+
+	stashes := parseStashList(output)
+	want := []stashEntry{
+		{Ref: "stash@{0}", Age: "2 hours ago", Branch: "main", Message: "half-done parser"},
+		{Ref: "stash@{1}", Age: "3 days ago", Branch: "feat/login", Message: "1a2b3c4 Add login form"},
+		{Ref: "stash@{2}", Age: "5 weeks ago", Message: "autostash"},
+	}
+	if len(stashes) != len(want) {
+		t.Fatalf("parseStashList() = %+v, want %d stashes", stashes, len(want))
+	}
+	for i := range want {
+		if stashes[i] != want[i] {
+			t.Errorf("stashes[%d] = %+v, want %+v", i, stashes[i], want[i])
+		}
+	}
+
+	if stashes := parseStashList(""); len(stashes) != 0 {
+		t.Errorf("parseStashList(\"\") = %+v, want none", stashes)
+	}
+}
+
+func TestStashLabel(t *testing.T) {
+	got := stashLabel(stashEntry{Ref: "stash@{0}", Age: "2 hours ago", Branch: "main", Message: "parser"})
+	if want := "stash@{0}  2 hours ago      parser (main)"; got != want {
+		t.Errorf("stashLabel() = %q, want %q", got, want)
+	}
+}
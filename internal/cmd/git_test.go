@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggestCommitMessage(t *testing.T) {
+	tests := []struct {
+		response string
+		want     string
+	}{
+		{"feat: add open command", "feat: add open command"},
+		{"  `fix: handle empty diff`\n", "fix: handle empty diff"},
+		{`"docs: update README"`, "docs: update README"},
+	}
+
+	for _, tt := range tests {
+		client := &MockAIClient{response: tt.response}
+		got, err := suggestCommitMessage(client, "diff --git a/x b/x")
+		if err != nil {
+			t.Fatalf("suggestCommitMessage() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("suggestCommitMessage() with response %q = %q, want %q", tt.response, got, tt.want)
+		}
+	}
+
+	client := &MockAIClient{shouldError: true}
+	if _, err := suggestCommitMessage(client, "diff"); err == nil {
+		t.Error("Expected an error when the AI request fails")
+	}
+}
+
+func TestSuggestCommitMessageRedactsSecrets(t *testing.T) {
+	client := &MockAIClient{response: "chore: configure deployment"}
+	password := "hunter2" + "hunter2"
+	diff := "diff --git a/deploy.env b/deploy.env\n+++ b/deploy.env\n+REGION=eu\n+DB_PASSWORD=" + password + "\n"
+
+	if _, err := suggestCommitMessage(client, diff); err != nil {
+		t.Fatalf("suggestCommitMessage() error = %v", err)
+	}
+	if sent := client.questions[0]; strings.Contains(sent, password) || !strings.Contains(sent, "+REGION=eu\n+DB_PASSWORD=[redacted secret variable]") {
+		t.Errorf("Expected the diff sent with the password redacted, got %q", sent)
+	}
+}
+
+func TestCommitScope(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{
+			name: "single module",
+			diff: "diff --git a/internal/db/usage.go b/internal/db/usage.go\n--- a/internal/db/usage.go\n+++ b/internal/db/usage.go\n@@ -1 +1,2 @@\n+func a() {}\n-func b() {}\n",
+			want: "db",
+		},
+		{
+			name: "dominant module with its tests and a small change elsewhere",
+			diff: "diff --git a/internal/cmd/open.go b/internal/cmd/open.go\n+a\n+b\n+c\ndiff --git a/internal/cmd/open_test.go b/internal/cmd/open_test.go\n+d\ndiff --git a/README.md b/README.md\n+e\n",
+			want: "cmd",
+		},
+		{
+			name: "no majority",
+			diff: "diff --git a/internal/db/db.go b/internal/db/db.go\n+a\ndiff --git a/internal/ai/client.go b/internal/ai/client.go\n+b\n",
+			want: "",
+		},
+		{
+			name: "root files other than dependencies",
+			diff: "diff --git a/Makefile b/Makefile\n+a\n",
+			want: "",
+		},
+		{
+			name: "dependencies",
+			diff: "diff --git a/go.mod b/go.mod\n+a\ndiff --git a/go.sum b/go.sum\n+b\n",
+			want: "deps",
+		},
+		{
+			name: "renamed into a workspace package",
+			diff: "diff --git a/old/x.ts b/packages/web/src/x.ts\n+a\n",
+			want: "web",
+		},
+		{
+			name: "documentation and workflows",
+			diff: "diff --git a/docs/guide.md b/docs/guide.md\n+a\n+b\ndiff --git a/.github/workflows/ci.yml b/.github/workflows/ci.yml\n+c\n",
+			want: "docs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitScope(tt.diff); got != tt.want {
+				t.Errorf("commitScope() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchIssue(t *testing.T) {
+	tests := []struct {
+		branch  string
+		pattern string
+		want    string
+	}{
+		{"feat/ABC-123-login", "", "ABC-123"},
+		{"ABC-123", "", "ABC-123"},
+		{"bugfix/PROJ2-7_crash", "", "PROJ2-7"},
+		{"fix/42-crash", "", "#42"},
+		{"issue-42", "", "#42"},
+		{"feature/gh-7/dark-mode", "", "#7"},
+		{"main", "", ""},
+		{"feat/2fa-login", "", ""},
+		{"release-2024", "", ""},
+		{"feat/abc-123-login", "", ""},
+		{"feat/abc-123-login", `(?i)[a-z]+-\d+`, "abc-123"},
+		{"team/42/login", `team/(\d+)`, "42"},
+		{"feat/ABC-123-login", "off", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := branchIssue(tt.branch, tt.pattern)
+		if err != nil {
+			t.Errorf("branchIssue(%q, %q) error = %v", tt.branch, tt.pattern, err)
+		}
+		if got != tt.want {
+			t.Errorf("branchIssue(%q, %q) = %q, want %q", tt.branch, tt.pattern, got, tt.want)
+		}
+	}
+
+	if _, err := branchIssue("main", "("); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestWithIssueReference(t *testing.T) {
+	tests := []struct {
+		message string
+		issue   string
+		want    string
+	}{
+		{"feat(auth): add login", "ABC-123", "feat(auth): add login\n\nRefs: ABC-123"},
+		{"feat(auth): add login\n", "#42", "feat(auth): add login\n\nRefs: #42"},
+		{"feat: add login\n\nBREAKING-CHANGE: new API", "ABC-1", "feat: add login\n\nBREAKING-CHANGE: new API\nRefs: ABC-1"},
+		{"feat: add login\n\nLonger body text.", "ABC-1", "feat: add login\n\nLonger body text.\n\nRefs: ABC-1"},
+		{"fix: ABC-123 crash on start", "ABC-123", "fix: ABC-123 crash on start"},
+		{"feat: add login", "", "feat: add login"},
+	}
+
+	for _, tt := range tests {
+		if got := withIssueReference(tt.message, "Refs", tt.issue); got != tt.want {
+			t.Errorf("withIssueReference(%q, %q) = %q, want %q", tt.message, tt.issue, got, tt.want)
+		}
+	}
+}
@@ -1,37 +1,93 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
 	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/finder"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/remote"
 )
 
 var goCmd = &cobra.Command{
 	Use:   "go [destination]",
 	Short: "Navigate to bookmarked directories",
 	Long: `Navigate to bookmarked directories using aliases or fuzzy search.
+
+Without a matching bookmark or visited directory, Aura falls back to the
+directory index of your project directories (~/code, ~/projects, ~/src, ...).
+See 'aura index --help' to configure it.
+
+With --semantic, bookmarks and frequently visited directories are compared by
+meaning, using their names, READMEs and project files. This requires an AI
+provider with an embedding model, see 'aura index embed --help'. Once an
+embedding model or API is configured, descriptions of several words that
+match nothing by name are resolved semantically without --semantic.
+
+Bookmarks of cloud storage (s3://, gs:// and az://) are listed with the
+provider's CLI, after checking it is logged in. Locations mounted locally are
+entered instead, configure the mounts in config.yaml:
+
+  navigation:
+    mounts:
+      s3://my-bucket: /mnt/my-bucket
 	
 Examples:
   aura go my-project     # Navigate to bookmarked 'my-project'
   aura go notes          # Navigate to bookmarked 'notes'
-  aura go proj           # Fuzzy search for directories matching 'proj'`,
-	Args: cobra.MinimumNArgs(1),
+  aura go 1              # Navigate to quick mark 1, see 'aura mark'
+  aura go logs           # List s3://my-bucket/logs, or enter where it's mounted
+  aura go proj           # Fuzzy search for directories matching 'proj'
+  aura go someRepo       # Jump to a project never bookmarked or visited
+  aura go --fzf          # Pick from bookmarks, history and indexed directories
+  aura go --fzf api      # Same, starting with the query 'api'
+  aura go --semantic "that repo with the billing service"
+  aura go --semantic payments  # Search by meaning even for a single word`,
 	RunE: runGo,
 }
 
+var (
+	goFzf      bool
+	goSemantic bool
+)
+
 func runGo(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !goFzf {
+		return failure.New(failure.UserInput, "requires a destination, or --fzf to pick one")
+	}
 	query := strings.Join(args, " ")
 
+	if path, ok := markedPath(query); ok && !goFzf {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: Marked path '%s' no longer exists\n", path)
+			return failure.New(failure.NotFound, "path not found")
+		}
+		fmt.Print(path)
+		return nil
+	}
+
 	database, err := db.New()
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer database.Close()
 
+	if goFzf {
+		return runGoFinder(database, query)
+	}
+	if goSemantic {
+		return runGoSemantic(database, query)
+	}
+
 	// First try exact bookmark match
 	bookmark, err := database.GetBookmark(query)
 	if err != nil {
@@ -39,16 +95,22 @@ func runGo(cmd *cobra.Command, args []string) error {
 	}
 
 	if bookmark != nil {
+		if location, ok := remote.Parse(bookmark.Path); ok {
+			recordBookmarkUse(database, bookmark.Alias)
+			return goRemote(location)
+		}
+
 		// Add to navigation history
 		if err := database.AddNavigationHistory(bookmark.Path); err != nil {
 			// Log warning but don't fail navigation
 			fmt.Fprintf(os.Stderr, "Warning: failed to add to navigation history: %v\n", err)
 		}
+		recordBookmarkUse(database, bookmark.Alias)
 
 		// Verify the path exists
 		if _, err := os.Stat(bookmark.Path); os.IsNotExist(err) {
 			fmt.Fprintf(os.Stderr, "Error: Bookmarked path '%s' no longer exists\n", bookmark.Path)
-			return fmt.Errorf("path not found")
+			return failure.New(failure.NotFound, "path not found")
 		}
 
 		// Print the absolute path to stdout for shell wrapper to use
@@ -62,23 +124,52 @@ func runGo(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("search error: %w", err)
 	}
 
+	// Keep the directory index fresh, building it first if it never was
+	stats, err := database.GetIndexStats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read directory index: %v\n", err)
+	} else if stats.BuiltAt.IsZero() && len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "Indexing directories...")
+		if _, err := rebuildIndex(database); err != nil {
+			return fmt.Errorf("failed to index directories: %w", err)
+		}
+		if results, err = database.FuzzySearch(query); err != nil {
+			return fmt.Errorf("search error: %w", err)
+		}
+	} else if time.Since(stats.BuiltAt) > config.GetIndexRefreshInterval() {
+		refreshIndexInBackground()
+	}
+
+	// Only fall back to semantic search where it was set up, so a mistyped
+	// destination doesn't send descriptions of directories to an AI provider
+	if len(results) == 0 && len(strings.Fields(query)) > 1 && ai.EmbeddingsConfigured() && !config.Offline() {
+		return runGoSemantic(database, query)
+	}
+
 	if len(results) == 0 {
 		fmt.Fprintf(os.Stderr, "No bookmarks found matching '%s'\n", query)
-		return fmt.Errorf("no matches found")
+		return failure.New(failure.NotFound, "no matches found")
 	}
 
 	// If only one result, use it
 	if len(results) == 1 {
 		result := results[0]
+		if location, ok := remote.Parse(result.Path); ok {
+			recordBookmarkUse(database, result.Alias)
+			return goRemote(location)
+		}
 		if err := database.AddNavigationHistory(result.Path); err != nil {
 			// Log warning but don't fail navigation
 			fmt.Fprintf(os.Stderr, "Warning: failed to add to navigation history: %v\n", err)
 		}
+		if !strings.HasPrefix(result.Alias, "history:") && !strings.HasPrefix(result.Alias, "index:") {
+			recordBookmarkUse(database, result.Alias)
+		}
 
 		// Verify the path exists
 		if _, err := os.Stat(result.Path); os.IsNotExist(err) {
 			fmt.Fprintf(os.Stderr, "Error: Path '%s' no longer exists\n", result.Path)
-			return fmt.Errorf("path not found")
+			return failure.New(failure.NotFound, "path not found")
 		}
 
 		fmt.Print(result.Path)
@@ -88,16 +179,133 @@ func runGo(cmd *cobra.Command, args []string) error {
 	// Multiple results - display them and ask user to be more specific
 	fmt.Fprintf(os.Stderr, "Multiple matches found for '%s':\n", query)
 	for _, result := range results {
-		if strings.HasPrefix(result.Alias, "history:") {
+		if strings.HasPrefix(result.Alias, "history:") || strings.HasPrefix(result.Alias, "index:") {
 			fmt.Fprintf(os.Stderr, "  %s\n", result.Path)
 		} else {
 			fmt.Fprintf(os.Stderr, "  %s -> %s\n", result.Alias, result.Path)
 		}
 	}
 	fmt.Fprintf(os.Stderr, "Please be more specific.\n")
-	return fmt.Errorf("ambiguous query")
+	return failure.New(failure.UserInput, "ambiguous query")
+}
+
+// runGoSemantic navigates to the bookmark or frequently visited directory
+// whose description is most similar to the query.
+func runGoSemantic(database *db.DB, query string) error {
+	results, err := semanticSearch(database, query, 3)
+	if err != nil {
+		return fmt.Errorf("semantic search failed: %w", err)
+	}
+	if len(results) == 0 {
+		fmt.Fprintf(os.Stderr, "No bookmarks or visited directories match '%s'\n", query)
+		return failure.New(failure.NotFound, "no matches found")
+	}
+
+	best := results[0]
+	fmt.Fprintf(os.Stderr, "🔎 %s (similarity %.2f)\n", best.Path, best.Score)
+	for _, other := range results[1:] {
+		fmt.Fprintf(os.Stderr, "   also: %s (%.2f)\n", other.Path, other.Score)
+	}
+
+	if err := database.AddNavigationHistory(best.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to add to navigation history: %v\n", err)
+	}
+
+	fmt.Print(best.Path)
+	return nil
+}
+
+// runGoFinder lets the user pick the destination in a fuzzy finder with a
+// preview of the directory contents.
+func runGoFinder(database *db.DB, query string) error {
+	candidates, err := navigationCandidates(database)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		fmt.Fprintln(os.Stderr, "No bookmarks, history or indexed directories yet.")
+		return failure.New(failure.NotFound, "no matches found")
+	}
+
+	preview := "ls -la {}"
+	if platform.Current().IsWindows() {
+		preview = "dir {}"
+	}
+
+	selected, err := finder.Select(candidates, finder.Options{
+		Prompt: "Go to",
+		Query:  query,
+		Preview: func(item finder.Item) string {
+			return finder.DirectoryPreview(item.Value, 15)
+		},
+		FzfPreview: preview,
+	})
+	if err != nil {
+		if errors.Is(err, finder.ErrCancelled) {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
+		}
+		return err
+	}
+
+	path := candidates[selected].Value
+	if location, ok := remote.Parse(path); ok {
+		return goRemote(location)
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: Path '%s' no longer exists\n", path)
+		return failure.New(failure.NotFound, "path not found")
+	}
+	if err := database.AddNavigationHistory(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to add to navigation history: %v\n", err)
+	}
+
+	fmt.Print(path)
+	return nil
+}
+
+// navigationCandidates returns the bookmarks, recently visited and indexed
+// directories, each path once.
+func navigationCandidates(database *db.DB) ([]finder.Item, error) {
+	var items []finder.Item
+	seen := make(map[string]bool)
+	add := func(label, path string) {
+		if !seen[path] {
+			seen[path] = true
+			items = append(items, finder.Item{Label: label, Value: path})
+		}
+	}
+
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return nil, err
+	}
+	for _, bookmark := range bookmarks {
+		add(fmt.Sprintf("%-16s %s", bookmark.Alias, bookmark.Path), bookmark.Path)
+	}
+
+	recent, err := database.RecentDirectories(50)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range recent {
+		add(path, path)
+	}
+
+	indexed, err := database.ListIndexedDirectories()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range indexed {
+		add(path, path)
+	}
+
+	return items, nil
 }
 
 func init() {
+	goCmd.Flags().BoolVar(&goFzf, "fzf", false, "Pick the destination in a fuzzy finder (uses fzf when installed)")
+	goCmd.Flags().BoolVar(&goSemantic, "semantic", false, "Find the destination by meaning using embeddings")
+
 	rootCmd.AddCommand(goCmd)
 }
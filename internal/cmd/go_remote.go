@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/remote"
+)
+
+// goRemote enters the mounted directory of a cloud storage location, or
+// lists the location with the provider's CLI when it isn't mounted. The
+// listing goes to stderr, so the shell integration stays in the current
+// directory.
+func goRemote(location remote.Location) error {
+	if path, ok := location.MountedPath(config.UserSettings.Navigation.Mounts); ok {
+		if _, err := os.Stat(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s is not mounted at '%s'\n", location.URI(), path)
+			return failure.New(failure.NotFound, "path not found")
+		}
+		fmt.Print(path)
+		return nil
+	}
+
+	// The provider's CLI runs on its own, outside the offline transport
+	if err := config.RequireNetwork("listing " + location.URI()); err != nil {
+		return err
+	}
+	if err := location.CheckCredentials(); err != nil {
+		return failure.Wrap(failure.Config, err)
+	}
+
+	args := location.ListCommand()
+	fmt.Fprintf(os.Stderr, "☁️  %s (%s)\n", location.URI(), strings.Join(args, " "))
+	command := exec.Command(args[0], args[1:]...)
+	command.Stdout = os.Stderr
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return failure.New(failure.Network, "failed to list %s: %w", location.URI(), err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/hooks"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install Aura checks as Git hooks",
+	Long:  `Install, remove and list Aura checks that run as Git hooks in the current repository.`,
+}
+
+var hooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available hooks",
+	RunE:  runHooksList,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install [hook]",
+	Short: "Install a hook in the current repository",
+	Long: `Install a hook in the current repository.
+
+Examples:
+  aura hooks install secrets    # Scan staged changes for secrets before each commit`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHooksInstall,
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall [hook]",
+	Short: "Remove a hook from the current repository",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHooksUninstall,
+}
+
+var hooksForce bool
+
+func runHooksList(cmd *cobra.Command, args []string) error {
+	dir, err := hooks.Dir()
+	if err != nil {
+		dir = ""
+	}
+
+	fmt.Println("Available hooks:")
+	for _, hook := range hooks.Available {
+		status := ""
+		if dir != "" && hook.Installed(dir) {
+			status = " (installed)"
+		}
+		fmt.Printf("  %-10s %s [%s]%s\n", hook.Name, hook.Description, hook.GitHook, status)
+	}
+	return nil
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) error {
+	hook, err := hooks.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	dir, err := hooks.Dir()
+	if err != nil {
+		return err
+	}
+
+	if config.DryRun() {
+		changes, err := hook.Changes(dir, hooksForce)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			skipChange("%s", change)
+		}
+		return nil
+	}
+	if err := hook.Install(dir, hooksForce); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Installed '%s' as %s hook\n", hook.Name, hook.GitHook)
+	return nil
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) error {
+	hook, err := hooks.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	dir, err := hooks.Dir()
+	if err != nil {
+		return err
+	}
+
+	if hook.Installed(dir) && skipChange("remove %s", filepath.Join(dir, hook.GitHook)) {
+		return nil
+	}
+	if err := hook.Uninstall(dir); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Removed '%s' %s hook\n", hook.Name, hook.GitHook)
+	return nil
+}
+
+func init() {
+	hooksInstallCmd.Flags().BoolVar(&hooksForce, "force", false, "Replace an existing hook (a backup is kept)")
+
+	hooksCmd.AddCommand(hooksListCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/dotenv"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/httpreq"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var httpCmd = &cobra.Command{
+	Use:   "http [METHOD] <url> [ITEM...]",
+	Short: "Send HTTP requests and pretty-print the responses",
+	Long: `Send an HTTP request written HTTPie style and print the response with its
+JSON body indented. Items after the URL are headers (Name:value), query
+parameters (name==value), JSON fields (name=value) and raw JSON fields
+(name:=json). Without a method, requests with fields are sent as POST and
+others as GET. URLs without a scheme use https, or http for localhost, and
+":3000/path" is short for http://localhost:3000/path.
+
+{{NAME}} placeholders in the URL and items are replaced with the variables of
+the .env file in the current directory, or of .env.<profile> with --env, and
+then with environment variables. Requests saved with --save keep their
+placeholders, so secrets stay in the .env files.
+
+When the output is piped, only the raw response body is written.
+
+Examples:
+  aura http api.example.com/users X-Auth:token          # GET with a header
+  aura http POST :3000/users name=ada admin:=true       # JSON body
+  aura http '{{API}}/users' page==2 --env staging       # Variables from .env.staging
+  aura http '{{API}}/me' 'Authorization:Bearer {{TOKEN}}' --save me
+  aura http run me --env prod                           # Run a saved request
+  aura http list                                        # List saved requests`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runHTTP,
+}
+
+var httpRunCmd = &cobra.Command{
+	Use:   "run <name> [ITEM...]",
+	Short: "Send a saved request",
+	Long: `Send a request saved with 'aura http --save'. Items given after the name
+are added to the saved ones.
+
+Examples:
+  aura http run me
+  aura http run users page==3 --env staging`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runHTTPRun,
+}
+
+var httpListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List saved requests",
+	Args:    cobra.NoArgs,
+	RunE:    runHTTPList,
+}
+
+var httpRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a saved request",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runHTTPRemove,
+}
+
+var (
+	httpSave    string
+	httpEnv     string
+	httpBody    bool
+	httpVerbose bool
+	httpTimeout time.Duration
+)
+
+func init() {
+	for _, command := range []*cobra.Command{httpCmd, httpRunCmd} {
+		command.Flags().StringVarP(&httpEnv, "env", "e", "", "Read variables from .env.<profile> instead of .env")
+		command.Flags().BoolVarP(&httpBody, "body", "b", false, "Print only the response body")
+		command.Flags().BoolVarP(&httpVerbose, "verbose", "v", false, "Print the request before the response")
+		command.Flags().DurationVar(&httpTimeout, "timeout", 30*time.Second, "Give up on the request after this long")
+	}
+	httpCmd.Flags().StringVar(&httpSave, "save", "", "Save the request under a name before sending it")
+
+	httpCmd.AddCommand(httpRunCmd)
+	httpCmd.AddCommand(httpListCmd)
+	httpCmd.AddCommand(httpRemoveCmd)
+	rootCmd.AddCommand(httpCmd)
+}
+
+func runHTTP(cmd *cobra.Command, args []string) error {
+	request, err := httpreq.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if httpSave != "" {
+		if strings.ContainsAny(httpSave, " \t/") {
+			return failure.New(failure.UserInput, "invalid request name '%s'", httpSave)
+		}
+		database, err := db.New()
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		err = database.SaveRequest(db.SavedRequest{Name: httpSave, Method: request.Method, URL: request.URL, Items: request.Items})
+		database.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "✓ Saved request '%s'\n", httpSave)
+		for _, key := range literalSecrets(request) {
+			fmt.Fprintf(os.Stderr, "ℹ️  %s is saved as typed. Use a {{VAR}} placeholder to keep it in .env instead.\n", key)
+		}
+	}
+
+	return sendHTTP(request)
+}
+
+func runHTTPRun(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	saved, err := database.GetRequest(args[0])
+	database.Close()
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return failure.New(failure.NotFound, "request '%s' not found, see 'aura http list'", args[0])
+	}
+
+	request, err := httpreq.Parse(append([]string{saved.Method, saved.URL}, append(saved.Items, args[1:]...)...))
+	if err != nil {
+		return err
+	}
+	return sendHTTP(request)
+}
+
+func runHTTPList(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	requests, err := database.ListRequests()
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		fmt.Println("No saved requests. Save one with 'aura http <url> --save <name>'.")
+		return nil
+	}
+
+	nameWidth, methodWidth := 0, 0
+	for _, request := range requests {
+		nameWidth = max(nameWidth, len(request.Name))
+		methodWidth = max(methodWidth, len(request.Method))
+	}
+	for _, request := range requests {
+		line := strings.Join(append([]string{request.URL}, request.Items...), " ")
+		fmt.Printf("  %-*s  %-*s  %s\n", nameWidth, request.Name, methodWidth, request.Method, line)
+	}
+	return nil
+}
+
+func runHTTPRemove(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	removed, err := database.RemoveRequest(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return failure.New(failure.NotFound, "request '%s' not found", args[0])
+	}
+	fmt.Printf("✓ Removed request '%s'\n", args[0])
+	return nil
+}
+
+// sendHTTP sends request and prints the response.
+func sendHTTP(request httpreq.Request) error {
+	lookup, err := httpVariables(httpEnv)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+	defer cancel()
+	req, err := request.Build(ctx, lookup)
+	if err != nil {
+		return err
+	}
+	if httpVerbose {
+		printHTTPRequest(os.Stdout, req)
+	}
+
+	client := &http.Client{
+		// Redirects are shown rather than followed, like the other headers
+		// of the response.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		return failure.Wrap(failure.Network, fmt.Errorf("request failed: %w", err))
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return failure.Wrap(failure.Network, fmt.Errorf("failed to read the response: %w", err))
+	}
+
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		_, err := os.Stdout.Write(body)
+		return err
+	}
+	printHTTPResponse(os.Stdout, response, body, httpBody)
+	return nil
+}
+
+// httpVariables returns the lookup of {{NAME}} placeholders: the variables
+// of .env, or of .env.<profile> if a profile is given, then the environment.
+// A missing .env is fine, a missing profile is not.
+func httpVariables(profile string) (func(string) (string, bool), error) {
+	path := ".env"
+	if profile != "" {
+		path = ".env." + profile
+	}
+	variables, err := dotenv.Read(path)
+	if err != nil && (profile != "" || !errors.Is(err, fs.ErrNotExist)) {
+		return nil, failure.Wrap(failure.Config, fmt.Errorf("failed to read %s: %w", path, err))
+	}
+	return func(name string) (string, bool) {
+		if value, ok := variables[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}, nil
+}
+
+// literalSecrets returns the keys of the items of request that look like
+// secrets but are typed out rather than taken from a variable.
+func literalSecrets(request httpreq.Request) []string {
+	var keys []string
+	for _, item := range request.Items {
+		key, _, found := strings.Cut(strings.ReplaceAll(item, "=", ":"), ":")
+		if found && dotenv.IsSensitiveKey(key) && !strings.Contains(item, "{{") {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// printHTTPRequest prints the request line, headers and body of req.
+func printHTTPRequest(w io.Writer, req *http.Request) {
+	fmt.Fprintf(w, "%s %s %s\n", theme.Paint(theme.Command, req.Method), req.URL.RequestURI(), req.Proto)
+	fmt.Fprintf(w, "%s: %s\n", theme.Paint(theme.Muted, "Host"), req.URL.Host)
+	printHTTPHeaders(w, req.Header)
+	fmt.Fprintln(w)
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			fmt.Fprint(w, httpreq.Pretty(req.Header.Get("Content-Type"), data))
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// printHTTPResponse prints the status line and headers of response, unless
+// bodyOnly is set, and its body formatted for reading.
+func printHTTPResponse(w io.Writer, response *http.Response, body []byte, bodyOnly bool) {
+	if !bodyOnly {
+		role := theme.Success
+		switch {
+		case response.StatusCode >= 400:
+			role = theme.Error
+		case response.StatusCode >= 300:
+			role = theme.Info
+		}
+		fmt.Fprintf(w, "%s %s\n", response.Proto, theme.Paint(role, response.Status))
+		printHTTPHeaders(w, response.Header)
+		if len(body) > 0 {
+			fmt.Fprintln(w)
+		}
+	}
+
+	if !utf8.Valid(body) {
+		fmt.Fprintf(w, "(%s of binary data not shown, pipe the output to save it)\n", formatSize(int64(len(body))))
+		return
+	}
+	fmt.Fprint(w, httpreq.Pretty(response.Header.Get("Content-Type"), body))
+}
+
+// printHTTPHeaders prints headers sorted by name.
+func printHTTPHeaders(w io.Writer, headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			fmt.Fprintf(w, "%s: %s\n", theme.Paint(theme.Muted, name), value)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/httpreq"
+)
+
+func TestHTTPVariables(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(originalDir); err != nil {
+			t.Errorf("Failed to restore original directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to change to temp dir: %v", err)
+	}
+	t.Setenv("AURA_TEST_HOME", "/home/me")
+
+	lookup, err := httpVariables("")
+	if err != nil {
+		t.Fatalf("httpVariables() without .env error = %v", err)
+	}
+	if value, ok := lookup("AURA_TEST_HOME"); !ok || value != "/home/me" {
+		t.Errorf("lookup(AURA_TEST_HOME) = %q, %v", value, ok)
+	}
+
+	if err := os.WriteFile(".env.staging", []byte("API=https://staging.example.com\nAURA_TEST_HOME=/srv\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lookup, err = httpVariables("staging")
+	if err != nil {
+		t.Fatalf("httpVariables(staging) error = %v", err)
+	}
+	if value, _ := lookup("API"); value != "https://staging.example.com" {
+		t.Errorf("lookup(API) = %q", value)
+	}
+	if value, _ := lookup("AURA_TEST_HOME"); value != "/srv" {
+		t.Errorf("lookup(AURA_TEST_HOME) = %q, want the profile to win", value)
+	}
+	if _, ok := lookup("AURA_TEST_MISSING"); ok {
+		t.Error("lookup(AURA_TEST_MISSING) found a value")
+	}
+
+	if _, err := httpVariables("prod"); err == nil {
+		t.Error("httpVariables(prod) without .env.prod succeeded")
+	}
+}
+
+func TestLiteralSecrets(t *testing.T) {
+	request := httpreq.Request{Items: []string{
+		"Authorization:Bearer abc",
+		"X-Api-Key:{{KEY}}",
+		"password=hunter2",
+		"page==2",
+		"Accept:text/plain",
+	}}
+	want := []string{"Authorization", "password"}
+	if got := literalSecrets(request); !reflect.DeepEqual(got, want) {
+		t.Errorf("literalSecrets() = %v, want %v", got, want)
+	}
+}
+
+func TestPrintHTTPResponse(t *testing.T) {
+	response := &http.Response{
+		Proto:      "HTTP/1.1",
+		Status:     "404 Not Found",
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": {"application/json"}, "Content-Length": {"19"}},
+	}
+	body := []byte(`{"error":"missing"}`)
+
+	var out bytes.Buffer
+	printHTTPResponse(&out, response, body, false)
+	want := "HTTP/1.1 404 Not Found\nContent-Length: 19\nContent-Type: application/json\n\n{\n  \"error\": \"missing\"\n}\n"
+	if out.String() != want {
+		t.Errorf("printHTTPResponse() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printHTTPResponse(&out, response, []byte{0xff, 0xfe, 0x00}, true)
+	if want := "(3 B of binary data not shown, pipe the output to save it)\n"; out.String() != want {
+		t.Errorf("printHTTPResponse() of binary data = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/index"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the directory index used by 'aura go'",
+	Long: `Aura indexes the directories below your project directories so 'aura go'
+finds them even if they were never bookmarked or visited. The index is rebuilt
+in the background when it is older than the refresh interval.
+
+Directories are indexed up to scan_depth levels deep. Hidden directories,
+dependency and build output folders, directories ignored by .gitignore files
+and directories matching an exclude pattern are skipped. Configure the index
+in config.yaml:
+
+  navigation:
+    project_roots: [~/code, ~/work]
+    scan_depth: 3
+    exclude: [archive, "**/fixtures"]
+    refresh_interval: 1h
+
+AURA_PROJECT_ROOTS overrides the project roots.`,
+}
+
+var indexStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the state of the directory index",
+	Args:  cobra.NoArgs,
+	RunE:  runIndexStatus,
+}
+
+var indexRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the directory index now",
+	Args:  cobra.NoArgs,
+	RunE:  runIndexRebuild,
+}
+
+// indexLockMaxAge is how long a lock of a rebuild is honored, so a crashed
+// rebuild doesn't block the index forever.
+const indexLockMaxAge = 10 * time.Minute
+
+func runIndexStatus(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	stats, err := database.GetIndexStats()
+	if err != nil {
+		return err
+	}
+
+	roots := config.GetProjectRoots()
+	if len(roots) == 0 {
+		fmt.Println("Roots:       (none found, configure navigation.project_roots)")
+	} else {
+		fmt.Printf("Roots:       %s\n", strings.Join(roots, ", "))
+	}
+	fmt.Printf("Depth:       %d\n", config.GetScanDepth())
+	fmt.Printf("Exclude:     %s\n", strings.Join(append(append([]string{}, index.DefaultExclude...), config.GetIndexExclude()...), ", "))
+	fmt.Printf("Directories: %d (%d projects)\n", stats.Directories, stats.Projects)
+
+	if stats.BuiltAt.IsZero() {
+		fmt.Println("Built:       never")
+		return nil
+	}
+
+	age := time.Since(stats.BuiltAt).Round(time.Second)
+	fmt.Printf("Built:       %s (%s ago, took %s)\n", stats.BuiltAt.Local().Format("2006-01-02 15:04:05"), age, stats.Duration.Round(time.Millisecond))
+	if age > config.GetIndexRefreshInterval() {
+		fmt.Println("Status:      stale, rebuilt on the next 'aura go'")
+	} else {
+		fmt.Println("Status:      up to date")
+	}
+	return nil
+}
+
+func runIndexRebuild(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	stats, err := rebuildIndex(database)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Indexed %d directories (%d projects) in %s\n", stats.Directories, stats.Projects, stats.Duration.Round(time.Millisecond))
+	return nil
+}
+
+// rebuildIndex walks the configured roots and replaces the directory index.
+func rebuildIndex(database *db.DB) (db.IndexStats, error) {
+	release, ok := acquireIndexLock()
+	if !ok {
+		return db.IndexStats{}, fmt.Errorf("the index is already being rebuilt")
+	}
+	defer release()
+
+	start := time.Now()
+	entries, err := index.Build(index.Options{
+		Roots:    config.GetProjectRoots(),
+		MaxDepth: config.GetScanDepth(),
+		Exclude:  config.GetIndexExclude(),
+	})
+	if err != nil {
+		if !errors.Is(err, index.ErrTooManyDirectories) {
+			return db.IndexStats{}, err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v, consider lowering scan_depth or adding exclude patterns\n", err)
+	}
+
+	stats := db.IndexStats{Directories: len(entries), Duration: time.Since(start)}
+	dirs := make([]db.IndexedDirectory, 0, len(entries))
+	for _, entry := range entries {
+		dirs = append(dirs, db.IndexedDirectory{Path: entry.Path, Name: entry.Name, Depth: entry.Depth, Project: entry.Project})
+		if entry.Project {
+			stats.Projects++
+		}
+	}
+
+	if err := database.ReplaceDirectories(dirs, stats.Duration); err != nil {
+		return db.IndexStats{}, err
+	}
+	return stats, nil
+}
+
+// acquireIndexLock creates a lock file so only one rebuild runs at a time. It
+// returns false if another rebuild holds the lock.
+func acquireIndexLock() (release func(), ok bool) {
+	dir := config.CacheDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		dir = os.TempDir()
+	}
+	path := filepath.Join(dir, "index.lock")
+
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > indexLockMaxAge {
+		os.Remove(path)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, false
+	}
+	file.Close()
+
+	return func() { os.Remove(path) }, true
+}
+
+// refreshIndexInBackground starts a detached 'aura index rebuild' process.
+func refreshIndexInBackground() {
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	// Output is discarded, the process outlives this command
+	rebuild := exec.Command(executable, "index", "rebuild")
+	if err := rebuild.Start(); err == nil {
+		rebuild.Process.Release()
+	}
+}
+
+func init() {
+	indexCmd.AddCommand(indexStatusCmd)
+	indexCmd.AddCommand(indexRebuildCmd)
+	rootCmd.AddCommand(indexCmd)
+}
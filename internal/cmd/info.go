@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	auracontext "github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Summarize the project in the current directory",
+	Long: `Summarize the project in the current directory: the stacks 'aura do'
+detects, the lines of code per language, the Git history, the TODO and
+FIXME comments and the number of dependencies in its manifests.
+
+In a Git repository the tracked files are counted, elsewhere the files not
+ignored by .gitignore nor in directories such as node_modules.
+
+With --describe the AI assistant describes the project in one paragraph.
+
+Examples:
+  aura info
+  aura info --describe`,
+	Args: cobra.NoArgs,
+	RunE: runInfo,
+}
+
+var infoDescribe bool
+
+// infoLanguageLimit is the number of languages listed.
+const infoLanguageLimit = 8
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	files, err := projectinfo.ListFiles(root)
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+	summary := formatProjectInfo(projectInfo{
+		Name:         filepath.Base(root),
+		Stacks:       auracontext.DetectStacks(),
+		Stats:        projectinfo.Count(root, files),
+		Git:          gitSummary(),
+		Dependencies: projectinfo.CountDependencies(root),
+	})
+	fmt.Print(summary)
+
+	if !infoDescribe {
+		return nil
+	}
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	// The README is file contents, which the privacy settings may keep back
+	readme := ""
+	if ai.RequireFileContents("the README") == nil {
+		readme = readmeExcerpt(root)
+	}
+	description, err := describeProject(client, summary, readme)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", description)
+	return nil
+}
+
+// projectInfo is what `aura info` reports.
+type projectInfo struct {
+	Name         string
+	Stacks       []string
+	Stats        projectinfo.Stats
+	Git          string
+	Dependencies []projectinfo.Dependencies
+}
+
+// formatProjectInfo formats the summary of a project.
+func formatProjectInfo(info projectInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📦 %s\n\n", info.Name)
+
+	stacks := "none detected"
+	if len(info.Stacks) > 0 {
+		stacks = strings.Join(info.Stacks, ", ")
+	}
+	fmt.Fprintf(&b, "%-14s%s\n", "Stacks:", stacks)
+
+	total := 0
+	for _, language := range info.Stats.Languages {
+		total += language.Lines
+	}
+	if total == 0 {
+		fmt.Fprintf(&b, "%-14s%s\n", "Languages:", "no source files")
+	} else {
+		fmt.Fprintf(&b, "%-14s%d lines\n", "Languages:", total)
+		for i, language := range info.Stats.Languages {
+			if i == infoLanguageLimit {
+				fmt.Fprintf(&b, "  … %d more\n", len(info.Stats.Languages)-infoLanguageLimit)
+				break
+			}
+			fmt.Fprintf(&b, "  %-16s %8d lines  %-10s %3d%%\n", language.Language, language.Lines, fileCount(language.Files), language.Lines*100/total)
+		}
+	}
+
+	if info.Git != "" {
+		fmt.Fprintf(&b, "%-14s%s\n", "Git:", info.Git)
+	}
+	fmt.Fprintf(&b, "%-14s%d TODO, %d FIXME\n", "Markers:", info.Stats.Todos, info.Stats.Fixmes)
+
+	if len(info.Dependencies) > 0 {
+		var parts []string
+		for _, deps := range info.Dependencies {
+			part := fmt.Sprintf("%s %d", deps.Manifest, deps.Direct)
+			if deps.Dev > 0 {
+				kind := "dev"
+				if deps.Manifest == "go.mod" {
+					kind = "indirect"
+				}
+				part += fmt.Sprintf(" (+%d %s)", deps.Dev, kind)
+			}
+			parts = append(parts, part)
+		}
+		fmt.Fprintf(&b, "%-14s%s\n", "Dependencies:", strings.Join(parts, ", "))
+	}
+	return b.String()
+}
+
+// gitSummary describes the branch and history of the repository in the
+// current directory, or returns "" outside of one.
+func gitSummary() string {
+	if !isGitRepository() {
+		return ""
+	}
+	branch := currentBranch()
+	if branch == "" {
+		branch = "detached HEAD"
+	}
+	commits, err := gitOutput("rev-list", "--count", "HEAD")
+	if err != nil {
+		return branch + ", no commits yet"
+	}
+	authors, _ := gitOutput("shortlog", "-sn", "HEAD")
+	last, _ := gitOutput("log", "-1", "--format=%cr")
+	first, _ := gitOutput("log", "--reverse", "--format=%cr", "--max-parents=0", "HEAD")
+	if firstLine, _, ok := strings.Cut(first, "\n"); ok {
+		first = firstLine
+	}
+
+	contributors := "1 contributor"
+	if count := len(strings.Split(authors, "\n")); count != 1 {
+		contributors = fmt.Sprintf("%d contributors", count)
+	}
+	return fmt.Sprintf("%s, %s commits by %s, last %s, first %s", branch, commits, contributors, last, first)
+}
+
+// readmeExcerptLength is the number of bytes of the README given to the AI
+// assistant.
+const readmeExcerptLength = 3000
+
+// readmeExcerpt returns the beginning of the README in root, or "".
+func readmeExcerpt(root string) string {
+	for _, name := range []string{"README.md", "README.rst", "README.txt", "README"} {
+		content, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		if len(content) > readmeExcerptLength {
+			content = content[:readmeExcerptLength]
+		}
+		return string(content)
+	}
+	return ""
+}
+
+// describeProject asks the AI assistant to describe the project of summary.
+func describeProject(client ai.Assistant, summary, readme string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	description, err := client.DescribeProject(ctx, summary, readme)
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to describe the project: %w", err)
+	}
+	return strings.TrimSpace(description), nil
+}
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoDescribe, "describe", false, "Let the AI assistant describe the project in one paragraph")
+	rootCmd.AddCommand(infoCmd)
+}
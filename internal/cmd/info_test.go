@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+)
+
+func TestFormatProjectInfo(t *testing.T) {
+	summary := formatProjectInfo(projectInfo{
+		Name:   "app",
+		Stacks: []string{"Git", "Go"},
+		Stats: projectinfo.Stats{
+			Languages: []projectinfo.LanguageStats{
+				{Language: "Go", Files: 10, Lines: 900},
+				{Language: "Markdown", Files: 1, Lines: 100},
+			},
+			Todos:  3,
+			Fixmes: 1,
+		},
+		Git: "main, 12 commits by 2 contributors, last 2 days ago, first 3 months ago",
+		Dependencies: []projectinfo.Dependencies{
+			{Manifest: "go.mod", Direct: 4, Dev: 7},
+			{Manifest: "package.json", Direct: 2},
+		},
+	})
+
+	for _, want := range []string{
+		"📦 app\n",
+		"Stacks:       Git, Go\n",
+		"Languages:    1000 lines\n",
+		"  Go                    900 lines  10 files    90%\n",
+		"  Markdown              100 lines  1 file      10%\n",
+		"Git:          main, 12 commits by 2 contributors",
+		"Markers:      3 TODO, 1 FIXME\n",
+		"Dependencies: go.mod 4 (+7 indirect), package.json 2\n",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("formatProjectInfo() lacks %q:\n%s", want, summary)
+		}
+	}
+
+	if empty := formatProjectInfo(projectInfo{Name: "empty"}); !strings.Contains(empty, "Stacks:       none detected") || !strings.Contains(empty, "no source files") {
+		t.Errorf("formatProjectInfo() of an empty project:\n%s", empty)
+	}
+}
+
+func TestDescribeProject(t *testing.T) {
+	client := &MockAIClient{response: " A CLI for navigating projects. \n"}
+	description, err := describeProject(client, "📦 app\n", "# App")
+	if err != nil {
+		t.Fatalf("describeProject() error = %v", err)
+	}
+	if description != "A CLI for navigating projects." || client.questions[0] != "📦 app\n" {
+		t.Errorf("describeProject() = %q, asked %q", description, client.questions)
+	}
+}
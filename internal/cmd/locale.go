@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+)
+
+// englishUsageTemplate is cobra's usage template, which the headings of are
+// translated for other locales.
+var englishUsageTemplate = rootCmd.UsageTemplate()
+
+// applyLocale sets the locale of messages from the settings or environment
+// and translates the help of all commands.
+func applyLocale() {
+	i18n.SetLocale(i18n.Detect(config.UserSettings.Locale))
+	rootCmd.SetUsageTemplate(localizeUsageTemplate(englishUsageTemplate))
+	localizeCommands(rootCmd)
+}
+
+// localizeUsageTemplate translates the headings of a cobra usage template.
+func localizeUsageTemplate(template string) string {
+	if i18n.Locale() == i18n.DefaultLocale {
+		return template
+	}
+	template = strings.Replace(template, "Usage:", i18n.T("help.usage"), 1)
+	for heading, key := range map[string]string{
+		"Aliases:":                "help.aliases",
+		"Examples:":               "help.examples",
+		"Available Commands:":     "help.commands",
+		"Flags:":                  "help.flags",
+		"Global Flags:":           "help.global_flags",
+		"Additional help topics:": "help.topics",
+	} {
+		template = strings.ReplaceAll(template, "\n"+heading, "\n"+i18n.T(key))
+	}
+	return strings.ReplaceAll(template,
+		`Use "{{.CommandPath}} [command] --help" for more information about a command.`,
+		i18n.T("help.more", "{{.CommandPath}}"))
+}
+
+// localizeCommands sets the short descriptions of cmd and its subcommands
+// that the catalog of the locale translates.
+func localizeCommands(cmd *cobra.Command) {
+	if short, ok := i18n.Lookup("cmd." + cmd.CommandPath() + ".short"); ok {
+		cmd.Short = short
+	}
+	for _, child := range cmd.Commands() {
+		localizeCommands(child)
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/marks"
+)
+
+var markCmd = &cobra.Command{
+	Use:   "mark [number] [path]",
+	Short: "Mark directories for this shell session",
+	Long: `Mark the current directory, or path, with a number to return to it with
+'aura go <number>'. Quick marks belong to the shell session that set them and
+disappear with it, for hopping between a few directories without adding
+bookmarks. Without arguments, list the marks of this session.
+
+Examples:
+  aura mark 1            # Mark the current directory
+  aura mark 2 ~/logs
+  aura go 1              # Back to the first mark
+  aura mark              # List the marks
+  aura mark -d 2         # Remove a mark
+  aura mark --clear      # Remove all marks of this session`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runMark,
+}
+
+var (
+	markDelete bool
+	markClear  bool
+)
+
+// markNamePattern matches the names of quick marks. Marks are numbered so
+// they don't shadow bookmark aliases in 'aura go'.
+var markNamePattern = regexp.MustCompile(`^[0-9]+$`)
+
+func runMark(cmd *cobra.Command, args []string) error {
+	session := marks.Current()
+	if markClear {
+		if err := session.Clear(); err != nil {
+			return err
+		}
+		fmt.Println("✓ Removed all marks of this session")
+		return nil
+	}
+	if len(args) == 0 {
+		if markDelete {
+			return failure.New(failure.UserInput, "--delete needs the number of the mark")
+		}
+		return listMarks(session)
+	}
+
+	name := args[0]
+	if !markNamePattern.MatchString(name) {
+		return failure.New(failure.UserInput, "marks are numbered, such as 'aura mark 1', use 'aura bookmark add' for named bookmarks")
+	}
+
+	if markDelete {
+		removed, err := session.Remove(name)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return failure.New(failure.NotFound, "mark %s not found", name)
+		}
+		fmt.Printf("✓ Removed mark %s\n", name)
+		return nil
+	}
+
+	path := "."
+	if len(args) == 2 {
+		path = config.ExpandHome(args[1])
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if info, err := os.Stat(absPath); err != nil || !info.IsDir() {
+		return failure.New(failure.NotFound, "'%s' is not a directory", absPath)
+	}
+
+	if err := session.Set(name, absPath); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Mark %s: %s\n", name, absPath)
+	return nil
+}
+
+func listMarks(session marks.Session) error {
+	current, err := session.Load()
+	if err != nil {
+		return err
+	}
+	if len(current) == 0 {
+		fmt.Println("No marks in this session. Mark the current directory with: aura mark 1")
+		return nil
+	}
+
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, _ := strconv.Atoi(names[i])
+		b, _ := strconv.Atoi(names[j])
+		return a < b
+	})
+
+	fmt.Println("Marks of this session:")
+	for _, name := range names {
+		fmt.Printf("  %s -> %s\n", name, current[name])
+	}
+	return nil
+}
+
+// markedPath returns the directory of the quick mark query in the current
+// session, if query names one.
+func markedPath(query string) (string, bool) {
+	if !markNamePattern.MatchString(query) {
+		return "", false
+	}
+	current, err := marks.Current().Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return "", false
+	}
+	path, ok := current[query]
+	return path, ok
+}
+
+func init() {
+	markCmd.Flags().BoolVarP(&markDelete, "delete", "d", false, "Remove the mark instead of setting it")
+	markCmd.Flags().BoolVar(&markClear, "clear", false, "Remove all marks of this session")
+	rootCmd.AddCommand(markCmd)
+}
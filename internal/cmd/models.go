@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/finder"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List the models of the AI provider",
+	Long: `List the models offered by the AI provider with their context sizes, and
+check that the configured model is among them.
+
+Examples:
+  aura models                  # List models and check the configured one
+  aura models use              # Pick the default model interactively
+  aura models use gpt-4o-mini  # Set the default model`,
+	Args: cobra.NoArgs,
+	RunE: runModels,
+}
+
+var modelsUseCmd = &cobra.Command{
+	Use:   "use [model]",
+	Short: "Set the default model",
+	Long: `Set the default model in the Aura settings file. Without a model, pick one
+of the provider's models interactively. AURA_MODEL still overrides the default.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runModelsUse,
+}
+
+// fetchModels lists the models of the configured provider.
+func fetchModels() ([]ai.Model, error) {
+	client, err := ai.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AI client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	models, err := client.ListModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("the provider offers no models")
+	}
+	return models, nil
+}
+
+func runModels(cmd *cobra.Command, args []string) error {
+	models, err := fetchModels()
+	if err != nil {
+		return err
+	}
+
+	configured := ai.ConfiguredModel()
+
+	fmt.Printf("🤖 Available models (%d):\n\n", len(models))
+	for _, model := range models {
+		marker := " "
+		if model.ID == configured {
+			marker = "*"
+		}
+		fmt.Printf("  %s %-40s %s\n", marker, model.ID, formatContextLength(model.ContextLength))
+	}
+	fmt.Println()
+
+	if ai.FindModel(models, configured) == nil {
+		return fmt.Errorf("configured model '%s' is not offered by the provider, set another one with 'aura models use'", configured)
+	}
+	fmt.Printf("✓ Configured model '%s' is available\n", configured)
+	return nil
+}
+
+func runModelsUse(cmd *cobra.Command, args []string) error {
+	models, err := fetchModels()
+	if err != nil {
+		return err
+	}
+
+	var model string
+	if len(args) == 1 {
+		model = args[0]
+		if ai.FindModel(models, model) == nil {
+			return fmt.Errorf("model '%s' is not offered by the provider, run 'aura models' to list models", model)
+		}
+	} else {
+		items := make([]finder.Item, len(models))
+		for i, m := range models {
+			items[i] = finder.Item{
+				Label: fmt.Sprintf("%-40s %s", m.ID, formatContextLength(m.ContextLength)),
+				Value: m.ID,
+			}
+		}
+
+		index, err := finder.Select(items, finder.Options{Prompt: "Default model"})
+		if errors.Is(err, finder.ErrCancelled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		model = models[index].ID
+	}
+
+	if err := config.SetSetting(config.GetSettingsFile(), "ai.model", model); err != nil {
+		return fmt.Errorf("failed to save default model: %w", err)
+	}
+
+	fmt.Printf("✓ Default model set to '%s'\n", model)
+	if env := envvar.Model.Get(); env != "" && env != model {
+		fmt.Printf("⚠️  AURA_MODEL is set to '%s' and overrides the default\n", env)
+	}
+	return nil
+}
+
+// formatContextLength renders a context window such as "128k tokens".
+func formatContextLength(tokens int) string {
+	switch {
+	case tokens == 0:
+		return "unknown context"
+	case tokens >= 1000:
+		return fmt.Sprintf("%dk tokens", tokens/1000)
+	default:
+		return fmt.Sprintf("%d tokens", tokens)
+	}
+}
+
+func init() {
+	modelsCmd.AddCommand(modelsUseCmd)
+	rootCmd.AddCommand(modelsCmd)
+}
@@ -5,21 +5,28 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/editor"
+	"github.com/timfewi/aura-cli-go/internal/open"
 )
 
 var newCmd = &cobra.Command{
 	Use:   "new [filename]",
 	Short: "Create a new file and open it in your default editor",
-	Long: `Create a new file in the current directory and open it in VS Code (if available) or the system's default editor.
+	Long: `Create a new file in the current directory and open it in your editor: the
+--editor flag, the editor setting, $VISUAL, $EDITOR or the first installed of
+code, nvim, vim, subl and notepad++ (configurable as editor_candidates).
+Without any, the system's default application opens it.
 
 Examples:
   aura new hello.txt
   aura new hello.cs
-  aura new README.md`,
+  aura new README.md --editor nvim
+  aura new notes.md --wait && cat notes.md   # Continue once edited`,
 	Args: cobra.ExactArgs(1),
 	RunE: runNew,
 }
@@ -37,6 +44,17 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("file '%s' already exists", filename)
 	}
 
+	if config.DryRun() {
+		opener := editor.Resolve(newEditor)
+		if opener == "" {
+			opener = "the default application"
+		}
+		absPath, _ := filepath.Abs(filename)
+		skipChange("create the empty file %s, entry in operations for 'aura undo'", absPath)
+		skipChange("open it in %s", opener)
+		return nil
+	}
+
 	// Create the file
 	file, err := os.Create(filename)
 	if err != nil {
@@ -44,9 +62,13 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 	file.Close()
 
+	if absPath, err := filepath.Abs(filename); err == nil {
+		recordOperation(nil, operationFileCreate, fmt.Sprintf("Create file '%s'", filename), pathOperation{Path: absPath})
+	}
+
 	fmt.Printf("✓ Created file '%s'\n", filename)
 
-	// Open the file in VS Code or default editor
+	// Open the file in the editor
 	if err := openFileInEditor(filename); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Could not open file in editor: %v\n", err)
 	}
@@ -71,20 +93,16 @@ func openFileInEditor(filename string) error {
 		absPath = filename
 	}
 
-	// Try VS Code first
-	if isCommandAvailable("code") {
-		return runEditorCommand("code", absPath)
+	if command := editor.Resolve(newEditor); command != "" {
+		return editor.Open(command, absPath, newWait)
 	}
 
-	// Fallback to system default editor
-	switch runtime.GOOS {
-	case "windows":
-		return runEditorCommand("cmd", "/c", "start", "", absPath)
-	case "darwin":
-		return runEditorCommand("open", absPath)
-	default: // Linux and others
-		return runEditorCommand("xdg-open", absPath)
+	// Fallback to the application the desktop opens the file with, which
+	// can only be waited for until the file is saved
+	if newWait {
+		return editor.StartAndWaitForSave(open.Command(absPath), absPath)
 	}
+	return open.Start(absPath)
 }
 
 func isCommandAvailable(name string) bool {
@@ -92,19 +110,13 @@ func isCommandAvailable(name string) bool {
 	return err == nil
 }
 
-func runEditorCommand(name string, args ...string) error {
-	cmd := exec.Command(name, args...)
-
-	// For Windows "start" command, we should use Start() to avoid blocking
-	// For other commands, we should use Run() to wait for completion
-	if runtime.GOOS == "windows" && name == "cmd" && len(args) > 0 && args[0] == "/c" && len(args) > 1 && args[1] == "start" {
-		return cmd.Start()
-	}
-
-	// For VS Code and other editors, start in background
-	return cmd.Start()
-}
+var (
+	newEditor string
+	newWait   bool
+)
 
 func init() {
+	newCmd.Flags().StringVar(&newEditor, "editor", "", "Editor to open the file in")
+	newCmd.Flags().BoolVar(&newWait, "wait", false, "Wait until the file is closed or saved")
 	rootCmd.AddCommand(newCmd)
 }
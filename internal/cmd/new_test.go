@@ -240,32 +240,6 @@ func TestOpenFileInEditor(t *testing.T) {
 	// This should handle the error gracefully
 }
 
-func TestRunEditorCommand(t *testing.T) {
-	// Test with a simple command that should work on most systems
-	// We use 'echo' as it's available on most platforms
-	tests := []struct {
-		name string
-		cmd  string
-		args []string
-	}{
-		{
-			name: "simple echo command",
-			cmd:  "echo",
-			args: []string{"test"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Just test that the function doesn't panic
-			err := runEditorCommand(tt.cmd, tt.args...)
-			// We don't assert on error as command availability varies
-			// Just ensure function completes
-			_ = err
-		})
-	}
-}
-
 func TestNewCommandConfiguration(t *testing.T) {
 	if newCmd.Use != "new [filename]" {
 		t.Errorf("Expected command use 'new [filename]', got '%s'", newCmd.Use)
@@ -280,3 +254,18 @@ func TestNewCommandConfiguration(t *testing.T) {
 		t.Error("Command should have argument validation configured")
 	}
 }
+
+func TestRunNewDryRun(t *testing.T) {
+	t.Setenv("AURA_DRY_RUN", "1")
+	tempDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tempDir)
+
+	if err := runNew(nil, []string{"notes.md"}); err != nil {
+		t.Fatalf("runNew() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "notes.md")); !os.IsNotExist(err) {
+		t.Error("runNew() created the file in dry-run mode")
+	}
+}
@@ -0,0 +1,408 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/editor"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Keep notes for a project or globally",
+	Long:  "Add, list, search and edit notes kept for the project you are in, for a bookmarked project or globally.",
+}
+
+var noteAddCmd = &cobra.Command{
+	Use:   "add [text]",
+	Short: "Add a note to the current project",
+	Long: `Add a note to the project you are in: the Git repository around the current
+directory, or else the directory itself. Without text the note is read from
+a pipe or written in your editor.
+
+Examples:
+  aura note add "deploy: make release, then tag the commit"
+  aura note add -b api "staging needs the VPN"
+  aura note add --global "the coffee machine is on floor 3"
+  git log -1 --format=%B | aura note add`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runNoteAdd,
+}
+
+var noteListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the notes of the current project and the global notes",
+	Long: `List the notes of the project you are in and the global notes, the most
+recently changed first.
+
+Examples:
+  aura note list
+  aura note list -b api       # The notes of a bookmarked project
+  aura note list --all        # The notes of every project`,
+	Args: cobra.NoArgs,
+	RunE: runNoteList,
+}
+
+var noteSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search all notes",
+	Long: `Search the notes of all projects for the words of the query, each matching
+the start of a word, the best matches first.
+
+Examples:
+  aura note search deploy
+  aura note search "staging vpn" -b api`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runNoteSearch,
+}
+
+var noteEditCmd = &cobra.Command{
+	Use:   "edit <id> [text]",
+	Short: "Change a note",
+	Long: `Replace the text of a note, or change it in your editor without text.
+
+Examples:
+  aura note edit 3
+  aura note edit 3 "deploy: run the release workflow"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runNoteEdit,
+}
+
+var noteRemoveCmd = &cobra.Command{
+	Use:     "remove <id>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a note",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runNoteRemove,
+}
+
+var (
+	noteGlobal   bool
+	noteBookmark string
+	noteAll      bool
+	noteLimit    int
+)
+
+func runNoteAdd(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	project, err := noteProject(database)
+	if err != nil {
+		return err
+	}
+
+	body := strings.Join(args, " ")
+	if len(args) == 0 {
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			body, err = editNote("")
+		} else {
+			var data []byte
+			data, err = io.ReadAll(os.Stdin)
+			body = string(data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		fmt.Println("Empty note, nothing added.")
+		return nil
+	}
+
+	id, err := database.AddNote(project, body)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Added note %d to %s\n", id, describeNoteProject(project))
+	return nil
+}
+
+func runNoteList(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	var projects []string
+	if !noteAll {
+		project, err := noteProject(database)
+		if err != nil {
+			return err
+		}
+		projects = []string{project, ""}
+	}
+	notes, err := database.ListNotes(projects...)
+	if err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		fmt.Println("No notes yet. Add one with 'aura note add <text>'.")
+		return nil
+	}
+	printNotes(os.Stdout, notes)
+	return nil
+}
+
+// printNotes prints notes grouped by project, the projects in the order
+// their first note comes in.
+func printNotes(w io.Writer, notes []db.Note) {
+	var order []string
+	groups := map[string][]db.Note{}
+	for _, note := range notes {
+		if _, ok := groups[note.Project]; !ok {
+			order = append(order, note.Project)
+		}
+		groups[note.Project] = append(groups[note.Project], note)
+	}
+
+	for i, project := range order {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, theme.Paint(theme.Heading, describeNoteProject(project)))
+		for _, note := range groups[project] {
+			id := fmt.Sprintf("%4d", note.ID)
+			date := note.UpdatedAt.Local().Format("2006-01-02")
+			for j, line := range strings.Split(note.Body, "\n") {
+				if j == 0 {
+					fmt.Fprintf(w, "%s  %s  %s\n", id, theme.Paint(theme.Muted, date), line)
+				} else {
+					fmt.Fprintf(w, "%s  %s  %s\n", strings.Repeat(" ", len(id)), strings.Repeat(" ", len(date)), line)
+				}
+			}
+		}
+	}
+}
+
+func runNoteSearch(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	var projects []string
+	if noteBookmark != "" || noteGlobal {
+		project, err := noteProject(database)
+		if err != nil {
+			return err
+		}
+		projects = []string{project}
+	}
+	notes, err := database.SearchNotes(strings.Join(args, " "), noteLimit, projects...)
+	if err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		fmt.Println("No matching notes.")
+		return nil
+	}
+	for _, note := range notes {
+		fmt.Printf("%4d  %s  %s\n", note.ID, theme.Paint(theme.Muted, describeNoteProject(note.Project)), highlightSnippet(note.Snippet))
+	}
+	return nil
+}
+
+// highlightSnippet puts a snippet of SearchNotes on one line with the
+// matches colored.
+func highlightSnippet(snippet string) string {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	var b strings.Builder
+	for {
+		before, rest, found := strings.Cut(snippet, db.SnippetStart)
+		b.WriteString(before)
+		if !found {
+			return b.String()
+		}
+		match, after, _ := strings.Cut(rest, db.SnippetEnd)
+		b.WriteString(theme.Paint(theme.Success, match))
+		snippet = after
+	}
+}
+
+func runNoteEdit(cmd *cobra.Command, args []string) error {
+	id, err := parseNoteID(args[0])
+	if err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	note, err := database.GetNote(id)
+	if err != nil {
+		return err
+	}
+	if note == nil {
+		return failure.New(failure.NotFound, "note %d not found", id)
+	}
+
+	body := strings.Join(args[1:], " ")
+	if len(args) == 1 {
+		if body, err = editNote(note.Body); err != nil {
+			return err
+		}
+	}
+	body = strings.TrimSpace(body)
+	switch {
+	case body == "":
+		fmt.Printf("Empty note, left note %d unchanged. Remove it with 'aura note rm %d'.\n", id, id)
+		return nil
+	case body == note.Body:
+		fmt.Printf("ℹ️  Note %d is unchanged\n", id)
+		return nil
+	}
+	if _, err := database.UpdateNote(id, body); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Updated note %d\n", id)
+	return nil
+}
+
+func runNoteRemove(cmd *cobra.Command, args []string) error {
+	id, err := parseNoteID(args[0])
+	if err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	removed, err := database.RemoveNote(id)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return failure.New(failure.NotFound, "note %d not found", id)
+	}
+	fmt.Printf("✓ Removed note %d\n", id)
+	return nil
+}
+
+func parseNoteID(text string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(text, "#"), 10, 64)
+	if err != nil {
+		return 0, failure.New(failure.UserInput, "invalid note ID '%s'", text)
+	}
+	return id, nil
+}
+
+// noteProject returns the project the --global and --bookmark flags or the
+// current directory select, empty for the global notes.
+func noteProject(database *db.DB) (string, error) {
+	switch {
+	case noteGlobal && noteBookmark != "":
+		return "", failure.New(failure.UserInput, "use either --global or --bookmark, not both")
+	case noteGlobal:
+		return "", nil
+	case noteBookmark != "":
+		bookmark, err := database.GetBookmark(noteBookmark)
+		if err != nil {
+			return "", fmt.Errorf("database error: %w", err)
+		}
+		if bookmark == nil {
+			return "", failure.New(failure.NotFound, "bookmark '%s' not found", noteBookmark)
+		}
+		return projectRootOf(bookmark.Path), nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return projectRootOf(dir), nil
+}
+
+// projectRootOf returns the project of dir, the Git repository around it or
+// else dir itself, normalized like bookmark paths.
+func projectRootOf(dir string) string {
+	if output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output(); err == nil {
+		if top := strings.TrimSpace(string(output)); top != "" {
+			dir = top
+		}
+	}
+	return config.NormalizePath(dir)
+}
+
+// describeNoteProject names the project of notes, with ~ for the home
+// directory.
+func describeNoteProject(project string) string {
+	if project == "" {
+		return "global notes"
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if project == home {
+			return "~"
+		}
+		if rest, ok := strings.CutPrefix(project, home+string(os.PathSeparator)); ok {
+			return "~" + string(os.PathSeparator) + rest
+		}
+	}
+	return project
+}
+
+// editNote lets the user write a note in their editor, starting with text.
+func editNote(text string) (string, error) {
+	file, err := os.CreateTemp("", "aura-note-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write to temp file: %w", err)
+	}
+	file.Close()
+
+	command := editor.Resolve("")
+	if command == "" {
+		command = platform.Current().FallbackEditor()
+	}
+	if err := editor.Open(command, file.Name(), true); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read the note: %w", err)
+	}
+	return string(edited), nil
+}
+
+func init() {
+	for _, command := range []*cobra.Command{noteAddCmd, noteListCmd, noteSearchCmd} {
+		command.Flags().StringVarP(&noteBookmark, "bookmark", "b", "", "Use the project of this bookmark")
+		command.Flags().BoolVarP(&noteGlobal, "global", "g", false, "Use the global notes")
+	}
+	noteListCmd.Flags().BoolVarP(&noteAll, "all", "a", false, "List the notes of every project")
+	noteSearchCmd.Flags().IntVarP(&noteLimit, "limit", "n", 10, "Maximum number of notes to show")
+	noteCmd.AddCommand(noteAddCmd)
+	noteCmd.AddCommand(noteListCmd)
+	noteCmd.AddCommand(noteSearchCmd)
+	noteCmd.AddCommand(noteEditCmd)
+	noteCmd.AddCommand(noteRemoveCmd)
+	rootCmd.AddCommand(noteCmd)
+}
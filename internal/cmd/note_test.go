@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+func TestPrintNotes(t *testing.T) {
+	day := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	var out bytes.Buffer
+	printNotes(&out, []db.Note{
+		{ID: 12, Project: "/src/api", Body: "deploy: make release\nthen tag the commit", UpdatedAt: day},
+		{ID: 3, Body: "coffee on floor 3", UpdatedAt: day},
+		{ID: 1, Project: "/src/api", Body: "staging needs the VPN", UpdatedAt: day},
+	})
+	want := `/src/api
+  12  2025-06-01  deploy: make release
+                  then tag the commit
+   1  2025-06-01  staging needs the VPN
+
+global notes
+   3  2025-06-01  coffee on floor 3
+`
+	if out.String() != want {
+		t.Errorf("printNotes() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	snippet := "…how to " + db.SnippetStart + "deploy" + db.SnippetEnd + ":\n  make " + db.SnippetStart + "release" + db.SnippetEnd
+	if got, want := highlightSnippet(snippet), "…how to deploy: make release"; got != want {
+		t.Errorf("highlightSnippet() = %q, want %q", got, want)
+	}
+}
+
+func TestDescribeNoteProject(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := map[string]string{
+		"":                             "global notes",
+		home:                           "~",
+		filepath.Join(home, "src/api"): filepath.Join("~", "src/api"),
+		"/opt/tools":                   "/opt/tools",
+	}
+	for project, want := range tests {
+		if got := describeNoteProject(project); got != want {
+			t.Errorf("describeNoteProject(%q) = %q, want %q", project, got, want)
+		}
+	}
+}
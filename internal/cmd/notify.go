@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/notify"
+)
+
+// notifyFinished shows a desktop notification that an operation of command
+// finished, if it ran long enough for the notification settings. Without
+// notification support it stays silent, the result is in the terminal too.
+func notifyFinished(command string, elapsed time.Duration, title, message string) {
+	if notify.Due(command, elapsed) {
+		sendNotification(title, message)
+	}
+}
+
+// sendNotification shows a desktop notification, warning if it fails.
+func sendNotification(title, message string) {
+	if err := notify.Send(title, message); err != nil && !errors.Is(err, notify.ErrUnavailable) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+// onEnterCmd is run by the shell integration after `aura go` changed the
+// directory. Its output is evaluated by the shell.
+var onEnterCmd = &cobra.Command{
+	Use:   "on-enter [directory]",
+	Short: "Print the on-enter commands of bookmarks for the shell integration",
+	Long: `Print the on-enter commands of the bookmarks pointing to the directory, for
+the shell integration to evaluate after 'aura go' changed into it.
+
+A command runs only after you approved it once. Changing the command of a
+bookmark requires approving it again.`,
+	Hidden: true,
+	Args:   cobra.MaximumNArgs(1),
+	RunE:   runOnEnter,
+}
+
+// onEnterTrustedKey is the bookmark metadata key holding the approved
+// on-enter command.
+const onEnterTrustedKey = "on_enter_trusted"
+
+func runOnEnter(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if len(args) == 1 {
+		if dir, err = filepath.Abs(args[0]); err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	for _, bookmark := range bookmarks {
+		if config.NormalizePath(bookmark.Path) != config.NormalizePath(dir) {
+			continue
+		}
+
+		metadata, err := database.GetBookmarkMetadata(bookmark.Alias)
+		if err != nil {
+			return fmt.Errorf("failed to get bookmark metadata: %w", err)
+		}
+		command := metadata["on_enter"]
+		if command == "" {
+			continue
+		}
+
+		if metadata[onEnterTrustedKey] != command {
+			if !approveOnEnter(bookmark.Alias, command) {
+				continue
+			}
+			if err := database.SetBookmarkMetadata(bookmark.Alias, onEnterTrustedKey, command); err != nil {
+				return fmt.Errorf("failed to approve on-enter command: %w", err)
+			}
+		}
+		if !allowShellCommand(command, dir, fmt.Sprintf("the on-enter command of '%s'", bookmark.Alias)) {
+			continue
+		}
+
+		fmt.Println(command)
+	}
+	return nil
+}
+
+// approveOnEnter asks on stderr whether the on-enter command of a bookmark
+// may run, since stdout is evaluated by the shell.
+func approveOnEnter(alias, command string) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "Skipping unapproved on-enter command of '%s', run 'aura go %s' in a terminal to approve it\n", alias, alias)
+		return false
+	}
+
+	fmt.Fprintf(os.Stderr, "Bookmark '%s' runs this command when you enter it:\n  %s\n", alias, command)
+	prompt := promptui.Prompt{
+		Label:     "Allow this command",
+		IsConfirm: true,
+		Stdout:    stderrCloser{os.Stderr},
+	}
+	_, err := prompt.Run()
+	return err == nil
+}
+
+// stderrCloser lets prompts write to stderr without closing it.
+type stderrCloser struct{ io.Writer }
+
+func (stderrCloser) Close() error { return nil }
+
+func init() {
+	rootCmd.AddCommand(onEnterCmd)
+}
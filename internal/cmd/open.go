@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/editor"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/open"
+	"github.com/timfewi/aura-cli-go/internal/remote"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <alias|path|url>",
+	Short: "Open a bookmark, file or URL",
+	Long: `Open a bookmark, file or URL in the application that fits it: directories
+in the file manager, text files in your editor, URLs in the browser and other
+files in the application the desktop associates with them. Cloud storage
+bookmarks open where they are mounted, see 'aura go --help', or else in the
+provider's web console.
+
+A name is looked up as a bookmark first. Paths starting with ., ~ or /, or
+containing a separator, are always taken as paths.
+
+Examples:
+  aura open notes              # Bookmarked directory in the file manager
+  aura open notes --editor     # The same directory in your editor
+  aura open README.md          # Text file in your editor
+  aura open README.md --app    # Text file in its default application
+  aura open report.pdf
+  aura open https://go.dev
+  aura open logs               # s3://my-bucket/logs in the AWS console`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpen,
+}
+
+var (
+	openEditor bool
+	openApp    bool
+)
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	if openEditor && openApp {
+		return failure.New(failure.UserInput, "use either --editor or --app")
+	}
+
+	target := args[0]
+	if !open.IsURL(target) && !looksLikePath(target) {
+		target = bookmarkTarget(target)
+	}
+
+	if location, ok := remote.Parse(target); ok {
+		if path, mounted := location.MountedPath(config.UserSettings.Navigation.Mounts); mounted {
+			target = path
+		} else if target = location.ConsoleURL(); target == "" {
+			return failure.New(failure.UserInput, "%s has no web console, mount it to open it, see 'aura go --help'", location.URI())
+		}
+	}
+	if open.IsURL(target) {
+		return startOpen(target)
+	}
+
+	path, err := filepath.Abs(config.ExpandHome(target))
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return failure.New(failure.NotFound, "'%s' is neither a bookmark nor an existing path", args[0])
+	}
+
+	if openEditor || (!openApp && !info.IsDir() && open.IsText(path)) {
+		command := editor.Resolve("")
+		if command != "" {
+			return editor.Open(command, path, false)
+		}
+		if openEditor {
+			return failure.New(failure.Config, "no editor found, set one with: aura config set editor code")
+		}
+	}
+	return startOpen(path)
+}
+
+// looksLikePath reports whether target is meant as a path rather than a
+// bookmark alias.
+func looksLikePath(target string) bool {
+	return strings.HasPrefix(target, ".") || strings.HasPrefix(target, "~") || strings.ContainsAny(target, `/\`) || filepath.IsAbs(target)
+}
+
+// bookmarkTarget returns the path of the bookmark alias, or alias itself if
+// there is no such bookmark.
+func bookmarkTarget(alias string) string {
+	database, err := db.New()
+	if err != nil {
+		return alias
+	}
+	defer database.Close()
+
+	bookmark, err := database.GetBookmark(alias)
+	if err != nil || bookmark == nil {
+		return alias
+	}
+	recordBookmarkUse(database, alias)
+	return bookmark.Path
+}
+
+// startOpen opens target in the application the desktop associates with it.
+func startOpen(target string) error {
+	if err := open.Start(target); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Opened %s\n", target)
+	return nil
+}
+
+func init() {
+	openCmd.Flags().BoolVar(&openEditor, "editor", false, "Open in your editor, even directories")
+	openCmd.Flags().BoolVar(&openApp, "app", false, "Open in the default application, even text files")
+	rootCmd.AddCommand(openCmd)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/archive"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+var packCmd = &cobra.Command{
+	Use:   "pack <dir> [archive]",
+	Short: "Pack a directory into an archive",
+	Long: `Pack a directory or bookmark into an archive, tar.gz by default, or zip on
+Windows. The format follows the name of the archive or --format: tar.gz,
+tar.xz, tar, zip or 7z. Tar, gzip and zip need no other tools, tar.xz needs
+xz and 7z needs 7-Zip.
+
+Examples:
+  aura pack project                    # project.tar.gz
+  aura pack project backup.zip
+  aura pack api --format tar.xz        # The directory of a bookmark`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runPack,
+}
+
+var (
+	packFormat string
+	packForce  bool
+)
+
+func runPack(cmd *cobra.Command, args []string) error {
+	target := args[0]
+	if !looksLikePath(target) {
+		target = bookmarkTarget(target)
+	}
+	dir, err := filepath.Abs(config.ExpandHome(target))
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return failure.New(failure.NotFound, "'%s' is neither a bookmark nor a directory", args[0])
+	}
+
+	output := ""
+	if len(args) > 1 {
+		output = config.ExpandHome(args[1])
+	}
+	format, output, err := packTarget(filepath.Base(dir), output, packFormat, platform.Current().IsWindows())
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(output); err == nil {
+		if !packForce {
+			return failure.New(failure.UserInput, "%s already exists, use --force to replace it", output)
+		}
+		if err := os.Remove(output); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", output, err)
+		}
+	}
+
+	if err := archive.Create(dir, output, format); err != nil {
+		return err
+	}
+	size := int64(0)
+	if info, err := os.Stat(output); err == nil {
+		size = info.Size()
+	}
+	fmt.Printf("✓ Packed %s into %s (%s)\n", dir, output, formatSize(size))
+	return nil
+}
+
+// packTarget returns the format and file of the archive of a directory
+// called name. The format comes from flag, or else the extension of output,
+// or else is the default of the system; the file is output, named after the
+// directory if empty.
+func packTarget(name, output, flag string, windows bool) (archive.Format, string, error) {
+	var format archive.Format
+	if flag != "" {
+		format = archive.Format(strings.TrimPrefix(strings.ToLower(flag), "."))
+		if format == "tgz" {
+			format = archive.TarGz
+		}
+		if !isArchiveFormat(format) {
+			return "", "", failure.New(failure.UserInput, "unknown format '%s', use tar.gz, tar.xz, tar, zip or 7z", flag)
+		}
+	}
+
+	if output != "" {
+		named := archive.FormatOf(output)
+		switch {
+		case format == "" && named != "":
+			format = named
+		case format != "" && named != "" && named != format:
+			return "", "", failure.New(failure.UserInput, "%s doesn't end in .%s", output, format)
+		}
+	}
+	if format == "" {
+		format = archive.TarGz
+		if windows {
+			format = archive.Zip
+		}
+	}
+	if output == "" {
+		output = name
+	}
+	if archive.FormatOf(output) == "" {
+		output += "." + string(format)
+	}
+	return format, output, nil
+}
+
+func isArchiveFormat(format archive.Format) bool {
+	for _, known := range archive.Formats {
+		if format == known {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	packCmd.Flags().StringVarP(&packFormat, "format", "f", "", "Archive format: tar.gz, tar.xz, tar, zip or 7z")
+	packCmd.Flags().BoolVar(&packForce, "force", false, "Replace an existing archive")
+	rootCmd.AddCommand(packCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/archive"
+)
+
+func TestPackTarget(t *testing.T) {
+	tests := []struct {
+		name, output, flag string
+		windows            bool
+		wantFormat         archive.Format
+		wantOutput         string
+		wantErr            bool
+	}{
+		{name: "project", wantFormat: archive.TarGz, wantOutput: "project.tar.gz"},
+		{name: "project", windows: true, wantFormat: archive.Zip, wantOutput: "project.zip"},
+		{name: "project", flag: "7z", wantFormat: archive.SevenZip, wantOutput: "project.7z"},
+		{name: "project", flag: ".TGZ", wantFormat: archive.TarGz, wantOutput: "project.tar.gz"},
+		{name: "project", output: "backup.tar.xz", wantFormat: archive.TarXz, wantOutput: "backup.tar.xz"},
+		{name: "project", output: "backup", flag: "zip", wantFormat: archive.Zip, wantOutput: "backup.zip"},
+		{name: "project", output: "backup.zip", flag: "tar.gz", wantErr: true},
+		{name: "project", flag: "rar", wantErr: true},
+	}
+	for _, tt := range tests {
+		format, output, err := packTarget(tt.name, tt.output, tt.flag, tt.windows)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("packTarget(%q, %q) succeeded", tt.output, tt.flag)
+			}
+			continue
+		}
+		if err != nil || format != tt.wantFormat || output != tt.wantOutput {
+			t.Errorf("packTarget(%q, %q) = %q, %q, %v, want %q, %q", tt.output, tt.flag, format, output, err, tt.wantFormat, tt.wantOutput)
+		}
+	}
+}
+
+func TestUnpackDestination(t *testing.T) {
+	single := []archive.Entry{{Name: "project/", Mode: os.ModeDir}, {Name: "project/main.go"}}
+	if dir, shown := unpackDestination("release.tar.gz", "", single); dir != "." || shown != "project" {
+		t.Errorf("unpackDestination() of a single directory = %q, %q", dir, shown)
+	}
+	loose := []archive.Entry{{Name: "main.go"}, {Name: "go.mod"}}
+	if dir, shown := unpackDestination("release.tar.gz", "", loose); dir != "release" || shown != "release" {
+		t.Errorf("unpackDestination() of loose files = %q, %q", dir, shown)
+	}
+	file := []archive.Entry{{Name: "main.go"}}
+	if dir, _ := unpackDestination("single.zip", "", file); dir != "single" {
+		t.Errorf("unpackDestination() of a single file = %q, want single", dir)
+	}
+	if dir, _ := unpackDestination("release.tar.gz", "out", single); dir != "out" {
+		t.Errorf("unpackDestination() with a destination = %q, want out", dir)
+	}
+}
+
+func TestPrintArchive(t *testing.T) {
+	var out bytes.Buffer
+	printArchive(&out, "release.tar.gz", []archive.Entry{
+		{Name: "project/", Mode: os.ModeDir},
+		{Name: "project/main.go", Size: 2048},
+		{Name: "project/current", Mode: os.ModeSymlink, Link: "main.go"},
+	}, 2)
+	want := `release.tar.gz: 2 files, 1 directory, 2.0 KiB unpacked
+              project/
+     2.0 KiB  project/main.go
+  … and 1 more
+`
+	if out.String() != want {
+		t.Errorf("printArchive() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
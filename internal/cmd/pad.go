@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var padCmd = &cobra.Command{
+	Use:   "pad",
+	Short: "Keep piped text in named scratch buffers",
+	Long:  "Save piped text in named scratch buffers kept in the Aura database and pipe it into other commands later.",
+}
+
+var padSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save piped text in a pad",
+	Long: `Save the text piped into the command in the pad called name, replacing
+what it held. A pad holds up to 1 MiB and all pads together up to 16 MiB.
+
+Examples:
+  go build ./... 2>&1 | aura pad save build-log
+  go test ./... 2>&1 | aura pad save test-log --tee
+  tail -f app.log | aura pad save app-log --tail   # Keep the last 1 MiB
+  date | aura pad save notes --append`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPadSave,
+}
+
+var padShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print the text of a pad",
+	Long: `Print the text of a pad, to read it or pipe it into another command.
+
+Examples:
+  aura pad show build-log
+  aura pad show build-log | grep error`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPadShow,
+}
+
+var padListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List pads",
+	Args:    cobra.NoArgs,
+	RunE:    runPadList,
+}
+
+var padRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a pad",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runPadRemove,
+}
+
+var (
+	padAppend bool
+	padTail   bool
+	padTee    bool
+)
+
+// Limits of the size of a pad and of all pads together.
+const (
+	padMaxSize   = 1 << 20
+	padTotalSize = 16 << 20
+)
+
+// padBuffer collects the content of a pad up to limit bytes. Beyond it the
+// oldest bytes are dropped with tail, and the newest otherwise.
+type padBuffer struct {
+	limit   int
+	tail    bool
+	data    []byte
+	dropped int64
+}
+
+func (b *padBuffer) Write(p []byte) (int, error) {
+	if !b.tail {
+		keep := min(len(p), b.limit-len(b.data))
+		b.data = append(b.data, p[:keep]...)
+		b.dropped += int64(len(p) - keep)
+		return len(p), nil
+	}
+
+	b.data = append(b.data, p...)
+	// Dropping only once twice the limit is reached saves moving the data
+	// on every write.
+	if len(b.data) > 2*b.limit {
+		b.trim()
+	}
+	return len(p), nil
+}
+
+// content returns the collected content.
+func (b *padBuffer) content() []byte {
+	if b.tail {
+		b.trim()
+	}
+	return b.data
+}
+
+func (b *padBuffer) trim() {
+	if over := len(b.data) - b.limit; over > 0 {
+		b.dropped += int64(over)
+		b.data = append(b.data[:0], b.data[over:]...)
+	}
+}
+
+func runPadSave(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if strings.ContainsAny(name, " \t/") {
+		return failure.New(failure.UserInput, "invalid pad name '%s'", name)
+	}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return failure.New(failure.UserInput, "pipe the text to save into the command, as in 'make 2>&1 | aura pad save %s'", name)
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	buffer := &padBuffer{limit: padMaxSize, tail: padTail}
+	if padAppend {
+		existing, err := database.GetPad(name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			buffer.Write(existing.Content)
+		}
+	}
+
+	// The input is read to its end even beyond the limit, so the command
+	// writing it isn't cut off and --tee passes all of it on.
+	var w io.Writer = buffer
+	if padTee {
+		w = io.MultiWriter(buffer, os.Stdout)
+	}
+	if _, err := io.Copy(w, os.Stdin); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	content := buffer.content()
+	if buffer.dropped > 0 && !padTail {
+		return failure.New(failure.UserInput, "the text is larger than the %s a pad holds, keep the end of it with --tail", formatSize(padMaxSize))
+	}
+
+	pads, err := database.ListPads()
+	if err != nil {
+		return err
+	}
+	total := int64(len(content))
+	for _, pad := range pads {
+		if pad.Name != name {
+			total += pad.Size
+		}
+	}
+	if total > padTotalSize {
+		return failure.New(failure.UserInput, "pads would take %s of the %s allowed, remove some with 'aura pad rm <name>'", formatSize(total), formatSize(padTotalSize))
+	}
+
+	if err := database.SavePad(name, content); err != nil {
+		return err
+	}
+	if buffer.dropped > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: dropped the first %s to keep the last %s\n", formatSize(buffer.dropped), formatSize(padMaxSize))
+	}
+	fmt.Fprintf(os.Stderr, "✓ Saved %s in pad '%s'\n", formatSize(int64(len(content))), name)
+	return nil
+}
+
+func runPadShow(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	pad, err := database.GetPad(args[0])
+	if err != nil {
+		return err
+	}
+	if pad == nil {
+		return failure.New(failure.NotFound, "pad '%s' not found", args[0])
+	}
+	_, err = os.Stdout.Write(pad.Content)
+	return err
+}
+
+func runPadList(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	pads, err := database.ListPads()
+	if err != nil {
+		return err
+	}
+	if len(pads) == 0 {
+		fmt.Println("No pads yet. Save one with 'command | aura pad save <name>'.")
+		return nil
+	}
+
+	width := 0
+	for _, pad := range pads {
+		width = max(width, len(pad.Name))
+	}
+	for _, pad := range pads {
+		fmt.Printf("%-*s  %10s  %s\n", width, pad.Name, formatSize(pad.Size),
+			theme.Paint(theme.Muted, pad.UpdatedAt.Local().Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func runPadRemove(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	removed, err := database.RemovePad(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return failure.New(failure.NotFound, "pad '%s' not found", args[0])
+	}
+	fmt.Printf("✓ Removed pad '%s'\n", args[0])
+	return nil
+}
+
+func init() {
+	padSaveCmd.Flags().BoolVarP(&padAppend, "append", "a", false, "Add the text to the end of the pad")
+	padSaveCmd.Flags().BoolVar(&padTail, "tail", false, "Keep the last 1 MiB of larger text instead of failing")
+	padSaveCmd.Flags().BoolVar(&padTee, "tee", false, "Also pass the text on to standard output")
+	padCmd.AddCommand(padSaveCmd)
+	padCmd.AddCommand(padShowCmd)
+	padCmd.AddCommand(padListCmd)
+	padCmd.AddCommand(padRemoveCmd)
+	rootCmd.AddCommand(padCmd)
+}
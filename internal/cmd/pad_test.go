@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPadBuffer(t *testing.T) {
+	tests := []struct {
+		name        string
+		tail        bool
+		writes      []string
+		want        string
+		wantDropped int64
+	}{
+		{name: "within the limit", writes: []string{"ab", "cd"}, want: "abcd"},
+		{name: "beyond the limit", writes: []string{"abcd", "efgh", "ij"}, want: "abcdefgh", wantDropped: 2},
+		{name: "tail within the limit", tail: true, writes: []string{"ab", "cd"}, want: "abcd"},
+		{name: "tail beyond the limit", tail: true, writes: []string{"abcd", "efgh", "ijkl", "mnopqrst", "u"}, want: "nopqrstu", wantDropped: 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &padBuffer{limit: 8, tail: tt.tail}
+			for _, write := range tt.writes {
+				if n, err := buffer.Write([]byte(write)); err != nil || n != len(write) {
+					t.Fatalf("Write(%q) = %d, %v", write, n, err)
+				}
+			}
+			if got := string(buffer.content()); got != tt.want || buffer.dropped != tt.wantDropped {
+				t.Errorf("content() = %q with %d dropped, want %q with %d", got, buffer.dropped, tt.want, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestPadBufferLargeTail(t *testing.T) {
+	buffer := &padBuffer{limit: 1000, tail: true}
+	for i := 0; i < 100; i++ {
+		buffer.Write([]byte(strings.Repeat(string(rune('a'+i%26)), 100)))
+	}
+	got := buffer.content()
+	if len(got) != 1000 || buffer.dropped != 9000 || got[999] != byte('a'+99%26) {
+		t.Errorf("content() = %d bytes ending in %q with %d dropped", len(got), got[len(got)-1], buffer.dropped)
+	}
+}
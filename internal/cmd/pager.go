@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/pager"
+)
+
+// paged returns run with its output shown in the pager, see pageOutput.
+func paged(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return pageOutput(func() error { return run(cmd, args) })
+	}
+}
+
+// pageOutput calls run and shows what it prints in the pager when stdout is
+// a terminal and the output is taller than it. Otherwise, or without a
+// pager, the output is printed directly. run must not ask anything, as its
+// output is held back until it returns.
+func pageOutput(run func() error) error {
+	command := pager.Resolve()
+	fd := int(os.Stdout.Fd())
+	if command == "" || !term.IsTerminal(fd) {
+		return run()
+	}
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		return run()
+	}
+
+	output, err := captureStdout(run)
+	// The last row stays free for the prompt of the shell
+	if pager.Fits(output, width, height-1) {
+		io.WriteString(os.Stdout, output)
+		return err
+	}
+
+	// Ctrl+C reaches the pager, which decides how to stop
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+	if pageErr := pager.Page(command, output); pageErr != nil {
+		io.WriteString(os.Stdout, output)
+	}
+	return err
+}
+
+// captureStdout calls run and returns what it printed to stdout.
+func captureStdout(run func() error) (string, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return "", run()
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	var output bytes.Buffer
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(&output, reader)
+		reader.Close()
+		close(copied)
+	}()
+
+	err = run()
+	writer.Close()
+	<-copied
+	return output.String(), err
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCaptureStdout(t *testing.T) {
+	failed := errors.New("failed")
+	var want strings.Builder
+	output, err := captureStdout(func() error {
+		// More than the buffer of the pipe
+		for i := 0; i < 10000; i++ {
+			fmt.Printf("line %d\n", i)
+			fmt.Fprintf(&want, "line %d\n", i)
+		}
+		return failed
+	})
+	if err != failed {
+		t.Errorf("captureStdout() error = %v, want the error of run", err)
+	}
+	if output != want.String() {
+		t.Errorf("captureStdout() = %d bytes, want all %d", len(output), want.Len())
+	}
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/finder"
+)
+
+// paletteEntry is an item of the command palette and what picking it does.
+type paletteEntry struct {
+	item finder.Item
+	run  func() error
+}
+
+// shellWrappedCommands are handled by the shell integration, so picking them
+// in the palette hands them to the shell instead of running them in Aura.
+var shellWrappedCommands = map[string]bool{"go": true, "alias": true}
+
+// runPalette opens a fuzzy finder over the actions of the current directory,
+// the bookmarks and all commands. Without a terminal it prints the help.
+func runPalette(cmd *cobra.Command, args []string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return cmd.Help()
+	}
+
+	var entries []paletteEntry
+	for _, action := range detectActions() {
+		entries = append(entries, actionPaletteEntry(action))
+	}
+	entries = append(entries, bookmarkPaletteEntries()...)
+	entries = append(entries, commandPaletteEntries(cmd)...)
+
+	items := make([]finder.Item, len(entries))
+	for i, entry := range entries {
+		items[i] = entry.item
+	}
+
+	selected, err := finder.Select(items, finder.Options{
+		Prompt: "aura",
+		Preview: func(item finder.Item) string {
+			return item.Value
+		},
+		FzfPreview: "echo {}",
+	})
+	if err != nil {
+		if errors.Is(err, finder.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+	return entries[selected].run()
+}
+
+func actionPaletteEntry(action context.Action) paletteEntry {
+	return paletteEntry{
+		item: finder.Item{Label: paletteLabel("action", action.Name), Value: action.Command},
+		run:  func() error { return runAction(action) },
+	}
+}
+
+// bookmarkPaletteEntries returns entries going to the bookmarks. Bookmarks
+// are left out if the database is unavailable.
+func bookmarkPaletteEntries() []paletteEntry {
+	database, err := db.New()
+	if err != nil {
+		return nil
+	}
+	defer database.Close()
+
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return nil
+	}
+
+	entries := make([]paletteEntry, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		alias := bookmark.Alias
+		entries[i] = paletteEntry{
+			item: finder.Item{Label: paletteLabel("go", alias), Value: bookmark.Path},
+			run:  func() error { return runPaletteCommand([]string{"go", alias}) },
+		}
+	}
+	return entries
+}
+
+// commandPaletteEntries returns entries for the runnable commands below cmd.
+func commandPaletteEntries(cmd *cobra.Command) []paletteEntry {
+	var entries []paletteEntry
+	for _, child := range cmd.Commands() {
+		if !child.IsAvailableCommand() || child.Name() == "help" || child.Name() == "completion" {
+			continue
+		}
+		if child.Runnable() {
+			command := child
+			path := strings.TrimPrefix(command.CommandPath(), command.Root().Name()+" ")
+			entries = append(entries, paletteEntry{
+				item: finder.Item{Label: paletteLabel("command", path+" — "+command.Short), Value: command.UseLine()},
+				run:  func() error { return runPaletteSubcommand(command, strings.Fields(path)) },
+			})
+		}
+		entries = append(entries, commandPaletteEntries(child)...)
+	}
+	return entries
+}
+
+// paletteLabel prefixes label with the kind of entry.
+func paletteLabel(kind, label string) string {
+	return fmt.Sprintf("%-8s %s", kind, label)
+}
+
+// requiredArgPattern matches placeholders of required arguments in the usage
+// of a command, such as <alias>.
+var requiredArgPattern = regexp.MustCompile(`<[^>]+>`)
+
+// runPaletteSubcommand asks for the arguments command requires and runs it.
+func runPaletteSubcommand(command *cobra.Command, path []string) error {
+	if command.ValidateArgs(nil) != nil || requiredArgPattern.MatchString(command.Use) {
+		prompt := promptui.Prompt{Label: "aura " + strings.Join(path, " ")}
+		input, err := prompt.Run()
+		if err != nil {
+			if errors.Is(err, promptui.ErrInterrupt) {
+				return nil
+			}
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		path = append(path, strings.Fields(input)...)
+	}
+	return runPaletteCommand(path)
+}
+
+// runPaletteCommand runs aura with args in a new process, or hands it to the
+// shell integration for commands it wraps.
+func runPaletteCommand(args []string) error {
+	if shellWrappedCommands[args[0]] && envvar.EvalFile.Get() != "" {
+		quoted := make([]string, len(args))
+		for i, arg := range args {
+			quoted[i] = quoteShellArg(context.ShellKind(), arg)
+		}
+		return emitShellCommand("aura " + strings.Join(quoted, " "))
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the aura executable: %w", err)
+	}
+	command := exec.Command(executable, args...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// The command printed its error already
+			os.Exit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("failed to run aura: %w", err)
+	}
+	return nil
+}
+
+// safeShellArg matches arguments that need no quotes in any shell.
+var safeShellArg = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+-]+$`)
+
+// quoteShellArg quotes arg for shell, one of "bash", "zsh", "fish" or
+// "powershell".
+func quoteShellArg(shell, arg string) string {
+	if safeShellArg.MatchString(arg) {
+		return arg
+	}
+	switch shell {
+	case "fish":
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(arg) + "'"
+	case "powershell", "pwsh":
+		return "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandPaletteEntries(t *testing.T) {
+	labels := map[string]bool{}
+	for _, entry := range commandPaletteEntries(rootCmd) {
+		labels[strings.Fields(entry.item.Label)[1]+" "+strings.Fields(entry.item.Label)[2]] = true
+	}
+
+	for _, want := range []string{"bookmark add", "undo —", "tour —"} {
+		if !labels[want] {
+			t.Errorf("Expected an entry for %s", want)
+		}
+	}
+	for _, unwanted := range []string{"help —", "completion —", "bookmark —"} {
+		if labels[unwanted] {
+			t.Errorf("Unexpected entry for %s", unwanted)
+		}
+	}
+}
+
+func TestQuoteShellArg(t *testing.T) {
+	tests := []struct {
+		shell string
+		arg   string
+		want  string
+	}{
+		{"bash", "notes", "notes"},
+		{"bash", "my notes", "'my notes'"},
+		{"zsh", "it's", `'it'\''s'`},
+		{"fish", "it's", `'it\'s'`},
+		{"powershell", "it's", "'it''s'"},
+	}
+
+	for _, tt := range tests {
+		if got := quoteShellArg(tt.shell, tt.arg); got != tt.want {
+			t.Errorf("quoteShellArg(%s, %q) = %s, want %s", tt.shell, tt.arg, got, tt.want)
+		}
+	}
+}
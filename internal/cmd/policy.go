@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Show the policy of your organization",
+	Long: `Show the policy your organization set for Aura. Administrators provision the
+policy file, such as with MDM, at /etc/aura/policy.yaml, /Library/Application
+Support/Aura/policy.yaml on macOS or %ProgramData%\Aura\policy.yaml on
+Windows, writable only by them. Settings, team settings, flags and
+environment variables can't override it.
+
+  disable: [ai, commands, telemetry]
+  api_url: https://llm.example.com/v1
+  contact: it@example.com`,
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the restrictions of the policy",
+	Long: `Show the policy file, whether users can change it, and the features and API
+endpoint it restricts.
+
+Examples:
+  aura policy show`,
+	Args: cobra.NoArgs,
+	RunE: runPolicyShow,
+}
+
+// policyFeatures describes the features of config.Features.
+var policyFeatures = map[string]string{
+	config.FeatureAI:        "AI assistant",
+	config.FeatureCommands:  "running commands",
+	config.FeatureTelemetry: "telemetry (Aura sends none)",
+}
+
+func runPolicyShow(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(config.PolicyFile); os.IsNotExist(err) {
+		fmt.Printf("ℹ️  No policy, %s does not exist\n", displayHomePath(config.PolicyFile))
+		return nil
+	}
+
+	locked := theme.Paint(theme.Success, "locked")
+	if !config.PolicyLocked() {
+		locked = theme.Paint(theme.Warning, "writable by you, not locked")
+	}
+	fmt.Printf("%-14s %s %s\n", "Policy file:", displayHomePath(config.PolicyFile), locked)
+	if config.ActivePolicy.Contact != "" {
+		fmt.Printf("%-14s %s\n", "Contact:", config.ActivePolicy.Contact)
+	}
+	endpoint := theme.Paint(theme.Muted, "not pinned")
+	if config.ActivePolicy.APIURL != "" {
+		endpoint = config.ActivePolicy.APIURL + " " + theme.Paint(theme.Muted, "(pinned)")
+	}
+	fmt.Printf("%-14s %s\n", "API endpoint:", endpoint)
+
+	fmt.Printf("\n%s\n", theme.Paint(theme.Heading, "Features"))
+	for _, feature := range config.Features {
+		state := theme.Paint(theme.Success, "allowed ")
+		if config.Disabled(feature) {
+			state = theme.Paint(theme.Error, "disabled")
+		}
+		fmt.Printf("  %-10s %s  %s\n", feature, state, theme.Paint(theme.Muted, policyFeatures[feature]))
+	}
+	return nil
+}
+
+func init() {
+	policyCmd.AddCommand(policyShowCmd)
+	rootCmd.AddCommand(policyCmd)
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+var pomodoroCmd = &cobra.Command{
+	Use:   "pomodoro [task]",
+	Short: "Run pomodoro work and break timers with notifications",
+	Long: `Start a pomodoro session: rounds of focused work separated by short breaks,
+with a notification when each round and each break ends. The timers are
+reminders, so they run in the background, show up in 'aura remind list' and
+survive closing the terminal. Starting a session replaces the running one.
+
+Examples:
+  aura pomodoro
+  aura pomodoro "write the release notes"
+  aura pomodoro --work 50m --break 10m --rounds 2
+  aura pomodoro stop`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runPomodoro,
+}
+
+var pomodoroStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running pomodoro session",
+	Args:  cobra.NoArgs,
+	RunE:  runPomodoroStop,
+}
+
+var (
+	pomodoroWork   time.Duration
+	pomodoroBreak  time.Duration
+	pomodoroRounds int
+)
+
+// pomodoroStep is a notification of a pomodoro session.
+type pomodoroStep struct {
+	at      time.Time
+	message string
+}
+
+func runPomodoro(cmd *cobra.Command, args []string) error {
+	if pomodoroWork < time.Minute || pomodoroBreak < 0 {
+		return failure.New(failure.UserInput, "work rounds must be at least a minute and breaks cannot be negative")
+	}
+	if pomodoroRounds < 1 {
+		return failure.New(failure.UserInput, "--rounds must be at least 1")
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	replaced, err := database.CancelReminders("pomodoro")
+	if err != nil {
+		return err
+	}
+	steps := pomodoroSchedule(strings.Join(args, " "), time.Now(), pomodoroWork, pomodoroBreak, pomodoroRounds)
+	for _, step := range steps {
+		if _, err := database.AddReminder("pomodoro", step.message, step.at); err != nil {
+			return err
+		}
+	}
+	startReminderDelivery()
+
+	if replaced > 0 {
+		fmt.Println("ℹ️  Replaced the running pomodoro session")
+	}
+	fmt.Printf("🍅 %d × %s of work", pomodoroRounds, shortDuration(pomodoroWork))
+	if pomodoroRounds > 1 {
+		fmt.Printf(" with %s breaks", shortDuration(pomodoroBreak))
+	}
+	fmt.Printf(", done at %s\n", steps[len(steps)-1].at.Format("15:04"))
+	fmt.Println("Stop with 'aura pomodoro stop'.")
+	return nil
+}
+
+// pomodoroSchedule returns the notifications of a session of rounds starting
+// at start: one at the end of each round and, but for the last, of the break
+// after it.
+func pomodoroSchedule(task string, start time.Time, work, pause time.Duration, rounds int) []pomodoroStep {
+	of := ""
+	if task != "" {
+		of = fmt.Sprintf(" of %q", task)
+	}
+
+	var steps []pomodoroStep
+	at := start
+	for round := 1; round <= rounds; round++ {
+		at = at.Add(work)
+		if round == rounds {
+			steps = append(steps, pomodoroStep{at, fmt.Sprintf("Round %d/%d%s done, session complete", round, rounds, of)})
+			break
+		}
+		steps = append(steps, pomodoroStep{at, fmt.Sprintf("Round %d/%d%s done, take a %s break", round, rounds, of, shortDuration(pause))})
+		at = at.Add(pause)
+		steps = append(steps, pomodoroStep{at, fmt.Sprintf("Break over, start round %d/%d%s", round+1, rounds, of)})
+	}
+	return steps
+}
+
+func runPomodoroStop(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	cancelled, err := database.CancelReminders("pomodoro")
+	if err != nil {
+		return err
+	}
+	if cancelled == 0 {
+		fmt.Println("No pomodoro session is running.")
+		return nil
+	}
+	fmt.Println("✓ Stopped the pomodoro session")
+	return nil
+}
+
+func init() {
+	pomodoroCmd.Flags().DurationVar(&pomodoroWork, "work", 25*time.Minute, "Length of a work round")
+	pomodoroCmd.Flags().DurationVar(&pomodoroBreak, "break", 5*time.Minute, "Length of the breaks between rounds")
+	pomodoroCmd.Flags().IntVar(&pomodoroRounds, "rounds", 4, "Number of work rounds")
+	pomodoroCmd.AddCommand(pomodoroStopCmd)
+	rootCmd.AddCommand(pomodoroCmd)
+}
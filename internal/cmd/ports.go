@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	auracontext "github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/ports"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports [port]",
+	Short: "List listening ports and the processes holding them",
+	Long: `List the TCP ports processes listen on, with the process holding each one.
+Processes of other users show without a name unless Aura runs with more
+privileges.
+
+When a command run with 'aura exec' fails because its port is taken, Aura
+remembers it and 'aura do' offers to stop the process on that port.
+
+Examples:
+  aura ports              # All listening ports
+  aura ports 3000         # Who is on port 3000
+  aura ports kill 3000    # Stop the process on port 3000
+  aura ports kill :8080 --force`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPorts,
+}
+
+var portsKillCmd = &cobra.Command{
+	Use:   "kill <port>",
+	Short: "Stop the process listening on a port",
+	Long: `Stop the process listening on a port, asking it to terminate first. With
+--force it is killed right away, and on Windows the processes it started
+are stopped with it.
+
+Examples:
+  aura ports kill 3000
+  aura ports kill :5173 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPortsKill,
+}
+
+var (
+	portsForce bool
+	portsYes   bool
+)
+
+// portsTimeout limits how long listing the ports may take.
+const portsTimeout = 10 * time.Second
+
+// portsStopWait is how long a stopped process gets to free its port.
+const portsStopWait = 3 * time.Second
+
+func runPorts(cmd *cobra.Command, args []string) error {
+	listeners, err := listPorts()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		port, err := parsePort(args[0])
+		if err != nil {
+			return err
+		}
+		listeners = ports.OnPort(listeners, port)
+		if len(listeners) == 0 {
+			fmt.Printf("Nothing listens on port %d.\n", port)
+			return nil
+		}
+	}
+	if len(listeners) == 0 {
+		fmt.Println("No listening ports found.")
+		return nil
+	}
+
+	printPorts(os.Stdout, listeners)
+	return nil
+}
+
+func runPortsKill(cmd *cobra.Command, args []string) error {
+	port, err := parsePort(args[0])
+	if err != nil {
+		return err
+	}
+	listeners, err := listPorts()
+	if err != nil {
+		return err
+	}
+	listeners = ports.OnPort(listeners, port)
+	if len(listeners) == 0 {
+		forgetPortConflict(port)
+		fmt.Printf("✓ Nothing listens on port %d\n", port)
+		return nil
+	}
+
+	pids := map[int]string{}
+	for _, listener := range listeners {
+		if listener.PID == 0 {
+			return failure.New(failure.Config, "port %d is held by a process of another user, run 'aura ports kill %d' with more privileges", port, port)
+		}
+		pids[listener.PID] = listener.Process
+	}
+	if !confirmAction("ports kill", fmt.Sprintf("Stop %s on port %d", describeProcesses(pids), port), true, portsYes) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	for pid := range pids {
+		if err := ports.Stop(pid, portsForce); err != nil {
+			return fmt.Errorf("failed to stop process %d: %w", pid, err)
+		}
+	}
+	if !waitForPort(port) {
+		return fmt.Errorf("port %d is still in use, try again with --force", port)
+	}
+	forgetPortConflict(port)
+	fmt.Printf("✓ Stopped %s, port %d is free\n", describeProcesses(pids), port)
+	return nil
+}
+
+// listPorts lists the listening ports of the current platform.
+func listPorts() ([]ports.Listener, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), portsTimeout)
+	defer cancel()
+	return ports.List(ctx, platform.Current())
+}
+
+// waitForPort waits for port to be freed and reports whether it was.
+func waitForPort(port int) bool {
+	deadline := time.Now().Add(portsStopWait)
+	for {
+		listeners, err := listPorts()
+		if err == nil && len(ports.OnPort(listeners, port)) == 0 {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// parsePort parses a port given as "3000" or ":3000".
+func parsePort(text string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimPrefix(text, ":"))
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, failure.New(failure.UserInput, "invalid port '%s'", text)
+	}
+	return port, nil
+}
+
+// describeProcesses names processes by PID, such as "node (PID 4242)".
+func describeProcesses(pids map[int]string) string {
+	var names []string
+	for _, pid := range slices.Sorted(maps.Keys(pids)) {
+		name := pids[pid]
+		if name == "" {
+			name = "process"
+		}
+		names = append(names, fmt.Sprintf("%s (PID %d)", name, pid))
+	}
+	return strings.Join(names, ", ")
+}
+
+// printPorts prints listeners as a table.
+func printPorts(w io.Writer, listeners []ports.Listener) {
+	addressWidth := len("ADDRESS")
+	for _, listener := range listeners {
+		addressWidth = max(addressWidth, len(listener.Address))
+	}
+	fmt.Fprintf(w, "%s\n", theme.Paint(theme.Heading, fmt.Sprintf("%-6s  %-*s  %-7s  %s", "PORT", addressWidth, "ADDRESS", "PID", "PROCESS")))
+	for _, listener := range listeners {
+		pid, process := "-", listener.Process
+		if listener.PID != 0 {
+			pid = strconv.Itoa(listener.PID)
+		}
+		if process == "" {
+			process = theme.Paint(theme.Muted, "(other user)")
+		}
+		fmt.Fprintf(w, "%-6d  %-*s  %-7s  %s\n", listener.Port, addressWidth, listener.Address, pid, process)
+	}
+}
+
+// portConflictsFile returns the file remembering, by directory, the port a
+// command run there failed to listen on.
+func portConflictsFile() string {
+	return filepath.Join(config.StateDir, "port_conflicts.json")
+}
+
+// loadPortConflicts reads the remembered port conflicts. A missing or
+// broken file means there are none.
+func loadPortConflicts() map[string]int {
+	conflicts := map[string]int{}
+	if data, err := os.ReadFile(portConflictsFile()); err == nil {
+		_ = json.Unmarshal(data, &conflicts)
+	}
+	return conflicts
+}
+
+func savePortConflicts(conflicts map[string]int) error {
+	data, err := json.MarshalIndent(conflicts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(portConflictsFile(), data, 0644)
+}
+
+// reportPortConflict remembers that a command in the current directory
+// failed because port was taken, for 'aura do', and tells who holds it.
+func reportPortConflict(port int) {
+	if dir, err := os.Getwd(); err == nil {
+		conflicts := loadPortConflicts()
+		conflicts[dir] = port
+		if err := savePortConflicts(conflicts); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remember the port conflict: %v\n", err)
+		}
+	}
+
+	holder := "another process"
+	if listeners, err := listPorts(); err == nil {
+		pids := map[int]string{}
+		for _, listener := range ports.OnPort(listeners, port) {
+			if listener.PID != 0 {
+				pids[listener.PID] = listener.Process
+			}
+		}
+		if len(pids) > 0 {
+			holder = describeProcesses(pids)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "ℹ️  Port %d is in use by %s. Stop it with 'aura ports kill %d' or from 'aura do'.\n", port, holder, port)
+}
+
+// forgetPortConflict forgets the conflicts on port once it is free.
+func forgetPortConflict(port int) {
+	conflicts := loadPortConflicts()
+	changed := false
+	for dir, conflict := range conflicts {
+		if conflict == port {
+			delete(conflicts, dir)
+			changed = true
+		}
+	}
+	if changed {
+		_ = savePortConflicts(conflicts)
+	}
+}
+
+// detectPortConflictActions offers to stop the process holding the port a
+// command in the current directory failed to listen on, while it still
+// does.
+func detectPortConflictActions() []auracontext.Action {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	port, ok := loadPortConflicts()[dir]
+	if !ok {
+		return nil
+	}
+	listeners, err := listPorts()
+	if err != nil {
+		return nil
+	}
+	return portConflictActions(port, ports.OnPort(listeners, port))
+}
+
+// portConflictActions returns the action stopping the listeners of port.
+func portConflictActions(port int, listeners []ports.Listener) []auracontext.Action {
+	if len(listeners) == 0 {
+		return nil
+	}
+	name := fmt.Sprintf("Kill process on :%d", port)
+	if process := listeners[0].Process; process != "" {
+		name += fmt.Sprintf(" (%s)", process)
+	}
+	return []auracontext.Action{{Name: name, Command: fmt.Sprintf("aura ports kill %d", port)}}
+}
+
+func init() {
+	portsKillCmd.Flags().BoolVarP(&portsForce, "force", "f", false, "Kill the process instead of asking it to terminate")
+	portsKillCmd.Flags().BoolVarP(&portsYes, "yes", "y", false, "Stop the process without asking")
+
+	portsCmd.AddCommand(portsKillCmd)
+	rootCmd.AddCommand(portsCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/ports"
+)
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    int
+		wantErr bool
+	}{
+		{"3000", 3000, false},
+		{":8080", 8080, false},
+		{"0", 0, true},
+		{"70000", 0, true},
+		{"http", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parsePort(tt.text)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parsePort(%q) = %d, %v, want %d", tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestPrintPorts(t *testing.T) {
+	var out bytes.Buffer
+	printPorts(&out, []ports.Listener{
+		{Port: 22, Address: "0.0.0.0"},
+		{Port: 3000, Address: "::1", PID: 4242, Process: "node"},
+	})
+	want := `PORT    ADDRESS  PID      PROCESS
+22      0.0.0.0  -        (other user)
+3000    ::1      4242     node
+`
+	if out.String() != want {
+		t.Errorf("printPorts() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPortConflictActions(t *testing.T) {
+	if actions := portConflictActions(3000, nil); actions != nil {
+		t.Errorf("portConflictActions() of a free port = %+v", actions)
+	}
+
+	actions := portConflictActions(3000, []ports.Listener{{Port: 3000, PID: 4242, Process: "node"}})
+	if len(actions) != 1 || actions[0].Name != "Kill process on :3000 (node)" || actions[0].Command != "aura ports kill 3000" {
+		t.Errorf("portConflictActions() = %+v", actions)
+	}
+}
+
+func TestPortConflicts(t *testing.T) {
+	originalDir := config.StateDir
+	defer func() { config.StateDir = originalDir }()
+	config.StateDir = t.TempDir()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := savePortConflicts(map[string]int{dir: 3000, "/elsewhere": 8080}); err != nil {
+		t.Fatalf("savePortConflicts() error = %v", err)
+	}
+	if got := loadPortConflicts()[dir]; got != 3000 {
+		t.Errorf("loadPortConflicts()[%s] = %d, want 3000", dir, got)
+	}
+
+	forgetPortConflict(3000)
+	conflicts := loadPortConflicts()
+	if _, ok := conflicts[dir]; ok || conflicts["/elsewhere"] != 8080 {
+		t.Errorf("loadPortConflicts() after forgetting 3000 = %v", conflicts)
+	}
+}
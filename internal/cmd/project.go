@@ -12,6 +12,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/timfewi/aura-cli-go/assets"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/theme"
 )
 
 var projectCmd = &cobra.Command{
@@ -22,7 +27,10 @@ var projectCmd = &cobra.Command{
 Examples:
   aura project my-api --type python
   aura project my-app --type node
-  aura project my-tool --type go`,
+  aura project my-tool --type go
+  aura project my-api --type go --with postgres,redis
+  aura project my-api --type go --dry-run   # Preview the files
+  aura project my-api --type go --var license=Apache-2.0 --var go_version=1.22`,
 	Args: cobra.ExactArgs(1),
 	RunE: runProject,
 }
@@ -31,6 +39,10 @@ var (
 	projectType string
 	description string
 	author      string
+	projectWith []string
+	projectYes  bool
+	noHooks     bool
+	projectVars []string
 )
 
 type ProjectData struct {
@@ -38,9 +50,14 @@ type ProjectData struct {
 	Type        string
 	Description string
 	Author      string
+	Email       string
 	ModuleName  string
-	GoVersion   string
 	RepoURL     string
+	// Addons are the add-ons layered onto the template, see ProjectAddon.
+	Addons       []string
+	Dependencies []ProjectDependency
+	// Vars are the values of the template's variables.
+	Vars map[string]string
 }
 
 func runProject(cmd *cobra.Command, args []string) error {
@@ -48,12 +65,12 @@ func runProject(cmd *cobra.Command, args []string) error {
 
 	// Validate project name
 	if !isValidProjectName(projectName) {
-		return fmt.Errorf("invalid project name. Use only letters, numbers, hyphens, and underscores")
+		return failure.New(failure.UserInput, "invalid project name. Use only letters, numbers, hyphens, and underscores")
 	}
 
 	// Check if directory already exists
 	if _, err := os.Stat(projectName); !os.IsNotExist(err) {
-		return fmt.Errorf("directory '%s' already exists", projectName)
+		return failure.New(failure.UserInput, "directory '%s' already exists", projectName)
 	}
 
 	// If type not specified, prompt for it
@@ -66,9 +83,28 @@ func runProject(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate project type
-	validTypes := []string{"python", "node", "go"}
+	validTypes := projectTypes()
 	if !contains(validTypes, projectType) {
-		return fmt.Errorf("unsupported project type '%s'. Supported types: %s", projectType, strings.Join(validTypes, ", "))
+		return failure.New(failure.UserInput, "unsupported project type '%s'. Supported types: %s", projectType, strings.Join(validTypes, ", "))
+	}
+
+	manifest, err := loadTemplateManifest(projectType)
+	if err != nil {
+		return err
+	}
+
+	addons, err := resolveAddons(projectWith)
+	if err != nil {
+		return err
+	}
+
+	givenVars, err := parseTemplateVars(projectVars)
+	if err != nil {
+		return err
+	}
+	vars, err := resolveTemplateVars(manifest, givenVars)
+	if err != nil {
+		return err
 	}
 
 	// Get additional information
@@ -76,20 +112,39 @@ func runProject(cmd *cobra.Command, args []string) error {
 		description = fmt.Sprintf("A new %s project", projectType)
 	}
 
+	if author == "" {
+		author = gitConfig("user.name")
+	}
 	if author == "" {
 		author = "Your Name"
 	}
+	owner := projectOwner(author)
+	host := config.GetProjectHost()
 
 	// Create project data
 	projectData := ProjectData{
-		ProjectName: projectName,
-		Type:        projectType,
-		Description: description,
-		Author:      author,
-		ModuleName:  fmt.Sprintf("github.com/%s/%s", author, projectName),
-		GoVersion:   "1.21",
-		RepoURL:     fmt.Sprintf("https://github.com/%s/%s.git", author, projectName),
+		ProjectName:  projectName,
+		Type:         projectType,
+		Description:  description,
+		Author:       author,
+		Email:        gitConfig("user.email"),
+		ModuleName:   fmt.Sprintf("%s/%s/%s", host, owner, projectName),
+		RepoURL:      fmt.Sprintf("https://%s/%s/%s.git", host, owner, projectName),
+		Addons:       addons,
+		Dependencies: addonDependencies(addons, projectType),
+		Vars:         vars,
+	}
+
+	if config.DryRun() {
+		return previewProject(os.Stdout, manifest, projectData)
 	}
+	if !confirmAction("project", fmt.Sprintf("Create %s project '%s'", projectType, projectName), false, projectYes) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	steps := progress.NewSteps(2)
+	steps.Next("Generating project files")
 
 	// Create project directory
 	if err := os.MkdirAll(projectName, 0755); err != nil {
@@ -97,34 +152,37 @@ func runProject(cmd *cobra.Command, args []string) error {
 	}
 
 	// Generate project files
-	if err := generateProjectFiles(projectName, projectData); err != nil {
+	if err := generateProjectFiles(projectName, manifest, projectData); err != nil {
 		// Clean up on error
 		os.RemoveAll(projectName)
 		return fmt.Errorf("failed to generate project files: %w", err)
 	}
+	if absPath, err := filepath.Abs(projectName); err == nil {
+		recordOperation(nil, operationProjectCreate, fmt.Sprintf("Create %s project '%s'", projectType, projectName), pathOperation{Path: absPath})
+	}
 
 	// Initialize git repository
+	steps.Next("Initializing Git repository")
 	if err := initializeGitRepository(projectName); err != nil {
 		fmt.Printf("Warning: Failed to initialize git repository: %v\n", err)
 	}
 
 	fmt.Printf("✓ Created %s project '%s'\n", projectType, projectName)
 	fmt.Printf("📁 Directory: %s\n", projectName)
+
+	hooks, err := manifest.renderHooks(projectData)
+	if err != nil {
+		return err
+	}
+	pending := runTemplateHooks(projectName, hooks)
+
 	fmt.Printf("🚀 Get started:\n")
 	fmt.Printf("   cd %s\n", projectName)
-
-	switch projectType {
-	case "python":
-		fmt.Printf("   python -m venv venv\n")
-		fmt.Printf("   source venv/bin/activate  # On Windows: venv\\Scripts\\activate\n")
-		fmt.Printf("   pip install -r requirements.txt\n")
-		fmt.Printf("   python main.py\n")
-	case "node":
-		fmt.Printf("   npm install\n")
-		fmt.Printf("   npm start\n")
-	case "go":
-		fmt.Printf("   go mod tidy\n")
-		fmt.Printf("   go run .\n")
+	for _, hook := range pending {
+		fmt.Printf("   %s\n", hook.Run)
+	}
+	if start, err := renderCommandTemplate(manifest.Start, projectData); err == nil && start != "" {
+		fmt.Printf("   %s\n", start)
 	}
 
 	return nil
@@ -132,116 +190,88 @@ func runProject(cmd *cobra.Command, args []string) error {
 
 func promptForProjectType() (string, error) {
 	prompt := promptui.Select{
-		Label: "Select project type",
-		Items: []string{"python", "node", "go"},
-		Templates: &promptui.SelectTemplates{
-			Label:    "{{ . }}?",
-			Active:   "▸ {{ . | cyan }}",
-			Inactive: "  {{ . | white }}",
-			Selected: "✓ {{ . | green }}",
-		},
+		Label:     "Select project type",
+		Items:     projectTypes(),
+		Templates: theme.SelectTemplates("."),
 	}
 
 	_, result, err := prompt.Run()
 	return result, err
 }
 
-func generateProjectFiles(projectDir string, data ProjectData) error {
-	// Common files for all projects
-	commonFiles := []string{
-		"README.md.tmpl",
-	}
-
-	// Type-specific files
-	var typeFiles []string
-	var gitignoreTemplate string
-
-	switch data.Type {
-	case "python":
-		typeFiles = []string{"main.py.tmpl"}
-		gitignoreTemplate = "python.gitignore.tmpl"
+// ProjectFile is a rendered file of a project.
+type ProjectFile struct {
+	// Path is relative to the project directory, separated by slashes.
+	Path    string
+	Content string
+}
 
-		// Create requirements.txt
-		reqFile := filepath.Join(projectDir, "requirements.txt")
-		if err := writeStringToFile(reqFile, "# Add your Python dependencies here\n"); err != nil {
-			return err
+// renderProjectFiles renders the files of a project without writing them.
+func renderProjectFiles(manifest *TemplateManifest, data ProjectData) ([]ProjectFile, error) {
+	var files []ProjectFile
+	for _, templateFile := range manifest.Files {
+		content, err := renderTemplateFile(templateFile, data)
+		if err != nil {
+			return nil, err
 		}
-
-	case "node":
-		typeFiles = []string{"package.json.tmpl", "index.js.tmpl"}
-		gitignoreTemplate = "node.gitignore.tmpl"
-
-	case "go":
-		typeFiles = []string{"go.mod.tmpl", "main.go.tmpl"}
-		gitignoreTemplate = "go.gitignore.tmpl"
+		files = append(files, ProjectFile{Path: strings.TrimSuffix(templateFile, ".tmpl"), Content: content})
 	}
 
-	// Generate common files
-	for _, templateFile := range commonFiles {
-		if err := generateFromTemplate(projectDir, templateFile, data); err != nil {
-			return err
+	// Generate .gitignore
+	if manifest.Gitignore != "" {
+		content, err := assets.Templates.ReadFile("templates/" + manifest.Gitignore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gitignore template: %w", err)
 		}
+		files = append(files, ProjectFile{Path: ".gitignore", Content: string(content)})
 	}
 
-	// Generate type-specific files
-	for _, templateFile := range typeFiles {
-		if err := generateFromTemplate(projectDir, templateFile, data); err != nil {
-			return err
-		}
+	// Layer add-ons onto the template
+	addonFiles, err := renderAddonFiles(data)
+	if err != nil {
+		return nil, err
 	}
+	return append(files, addonFiles...), nil
+}
 
-	// Generate .gitignore
-	if gitignoreTemplate != "" {
-		if err := generateGitignore(projectDir, gitignoreTemplate, data); err != nil {
-			return err
-		}
+func generateProjectFiles(projectDir string, manifest *TemplateManifest, data ProjectData) error {
+	files, err := renderProjectFiles(manifest, data)
+	if err != nil {
+		return err
 	}
 
+	for _, file := range files {
+		outputPath := filepath.Join(projectDir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+		}
+		if err := writeStringToFile(outputPath, file.Content); err != nil {
+			return fmt.Errorf("failed to create file %s: %w", outputPath, err)
+		}
+	}
 	return nil
 }
 
-func generateFromTemplate(projectDir, templateFile string, data ProjectData) error {
+func renderTemplateFile(templateFile string, data ProjectData) (string, error) {
 	// Read template from embedded assets
 	templateContent, err := assets.Templates.ReadFile("templates/" + templateFile)
 	if err != nil {
-		return fmt.Errorf("failed to read template %s: %w", templateFile, err)
+		return "", fmt.Errorf("failed to read template %s: %w", templateFile, err)
 	}
 
 	// Parse template
 	tmpl, err := template.New(templateFile).Parse(string(templateContent))
 	if err != nil {
-		return fmt.Errorf("failed to parse template %s: %w", templateFile, err)
+		return "", fmt.Errorf("failed to parse template %s: %w", templateFile, err)
 	}
 
-	// Determine output filename (remove .tmpl extension)
-	outputFile := strings.TrimSuffix(templateFile, ".tmpl")
-	outputPath := filepath.Join(projectDir, outputFile)
-
-	// Create output file
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", outputPath, err)
-	}
-	defer file.Close()
-
 	// Execute template
-	if err := tmpl.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", templateFile, err)
+	var output strings.Builder
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", templateFile, err)
 	}
 
-	return nil
-}
-
-func generateGitignore(projectDir, templateFile string, data ProjectData) error {
-	// Read gitignore template
-	templateContent, err := assets.Templates.ReadFile("templates/" + templateFile)
-	if err != nil {
-		return fmt.Errorf("failed to read gitignore template: %w", err)
-	}
-
-	// Write .gitignore file
-	gitignorePath := filepath.Join(projectDir, ".gitignore")
-	return writeStringToFile(gitignorePath, string(templateContent))
+	return output.String(), nil
 }
 
 func writeStringToFile(filePath, content string) error {
@@ -261,14 +291,45 @@ func initializeGitRepository(projectDir string) error {
 }
 
 func runShellCommand(command string) error {
-	var cmd *exec.Cmd
-	if isWindows() {
-		cmd = exec.Command("cmd", "/c", command)
-	} else {
-		cmd = exec.Command("sh", "-c", command)
+	if err := checkCommand(command, ""); err != nil {
+		return err
 	}
+	return platform.Current().ShellCommand(command).Run()
+}
 
-	return cmd.Run()
+// gitConfig returns a value of the Git configuration, or "" if it is not set.
+func gitConfig(key string) string {
+	output, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// projectOwner returns the user or organization owning new projects on the
+// VCS host: the configured owner, the GitHub user of the Git configuration,
+// or else the author's name made usable in paths.
+func projectOwner(author string) string {
+	if owner := strings.Trim(config.UserSettings.Project.Owner, "/ "); owner != "" {
+		return owner
+	}
+	if user := gitConfig("github.user"); user != "" {
+		return user
+	}
+
+	var owner strings.Builder
+	for _, char := range strings.ToLower(author) {
+		switch {
+		case char >= 'a' && char <= 'z', char >= '0' && char <= '9', char == '-', char == '_', char == '.':
+			owner.WriteRune(char)
+		case char == ' ' && owner.Len() > 0:
+			owner.WriteRune('-')
+		}
+	}
+	if owner.Len() == 0 {
+		return "your-name"
+	}
+	return strings.Trim(owner.String(), "-")
 }
 
 func isValidProjectName(name string) bool {
@@ -298,9 +359,13 @@ func contains(slice []string, item string) bool {
 }
 
 func init() {
-	projectCmd.Flags().StringVar(&projectType, "type", "", "Project type (python, node, go)")
+	projectCmd.Flags().StringVar(&projectType, "type", "", "Project type ("+strings.Join(projectTypes(), ", ")+")")
 	projectCmd.Flags().StringVar(&description, "description", "", "Project description")
-	projectCmd.Flags().StringVar(&author, "author", "", "Author name")
+	projectCmd.Flags().StringVar(&author, "author", "", "Author name (default: git config user.name)")
+	projectCmd.Flags().BoolVarP(&projectYes, "yes", "y", false, "Run the setup steps without asking")
+	projectCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "Don't run the setup steps, only print them")
+	projectCmd.Flags().StringArrayVar(&projectVars, "var", nil, "Template variable as key=value, asked for if not given")
+	projectCmd.Flags().StringSliceVar(&projectWith, "with", nil, "Add-ons to include ("+strings.Join(addonNames(), ", ")+")")
 
 	rootCmd.AddCommand(projectCmd)
 }
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/timfewi/aura-cli-go/assets"
+)
+
+// ProjectAddon is an optional service layered onto a project template. Its
+// templates live in templates/addons/<name>:
+//
+//	compose.yaml.tmpl  merged into docker-compose.yml
+//	env.tmpl           appended to .env.example
+//	<type>/*.tmpl      generated into projects of that type
+type ProjectAddon struct {
+	Name        string
+	Description string
+	// Requires lists add-ons that are added along with this one.
+	Requires []string
+	// Dependencies are the packages the add-on needs, by project type.
+	Dependencies map[string][]ProjectDependency
+}
+
+// ProjectDependency is a package a project depends on.
+type ProjectDependency struct {
+	Name    string
+	Version string
+}
+
+// projectAddons are the available add-ons, in the order their fragments are
+// composed.
+var projectAddons = []ProjectAddon{
+	{
+		Name:        "docker-compose",
+		Description: "Dockerfile and docker-compose.yml running the app",
+	},
+	{
+		Name:        "postgres",
+		Description: "PostgreSQL service and connection boilerplate",
+		Requires:    []string{"docker-compose"},
+		Dependencies: map[string][]ProjectDependency{
+			"go":     {{Name: "github.com/jackc/pgx/v5", Version: "v5.5.5"}},
+			"python": {{Name: "psycopg[binary]", Version: ">=3.1"}},
+			"node":   {{Name: "pg", Version: "^8.11.3"}},
+		},
+	},
+	{
+		Name:        "redis",
+		Description: "Redis service and connection boilerplate",
+		Requires:    []string{"docker-compose"},
+		Dependencies: map[string][]ProjectDependency{
+			"go":     {{Name: "github.com/redis/go-redis/v9", Version: "v9.5.1"}},
+			"python": {{Name: "redis", Version: ">=5.0"}},
+			"node":   {{Name: "redis", Version: "^4.6.13"}},
+		},
+	},
+}
+
+// HasAddon reports whether the project is generated with the named add-on.
+func (d ProjectData) HasAddon(name string) bool {
+	return contains(d.Addons, name)
+}
+
+// resolveAddons validates the requested add-ons and returns them with the
+// add-ons they require, in composition order.
+func resolveAddons(names []string) ([]string, error) {
+	selected := make(map[string]bool)
+
+	var add func(name string) error
+	add = func(name string) error {
+		addon, err := findAddon(name)
+		if err != nil {
+			return err
+		}
+		selected[name] = true
+		for _, required := range addon.Requires {
+			if err := add(required); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			if err := add(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var resolved []string
+	for _, addon := range projectAddons {
+		if selected[addon.Name] {
+			resolved = append(resolved, addon.Name)
+		}
+	}
+	return resolved, nil
+}
+
+// findAddon returns the add-on with the given name.
+func findAddon(name string) (ProjectAddon, error) {
+	var available []string
+	for _, addon := range projectAddons {
+		if addon.Name == name {
+			return addon, nil
+		}
+		available = append(available, addon.Name)
+	}
+	return ProjectAddon{}, fmt.Errorf("unknown add-on '%s'. Available add-ons: %s", name, strings.Join(available, ", "))
+}
+
+// addonDependencies returns the dependencies of the add-ons for a project type.
+func addonDependencies(addons []string, projectType string) []ProjectDependency {
+	var dependencies []ProjectDependency
+	for _, name := range addons {
+		addon, err := findAddon(name)
+		if err != nil {
+			continue
+		}
+		dependencies = append(dependencies, addon.Dependencies[projectType]...)
+	}
+	return dependencies
+}
+
+// renderAddonFiles renders the files the add-ons of data layer onto the
+// project.
+func renderAddonFiles(data ProjectData) ([]ProjectFile, error) {
+	var files []ProjectFile
+	var composeFragments, envFragments []string
+
+	for _, name := range data.Addons {
+		dir := path.Join("templates/addons", name)
+
+		fragment, err := renderAddonFragment(path.Join(dir, "compose.yaml.tmpl"), data)
+		if err != nil {
+			return nil, err
+		}
+		if fragment != "" {
+			composeFragments = append(composeFragments, fragment)
+		}
+
+		fragment, err = renderAddonFragment(path.Join(dir, "env.tmpl"), data)
+		if err != nil {
+			return nil, err
+		}
+		if fragment != "" {
+			envFragments = append(envFragments, strings.TrimSpace(fragment))
+		}
+
+		templates, err := fs.Glob(assets.Templates, path.Join(dir, data.Type, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range templates {
+			content, err := renderAddonFragment(name, data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, ProjectFile{Path: strings.TrimSuffix(path.Base(name), ".tmpl"), Content: content})
+		}
+	}
+
+	if len(composeFragments) > 0 {
+		compose, err := mergeComposeFragments(composeFragments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, ProjectFile{Path: "docker-compose.yml", Content: compose})
+	}
+
+	if len(envFragments) > 0 {
+		files = append(files, ProjectFile{Path: ".env.example", Content: strings.Join(envFragments, "\n\n") + "\n"})
+	}
+
+	return files, nil
+}
+
+// renderAddonFragment renders an add-on template. Missing templates render
+// as empty fragments.
+func renderAddonFragment(name string, data ProjectData) (string, error) {
+	content, err := assets.Templates.ReadFile(name)
+	if err != nil {
+		return "", nil
+	}
+
+	tmpl, err := template.New(path.Base(name)).Parse(string(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", name, err)
+	}
+	return output.String(), nil
+}
+
+// mergeComposeFragments merges Compose files: top-level sections such as
+// services and volumes are combined, later fragments override earlier ones.
+func mergeComposeFragments(fragments []string) (string, error) {
+	merged := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, fragment := range fragments {
+		var document yaml.Node
+		if err := yaml.Unmarshal([]byte(fragment), &document); err != nil {
+			return "", fmt.Errorf("invalid compose fragment: %w", err)
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		root := document.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return "", fmt.Errorf("invalid compose fragment: not a mapping")
+		}
+
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			key, value := root.Content[i], root.Content[i+1]
+			existing := mappingValue(merged, key.Value)
+			switch {
+			case existing == nil:
+				merged.Content = append(merged.Content, key, value)
+			case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					setMappingValue(existing, value.Content[j], value.Content[j+1])
+				}
+			default:
+				*existing = *value
+			}
+		}
+	}
+
+	var output bytes.Buffer
+	encoder := yaml.NewEncoder(&output)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(merged); err != nil {
+		return "", fmt.Errorf("failed to encode docker-compose.yml: %w", err)
+	}
+	return output.String(), nil
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key to value in a mapping node.
+func setMappingValue(mapping, key, value *yaml.Node) {
+	if existing := mappingValue(mapping, key.Value); existing != nil {
+		*existing = *value
+		return
+	}
+	mapping.Content = append(mapping.Content, key, value)
+}
+
+// addonNames returns the names of the available add-ons, sorted.
+func addonNames() []string {
+	var names []string
+	for _, addon := range projectAddons {
+		names = append(names, addon.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveAddons(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		want      []string
+		wantError bool
+	}{
+		{"none", nil, nil, false},
+		{"requirements added", []string{"redis"}, []string{"docker-compose", "redis"}, false},
+		{"composition order", []string{"redis", "postgres", "docker-compose"}, []string{"docker-compose", "postgres", "redis"}, false},
+		{"unknown add-on", []string{"mysql"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAddons(tt.requested)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("resolveAddons() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveAddons() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeComposeFragments(t *testing.T) {
+	fragments := []string{
+		"services:\n  app:\n    build: .\n",
+		"services:\n  postgres:\n    image: postgres:16\nvolumes:\n  postgres-data:\n",
+		"services:\n  redis:\n    image: redis:7\nvolumes:\n  redis-data:\n",
+	}
+
+	merged, err := mergeComposeFragments(fragments)
+	if err != nil {
+		t.Fatalf("mergeComposeFragments() error = %v", err)
+	}
+
+	for _, want := range []string{"  app:\n", "  postgres:\n", "  redis:\n", "  postgres-data:\n", "  redis-data:\n"} {
+		if !strings.Contains(merged, want) {
+			t.Errorf("Expected %q in merged file:\n%s", want, merged)
+		}
+	}
+	if strings.Count(merged, "services:") != 1 || strings.Count(merged, "volumes:") != 1 {
+		t.Errorf("Expected sections to be merged:\n%s", merged)
+	}
+}
+
+func TestAddonFragmentsRender(t *testing.T) {
+	data := ProjectData{ProjectName: "shop", Type: "go", Vars: map[string]string{"go_version": "1.21"}, Addons: []string{"docker-compose", "postgres", "redis"}}
+
+	for _, addon := range data.Addons {
+		fragment, err := renderAddonFragment("templates/addons/"+addon+"/compose.yaml.tmpl", data)
+		if err != nil {
+			t.Fatalf("renderAddonFragment(%s) error = %v", addon, err)
+		}
+		if _, err := mergeComposeFragments([]string{fragment}); err != nil {
+			t.Errorf("Compose fragment of %s is invalid: %v", addon, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/timfewi/aura-cli-go/assets"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+)
+
+// TemplateManifest describes a project template, read from
+// templates/manifests/<type>.yaml.
+type TemplateManifest struct {
+	// Files are the templates generated into the project.
+	Files     []string `yaml:"files"`
+	Gitignore string   `yaml:"gitignore"`
+	// Variables are asked for before generation and available to files and
+	// commands as {{.Vars.name}}.
+	Variables []TemplateVariable `yaml:"variables"`
+	// Start is the command running the project, shown after generation.
+	Start string `yaml:"start"`
+	// Hooks are the setup steps run in the project after generation.
+	Hooks []TemplateHook `yaml:"hooks"`
+}
+
+// TemplateHook is a setup step of a template. Run is a template with the
+// project data; steps rendering to an empty command are skipped.
+type TemplateHook struct {
+	Name string `yaml:"name"`
+	Run  string `yaml:"run"`
+}
+
+// OS returns the operating system the project is generated on, for use in
+// hook commands.
+func (d ProjectData) OS() string {
+	return platform.Current().OS
+}
+
+// projectTypes returns the project types that have a template manifest.
+func projectTypes() []string {
+	files, _ := fs.Glob(assets.Templates, "templates/manifests/*.yaml")
+
+	var types []string
+	for _, file := range files {
+		types = append(types, strings.TrimSuffix(path.Base(file), ".yaml"))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// loadTemplateManifest reads the manifest of a project type.
+func loadTemplateManifest(projectType string) (*TemplateManifest, error) {
+	content, err := assets.Templates.ReadFile("templates/manifests/" + projectType + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest of %s template: %w", projectType, err)
+	}
+
+	manifest := &TemplateManifest{}
+	if err := yaml.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest of %s template: %w", projectType, err)
+	}
+	return manifest, nil
+}
+
+// renderHooks returns the hooks of the manifest with their commands rendered
+// for data, without the ones that don't apply.
+func (m *TemplateManifest) renderHooks(data ProjectData) ([]TemplateHook, error) {
+	var hooks []TemplateHook
+	for _, hook := range m.Hooks {
+		command, err := renderCommandTemplate(hook.Run, data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hook '%s': %w", hook.Name, err)
+		}
+		if command != "" {
+			hooks = append(hooks, TemplateHook{Name: hook.Name, Run: command})
+		}
+	}
+	return hooks, nil
+}
+
+// renderCommandTemplate renders a command template of a manifest.
+func renderCommandTemplate(command string, data ProjectData) (string, error) {
+	tmpl, err := template.New("command").Parse(command)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output.String()), nil
+}
+
+// runTemplateHooks runs the hooks in the project directory after asking for
+// confirmation, streaming their output. It returns the hooks that did not run,
+// which are left to the user.
+func runTemplateHooks(projectDir string, hooks []TemplateHook) []TemplateHook {
+	if len(hooks) == 0 || noHooks {
+		return hooks
+	}
+
+	// The setup steps run commands of the template, which are confirmed
+	// unless the confirm policy of project is never
+	confirm := !projectYes && needsConfirmation("project", true)
+	if confirm && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return hooks
+	}
+
+	fmt.Println("🪝 Setup steps:")
+	for _, hook := range hooks {
+		fmt.Printf("   %-28s %s\n", hook.Name, hook.Run)
+	}
+
+	if confirm {
+		prompt := promptui.Prompt{Label: "Run these steps", IsConfirm: true}
+		if _, err := prompt.Run(); err != nil {
+			return hooks
+		}
+	}
+
+	steps := progress.NewSteps(len(hooks))
+	for i, hook := range hooks {
+		steps.Next(hook.Name)
+		if err := checkCommand(hook.Run, projectDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			return hooks[i:]
+		}
+
+		cmd := platform.Current().ShellCommand(hook.Run)
+		cmd.Dir = projectDir
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: setup step '%s' failed: %v\n", hook.Name, err)
+			return hooks[i:]
+		}
+	}
+	fmt.Println("✓ Setup complete")
+	return nil
+}
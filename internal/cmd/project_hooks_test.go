@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+func TestTemplateManifests(t *testing.T) {
+	types := projectTypes()
+	if len(types) != 3 {
+		t.Fatalf("projectTypes() = %v, want go, node and python", types)
+	}
+
+	for _, projectType := range types {
+		manifest, err := loadTemplateManifest(projectType)
+		if err != nil {
+			t.Fatalf("loadTemplateManifest(%s) error = %v", projectType, err)
+		}
+		if len(manifest.Files) == 0 || manifest.Start == "" {
+			t.Errorf("Manifest of %s has no files or start command", projectType)
+		}
+		for _, variable := range manifest.Variables {
+			if err := variable.Validate(variable.Default); err != nil {
+				t.Errorf("Default of %s in %s template is invalid: %v", variable.Name, projectType, err)
+			}
+		}
+	}
+}
+
+func TestRenderHooks(t *testing.T) {
+	manifest, err := loadTemplateManifest("go")
+	if err != nil {
+		t.Fatalf("loadTemplateManifest() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		addons []string
+		want   []string
+	}{
+		{"without add-ons", nil, []string{"go mod tidy", `git add -A && git commit -q -m "initial scaffold"`}},
+		{"with compose", []string{"docker-compose"}, []string{"go mod tidy", `git add -A && git commit -q -m "initial scaffold"`, "docker compose up -d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks, err := manifest.renderHooks(ProjectData{Type: "go", Addons: tt.addons})
+			if err != nil {
+				t.Fatalf("renderHooks() error = %v", err)
+			}
+			if len(hooks) != len(tt.want) {
+				t.Fatalf("renderHooks() = %+v, want %v", hooks, tt.want)
+			}
+			for i, hook := range hooks {
+				if hook.Run != tt.want[i] {
+					t.Errorf("Hook %d = %q, want %q", i, hook.Run, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProjectOwner(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	originalSettings := config.UserSettings
+	defer func() { config.UserSettings = originalSettings }()
+
+	tests := []struct {
+		author string
+		owner  string
+		want   string
+	}{
+		{"Jane Doe", "", "jane-doe"},
+		{"Jürgen Müller", "", "jrgen-mller"},
+		{"Your Name", "", "your-name"},
+		{"???", "", "your-name"},
+		{"Jane Doe", "acme", "acme"},
+	}
+
+	for _, tt := range tests {
+		config.UserSettings.Project.Owner = tt.owner
+		if got := projectOwner(tt.author); got != tt.want {
+			t.Errorf("projectOwner(%q) with owner %q = %q, want %q", tt.author, tt.owner, got, tt.want)
+		}
+	}
+}
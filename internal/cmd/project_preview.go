@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// previewLines limits how many lines of each file a dry run shows.
+const previewLines = 40
+
+// previewProject prints the file tree, the rendered files as a diff against
+// an empty directory and the setup steps of a project, without writing
+// anything.
+func previewProject(w io.Writer, manifest *TemplateManifest, data ProjectData) error {
+	files, err := renderProjectFiles(manifest, data)
+	if err != nil {
+		return fmt.Errorf("failed to render project files: %w", err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	fmt.Fprintf(w, "🔍 Dry run, nothing is written\n\n")
+
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	fmt.Fprintf(w, "📁 %s/\n", data.ProjectName)
+	printFileTree(w, paths)
+
+	for _, file := range files {
+		lines := strings.Split(strings.TrimSuffix(file.Content, "\n"), "\n")
+		fmt.Fprintf(w, "\n--- /dev/null\n+++ %s/%s\n@@ -0,0 +1,%d @@\n", data.ProjectName, file.Path, len(lines))
+		for i, line := range lines {
+			if i == previewLines {
+				fmt.Fprintf(w, "… %d more lines\n", len(lines)-previewLines)
+				break
+			}
+			fmt.Fprintf(w, "+%s\n", line)
+		}
+	}
+
+	hooks, err := manifest.renderHooks(data)
+	if err != nil {
+		return err
+	}
+	if len(hooks) > 0 {
+		fmt.Fprintf(w, "\n🪝 Setup steps:\n")
+		for _, hook := range hooks {
+			fmt.Fprintf(w, "   %-28s %s\n", hook.Name, hook.Run)
+		}
+	}
+	return nil
+}
+
+// printFileTree prints slash-separated paths as a tree.
+func printFileTree(w io.Writer, paths []string) {
+	type node struct {
+		name     string
+		children []*node
+	}
+
+	root := &node{}
+	for _, p := range paths {
+		current := root
+		for _, part := range strings.Split(p, "/") {
+			var child *node
+			for _, existing := range current.children {
+				if existing.name == part {
+					child = existing
+					break
+				}
+			}
+			if child == nil {
+				child = &node{name: part}
+				current.children = append(current.children, child)
+			}
+			current = child
+		}
+	}
+
+	var print func(n *node, prefix string)
+	print = func(n *node, prefix string) {
+		for i, child := range n.children {
+			branch, indent := "├── ", "│   "
+			if i == len(n.children)-1 {
+				branch, indent = "└── ", "    "
+			}
+			name := child.name
+			if len(child.children) > 0 {
+				name += "/"
+			}
+			fmt.Fprintf(w, "%s%s%s\n", prefix, branch, name)
+			print(child, prefix+indent)
+		}
+	}
+	print(root, "")
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintFileTree(t *testing.T) {
+	var out bytes.Buffer
+	printFileTree(&out, []string{".gitignore", "cmd/app/main.go", "cmd/tool.go", "go.mod"})
+
+	want := `├── .gitignore
+├── cmd/
+│   ├── app/
+│   │   └── main.go
+│   └── tool.go
+└── go.mod
+`
+	if out.String() != want {
+		t.Errorf("printFileTree() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPreviewProject(t *testing.T) {
+	manifest, err := loadTemplateManifest("go")
+	if err != nil {
+		t.Fatalf("loadTemplateManifest() error = %v", err)
+	}
+	data := ProjectData{ProjectName: "shop", Type: "go", ModuleName: "github.com/me/shop", Vars: map[string]string{"go_version": "1.21"}, Addons: []string{"docker-compose", "postgres"}}
+
+	var out bytes.Buffer
+	if err := previewProject(&out, manifest, data); err != nil {
+		t.Fatalf("previewProject() error = %v", err)
+	}
+
+	for _, want := range []string{"📁 shop/\n", "├── db.go\n", "+++ shop/go.mod\n", "+module github.com/me/shop\n", "docker compose up -d"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in preview:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+// TemplateVariable is a value a template asks for when generating a project.
+type TemplateVariable struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Default     string `yaml:"default"`
+	// Pattern is a regular expression values must match.
+	Pattern string `yaml:"pattern"`
+	// Options are the allowed values, offered as a selection.
+	Options []string `yaml:"options"`
+}
+
+// Validate returns an error if value is not allowed for the variable.
+func (v TemplateVariable) Validate(value string) error {
+	if len(v.Options) > 0 && !contains(v.Options, value) {
+		return fmt.Errorf("'%s' is not a valid %s, use one of: %s", value, v.Name, strings.Join(v.Options, ", "))
+	}
+	if v.Pattern != "" {
+		pattern, err := regexp.Compile(v.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern of variable %s: %w", v.Name, err)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("'%s' is not a valid %s, it must match %s", value, v.Name, v.Pattern)
+		}
+	}
+	if value == "" {
+		return fmt.Errorf("%s is required", v.Name)
+	}
+	return nil
+}
+
+// parseTemplateVars parses key=value pairs given with --var.
+func parseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid variable '%s', use key=value", pair)
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, nil
+}
+
+// resolveTemplateVars returns the values of the variables of a manifest:
+// given values are validated, missing ones are asked for in a terminal and
+// default otherwise. Defaults of the team settings replace those of the
+// manifest.
+func resolveTemplateVars(manifest *TemplateManifest, given map[string]string) (map[string]string, error) {
+	vars := make(map[string]string)
+	interactive := term.IsTerminal(int(os.Stdin.Fd()))
+
+	declared := make(map[string]bool)
+	for _, variable := range manifest.Variables {
+		declared[variable.Name] = true
+		if value, ok := config.Team.Variables[variable.Name]; ok {
+			variable.Default = value
+		}
+
+		value, ok := given[variable.Name]
+		switch {
+		case ok:
+		case interactive:
+			var err error
+			if value, err = promptForTemplateVar(variable); err != nil {
+				return nil, err
+			}
+		default:
+			value = variable.Default
+		}
+
+		if err := variable.Validate(value); err != nil {
+			return nil, err
+		}
+		vars[variable.Name] = value
+	}
+
+	for name := range given {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown variable '%s' for this template", name)
+		}
+	}
+	return vars, nil
+}
+
+// promptForTemplateVar asks for the value of a variable.
+func promptForTemplateVar(variable TemplateVariable) (string, error) {
+	label := variable.Name
+	if variable.Description != "" {
+		label = variable.Description
+	}
+
+	if len(variable.Options) > 0 {
+		cursor := 0
+		for i, option := range variable.Options {
+			if option == variable.Default {
+				cursor = i
+			}
+		}
+		prompt := promptui.Select{
+			Label:     label,
+			Items:     variable.Options,
+			CursorPos: cursor,
+			Templates: theme.SelectTemplates("."),
+		}
+		_, value, err := prompt.Run()
+		return value, err
+	}
+
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   variable.Default,
+		AllowEdit: true,
+		Validate:  variable.Validate,
+	}
+	return prompt.Run()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+func TestTemplateVariableValidate(t *testing.T) {
+	version := TemplateVariable{Name: "go_version", Pattern: `^1\.[0-9]+$`}
+	license := TemplateVariable{Name: "license", Options: []string{"MIT", "Apache-2.0"}}
+
+	tests := []struct {
+		name      string
+		variable  TemplateVariable
+		value     string
+		wantError bool
+	}{
+		{"matching pattern", version, "1.22", false},
+		{"not matching pattern", version, "2", true},
+		{"option", license, "MIT", false},
+		{"not an option", license, "WTFPL", true},
+		{"empty", TemplateVariable{Name: "owner"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.variable.Validate(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate(%q) error = %v, wantError %v", tt.value, err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestResolveTemplateVars(t *testing.T) {
+	manifest := &TemplateManifest{Variables: []TemplateVariable{
+		{Name: "license", Default: "MIT", Options: []string{"MIT", "Apache-2.0"}},
+		{Name: "go_version", Default: "1.21", Pattern: `^1\.[0-9]+$`},
+	}}
+
+	given, err := parseTemplateVars([]string{"license=Apache-2.0"})
+	if err != nil {
+		t.Fatalf("parseTemplateVars() error = %v", err)
+	}
+
+	// Tests don't run in a terminal, so missing values default
+	vars, err := resolveTemplateVars(manifest, given)
+	if err != nil {
+		t.Fatalf("resolveTemplateVars() error = %v", err)
+	}
+	if vars["license"] != "Apache-2.0" || vars["go_version"] != "1.21" {
+		t.Errorf("resolveTemplateVars() = %v", vars)
+	}
+
+	if _, err := resolveTemplateVars(manifest, map[string]string{"go_version": "two"}); err == nil {
+		t.Error("Expected error for an invalid value")
+	}
+	if _, err := resolveTemplateVars(manifest, map[string]string{"owner": "me"}); err == nil {
+		t.Error("Expected error for an unknown variable")
+	}
+	if _, err := parseTemplateVars([]string{"license"}); err == nil {
+		t.Error("Expected error for a variable without value")
+	}
+
+	// The team settings replace the defaults, given values win
+	original := config.Team
+	defer func() { config.Team = original }()
+	config.Team.Variables = map[string]string{"license": "Apache-2.0", "go_version": "1.23"}
+	vars, err = resolveTemplateVars(manifest, map[string]string{"go_version": "1.22"})
+	if err != nil || vars["license"] != "Apache-2.0" || vars["go_version"] != "1.22" {
+		t.Errorf("resolveTemplateVars() = %v, %v, want the team license", vars, err)
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/query"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query <question>",
+	Short: "Ask a question about piped JSON or YAML",
+	Long: `Answer a question about the JSON or YAML piped into Aura with a jq or yq
+expression written by the AI assistant.
+
+Only the structure of the data, its paths and their types, is sent to the AI
+assistant, never the values. The expression is shown and, once confirmed,
+run locally with jq for JSON, including JSON Lines, or yq for YAML. The
+expression is printed to the standard error and the result to the standard
+output, so the result can be piped on.
+
+Examples:
+  curl -s https://api.github.com/repos/cli/cli/releases | aura query "names of the last 3 releases"
+  kubectl get pods -o json | aura query "pods that are not running" --yes
+  cat docker-compose.yml | aura query "which ports does each service publish"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuery,
+}
+
+var queryYes bool
+
+// queryAnswer is the JSON answer of the AI assistant.
+type queryAnswer struct {
+	Expression  string `json:"expression"`
+	Raw         bool   `json:"raw"`
+	Explanation string `json:"explanation"`
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return failure.New(failure.UserInput, "pipe the JSON or YAML to query into aura, such as: kubectl get pods -o json | aura query \"%s\"", args[0])
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read the input: %w", err)
+	}
+	format, values, err := query.Detect(data)
+	if err != nil {
+		return err
+	}
+	tool := format.Tool()
+	if !platform.Current().Installed(tool) {
+		return failure.New(failure.NotFound, "%s is not installed, install it to query %s", tool, format)
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	answer, err := writeQuery(client, tool, query.Outline(values), args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s\n", theme.Paint(theme.Heading, tool+":"), theme.Paint(theme.Command, answer.Expression))
+	if answer.Explanation != "" {
+		fmt.Fprintf(os.Stderr, "ℹ️  %s\n", strings.TrimSpace(answer.Explanation))
+	}
+	if needsConfirmation("query", true) && !confirmOnTerminal("Run it", queryYes) {
+		fmt.Fprintln(os.Stderr, "Cancelled.")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := query.Run(ctx, format, answer.Expression, answer.Raw, data)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Print(result)
+	return nil
+}
+
+// writeQuery asks the AI assistant for the expression of tool answering
+// question about data with the structure of outline.
+func writeQuery(client ai.Assistant, tool, outline, question string) (queryAnswer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	response, err := client.QueryExpression(ctx, tool, outline, question)
+	stopThinking()
+
+	if err != nil {
+		return queryAnswer{}, fmt.Errorf("failed to write the expression: %w", err)
+	}
+	return parseQueryAnswer(response)
+}
+
+// parseQueryAnswer parses the JSON answer of the AI assistant.
+func parseQueryAnswer(response string) (queryAnswer, error) {
+	var answer queryAnswer
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start || json.Unmarshal([]byte(response[start:end+1]), &answer) != nil {
+		return queryAnswer{}, failure.New(failure.API, "the AI assistant returned no valid expression")
+	}
+	answer.Expression = strings.TrimSpace(answer.Expression)
+	if answer.Expression == "" {
+		return queryAnswer{}, failure.New(failure.API, "the AI assistant returned no valid expression")
+	}
+	return answer, nil
+}
+
+func init() {
+	queryCmd.Flags().BoolVarP(&queryYes, "yes", "y", false, "Run the expression without asking")
+	rootCmd.AddCommand(queryCmd)
+}
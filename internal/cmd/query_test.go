@@ -0,0 +1,52 @@
+package cmd
+
+import "testing"
+
+func TestParseQueryAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantRaw  bool
+		wantErr  bool
+	}{
+		{
+			name:     "fenced json",
+			response: "```json\n{\"expression\": \".items[] | {name: .metadata.name}\", \"raw\": false, \"explanation\": \"Names of the items\"}\n```",
+			want:     ".items[] | {name: .metadata.name}",
+		},
+		{
+			name:     "raw strings",
+			response: `{"expression": " .users[].name ", "raw": true}`,
+			want:     ".users[].name",
+			wantRaw:  true,
+		},
+		{name: "no expression", response: `{"expression": "", "raw": true}`, wantErr: true},
+		{name: "prose", response: "Use .users[].name", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQueryAnswer(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQueryAnswer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Expression != tt.want || got.Raw != tt.wantRaw {
+				t.Errorf("parseQueryAnswer() = %q raw %v, want %q raw %v", got.Expression, got.Raw, tt.want, tt.wantRaw)
+			}
+		})
+	}
+}
+
+func TestWriteQuery(t *testing.T) {
+	client := &MockAIClient{response: `{"expression": ".total", "raw": false, "explanation": "The total"}`}
+	answer, err := writeQuery(client, "jq", ".total: number\n", "how many users are there")
+	if err != nil {
+		t.Fatalf("writeQuery() error = %v", err)
+	}
+	if answer.Expression != ".total" {
+		t.Errorf("writeQuery() expression = %q", answer.Expression)
+	}
+	if len(client.questions) != 1 || client.questions[0] != "how many users are there" {
+		t.Errorf("writeQuery() asked %q", client.questions)
+	}
+}
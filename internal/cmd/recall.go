@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/semantic"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var recallCmd = &cobra.Command{
+	Use:   "recall <query...>",
+	Short: "Find answers of past 'aura ask' conversations",
+	Long: `Search the questions asked with 'aura ask' and their answers by meaning, to
+find a previous answer instead of asking, and paying, again.
+
+Conversations are compared using embeddings, see 'aura index embed --help' to
+configure the embedding model. Without an AI provider, conversations containing
+all words of the query are shown.
+
+Examples:
+  aura recall "what did it tell me about systemd timers"
+  aura recall docker volumes --since 30d
+  aura recall "git rebase" --limit 1`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: paged(runRecall),
+}
+
+var (
+	recallLimit int
+	recallSince string
+)
+
+// recallTextLength limits how much of a conversation is embedded.
+const recallTextLength = 2000
+
+func runRecall(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+
+	var since time.Time
+	if recallSince != "" {
+		age, err := parseAge(recallSince)
+		if err != nil {
+			return err
+		}
+		since = time.Now().Add(-age)
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	conversations, err := database.ListConversations(since)
+	if err != nil {
+		return err
+	}
+	if len(conversations) == 0 {
+		fmt.Println("No saved conversations yet. Answers of 'aura ask' are saved automatically.")
+		return nil
+	}
+
+	byKey := make(map[string]db.Conversation, len(conversations))
+	docs := make([]semantic.Document, len(conversations))
+	for i, conversation := range conversations {
+		key := fmt.Sprintf("conversation:%d", conversation.ID)
+		byKey[key] = conversation
+
+		text := "Question: " + conversation.Question + "\nAnswer: " + conversation.Answer
+		if len(text) > recallTextLength {
+			text = text[:recallTextLength]
+		}
+		docs[i] = semantic.Document{Path: key, Text: text}
+	}
+
+	var matches []db.Conversation
+	index, err := semanticIndex(database)
+	if err != nil {
+		matches = keywordMatches(conversations, strings.Fields(query), recallLimit)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		if _, err := index.Update(ctx, docs); err != nil {
+			return err
+		}
+		results, err := index.Search(ctx, docs, query, recallLimit)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			matches = append(matches, byKey[result.Path])
+		}
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("No conversations match '%s'\n", query)
+		return nil
+	}
+
+	for i, conversation := range matches {
+		if i > 0 {
+			fmt.Println("─────────────────────────────────────")
+		}
+		header := conversation.CreatedAt.Local().Format("2006-01-02 15:04")
+		if conversation.Model != "" {
+			header += " · " + conversation.Model
+		}
+		fmt.Printf("📅 %s", theme.Paint(theme.Muted, header))
+		fmt.Printf("\n❓ %s\n\n%s\n", theme.Paint(theme.Heading, firstLine(conversation.Question)), strings.TrimSpace(conversation.Answer))
+	}
+	return nil
+}
+
+// keywordMatches returns the newest conversations containing all terms,
+// ignoring case.
+func keywordMatches(conversations []db.Conversation, terms []string, limit int) []db.Conversation {
+	var matches []db.Conversation
+	for _, conversation := range conversations {
+		text := strings.ToLower(conversation.Question + " " + conversation.Answer)
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, strings.ToLower(term)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, conversation)
+			if len(matches) == limit {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// parseAge parses durations such as "30d", "2w" or "12h".
+func parseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age '%s'", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age '%s', use e.g. 30d, 2w or 12h", value)
+	}
+	return age, nil
+}
+
+// firstLine returns the first line of s, marking cut off text.
+func firstLine(s string) string {
+	line, rest, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	if rest != "" {
+		return line + " …"
+	}
+	return line
+}
+
+func init() {
+	recallCmd.Flags().IntVarP(&recallLimit, "limit", "n", 3, "Maximum number of conversations to show")
+	recallCmd.Flags().StringVar(&recallSince, "since", "", "Only search conversations newer than this, e.g. 30d, 2w or 12h")
+	rootCmd.AddCommand(recallCmd)
+}
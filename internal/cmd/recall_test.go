@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"xd", 0, true},
+		{"-1d", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAge(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAge(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestKeywordMatches(t *testing.T) {
+	conversations := []db.Conversation{
+		{ID: 3, Question: "systemd timers?", Answer: "Use OnCalendar= in a .timer unit"},
+		{ID: 2, Question: "docker volumes", Answer: "docker volume ls"},
+		{ID: 1, Question: "Systemd services", Answer: "systemctl enable --now app.timer"},
+	}
+
+	matches := keywordMatches(conversations, []string{"SYSTEMD", "timer"}, 5)
+	if len(matches) != 2 || matches[0].ID != 3 || matches[1].ID != 1 {
+		t.Errorf("Unexpected matches %+v", matches)
+	}
+
+	if matches := keywordMatches(conversations, []string{"systemd"}, 1); len(matches) != 1 {
+		t.Errorf("Expected limit to apply, got %d matches", len(matches))
+	}
+}
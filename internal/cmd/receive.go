@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/transfer"
+)
+
+var receiveCmd = &cobra.Command{
+	Use:   "receive <code> [destination]",
+	Short: "Receive a file sent with aura send",
+	Long: `Receive the file another machine on the network offers with 'aura send'
+under the code it printed. The file is saved in the destination directory,
+the current one by default, or under the destination path if it isn't a
+directory. The destination may start with a bookmark, as in api/docs.
+
+Examples:
+  aura receive k7m2-p9xq-h3td
+  aura receive k7m2-p9xq-h3td downloads          # Into a bookmarked directory
+  aura receive k7m2-p9xq-h3td api/docs/spec.pdf   # Under a new name
+  aura receive k7m2-p9xq-h3td --from 192.168.1.20:7777`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runReceive,
+}
+
+var (
+	receiveFrom    string
+	receiveTimeout time.Duration
+	receiveForce   bool
+)
+
+func runReceive(cmd *cobra.Command, args []string) error {
+	if err := config.RequireNetwork("aura receive"); err != nil {
+		return err
+	}
+	code, err := transfer.NormalizeCode(args[0])
+	if err != nil {
+		return failure.Wrap(failure.UserInput, err)
+	}
+	destination := "."
+	if len(args) > 1 {
+		destination = args[1]
+	}
+	destination, err = receiveDestination(destination)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	address := receiveFrom
+	if address == "" {
+		discoverCtx, cancel := context.WithTimeout(ctx, receiveTimeout)
+		address, err = transfer.Discover(discoverCtx, code)
+		cancel()
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return failure.New(failure.Network, "no file offered under this code found within %s, check the code or use --from with the address 'aura send' printed", receiveTimeout)
+		case errors.Is(err, context.Canceled):
+			return nil
+		case err != nil:
+			return failure.Wrap(failure.Network, err)
+		}
+	}
+
+	var dialer net.Dialer
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	conn, err := dialer.DialContext(dialCtx, "tcp", address)
+	cancel()
+	if err != nil {
+		return failure.Wrap(failure.Network, fmt.Errorf("failed to connect to %s: %w", address, err))
+	}
+	defer conn.Close()
+	stopClose := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stopClose()
+
+	var (
+		target string
+		temp   *os.File
+		meter  *transferMeter
+	)
+	accept := func(file transfer.File) (io.Writer, error) {
+		target = receiveTarget(destination, file.Name)
+		if _, err := os.Stat(target); err == nil && !receiveForce {
+			return nil, failure.New(failure.UserInput, "%s already exists, use --force to replace it", target)
+		}
+		created, err := os.CreateTemp(filepath.Dir(target), ".aura-receive-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file: %w", err)
+		}
+		temp = created
+		fmt.Printf("Receiving %s (%s) from %s\n", file.Name, formatSize(file.Size), address)
+		meter = newTransferMeter(file.Size)
+		return temp, nil
+	}
+	update := func(current int64) { meter.update(current) }
+
+	file, err := transfer.Receive(conn, code, accept, update)
+	if meter != nil {
+		meter.done()
+	}
+	if temp != nil {
+		temp.Close()
+		if err != nil {
+			os.Remove(temp.Name())
+		}
+	}
+	switch {
+	case ctx.Err() != nil:
+		fmt.Println("Stopped receiving the file.")
+		return nil
+	case errors.Is(err, transfer.ErrWrongCode):
+		return failure.Wrap(failure.UserInput, err)
+	case err != nil:
+		var kind *failure.Error
+		if errors.As(err, &kind) {
+			return err
+		}
+		return failure.Wrap(failure.Network, err)
+	}
+
+	if file.Mode != 0 {
+		os.Chmod(temp.Name(), file.Mode.Perm())
+	}
+	if err := os.Rename(temp.Name(), target); err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to save %s: %w", target, err)
+	}
+	fmt.Printf("✓ Received %s\n", target)
+	return nil
+}
+
+// receiveDestination resolves destination to an absolute path. A first
+// element naming a bookmark stands for its directory.
+func receiveDestination(destination string) (string, error) {
+	if !filepath.IsAbs(destination) && !strings.HasPrefix(destination, ".") && !strings.HasPrefix(destination, "~") {
+		alias, rest, _ := strings.Cut(filepath.ToSlash(destination), "/")
+		if database, err := db.New(); err == nil {
+			bookmark, err := database.GetBookmark(alias)
+			if err == nil && bookmark != nil {
+				recordBookmarkUse(database, alias)
+				destination = filepath.Join(bookmark.Path, filepath.FromSlash(rest))
+			}
+			database.Close()
+		}
+	}
+	path, err := filepath.Abs(config.ExpandHome(destination))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path, nil
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		return "", failure.New(failure.NotFound, "directory %s not found", filepath.Dir(path))
+	}
+	return path, nil
+}
+
+// receiveTarget returns the path a file called name is saved at when
+// received into destination: in it if it is a directory, or else destination
+// itself.
+func receiveTarget(destination, name string) string {
+	if info, err := os.Stat(destination); err == nil && info.IsDir() {
+		return filepath.Join(destination, name)
+	}
+	return destination
+}
+
+func init() {
+	receiveCmd.Flags().StringVar(&receiveFrom, "from", "", "Address 'aura send' printed, for networks without multicast DNS")
+	receiveCmd.Flags().DurationVar(&receiveTimeout, "timeout", 30*time.Second, "How long to look for the sending machine")
+	receiveCmd.Flags().BoolVarP(&receiveForce, "force", "f", false, "Replace an existing file")
+	rootCmd.AddCommand(receiveCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReceiveTarget(t *testing.T) {
+	dir := t.TempDir()
+	if got, want := receiveTarget(dir, "report.pdf"), filepath.Join(dir, "report.pdf"); got != want {
+		t.Errorf("receiveTarget() of a directory = %q, want %q", got, want)
+	}
+	renamed := filepath.Join(dir, "spec.pdf")
+	if got := receiveTarget(renamed, "report.pdf"); got != renamed {
+		t.Errorf("receiveTarget() of a new file = %q, want %q", got, renamed)
+	}
+}
+
+func TestReceiveDestination(t *testing.T) {
+	dir := t.TempDir()
+	if got, err := receiveDestination(dir); err != nil || got != dir {
+		t.Errorf("receiveDestination() of a directory = %q, %v", got, err)
+	}
+	if _, err := receiveDestination(filepath.Join(dir, "missing", "report.pdf")); err == nil {
+		t.Error("receiveDestination() in a missing directory succeeded")
+	}
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "docs", "spec.pdf")
+	if got, err := receiveDestination(want); err != nil || got != want {
+		t.Errorf("receiveDestination() of a new file = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestTransferLine(t *testing.T) {
+	if got, want := transferLine(512<<10, 1<<20, false), "[###############---------------]  50%  512.0 KiB of 1.0 MiB"; got != want {
+		t.Errorf("transferLine() = %q, want %q", got, want)
+	}
+	if got, want := transferLine(0, 0, false), "[##############################] 100%  0 B of 0 B"; got != want {
+		t.Errorf("transferLine() of an empty file = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var refactorCmd = &cobra.Command{
+	Use:   "refactor <file> <instruction>",
+	Short: "Refactor a file with the AI assistant",
+	Long: `Refactor a source file as instructed with the AI assistant.
+
+The AI assistant answers with a unified diff of the file, which Aura checks
+with 'git apply --check', shows in color and applies only once confirmed.
+Nothing but the lines of the diff changes.
+
+When the diff doesn't apply cleanly, you choose to have the AI assistant
+correct it, to apply the hunks that fit and keep the others as a .rej file
+next to the source, or to save the diff to apply it by hand. Without a
+terminal it is saved.
+
+Examples:
+  aura refactor internal/parser/parser.go "extract the token loop into its own function"
+  aura refactor src/api.ts "replace the promise chains with async/await" --dry-run
+  aura refactor app/views.py "rename get_user to fetch_user" --yes`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRefactor,
+}
+
+var (
+	refactorYes bool
+)
+
+// refactorAttempts is how many diffs the AI assistant may propose for one
+// refactoring before only applying or saving are offered.
+const refactorAttempts = 3
+
+// Choices offered when a diff doesn't apply cleanly.
+const (
+	rejectRetry   = "Ask the AI assistant to correct the diff"
+	rejectPartial = "Apply the hunks that fit, keep the others in a .rej file"
+	rejectSave    = "Save the diff to apply it by hand"
+	rejectCancel  = "Cancel"
+)
+
+func runRefactor(cmd *cobra.Command, args []string) error {
+	instruction := strings.TrimSpace(args[1])
+	if instruction == "" {
+		return failure.New(failure.UserInput, "the instruction is empty")
+	}
+	root, file, err := projectFile(args[0])
+	if err != nil {
+		return err
+	}
+	source, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+	if err != nil {
+		return failure.Wrap(failure.NotFound, fmt.Errorf("failed to read %s: %w", args[0], err))
+	}
+	if len(source) > generationSourceLimit {
+		return failure.New(failure.UserInput, "%s is too large, split it up or pick a smaller file", args[0])
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+
+	feedback := ""
+	for attempt := 1; ; attempt++ {
+		response, err := proposeRefactor(client, file, string(source), instruction, feedback)
+		if err != nil {
+			return err
+		}
+		patch, checkErr := extractPatch(response, file)
+		if checkErr == nil {
+			printPatch(os.Stdout, patch)
+			checkErr = gitApply(root, patch, "--check")
+		}
+
+		if checkErr == nil {
+			if config.DryRun() {
+				return nil
+			}
+			if !confirmAction("refactor", fmt.Sprintf("Apply this diff to %s", file), true, refactorYes) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			if err := gitApply(root, patch); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Refactored %s\n", file)
+			return nil
+		}
+
+		fmt.Printf("⚠️  The diff doesn't apply cleanly: %v\n", checkErr)
+		if config.DryRun() {
+			return nil
+		}
+		switch rejectionChoice(attempt < refactorAttempts, patch != "") {
+		case rejectRetry:
+			feedback = fmt.Sprintf("Your previous diff failed to apply: %v\n\n%s", checkErr, patch)
+			continue
+		case rejectPartial:
+			// git apply --reject also fails when it leaves rejected hunks.
+			rejectErr := gitApply(root, patch, "--reject")
+			if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(file)) + ".rej"); err != nil {
+				if rejectErr != nil {
+					return rejectErr
+				}
+				fmt.Printf("✓ Refactored %s\n", file)
+				return nil
+			}
+			fmt.Printf("✓ Applied the hunks that fit, see %s.rej for the others\n", file)
+			return nil
+		case rejectSave:
+			saved, err := savePatch(patch)
+			if err != nil {
+				return err
+			}
+			return failure.New(failure.API, "the diff doesn't apply cleanly, saved it to %s", saved)
+		default:
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+}
+
+// proposeRefactor asks the AI assistant for a diff refactoring the source
+// of file, with feedback on a previous diff that failed to apply if any.
+func proposeRefactor(client ai.Assistant, file, source, instruction, feedback string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	response, err := client.RefactorCode(ctx, file, source, instruction, feedback)
+	stopThinking()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get a refactoring: %w", err)
+	}
+	return response, nil
+}
+
+// extractPatch returns the unified diff of file in the answer of the AI
+// assistant, with its headers naming file as git apply expects. Diffs of
+// other files are refused.
+func extractPatch(response, file string) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(response, "\r\n", "\n"), "\n")
+	var patch []string
+	hunks := 0
+	inHunk := false
+	for i, line := range lines {
+		// Inside a hunk "--- " may also remove a line starting with "-- ".
+		header := strings.HasPrefix(line, "--- ") && (!inHunk || i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "))
+		switch {
+		case header:
+			if name := patchPath(line); name != "" && name != file {
+				return "", failure.New(failure.API, "the AI assistant changed %s instead of %s", name, file)
+			}
+			patch = append(patch, "--- a/"+file)
+			inHunk = false
+		case strings.HasPrefix(line, "+++ ") && !inHunk:
+			if name := patchPath(line); name != "" && name != file {
+				return "", failure.New(failure.API, "the AI assistant changed %s instead of %s", name, file)
+			}
+			patch = append(patch, "+++ b/"+file)
+		case strings.HasPrefix(line, "@@"):
+			if len(patch) == 0 {
+				patch = append(patch, "--- a/"+file, "+++ b/"+file)
+			}
+			patch = append(patch, line)
+			hunks++
+			inHunk = true
+		case inHunk && (line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, `\`)):
+			// Empty lines stand for empty context lines whose space was lost.
+			if line == "" {
+				line = " "
+			}
+			patch = append(patch, line)
+		default:
+			inHunk = false
+		}
+	}
+	if hunks == 0 {
+		return "", failure.New(failure.API, "the AI assistant returned no diff")
+	}
+	// Trailing empty context lines are usually the end of the answer.
+	for len(patch) > 0 && patch[len(patch)-1] == " " {
+		patch = patch[:len(patch)-1]
+	}
+	return strings.Join(patch, "\n") + "\n", nil
+}
+
+// patchPath returns the path in a --- or +++ header line without its a/ or
+// b/ prefix, or "" for /dev/null.
+func patchPath(header string) string {
+	name := strings.TrimSpace(header[4:])
+	if tab := strings.IndexByte(name, '\t'); tab >= 0 {
+		name = name[:tab]
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		name = name[2:]
+	}
+	return name
+}
+
+// gitApply runs git apply with args on patch in root. Line counts of the
+// hunk headers are recounted, as the AI assistant often gets them wrong.
+func gitApply(root, patch string, args ...string) error {
+	var stderr bytes.Buffer
+	apply := exec.Command("git", append([]string{"apply", "--recount"}, args...)...)
+	apply.Dir = root
+	apply.Stdin = strings.NewReader(patch)
+	apply.Stderr = &stderr
+	if err := apply.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return failure.New(failure.API, "%s", strings.ReplaceAll(message, "\n", "; "))
+	}
+	return nil
+}
+
+// printPatch prints patch with the roles of its lines colored.
+func printPatch(w io.Writer, patch string) {
+	for _, line := range strings.Split(strings.TrimRight(patch, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "):
+			line = theme.Paint(theme.Heading, line)
+		case strings.HasPrefix(line, "@@"):
+			line = theme.Paint(theme.Info, line)
+		case strings.HasPrefix(line, "+"):
+			line = theme.Paint(theme.Success, line)
+		case strings.HasPrefix(line, "-"):
+			line = theme.Paint(theme.Error, line)
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+}
+
+// rejectionChoice asks what to do with a diff that doesn't apply cleanly.
+// Without a terminal the diff is saved, or there is nothing to do without
+// one.
+func rejectionChoice(canRetry, hasPatch bool) string {
+	var choices []string
+	if canRetry {
+		choices = append(choices, rejectRetry)
+	}
+	if hasPatch {
+		choices = append(choices, rejectPartial, rejectSave)
+	}
+	choices = append(choices, rejectCancel)
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		if hasPatch {
+			return rejectSave
+		}
+		return rejectCancel
+	}
+	prompt := promptui.Select{
+		Label:     "What now",
+		Items:     choices,
+		Templates: theme.SelectTemplates("."),
+	}
+	_, choice, err := prompt.Run()
+	if err != nil {
+		if !errors.Is(err, promptui.ErrInterrupt) {
+			fmt.Fprintf(os.Stderr, "Warning: prompt failed: %v\n", err)
+		}
+		return rejectCancel
+	}
+	return choice
+}
+
+// savePatch writes patch to a temporary file and returns its path.
+func savePatch(patch string) (string, error) {
+	saved, err := os.CreateTemp("", "aura-refactor-*.patch")
+	if err != nil {
+		return "", fmt.Errorf("failed to save the diff: %w", err)
+	}
+	defer saved.Close()
+	if _, err := saved.WriteString(patch); err != nil {
+		return "", fmt.Errorf("failed to save the diff: %w", err)
+	}
+	return saved.Name(), nil
+}
+
+func init() {
+	refactorCmd.Flags().BoolVarP(&refactorYes, "yes", "y", false, "Apply the diff without asking")
+	rootCmd.AddCommand(refactorCmd)
+}
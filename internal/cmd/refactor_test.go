@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "fenced with wrong prefixes",
+			response: "```diff\n--- parser.go\n+++ parser.go\n@@ -1,3 +1,3 @@\n package parser\n\n-func old() {}\n+func renamed() {}\n```\nThis renames old.",
+			want:     "--- a/parser.go\n+++ b/parser.go\n@@ -1,3 +1,3 @@\n package parser\n \n-func old() {}\n+func renamed() {}\n",
+		},
+		{
+			name:     "hunks without headers",
+			response: "@@ -2 +2 @@\n-x = 1\n+x = 2\n",
+			want:     "--- a/parser.go\n+++ b/parser.go\n@@ -2 +2 @@\n-x = 1\n+x = 2\n",
+		},
+		{
+			name:     "removed line starting with two dashes",
+			response: "--- a/parser.go\n+++ b/parser.go\n@@ -1,2 +1,1 @@\n--- a comment\n SELECT 1;\n",
+			want:     "--- a/parser.go\n+++ b/parser.go\n@@ -1,2 +1,1 @@\n--- a comment\n SELECT 1;\n",
+		},
+		{name: "other file", response: "--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+b\n", wantErr: true},
+		{name: "no diff", response: "I would rename the function.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractPatch(tt.response, "parser.go")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("extractPatch() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitApply(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(root))
+	source := filepath.Join(root, "parser.go")
+	os.WriteFile(source, []byte("package parser\n\nfunc old() {}\n"), 0o644)
+
+	// The hunk header counts are wrong on purpose, git apply recounts them.
+	patch, err := extractPatch("@@ -1,9 +1,9 @@\n package parser\n\n-func old() {}\n+func renamed() {}\n", "parser.go")
+	if err != nil {
+		t.Fatalf("extractPatch() error = %v", err)
+	}
+	if err := gitApply(root, patch, "--check"); err != nil {
+		t.Fatalf("gitApply(--check) error = %v", err)
+	}
+	if err := gitApply(root, patch); err != nil {
+		t.Fatalf("gitApply() error = %v", err)
+	}
+	if content, _ := os.ReadFile(source); string(content) != "package parser\n\nfunc renamed() {}\n" {
+		t.Errorf("parser.go = %q", content)
+	}
+
+	if err := gitApply(root, patch, "--check"); err == nil {
+		t.Error("gitApply(--check) of an applied patch error = nil")
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var regexCmd = &cobra.Command{
+	Use:   "regex <description>",
+	Short: "Build a regular expression from a description",
+	Long: `Build a regular expression from a description with the AI assistant and
+explain each part of it. With --explain the argument is an existing regular
+expression to explain instead.
+
+The expression is then tested against sample lines: the lines piped into
+aura, or in a terminal the lines you type, one at a time, until an empty
+line. Matches are highlighted and their groups listed.
+
+--flavor picks the dialect: go for Go's RE2, also used by the tester, or
+pcre for Perl compatible expressions as in PHP, grep -P or most editors.
+PCRE expressions are tested with RE2 where they are compatible.
+
+Examples:
+  aura regex "match ISO dates in logs"
+  aura regex "an email address" --flavor pcre
+  aura regex --explain '^(?P<key>\w+)=(.*)$'
+  grep ERROR app.log | aura regex "the request ID after req="`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRegex,
+}
+
+var (
+	regexFlavor  string
+	regexExplain bool
+)
+
+// regexFlavors names the supported flavors.
+var regexFlavors = map[string]string{
+	"go":   "Go RE2",
+	"pcre": "PCRE",
+}
+
+// regexAnswer is the JSON answer of the AI assistant.
+type regexAnswer struct {
+	Pattern string `json:"pattern"`
+	Parts   []struct {
+		Token   string `json:"token"`
+		Meaning string `json:"meaning"`
+	} `json:"parts"`
+	Notes string `json:"notes"`
+}
+
+func runRegex(cmd *cobra.Command, args []string) error {
+	flavor, ok := regexFlavors[regexFlavor]
+	if !ok {
+		return failure.New(failure.UserInput, "unknown flavor '%s', use go or pcre", regexFlavor)
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	answer, err := buildRegex(client, args[0], flavor, regexExplain)
+	if err != nil {
+		return err
+	}
+	printRegexAnswer(os.Stdout, answer, flavor)
+
+	re, err := regexp.Compile(answer.Pattern)
+	if err != nil {
+		if regexFlavor == "go" {
+			fmt.Fprintf(os.Stderr, "Warning: the expression doesn't compile: %v\n", err)
+		} else {
+			fmt.Println("ℹ️  The expression uses features RE2 lacks, so it can't be tested here")
+		}
+		return nil
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println("Type lines to test, an empty line to quit.")
+		return testRegex(os.Stdout, re, os.Stdin, true)
+	}
+	return testRegex(os.Stdout, re, os.Stdin, false)
+}
+
+// buildRegex asks the AI assistant for an expression matching description,
+// or with explain to explain the expression description.
+func buildRegex(client ai.Assistant, description, flavor string, explain bool) (regexAnswer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+	var (
+		response string
+		err      error
+	)
+	if explain {
+		response, err = client.ExplainRegex(ctx, description, flavor)
+	} else {
+		response, err = client.BuildRegex(ctx, description, flavor)
+	}
+	stopThinking()
+
+	if err != nil {
+		return regexAnswer{}, fmt.Errorf("failed to build the expression: %w", err)
+	}
+	answer, err := parseRegexAnswer(response)
+	if err != nil {
+		return regexAnswer{}, err
+	}
+	if explain {
+		answer.Pattern = description
+	}
+	return answer, nil
+}
+
+// parseRegexAnswer parses the JSON answer of the AI assistant.
+func parseRegexAnswer(response string) (regexAnswer, error) {
+	var answer regexAnswer
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start || json.Unmarshal([]byte(response[start:end+1]), &answer) != nil || answer.Pattern == "" {
+		return regexAnswer{}, failure.New(failure.API, "the AI assistant returned no valid expression")
+	}
+	return answer, nil
+}
+
+// printRegexAnswer prints the expression with the meaning of each part.
+func printRegexAnswer(w io.Writer, answer regexAnswer, flavor string) {
+	fmt.Fprintf(w, "%s\n  %s\n\n", theme.Paint(theme.Heading, flavor+":"), theme.Paint(theme.Command, answer.Pattern))
+
+	width := 0
+	for _, part := range answer.Parts {
+		width = max(width, len([]rune(part.Token)))
+	}
+	for _, part := range answer.Parts {
+		padding := strings.Repeat(" ", width-len([]rune(part.Token)))
+		fmt.Fprintf(w, "  %s%s  %s\n", theme.Paint(theme.Command, part.Token), padding, part.Meaning)
+	}
+	if answer.Notes != "" {
+		fmt.Fprintf(w, "\nℹ️  %s\n", strings.TrimSpace(answer.Notes))
+	}
+	fmt.Fprintln(w)
+}
+
+// testRegex tests re against the lines of input, prompting for each one
+// when interactive, and prints the matches.
+func testRegex(w io.Writer, re *regexp.Regexp, input io.Reader, interactive bool) error {
+	scanner := bufio.NewScanner(input)
+	matched, total := 0, 0
+	for {
+		if interactive {
+			fmt.Fprint(w, "❯ ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := scanner.Text()
+		if interactive && line == "" {
+			break
+		}
+		total++
+		if describeMatch(w, re, line) {
+			matched++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read the sample lines: %w", err)
+	}
+	if !interactive && total > 0 {
+		fmt.Fprintf(w, "\n%d of %d lines match\n", matched, total)
+	}
+	return nil
+}
+
+// describeMatch prints line with the matches of re highlighted and the
+// groups of the first match, and reports whether re matches.
+func describeMatch(w io.Writer, re *regexp.Regexp, line string) bool {
+	matches := re.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 {
+		fmt.Fprintf(w, "✗ %s\n", theme.Paint(theme.Muted, line))
+		return false
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		b.WriteString(line[last:match[0]])
+		b.WriteString(theme.Paint(theme.Success, "["+line[match[0]:match[1]]+"]"))
+		last = match[1]
+	}
+	b.WriteString(line[last:])
+	fmt.Fprintf(w, "✓ %s\n", b.String())
+
+	names := re.SubexpNames()
+	first := matches[0]
+	for group := 1; group < len(names); group++ {
+		name := fmt.Sprint(group)
+		if names[group] != "" {
+			name = names[group]
+		}
+		value := "(no match)"
+		if first[2*group] >= 0 {
+			value = fmt.Sprintf("%q", line[first[2*group]:first[2*group+1]])
+		}
+		fmt.Fprintf(w, "    %s: %s\n", name, value)
+	}
+	return true
+}
+
+func init() {
+	regexCmd.Flags().StringVar(&regexFlavor, "flavor", "go", "Dialect of the expression: go (RE2) or pcre")
+	regexCmd.Flags().BoolVar(&regexExplain, "explain", false, "Explain the expression given instead of building one")
+	rootCmd.AddCommand(regexCmd)
+}
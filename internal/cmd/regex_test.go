@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseRegexAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "fenced json",
+			response: "```json\n{\"pattern\": \"\\\\d{4}-\\\\d{2}-\\\\d{2}\", \"parts\": [{\"token\": \"\\\\d{4}\", \"meaning\": \"the year\"}]}\n```",
+			want:     `\d{4}-\d{2}-\d{2}`,
+		},
+		{name: "no pattern", response: `{"parts": []}`, wantErr: true},
+		{name: "prose", response: "Use \\d+", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRegexAnswer(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRegexAnswer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Pattern != tt.want {
+				t.Errorf("parseRegexAnswer() pattern = %q, want %q", got.Pattern, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestRegex(t *testing.T) {
+	re := regexp.MustCompile(`(?P<year>\d{4})-(\d{2})`)
+	input := "2024-05-01 started\nno date here\n1999-12 and 2000-01\n"
+
+	var out bytes.Buffer
+	if err := testRegex(&out, re, strings.NewReader(input), false); err != nil {
+		t.Fatalf("testRegex() error = %v", err)
+	}
+	want := `✓ [2024-05]-01 started
+    year: "2024"
+    2: "05"
+✗ no date here
+✓ [1999-12] and [2000-01]
+    year: "1999"
+    2: "12"
+
+2 of 3 lines match
+`
+	if out.String() != want {
+		t.Errorf("testRegex() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestTestRegexInteractiveStopsAtEmptyLine(t *testing.T) {
+	var out bytes.Buffer
+	testRegex(&out, regexp.MustCompile(`a`), strings.NewReader("a\n\nb\n"), true)
+	if strings.Contains(out.String(), "b") || strings.Contains(out.String(), "lines match") {
+		t.Errorf("testRegex() = %q, want it to stop at the empty line", out.String())
+	}
+}
+
+func TestBuildRegexExplainKeepsPattern(t *testing.T) {
+	client := &MockAIClient{response: `{"pattern": "changed", "parts": [{"token": "^", "meaning": "start"}]}`}
+	answer, err := buildRegex(client, `^\w+$`, "Go RE2", true)
+	if err != nil {
+		t.Fatalf("buildRegex() error = %v", err)
+	}
+	if answer.Pattern != `^\w+$` || len(answer.Parts) != 1 {
+		t.Errorf("buildRegex() = %+v", answer)
+	}
+}
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/notify"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+var remindCmd = &cobra.Command{
+	Use:   "remind <message>",
+	Short: "Get a desktop notification after a while or at a time",
+	Long: `Schedule a reminder, delivered as a desktop notification, or with the
+terminal bell where notifications are unavailable.
+
+Reminders are kept in the Aura database and delivered by a background
+process. One missed because the computer was off is delivered the next time
+you run 'aura remind' or 'aura pomodoro'.
+
+Examples:
+  aura remind "standup" --in 25m
+  aura remind "deploy window opens" --at 14:30
+  aura remind "renew the certificate" --in 2d
+  aura remind list
+  aura remind cancel 3`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRemind,
+}
+
+var remindListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List pending reminders",
+	Args:    cobra.NoArgs,
+	RunE:    runRemindList,
+}
+
+var remindCancelCmd = &cobra.Command{
+	Use:     "cancel <id>",
+	Aliases: []string{"rm"},
+	Short:   "Cancel a pending reminder",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runRemindCancel,
+}
+
+// remindDeliverCmd is started in the background by the reminder commands. It
+// delivers the pending reminders as they come due and exits when there are
+// none left.
+var remindDeliverCmd = &cobra.Command{
+	Use:    "deliver",
+	Short:  "Deliver pending reminders as they come due",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runRemindDeliver,
+}
+
+var (
+	remindIn string
+	remindAt string
+)
+
+// reminderPoll is the longest the background process sleeps before looking
+// at the reminders again, so new ones and a suspended computer don't delay
+// them long.
+const reminderPoll = 10 * time.Second
+
+// reminderLease is how long the background process delivering reminders
+// holds the lease it renews on every look. Another one takes over once a
+// stopped process let it expire.
+const reminderLease = time.Minute
+
+func runRemind(cmd *cobra.Command, args []string) error {
+	due, err := reminderDue(remindIn, remindAt, time.Now())
+	if err != nil {
+		return err
+	}
+	message := strings.Join(args, " ")
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	id, err := database.AddReminder("remind", message, due)
+	if err != nil {
+		return err
+	}
+	startReminderDelivery()
+	fmt.Printf("✓ Reminder %d set for %s\n", id, describeDue(due, time.Now()))
+	return nil
+}
+
+// reminderDue returns when a reminder set with --in or --at is due. A time
+// of day that has passed today means tomorrow.
+func reminderDue(in, at string, now time.Time) (time.Time, error) {
+	switch {
+	case in != "" && at != "":
+		return time.Time{}, failure.New(failure.UserInput, "use either --in or --at, not both")
+	case in != "":
+		delay, err := parseAge(in)
+		if err != nil || delay == 0 {
+			return time.Time{}, failure.New(failure.UserInput, "invalid duration '%s', use e.g. 25m, 1h30m or 2d", in)
+		}
+		return now.Add(delay), nil
+	case at != "":
+		if due, err := time.ParseInLocation("2006-01-02 15:04", at, now.Location()); err == nil {
+			if !due.After(now) {
+				return time.Time{}, failure.New(failure.UserInput, "%s is in the past", at)
+			}
+			return due, nil
+		}
+		clock, err := time.ParseInLocation("15:04", at, now.Location())
+		if err != nil {
+			return time.Time{}, failure.New(failure.UserInput, "invalid time '%s', use e.g. 14:30 or \"2025-06-01 09:00\"", at)
+		}
+		due := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !due.After(now) {
+			due = due.AddDate(0, 0, 1)
+		}
+		return due, nil
+	}
+	return time.Time{}, failure.New(failure.UserInput, "say when with --in, such as --in 25m, or --at, such as --at 14:30")
+}
+
+// describeDue describes when a reminder is due, such as "14:30 (in 25m)".
+func describeDue(due, now time.Time) string {
+	when := due.Format("15:04")
+	if y, m, d := due.Date(); y != now.Year() || m != now.Month() || d != now.Day() {
+		when = due.Format("Mon Jan 2 15:04")
+	}
+	if !due.After(now) {
+		return when + " (due)"
+	}
+	return fmt.Sprintf("%s (in %s)", when, shortDuration(due.Sub(now)))
+}
+
+// shortDuration formats d in whole minutes, or seconds below one, such as
+// "1h5m" or "40s".
+func shortDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	s := d.Round(time.Minute).String()
+	s = strings.TrimSuffix(s, "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func runRemindList(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	reminders, err := database.PendingReminders()
+	if err != nil {
+		return err
+	}
+	if len(reminders) == 0 {
+		fmt.Println("No pending reminders. Set one with 'aura remind <message> --in 25m'.")
+		return nil
+	}
+	startReminderDelivery()
+
+	now := time.Now()
+	idWidth, dueWidth := 0, 0
+	dues := make([]string, len(reminders))
+	for i, reminder := range reminders {
+		dues[i] = describeDue(reminder.Due, now)
+		idWidth = max(idWidth, len(strconv.FormatInt(reminder.ID, 10)))
+		dueWidth = max(dueWidth, len(dues[i]))
+	}
+	for i, reminder := range reminders {
+		fmt.Printf("  %*d  %-*s  %s\n", idWidth, reminder.ID, dueWidth, dues[i], reminder.Message)
+	}
+	return nil
+}
+
+func runRemindCancel(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return failure.New(failure.UserInput, "invalid reminder ID '%s'", args[0])
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	cancelled, err := database.CancelReminder(id)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return failure.New(failure.NotFound, "no pending reminder %d", id)
+	}
+	fmt.Printf("✓ Cancelled reminder %d\n", id)
+	return nil
+}
+
+func runRemindDeliver(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	holder := strconv.Itoa(os.Getpid())
+	defer database.ReleaseReminders(holder)
+	for {
+		leased, err := database.LeaseReminders(holder, time.Now().Add(reminderLease), time.Now())
+		if err != nil || !leased {
+			// Another process delivers them
+			return err
+		}
+		next, err := deliverDueReminders(database, time.Now())
+		if err != nil {
+			return err
+		}
+		if next.IsZero() {
+			// A reminder added meanwhile was left to this process
+			if err := database.ReleaseReminders(holder); err != nil {
+				return err
+			}
+			if pending, err := database.PendingReminders(); err != nil || len(pending) == 0 {
+				return err
+			}
+			continue
+		}
+		time.Sleep(min(time.Until(next), reminderPoll))
+	}
+}
+
+// deliverDueReminders delivers the reminders due at now and returns when the
+// next one is due, or the zero time if none is pending.
+func deliverDueReminders(database *db.DB, now time.Time) (time.Time, error) {
+	reminders, err := database.PendingReminders()
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, reminder := range reminders {
+		if reminder.Due.After(now) {
+			return reminder.Due, nil
+		}
+		// It may have been cancelled meanwhile
+		claimed, err := database.ClaimReminder(reminder.ID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if claimed {
+			deliverReminder(reminder)
+		}
+	}
+	return time.Time{}, nil
+}
+
+// deliverReminder shows a reminder as a desktop notification, or rings the
+// terminal bell and prints it on the terminal without one.
+func deliverReminder(reminder db.Reminder) {
+	title := "⏰ Reminder"
+	if reminder.Kind == "pomodoro" {
+		title = "🍅 Pomodoro"
+	}
+	err := notify.Send(title, reminder.Message)
+	if err == nil {
+		return
+	}
+
+	terminal, openErr := os.OpenFile(platform.Current().TerminalDevice(), os.O_WRONLY, 0)
+	if openErr != nil {
+		return
+	}
+	defer terminal.Close()
+	fmt.Fprintf(terminal, "\a\n%s: %s\n", title, reminder.Message)
+	if !errors.Is(err, notify.ErrUnavailable) {
+		fmt.Fprintf(terminal, "Warning: %v\n", err)
+	}
+}
+
+// startReminderDelivery starts a detached 'aura remind deliver' process
+// delivering the pending reminders. It exits right away if another one
+// already does.
+func startReminderDelivery() {
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	// Output is discarded, the process outlives this command
+	waiter := exec.Command(executable, "remind", "deliver")
+	detachProcess(waiter)
+	if err := waiter.Start(); err == nil {
+		waiter.Process.Release()
+	}
+}
+
+func init() {
+	remindCmd.Flags().StringVar(&remindIn, "in", "", "Remind after this long, e.g. 25m, 1h30m or 2d")
+	remindCmd.Flags().StringVar(&remindAt, "at", "", "Remind at this time, e.g. 14:30 or \"2025-06-01 09:00\"")
+	remindCmd.AddCommand(remindListCmd)
+	remindCmd.AddCommand(remindCancelCmd)
+	remindCmd.AddCommand(remindDeliverCmd)
+	rootCmd.AddCommand(remindCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		in, at  string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "25m", want: now.Add(25 * time.Minute)},
+		{in: "2d", want: now.Add(48 * time.Hour)},
+		{at: "16:30", want: time.Date(2025, 6, 1, 16, 30, 0, 0, time.Local)},
+		{at: "09:00", want: time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local)},
+		{at: "2025-06-03 08:15", want: time.Date(2025, 6, 3, 8, 15, 0, 0, time.Local)},
+		{at: "2025-05-01 08:15", wantErr: true},
+		{in: "0m", wantErr: true},
+		{in: "soon", wantErr: true},
+		{at: "noon", wantErr: true},
+		{in: "5m", at: "16:00", wantErr: true},
+		{wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := reminderDue(tt.in, tt.at, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("reminderDue(%q, %q) = %v, %v, want %v", tt.in, tt.at, got, err, tt.want)
+		}
+	}
+}
+
+func TestDescribeDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		due  time.Time
+		want string
+	}{
+		{now.Add(25 * time.Minute), "15:25 (in 25m)"},
+		{now.Add(2*time.Hour + 20*time.Second), "17:00 (in 2h)"},
+		{now.Add(40 * time.Second), "15:00 (in 40s)"},
+		{now.Add(10 * time.Hour), "Mon Jun 2 01:00 (in 10h)"},
+		{now.Add(-time.Minute), "14:59 (due)"},
+	}
+	for _, tt := range tests {
+		if got := describeDue(tt.due, now); got != tt.want {
+			t.Errorf("describeDue(%v) = %q, want %q", tt.due, got, tt.want)
+		}
+	}
+}
+
+func TestPomodoroSchedule(t *testing.T) {
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local)
+	steps := pomodoroSchedule("docs", start, 25*time.Minute, 5*time.Minute, 2)
+	want := []pomodoroStep{
+		{start.Add(25 * time.Minute), `Round 1/2 of "docs" done, take a 5m break`},
+		{start.Add(30 * time.Minute), `Break over, start round 2/2 of "docs"`},
+		{start.Add(55 * time.Minute), `Round 2/2 of "docs" done, session complete`},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("pomodoroSchedule() = %+v, want %+v", steps, want)
+	}
+	for i := range want {
+		if !steps[i].at.Equal(want[i].at) || steps[i].message != want[i].message {
+			t.Errorf("pomodoroSchedule()[%d] = %+v, want %+v", i, steps[i], want[i])
+		}
+	}
+
+	if steps := pomodoroSchedule("", start, time.Hour, 0, 1); len(steps) != 1 || steps[0].message != "Round 1/1 done, session complete" {
+		t.Errorf("pomodoroSchedule() of one round = %+v", steps)
+	}
+}
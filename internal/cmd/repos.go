@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var reposCmd = &cobra.Command{
+	Use:   "repos",
+	Short: "Overview of bookmarked Git repositories",
+	Long:  "Show the state of all bookmarked Git repositories at once.",
+}
+
+var reposStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the state of all bookmarked repositories",
+	Long: `Show a table of the bookmarked Git repositories with their branch, their
+uncommitted changes, how far they are ahead of or behind their upstream and
+the age of their last commit. Repositories are read several at a time.
+
+Ahead and behind counts are as of the last fetch; --fetch fetches first.
+
+Examples:
+  aura repos status
+  aura repos status --fetch
+  aura repos status --tag work --dirty`,
+	Args: cobra.NoArgs,
+	RunE: paged(runReposStatus),
+}
+
+var (
+	reposFetch bool
+	reposTag   string
+	reposDirty bool
+	reposJobs  int
+)
+
+// repoState is the state of a repository in `aura repos status`.
+type repoState struct {
+	Alias  string
+	Path   string
+	Branch string
+	// Upstream is the tracked branch, or "" if there is none.
+	Upstream      string
+	Ahead, Behind int
+	Changes       statusCounts
+	// LastCommit is the age of the last commit, such as "2 days ago", or ""
+	// if there are no commits.
+	LastCommit string
+	// Error is why the state could not be read.
+	Error string
+}
+
+// clean reports whether the repository has nothing to commit, push or pull.
+func (s repoState) clean() bool {
+	return s.Error == "" && s.Changes == statusCounts{} && s.Ahead == 0 && s.Behind == 0
+}
+
+func runReposStatus(cmd *cobra.Command, args []string) error {
+	if reposJobs < 1 {
+		return failure.New(failure.UserInput, "--jobs must be at least 1")
+	}
+	// git fetch runs on its own, outside the offline transport
+	if reposFetch {
+		if err := config.RequireNetwork("aura repos status --fetch"); err != nil {
+			return err
+		}
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	if reposTag != "" {
+		if bookmarks, err = selectBookmarks(database, bookmarkSelector{Tag: strings.ToLower(reposTag)}); err != nil {
+			return err
+		}
+	}
+
+	// Several bookmarks may point into the same repository.
+	var repos []repoState
+	seen := make(map[string]bool)
+	for _, bookmark := range bookmarks {
+		if root := repoRoot(bookmark.Path); root != "" && !seen[root] {
+			seen[root] = true
+			repos = append(repos, repoState{Alias: bookmark.Alias, Path: root})
+		}
+	}
+	if len(repos) == 0 {
+		if reposTag != "" {
+			fmt.Printf("ℹ️  No bookmarked Git repositories are tagged '%s'.\n", reposTag)
+		} else {
+			fmt.Println("ℹ️  No bookmarked Git repositories. Bookmark one with 'aura bookmark add <alias>' inside it.")
+		}
+		return nil
+	}
+
+	message := "Reading"
+	if reposFetch {
+		message = "Fetching"
+	}
+	spinner := progress.NewSpinner(fmt.Sprintf("%s %d %s", message, len(repos), plural(int64(len(repos)), "repository", "repositories"))).Start()
+	var done atomic.Int32
+	var group errgroup.Group
+	group.SetLimit(reposJobs)
+	for i := range repos {
+		group.Go(func() error {
+			readRepoState(&repos[i], reposFetch)
+			spinner.Update(fmt.Sprintf("%d of %d done", done.Add(1), len(repos)))
+			return nil
+		})
+	}
+	group.Wait()
+	spinner.Stop()
+
+	if reposDirty {
+		var dirty []repoState
+		for _, repo := range repos {
+			if !repo.clean() {
+				dirty = append(dirty, repo)
+			}
+		}
+		if len(dirty) == 0 {
+			fmt.Printf("✓ All %d %s are clean and in sync\n", len(repos), plural(int64(len(repos)), "repository", "repositories"))
+			return nil
+		}
+		repos = dirty
+	}
+	printRepoStates(repos)
+	return nil
+}
+
+// readRepoState fills in the state of the repository at repo.Path, fetching
+// first if fetch is set.
+func readRepoState(repo *repoState, fetch bool) {
+	git := func(args ...string) *exec.Cmd {
+		cmd := exec.Command("git", append([]string{"-C", repo.Path}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		return cmd
+	}
+
+	if fetch {
+		if output, err := git("fetch", "--quiet").CombinedOutput(); err != nil {
+			repo.Error = "fetch failed: " + lastLine(string(output))
+		}
+	}
+	output, err := git("status", "--porcelain=v2", "--branch").Output()
+	if err != nil {
+		repo.Error = "git status failed"
+		return
+	}
+	parseRepoStatus(repo, string(output))
+	if age, err := git("log", "-1", "--format=%cr").Output(); err == nil {
+		repo.LastCommit = strings.TrimSpace(string(age))
+	}
+}
+
+// parseRepoStatus reads the branch, upstream, divergence and changes of repo
+// from the output of `git status --porcelain=v2 --branch`.
+func parseRepoStatus(repo *repoState, output string) {
+	var entries []string
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			repo.Branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.upstream "):
+			repo.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &repo.Ahead, &repo.Behind)
+		case strings.HasPrefix(line, "1 ") || strings.HasPrefix(line, "2 "):
+			// Ordinary and renamed entries carry the XY status of v1.
+			entries = append(entries, strings.ReplaceAll(line[2:4], ".", " ")+" x")
+		case strings.HasPrefix(line, "u "):
+			entries = append(entries, "UU x")
+		case strings.HasPrefix(line, "? "):
+			entries = append(entries, "?? x")
+		}
+	}
+	repo.Changes = parseStatusCounts(strings.Join(entries, "\n"))
+}
+
+// printRepoStates prints a table with a line per repository.
+func printRepoStates(repos []repoState) {
+	rows := [][]string{{"Repository", "Branch", "Changes", "Upstream", "Last commit"}}
+	for _, repo := range repos {
+		rows = append(rows, []string{repo.Alias, repo.Branch, repoChanges(repo.Changes), repoSync(repo), repo.LastCommit})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+
+	for r, row := range rows {
+		var cells []string
+		for i, cell := range row {
+			cell = fmt.Sprintf("%-*s", widths[i], cell)
+			switch {
+			case r == 0:
+				cell = theme.Paint(theme.Muted, cell)
+			case i == 2 && repos[r-1].Changes != statusCounts{}:
+				cell = theme.Paint(theme.Warning, cell)
+			case i == 3 && repos[r-1].Behind > 0:
+				cell = theme.Paint(theme.Warning, cell)
+			case i == 4:
+				cell = theme.Paint(theme.Muted, cell)
+			}
+			cells = append(cells, cell)
+		}
+		line := strings.TrimRight(strings.Join(cells, "  "), " ")
+		if r > 0 && repos[r-1].Error != "" {
+			line += "  " + theme.Paint(theme.Error, "✗ "+repos[r-1].Error)
+		}
+		fmt.Println(line)
+	}
+}
+
+// repoChanges describes uncommitted changes briefly, such as "2M 1?".
+func repoChanges(counts statusCounts) string {
+	var parts []string
+	for _, part := range []struct {
+		count int
+		mark  string
+	}{
+		{counts.Conflicts, "!"},
+		{counts.Staged, "+"},
+		{counts.Modified, "M"},
+		{counts.Untracked, "?"},
+	} {
+		if part.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", part.count, part.mark))
+		}
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, " ")
+}
+
+// repoSync describes how the branch of repo relates to its upstream.
+func repoSync(repo repoState) string {
+	switch {
+	case repo.Branch == "(detached)":
+		return "detached"
+	case repo.Upstream == "":
+		return "no upstream"
+	case repo.Ahead == 0 && repo.Behind == 0:
+		return "in sync"
+	}
+	var parts []string
+	if repo.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", repo.Ahead))
+	}
+	if repo.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", repo.Behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	reposStatusCmd.Flags().BoolVar(&reposFetch, "fetch", false, "Fetch every repository first")
+	reposStatusCmd.Flags().StringVar(&reposTag, "tag", "", "Only bookmarks with this tag")
+	reposStatusCmd.Flags().BoolVar(&reposDirty, "dirty", false, "Only repositories with changes or not in sync")
+	reposStatusCmd.Flags().IntVarP(&reposJobs, "jobs", "j", 8, "Number of repositories to read at a time")
+
+	reposCmd.AddCommand(reposStatusCmd)
+	rootCmd.AddCommand(reposCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/remote"
+)
+
+func TestParseRepoStatus(t *testing.T) {
+	output := `# branch.oid 1a2b3c4d
+# branch.head feat/login
+# branch.upstream origin/feat/login
+# branch.ab +2 -1
+1 .M N... 100644 100644 100644 aaa bbb src/app.go
+1 M. N... 100644 100644 100644 aaa bbb README.md
+2 R. N... 100644 100644 100644 aaa bbb R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 aaa bbb ccc go.mod
+? notes.txt
+`
+	var repo repoState
+	parseRepoStatus(&repo, output)
+	if repo.Branch != "feat/login" || repo.Upstream != "origin/feat/login" || repo.Ahead != 2 || repo.Behind != 1 {
+		t.Errorf("parseRepoStatus() branch = %+v", repo)
+	}
+	if want := (statusCounts{Staged: 2, Modified: 1, Untracked: 1, Conflicts: 1}); repo.Changes != want {
+		t.Errorf("parseRepoStatus() changes = %+v, want %+v", repo.Changes, want)
+	}
+	if got := repoChanges(repo.Changes); got != "1! 2+ 1M 1?" {
+		t.Errorf("repoChanges() = %q", got)
+	}
+	if got := repoSync(repo); got != "↑2 ↓1" {
+		t.Errorf("repoSync() = %q", got)
+	}
+	if repo.clean() {
+		t.Error("clean() = true for a repository with changes")
+	}
+
+	var initial repoState
+	parseRepoStatus(&initial, "# branch.oid (initial)\n# branch.head main\n")
+	if !initial.clean() || repoSync(initial) != "no upstream" || repoChanges(initial.Changes) != "clean" {
+		t.Errorf("parseRepoStatus() of a new repository = %+v", initial)
+	}
+}
+
+func TestReposStatusFetchOffline(t *testing.T) {
+	t.Setenv("AURA_OFFLINE", "1")
+	original := reposFetch
+	defer func() { reposFetch = original }()
+	reposFetch = true
+
+	if err := runReposStatus(reposStatusCmd, nil); failure.KindOf(err) != failure.Network {
+		t.Errorf("runReposStatus() error = %v, want a network error", err)
+	}
+}
+
+func TestNetworkCommandsOffline(t *testing.T) {
+	t.Setenv("AURA_OFFLINE", "1")
+	originalRemote := cleanupRemote
+	defer func() { cleanupRemote = originalRemote }()
+	cleanupRemote = true
+
+	location, _ := remote.Parse("s3://bucket/logs")
+	tests := map[string]func() error{
+		"git cleanup --remote": func() error { return runGitCleanup(gitCleanupCmd, nil) },
+		"dotfiles init":        func() error { return runDotfilesInit(dotfilesInitCmd, []string{"https://example.com/dotfiles.git"}) },
+		"go s3://":             func() error { return goRemote(location) },
+	}
+	for name, run := range tests {
+		if err := run(); failure.KindOf(err) != failure.Network {
+			t.Errorf("%s error = %v, want a network error", name, err)
+		}
+	}
+}
@@ -3,10 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
+	"github.com/timfewi/aura-cli-go/internal/auditlog"
 	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+	"github.com/timfewi/aura-cli-go/internal/theme"
 )
 
 var rootCmd = &cobra.Command{
@@ -14,22 +20,132 @@ var rootCmd = &cobra.Command{
 	Short: "Aura - Intelligent CLI Assistant",
 	Long: `Aura is an intelligent command-line interface assistant designed to augment
 your existing shell with context-aware suggestions, AI-powered assistance,
-and intelligent navigation.`,
+and intelligent navigation.
+
+Run without arguments in a terminal to open the command palette, a fuzzy
+finder over the actions of the current directory, your bookmarks and all
+commands.`,
 	Version: "1.0.0",
 }
 
-// Execute runs the root command.
+// Execute runs the root command and reports its error. The exit code of the
+// error is failure.ExitCode.
 func Execute() error {
-	return rootCmd.Execute()
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		err = classifyError(err)
+		reportError(cmd, err)
+	}
+	return err
 }
 
+// noColor turns colored output off, like the NO_COLOR environment variable.
+var noColor bool
+
+// deterministic asks the AI assistant for reproducible answers.
+var deterministic bool
+
+// showPrompt shows every prompt before it is sent to the AI assistant and
+// asks whether to send it.
+var showPrompt bool
+
+// offline and readOnly keep Aura off the network and from changing the
+// database, dryRun from changing anything, see config.Offline,
+// config.ReadOnly and config.DryRun.
+var offline, readOnly, dryRun bool
+
+// noPager prints long output directly instead of showing it in the pager,
+// see pageOutput.
+var noPager bool
+
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.RunE = runPalette
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		auditlog.Source = cmd.CommandPath()
+		// aura config doctor reports them itself, completions must stay quiet
+		if cmd != configDoctorCmd && cmd.Name() != cobra.ShellCompRequestCmd && cmd.Name() != cobra.ShellCompNoDescRequestCmd {
+			warnSettingsProblems()
+		}
+	}
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "", "Print errors as text or json")
+	rootCmd.PersistentFlags().BoolVar(&deterministic, "deterministic", false, "Ask the AI assistant for reproducible answers (temperature 0, fixed seed)")
+	rootCmd.PersistentFlags().BoolVar(&showPrompt, "show-prompt", false, "Show every prompt before it is sent to the AI assistant and ask whether to send it")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Turn the AI assistant and all other network requests off")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Open the database read-only, changes fail")
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "Print long output directly instead of showing it in the pager")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show the files, database rows and commands a command would change or run, without doing it")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return failure.Wrap(failure.UserInput, err)
+	})
+
+	// --help skips the initializers, which translate the help
+	help := rootCmd.HelpFunc()
+	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if config.ConfigDir == "" {
+			initConfig()
+		}
+		help(cmd, args)
+	})
 }
 
 func initConfig() {
+	// Set before Initialize, which enforces them; they also reach aura
+	// processes started by this one, such as hooks
+	if offline {
+		os.Setenv(envvar.Offline.Name, "1")
+	}
+	if readOnly {
+		os.Setenv(envvar.ReadOnly.Name, "1")
+	}
+	if dryRun {
+		os.Setenv(envvar.DryRun.Name, "1")
+	}
+	if noPager {
+		os.Setenv(envvar.Pager.Name, "cat")
+	}
 	if err := config.Initialize(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing config: %v\n", err)
-		os.Exit(1)
+		exitWithError(failure.Wrap(failure.Config, fmt.Errorf("failed to initialize config: %w", err)))
+	}
+	if errorFormat != "" && errorFormat != "text" && errorFormat != "json" {
+		exitWithError(failure.New(failure.UserInput, "invalid error format '%s', use text or json", errorFormat))
+	}
+	for _, path := range config.MigratedFiles {
+		fmt.Fprintf(os.Stderr, "ℹ️  Moved %s to %s\n", filepath.Base(path), displayHomePath(filepath.Dir(path)))
+	}
+	if deterministic {
+		// Also reaches aura processes started by this one, such as hooks
+		os.Setenv(envvar.Deterministic.Name, "1")
+	}
+	applySettings()
+}
+
+// warnSettingsProblems reports the unknown settings and invalid values in
+// the settings file and the team settings file.
+func warnSettingsProblems() {
+	for _, problem := range config.SettingsProblems {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s: %s\n", problem.Location(displayHomePath(config.GetSettingsFile())), problem.Key, problem.Message)
+	}
+	for _, problem := range config.TeamProblems {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s: %s\n", problem.Location(displayHomePath(config.TeamSettingsFile)), problem.Key, problem.Message)
+	}
+	for _, problem := range config.PolicyProblems {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s: %s\n", problem.Location(displayHomePath(config.PolicyFile)), problem.Key, problem.Message)
+	}
+}
+
+// applySettings applies the settings that shape the output, such as the
+// theme and the language.
+func applySettings() {
+	color := theme.ColorEnabled(noColor)
+	if err := theme.Apply(config.UserSettings.Theme, color); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		theme.Apply(theme.DefaultName, color)
 	}
+	applyLocale()
+	rootCmd.SetErrPrefix(theme.Paint(theme.Error, i18n.T("error.prefix")))
 }
@@ -3,10 +3,27 @@ package cmd
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
 )
 
+func TestMain(m *testing.M) {
+	// Keep the audit log of prompts and commands out of the package directory
+	tempDir, err := os.MkdirTemp("", "aura_cmd_test_*")
+	if err != nil {
+		panic(err)
+	}
+	config.StateDir = tempDir
+
+	code := m.Run()
+
+	os.RemoveAll(tempDir)
+	os.Exit(code)
+}
+
 func TestRootCommand(t *testing.T) {
 	cmd := rootCmd
 
@@ -102,6 +119,7 @@ func TestInitConfig(t *testing.T) {
 	defer os.Setenv("AURA_ENV", originalEnv)
 
 	os.Setenv("AURA_ENV", "test")
+	t.Setenv("AURA_DB_PATH", filepath.Join(tempDir, "aura.db"))
 
 	// This should not cause any errors
 	initConfig()
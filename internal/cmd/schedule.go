@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/cron"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/scheduler"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run commands on a schedule",
+	Long:  "Run commands and aliases on a cron schedule through the crontab or the Windows Task Scheduler, with a log of their runs.",
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <name> <cron> <command>",
+	Short: "Schedule a command",
+	Long: `Schedule a command to run at the times of a cron expression, replacing any
+schedule with the same name. It is added to your crontab, or to the Task
+Scheduler on Windows, which runs 'aura schedule run <name>'.
+
+The command runs in the bookmarks given with --in, in every bookmarked Git
+repository with --repos, or else in your home directory. A command starting
+with an alias of 'aura alias' runs the aliased command. Use 'aura cron' to
+write or check the expression.
+
+Examples:
+  aura schedule add fetch "0 2 * * *" "git fetch --all --prune" --repos
+  aura schedule add backup @daily "aura pack notes" --in notes
+  aura schedule add cleanup "0 9 * * 1" "docker system prune -f"`,
+	Args: cobra.ExactArgs(3),
+	RunE: runScheduleAdd,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List scheduled commands",
+	Args:    cobra.NoArgs,
+	RunE:    runScheduleList,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a scheduled command",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runScheduleRemove,
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a scheduled command now",
+	Long: `Run a scheduled command now in each of its directories and record the runs
+in the log. The scheduler of the system runs it this way; a failed run shows
+a desktop notification.
+
+Examples:
+  aura schedule run fetch`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScheduleRun,
+}
+
+var scheduleLogCmd = &cobra.Command{
+	Use:   "log [name]",
+	Short: "Show the runs of scheduled commands",
+	Long: `Show the latest runs of scheduled commands, or of one, with their exit
+code and duration.
+
+Examples:
+  aura schedule log
+  aura schedule log fetch --output`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: paged(runScheduleLog),
+}
+
+var (
+	scheduleIn     []string
+	scheduleRepos  bool
+	scheduleLimit  int
+	scheduleOutput bool
+)
+
+// scheduleAllRepos is the target standing for all bookmarked repositories.
+const scheduleAllRepos = "*"
+
+// scheduleTailSize is how much of the end of the output of a run is kept.
+const scheduleTailSize = 4 * 1024
+
+// scheduleNamePattern matches schedule names, which end up in crontab
+// comments and task names.
+var scheduleNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	name, expression, command := args[0], args[1], strings.TrimSpace(args[2])
+	if !scheduleNamePattern.MatchString(name) {
+		return failure.New(failure.UserInput, "schedule names can only contain letters, digits, dots, dashes and underscores")
+	}
+	if command == "" {
+		return failure.New(failure.UserInput, "the command is empty")
+	}
+	if scheduleRepos && len(scheduleIn) > 0 {
+		return failure.New(failure.UserInput, "use either --in or --repos")
+	}
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return err
+	}
+	if platform.Current().IsWindows() {
+		if _, err := schedule.TaskScheduler(); err != nil {
+			return err
+		}
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	targets := scheduleIn
+	if scheduleRepos {
+		targets = []string{scheduleAllRepos}
+	}
+	for _, alias := range targets {
+		if alias == scheduleAllRepos {
+			continue
+		}
+		bookmark, err := database.GetBookmark(alias)
+		if err != nil {
+			return err
+		}
+		if bookmark == nil {
+			return failure.New(failure.NotFound, "bookmark '%s' not found", alias)
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the aura executable: %w", err)
+	}
+	entry := scheduler.Entry{
+		Name:     name,
+		Schedule: schedule,
+		Command:  []string{executable, "schedule", "run", name},
+		Env:      scheduleEnv(),
+	}
+	if err := scheduler.Install(entry); err != nil {
+		return err
+	}
+	if err := database.SaveSchedule(db.Schedule{Name: name, Cron: schedule.Expression, Command: command, Targets: targets}); err != nil {
+		scheduler.Uninstall(name)
+		return err
+	}
+
+	fmt.Printf("✓ Scheduled '%s'\n", name)
+	fmt.Printf("  %s  %s\n", theme.Paint(theme.Muted, "When    "), schedule.Describe())
+	if next := schedule.Next(time.Now(), 1); len(next) > 0 {
+		fmt.Printf("  %s  %s\n", theme.Paint(theme.Muted, "Next run"), next[0].Format("Mon 2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	schedules, err := database.ListSchedules()
+	if err != nil {
+		return err
+	}
+	if len(schedules) == 0 {
+		fmt.Println("No scheduled commands yet. Add one with 'aura schedule add <name> <cron> <command>'.")
+		return nil
+	}
+
+	width := 0
+	for _, schedule := range schedules {
+		width = max(width, len(schedule.Name))
+	}
+	for _, schedule := range schedules {
+		when := schedule.Cron
+		if parsed, err := cron.Parse(schedule.Cron); err == nil {
+			when = parsed.Describe()
+		}
+		fmt.Printf("%-*s  %s  %s\n", width, schedule.Name, theme.Paint(theme.Command, schedule.Command), theme.Paint(theme.Muted, scheduleTargets(schedule.Targets)))
+
+		status := "never run"
+		if runs, err := database.ScheduleRuns(schedule.Name, 1); err == nil && len(runs) > 0 {
+			status = "last run " + scheduleRunStatus(runs[0], 0) + " " + runs[0].StartedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-*s  %s, %s\n", width, "", when, status)
+	}
+	return nil
+}
+
+func runScheduleRemove(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	schedule, err := database.GetSchedule(args[0])
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return failure.New(failure.NotFound, "schedule '%s' not found", args[0])
+	}
+	if err := scheduler.Uninstall(schedule.Name); err != nil {
+		return err
+	}
+	if _, err := database.RemoveSchedule(schedule.Name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Removed schedule '%s'\n", schedule.Name)
+	return nil
+}
+
+func runScheduleRun(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	schedule, err := database.GetSchedule(args[0])
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return failure.New(failure.NotFound, "schedule '%s' not found", args[0])
+	}
+	aliases, err := database.ListShellAliases()
+	if err != nil {
+		return err
+	}
+	command := expandAlias(schedule.Command, aliases)
+	dirs, missing, err := scheduleDirs(database, schedule.Targets)
+	if err != nil {
+		return err
+	}
+
+	if config.DryRun() {
+		for _, dir := range dirs {
+			skipChange("run in %s: %s", displayHomePath(dir), command)
+		}
+		skipChange("record %d %s in schedule_runs", len(dirs)+len(missing), plural(int64(len(dirs)+len(missing)), "run", "runs"))
+		return nil
+	}
+
+	failed := 0
+	for _, alias := range missing {
+		run := db.ScheduleRun{Name: schedule.Name, Dir: alias, StartedAt: time.Now(), ExitCode: -1, Output: fmt.Sprintf("bookmark '%s' not found", alias)}
+		fmt.Fprintf(os.Stderr, "✗ %s\n", run.Output)
+		if err := database.AddScheduleRun(run); err != nil {
+			return err
+		}
+		failed++
+	}
+	for _, dir := range dirs {
+		if len(dirs) > 1 {
+			fmt.Println(theme.Paint(theme.Heading, "→ "+displayHomePath(dir)))
+		}
+		run := runScheduled(schedule.Name, command, dir, os.Stdout)
+		if err := database.AddScheduleRun(run); err != nil {
+			return err
+		}
+		if run.ExitCode != 0 {
+			failed++
+			sendNotification("✗ Scheduled command failed", fmt.Sprintf("%s in %s\nexit code %d", schedule.Name, filepath.Base(dir), run.ExitCode))
+		}
+	}
+
+	if total := len(dirs) + len(missing); failed > 0 {
+		return fmt.Errorf("%d of %d %s of '%s' failed, see 'aura schedule log %s'", failed, total, plural(int64(total), "run", "runs"), schedule.Name, schedule.Name)
+	}
+	return nil
+}
+
+func runScheduleLog(cmd *cobra.Command, args []string) error {
+	if scheduleLimit <= 0 {
+		return failure.New(failure.UserInput, "--limit must be positive")
+	}
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	runs, err := database.ScheduleRuns(name, scheduleLimit)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		if name != "" {
+			fmt.Printf("ℹ️  '%s' has not run yet.\n", name)
+		} else {
+			fmt.Println("ℹ️  No scheduled command has run yet.")
+		}
+		return nil
+	}
+
+	width := 0
+	for _, run := range runs {
+		width = max(width, len(run.Name))
+	}
+	for _, run := range runs {
+		fmt.Printf("%s  %s  %-*s  %6s  %s\n",
+			theme.Paint(theme.Muted, run.StartedAt.Format("2006-01-02 15:04")),
+			scheduleRunStatus(run, 5), width, run.Name, shortDuration(run.Duration), displayHomePath(run.Dir))
+		if scheduleOutput && strings.TrimSpace(run.Output) != "" {
+			for _, line := range strings.Split(strings.TrimRight(run.Output, "\n"), "\n") {
+				fmt.Printf("    %s\n", theme.Paint(theme.Muted, line))
+			}
+		}
+	}
+	return nil
+}
+
+// runScheduled runs command in dir, writing its output to out, and returns
+// the run with the masked end of its output.
+func runScheduled(name, command, dir string, out io.Writer) db.ScheduleRun {
+	run := db.ScheduleRun{Name: name, Dir: dir, StartedAt: time.Now()}
+	if err := checkCommand(command, dir); err != nil {
+		run.ExitCode, run.Output = -1, err.Error()+"\n"
+		fmt.Fprintln(out, err)
+		return run
+	}
+	output := &outputTail{max: scheduleTailSize}
+
+	shell := platform.Current().ShellCommand(command)
+	shell.Dir = dir
+	shell.Stdout = io.MultiWriter(out, output)
+	shell.Stderr = io.MultiWriter(out, output)
+	err := shell.Run()
+	run.Duration = time.Since(run.StartedAt)
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		run.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		run.ExitCode = -1
+		fmt.Fprintf(output, "failed to run command: %v\n", err)
+	}
+	run.Output = scrubSecrets("the output of "+name, output.String())
+	return run
+}
+
+// expandAlias replaces an alias at the start of command by its command, as
+// the shell would.
+func expandAlias(command string, aliases []db.ShellAlias) string {
+	name, rest, _ := strings.Cut(command, " ")
+	for _, alias := range aliases {
+		if alias.Name == name {
+			return strings.TrimSpace(alias.Command + " " + rest)
+		}
+	}
+	return command
+}
+
+// scheduleDirs returns the directories of targets, bookmarks or
+// scheduleAllRepos for the bookmarked Git repositories, and the bookmarks
+// that no longer exist. Without targets it is the home directory.
+func scheduleDirs(database *db.DB, targets []string) (dirs, missing []string, err error) {
+	if len(targets) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return []string{home}, nil, nil
+	}
+
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, target := range targets {
+		if target == scheduleAllRepos {
+			bookmarks, err := database.ListBookmarks()
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, bookmark := range bookmarks {
+				if root := repoRoot(bookmark.Path); root != "" {
+					add(root)
+				}
+			}
+			continue
+		}
+		bookmark, err := database.GetBookmark(target)
+		if err != nil {
+			return nil, nil, err
+		}
+		if bookmark == nil {
+			missing = append(missing, target)
+			continue
+		}
+		add(bookmark.Path)
+	}
+	return dirs, missing, nil
+}
+
+// scheduleTargets describes where a schedule runs.
+func scheduleTargets(targets []string) string {
+	switch {
+	case len(targets) == 0:
+		return "in ~"
+	case len(targets) == 1 && targets[0] == scheduleAllRepos:
+		return "in all bookmarked repositories"
+	default:
+		return "in " + strings.Join(targets, ", ")
+	}
+}
+
+// scheduleRunStatus returns the status mark of run, padded to width.
+func scheduleRunStatus(run db.ScheduleRun, width int) string {
+	if run.ExitCode == 0 {
+		return theme.Paint(theme.Success, fmt.Sprintf("%-*s", width, "✓"))
+	}
+	return theme.Paint(theme.Error, fmt.Sprintf("%-*s", width, fmt.Sprintf("✗ %d", run.ExitCode)))
+}
+
+// scheduleEnv returns the variables scheduled commands need from the
+// current environment: the PATH, which cron keeps minimal, and the Aura
+// settings and XDG directories selecting the database.
+func scheduleEnv() []string {
+	var env []string
+	for _, key := range []string{"PATH", envvar.Env.Name, envvar.DBPath.Name, "XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		if value := os.Getenv(key); value != "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+func init() {
+	scheduleAddCmd.Flags().StringSliceVar(&scheduleIn, "in", nil, "Bookmarks to run the command in (comma-separated)")
+	scheduleAddCmd.Flags().BoolVar(&scheduleRepos, "repos", false, "Run the command in every bookmarked Git repository")
+	scheduleLogCmd.Flags().IntVarP(&scheduleLimit, "limit", "n", 20, "Number of runs to show")
+	scheduleLogCmd.Flags().BoolVar(&scheduleOutput, "output", false, "Show the end of the output of each run")
+
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	scheduleCmd.AddCommand(scheduleLogCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
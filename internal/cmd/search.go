@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search notes, snippets, chats and bookmarks at once",
+	Long: `Search your notes, shell aliases, saved HTTP requests, 'aura ask'
+conversations and bookmarks with their metadata for the words of the query,
+each matching the start of a word, the best matches first. Matches in names
+and questions rank above matches in the rest.
+
+Types: note, chat, bookmark, alias and request, or snippet for aliases and
+requests together.
+
+Examples:
+  aura search deploy
+  aura search "staging vpn" --type note
+  aura search docker -t snippet -t chat -n 20`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+var (
+	searchTypes []string
+	searchLimit int
+)
+
+// searchTypeKinds maps the types of --type to the kinds of search results.
+var searchTypeKinds = map[string][]string{
+	"note":     {"note"},
+	"chat":     {"chat"},
+	"bookmark": {"bookmark"},
+	"alias":    {"alias"},
+	"request":  {"request"},
+	"snippet":  {"alias", "request"},
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	kinds, err := searchKinds(searchTypes)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	results, err := database.Search(strings.Join(args, " "), searchLimit, kinds...)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No matches.")
+		return nil
+	}
+	printSearchResults(os.Stdout, results)
+	return nil
+}
+
+// searchKinds returns the result kinds of types, or none for all kinds.
+func searchKinds(types []string) ([]string, error) {
+	var kinds []string
+	seen := map[string]bool{}
+	for _, name := range types {
+		expanded, ok := searchTypeKinds[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, failure.New(failure.UserInput, "unknown type '%s', use note, chat, bookmark, alias, request or snippet", name)
+		}
+		for _, kind := range expanded {
+			if !seen[kind] {
+				seen[kind] = true
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	return kinds, nil
+}
+
+// printSearchResults prints one line per result: its kind, what it is and
+// the matching part.
+func printSearchResults(w io.Writer, results []db.SearchResult) {
+	items := make([]string, len(results))
+	width := 0
+	for i, result := range results {
+		items[i] = searchItem(result)
+		width = max(width, len(items[i]))
+	}
+	for i, result := range results {
+		fmt.Fprintf(w, "%s  %s  %s\n",
+			theme.Paint(theme.Muted, fmt.Sprintf("%-8s", result.Kind)),
+			items[i]+strings.Repeat(" ", width-len(items[i])),
+			highlightSnippet(result.Snippet))
+	}
+}
+
+// searchItem names the entry of a result the way its own command does.
+func searchItem(result db.SearchResult) string {
+	switch result.Kind {
+	case "note":
+		return fmt.Sprintf("#%s %s", result.Ref, describeNoteProject(result.Label))
+	case "chat":
+		return result.Label
+	case "request":
+		return result.Title + " " + result.Label
+	case "bookmark":
+		return result.Title + " " + describeNoteProject(result.Label)
+	default:
+		return result.Title
+	}
+}
+
+func init() {
+	searchCmd.Flags().StringSliceVarP(&searchTypes, "type", "t", nil, "Only search these types (note, chat, bookmark, alias, request, snippet)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 10, "Maximum number of results to show")
+	rootCmd.AddCommand(searchCmd)
+}
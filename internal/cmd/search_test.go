@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+func TestSearchKinds(t *testing.T) {
+	kinds, err := searchKinds([]string{"snippet", "Alias", "note"})
+	if err != nil {
+		t.Fatalf("searchKinds() error = %v", err)
+	}
+	if want := []string{"alias", "request", "note"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("searchKinds() = %v, want %v", kinds, want)
+	}
+	if kinds, err := searchKinds(nil); err != nil || kinds != nil {
+		t.Errorf("searchKinds(nil) = %v, %v, want all kinds", kinds, err)
+	}
+	if _, err := searchKinds([]string{"history"}); err == nil {
+		t.Error("searchKinds() of an unknown type succeeded")
+	}
+}
+
+func TestPrintSearchResults(t *testing.T) {
+	var out bytes.Buffer
+	printSearchResults(&out, []db.SearchResult{
+		{Kind: "alias", Ref: "dc", Title: "dc", Snippet: db.SnippetStart + "docker" + db.SnippetEnd + " compose"},
+		{Kind: "request", Ref: "health", Label: "GET", Title: "health", Snippet: "GET http://" + db.SnippetStart + "docker" + db.SnippetEnd + ".test/health"},
+		{Kind: "note", Ref: "3", Snippet: "restart " + db.SnippetStart + "docker" + db.SnippetEnd},
+	})
+	want := `alias     dc               docker compose
+request   health GET       GET http://docker.test/health
+note      #3 global notes  restart docker
+`
+	if out.String() != want {
+		t.Errorf("printSearchResults() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/secrets"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Find secrets before they are committed",
+	Long:  `Detect likely secrets such as API keys, tokens and private keys in your files.`,
+}
+
+var secretsScanCmd = &cobra.Command{
+	Use:   "scan [paths...]",
+	Short: "Scan files for likely secrets",
+	Long: `Scan files for likely secrets. Without arguments all files tracked by Git are
+scanned, or all files below the current directory outside of a Git repository.
+
+Use --staged to scan only the lines added in the staged changes, as done by
+the pre-commit hook installed with 'aura hooks install secrets'.
+
+Besides known formats such as AWS keys, tokens and private keys, random looking
+values assigned to names like "key", "token" or "secret" are reported.
+Add the comment "aura:allow-secret" to a line to ignore it.
+
+Examples:
+  aura secrets scan
+  aura secrets scan --staged
+  aura secrets scan config/ deploy.sh`,
+	RunE: runSecretsScan,
+}
+
+// skippedDirs lists directories that are never scanned when walking the tree.
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"venv":         true,
+	".venv":        true,
+}
+
+var secretsStaged bool
+
+func runSecretsScan(cmd *cobra.Command, args []string) error {
+	if secretsStaged {
+		return runSecretsScanStaged()
+	}
+
+	files, err := filesToScan(args)
+	if err != nil {
+		return err
+	}
+
+	var findings []secrets.Finding
+	for _, file := range files {
+		result, err := secrets.ScanFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", file, err)
+			continue
+		}
+		findings = append(findings, result...)
+	}
+
+	if len(findings) == 0 {
+		fmt.Printf("✓ No secrets found in %d files\n", len(files))
+		return nil
+	}
+
+	return reportSecrets(findings)
+}
+
+func runSecretsScanStaged() error {
+	if !isGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	diff, err := exec.Command("git", "diff", "--staged", "--unified=0", "--no-color").Output()
+	if err != nil {
+		return fmt.Errorf("failed to get staged changes: %w", err)
+	}
+
+	findings := secrets.ScanDiff(string(diff))
+	if len(findings) == 0 {
+		fmt.Println("✓ No secrets found in staged changes")
+		return nil
+	}
+
+	err = reportSecrets(findings)
+	fmt.Println("Remove the secrets, or mark false positives with 'aura:allow-secret'.")
+	fmt.Println("To commit anyway, use: git commit --no-verify")
+	return err
+}
+
+// reportSecrets prints the findings and returns an error so the command
+// exits with a non-zero status.
+func reportSecrets(findings []secrets.Finding) error {
+	fmt.Printf("Found %d likely secrets:\n", len(findings))
+	for _, finding := range findings {
+		fmt.Printf("  %s\n", finding)
+	}
+	return fmt.Errorf("secrets detected")
+}
+
+// filesToScan expands the given paths into files. Without paths it returns the
+// files tracked by Git, falling back to walking the current directory.
+func filesToScan(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		if output, err := exec.Command("git", "ls-files", "-z").Output(); err == nil {
+			var files []string
+			for _, file := range strings.Split(string(output), "\x00") {
+				if file != "" {
+					files = append(files, file)
+				}
+			}
+			return files, nil
+		}
+		paths = []string{"."}
+	}
+
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if p != path && skippedDirs[d.Name()] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return files, nil
+}
+
+func init() {
+	secretsScanCmd.Flags().BoolVar(&secretsStaged, "staged", false, "Scan only lines added in staged changes")
+
+	secretsCmd.AddCommand(secretsScanCmd)
+	rootCmd.AddCommand(secretsCmd)
+}
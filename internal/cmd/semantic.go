@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/semantic"
+)
+
+var indexEmbedCmd = &cobra.Command{
+	Use:   "embed",
+	Short: "Update the embeddings used by semantic search",
+	Long: `Embed the descriptions of bookmarks and frequently visited directories, so
+'aura go' finds them by meaning, e.g. 'aura go "the repo with the billing
+service"'. Only directories whose description changed are embedded again.
+
+'aura go' updates the embeddings itself when it searches semantically; run
+this to do it ahead of time. The embedding model is configured in config.yaml:
+
+  ai:
+    embeddings:
+      model: nomic-embed-text
+      url: http://localhost:11434/v1   # local Ollama, no API key`,
+	Args: cobra.NoArgs,
+	RunE: runIndexEmbed,
+}
+
+// semanticHistoryLimit is how many of the most visited directories are
+// searched semantically besides the bookmarks.
+const semanticHistoryLimit = 100
+
+// semanticDocuments returns the bookmarks and most visited directories that
+// still exist, each path once. Their READMEs are described only if the
+// privacy settings allow sending file contents.
+func semanticDocuments(database *db.DB) ([]semantic.Document, error) {
+	var docs []semantic.Document
+	seen := make(map[string]bool)
+	readme := ai.RequireFileContents("the README") == nil
+	add := func(path, alias string) {
+		if seen[path] {
+			return
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return
+		}
+		seen[path] = true
+		docs = append(docs, semantic.Document{Path: path, Alias: alias, Text: semantic.Describe(path, alias, readme)})
+	}
+
+	bookmarks, err := database.ListBookmarks()
+	if err != nil {
+		return nil, err
+	}
+	for _, bookmark := range bookmarks {
+		add(bookmark.Path, bookmark.Alias)
+	}
+
+	frequent, err := database.FrequentDirectories(semanticHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range frequent {
+		add(path, "")
+	}
+
+	return docs, nil
+}
+
+// semanticIndex returns the semantic index of the database using the
+// configured embedding model.
+func semanticIndex(database *db.DB) (semantic.Index, error) {
+	client, err := ai.NewClient()
+	if err != nil {
+		return semantic.Index{}, fmt.Errorf("failed to initialize AI client: %w", err)
+	}
+	return semantic.Index{Store: database, Embedder: client, Model: ai.EmbeddingModel()}, nil
+}
+
+// semanticSearch updates the embeddings and returns the directories best
+// matching a description.
+func semanticSearch(database *db.DB, query string, limit int) ([]semantic.Result, error) {
+	index, err := semanticIndex(database)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := semanticDocuments(database)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if _, err := index.Update(ctx, docs); err != nil {
+		return nil, err
+	}
+	return index.Search(ctx, docs, query, limit)
+}
+
+func runIndexEmbed(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	index, err := semanticIndex(database)
+	if err != nil {
+		return err
+	}
+
+	docs, err := semanticDocuments(database)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	count, err := index.Update(ctx, docs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Embedded %d of %d directories with %s\n", count, len(docs), index.Model)
+	return nil
+}
+
+func init() {
+	indexCmd.AddCommand(indexEmbedCmd)
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+	"github.com/timfewi/aura-cli-go/internal/transfer"
+)
+
+var sendCmd = &cobra.Command{
+	Use:   "send <file>",
+	Short: "Send a file to another machine on the network",
+	Long: `Offer a file to another machine on the same network under a one-time code.
+Running 'aura receive <code>' there finds this machine through multicast
+DNS and fetches the file, encrypted with a key agreed on the code. Only the
+first group of the code is sent over the network, to find the offer; the
+rest is a secret no one watching the network can guess from the transfer,
+and after 3 connections with a wrong code the offer ends. It also ends once
+the file was received.
+
+Networks that block multicast DNS, such as some office and guest networks,
+need the address printed with the code: 'aura receive <code> --from
+<address>'.
+
+Examples:
+  aura send report.pdf
+  aura send ~/Downloads/backup.tar.gz --timeout 1h
+  aura send build.zip --port 7777   # A fixed port to open in a firewall`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSend,
+}
+
+var (
+	sendPort    int
+	sendTimeout time.Duration
+)
+
+func runSend(cmd *cobra.Command, args []string) error {
+	if err := config.RequireNetwork("aura send"); err != nil {
+		return err
+	}
+	path, err := filepath.Abs(config.ExpandHome(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return failure.New(failure.NotFound, "'%s' not found", args[0])
+	}
+	if !info.Mode().IsRegular() {
+		return failure.New(failure.UserInput, "'%s' is not a file, send an archive of a directory instead", args[0])
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	code, err := transfer.NewCode()
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(sendPort))
+	if err != nil {
+		return failure.Wrap(failure.Network, fmt.Errorf("failed to listen on port %d: %w", sendPort, err))
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	go func() {
+		if err := transfer.Advertise(ctx, code, port); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: receivers can't find this machine by the code: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("Offering %s (%s). On the other machine run:\n\n", info.Name(), formatSize(info.Size()))
+	fmt.Printf("  %s\n\n", theme.Paint(theme.Heading, "aura receive "+code))
+	if addresses := localAddresses(port); len(addresses) > 0 {
+		fmt.Println(theme.Paint(theme.Muted, "If it isn't found, add --from "+strings.Join(addresses, " or --from ")))
+	}
+	fmt.Println(theme.Paint(theme.Muted, "Waiting for the receiver, press Ctrl+C to stop..."))
+
+	meter := newTransferMeter(info.Size())
+	err = transfer.Serve(ctx, listener, code, transfer.File{Name: info.Name(), Size: info.Size(), Mode: info.Mode().Perm()}, file, meter.update)
+	meter.done()
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return failure.New(failure.Network, "nobody received %s within %s", info.Name(), sendTimeout)
+	case errors.Is(err, context.Canceled):
+		fmt.Println("Stopped offering the file.")
+		return nil
+	case err != nil:
+		return failure.Wrap(failure.Network, fmt.Errorf("failed to send %s: %w", info.Name(), err))
+	}
+	fmt.Printf("✓ Sent %s\n", info.Name())
+	return nil
+}
+
+// localAddresses returns the addresses with port other machines may reach
+// this one at, its IPv4 addresses outside of loopback and link-local ones.
+func localAddresses(port int) []string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var addresses []string
+	for _, addr := range addrs {
+		ip, ok := addr.(*net.IPNet)
+		if !ok || ip.IP.To4() == nil || ip.IP.IsLoopback() || ip.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		addresses = append(addresses, net.JoinHostPort(ip.IP.String(), strconv.Itoa(port)))
+	}
+	return addresses
+}
+
+// transferMeter draws the progress of a transfer on standard error if it is
+// a terminal.
+type transferMeter struct {
+	w       io.Writer
+	total   int64
+	unicode bool
+
+	mu     sync.Mutex
+	drawn  time.Time
+	active bool
+}
+
+func newTransferMeter(total int64) *transferMeter {
+	m := &transferMeter{total: total, unicode: progress.UnicodeSupported()}
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		m.w = os.Stderr
+	}
+	return m
+}
+
+// update shows that current bytes are transferred, at most ten times a
+// second.
+func (m *transferMeter) update(current int64) {
+	if m.w == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current < m.total && time.Since(m.drawn) < 100*time.Millisecond {
+		return
+	}
+	m.drawn = time.Now()
+	m.active = true
+	fmt.Fprintf(m.w, "\r%s\033[K", transferLine(current, m.total, m.unicode))
+}
+
+// done ends the line of the meter.
+func (m *transferMeter) done() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active {
+		fmt.Fprintln(m.w)
+		m.active = false
+	}
+}
+
+// transferLine describes the progress of a transfer of current of total
+// bytes.
+func transferLine(current, total int64, unicode bool) string {
+	percent := 100
+	if total > 0 {
+		percent = int(current * 100 / total)
+	}
+	return fmt.Sprintf("%s %3d%%  %s of %s", progress.Bar(percent, 100, 30, unicode), percent, formatSize(current), formatSize(total))
+}
+
+func init() {
+	sendCmd.Flags().IntVar(&sendPort, "port", 0, "Port to offer the file on, a free one by default")
+	sendCmd.Flags().DurationVar(&sendTimeout, "timeout", 10*time.Minute, "How long to wait for the receiver")
+	rootCmd.AddCommand(sendCmd)
+}
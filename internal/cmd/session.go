@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/context"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/editor"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/shell"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Save and restore working contexts",
+	Long:  "Save the directory, dev commands and editor file you work with under a name and resume them later, such as after a reboot.",
+}
+
+var sessionSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save the current working context",
+	Long: `Save the current directory, the dev commands you ran for its project and
+the file you edited last as a session, replacing any with the same name.
+
+Dev commands such as 'npm run dev', 'docker compose up' or 'go run .' are
+found in the shell history of the last 12 hours, when it records times, and
+the file is the changed file of the repository modified last. Name them with
+--command and --edit instead.
+
+Examples:
+  aura session save api
+  aura session save web -c "npm run dev" -c "npm run test:watch"
+  aura session save docs --edit docs/index.md --no-commands`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionSave,
+}
+
+var sessionRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Resume a saved working context",
+	Long: `Resume a saved session: change into its directory, start its dev commands
+in the background and open its file in the editor. The shell integration
+runs them in your shell; without it they are printed to run yourself.
+
+Examples:
+  aura session restore api`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionRestore,
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List saved sessions",
+	Args:    cobra.NoArgs,
+	RunE:    runSessionList,
+}
+
+var sessionRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a saved session",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runSessionRemove,
+}
+
+var (
+	sessionCommands   []string
+	sessionNoCommands bool
+	sessionEdit       string
+)
+
+// sessionHistory is how far back the shell history is searched for dev
+// commands.
+const sessionHistory = 12 * time.Hour
+
+// sessionMaxCommands limits the dev commands found in the history.
+const sessionMaxCommands = 5
+
+// devCommandPattern matches commands running a project while it is worked
+// on, such as dev servers, watchers and containers.
+var devCommandPattern = regexp.MustCompile(`^(` +
+	`(npm|pnpm|yarn|bun) (run )?(dev|start|serve|watch)\b|npx (vite|next dev)|vite\b|next dev|` +
+	`docker[ -]compose( \S+)* up\b|` +
+	`go run |air\b|cargo (run|watch)\b|hugo server|make (dev|run|serve|watch)\b|` +
+	`(python3? )?manage\.py runserver|flask run|uvicorn |rails (s|server)\b|bundle exec jekyll serve|` +
+	`aura watch )`)
+
+func runSessionSave(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if strings.ContainsAny(name, " /\\") {
+		return failure.New(failure.UserInput, "session names can't contain spaces or slashes")
+	}
+	if sessionNoCommands && len(sessionCommands) > 0 {
+		return failure.New(failure.UserInput, "use either --command or --no-commands")
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	session := db.Session{Name: name, Dir: dir, Commands: sessionCommands}
+	if !sessionNoCommands && len(sessionCommands) == 0 {
+		since := time.Now().Add(-sessionHistory)
+		visits, err := database.NavigationHistory(since)
+		if err != nil {
+			return err
+		}
+		home, _ := os.UserHomeDir()
+		session.Commands = detectDevCommands(shell.History(home, since), visits, timeProjectOf(dir))
+	}
+
+	switch {
+	case sessionEdit != "":
+		file, err := filepath.Abs(sessionEdit)
+		if err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+		if _, err := os.Stat(file); err != nil {
+			return failure.New(failure.NotFound, "'%s' not found", sessionEdit)
+		}
+		session.EditorFile = file
+	default:
+		if root := repoRoot(dir); root != "" {
+			session.EditorFile = lastChangedFile(root)
+		}
+	}
+
+	if err := database.SaveSession(session); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Saved session '%s'\n", name)
+	printSession(session)
+	return nil
+}
+
+func runSessionRestore(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	session, err := database.GetSession(args[0])
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return failure.New(failure.NotFound, "session '%s' not found", args[0])
+	}
+	if info, err := os.Stat(session.Dir); err != nil || !info.IsDir() {
+		return failure.New(failure.NotFound, "the directory of session '%s', %s, no longer exists", session.Name, session.Dir)
+	}
+	if session.EditorFile != "" {
+		if _, err := os.Stat(session.EditorFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s no longer exists, not opening it\n", session.EditorFile)
+			session.EditorFile = ""
+		}
+	}
+
+	lines := sessionScript(*session, context.ShellKind(), editor.Resolve(""))
+	if envvar.EvalFile.Get() == "" {
+		fmt.Println("Restoring a session must happen in your shell, which the Aura shell integration")
+		fmt.Println("does automatically. Run it yourself:")
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+		return nil
+	}
+	for _, line := range lines {
+		if err := emitShellCommand(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runSessionList(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	sessions, err := database.ListSessions()
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No sessions yet. Save one with 'aura session save <name>'.")
+		return nil
+	}
+
+	width, dirWidth := 0, 0
+	for _, session := range sessions {
+		width = max(width, len(session.Name))
+		dirWidth = max(dirWidth, len(displayHomePath(session.Dir)))
+	}
+	for _, session := range sessions {
+		commands := fmt.Sprintf("%d %s", len(session.Commands), plural(int64(len(session.Commands)), "command", "commands"))
+		fmt.Printf("%-*s  %-*s  %-11s  %s\n", width, session.Name, dirWidth, displayHomePath(session.Dir), commands,
+			theme.Paint(theme.Muted, session.UpdatedAt.Local().Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func runSessionRemove(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	removed, err := database.RemoveSession(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return failure.New(failure.NotFound, "session '%s' not found", args[0])
+	}
+	fmt.Printf("✓ Removed session '%s'\n", args[0])
+	return nil
+}
+
+// detectDevCommands returns the distinct dev commands of history run for
+// project, the most recent last. A command counts for the project of the
+// directory entered last before it, or for any project before the first.
+func detectDevCommands(history []shell.Command, visits []db.NavigationEntry, project string) []string {
+	var commands []string
+	for _, command := range history {
+		line := strings.TrimSpace(command.Line)
+		if !devCommandPattern.MatchString(line) {
+			continue
+		}
+		entered := ""
+		for _, visit := range visits {
+			if visit.Time.After(command.Time) {
+				break
+			}
+			entered = visit.Path
+		}
+		if entered != "" && timeProjectOf(entered) != project {
+			continue
+		}
+		for i, existing := range commands {
+			if existing == line {
+				commands = append(commands[:i], commands[i+1:]...)
+				break
+			}
+		}
+		commands = append(commands, line)
+	}
+	if len(commands) > sessionMaxCommands {
+		commands = commands[len(commands)-sessionMaxCommands:]
+	}
+	return commands
+}
+
+// lastChangedFile returns the changed file of the repository at root that
+// was modified last, or "" if nothing changed.
+func lastChangedFile(root string) string {
+	output, err := exec.Command("git", "-C", root, "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		return ""
+	}
+	latest := ""
+	var latestTime time.Time
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		name := line[3:]
+		if _, renamed, ok := strings.Cut(name, " -> "); ok {
+			name = renamed
+		}
+		path := filepath.Join(root, filepath.FromSlash(strings.Trim(name, `"`)))
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if info.ModTime().After(latestTime) {
+			latest, latestTime = path, info.ModTime()
+		}
+	}
+	return latest
+}
+
+// sessionScript returns the lines of shell restoring session: entering its
+// directory, starting its commands in the background and opening its file
+// with editorCommand.
+func sessionScript(session db.Session, shellKind, editorCommand string) []string {
+	dir := quoteShellArg(shellKind, session.Dir)
+	powershell := shellKind == "powershell" || shellKind == "pwsh"
+
+	var lines []string
+	if powershell {
+		lines = append(lines, "Set-Location "+dir)
+	} else {
+		lines = append(lines, "cd "+dir)
+	}
+	for _, command := range session.Commands {
+		if powershell {
+			lines = append(lines, fmt.Sprintf("Start-Job -ScriptBlock { Set-Location %s; %s } | Out-Null", dir, command))
+		} else {
+			lines = append(lines, command+" &")
+		}
+	}
+	if session.EditorFile != "" && editorCommand != "" {
+		lines = append(lines, editorCommand+" "+quoteShellArg(shellKind, session.EditorFile))
+	}
+	return lines
+}
+
+// printSession prints what session restores.
+func printSession(session db.Session) {
+	fmt.Printf("  %s  %s\n", theme.Paint(theme.Muted, "Directory"), displayHomePath(session.Dir))
+	for i, command := range session.Commands {
+		label := "         "
+		if i == 0 {
+			label = "Commands "
+		}
+		fmt.Printf("  %s  %s\n", theme.Paint(theme.Muted, label), command)
+	}
+	if session.EditorFile != "" {
+		file := session.EditorFile
+		if rel, err := filepath.Rel(session.Dir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+		fmt.Printf("  %s  %s\n", theme.Paint(theme.Muted, "Editor   "), file)
+	}
+}
+
+func init() {
+	sessionSaveCmd.Flags().StringArrayVarP(&sessionCommands, "command", "c", nil, "Dev command to start on restore, instead of those found (repeatable)")
+	sessionSaveCmd.Flags().BoolVar(&sessionNoCommands, "no-commands", false, "Save no dev commands")
+	sessionSaveCmd.Flags().StringVarP(&sessionEdit, "edit", "e", "", "File to open in the editor on restore")
+
+	sessionCmd.AddCommand(sessionSaveCmd)
+	sessionCmd.AddCommand(sessionRestoreCmd)
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionRemoveCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/shell"
+)
+
+func TestDetectDevCommands(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api")
+	web := filepath.Join(dir, "web")
+	for _, path := range []string{filepath.Join(api, ".git"), filepath.Join(web, ".git")} {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Date(2024, 5, 13, 9, 0, 0, 0, time.Local)
+	minute := func(n int) time.Time { return start.Add(time.Duration(n) * time.Minute) }
+	history := []shell.Command{
+		{Line: "make dev", Time: minute(0)}, // Before entering any directory
+		{Line: "docker compose up -d", Time: minute(2)},
+		{Line: "git status", Time: minute(3)},
+		{Line: "npm run dev", Time: minute(12)},
+		{Line: "docker compose up -d", Time: minute(20)},
+		{Line: "go run ./cmd/server", Time: minute(25)},
+	}
+	visits := []db.NavigationEntry{
+		{Path: api, Time: minute(1)},
+		{Path: web, Time: minute(10)},
+		{Path: filepath.Join(api, "cmd"), Time: minute(15)},
+	}
+
+	got := detectDevCommands(history, visits, api)
+	want := []string{"make dev", "docker compose up -d", "go run ./cmd/server"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("detectDevCommands() = %q, want %q", got, want)
+	}
+}
+
+func TestSessionScript(t *testing.T) {
+	session := db.Session{
+		Dir:        "/home/me/my code/api",
+		Commands:   []string{"npm run dev", "docker compose up"},
+		EditorFile: "/home/me/my code/api/src/server.ts",
+	}
+
+	got := sessionScript(session, "bash", "code")
+	want := []string{
+		"cd '/home/me/my code/api'",
+		"npm run dev &",
+		"docker compose up &",
+		"code '/home/me/my code/api/src/server.ts'",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sessionScript(bash) = %q, want %q", got, want)
+	}
+
+	got = sessionScript(db.Session{Dir: `C:\code\api`, Commands: []string{"npm start"}}, "powershell", "")
+	want = []string{
+		`Set-Location 'C:\code\api'`,
+		`Start-Job -ScriptBlock { Set-Location 'C:\code\api'; npm start } | Out-Null`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sessionScript(powershell) = %q, want %q", got, want)
+	}
+}
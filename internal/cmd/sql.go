@@ -0,0 +1,505 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var sqlCmd = &cobra.Command{
+	Use:   "sql [file.db]",
+	Short: "Open a SQL console on the Aura database or a SQLite file",
+	Long: `Open a SQL console on a SQLite database file, or without one on the Aura
+database with its bookmarks, navigation history and conversations.
+
+Statements end with a semicolon and may span lines. Results are shown as
+tables. Line editing and history work as in the shell, with the history kept
+across sessions.
+
+Commands:
+  .tables           List the tables and views
+  .schema [table]   Show the CREATE statements, of all tables or one
+  .help             Show the commands
+  .quit             Leave the console, as do .exit and Ctrl+D
+
+Without a terminal the statements are read from the standard input, so
+scripts can be piped in. Use 'aura db shell' for the databases of a project.
+
+Examples:
+  aura sql
+  aura sql --read-only app.db
+  echo "SELECT alias, path FROM bookmarks;" | aura sql`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSQL,
+}
+
+var sqlReadOnly bool
+
+// sqlCellWidth bounds the width of the cells of result tables.
+const sqlCellWidth = 60
+
+// sqlResult is the result of a statement: the columns and rows of a query,
+// or the number of rows a change changed.
+type sqlResult struct {
+	query   bool
+	columns []string
+	rows    [][]string
+	changed int64
+}
+
+// sqlRunner runs statements on a database.
+type sqlRunner interface {
+	run(statement string) (sqlResult, error)
+	Close() error
+}
+
+func runSQL(cmd *cobra.Command, args []string) error {
+	runner, name, err := openSQLRunner(args)
+	if err != nil {
+		return err
+	}
+	defer runner.Close()
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+		next := func(string) (string, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return "", err
+				}
+				return "", io.EOF
+			}
+			return scanner.Text(), nil
+		}
+		return runSQLConsole(runner, next, os.Stdout, false)
+	}
+
+	line, err := readline.NewEx(&readline.Config{
+		Prompt:          "sql> ",
+		HistoryFile:     filepath.Join(config.StateDir, "sql_history"),
+		InterruptPrompt: "^C",
+		EOFPrompt:       ".quit",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start the console: %w", err)
+	}
+	defer line.Close()
+
+	fmt.Printf("Connected to %s. Type .help for the commands, .quit to leave.\n", name)
+	next := func(prompt string) (string, error) {
+		line.SetPrompt(prompt)
+		text, err := line.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			// Ctrl+C drops the statement being typed, not the console.
+			return "", errSQLInterrupt
+		}
+		return text, err
+	}
+	return runSQLConsole(runner, next, os.Stdout, true)
+}
+
+// errSQLInterrupt tells the console that the statement being typed was
+// interrupted.
+var errSQLInterrupt = errors.New("interrupted")
+
+// openSQLRunner opens the database file named by args, or the Aura
+// database, and returns its name for the greeting.
+func openSQLRunner(args []string) (sqlRunner, string, error) {
+	if len(args) == 0 && config.IsDockerMode() {
+		if err := config.EnsureAuraDbRunning(); err != nil {
+			return nil, "", fmt.Errorf("failed to ensure Docker container is running: %w", err)
+		}
+		return dockerSQL{container: "aura-db", path: config.DatabasePath, readOnly: sqlReadOnly || config.ReadOnly()}, "the Aura database in Docker", nil
+	}
+
+	path := config.DatabasePath
+	if len(args) == 1 {
+		path = args[0]
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, "", failure.Wrap(failure.NotFound, fmt.Errorf("failed to open database %s: %w", path, err))
+	}
+	dsn := path
+	if sqlReadOnly || config.ReadOnly() {
+		dsn = "file:" + path + "?mode=ro"
+	}
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, "", failure.Wrap(failure.UserInput, fmt.Errorf("failed to open database %s: %w", path, err))
+	}
+	return localSQL{conn: conn}, path, nil
+}
+
+// runSQLConsole runs the statements and commands read with next, which
+// shows a prompt when interactive, until the input ends or .quit. Errors
+// of statements are printed and the console goes on, unless it reads a
+// script.
+func runSQLConsole(runner sqlRunner, next func(prompt string) (string, error), w io.Writer, interactive bool) error {
+	var statement strings.Builder
+	for {
+		prompt := "sql> "
+		if statement.Len() > 0 {
+			prompt = "...> "
+		}
+		line, err := next(prompt)
+		if errors.Is(err, errSQLInterrupt) {
+			statement.Reset()
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the input: %w", err)
+		}
+
+		if statement.Len() == 0 && strings.HasPrefix(strings.TrimSpace(line), ".") {
+			quit, err := runSQLCommand(runner, strings.Fields(line), w)
+			if err != nil {
+				if !interactive {
+					return err
+				}
+				fmt.Fprintf(w, "Error: %v\n", err)
+			}
+			if quit {
+				return nil
+			}
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if !statementComplete(statement.String()) {
+			continue
+		}
+		text := strings.TrimSpace(statement.String())
+		statement.Reset()
+		if text == ";" {
+			continue
+		}
+
+		result, err := runner.run(text)
+		if err != nil {
+			if !interactive {
+				return failure.Wrap(failure.UserInput, err)
+			}
+			fmt.Fprintf(w, "Error: %v\n", err)
+			continue
+		}
+		printSQLResult(w, result)
+	}
+
+	if rest := strings.TrimSpace(statement.String()); rest != "" {
+		result, err := runner.run(rest)
+		if err != nil {
+			return failure.Wrap(failure.UserInput, err)
+		}
+		printSQLResult(w, result)
+	}
+	return nil
+}
+
+// runSQLCommand runs a dot command and reports whether it quits the
+// console.
+func runSQLCommand(runner sqlRunner, fields []string, w io.Writer) (bool, error) {
+	switch fields[0] {
+	case ".quit", ".exit":
+		return true, nil
+	case ".help":
+		fmt.Fprintln(w, `.tables           List the tables and views
+.schema [table]   Show the CREATE statements, of all tables or one
+.help             Show the commands
+.quit             Leave the console`)
+	case ".tables":
+		result, err := runner.run(`SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+		if err != nil {
+			return false, err
+		}
+		var names []string
+		for _, row := range result.rows {
+			names = append(names, row[0])
+		}
+		fmt.Fprintln(w, strings.Join(names, "  "))
+	case ".schema":
+		query := `SELECT sql FROM sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'`
+		if len(fields) > 1 {
+			query += fmt.Sprintf(" AND tbl_name = '%s'", strings.ReplaceAll(fields[1], "'", "''"))
+		}
+		result, err := runner.run(query + " ORDER BY tbl_name, type DESC, name")
+		if err != nil {
+			return false, err
+		}
+		if len(result.rows) == 0 && len(fields) > 1 {
+			return false, fmt.Errorf("no table %s", fields[1])
+		}
+		for _, row := range result.rows {
+			fmt.Fprintf(w, "%s;\n", row[0])
+		}
+	default:
+		return false, fmt.Errorf("unknown command %s, see .help", fields[0])
+	}
+	return false, nil
+}
+
+// statementComplete reports whether text ends with a semicolon outside of
+// quotes and comments.
+func statementComplete(text string) bool {
+	complete := false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(text[i+1:], c)
+			if end < 0 {
+				return false
+			}
+			i += end + 1
+			complete = false
+		case c == '-' && strings.HasPrefix(text[i:], "--"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				return complete
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return false
+			}
+			i += end + 3
+		case c == ';':
+			complete = true
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			complete = false
+		}
+	}
+	return complete
+}
+
+// printSQLResult prints the rows of result as a table, or how many rows
+// the statement changed.
+func printSQLResult(w io.Writer, result sqlResult) {
+	if !result.query {
+		if result.changed > 0 {
+			fmt.Fprintf(w, "✓ %d %s changed\n", result.changed, plural(result.changed, "row", "rows"))
+		} else {
+			fmt.Fprintln(w, "✓ Done")
+		}
+		return
+	}
+	if len(result.columns) == 0 {
+		fmt.Fprintln(w, "(0 rows)")
+		return
+	}
+
+	widths := make([]int, len(result.columns))
+	cells := make([][]string, 0, len(result.rows)+1)
+	for _, row := range append([][]string{result.columns}, result.rows...) {
+		line := make([]string, len(result.columns))
+		for i := range line {
+			if i < len(row) {
+				line[i] = truncateCell(row[i])
+			}
+			widths[i] = max(widths[i], len([]rune(line[i])))
+		}
+		cells = append(cells, line)
+	}
+
+	for i, line := range cells {
+		parts := make([]string, len(line))
+		for j, cell := range line {
+			padded := cell + strings.Repeat(" ", widths[j]-len([]rune(cell)))
+			if i == 0 {
+				padded = theme.Paint(theme.Heading, padded)
+			}
+			parts[j] = padded
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(parts, " │ "), " "))
+		if i == 0 {
+			separators := make([]string, len(widths))
+			for j, width := range widths {
+				separators[j] = strings.Repeat("─", width)
+			}
+			fmt.Fprintln(w, strings.Join(separators, "─┼─"))
+		}
+	}
+	fmt.Fprintf(w, "(%d %s)\n", len(result.rows), plural(int64(len(result.rows)), "row", "rows"))
+}
+
+// truncateCell shortens a cell to sqlCellWidth on a single line.
+func truncateCell(cell string) string {
+	cell = strings.NewReplacer("\r\n", "↵", "\n", "↵", "\t", " ").Replace(cell)
+	if runes := []rune(cell); len(runes) > sqlCellWidth {
+		return string(runes[:sqlCellWidth-1]) + "…"
+	}
+	return cell
+}
+
+// plural returns one or other as n calls for.
+func plural(n int64, one, other string) string {
+	if n == 1 {
+		return one
+	}
+	return other
+}
+
+// returnsRows reports whether statement is a query returning rows rather
+// than a change.
+func returnsRows(statement string) bool {
+	fields := strings.Fields(strings.ToUpper(statement))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "SELECT", "WITH", "PRAGMA", "EXPLAIN", "VALUES":
+		return true
+	}
+	for _, field := range fields {
+		if field == "RETURNING" {
+			return true
+		}
+	}
+	return false
+}
+
+// localSQL runs statements on a SQLite file.
+type localSQL struct {
+	conn *sql.DB
+}
+
+func (l localSQL) run(statement string) (sqlResult, error) {
+	if !returnsRows(statement) {
+		result, err := l.conn.Exec(statement)
+		if err != nil {
+			return sqlResult{}, err
+		}
+		changed, _ := result.RowsAffected()
+		return sqlResult{changed: changed}, nil
+	}
+
+	rows, err := l.conn.Query(statement)
+	if err != nil {
+		return sqlResult{}, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return sqlResult{}, err
+	}
+	result := sqlResult{query: true, columns: columns}
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return sqlResult{}, err
+		}
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = formatSQLValue(value)
+		}
+		result.rows = append(result.rows, row)
+	}
+	return result, rows.Err()
+}
+
+func (l localSQL) Close() error {
+	return l.conn.Close()
+}
+
+// formatSQLValue formats a value read from SQLite.
+func formatSQLValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		if !bytes.ContainsRune(v, 0) && len(v) < 1<<10 {
+			return string(v)
+		}
+		return fmt.Sprintf("<blob, %s>", formatSize(int64(len(v))))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// dockerSQL runs statements with sqlite3 in the Docker container of the
+// Aura database.
+type dockerSQL struct {
+	container string
+	path      string
+	readOnly  bool
+}
+
+func (d dockerSQL) run(statement string) (sqlResult, error) {
+	query := returnsRows(statement)
+	if !query {
+		statement = strings.TrimRight(statement, "; \n") + ";\nSELECT changes();"
+	}
+	args := []string{"exec", "-i", d.container, "sqlite3", "-bail", "-csv", "-header", "-nullvalue", "NULL"}
+	if d.readOnly {
+		args = append(args, "-readonly")
+	}
+	var stdout, stderr bytes.Buffer
+	sqlite := exec.Command("docker", append(args, d.path)...)
+	sqlite.Stdin = strings.NewReader(statement)
+	sqlite.Stdout = &stdout
+	sqlite.Stderr = &stderr
+	if err := sqlite.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return sqlResult{}, errors.New(message)
+		}
+		return sqlResult{}, fmt.Errorf("sqlite3 failed: %w", err)
+	}
+
+	reader := csv.NewReader(&stdout)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return sqlResult{}, fmt.Errorf("failed to read the result: %w", err)
+	}
+	if !query {
+		result := sqlResult{}
+		if n := len(records); n > 0 && len(records[n-1]) == 1 {
+			result.changed, _ = strconv.ParseInt(records[n-1][0], 10, 64)
+		}
+		return result, nil
+	}
+	// sqlite3 prints no header for queries without rows.
+	if len(records) == 0 {
+		return sqlResult{query: true}, nil
+	}
+	return sqlResult{query: true, columns: records[0], rows: records[1:]}, nil
+}
+
+func (d dockerSQL) Close() error {
+	return nil
+}
+
+func init() {
+	sqlCmd.Flags().BoolVar(&sqlReadOnly, "read-only", false, "Open the database read-only")
+	rootCmd.AddCommand(sqlCmd)
+}
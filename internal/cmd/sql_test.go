@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatementComplete(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"SELECT 1;", true},
+		{"SELECT 1", false},
+		{"SELECT 1;  \n", true},
+		{"SELECT ';'", false},
+		{"SELECT 'it''s';", true},
+		{"SELECT 1; -- done", true},
+		{"SELECT 1 -- no end;", false},
+		{"SELECT /* ; */ 1", false},
+		{"SELECT \"a;b\" FROM t;", true},
+		{"SELECT 1; SELECT 2", false},
+	}
+	for _, tt := range tests {
+		if got := statementComplete(tt.text); got != tt.want {
+			t.Errorf("statementComplete(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPrintSQLResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result sqlResult
+		want   string
+	}{
+		{
+			name: "rows",
+			result: sqlResult{
+				query:   true,
+				columns: []string{"alias", "path"},
+				rows:    [][]string{{"code", "/home/me/code"}, {"docs", "NULL"}},
+			},
+			want: "alias │ path\n──────┼──────────────\ncode  │ /home/me/code\ndocs  │ NULL\n(2 rows)\n",
+		},
+		{name: "no rows", result: sqlResult{query: true}, want: "(0 rows)\n"},
+		{name: "change", result: sqlResult{changed: 1}, want: "✓ 1 row changed\n"},
+		{name: "no change", result: sqlResult{}, want: "✓ Done\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printSQLResult(&out, tt.result)
+			if out.String() != tt.want {
+				t.Errorf("printSQLResult() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSQLConsole(t *testing.T) {
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	runner := localSQL{conn: conn}
+	defer runner.Close()
+
+	script := []string{
+		"CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT);",
+		"INSERT INTO notes (body)",
+		"VALUES ('first'), (NULL);",
+		".tables",
+		".schema notes",
+		"SELECT id, body FROM notes",
+		"ORDER BY id;",
+		".quit",
+		"SELECT 'not reached';",
+	}
+	var out bytes.Buffer
+	if err := runSQLConsole(runner, scriptLines(script...), &out, false); err != nil {
+		t.Fatalf("runSQLConsole() error = %v", err)
+	}
+	want := `✓ Done
+✓ 2 rows changed
+notes
+CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT);
+id │ body
+───┼──────
+1  │ first
+2  │ NULL
+(2 rows)
+`
+	if out.String() != want {
+		t.Errorf("runSQLConsole() output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRunSQLConsoleScriptError(t *testing.T) {
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	runner := localSQL{conn: conn}
+	defer runner.Close()
+
+	var out bytes.Buffer
+	next := scriptLines("SELECT * FROM missing;", "SELECT 1;")
+	if err := runSQLConsole(runner, next, &out, false); err == nil {
+		t.Error("runSQLConsole() of a failing script succeeded")
+	}
+	if out.Len() != 0 {
+		t.Errorf("runSQLConsole() went on after the error: %q", out.String())
+	}
+}
+
+// scriptLines returns a reader of the console returning lines one by one.
+func scriptLines(lines ...string) func(string) (string, error) {
+	return func(string) (string, error) {
+		if len(lines) == 0 {
+			return "", io.EOF
+		}
+		line := lines[0]
+		lines = lines[1:]
+		return line, nil
+	}
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/shell"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var standupCmd = &cobra.Command{
+	Use:   "standup",
+	Short: "Summarize your recent work for a standup",
+	Long: `Summarize what you worked on for a standup or status update. Aura collects
+your commits in the repositories of the directories you visited and those
+configured as standup.repos, the directories you worked in, and the commands
+you ran from shell histories recording their time, and the AI assistant
+turns them into a few bullets.
+
+Activity is collected since the start of the previous workday, or since
+--since: a weekday such as monday, yesterday, today, a date such as
+2024-05-01 or an age such as 3d or 12h. Likely secrets in commands are
+masked before they are sent; --raw only shows the activity.
+
+Examples:
+  aura standup
+  aura standup --since monday
+  aura standup --since 3d --raw`,
+	Args: cobra.NoArgs,
+	RunE: runStandup,
+}
+
+var (
+	standupSince string
+	standupRaw   bool
+)
+
+// Limits of the activity summarized, keeping the most relevant.
+const (
+	standupMaxCommits     = 40
+	standupMaxDirectories = 15
+	standupMaxCommands    = 60
+)
+
+// standupTrivial are commands that say nothing about the work done.
+var standupTrivial = map[string]bool{
+	"ls": true, "ll": true, "la": true, "l": true, "cd": true, "pwd": true, "clear": true, "cls": true,
+	"exit": true, "history": true, "z": true, "..": true,
+}
+
+// standupRepo is a repository with the commits of the user.
+type standupRepo struct {
+	Path    string
+	Commits []string
+}
+
+// standupActivity is the work found since a time.
+type standupActivity struct {
+	Since       time.Time
+	Repos       []standupRepo
+	Directories []db.DirectoryVisits
+	Commands    []string
+}
+
+func runStandup(cmd *cobra.Command, args []string) error {
+	since, err := standupStart(standupSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+	dirs, err := database.VisitedDirectories(since)
+	if err != nil {
+		return err
+	}
+
+	activity := standupActivity{Since: since, Directories: dirs}
+	cwd, _ := os.Getwd()
+	for _, repo := range standupRepos(config.GetStandupRepos(), dirs, cwd) {
+		if commits := standupCommits(repo, since); len(commits) > 0 {
+			activity.Repos = append(activity.Repos, standupRepo{Path: repo, Commits: commits})
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		activity.Commands = standupCommands(shell.History(home, since))
+	}
+
+	if activity.empty() {
+		fmt.Printf("ℹ️  No commits, visited directories or commands since %s\n", since.Format("Mon 2006-01-02 15:04"))
+		return nil
+	}
+	if standupRaw {
+		activity.write(os.Stdout)
+		return nil
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	activity.write(&b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	stopThinking := startThinking()
+	summary, err := client.Standup(ctx, b.String())
+	stopThinking()
+	if err != nil {
+		return fmt.Errorf("failed to summarize the activity: %w", err)
+	}
+	fmt.Println(theme.Paint(theme.Heading, "Since "+since.Format("Mon 2006-01-02")))
+	fmt.Println(strings.TrimSpace(summary))
+	return nil
+}
+
+// standupStart returns the time value names relative to now: a weekday,
+// today, yesterday, a date or an age. Without a value it is the start of
+// the previous workday, Friday after a weekend.
+func standupStart(value string, now time.Time) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "":
+		start := midnight.AddDate(0, 0, -1)
+		for start.Weekday() == time.Saturday || start.Weekday() == time.Sunday {
+			start = start.AddDate(0, 0, -1)
+		}
+		return start, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			// The last such day before today, a week ago on the day itself.
+			days := (int(now.Weekday()) - int(day) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return midnight.AddDate(0, 0, -days), nil
+		}
+	}
+	if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return date, nil
+	}
+	age, err := parseAge(value)
+	if err != nil {
+		return time.Time{}, failure.New(failure.UserInput, "invalid --since '%s', use a weekday, yesterday, a date such as 2024-05-01 or an age such as 3d", value)
+	}
+	return now.Add(-age), nil
+}
+
+// standupRepos returns the Git repositories of the configured repos, the
+// visited directories and the current directory, each once.
+func standupRepos(configured []string, dirs []db.DirectoryVisits, cwd string) []string {
+	candidates := append([]string{}, configured...)
+	for _, dir := range dirs {
+		candidates = append(candidates, dir.Path)
+	}
+	candidates = append(candidates, cwd)
+
+	seen := make(map[string]bool)
+	var repos []string
+	for _, dir := range candidates {
+		root := repoRoot(dir)
+		if root == "" || seen[root] {
+			continue
+		}
+		seen[root] = true
+		repos = append(repos, root)
+	}
+	return repos
+}
+
+// repoRoot returns the root of the Git repository dir is in, or "" if it
+// is in none.
+func repoRoot(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for dir = filepath.Clean(dir); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
+
+// standupCommits returns the commits of the user on any branch of repo
+// since since, oldest first, as "2024-05-01 subject".
+func standupCommits(repo string, since time.Time) []string {
+	args := []string{"-C", repo, "log", "--all", "--no-merges", "--reverse",
+		"--since=" + since.Format(time.RFC3339), "--date=short", "--format=%ad %s"}
+	author, _ := exec.Command("git", "-C", repo, "config", "user.email").Output()
+	if email := strings.TrimSpace(string(author)); email != "" {
+		args = append(args, "--author="+email)
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil
+	}
+	var commits []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			commits = append(commits, line)
+		}
+	}
+	if len(commits) > standupMaxCommits {
+		commits = commits[len(commits)-standupMaxCommits:]
+	}
+	return commits
+}
+
+// standupCommands returns the distinct commands of history worth
+// mentioning, with how often they ran, and likely secrets masked.
+func standupCommands(history []shell.Command) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, command := range history {
+		line := strings.Join(strings.Fields(command.Line), " ")
+		fields := strings.Fields(line)
+		if len(fields) == 0 || standupTrivial[fields[0]] || (fields[0] == "aura" && len(fields) > 1 && fields[1] == "go") {
+			continue
+		}
+		if counts[line] == 0 {
+			order = append(order, line)
+		}
+		counts[line]++
+	}
+	if len(order) > standupMaxCommands {
+		order = order[len(order)-standupMaxCommands:]
+	}
+
+	commands := make([]string, len(order))
+	for i, line := range order {
+		if counts[line] > 1 {
+			line = fmt.Sprintf("%s (%d×)", line, counts[line])
+		}
+		commands[i] = line
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	return strings.Split(scrubSecrets("the shell history", strings.Join(commands, "\n")), "\n")
+}
+
+func (a standupActivity) empty() bool {
+	return len(a.Repos) == 0 && len(a.Directories) == 0 && len(a.Commands) == 0
+}
+
+// write writes the activity as the text summarized.
+func (a standupActivity) write(w io.Writer) {
+	fmt.Fprintf(w, "Activity since %s\n", a.Since.Format("Monday 2006-01-02 15:04"))
+	if len(a.Repos) > 0 {
+		fmt.Fprintln(w, "\nCommits:")
+		for _, repo := range a.Repos {
+			fmt.Fprintf(w, "  %s (%s)\n", filepath.Base(repo.Path), displayHomePath(repo.Path))
+			for _, commit := range repo.Commits {
+				fmt.Fprintf(w, "    %s\n", commit)
+			}
+		}
+	}
+	if len(a.Directories) > 0 {
+		fmt.Fprintln(w, "\nDirectories worked in:")
+		for i, dir := range a.Directories {
+			if i == standupMaxDirectories {
+				break
+			}
+			fmt.Fprintf(w, "  %s (%d %s)\n", displayHomePath(dir.Path), dir.Visits, plural(int64(dir.Visits), "visit", "visits"))
+		}
+	}
+	if len(a.Commands) > 0 {
+		fmt.Fprintln(w, "\nCommands run:")
+		for _, command := range a.Commands {
+			fmt.Fprintf(w, "  %s\n", command)
+		}
+	}
+}
+
+func init() {
+	standupCmd.Flags().StringVar(&standupSince, "since", "", "Start of the activity: a weekday, yesterday, a date or an age such as 3d (default: the previous workday)")
+	standupCmd.Flags().BoolVar(&standupRaw, "raw", false, "Show the activity without summarizing it")
+	rootCmd.AddCommand(standupCmd)
+}
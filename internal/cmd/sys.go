@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/sysinfo"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var sysCmd = &cobra.Command{
+	Use:   "sys",
+	Short: "Show CPU, memory, disk and battery use and the busiest processes",
+	Long: `Show a compact overview of the system: CPU use and load, memory and swap,
+the disks of the root, home and working directories, the battery and the
+processes using the most CPU.
+
+CPU use is measured over half a second, or between refreshes with --watch.
+
+Examples:
+  aura sys
+  aura sys --watch              # Refresh every 2 seconds until Ctrl+C
+  aura sys -w --interval 5s --top 10`,
+	Args: cobra.NoArgs,
+	RunE: runSys,
+}
+
+var (
+	sysWatch    bool
+	sysInterval time.Duration
+	sysTop      int
+)
+
+// sysTimeout limits how long taking one snapshot may take.
+const sysTimeout = 10 * time.Second
+
+// sysBarWidth is the width of the usage bars in cells.
+const sysBarWidth = 20
+
+func runSys(cmd *cobra.Command, args []string) error {
+	if sysInterval < time.Second/2 {
+		return failure.New(failure.UserInput, "the interval must be at least 500ms")
+	}
+	if sysTop < 0 {
+		return failure.New(failure.UserInput, "--top cannot be negative")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	sampler := sysinfo.NewSampler(platform.Current(), sysTop)
+	interactive := term.IsTerminal(int(os.Stdout.Fd()))
+	for {
+		snapshot, err := takeSnapshot(ctx, sampler)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if sysWatch && interactive {
+			// Redraw in place instead of scrolling
+			fmt.Print("\033[H\033[2J")
+		}
+		printSnapshot(os.Stdout, snapshot, progress.UnicodeSupported())
+		if !sysWatch {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(sysInterval):
+		}
+		if !interactive {
+			fmt.Println()
+		}
+	}
+}
+
+func takeSnapshot(ctx context.Context, sampler *sysinfo.Sampler) (*sysinfo.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, sysTimeout)
+	defer cancel()
+	snapshot, err := sampler.Take(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the system state: %w", err)
+	}
+	return snapshot, nil
+}
+
+// printSnapshot prints snapshot with a usage bar per resource.
+func printSnapshot(w io.Writer, snapshot *sysinfo.Snapshot, unicode bool) {
+	cpu := snapshot.CPU
+	details := fmt.Sprintf("%d %s", cpu.Cores, plural(int64(cpu.Cores), "core", "cores"))
+	if cpu.Load != [3]float64{} {
+		details += fmt.Sprintf("  load %.2f %.2f %.2f", cpu.Load[0], cpu.Load[1], cpu.Load[2])
+	}
+	printUsage(w, "CPU", cpu.Usage, details, unicode)
+
+	memory := snapshot.Memory
+	details = fmt.Sprintf("%s / %s", formatSize(int64(memory.Used)), formatSize(int64(memory.Total)))
+	if memory.SwapTotal > 0 {
+		details += fmt.Sprintf("  swap %s / %s", formatSize(int64(memory.SwapUsed)), formatSize(int64(memory.SwapTotal)))
+	}
+	printUsage(w, "Memory", sysinfo.Percent(memory.Used, memory.Total), details, unicode)
+
+	for _, disk := range snapshot.Disks {
+		details = fmt.Sprintf("%s / %s  %s", formatSize(int64(disk.Used)), formatSize(int64(disk.Total)), disk.Path)
+		printUsage(w, "Disk", sysinfo.Percent(disk.Used, disk.Total), details, unicode)
+	}
+
+	if battery := snapshot.Battery; battery != nil {
+		// An emptying battery is the concern, not a full one.
+		empty := 100 - float64(battery.Percent)
+		if battery.Status != "discharging" {
+			empty = 0
+		}
+		bar := progress.Bar(battery.Percent, 100, sysBarWidth, unicode)
+		fmt.Fprintf(w, "%-8s%s %3d%%  %s\n", "Battery", theme.Paint(usageRole(empty), bar), battery.Percent, battery.Status)
+	}
+
+	if len(snapshot.Processes) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s\n", theme.Paint(theme.Heading, fmt.Sprintf("%-7s  %6s  %10s  %s", "PID", "CPU", "MEM", "PROCESS")))
+	for _, process := range snapshot.Processes {
+		fmt.Fprintf(w, "%-7d  %5.1f%%  %10s  %s\n", process.PID, process.CPU, formatSize(int64(process.Memory)), process.Name)
+	}
+}
+
+// printUsage prints a line with a bar of percent, colored by how full it is.
+func printUsage(w io.Writer, name string, percent float64, details string, unicode bool) {
+	bar := progress.Bar(int(percent+0.5), 100, sysBarWidth, unicode)
+	fmt.Fprintf(w, "%-8s%s %3.0f%%  %s\n", name, theme.Paint(usageRole(percent), bar), percent, details)
+}
+
+// usageRole returns the color of a usage of percent.
+func usageRole(percent float64) theme.Role {
+	switch {
+	case percent >= 90:
+		return theme.Error
+	case percent >= 70:
+		return theme.Warning
+	}
+	return theme.Success
+}
+
+func init() {
+	sysCmd.Flags().BoolVarP(&sysWatch, "watch", "w", false, "Refresh the view until interrupted")
+	sysCmd.Flags().DurationVar(&sysInterval, "interval", 2*time.Second, "Time between refreshes with --watch")
+	sysCmd.Flags().IntVar(&sysTop, "top", 5, "Number of processes to list")
+	rootCmd.AddCommand(sysCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/sysinfo"
+)
+
+func TestPrintSnapshot(t *testing.T) {
+	var out bytes.Buffer
+	printSnapshot(&out, &sysinfo.Snapshot{
+		CPU:     sysinfo.CPU{Cores: 8, Usage: 34, Load: [3]float64{1.2, 0.95, 0.8}},
+		Memory:  sysinfo.Memory{Total: 16 << 30, Used: 12 << 30, SwapTotal: 2 << 30, SwapUsed: 512 << 20},
+		Disks:   []sysinfo.Disk{{Path: "/", Total: 100 << 30, Used: 95 << 30}},
+		Battery: &sysinfo.Battery{Percent: 80, Status: "charging"},
+		Processes: []sysinfo.Process{
+			{PID: 4242, Name: "node", CPU: 87.5, Memory: 300 << 20},
+			{PID: 1, Name: "init", Memory: 8 << 20},
+		},
+	}, false)
+	want := `CPU     [######--------------]  34%  8 cores  load 1.20 0.95 0.80
+Memory  [###############-----]  75%  12.0 GiB / 16.0 GiB  swap 512.0 MiB / 2.0 GiB
+Disk    [###################-]  95%  95.0 GiB / 100.0 GiB  /
+Battery [################----]  80%  charging
+
+PID         CPU         MEM  PROCESS
+4242      87.5%   300.0 MiB  node
+1          0.0%     8.0 MiB  init
+`
+	if out.String() != want {
+		t.Errorf("printSnapshot() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printSnapshot(&out, &sysinfo.Snapshot{CPU: sysinfo.CPU{Cores: 1, Usage: 5}, Memory: sysinfo.Memory{Total: 1 << 30}}, false)
+	want = `CPU     [#-------------------]   5%  1 core
+Memory  [--------------------]   0%  0 B / 1.0 GiB
+`
+	if out.String() != want {
+		t.Errorf("printSnapshot() of a bare snapshot =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/shell"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+	"github.com/timfewi/aura-cli-go/internal/timetrack"
+)
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Estimate the time spent on projects",
+	Long:  `Estimate the time spent on projects from the directories you entered and the commands you ran.`,
+}
+
+var timeReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show the time spent per project",
+	Long: `Show the time spent per project, today by default. Time is estimated from
+the directories entered with 'aura go' and the commands of shell histories
+recording their time: zsh with EXTENDED_HISTORY, bash with HISTTIMEFORMAT
+and fish. Activity counts for the repository of the directory entered last,
+and gaps longer than --idle count as breaks.
+
+Examples:
+  aura time report
+  aura time report --week
+  aura time report --since 2024-05-01 --csv > hours.csv`,
+	Args: cobra.NoArgs,
+	RunE: runTimeReport,
+}
+
+var (
+	timeWeek  bool
+	timeSince string
+	timeCSV   bool
+	timeIdle  time.Duration
+)
+
+// timeLookback is how long before the report starts activity is read, to
+// know the directory worked in at its start.
+const timeLookback = 12 * time.Hour
+
+// timeProject is the time spent on a project, in total and per day.
+type timeProject struct {
+	Path  string
+	Total time.Duration
+	Days  map[time.Time]time.Duration
+}
+
+func runTimeReport(cmd *cobra.Command, args []string) error {
+	if timeWeek && timeSince != "" {
+		return failure.New(failure.UserInput, "use either --week or --since")
+	}
+	if timeIdle <= 0 {
+		return failure.New(failure.UserInput, "--idle must be positive")
+	}
+	now := time.Now()
+	value := timeSince
+	switch {
+	case timeWeek:
+		value = "monday"
+		if now.Weekday() == time.Monday {
+			value = "today"
+		}
+	case value == "":
+		value = "today"
+	}
+	from, err := standupStart(value, now)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+	visits, err := database.NavigationHistory(from.Add(-timeLookback))
+	if err != nil {
+		return err
+	}
+
+	var events []timetrack.Event
+	for _, visit := range visits {
+		events = append(events, timetrack.Event{Time: visit.Time, Dir: visit.Path})
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, command := range shell.History(home, from.Add(-timeLookback)) {
+			events = append(events, timetrack.Event{Time: command.Time})
+		}
+	}
+
+	entries := timetrack.ByDay(timetrack.Spans(events, timeIdle), from, now, timeProjectOf)
+	if len(entries) == 0 {
+		fmt.Printf("ℹ️  No time tracked since %s. Time is estimated from the directories entered with 'aura go' and timed shell histories.\n", from.Format("Mon 2006-01-02 15:04"))
+		return nil
+	}
+	if timeCSV {
+		return writeTimeCSV(os.Stdout, entries)
+	}
+	printTimeReport(os.Stdout, entries, from, now)
+	return nil
+}
+
+// timeProjectOf returns the project of dir: the root of its repository, or
+// dir itself outside of one.
+func timeProjectOf(dir string) string {
+	if root := repoRoot(dir); root != "" {
+		return root
+	}
+	return dir
+}
+
+// timeProjects sums entries per project, most time first.
+func timeProjects(entries []timetrack.Entry) []timeProject {
+	byPath := make(map[string]*timeProject)
+	var projects []*timeProject
+	for _, entry := range entries {
+		project := byPath[entry.Project]
+		if project == nil {
+			project = &timeProject{Path: entry.Project, Days: make(map[time.Time]time.Duration)}
+			byPath[entry.Project] = project
+			projects = append(projects, project)
+		}
+		project.Total += entry.Duration
+		project.Days[entry.Day] += entry.Duration
+	}
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].Total > projects[j].Total })
+
+	sorted := make([]timeProject, len(projects))
+	for i, project := range projects {
+		sorted[i] = *project
+	}
+	return sorted
+}
+
+// printTimeReport prints the time per project between from and to, per day
+// if they are up to a week apart.
+func printTimeReport(w io.Writer, entries []timetrack.Entry, from, to time.Time) {
+	projects := timeProjects(entries)
+
+	var days []time.Time
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location()); day.Before(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	if len(days) < 2 || len(days) > 7 {
+		days = nil
+	}
+
+	width := len("Total")
+	for _, project := range projects {
+		width = max(width, len(filepath.Base(project.Path)))
+	}
+	fmt.Fprintln(w, theme.Paint(theme.Heading, "Time spent since "+from.Format("Mon 2006-01-02 15:04")))
+	header := fmt.Sprintf("%-*s", width, "")
+	for _, day := range days {
+		header += fmt.Sprintf("  %6s", day.Format("Mon"))
+	}
+	fmt.Fprintln(w, theme.Paint(theme.Muted, header+fmt.Sprintf("  %7s", "Total")))
+
+	var total time.Duration
+	totals := make(map[time.Time]time.Duration)
+	for _, project := range projects {
+		line := fmt.Sprintf("%-*s", width, filepath.Base(project.Path))
+		for _, day := range days {
+			line += fmt.Sprintf("  %6s", timeCell(project.Days[day]))
+			totals[day] += project.Days[day]
+		}
+		fmt.Fprintf(w, "%s  %7s\n", line, timeCell(project.Total))
+		total += project.Total
+	}
+	if len(projects) > 1 {
+		line := fmt.Sprintf("%-*s", width, "Total")
+		for _, day := range days {
+			line += fmt.Sprintf("  %6s", timeCell(totals[day]))
+		}
+		fmt.Fprintln(w, theme.Paint(theme.Heading, fmt.Sprintf("%s  %7s", line, timeCell(total))))
+	}
+}
+
+// timeCell formats a duration of the report in minutes, "-" for none.
+func timeCell(d time.Duration) string {
+	if d < time.Minute {
+		return "-"
+	}
+	return shortDuration(d)
+}
+
+// writeTimeCSV writes entries as CSV with one row per day and project.
+func writeTimeCSV(w io.Writer, entries []timetrack.Entry) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "project", "path", "minutes"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.Day.Format("2006-01-02"),
+			filepath.Base(entry.Project),
+			entry.Project,
+			strconv.Itoa(int(entry.Duration.Round(time.Minute) / time.Minute)),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	timeReportCmd.Flags().BoolVar(&timeWeek, "week", false, "Report this week, per day")
+	timeReportCmd.Flags().StringVar(&timeSince, "since", "", "Start of the report: a weekday, yesterday, a date or an age such as 3d")
+	timeReportCmd.Flags().BoolVar(&timeCSV, "csv", false, "Write CSV with one row per day and project")
+	timeReportCmd.Flags().DurationVar(&timeIdle, "idle", 15*time.Minute, "Gaps between activity longer than this are breaks")
+
+	timeCmd.AddCommand(timeReportCmd)
+	rootCmd.AddCommand(timeCmd)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/timetrack"
+)
+
+func TestTimeReport(t *testing.T) {
+	monday := time.Date(2024, 5, 13, 0, 0, 0, 0, time.Local)
+	entries := []timetrack.Entry{
+		{Day: monday, Project: "/code/api", Duration: 90 * time.Minute},
+		{Day: monday, Project: "/code/web", Duration: 20 * time.Minute},
+		{Day: monday.AddDate(0, 0, 1), Project: "/code/web", Duration: 2 * time.Hour},
+	}
+
+	var b strings.Builder
+	printTimeReport(&b, entries, monday, monday.AddDate(0, 0, 2).Add(9*time.Hour))
+	want := `Time spent since Mon 2024-05-13 00:00
+          Mon     Tue     Wed    Total
+web       20m      2h       -    2h20m
+api     1h30m       -       -    1h30m
+Total   1h50m      2h       -    3h50m
+`
+	if got := b.String(); got != want {
+		t.Errorf("printTimeReport() =\n%s\nwant\n%s", got, want)
+	}
+
+	b.Reset()
+	if err := writeTimeCSV(&b, entries[:2]); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "date,project,path,minutes\n2024-05-13,api,/code/api,90\n2024-05-13,web,/code/web,20\n"
+	if got := b.String(); got != wantCSV {
+		t.Errorf("writeTimeCSV() = %q, want %q", got, wantCSV)
+	}
+}
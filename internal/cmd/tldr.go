@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/tldr"
+)
+
+var tldrCmd = &cobra.Command{
+	Use:     "tldr <command>",
+	Aliases: []string{"man"},
+	Short:   "Show concise usage examples of a command",
+	Long: `Show the most common usage examples of a command, from the tldr-pages
+project. Pages are cached, so they are available offline after the first
+lookup. For commands without a tldr page, the AI assistant writes one, which
+is cached as well.
+
+Examples:
+  aura tldr tar              # Examples of tar
+  aura man rsync             # Same, 'man' is an alias
+  aura tldr tar --update     # Download the page again
+  aura tldr mytool --no-ai   # Don't generate missing pages`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTLDR,
+}
+
+var (
+	tldrUpdate bool
+	tldrNoAI   bool
+)
+
+// tldrAISource is the cache directory of pages written by the AI assistant.
+const tldrAISource = "ai"
+
+func runTLDR(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(args[0])
+	if !tldr.ValidName(name) {
+		return failure.New(failure.UserInput, "invalid command name '%s'", args[0])
+	}
+
+	cache := tldr.Cache{Dir: filepath.Join(config.CacheDir, "tldr")}
+	platforms := tldr.Platforms()
+	sources := append(append([]string{}, platforms...), tldrAISource)
+
+	content, source, err := cache.Get(name, sources)
+	if err != nil || tldrUpdate {
+		content, source, err = downloadTLDRPage(cache, name, platforms)
+		if err != nil && !errors.Is(err, tldr.ErrNotFound) {
+			// Offline: fall back to the cached page, or a page written by a
+			// possibly local AI model
+			if cached, cachedSource, cacheErr := cache.Get(name, sources); cacheErr == nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v, showing the cached page\n", err)
+				content, source, err = cached, cachedSource, nil
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		if err != nil {
+			if content, source, err = generateTLDRPage(cache, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	page, err := tldr.Parse(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to read the page of '%s': %w", name, err)
+	}
+
+	tldr.Render(os.Stdout, page)
+	if source == tldrAISource {
+		fmt.Println("ℹ️  Written by the AI assistant, there is no tldr page for this command.")
+	}
+	return nil
+}
+
+// downloadTLDRPage fetches the page of name and caches it.
+func downloadTLDRPage(cache tldr.Cache, name string, platforms []string) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	content, platform, err := tldr.NewFetcher().Fetch(ctx, name, platforms)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := cache.Put(name, platform, content); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache page: %v\n", err)
+	}
+	return content, platform, nil
+}
+
+// generateTLDRPage asks the AI assistant to write the page of name and
+// caches it if it is a valid page.
+func generateTLDRPage(cache tldr.Cache, name string) ([]byte, string, error) {
+	if tldrNoAI {
+		return nil, "", failure.New(failure.NotFound, "no tldr page for '%s'", name)
+	}
+
+	client, err := newAssistant()
+	if err != nil {
+		return nil, "", failure.New(failure.NotFound, "no tldr page for '%s' and the AI client is unavailable: %w", name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stopThinking := startThinking()
+
+	response, err := client.TLDRPage(ctx, name)
+	stopThinking()
+
+	if err != nil {
+		return nil, "", fmt.Errorf("AI request failed: %w", err)
+	}
+
+	content := []byte(strings.TrimSpace(response) + "\n")
+	if _, err := tldr.Parse(bytes.NewReader(content)); err != nil {
+		return nil, "", fmt.Errorf("the AI assistant did not write a valid page: %w", err)
+	}
+	if err := cache.Put(name, tldrAISource, content); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache page: %v\n", err)
+	}
+	printFallbackNote(client)
+	return content, tldrAISource, nil
+}
+
+func init() {
+	tldrCmd.Flags().BoolVar(&tldrUpdate, "update", false, "Download the page again instead of using the cache")
+	tldrCmd.Flags().BoolVar(&tldrNoAI, "no-ai", false, "Don't let the AI assistant write missing pages")
+	rootCmd.AddCommand(tldrCmd)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/remote"
+)
+
+var tmuxCmd = &cobra.Command{
+	Use:   "tmux [alias]",
+	Short: "Open a tmux session for a bookmark",
+	Long: `Create or attach a tmux session named after a bookmark, started in the
+bookmarked directory. New sessions run the bookmark's startup command, if any.
+
+Examples:
+  aura tmux api                          # Create or attach the session 'api'
+  aura bookmark set api tmux "make dev"  # Run 'make dev' in new 'api' sessions
+  aura tmux api --detach                 # Create the session in the background`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTmux,
+}
+
+var tmuxDetach bool
+
+func runTmux(cmd *cobra.Command, args []string) error {
+	alias := args[0]
+
+	tmux, err := exec.LookPath("tmux")
+	if err != nil {
+		return fmt.Errorf("tmux is not installed")
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	bookmark, err := database.GetBookmark(alias)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if bookmark == nil {
+		return fmt.Errorf("bookmark '%s' not found", alias)
+	}
+	if location, ok := remote.Parse(bookmark.Path); ok {
+		path, mounted := location.MountedPath(config.UserSettings.Navigation.Mounts)
+		if !mounted {
+			return fmt.Errorf("bookmark '%s' is cloud storage, tmux sessions need it mounted, see 'aura go --help'", alias)
+		}
+		bookmark.Path = path
+	}
+	if _, err := os.Stat(bookmark.Path); os.IsNotExist(err) {
+		return fmt.Errorf("bookmarked path '%s' no longer exists", bookmark.Path)
+	}
+	recordBookmarkUse(database, alias)
+
+	metadata, err := database.GetBookmarkMetadata(alias)
+	if err != nil {
+		return fmt.Errorf("failed to get bookmark metadata: %w", err)
+	}
+
+	session := tmuxSessionName(alias)
+	exists := exec.Command(tmux, "has-session", "-t", "="+session).Run() == nil
+	insideTmux := os.Getenv("TMUX") != ""
+	startup := metadata["tmux"]
+	if startup != "" && !exists && !allowShellCommand(startup, bookmark.Path, "the startup command of the session") {
+		startup = ""
+	}
+
+	for _, tmuxArgs := range tmuxCommands(session, bookmark.Path, startup, exists, insideTmux, tmuxDetach) {
+		run := exec.Command(tmux, tmuxArgs...)
+		run.Stdin = os.Stdin
+		run.Stdout = os.Stdout
+		run.Stderr = os.Stderr
+		if err := run.Run(); err != nil {
+			return fmt.Errorf("tmux %s failed: %w", tmuxArgs[0], err)
+		}
+	}
+
+	if tmuxDetach {
+		fmt.Printf("✓ Session '%s' is running in %s\n", session, bookmark.Path)
+	}
+	return nil
+}
+
+// tmuxSessionName derives a valid tmux session name from a bookmark alias.
+// tmux does not allow '.' and ':' in session names.
+func tmuxSessionName(alias string) string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(alias)
+}
+
+// tmuxCommands returns the tmux invocations that create the session if needed
+// and attach to it, or switch to it when already running inside tmux.
+func tmuxCommands(session, path, startup string, exists, insideTmux, detach bool) [][]string {
+	var commands [][]string
+	target := "=" + session
+
+	if !exists {
+		commands = append(commands, []string{"new-session", "-d", "-s", session, "-c", path})
+		if startup != "" {
+			commands = append(commands, []string{"send-keys", "-t", target + ":", startup, "Enter"})
+		}
+	}
+
+	switch {
+	case detach:
+	case insideTmux:
+		commands = append(commands, []string{"switch-client", "-t", target})
+	default:
+		commands = append(commands, []string{"attach-session", "-t", target})
+	}
+	return commands
+}
+
+func init() {
+	tmuxCmd.Flags().BoolVarP(&tmuxDetach, "detach", "d", false, "Create the session without attaching to it")
+
+	rootCmd.AddCommand(tmuxCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTmuxSessionName(t *testing.T) {
+	if got := tmuxSessionName("api.v2:dev"); got != "api_v2_dev" {
+		t.Errorf("tmuxSessionName() = %q, want %q", got, "api_v2_dev")
+	}
+}
+
+func TestTmuxCommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		exists     bool
+		insideTmux bool
+		detach     bool
+		startup    string
+		want       [][]string
+	}{
+		{
+			name:    "new session with startup command",
+			startup: "make dev",
+			want: [][]string{
+				{"new-session", "-d", "-s", "api", "-c", "/src/api"},
+				{"send-keys", "-t", "=api:", "make dev", "Enter"},
+				{"attach-session", "-t", "=api"},
+			},
+		},
+		{
+			name:    "existing session skips startup command",
+			exists:  true,
+			startup: "make dev",
+			want:    [][]string{{"attach-session", "-t", "=api"}},
+		},
+		{
+			name:       "inside tmux switches client",
+			exists:     true,
+			insideTmux: true,
+			want:       [][]string{{"switch-client", "-t", "=api"}},
+		},
+		{
+			name:   "detached",
+			detach: true,
+			want:   [][]string{{"new-session", "-d", "-s", "api", "-c", "/src/api"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tmuxCommands("api", "/src/api", tt.startup, tt.exists, tt.insideTmux, tt.detach)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tmuxCommands() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
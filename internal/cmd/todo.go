@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+)
+
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "Track TODO, FIXME and HACK comments",
+	Long:  `Track the TODO, FIXME and HACK comments in the source code of the project.`,
+}
+
+var todoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List TODO, FIXME and HACK comments",
+	Long: `List the TODO, FIXME and HACK comments in the source files of the current
+directory, grouped by file or, in a Git repository, by the author of the line
+according to git blame. Files ignored by .gitignore are skipped.
+
+With --export each comment is written as a draft of a GitHub issue, a
+markdown file with the title and labels in its front matter. Create an issue
+from one with 'gh issue create --title <title> --body-file <file>'.
+
+Examples:
+  aura todo list
+  aura todo list --by author
+  aura todo list --kind FIXME,HACK
+  aura todo list --export issue-drafts`,
+	Args: cobra.NoArgs,
+	RunE: runTodoList,
+}
+
+var (
+	todoBy     string
+	todoKinds  []string
+	todoExport string
+)
+
+func runTodoList(cmd *cobra.Command, args []string) error {
+	if todoBy != "file" && todoBy != "author" {
+		return failure.New(failure.UserInput, "unknown grouping '%s', use file or author", todoBy)
+	}
+	kinds := map[string]bool{}
+	for _, kind := range todoKinds {
+		kind = strings.ToUpper(strings.TrimSpace(kind))
+		if !isAnnotationKind(kind) {
+			return failure.New(failure.UserInput, "unknown kind '%s', use %s", kind, strings.Join(projectinfo.AnnotationKinds, ", "))
+		}
+		kinds[kind] = true
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	files, err := projectinfo.ListFiles(root)
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var annotations []projectinfo.Annotation
+	for _, annotation := range projectinfo.ScanAnnotations(root, files) {
+		if len(kinds) == 0 || kinds[annotation.Kind] {
+			annotations = append(annotations, annotation)
+		}
+	}
+	if len(annotations) == 0 {
+		fmt.Println("✓ No TODO, FIXME or HACK comments found")
+		return nil
+	}
+
+	if isGitRepository() {
+		projectinfo.Blame(root, annotations)
+	} else if todoBy == "author" {
+		return failure.New(failure.UserInput, "grouping by author needs a git repository")
+	}
+
+	if todoExport != "" {
+		written, err := exportIssueDrafts(todoExport, root, annotations)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Wrote %d issue drafts to %s\n", written, todoExport)
+		return nil
+	}
+
+	printAnnotations(os.Stdout, annotations, todoBy)
+	return nil
+}
+
+// isAnnotationKind reports whether kind is one of the annotation kinds.
+func isAnnotationKind(kind string) bool {
+	for _, known := range projectinfo.AnnotationKinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
+
+// printAnnotations prints annotations grouped by "file" or "author", groups
+// with the most annotations first.
+func printAnnotations(w io.Writer, annotations []projectinfo.Annotation, by string) {
+	groups := map[string][]projectinfo.Annotation{}
+	for _, annotation := range annotations {
+		key := annotation.File
+		if by == "author" {
+			key = annotation.Author
+			if key == "" {
+				key = "Not committed yet"
+			}
+		}
+		groups[key] = append(groups[key], annotation)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(groups[keys[i]]) != len(groups[keys[j]]) {
+			return len(groups[keys[i]]) > len(groups[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+
+	for i, key := range keys {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d)\n", key, len(groups[key]))
+		for _, annotation := range groups[key] {
+			location := fmt.Sprintf("%5d", annotation.Line)
+			suffix := ""
+			if by == "author" {
+				location = fmt.Sprintf("%s:%d", annotation.File, annotation.Line)
+			} else if annotation.Author != "" {
+				suffix = " — " + annotation.Author
+			}
+			fmt.Fprintf(w, "  %s  %-5s %s%s\n", location, annotation.Kind, annotation.Text, suffix)
+		}
+	}
+
+	counts := map[string]int{}
+	for _, annotation := range annotations {
+		counts[annotation.Kind]++
+	}
+	var totals []string
+	for _, kind := range projectinfo.AnnotationKinds {
+		if counts[kind] > 0 {
+			totals = append(totals, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	groupNoun := "files"
+	if by == "author" {
+		groupNoun = "authors"
+	}
+	fmt.Fprintf(w, "\n%s in %d %s\n", strings.Join(totals, ", "), len(keys), groupNoun)
+}
+
+// slugPattern matches the characters replaced in the file names of drafts.
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// issueDraftName returns the file name of the draft number n for annotation.
+func issueDraftName(n int, annotation projectinfo.Annotation) string {
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(annotation.Text), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	if slug == "" {
+		slug = strings.ToLower(annotation.Kind)
+	}
+	return fmt.Sprintf("%03d-%s.md", n, slug)
+}
+
+// issueLabels are the labels of issue drafts by annotation kind.
+var issueLabels = map[string]string{"TODO": "enhancement", "FIXME": "bug", "HACK": "tech-debt"}
+
+// issueDraft returns a GitHub issue draft for annotation, with context the
+// lines around it.
+func issueDraft(annotation projectinfo.Annotation, context string) string {
+	title := annotation.Text
+	if title == "" {
+		title = fmt.Sprintf("%s in %s", annotation.Kind, filepath.Base(annotation.File))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: %q\nlabels: [%s]\n---\n\n", title, issueLabels[annotation.Kind])
+	fmt.Fprintf(&b, "`%s` comment in `%s` line %d", annotation.Kind, annotation.File, annotation.Line)
+	if annotation.Author != "" {
+		fmt.Fprintf(&b, ", written by %s", annotation.Author)
+	}
+	fmt.Fprintf(&b, ":\n\n> %s\n", title)
+	if context != "" {
+		fmt.Fprintf(&b, "\n```%s\n%s\n```\n", strings.TrimPrefix(filepath.Ext(annotation.File), "."), context)
+	}
+	return b.String()
+}
+
+// issueContextLines is the number of lines around an annotation shown in
+// its issue draft.
+const issueContextLines = 3
+
+// annotationContext returns the lines around annotation in its file.
+func annotationContext(root string, annotation projectinfo.Annotation) string {
+	content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(annotation.File)))
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(content), "\n")
+	start := max(annotation.Line-1-issueContextLines, 0)
+	end := min(annotation.Line+issueContextLines, len(lines))
+	if start >= end {
+		return ""
+	}
+	return strings.TrimRight(strings.Join(lines[start:end], "\n"), "\n")
+}
+
+// exportIssueDrafts writes a GitHub issue draft for each annotation into dir
+// and returns how many it wrote.
+func exportIssueDrafts(dir, root string, annotations []projectinfo.Annotation) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for i, annotation := range annotations {
+		path := filepath.Join(dir, issueDraftName(i+1, annotation))
+		draft := issueDraft(annotation, annotationContext(root, annotation))
+		if err := os.WriteFile(path, []byte(draft), 0o644); err != nil {
+			return i, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return len(annotations), nil
+}
+
+func init() {
+	todoListCmd.Flags().StringVar(&todoBy, "by", "file", "Group by file or author")
+	todoListCmd.Flags().StringSliceVar(&todoKinds, "kind", nil, "Only list these kinds, such as FIXME,HACK")
+	todoListCmd.Flags().StringVar(&todoExport, "export", "", "Write GitHub issue drafts into this directory")
+
+	todoCmd.AddCommand(todoListCmd)
+	rootCmd.AddCommand(todoCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+)
+
+var testAnnotations = []projectinfo.Annotation{
+	{File: "main.go", Line: 3, Kind: "TODO", Text: "parse flags", Author: "Ana"},
+	{File: "db/db.go", Line: 10, Kind: "FIXME", Text: "racy", Author: "Ben"},
+	{File: "db/db.go", Line: 42, Kind: "HACK", Text: "skip migrations", Author: "Ana"},
+}
+
+func TestPrintAnnotations(t *testing.T) {
+	var out bytes.Buffer
+	printAnnotations(&out, testAnnotations, "file")
+	want := "db/db.go (2)\n" +
+		"     10  FIXME racy — Ben\n" +
+		"     42  HACK  skip migrations — Ana\n" +
+		"\n" +
+		"main.go (1)\n" +
+		"      3  TODO  parse flags — Ana\n" +
+		"\n" +
+		"1 TODO, 1 FIXME, 1 HACK in 2 files\n"
+	if out.String() != want {
+		t.Errorf("printAnnotations(file) =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printAnnotations(&out, testAnnotations, "author")
+	if !strings.HasPrefix(out.String(), "Ana (2)\n  main.go:3  TODO  parse flags\n  db/db.go:42  HACK  skip migrations\n\nBen (1)\n") {
+		t.Errorf("printAnnotations(author) =\n%s", out.String())
+	}
+}
+
+func TestIssueDraft(t *testing.T) {
+	if got := issueDraftName(7, testAnnotations[0]); got != "007-parse-flags.md" {
+		t.Errorf("issueDraftName() = %q", got)
+	}
+	if got := issueDraftName(1, projectinfo.Annotation{Kind: "HACK"}); got != "001-hack.md" {
+		t.Errorf("issueDraftName() without text = %q", got)
+	}
+
+	draft := issueDraft(testAnnotations[1], "mu.Lock()\n// FIXME: racy")
+	for _, want := range []string{
+		"---\ntitle: \"racy\"\nlabels: [bug]\n---\n",
+		"`FIXME` comment in `db/db.go` line 10, written by Ben",
+		"```go\nmu.Lock()\n// FIXME: racy\n```",
+	} {
+		if !strings.Contains(draft, want) {
+			t.Errorf("issueDraft() lacks %q:\n%s", want, draft)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/ai"
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/progress"
+	"github.com/timfewi/aura-cli-go/internal/shell"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var tourCmd = &cobra.Command{
+	Use:   "tour",
+	Short: "Take a guided tour that sets up Aura",
+	Long: `Walk through setting up Aura step by step: the API key of the AI
+assistant, a first bookmark, 'aura do' in a sample project and the shell
+integration. Each step is checked after it ran, and steps that are already
+done are skipped, so the tour can be taken again to finish the setup.
+
+Examples:
+  aura tour`,
+	Args: cobra.NoArgs,
+	RunE: runTour,
+}
+
+// tourStep is a step of the tour.
+type tourStep struct {
+	name string
+	// done reports whether the user already took the step.
+	done func() bool
+	// run takes the step and returns an error if it didn't work.
+	run func() error
+}
+
+// sampleMakefile is the project 'aura do' is tried in.
+const sampleMakefile = `.PHONY: all help test
+
+all:
+	@echo "Built the sample project"
+
+help:
+	@echo "Targets: all, help, test"
+
+test:
+	@echo "All tests of the sample project passed"
+`
+
+func runTour(cmd *cobra.Command, args []string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("the tour is interactive and needs a terminal")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	shellName := shell.Detect()
+	rcFile, rcErr := shell.RCFile(shellName, home)
+
+	steps := []tourStep{
+		{name: "Connect the AI assistant", done: aiKeyWorks, run: func() error { return tourAPIKey(shellName, rcFile, rcErr) }},
+		{name: "Bookmark a directory", done: hasBookmarks, run: tourBookmark},
+		{name: "Try 'aura do' in a sample project", done: func() bool { return false }, run: tourDo},
+		{
+			name: "Install the shell integration",
+			done: func() bool {
+				return rcErr == nil && shell.Installed(shellName, rcFile) && shell.Verify(shellName) == nil
+			},
+			run: func() error { return tourShell(shellName, rcFile, rcErr) },
+		},
+	}
+
+	fmt.Println("👋 Welcome to Aura! This tour sets it up in a few steps.")
+	progressSteps := progress.NewSteps(len(steps))
+	completed := 0
+	for _, step := range steps {
+		fmt.Println()
+		progressSteps.Next(step.name)
+		if step.done() {
+			fmt.Println(theme.Paint(theme.Success, "✓ Already done"))
+			completed++
+			continue
+		}
+
+		for {
+			err := step.run()
+			if err == nil {
+				fmt.Println(theme.Paint(theme.Success, "✓ Done"))
+				completed++
+				break
+			}
+			if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, errTourSkipped) {
+				fmt.Println(theme.Paint(theme.Muted, "Skipped, run 'aura tour' again to finish it"))
+				break
+			}
+			fmt.Println(theme.Paint(theme.Error, "✗ "+err.Error()))
+			if !confirmChange("Try again", false) {
+				break
+			}
+		}
+	}
+
+	fmt.Printf("\n🎉 %d of %d steps done.", completed, len(steps))
+	if completed < len(steps) {
+		fmt.Print(" Run 'aura tour' again to finish the others.")
+	}
+	fmt.Println()
+	return nil
+}
+
+// errTourSkipped is returned by steps the user chose to skip.
+var errTourSkipped = errors.New("skipped")
+
+// aiKeyWorks reports whether an API key is set and the provider accepts it.
+func aiKeyWorks() bool {
+	return verifyAIKey() == nil
+}
+
+// verifyAIKey lists the models of the provider, which fails for invalid keys.
+func verifyAIKey() error {
+	client, err := ai.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if _, err := client.ListModels(ctx); err != nil {
+		return fmt.Errorf("the API key doesn't work: %w", err)
+	}
+	return nil
+}
+
+// tourAPIKey asks for an API key, checks it and offers to save it in the
+// startup file of the shell.
+func tourAPIKey(shellName, rcFile string, rcErr error) error {
+	fmt.Println("Aura's AI features use an OpenAI compatible API. Set AURA_API_URL for")
+	fmt.Println("other providers. Leave the key empty to skip this step.")
+
+	prompt := promptui.Prompt{Label: "API key", Mask: '*'}
+	key, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errTourSkipped
+	}
+
+	os.Setenv(envvar.APIKey.Name, key)
+	if err := verifyAIKey(); err != nil {
+		os.Unsetenv(envvar.APIKey.Name)
+		return err
+	}
+
+	if rcErr != nil {
+		fmt.Println("Set AURA_API_KEY in your shell profile to keep the key.")
+		return nil
+	}
+	if confirmChange(fmt.Sprintf("Save the key in %s", rcFile), false) {
+		if err := appendLine(rcFile, shell.ExportLine(shellName, envvar.APIKey.Name, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasBookmarks reports whether the user has added a bookmark.
+func hasBookmarks() bool {
+	database, err := db.New()
+	if err != nil {
+		return false
+	}
+	defer database.Close()
+
+	bookmarks, err := database.ListBookmarks()
+	return err == nil && len(bookmarks) > 0
+}
+
+// tourBookmark bookmarks a directory and checks that 'aura go' finds it.
+func tourBookmark() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	fmt.Println("Bookmarks take you to a directory with 'aura go <alias>'.")
+	pathPrompt := promptui.Prompt{Label: "Directory", Default: cwd}
+	path, err := pathPrompt.Run()
+	if err != nil {
+		return err
+	}
+	aliasPrompt := promptui.Prompt{Label: "Alias", Default: strings.ToLower(filepath.Base(path))}
+	alias, err := aliasPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	if err := runBookmarkAdd(bookmarkAddCmd, []string{strings.TrimSpace(alias), path}); err != nil {
+		return err
+	}
+
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	bookmark, err := database.GetBookmark(strings.TrimSpace(alias))
+	if err != nil || bookmark == nil {
+		return fmt.Errorf("the bookmark '%s' was not saved", alias)
+	}
+	fmt.Printf("Try it: aura go %s\n", bookmark.Alias)
+	return nil
+}
+
+// tourDo creates a sample project, shows the actions 'aura do' detects in it
+// and runs the one picked.
+func tourDo() error {
+	dir, err := os.MkdirTemp("", "aura-tour-")
+	if err != nil {
+		return fmt.Errorf("failed to create sample project: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(sampleMakefile), 0644); err != nil {
+		return fmt.Errorf("failed to create sample project: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("failed to enter sample project: %w", err)
+	}
+	defer os.Chdir(cwd)
+
+	actions := detectActions()
+	if len(actions) == 0 {
+		return fmt.Errorf("'aura do' detected no actions in the sample project")
+	}
+
+	fmt.Println("'aura do' suggests actions for the project in the current directory.")
+	fmt.Println("This sample project has a Makefile, pick an action to run it:")
+	items := make([]string, len(actions))
+	for i, action := range actions {
+		items[i] = action.Name
+	}
+	prompt := promptui.Select{Label: "Select an action", Items: items, Size: 10, Templates: theme.SelectTemplates(".")}
+	selected, _, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	if err := runAction(actions[selected]); err != nil {
+		return fmt.Errorf("the action failed: %w", err)
+	}
+	return nil
+}
+
+// tourShell installs the shell integration and checks that a new shell has
+// the aura function.
+func tourShell(shellName, rcFile string, rcErr error) error {
+	if rcErr != nil {
+		return fmt.Errorf("%w, see the installation guide to set up the integration", rcErr)
+	}
+
+	fmt.Println("The shell integration lets 'aura go' change the directory of your shell.")
+	if !confirmChange(fmt.Sprintf("Add it to %s", rcFile), false) {
+		return errTourSkipped
+	}
+	if err := shell.Install(shellName, rcFile); err != nil {
+		return err
+	}
+	if err := shell.Verify(shellName); err != nil {
+		return err
+	}
+	fmt.Printf("Restart your shell or run: source %s\n", rcFile)
+	return nil
+}
+
+// appendLine appends line to the file at path.
+func appendLine(path, line string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "\n%s\n", line); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(tourCmd)
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/trash"
+)
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <path>...",
+	Short: "Move files to the trash instead of deleting them",
+	Long: `Move files and directories to Aura's trash, where they are kept with the
+path they came from until the trash is emptied. Restore them with
+'aura trash restore' or 'aura undo'.
+
+Examples:
+  aura rm old-notes.txt
+  aura rm build dist
+  aura trash restore build`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRm,
+}
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage files moved to the trash by 'aura rm'",
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the files in the trash",
+	Args:    cobra.NoArgs,
+	RunE:    runTrashList,
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <name|id|path>",
+	Short: "Move a file from the trash back to where it came from",
+	Long: `Move a file or directory from the trash back to where it came from, or to
+the path given with --to. If several trashed files have the same name, the
+most recently trashed is restored; use the ID from 'aura trash list' to pick
+another.
+
+Examples:
+  aura trash restore notes.txt
+  aura trash restore 20261016-083300-a1b2c3
+  aura trash restore build --to build-old`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrashRestore,
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete the files in the trash",
+	Long: `Permanently delete the files in the trash, or only those trashed before
+--older-than. Asks for confirmation, or needs --yes without a terminal.
+
+Examples:
+  aura trash empty
+  aura trash empty --older-than 30d --yes`,
+	Args: cobra.NoArgs,
+	RunE: runTrashEmpty,
+}
+
+var (
+	trashRestoreTo string
+	trashOlderThan string
+	trashYes       bool
+)
+
+// operationFileTrash is the kind of journal entries of 'aura rm'.
+const operationFileTrash = "file.trash"
+
+// trashOperation records the items moved to the trash.
+type trashOperation struct {
+	IDs []string `json:"ids"`
+}
+
+// auraTrash returns the trash of Aura, in the config directory.
+func auraTrash() trash.Trash {
+	return trash.Trash{Dir: filepath.Join(config.DataDir, "trash")}
+}
+
+func runRm(cmd *cobra.Command, args []string) error {
+	bin := auraTrash()
+
+	var ids []string
+	defer func() {
+		if len(ids) == 0 {
+			return
+		}
+		description := fmt.Sprintf("Move '%s' to the trash", args[0])
+		if len(ids) > 1 {
+			description = fmt.Sprintf("Move %d files to the trash", len(ids))
+		}
+		recordOperation(nil, operationFileTrash, description, trashOperation{IDs: ids})
+	}()
+
+	for _, path := range args {
+		item, err := bin.Move(path)
+		if err != nil {
+			return fmt.Errorf("failed to remove '%s': %w", path, err)
+		}
+		ids = append(ids, item.ID)
+		fmt.Printf("🗑️  Moved '%s' to the trash\n", path)
+	}
+	return nil
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	items, err := auraTrash().List()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("The trash is empty")
+		return nil
+	}
+
+	fmt.Println("Trash, most recently removed first:")
+	for _, item := range items {
+		name := item.Name()
+		if item.IsDir {
+			name += "/"
+		}
+		fmt.Printf("  %s  %s  %-24s %8s  from %s\n", item.ID, item.DeletedAt.Format("2006-01-02 15:04"), name, formatSize(item.Size), filepath.Dir(item.OriginalPath))
+	}
+	return nil
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) error {
+	bin := auraTrash()
+	item, err := bin.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	target := trashRestoreTo
+	if target != "" {
+		if target, err = filepath.Abs(target); err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+	}
+	restored, err := bin.Restore(item, target)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Restored %s\n", restored)
+	return nil
+}
+
+func runTrashEmpty(cmd *cobra.Command, args []string) error {
+	var before time.Time
+	label := "Permanently delete all files in the trash"
+	if trashOlderThan != "" {
+		age, err := parseAge(trashOlderThan)
+		if err != nil {
+			return err
+		}
+		before = time.Now().Add(-age)
+		label = fmt.Sprintf("Permanently delete the files trashed more than %s ago", trashOlderThan)
+	}
+
+	if !confirmChange(label, trashYes) {
+		return nil
+	}
+	deleted, err := auraTrash().Empty(before)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Deleted %d items from the trash\n", deleted)
+	return nil
+}
+
+// formatSize returns a size in bytes in a human readable unit.
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	trashRestoreCmd.Flags().StringVar(&trashRestoreTo, "to", "", "Restore to this path instead")
+	trashEmptyCmd.Flags().StringVar(&trashOlderThan, "older-than", "", "Only delete files trashed before this, e.g. 30d, 2w or 12h")
+	trashEmptyCmd.Flags().BoolVarP(&trashYes, "yes", "y", false, "Delete without asking")
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+	rootCmd.AddCommand(rmCmd)
+	rootCmd.AddCommand(trashCmd)
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import "testing"
+
+func TestFormatSize(t *testing.T) {
+	for bytes, want := range map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 30:         "3.0 GiB",
+	} {
+		if got := formatSize(bytes); got != want {
+			t.Errorf("formatSize(%d) = %s, want %s", bytes, got, want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Roll back the most recent change made by Aura",
+	Long: `Roll back the most recent change made by Aura: adding or removing
+bookmarks, moving files to the trash with 'aura rm', or creating a file with
+'aura new' or a project with 'aura project'.
+Changes are recorded in a journal and each is undone once, so running undo
+again rolls back the change before.
+
+Deleting files and projects asks for confirmation, or needs --yes without a
+terminal.
+
+Examples:
+  aura undo
+  aura undo --list   # Show the changes that can be undone
+  aura undo --yes`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+var (
+	undoList bool
+	undoYes  bool
+)
+
+// Kinds of operations recorded in the journal.
+const (
+	operationBookmarkAdd    = "bookmark.add"
+	operationBookmarkRemove = "bookmark.remove"
+	operationFileCreate     = "file.create"
+	operationProjectCreate  = "project.create"
+)
+
+// bookmarkAddOperation records a bookmark that was added or changed.
+type bookmarkAddOperation struct {
+	Alias string `json:"alias"`
+	Path  string `json:"path"`
+	// PreviousPath is the path of the bookmark before it was changed, empty
+	// if it was added.
+	PreviousPath string `json:"previous_path,omitempty"`
+}
+
+// bookmarkRemoveOperation records removed bookmarks with their metadata.
+type bookmarkRemoveOperation struct {
+	Bookmarks []removedBookmark `json:"bookmarks"`
+}
+
+type removedBookmark struct {
+	Alias    string            `json:"alias"`
+	Path     string            `json:"path"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// pathOperation records a file or directory that was created.
+type pathOperation struct {
+	Path string `json:"path"`
+}
+
+// recordOperation adds an operation to the journal, opening the database if
+// database is nil. Failing to record it doesn't fail the command.
+func recordOperation(database *db.DB, kind, description string, data any) {
+	encoded, err := json.Marshal(data)
+	if err == nil && database == nil {
+		if database, err = db.New(); err == nil {
+			defer database.Close()
+		}
+	}
+	if err == nil {
+		err = database.RecordOperation(kind, description, string(encoded))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the change for 'aura undo': %v\n", err)
+	}
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	database, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	if undoList {
+		operations, err := database.ListOperations(10)
+		if err != nil {
+			return fmt.Errorf("failed to list changes: %w", err)
+		}
+		if len(operations) == 0 {
+			fmt.Println(i18n.T("undo.nothing"))
+			return nil
+		}
+		fmt.Println(i18n.T("undo.list"))
+		for _, operation := range operations {
+			fmt.Printf("  %s  %s\n", operation.CreatedAt.Local().Format("2006-01-02 15:04"), operation.Description)
+		}
+		return nil
+	}
+
+	operation, err := database.LastOperation()
+	if err != nil {
+		return fmt.Errorf("failed to read the last change: %w", err)
+	}
+	if operation == nil {
+		fmt.Println(i18n.T("undo.nothing"))
+		return nil
+	}
+
+	undone, err := undoOperation(database, operation)
+	if err != nil {
+		return fmt.Errorf("failed to undo '%s': %w", operation.Description, err)
+	}
+	if !undone {
+		return nil
+	}
+	if err := database.MarkOperationUndone(operation.ID); err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("undo.done", operation.Description))
+	return nil
+}
+
+// undoOperation rolls an operation back. It returns false if the user
+// cancelled.
+func undoOperation(database *db.DB, operation *db.Operation) (bool, error) {
+	switch operation.Kind {
+	case operationBookmarkAdd:
+		var data bookmarkAddOperation
+		if err := json.Unmarshal([]byte(operation.Data), &data); err != nil {
+			return false, fmt.Errorf("invalid journal entry: %w", err)
+		}
+		current, err := database.GetBookmark(data.Alias)
+		if err != nil {
+			return false, fmt.Errorf("database error: %w", err)
+		}
+		if current == nil || current.Path != data.Path {
+			return false, fmt.Errorf("bookmark '%s' was changed since", data.Alias)
+		}
+		if data.PreviousPath != "" {
+			return true, database.UpdateBookmarkPath(data.Alias, data.PreviousPath)
+		}
+		return true, database.RemoveBookmark(data.Alias)
+
+	case operationBookmarkRemove:
+		var data bookmarkRemoveOperation
+		if err := json.Unmarshal([]byte(operation.Data), &data); err != nil {
+			return false, fmt.Errorf("invalid journal entry: %w", err)
+		}
+		for _, bookmark := range data.Bookmarks {
+			if existing, err := database.GetBookmark(bookmark.Alias); err != nil || existing != nil {
+				return false, fmt.Errorf("bookmark '%s' exists again", bookmark.Alias)
+			}
+		}
+		bookmarks := make([]*db.Bookmark, 0, len(data.Bookmarks))
+		metadata := make(map[string]map[string]string)
+		for _, bookmark := range data.Bookmarks {
+			bookmarks = append(bookmarks, &db.Bookmark{Alias: bookmark.Alias, Path: bookmark.Path})
+			metadata[bookmark.Alias] = bookmark.Metadata
+		}
+		return true, database.AddBookmarks(bookmarks, metadata)
+
+	case operationFileTrash:
+		var data trashOperation
+		if err := json.Unmarshal([]byte(operation.Data), &data); err != nil {
+			return false, fmt.Errorf("invalid journal entry: %w", err)
+		}
+		bin := auraTrash()
+		for _, id := range data.IDs {
+			item, err := bin.Find(id)
+			if err != nil {
+				return false, err
+			}
+			restored, err := bin.Restore(item, "")
+			if err != nil {
+				return false, err
+			}
+			fmt.Println(i18n.T("undo.restored", restored))
+		}
+		return true, nil
+
+	case operationFileCreate, operationProjectCreate:
+		var data pathOperation
+		if err := json.Unmarshal([]byte(operation.Data), &data); err != nil {
+			return false, fmt.Errorf("invalid journal entry: %w", err)
+		}
+		if _, err := os.Stat(data.Path); os.IsNotExist(err) {
+			fmt.Println(i18n.T("undo.gone", data.Path))
+			return true, nil
+		}
+		if operation.Kind == operationFileCreate {
+			if !confirmChange(i18n.T("undo.confirm_file", data.Path), undoYes) {
+				return false, nil
+			}
+			return true, os.Remove(data.Path)
+		}
+		if !confirmChange(i18n.T("undo.confirm_folder", data.Path), undoYes) {
+			return false, nil
+		}
+		return true, os.RemoveAll(data.Path)
+	}
+	return false, fmt.Errorf("unknown kind of change '%s'", operation.Kind)
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoList, "list", false, "List the changes that can be undone")
+	undoCmd.Flags().BoolVarP(&undoYes, "yes", "y", false, "Delete files and projects without asking")
+	rootCmd.AddCommand(undoCmd)
+}
@@ -5,10 +5,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
 )
 
 var uninstallCmd = &cobra.Command{
@@ -18,13 +19,23 @@ var uninstallCmd = &cobra.Command{
 
 This will delete:
 - The Aura binary from your PATH (if found)
-- The Aura config and data directory (~/.config/aura or %APPDATA%\aura)
+- The config, data, cache and state directories of Aura, with the database,
+  as listed by 'aura config doctor'
 - The Aura database (if present)
+
+Asks for confirmation first, unless --yes is given or the confirm policy of
+uninstall is never.
 `,
 	RunE: runUninstall,
 }
 
+var uninstallYes bool
+
 func runUninstall(cmd *cobra.Command, args []string) error {
+	if !config.DryRun() && !confirmAction("uninstall", "Remove Aura, its configuration and all its data", true, uninstallYes) {
+		return nil
+	}
+
 	binaryName := "aura"
 	var binaryPaths []string
 
@@ -42,6 +53,24 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	dirs := auraDirs()
+	dbPath := filepath.Join("data", "sqlite", "aura.db")
+
+	if config.DryRun() {
+		for _, bin := range binaryPaths {
+			skipChange("remove binary: %s", bin)
+		}
+		for _, dir := range dirs {
+			if _, err := os.Stat(dir); err == nil {
+				skipChange("remove directory: %s", dir)
+			}
+		}
+		if _, err := os.Stat(dbPath); err == nil {
+			skipChange("remove database: %s", dbPath)
+		}
+		return nil
+	}
+
 	// Remove binaries
 	for _, bin := range binaryPaths {
 		if err := os.Remove(bin); err == nil {
@@ -49,25 +78,17 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Remove config/data directory
-	var configDir string
-	if runtime.GOOS == "windows" {
-		appData := os.Getenv("APPDATA")
-		if appData != "" {
-			configDir = filepath.Join(appData, "aura")
+	// Remove the config, data, cache and state directories
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); err != nil {
+			continue
 		}
-	} else {
-		home, _ := os.UserHomeDir()
-		configDir = filepath.Join(home, ".config", "aura")
-	}
-	if configDir != "" {
-		if err := os.RemoveAll(configDir); err == nil {
-			fmt.Printf("Removed config/data directory: %s\n", configDir)
+		if err := os.RemoveAll(dir); err == nil {
+			fmt.Printf("Removed directory: %s\n", dir)
 		}
 	}
 
 	// Remove database if present
-	dbPath := filepath.Join("data", "sqlite", "aura.db")
 	if err := os.Remove(dbPath); err == nil {
 		fmt.Printf("Removed database: %s\n", dbPath)
 	}
@@ -76,6 +97,34 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// auraDirs returns the config, data, cache and state directories of Aura,
+// leaving out those inside another one, such as the cache in the data
+// directory on Windows, and repeated ones.
+func auraDirs() []string {
+	var dirs []string
+	for _, dir := range []string{config.ConfigDir, config.DataDir, config.CacheDir, config.StateDir} {
+		if dir != "" {
+			dirs = append(dirs, filepath.Clean(dir))
+		}
+	}
+
+	var outermost []string
+	for i, dir := range dirs {
+		nested := false
+		for j, other := range dirs {
+			if rel, err := filepath.Rel(other, dir); err == nil && (rel == "." && j < i || rel != "." && !strings.HasPrefix(rel, "..")) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			outermost = append(outermost, dir)
+		}
+	}
+	return outermost
+}
+
 func init() {
+	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "Uninstall without asking")
 	rootCmd.AddCommand(uninstallCmd)
 }
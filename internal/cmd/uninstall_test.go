@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+func TestAuraDirs(t *testing.T) {
+	saved := []string{config.ConfigDir, config.DataDir, config.CacheDir, config.StateDir}
+	t.Cleanup(func() {
+		config.ConfigDir, config.DataDir, config.CacheDir, config.StateDir = saved[0], saved[1], saved[2], saved[3]
+	})
+
+	home := t.TempDir()
+	tests := []struct {
+		name                   string
+		configDir, data, cache string
+		state                  string
+		want                   []string
+	}{
+		{
+			name:      "xdg",
+			configDir: filepath.Join(home, ".config", "aura"),
+			data:      filepath.Join(home, ".local", "share", "aura"),
+			cache:     filepath.Join(home, ".cache", "aura"),
+			state:     filepath.Join(home, ".local", "state", "aura"),
+			want: []string{
+				filepath.Join(home, ".config", "aura"),
+				filepath.Join(home, ".local", "share", "aura"),
+				filepath.Join(home, ".cache", "aura"),
+				filepath.Join(home, ".local", "state", "aura"),
+			},
+		},
+		{
+			name:      "nested",
+			configDir: filepath.Join(home, "Roaming", "aura"),
+			data:      filepath.Join(home, "Local", "aura"),
+			cache:     filepath.Join(home, "Local", "aura", "cache"),
+			state:     filepath.Join(home, "Local", "aura", "state"),
+			want:      []string{filepath.Join(home, "Roaming", "aura"), filepath.Join(home, "Local", "aura")},
+		},
+		{
+			name:      "same",
+			configDir: filepath.Join(home, "aura"),
+			data:      filepath.Join(home, "aura"),
+			cache:     filepath.Join(home, "aura"),
+			state:     filepath.Join(home, "aura"),
+			want:      []string{filepath.Join(home, "aura")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.ConfigDir, config.DataDir, config.CacheDir, config.StateDir = tt.configDir, tt.data, tt.cache, tt.state
+			if got := auraDirs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auraDirs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/archive"
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+var unpackCmd = &cobra.Command{
+	Use:   "unpack <archive> [destination]",
+	Short: "Preview and unpack an archive safely",
+	Long: `Show what an archive holds and unpack it after asking. The format is
+recognized by the content: tar.gz, tar.xz, tar, zip or 7z.
+
+Without a destination an archive holding a single directory is unpacked
+here, and any other into a new directory named after it, so files never
+spill into the current directory. Archives with entries that would land
+outside of the destination, such as ../ paths or links pointing out of it,
+are refused, and existing files are only replaced with --force.
+
+Examples:
+  aura unpack release.tar.gz
+  aura unpack photos.zip ~/Pictures/trip
+  aura unpack backup.7z --list
+  aura unpack build.tar.xz -y`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runUnpack,
+}
+
+var (
+	unpackList  bool
+	unpackYes   bool
+	unpackForce bool
+)
+
+// unpackPreview is the number of entries shown before asking.
+const unpackPreview = 15
+
+func runUnpack(cmd *cobra.Command, args []string) error {
+	file := config.ExpandHome(args[0])
+	if _, err := os.Stat(file); err != nil {
+		return failure.New(failure.NotFound, "'%s' not found", args[0])
+	}
+	entries, err := archive.List(file)
+	if err != nil {
+		return failure.Wrap(failure.UserInput, err)
+	}
+
+	unsafe := archive.Check(entries)
+	if unpackList {
+		printArchive(os.Stdout, filepath.Base(file), entries, 0)
+		if unsafe != nil {
+			fmt.Fprintf(os.Stderr, "Warning: aura unpack refuses this archive: %v\n", unsafe)
+		}
+		return nil
+	}
+	if unsafe != nil {
+		return failure.New(failure.UserInput, "refusing to unpack %s: %v", filepath.Base(file), unsafe)
+	}
+
+	dir := ""
+	if len(args) > 1 {
+		dir = config.ExpandHome(args[1])
+	}
+	dir, shown := unpackDestination(file, dir, entries)
+
+	if conflicts := archive.Conflicts(entries, dir); len(conflicts) > 0 && !unpackForce {
+		more := ""
+		if len(conflicts) > 1 {
+			more = fmt.Sprintf(" and %d more existing %s", len(conflicts)-1, plural(int64(len(conflicts)-1), "file", "files"))
+		}
+		return failure.New(failure.UserInput, "unpacking would replace %s%s, use --force to replace them", conflicts[0], more)
+	}
+
+	printArchive(os.Stdout, filepath.Base(file), entries, unpackPreview)
+	fmt.Println()
+	if !confirmChange(fmt.Sprintf("Unpack into %s", shown), unpackYes) {
+		return nil
+	}
+	if err := archive.Extract(file, dir); err != nil {
+		if errors.Is(err, archive.ErrUnsafe) {
+			return failure.New(failure.UserInput, "stopped unpacking %s: %v", filepath.Base(file), err)
+		}
+		return err
+	}
+	fmt.Printf("✓ Unpacked %s into %s\n", filepath.Base(file), shown)
+	return nil
+}
+
+// unpackDestination returns the directory to unpack entries of file into,
+// dir if given, and the directory they end up in to show. Without dir an
+// archive holding a single directory is unpacked into the current one, any
+// other into a new directory named after the archive.
+func unpackDestination(file, dir string, entries []archive.Entry) (string, string) {
+	if dir != "" {
+		return dir, dir
+	}
+	roots := archive.Roots(entries)
+	if len(roots) == 1 {
+		for _, entry := range entries {
+			if name := strings.TrimPrefix(strings.ReplaceAll(entry.Name, `\`, "/"), "./"); strings.HasPrefix(name, roots[0]+"/") {
+				return ".", roots[0]
+			}
+		}
+	}
+	name := archive.TrimExtension(filepath.Base(file))
+	if name == filepath.Base(file) {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name
+}
+
+// printArchive summarizes the entries of archive name and lists up to limit
+// of them, all without a limit.
+func printArchive(w io.Writer, name string, entries []archive.Entry, limit int) {
+	var files, dirs int64
+	var size int64
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs++
+		} else {
+			files++
+			size += entry.Size
+		}
+	}
+	fmt.Fprintf(w, "%s: %d %s, %d %s, %s unpacked\n", theme.Paint(theme.Heading, name),
+		files, plural(files, "file", "files"), dirs, plural(dirs, "directory", "directories"), formatSize(size))
+
+	for i, entry := range entries {
+		if limit > 0 && i == limit {
+			fmt.Fprintln(w, theme.Paint(theme.Muted, fmt.Sprintf("  … and %d more", len(entries)-limit)))
+			break
+		}
+		sizeText := ""
+		if !entry.IsDir() && entry.Link == "" {
+			sizeText = formatSize(entry.Size)
+		}
+		line := entry.Name
+		if entry.Link != "" {
+			line += " -> " + entry.Link
+		}
+		fmt.Fprintf(w, "  %s  %s\n", theme.Paint(theme.Muted, fmt.Sprintf("%10s", sizeText)), line)
+	}
+}
+
+func init() {
+	unpackCmd.Flags().BoolVarP(&unpackList, "list", "l", false, "Only list the contents")
+	unpackCmd.Flags().BoolVarP(&unpackYes, "yes", "y", false, "Unpack without asking")
+	unpackCmd.Flags().BoolVar(&unpackForce, "force", false, "Replace existing files")
+	rootCmd.AddCommand(unpackCmd)
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/watch"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <command>...",
+	Short: "Rerun a command when files change",
+	Long: `Run a command and rerun it whenever files below the current directory
+change, for test and build loops in any ecosystem. Changes are collected
+until files stay unchanged for the debounce time, and a change while the
+command runs restarts it.
+
+Version control directories, dependencies such as node_modules, build output
+and files ignored by .gitignore don't trigger a rerun. Ignore more with
+--ignore. Runs taking longer than the notifications setting show a desktop
+notification when they finish. Press Ctrl+C to stop.
+
+Examples:
+  aura watch go test ./...
+  aura watch "npm run build && npm test"
+  aura watch --ignore "*.log" --ignore "coverage" pytest
+  aura do --watch "run tests"            # Watch a context action`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runWatch,
+}
+
+var (
+	watchIgnore   []string
+	watchDebounce time.Duration
+)
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	return watchCommand(strings.Join(args, " "))
+}
+
+// watchCommand runs command in the shell and reruns it on changes until the
+// user interrupts.
+func watchCommand(command string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	changes := make(chan []string, 1)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- watch.Watch(ctx, watch.Options{Root: ".", Ignore: watchIgnore, Debounce: watchDebounce}, func(paths []string) {
+			select {
+			case changes <- paths:
+			default:
+				// A rerun is already pending
+			}
+		})
+	}()
+
+	reloader := config.NewReloader(config.GetSettingsFile())
+	fmt.Printf("👀 Watching for changes, press Ctrl+C to stop\n")
+	for {
+		reloadSettings(reloader)
+		fmt.Printf("▶ %s\n", command)
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		started := time.Now()
+		go func() {
+			if err := checkCommand(command, ""); err != nil {
+				done <- err
+				return
+			}
+			done <- watch.Command(runCtx, command).Run()
+		}()
+
+		var paths []string
+		select {
+		case err := <-done:
+			cancel()
+			elapsed := time.Since(started).Round(time.Millisecond)
+			if err != nil {
+				fmt.Printf("✗ Failed after %s: %v\n", elapsed, err)
+				notifyFinished("watch", elapsed, "✗ Failed", command)
+			} else {
+				fmt.Printf("✓ Done in %s\n", elapsed)
+				notifyFinished("watch", elapsed, "✓ Done", command)
+			}
+
+			select {
+			case paths = <-changes:
+			case err := <-watchErr:
+				return watchStopped(err)
+			case <-ctx.Done():
+				return nil
+			}
+		case paths = <-changes:
+			cancel()
+			<-done
+			fmt.Println("⟳ Restarting")
+		case err := <-watchErr:
+			cancel()
+			<-done
+			return watchStopped(err)
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		}
+
+		fmt.Printf("🔄 %s\n", describeChanges(paths))
+	}
+}
+
+// watchStopped returns the error that ended watching, if any.
+func watchStopped(err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to watch for changes: %w", err)
+	}
+	return nil
+}
+
+// describeChanges summarizes changed paths for the rerun message.
+func describeChanges(paths []string) string {
+	switch len(paths) {
+	case 0:
+		return "Files changed"
+	case 1:
+		return fmt.Sprintf("Changed: %s", paths[0])
+	case 2, 3:
+		return fmt.Sprintf("Changed: %s", strings.Join(paths, ", "))
+	default:
+		return fmt.Sprintf("Changed: %s and %d more", strings.Join(paths[:2], ", "), len(paths)-2)
+	}
+}
+
+// addWatchFlags registers the flags configuring what is watched on cmd.
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&watchIgnore, "ignore", nil, "Glob pattern of files or directories that don't trigger a rerun (repeatable)")
+	cmd.Flags().DurationVar(&watchDebounce, "debounce", 300*time.Millisecond, "How long files must stay unchanged before rerunning")
+}
+
+func init() {
+	// Watch arguments are the command, not flags of aura
+	watchCmd.Flags().SetInterspersed(false)
+	addWatchFlags(watchCmd)
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/context"
+)
+
+func TestFindAction(t *testing.T) {
+	actions := []context.Action{
+		{Name: "Run tests", Command: "go test ./..."},
+		{Name: "Run tests with coverage", Command: "go test -cover ./..."},
+		{Name: "Build", Command: "go build ./..."},
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+		wantErr  bool
+	}{
+		{"run tests", "Run tests", false},
+		{"build", "Build", false},
+		{"cover", "Run tests with coverage", false},
+		{"go test", "", true},
+		{"deploy", "", true},
+	}
+
+	for _, tt := range tests {
+		action, err := findAction(actions, tt.query)
+		if (err != nil) != tt.wantErr || action.Name != tt.expected {
+			t.Errorf("findAction(%q) = %q, %v, want %q", tt.query, action.Name, err, tt.expected)
+		}
+	}
+}
+
+func TestDescribeChanges(t *testing.T) {
+	for _, tt := range []struct {
+		paths    []string
+		expected string
+	}{
+		{[]string{"main.go"}, "Changed: main.go"},
+		{[]string{"a.go", "b.go"}, "Changed: a.go, b.go"},
+		{[]string{"a.go", "b.go", "c.go", "d.go"}, "Changed: a.go, b.go and 2 more"},
+	} {
+		if got := describeChanges(tt.paths); got != tt.expected {
+			t.Errorf("describeChanges(%v) = %q, want %q", tt.paths, got, tt.expected)
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
 )
 
 var (
@@ -15,58 +21,115 @@ var (
 	DatabasePath string
 	// Environment indicates the current environment (development, production).
 	Environment string
-	// DatabaseType indicates if using local file or Docker container
+	// DatabaseType indicates if using local file or Docker container, ""
+	// until detectDatabase ran.
 	DatabaseType string
+	// MigratedFiles are the new paths of the files Initialize moved out of
+	// ConfigDir, see DataDir.
+	MigratedFiles []string
 )
 
 // Initialize sets up the configuration directories and paths.
 func Initialize() error {
 	// Set environment
-	Environment = os.Getenv("AURA_ENV")
+	Environment = envvar.Env.Get()
 	if Environment == "" {
 		Environment = "production"
 	}
 
+	if Offline() {
+		http.DefaultTransport = offlineTransport{}
+	}
+	if err := loadPolicy(); err != nil {
+		return err
+	}
+
 	// Check for environment-specific database path
-	if dbPath := os.Getenv("AURA_DB_PATH"); dbPath != "" {
+	if dbPath := envvar.DBPath.Get(); dbPath != "" {
 		DatabasePath = dbPath
 		ConfigDir = filepath.Dir(dbPath)
+		DataDir, CacheDir, StateDir = ConfigDir, ConfigDir, ConfigDir
 		DatabaseType = "file"
 	} else {
-		// Check if running in Docker container mode (production)
-		if isDockerAvailable() && isAuraDbRunning() {
-			DatabaseType = "docker"
-			DatabasePath = "/data/aura.db" // Path inside container
-
-			userConfigDir, err := os.UserConfigDir()
-			if err != nil {
-				return err
-			}
-			ConfigDir = filepath.Join(userConfigDir, "aura")
-		} else {
-			// Fallback to local file
-			DatabaseType = "file"
-			userConfigDir, err := os.UserConfigDir()
-			if err != nil {
-				return err
-			}
-			ConfigDir = filepath.Join(userConfigDir, "aura")
-			DatabasePath = filepath.Join(ConfigDir, "aura.db")
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
 		}
-	}
-
-	// Create the config directory if it doesn't exist (for file mode)
-	if DatabaseType == "file" {
-		if err := os.MkdirAll(ConfigDir, 0755); err != nil {
+		if ConfigDir, err = os.UserConfigDir(); err != nil {
 			return err
 		}
+		ConfigDir = filepath.Join(ConfigDir, "aura")
+		_, DataDir, CacheDir, StateDir = userDirs(runtime.GOOS, home, os.Getenv)
+
+		// Whether the database runs in Docker is detected when it is used,
+		// so commands without it don't wait for docker ps
+		DatabaseType = ""
+		DatabasePath = filepath.Join(DataDir, "aura.db")
+		MigratedFiles = migrateLegacyFiles()
+	}
+
+	if err := os.MkdirAll(ConfigDir, 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(DatabasePath), 0755); err != nil {
+		return err
+	}
+
+	settings, err := LoadSettings(GetSettingsFile())
+	var invalid *SettingsError
+	if errors.As(err, &invalid) {
+		SettingsProblems = invalid.Problems
+	} else if err != nil {
+		return err
 	}
+	UserSettings = settings
 
+	return loadTeamSettings()
+}
+
+// loadTeamSettings loads the team settings of the current directory.
+func loadTeamSettings() error {
+	Team, TeamSettingsFile, TeamProblems = TeamSettings{}, "", nil
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	if TeamSettingsFile = FindTeamFile(dir); TeamSettingsFile == "" {
+		return nil
+	}
+	team, err := LoadTeamSettings(TeamSettingsFile)
+	var invalid *SettingsError
+	if errors.As(err, &invalid) {
+		TeamProblems = invalid.Problems
+	} else if err != nil {
+		return err
+	}
+	Team = team
 	return nil
 }
 
+// databaseMu guards the detection of the database by detectDatabase.
+var databaseMu sync.Mutex
+
+// detectDatabase sets DatabaseType and DatabasePath unless Initialize did:
+// the database in the aura-db container if it runs, else the local file.
+func detectDatabase() {
+	databaseMu.Lock()
+	defer databaseMu.Unlock()
+	if DatabaseType != "" {
+		return
+	}
+	if isDockerAvailable() && isAuraDbRunning() {
+		DatabaseType = "docker"
+		DatabasePath = "/data/aura.db" // Path inside container
+		return
+	}
+	DatabaseType = "file"
+}
+
 // GetDatabaseConnection returns the appropriate database connection string
 func GetDatabaseConnection() string {
+	detectDatabase()
 	if DatabaseType == "docker" {
 		// Use Docker exec to access the database
 		return "docker:aura-db:" + DatabasePath
@@ -95,14 +158,16 @@ func IsDevelopment() bool {
 	return Environment == "development"
 }
 
-// IsDockerMode returns true if using Docker database
+// IsDockerMode returns true if using Docker database. The database is
+// detected on the first call, see detectDatabase.
 func IsDockerMode() bool {
+	detectDatabase()
 	return DatabaseType == "docker"
 }
 
 // GetLogLevel returns the configured log level.
 func GetLogLevel() string {
-	if level := os.Getenv("AURA_LOG_LEVEL"); level != "" {
+	if level := envvar.LogLevel.Get(); level != "" {
 		return level
 	}
 	if IsDevelopment() {
@@ -113,10 +178,10 @@ func GetLogLevel() string {
 
 // GetLogFile returns the configured log file path.
 func GetLogFile() string {
-	if file := os.Getenv("AURA_LOG_FILE"); file != "" {
+	if file := envvar.LogFile.Get(); file != "" {
 		return file
 	}
-	return filepath.Join(ConfigDir, "aura.log")
+	return filepath.Join(StateDir, "aura.log")
 }
 
 // EnsureAuraDbRunning starts the Aura database container if not running
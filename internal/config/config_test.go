@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -46,6 +48,13 @@ func TestInitialize(t *testing.T) {
 		},
 	}
 
+	// Keep the files of the user out of reach of the migration
+	home := t.TempDir()
+	for _, name := range []string{"HOME", "USERPROFILE", "XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME", "AppData", "LocalAppData"} {
+		t.Setenv(name, filepath.Join(home, name))
+	}
+	t.Setenv("AURA_DB_PATH", "")
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set environment
@@ -64,6 +73,8 @@ func TestInitialize(t *testing.T) {
 				t.Errorf("Environment = %v, want %v", Environment, tt.expectEnv)
 			}
 
+			// The database is detected when it is used
+			IsDockerMode()
 			if DatabaseType != tt.expectType {
 				t.Errorf("DatabaseType = %v, want %v", DatabaseType, tt.expectType)
 			}
@@ -115,6 +126,38 @@ func TestIsDevelopment(t *testing.T) {
 	}
 }
 
+func TestDatabaseDetectedLazily(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as docker")
+	}
+	originalPath, originalType := DatabasePath, DatabaseType
+	defer func() { DatabasePath, DatabaseType = originalPath, originalType }()
+
+	// A docker that records its calls and reports aura-db as running
+	bin, calls := t.TempDir(), filepath.Join(t.TempDir(), "calls")
+	os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\necho \"$@\" >> "+calls+"\necho 4f2a\n"), 0755)
+	t.Setenv("PATH", bin)
+	home := t.TempDir()
+	for _, name := range []string{"HOME", "XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		t.Setenv(name, filepath.Join(home, name))
+	}
+	t.Setenv("AURA_DB_PATH", "")
+
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if _, err := os.Stat(calls); err == nil {
+		t.Error("Initialize() ran docker")
+	}
+	if !IsDockerMode() || DatabasePath != "/data/aura.db" {
+		t.Errorf("IsDockerMode() = false, DatabasePath = %s, want the database in Docker", DatabasePath)
+	}
+	IsDockerMode()
+	if data, _ := os.ReadFile(calls); strings.Count(string(data), "\n") != 1 {
+		t.Errorf("docker ran %q, want it run once", data)
+	}
+}
+
 func TestIsDockerMode(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -181,7 +224,7 @@ func TestGetLogFile(t *testing.T) {
 	}
 	defer os.RemoveAll(tempDir)
 
-	ConfigDir = tempDir
+	StateDir = tempDir
 
 	logFile := GetLogFile()
 	expectedPath := filepath.Join(tempDir, "aura.log")
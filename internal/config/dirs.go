@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Directories of the files Aura keeps besides its settings, following the
+// XDG Base Directory specification. With AURA_DB_PATH set they are all the
+// directory of the database, like ConfigDir, so an installation can be kept
+// apart from others.
+var (
+	// DataDir holds the database and files such as the trash and cheatsheets.
+	DataDir string
+	// CacheDir holds files that are fetched or rebuilt when missing, such as
+	// tldr pages.
+	CacheDir string
+	// StateDir holds logs and history.
+	StateDir string
+)
+
+// userDirs returns the config, data, cache and state directories of Aura on
+// goos for the user with the home directory home, honoring the XDG_*_HOME
+// variables of getenv outside of Windows. The config directory is the one of
+// os.UserConfigDir.
+func userDirs(goos, home string, getenv func(string) string) (configDir, dataDir, cacheDir, stateDir string) {
+	xdg := func(name string, fallback ...string) string {
+		if dir := getenv(name); filepath.IsAbs(dir) && goos != "windows" {
+			return filepath.Join(dir, "aura")
+		}
+		return filepath.Join(append([]string{home}, append(fallback, "aura")...)...)
+	}
+
+	switch goos {
+	case "windows":
+		roaming, local := getenv("AppData"), getenv("LocalAppData")
+		if roaming == "" {
+			roaming = filepath.Join(home, "AppData", "Roaming")
+		}
+		if local == "" {
+			local = filepath.Join(home, "AppData", "Local")
+		}
+		dataDir = filepath.Join(local, "aura")
+		return filepath.Join(roaming, "aura"), dataDir, filepath.Join(dataDir, "cache"), filepath.Join(dataDir, "state")
+	case "darwin":
+		support := []string{"Library", "Application Support"}
+		return filepath.Join(home, "Library", "Application Support", "aura"), xdg("XDG_DATA_HOME", support...), xdg("XDG_CACHE_HOME", "Library", "Caches"), xdg("XDG_STATE_HOME", support...)
+	default:
+		return xdg("XDG_CONFIG_HOME", ".config"), xdg("XDG_DATA_HOME", ".local", "share"), xdg("XDG_CACHE_HOME", ".cache"), xdg("XDG_STATE_HOME", ".local", "state")
+	}
+}
+
+// legacyFile is a file Aura kept in ConfigDir before it had DataDir,
+// CacheDir and StateDir, with the directory it belongs in now.
+type legacyFile struct {
+	name string
+	dir  *string
+}
+
+// legacyFiles are moved by migrateLegacyFiles, besides the database.
+var legacyFiles = []legacyFile{
+	{"trash", &DataDir},
+	{"cheatsheets", &DataDir},
+	{"aliases", &DataDir},
+	{"tldr", &CacheDir},
+	{"aura.log", &StateDir},
+	{"sql_history", &StateDir},
+	{"port_conflicts.json", &StateDir},
+}
+
+// migrateLegacyFiles moves the database and the other files of older
+// versions from ConfigDir to the directories they belong in, unless they
+// exist there already, and returns the new paths of those moved. A database
+// that can't be moved, such as one on another file system, is kept and used
+// where it is. In read-only mode, which includes dry runs, nothing is moved
+// and the database is used where it is.
+func migrateLegacyFiles() []string {
+	var moved []string
+	readOnly := ReadOnly()
+	if legacy := filepath.Join(ConfigDir, "aura.db"); DatabaseType != "docker" && legacy != DatabasePath && exists(legacy) {
+		if !exists(DatabasePath) && !readOnly && moveDatabase(legacy, DatabasePath) == nil {
+			moved = append(moved, DatabasePath)
+		} else if !exists(DatabasePath) {
+			DatabasePath = legacy
+		}
+	}
+
+	for _, file := range legacyFiles {
+		from, to := filepath.Join(ConfigDir, file.name), filepath.Join(*file.dir, file.name)
+		if readOnly || from == to || !exists(from) || exists(to) {
+			continue
+		}
+		if err := os.MkdirAll(*file.dir, 0755); err != nil {
+			continue
+		}
+		if os.Rename(from, to) == nil {
+			moved = append(moved, to)
+		}
+	}
+	return moved
+}
+
+// moveDatabase moves the SQLite database from to to together with its
+// journal files, or leaves all of them where they are.
+func moveDatabase(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	var journals []string
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if !exists(from + suffix) {
+			continue
+		}
+		if err := os.Rename(from+suffix, to+suffix); err != nil {
+			undoMoves(from, to, journals)
+			return err
+		}
+		journals = append(journals, suffix)
+	}
+	if err := os.Rename(from, to); err != nil {
+		undoMoves(from, to, journals)
+		return err
+	}
+	return nil
+}
+
+// undoMoves moves the journal files with suffixes back from to to from.
+func undoMoves(from, to string, suffixes []string) {
+	for _, suffix := range suffixes {
+		os.Rename(to+suffix, from+suffix)
+	}
+}
+
+// exists reports whether there is a file or directory at path.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
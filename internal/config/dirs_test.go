@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUserDirs(t *testing.T) {
+	tests := []struct {
+		goos string
+		env  map[string]string
+		want [4]string
+	}{
+		{"linux", nil, [4]string{"/home/me/.config/aura", "/home/me/.local/share/aura", "/home/me/.cache/aura", "/home/me/.local/state/aura"}},
+		{
+			"linux",
+			map[string]string{"XDG_DATA_HOME": "/data", "XDG_CACHE_HOME": "/tmp/cache", "XDG_STATE_HOME": "relative"},
+			[4]string{"/home/me/.config/aura", "/data/aura", "/tmp/cache/aura", "/home/me/.local/state/aura"},
+		},
+		{"darwin", nil, [4]string{"/home/me/Library/Application Support/aura", "/home/me/Library/Application Support/aura", "/home/me/Library/Caches/aura", "/home/me/Library/Application Support/aura"}},
+		{"windows", map[string]string{"AppData": "/roaming", "LocalAppData": "/local"}, [4]string{"/roaming/aura", "/local/aura", "/local/aura/cache", "/local/aura/state"}},
+	}
+	for _, test := range tests {
+		getenv := func(name string) string { return test.env[name] }
+		configDir, dataDir, cacheDir, stateDir := userDirs(test.goos, "/home/me", getenv)
+		got := [4]string{filepath.ToSlash(configDir), filepath.ToSlash(dataDir), filepath.ToSlash(cacheDir), filepath.ToSlash(stateDir)}
+		if got != test.want {
+			t.Errorf("userDirs(%s, %v) = %v, want %v", test.goos, test.env, got, test.want)
+		}
+	}
+}
+
+func TestMigrateLegacyFiles(t *testing.T) {
+	original := [5]string{ConfigDir, DataDir, CacheDir, StateDir, DatabasePath}
+	originalType := DatabaseType
+	defer func() {
+		ConfigDir, DataDir, CacheDir, StateDir, DatabasePath = original[0], original[1], original[2], original[3], original[4]
+		DatabaseType = originalType
+	}()
+
+	root := t.TempDir()
+	ConfigDir = filepath.Join(root, "config")
+	DataDir, CacheDir, StateDir = filepath.Join(root, "data"), filepath.Join(root, "cache"), filepath.Join(root, "state")
+	DatabasePath, DatabaseType = filepath.Join(DataDir, "aura.db"), "file"
+
+	for _, name := range []string{"aura.db", "aura.db-wal", "aura.log", "config.yaml", "tldr/git.md"} {
+		path := filepath.Join(ConfigDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Files already in place are not replaced
+	if err := os.MkdirAll(StateDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(StateDir, "aura.log"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	moved := migrateLegacyFiles()
+	if len(moved) != 2 {
+		t.Errorf("migrateLegacyFiles() = %v, want the database and tldr", moved)
+	}
+	for path, want := range map[string]string{
+		filepath.Join(DataDir, "aura.db"):       "aura.db",
+		filepath.Join(DataDir, "aura.db-wal"):   "aura.db-wal",
+		filepath.Join(CacheDir, "tldr/git.md"):  "tldr/git.md",
+		filepath.Join(StateDir, "aura.log"):     "new",
+		filepath.Join(ConfigDir, "aura.log"):    "aura.log",
+		filepath.Join(ConfigDir, "config.yaml"): "config.yaml",
+	} {
+		if content, err := os.ReadFile(path); err != nil || string(content) != want {
+			t.Errorf("%s = %q, %v, want %q", path, content, err, want)
+		}
+	}
+	if DatabasePath != filepath.Join(DataDir, "aura.db") {
+		t.Errorf("DatabasePath = %s", DatabasePath)
+	}
+
+	// A database that can't be moved is used where it is
+	DatabasePath = filepath.Join(root, "readonly", "aura.db")
+	if err := os.WriteFile(filepath.Join(ConfigDir, "aura.db"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "readonly"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	migrateLegacyFiles()
+	if DatabasePath != filepath.Join(ConfigDir, "aura.db") {
+		t.Errorf("DatabasePath = %s, want the legacy database", DatabasePath)
+	}
+}
+
+func TestMigrateLegacyFilesReadOnly(t *testing.T) {
+	for _, env := range []string{"AURA_DRY_RUN", "AURA_READ_ONLY"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, "1")
+			testMigrateNothing(t)
+		})
+	}
+}
+
+// testMigrateNothing checks that migrateLegacyFiles moves nothing and uses
+// the legacy database where it is.
+func testMigrateNothing(t *testing.T) {
+	original := [5]string{ConfigDir, DataDir, CacheDir, StateDir, DatabasePath}
+	originalType := DatabaseType
+	defer func() {
+		ConfigDir, DataDir, CacheDir, StateDir, DatabasePath = original[0], original[1], original[2], original[3], original[4]
+		DatabaseType = originalType
+	}()
+
+	root := t.TempDir()
+	ConfigDir = filepath.Join(root, "config")
+	DataDir, CacheDir, StateDir = filepath.Join(root, "data"), filepath.Join(root, "cache"), filepath.Join(root, "state")
+	DatabasePath, DatabaseType = filepath.Join(DataDir, "aura.db"), "file"
+	if err := os.MkdirAll(ConfigDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"aura.db", "aura.log"} {
+		if err := os.WriteFile(filepath.Join(ConfigDir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if moved := migrateLegacyFiles(); len(moved) != 0 {
+		t.Errorf("migrateLegacyFiles() = %v, want nothing moved", moved)
+	}
+	if _, err := os.Stat(filepath.Join(StateDir, "aura.log")); err == nil {
+		t.Error("migrateLegacyFiles() moved the log")
+	}
+	if DatabasePath != filepath.Join(ConfigDir, "aura.db") {
+		t.Errorf("DatabasePath = %s, want the database where it is", DatabasePath)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// Offline reports whether Aura must not use the network: --offline or
+// AURA_OFFLINE=1. Initialize enforces it for all HTTP requests.
+func Offline() bool {
+	return enabled(envvar.Offline)
+}
+
+// ReadOnly reports whether the database must not be changed: --read-only,
+// AURA_READ_ONLY=1 or dry-run mode. The database is then opened read-only.
+func ReadOnly() bool {
+	return enabled(envvar.ReadOnly) || DryRun()
+}
+
+// DryRun reports whether commands only show what they would change:
+// --dry-run or AURA_DRY_RUN=1. Commands without a dry run fail on changes to
+// the database, as in read-only mode, and don't run commands.
+func DryRun() bool {
+	return enabled(envvar.DryRun)
+}
+
+// enabled reports whether the switch v is set to 1 or true.
+func enabled(v envvar.Var) bool {
+	value := strings.ToLower(strings.TrimSpace(v.Get()))
+	return value == "1" || value == "true"
+}
+
+// RequireNetwork returns an error in offline mode saying that feature, such
+// as "the AI assistant", needs the network.
+func RequireNetwork(feature string) error {
+	if !Offline() {
+		return nil
+	}
+	return failure.New(failure.Network, "%s needs the network, which is off in offline mode (--offline or AURA_OFFLINE)", feature)
+}
+
+// ErrReadOnly is returned for changes to the database in read-only mode.
+var ErrReadOnly = failure.New(failure.Config, "the database is read-only, run without --read-only, --dry-run or AURA_READ_ONLY to save changes")
+
+// IsReadOnlyError reports whether err is a change refused in read-only mode.
+func IsReadOnlyError(err error) bool {
+	return errors.Is(err, ErrReadOnly) || (ReadOnly() && err != nil && strings.Contains(err.Error(), "readonly database"))
+}
+
+// offlineTransport fails all requests. It replaces http.DefaultTransport in
+// offline mode, which every HTTP client of Aura uses.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, failure.New(failure.Network, "no request to %s in offline mode (--offline or AURA_OFFLINE)", req.URL.Host)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestModes(t *testing.T) {
+	for _, value := range []string{"", "0", "false", "yes"} {
+		t.Setenv("AURA_OFFLINE", value)
+		t.Setenv("AURA_READ_ONLY", value)
+		if Offline() || ReadOnly() {
+			t.Errorf("%q turned a mode on", value)
+		}
+		if err := RequireNetwork("aura send"); err != nil {
+			t.Errorf("RequireNetwork() = %v with AURA_OFFLINE=%q", err, value)
+		}
+	}
+
+	for _, value := range []string{"1", "true", " TRUE "} {
+		t.Setenv("AURA_OFFLINE", value)
+		t.Setenv("AURA_READ_ONLY", value)
+		if !Offline() || !ReadOnly() {
+			t.Errorf("%q did not turn the modes on", value)
+		}
+		if err := RequireNetwork("aura send"); failure.KindOf(err) != failure.Network {
+			t.Errorf("RequireNetwork() = %v, want a network error", err)
+		}
+	}
+}
+
+func TestIsReadOnlyError(t *testing.T) {
+	sqliteErr := errors.New("failed to add bookmark: attempt to write a readonly database (8)")
+
+	t.Setenv("AURA_READ_ONLY", "")
+	if IsReadOnlyError(sqliteErr) {
+		t.Error("IsReadOnlyError() is true outside of read-only mode")
+	}
+	if !IsReadOnlyError(ErrReadOnly) {
+		t.Error("IsReadOnlyError(ErrReadOnly) is false")
+	}
+
+	t.Setenv("AURA_READ_ONLY", "1")
+	if !IsReadOnlyError(sqliteErr) {
+		t.Error("IsReadOnlyError() is false for a refused write in read-only mode")
+	}
+	if IsReadOnlyError(errors.New("no such table: bookmarks")) || IsReadOnlyError(nil) {
+		t.Error("IsReadOnlyError() is true for other errors")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("AURA_READ_ONLY", "")
+	t.Setenv("AURA_DRY_RUN", "")
+	if DryRun() || ReadOnly() {
+		t.Error("DryRun() or ReadOnly() is true by default")
+	}
+	t.Setenv("AURA_DRY_RUN", "1")
+	if !DryRun() || !ReadOnly() {
+		t.Error("dry-run mode doesn't open the database read-only")
+	}
+}
+
+func TestOfflineTransport(t *testing.T) {
+	client := &http.Client{Transport: offlineTransport{}}
+	_, err := client.Get("https://api.openai.com/v1/models")
+	if failure.KindOf(err) != failure.Network {
+		t.Errorf("Get() = %v, want a network error", err)
+	}
+}
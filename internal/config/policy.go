@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// Features an organization policy can disable.
+const (
+	// FeatureAI is the AI assistant.
+	FeatureAI = "ai"
+	// FeatureCommands is running commands for the user, such as those of
+	// aura do, aura exec and aura each.
+	FeatureCommands = "commands"
+	// FeatureTelemetry is sending usage data. Aura sends none, the feature
+	// exists so policies stay valid if it ever does.
+	FeatureTelemetry = "telemetry"
+)
+
+// Features lists the features a policy can disable.
+var Features = []string{FeatureAI, FeatureCommands, FeatureTelemetry}
+
+// Policy is the policy of an organization, set by its administrators in the
+// policy file, such as provisioned with MDM. Neither the settings, the team
+// settings, flags nor environment variables override it.
+type Policy struct {
+	// Disable lists the features turned off, see Features.
+	Disable []string `yaml:"disable"`
+	// APIURL pins the API of the AI assistant: it replaces AURA_API_URL, and
+	// fallback models and embeddings of other APIs are not used.
+	APIURL string `yaml:"api_url"`
+	// Contact tells users whom to ask about the policy, such as an email
+	// address.
+	Contact string `yaml:"contact"`
+}
+
+// PolicyFile is the path of the policy file, writable only by
+// administrators: /etc/aura/policy.yaml, /Library/Application
+// Support/Aura/policy.yaml on macOS or %ProgramData%\Aura\policy.yaml on
+// Windows.
+var PolicyFile = policyPath(runtime.GOOS, os.Getenv)
+
+// ActivePolicy holds the policy loaded by Initialize.
+var ActivePolicy Policy
+
+// PolicyProblems are the unknown settings and invalid values Initialize
+// found in the policy file, like SettingsProblems.
+var PolicyProblems []Problem
+
+// policyPath returns the path of the policy file on goos.
+func policyPath(goos string, getenv func(string) string) string {
+	switch goos {
+	case "windows":
+		programData := getenv("ProgramData")
+		if programData == "" {
+			programData = `C:\ProgramData`
+		}
+		return filepath.Join(programData, "Aura", "policy.yaml")
+	case "darwin":
+		return "/Library/Application Support/Aura/policy.yaml"
+	default:
+		return "/etc/aura/policy.yaml"
+	}
+}
+
+// loadPolicy loads the policy file into ActivePolicy. A policy file that
+// can't be read or parsed fails, as ignoring it would lift the policy.
+func loadPolicy() error {
+	ActivePolicy, PolicyProblems = Policy{}, nil
+	var policy Policy
+	err := loadSettingsFile(PolicyFile, &policy)
+	var invalid *SettingsError
+	if errors.As(err, &invalid) {
+		PolicyProblems = invalid.Problems
+	} else if err != nil {
+		return err
+	}
+	policy.APIURL = strings.TrimSuffix(policy.APIURL, "/")
+	ActivePolicy = policy
+	return nil
+}
+
+// Disabled reports whether the policy turns feature off.
+func Disabled(feature string) bool {
+	for _, disabled := range ActivePolicy.Disable {
+		if strings.EqualFold(strings.TrimSpace(disabled), feature) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireFeature returns an error if the policy turns feature off. what
+// names it in the error, such as "the AI assistant".
+func RequireFeature(feature, what string) error {
+	if !Disabled(feature) {
+		return nil
+	}
+	message := what + " is disabled by the policy of your organization, see 'aura policy show'"
+	if ActivePolicy.Contact != "" {
+		message += ", contact: " + ActivePolicy.Contact
+	}
+	return failure.New(failure.Config, "%s", message)
+}
+
+// APIURLAllowed reports whether the policy allows requests to the API at
+// url: any API unless the policy pins one.
+func APIURLAllowed(url string) bool {
+	return ActivePolicy.APIURL == "" || strings.TrimSuffix(url, "/") == ActivePolicy.APIURL
+}
+
+// PolicyLocked reports whether the policy file can't be changed by the
+// current user, as it should be. A missing file is not locked.
+func PolicyLocked() bool {
+	if _, err := os.Stat(PolicyFile); err != nil {
+		return false
+	}
+	file, err := os.OpenFile(PolicyFile, os.O_WRONLY, 0)
+	if err != nil {
+		return true
+	}
+	file.Close()
+	return false
+}
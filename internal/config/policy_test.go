@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestPolicyPath(t *testing.T) {
+	getenv := func(name string) string {
+		if name == "ProgramData" {
+			return `D:\Data`
+		}
+		return ""
+	}
+	for goos, want := range map[string]string{
+		"linux":   "/etc/aura/policy.yaml",
+		"darwin":  "/Library/Application Support/Aura/policy.yaml",
+		"windows": filepath.Join(`D:\Data`, "Aura", "policy.yaml"),
+	} {
+		if got := policyPath(goos, getenv); got != want {
+			t.Errorf("policyPath(%s) = %s, want %s", goos, got, want)
+		}
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	originalFile, originalPolicy := PolicyFile, ActivePolicy
+	defer func() { PolicyFile, ActivePolicy, PolicyProblems = originalFile, originalPolicy, nil }()
+	PolicyFile = filepath.Join(t.TempDir(), "policy.yaml")
+
+	if err := loadPolicy(); err != nil || len(ActivePolicy.Disable) != 0 {
+		t.Errorf("loadPolicy() = %v, %+v without a policy file", err, ActivePolicy)
+	}
+
+	os.WriteFile(PolicyFile, []byte("disable: [AI, printing]\napi_url: https://llm.example.com/v1/\ncontact: it@example.com\n"), 0644)
+	if err := loadPolicy(); err != nil {
+		t.Fatalf("loadPolicy() error = %v", err)
+	}
+	if len(PolicyProblems) != 1 || PolicyProblems[0].Key != "disable[1]" {
+		t.Errorf("PolicyProblems = %+v, want the unknown feature", PolicyProblems)
+	}
+	if !Disabled(FeatureAI) || Disabled(FeatureCommands) || ActivePolicy.APIURL != "https://llm.example.com/v1" {
+		t.Errorf("ActivePolicy = %+v", ActivePolicy)
+	}
+
+	// A policy that can't be parsed isn't ignored
+	os.WriteFile(PolicyFile, []byte("disable: [ai\n"), 0644)
+	if err := loadPolicy(); failure.KindOf(err) != failure.Config {
+		t.Errorf("loadPolicy() = %v, want a config error", err)
+	}
+}
+
+func TestRequireFeature(t *testing.T) {
+	original := ActivePolicy
+	defer func() { ActivePolicy = original }()
+
+	ActivePolicy = Policy{}
+	if err := RequireFeature(FeatureCommands, "running commands"); err != nil {
+		t.Errorf("RequireFeature() = %v without a policy", err)
+	}
+	if !APIURLAllowed("http://localhost:11434/v1") {
+		t.Error("APIURLAllowed() = false without a pinned API")
+	}
+
+	ActivePolicy = Policy{Disable: []string{"commands"}, APIURL: "https://llm.example.com/v1", Contact: "it@example.com"}
+	err := RequireFeature(FeatureCommands, "running commands")
+	if failure.KindOf(err) != failure.Config || !strings.Contains(err.Error(), "running commands is disabled") || !strings.Contains(err.Error(), "it@example.com") {
+		t.Errorf("RequireFeature() = %v", err)
+	}
+	if !APIURLAllowed("https://llm.example.com/v1/") || APIURLAllowed("http://localhost:11434/v1") {
+		t.Error("APIURLAllowed() doesn't follow the pinned API")
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Reloader picks up changes to the settings file, such as ones made by
+// aura config set in another terminal, in long-running commands.
+type Reloader struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// NewReloader returns a Reloader for the settings file at path, taking its
+// current content as loaded.
+func NewReloader(path string) *Reloader {
+	r := &Reloader{path: path}
+	r.modTime, r.size = r.stat()
+	return r
+}
+
+// Reload loads the settings into UserSettings if the file changed since the
+// last call and reports whether it did. Invalid settings are returned as an
+// error and leave UserSettings unchanged.
+func (r *Reloader) Reload() (bool, error) {
+	modTime, size := r.stat()
+	if modTime.Equal(r.modTime) && size == r.size {
+		return false, nil
+	}
+	r.modTime, r.size = modTime, size
+
+	settings, err := LoadSettings(r.path)
+	if err != nil {
+		return false, err
+	}
+	UserSettings = settings
+	return true, nil
+}
+
+// stat returns the modification time and size of the file, zero if missing.
+func (r *Reloader) stat() (time.Time, int64) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return time.Time{}, -1
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,571 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// Settings holds the user preferences read from config.yaml in ConfigDir.
+type Settings struct {
+	AI         AISettings         `yaml:"ai"`
+	Navigation NavigationSettings `yaml:"navigation"`
+	Dotfiles   DotfilesSettings   `yaml:"dotfiles"`
+	Project    ProjectSettings    `yaml:"project"`
+	Git        GitSettings        `yaml:"git"`
+	Standup    StandupSettings    `yaml:"standup"`
+	// Editor is the editor files are opened in, such as "nvim" or
+	// "code --wait". Defaults to $VISUAL, $EDITOR or the first installed
+	// of EditorCandidates.
+	Editor           string   `yaml:"editor"`
+	EditorCandidates []string `yaml:"editor_candidates"`
+
+	Notifications NotificationSettings `yaml:"notifications"`
+	Confirm       ConfirmSettings      `yaml:"confirm"`
+	Privacy       PrivacySettings      `yaml:"privacy"`
+	// Theme is the color theme: default, solarized or monochrome.
+	Theme string `yaml:"theme"`
+	// Locale is the language of messages, such as "de". Defaults to the
+	// language of LC_ALL, LC_MESSAGES or LANG.
+	Locale string `yaml:"locale"`
+}
+
+// AISettings configures the AI assistant.
+type AISettings struct {
+	// Model is the default model, overridden by AURA_MODEL.
+	Model string `yaml:"model"`
+	// Fallback lists the models tried in order when the model is unavailable
+	// or out of quota.
+	Fallback []FallbackModel `yaml:"fallback"`
+	// Embeddings configures the model used for semantic search.
+	Embeddings EmbeddingSettings `yaml:"embeddings"`
+	// Language is the language of answers, such as "de" or "Japanese".
+	// Empty answers in the language of the question.
+	Language string `yaml:"language"`
+	// CommitLanguage is the language of generated commit messages, English
+	// if empty.
+	CommitLanguage string `yaml:"commit_language"`
+	// Deterministic asks for reproducible answers: temperature 0 and a fixed
+	// seed where the provider supports one.
+	Deterministic bool `yaml:"deterministic"`
+}
+
+// FallbackModel is a model tried when the models before it failed.
+type FallbackModel struct {
+	Model string `yaml:"model"`
+	// URL is the API base URL, such as a local Ollama server. Defaults to the
+	// URL of the default model.
+	URL string `yaml:"url"`
+	// APIKeyEnv names the environment variable holding the API key. Defaults
+	// to the key of the default model when URL is not set.
+	APIKeyEnv string `yaml:"api_key_env"`
+}
+
+// NavigationSettings configures how `aura go` finds directories.
+type NavigationSettings struct {
+	// ProjectRoots are the base directories that are indexed.
+	ProjectRoots []string `yaml:"project_roots"`
+	// ScanDepth limits how many levels below a base directory are indexed.
+	ScanDepth int `yaml:"scan_depth"`
+	// Exclude lists glob patterns of directories that are not indexed.
+	Exclude []string `yaml:"exclude"`
+	// RefreshInterval is how old the directory index may get before it is
+	// rebuilt in the background, e.g. "30m".
+	RefreshInterval string `yaml:"refresh_interval"`
+	// Mounts maps cloud storage URIs such as s3://bucket to the directories
+	// they are mounted at, e.g. with s3fs, so 'aura go' enters them instead
+	// of listing them.
+	Mounts map[string]string `yaml:"mounts"`
+}
+
+// DotfilesSettings configures `aura dotfiles`.
+type DotfilesSettings struct {
+	// Repo is the directory of the dotfiles repository.
+	Repo string `yaml:"repo"`
+}
+
+// ProjectSettings configures `aura project`.
+type ProjectSettings struct {
+	// Host is the VCS host of module paths and repository URLs, such as
+	// gitlab.com.
+	Host string `yaml:"host"`
+	// Owner is the user or organization on the host.
+	Owner string `yaml:"owner"`
+}
+
+// GitSettings configures `aura git`. The Git configuration of a repository
+// overrides them with aura.issuePattern and aura.issueTrailer.
+type GitSettings struct {
+	// IssuePattern is a regular expression finding the issue of a branch in
+	// its name; its first group, if any, is the reference. By default Jira
+	// keys such as ABC-123 and GitHub issues such as 42 in fix/42-crash are
+	// found. "off" turns issue references off.
+	IssuePattern string `yaml:"issue_pattern"`
+	// IssueTrailer is the trailer of commit messages referencing the issue,
+	// "Refs" by default.
+	IssueTrailer string `yaml:"issue_trailer"`
+	// CommitStyle is the style of generated commit messages: conventional,
+	// the default, such as "fix(db): handle timeouts", or plain, such as
+	// "Handle database timeouts".
+	CommitStyle string `yaml:"commit_style"`
+	// Forges maps the hosts of self-hosted forges to their kind, github or
+	// gitlab, such as git.example.com: gitlab. Their open issues and pull
+	// requests are fetched with GITHUB_TOKEN or GITLAB_TOKEN.
+	Forges map[string]string `yaml:"forges"`
+}
+
+// StandupSettings configures `aura standup`.
+type StandupSettings struct {
+	// Repos are the repositories whose commits are summarized, besides those
+	// of the directories visited.
+	Repos []string `yaml:"repos"`
+}
+
+// NotificationSettings configures the desktop notifications shown when long
+// operations finish.
+type NotificationSettings struct {
+	// Disabled turns all notifications off.
+	Disabled bool `yaml:"disabled"`
+	// After is how long an operation must run before its end is notified,
+	// e.g. "30s".
+	After string `yaml:"after"`
+	// Commands overrides After for commands such as exec, watch and ai, the
+	// AI assistant. "off" turns their notifications off.
+	Commands map[string]string `yaml:"commands"`
+}
+
+// ConfirmSettings configures when commands ask before they act.
+type ConfirmSettings struct {
+	// Policy is "always", "never" or "destructive", the default, which asks
+	// only before destructive actions and before acting on something not
+	// reviewed yet, such as generated commit messages.
+	Policy string `yaml:"policy"`
+	// Commands overrides Policy for commands such as do, exec, "git commit",
+	// "git split", "git stash", "git cleanup", "gen tests", "gen docs",
+	// refactor, query, "api call", "ports kill", project and uninstall.
+	Commands map[string]string `yaml:"commands"`
+}
+
+// PrivacySettings configures what context the AI assistant is sent. All of
+// it is sent unless turned off with false.
+type PrivacySettings struct {
+	// Paths sends absolute paths, such as the working directory; false
+	// replaces them with <path>.
+	Paths *bool `yaml:"paths"`
+	// FileContents sends the contents of files, diffs and piped input; false
+	// makes the commands sending them fail instead.
+	FileContents *bool `yaml:"file_contents"`
+	// EnvNames names the variables whose values are replaced because of
+	// EnvValues, such as $GITHUB_TOKEN; false replaces them with <redacted>.
+	EnvNames *bool `yaml:"env_names"`
+	// EnvValues sends values of environment variables found in prompts;
+	// false replaces them.
+	EnvValues *bool `yaml:"env_values"`
+	// GitRemotes sends the URLs of the remotes of the current repository;
+	// false replaces them with <git remote>.
+	GitRemotes *bool `yaml:"git_remotes"`
+}
+
+// UserSettings holds the settings loaded by Initialize.
+var UserSettings Settings
+
+// SettingsProblems are the unknown settings and invalid values Initialize
+// found in the settings file. The invalid values are left at their defaults.
+var SettingsProblems []Problem
+
+// defaultProjectRoots are scanned when no project roots are configured.
+var defaultProjectRoots = []string{"~/code", "~/projects", "~/src", "~/dev", "~/workspace", "~/repos"}
+
+// defaultScanDepth is used when no scan depth is configured.
+const defaultScanDepth = 3
+
+// defaultRefreshInterval is used when no valid refresh interval is configured.
+const defaultRefreshInterval = time.Hour
+
+// EmbeddingSettings configures the embedding model, which may be served by a
+// different API than the chat models, such as a local Ollama server.
+type EmbeddingSettings struct {
+	// Model is the embedding model, overridden by AURA_EMBEDDING_MODEL.
+	Model string `yaml:"model"`
+	// URL is the API base URL. Defaults to the URL of the chat models.
+	URL string `yaml:"url"`
+	// APIKeyEnv names the environment variable holding the API key. Defaults
+	// to the key of the chat models when URL is not set.
+	APIKeyEnv string `yaml:"api_key_env"`
+}
+
+// GetSettingsFile returns the path of the settings file.
+func GetSettingsFile() string {
+	return filepath.Join(ConfigDir, "config.yaml")
+}
+
+// LoadSettings reads the settings file at path. A missing file yields the
+// default settings. Unknown settings and invalid values are returned as a
+// *SettingsError along with the settings, in which invalid values are left
+// at their defaults.
+func LoadSettings(path string) (Settings, error) {
+	var settings Settings
+	err := loadSettingsFile(path, &settings)
+	return settings, err
+}
+
+// loadSettingsFile decodes the settings file at path into settings, a
+// pointer to a settings struct, like LoadSettings.
+func loadSettingsFile(path string, settings any) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return failure.New(failure.Config, "failed to read %s: %w", path, err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return failure.New(failure.Config, "invalid settings in %s: %w", path, err)
+	}
+	if document.Kind == 0 {
+		return nil
+	}
+	problems := validateSettings(document.Content[0], reflect.TypeOf(settings).Elem())
+	if err := document.Decode(settings); err != nil {
+		return failure.New(failure.Config, "invalid settings in %s: %w", path, err)
+	}
+	if len(problems) > 0 {
+		return &SettingsError{Path: path, Problems: problems}
+	}
+	return nil
+}
+
+// SetSetting sets the value of a dotted key such as "ai.model" in the settings
+// file at path, creating the file and missing sections. Comments and other
+// settings in the file are kept. Concurrent writers are serialized by a lock
+// and the file is replaced atomically.
+func SetSetting(path, key, value string) error {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return failure.Wrap(failure.Config, err)
+	}
+	defer unlock()
+
+	var document yaml.Node
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("invalid settings in %s: %w", path, err)
+	}
+	if document.Kind == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	node := document.Content[0]
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("invalid settings in %s: %s is not a section", path, strings.Join(parts[:i], "."))
+		}
+
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == part {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, child)
+		}
+		node = child
+	}
+	*node = yaml.Node{Kind: yaml.ScalarNode, Value: value, LineComment: node.LineComment}
+
+	var output bytes.Buffer
+	encoder := yaml.NewEncoder(&output)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	var check yaml.Node
+	if err := yaml.Unmarshal(output.Bytes(), &check); err != nil {
+		return failure.New(failure.UserInput, "invalid value '%s' for %s: %w", value, key, err)
+	}
+	// Only the changed setting must be valid, others are reported on load
+	for _, problem := range validateSettings(check.Content[0], reflect.TypeOf(Settings{})) {
+		if problem.Key == key || strings.HasPrefix(problem.Key, key+".") || strings.HasPrefix(problem.Key, key+"[") {
+			return failure.New(failure.UserInput, "%s: %s", problem.Key, problem.Message)
+		}
+	}
+	var settings Settings
+	if err := check.Decode(&settings); err != nil {
+		return failure.New(failure.UserInput, "invalid value '%s' for %s: %w", value, key, err)
+	}
+	return writeFileAtomic(path, output.Bytes(), 0644)
+}
+
+// GetProjectRoots returns the existing base directories scanned for projects.
+// AURA_PROJECT_ROOTS, a list separated like PATH, overrides the settings file.
+func GetProjectRoots() []string {
+	roots := UserSettings.Navigation.ProjectRoots
+	if env := envvar.ProjectRoots.Get(); env != "" {
+		roots = filepath.SplitList(env)
+	}
+	if len(roots) == 0 {
+		roots = defaultProjectRoots
+	}
+
+	var existing []string
+	for _, root := range roots {
+		root = ExpandHome(strings.TrimSpace(root))
+		if root == "" {
+			continue
+		}
+		if info, err := os.Stat(root); err == nil && info.IsDir() {
+			existing = append(existing, root)
+		}
+	}
+	return existing
+}
+
+// GetScanDepth returns the maximum directory depth scanned below project roots.
+func GetScanDepth() int {
+	if UserSettings.Navigation.ScanDepth > 0 {
+		return UserSettings.Navigation.ScanDepth
+	}
+	return defaultScanDepth
+}
+
+// GetIndexExclude returns the configured glob patterns of directories that
+// are not indexed.
+func GetIndexExclude() []string {
+	return UserSettings.Navigation.Exclude
+}
+
+// GetIndexRefreshInterval returns how old the directory index may get before
+// it is rebuilt.
+func GetIndexRefreshInterval() time.Duration {
+	interval, err := time.ParseDuration(UserSettings.Navigation.RefreshInterval)
+	if err != nil || interval <= 0 {
+		return defaultRefreshInterval
+	}
+	return interval
+}
+
+// defaultProjectHost is used when no VCS host is configured.
+const defaultProjectHost = "github.com"
+
+// GetProjectHost returns the VCS host of new projects.
+func GetProjectHost() string {
+	if host := strings.Trim(UserSettings.Project.Host, "/ "); host != "" {
+		return host
+	}
+	return defaultProjectHost
+}
+
+// defaultDotfilesRepo is used when no dotfiles repository is configured.
+const defaultDotfilesRepo = "~/.dotfiles"
+
+// GetDotfilesRepo returns the directory of the dotfiles repository.
+func GetDotfilesRepo() string {
+	if UserSettings.Dotfiles.Repo != "" {
+		return ExpandHome(UserSettings.Dotfiles.Repo)
+	}
+	return ExpandHome(defaultDotfilesRepo)
+}
+
+// GetStandupRepos returns the configured repositories of 'aura standup'.
+func GetStandupRepos() []string {
+	var repos []string
+	for _, repo := range UserSettings.Standup.Repos {
+		repos = append(repos, ExpandHome(repo))
+	}
+	return repos
+}
+
+// defaultNotifyAfter is used when no valid notification threshold is
+// configured.
+const defaultNotifyAfter = 10 * time.Second
+
+// GetNotifyAfter returns how long an operation of command must run before a
+// desktop notification shows that it finished. It returns false if
+// notifications are off for command.
+func GetNotifyAfter(command string) (time.Duration, bool) {
+	settings := UserSettings.Notifications
+	if settings.Disabled {
+		return 0, false
+	}
+
+	value := settings.After
+	if override, ok := settings.Commands[command]; ok {
+		value = override
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "off", "never", "false", "no":
+		return 0, false
+	}
+
+	after, err := time.ParseDuration(value)
+	if err != nil || after < 0 {
+		return defaultNotifyAfter, true
+	}
+	return after, true
+}
+
+// Confirmation policies.
+const (
+	ConfirmAlways      = "always"
+	ConfirmNever       = "never"
+	ConfirmDestructive = "destructive"
+)
+
+// GetConfirmPolicy returns the confirmation policy of command. Invalid
+// policies yield ConfirmDestructive.
+func GetConfirmPolicy(command string) string {
+	settings := UserSettings.Confirm
+	value := settings.Policy
+	if override, ok := settings.Commands[command]; ok {
+		value = override
+	}
+
+	switch policy := strings.ToLower(strings.TrimSpace(value)); policy {
+	case ConfirmAlways, ConfirmNever:
+		return policy
+	}
+	return ConfirmDestructive
+}
+
+// Commit styles of generated commit messages.
+const (
+	CommitConventional = "conventional"
+	CommitPlain        = "plain"
+)
+
+// GetCommitStyle returns the style of generated commit messages: the
+// settings, the team settings or CommitConventional.
+func GetCommitStyle() string {
+	style := UserSettings.Git.CommitStyle
+	if style == "" {
+		style = Team.Git.CommitStyle
+	}
+	if strings.EqualFold(strings.TrimSpace(style), CommitPlain) {
+		return CommitPlain
+	}
+	return CommitConventional
+}
+
+// Privacy tells which kinds of context the AI assistant is sent, see
+// PrivacySettings.
+type Privacy struct {
+	Paths        bool
+	FileContents bool
+	EnvNames     bool
+	EnvValues    bool
+	GitRemotes   bool
+}
+
+// GetPrivacy returns which kinds of context the AI assistant is sent.
+func GetPrivacy() Privacy {
+	settings := UserSettings.Privacy
+	send := func(setting *bool) bool {
+		return setting == nil || *setting
+	}
+	return Privacy{
+		Paths:        send(settings.Paths),
+		FileContents: send(settings.FileContents),
+		EnvNames:     send(settings.EnvNames),
+		EnvValues:    send(settings.EnvValues),
+		GitRemotes:   send(settings.GitRemotes),
+	}
+}
+
+// ExpandHome replaces a leading ~ in path with the user's home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// NormalizePath returns path as an absolute, clean path with ~ expanded and
+// symbolic links resolved, so a directory is stored the same way however it
+// was reached. On Windows the case follows the file system. Paths that don't
+// exist are only cleaned, paths of the other side of WSL are translated, see
+// LocalPath, and URIs and paths of other systems are kept.
+func NormalizePath(path string) string {
+	path = LocalPath(ExpandHome(path))
+	if strings.Contains(path, "://") || isForeignPath(path, platform.Current().IsWindows()) {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if platform.Current().IsWindows() && len(path) >= 2 && path[1] == ':' {
+		path = strings.ToUpper(path[:1]) + path[1:]
+	}
+	return path
+}
+
+// GetSetting returns the value of a dotted key such as "ai.model" in the
+// settings file at path, formatted as YAML for sections, and whether it is set.
+func GetSetting(path, key string) (string, bool, error) {
+	var document yaml.Node
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", false, failure.New(failure.Config, "failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return "", false, failure.New(failure.Config, "invalid settings in %s: %w", path, err)
+	}
+	if document.Kind == 0 {
+		return "", false, nil
+	}
+
+	node := document.Content[0]
+	for _, part := range strings.Split(key, ".") {
+		if node.Kind != yaml.MappingNode {
+			return "", false, nil
+		}
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == part {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if child == nil {
+			return "", false, nil
+		}
+		node = child
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, true, nil
+	}
+
+	var output bytes.Buffer
+	encoder := yaml.NewEncoder(&output)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", false, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return strings.TrimRight(output.String(), "\n"), true, nil
+}
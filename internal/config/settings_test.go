@@ -0,0 +1,349 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadSettings(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Missing file yields defaults
+	settings, err := LoadSettings(filepath.Join(tempDir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if len(settings.Navigation.ProjectRoots) != 0 {
+		t.Errorf("Expected no project roots, got %v", settings.Navigation.ProjectRoots)
+	}
+
+	path := filepath.Join(tempDir, "config.yaml")
+	content := "navigation:\n  project_roots:\n    - ~/code\n    - /srv/work\n  scan_depth: 2\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	settings, err = LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if len(settings.Navigation.ProjectRoots) != 2 || settings.Navigation.ProjectRoots[1] != "/srv/work" {
+		t.Errorf("Unexpected project roots: %v", settings.Navigation.ProjectRoots)
+	}
+	if settings.Navigation.ScanDepth != 2 {
+		t.Errorf("ScanDepth = %d, want 2", settings.Navigation.ScanDepth)
+	}
+
+	if err := os.WriteFile(path, []byte("navigation: [\n"), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	if _, err := LoadSettings(path); err == nil {
+		t.Error("Expected error for invalid settings")
+	}
+}
+
+func TestGetProjectRoots(t *testing.T) {
+	tempDir := t.TempDir()
+	missing := filepath.Join(tempDir, "missing")
+
+	originalSettings := UserSettings
+	defer func() { UserSettings = originalSettings }()
+
+	UserSettings = Settings{Navigation: NavigationSettings{ProjectRoots: []string{tempDir, missing}}}
+	t.Setenv("AURA_PROJECT_ROOTS", "")
+
+	roots := GetProjectRoots()
+	if len(roots) != 1 || roots[0] != tempDir {
+		t.Errorf("GetProjectRoots() = %v, want [%s]", roots, tempDir)
+	}
+
+	// The environment overrides the settings file
+	other := t.TempDir()
+	t.Setenv("AURA_PROJECT_ROOTS", other+string(os.PathListSeparator)+missing)
+	roots = GetProjectRoots()
+	if len(roots) != 1 || roots[0] != other {
+		t.Errorf("GetProjectRoots() = %v, want [%s]", roots, other)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("No home directory")
+	}
+
+	if got := ExpandHome("~/code"); got != filepath.Join(home, "code") {
+		t.Errorf("ExpandHome(~/code) = %q", got)
+	}
+	if got := ExpandHome("/tmp/~code"); got != "/tmp/~code" {
+		t.Errorf("ExpandHome(/tmp/~code) = %q", got)
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	project := filepath.Join(dir, "project")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(project, link); err != nil {
+		t.Skipf("Symlinks unsupported: %v", err)
+	}
+
+	for _, path := range []string{project, link, project + string(filepath.Separator), filepath.Join(dir, "other", "..", "link")} {
+		if got := NormalizePath(path); got != project {
+			t.Errorf("NormalizePath(%q) = %q, want %q", path, got, project)
+		}
+	}
+
+	missing := filepath.Join(dir, "missing", "..", "gone")
+	if got := NormalizePath(missing); got != filepath.Join(dir, "gone") {
+		t.Errorf("NormalizePath(%q) = %q", missing, got)
+	}
+}
+
+func TestGetIndexRefreshInterval(t *testing.T) {
+	originalSettings := UserSettings
+	defer func() { UserSettings = originalSettings }()
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", time.Hour},
+		{"30m", 30 * time.Minute},
+		{"soon", time.Hour},
+		{"-5m", time.Hour},
+	}
+
+	for _, tt := range tests {
+		UserSettings.Navigation.RefreshInterval = tt.value
+		if got := GetIndexRefreshInterval(); got != tt.want {
+			t.Errorf("GetIndexRefreshInterval() with %q = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGetNotifyAfter(t *testing.T) {
+	originalSettings := UserSettings
+	defer func() { UserSettings = originalSettings }()
+
+	UserSettings.Notifications = NotificationSettings{
+		After:    "30s",
+		Commands: map[string]string{"watch": "off", "ai": "5s", "exec": "later"},
+	}
+
+	tests := []struct {
+		command string
+		after   time.Duration
+		enabled bool
+	}{
+		{"git", 30 * time.Second, true},
+		{"ai", 5 * time.Second, true},
+		{"watch", 0, false},
+		{"exec", 10 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		after, enabled := GetNotifyAfter(tt.command)
+		if after != tt.after || enabled != tt.enabled {
+			t.Errorf("GetNotifyAfter(%s) = %v, %v, want %v, %v", tt.command, after, enabled, tt.after, tt.enabled)
+		}
+	}
+
+	UserSettings.Notifications.Disabled = true
+	if _, enabled := GetNotifyAfter("git"); enabled {
+		t.Error("Expected notifications to be off")
+	}
+}
+
+func TestSetSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	// Missing file is created
+	if err := SetSetting(path, "ai.model", "gpt-4o"); err != nil {
+		t.Fatalf("SetSetting() error = %v", err)
+	}
+	settings, err := LoadSettings(path)
+	if err != nil || settings.AI.Model != "gpt-4o" {
+		t.Fatalf("LoadSettings() = %+v, %v", settings, err)
+	}
+
+	content := "# Aura configuration\nai:\n  provider: \"openai\"\n  model: \"gpt-3.5-turbo\" # default model\nnavigation:\n  scan_depth: 2\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	if err := SetSetting(path, "ai.model", "gpt-4o-mini"); err != nil {
+		t.Fatalf("SetSetting() error = %v", err)
+	}
+
+	// ai.provider is unknown, but kept like the comments
+	settings, err = LoadSettings(path)
+	var invalid *SettingsError
+	if !errors.As(err, &invalid) || len(invalid.Problems) != 1 || invalid.Problems[0].Key != "ai.provider" {
+		t.Fatalf("LoadSettings() error = %v, want ai.provider unknown", err)
+	}
+	if settings.AI.Model != "gpt-4o-mini" || settings.Navigation.ScanDepth != 2 {
+		t.Errorf("Unexpected settings after update: %+v", settings)
+	}
+
+	updated, _ := os.ReadFile(path)
+	for _, kept := range []string{"# Aura configuration", "provider: \"openai\"", "# default model"} {
+		if !strings.Contains(string(updated), kept) {
+			t.Errorf("Expected %q to be kept, got:\n%s", kept, updated)
+		}
+	}
+
+	if err := SetSetting(path, "navigation.scan_depth.value", "1"); err == nil {
+		t.Error("Expected error when a value is used as a section")
+	}
+}
+
+func TestGetConfirmPolicy(t *testing.T) {
+	originalSettings := UserSettings
+	defer func() { UserSettings = originalSettings }()
+
+	UserSettings.Confirm = ConfirmSettings{
+		Policy:   "Never",
+		Commands: map[string]string{"uninstall": "always", "do": "destructive-only", "exec": "sometimes"},
+	}
+
+	for command, want := range map[string]string{
+		"git commit": ConfirmNever,
+		"uninstall":  ConfirmAlways,
+		"do":         ConfirmDestructive,
+		"exec":       ConfirmDestructive,
+	} {
+		if got := GetConfirmPolicy(command); got != want {
+			t.Errorf("GetConfirmPolicy(%s) = %s, want %s", command, got, want)
+		}
+	}
+
+	UserSettings.Confirm = ConfirmSettings{}
+	if got := GetConfirmPolicy("do"); got != ConfirmDestructive {
+		t.Errorf("GetConfirmPolicy() without settings = %s, want %s", got, ConfirmDestructive)
+	}
+}
+
+func TestGetPrivacy(t *testing.T) {
+	originalSettings := UserSettings
+	defer func() { UserSettings = originalSettings }()
+
+	UserSettings.Privacy = PrivacySettings{}
+	if got := GetPrivacy(); got != (Privacy{Paths: true, FileContents: true, EnvNames: true, EnvValues: true, GitRemotes: true}) {
+		t.Errorf("GetPrivacy() without settings = %+v, want everything sent", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("privacy:\n  paths: false\n  env_values: no\n  git_remotes: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() failed: %v", err)
+	}
+	UserSettings = settings
+	if got := GetPrivacy(); got != (Privacy{Paths: false, FileContents: true, EnvNames: true, EnvValues: false, GitRemotes: true}) {
+		t.Errorf("GetPrivacy() = %+v", got)
+	}
+}
+
+func TestSetSettingConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- SetSetting(path, fmt.Sprintf("confirm.commands.cmd%d", i), "never")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("SetSetting() error = %v", err)
+		}
+	}
+
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if len(settings.Confirm.Commands) != 10 {
+		t.Errorf("Expected all 10 settings to be kept, got %v", settings.Confirm.Commands)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("Expected the lock to be released, got %v", err)
+	}
+
+	// Locks left by crashed processes are broken
+	if err := os.WriteFile(path+".lock", nil, 0644); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(path+".lock", old, old)
+	if err := SetSetting(path, "ai.model", "gpt-4o"); err != nil {
+		t.Errorf("SetSetting() with a stale lock error = %v", err)
+	}
+
+	if err := SetSetting(path, "navigation.scan_depth", "deep"); err == nil {
+		t.Error("Expected error for a value of the wrong type")
+	}
+}
+
+func TestGetSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("ai:\n  model: gpt-4o # default\n  fallback:\n    - model: gpt-4o-mini\n"), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	if value, ok, err := GetSetting(path, "ai.model"); err != nil || !ok || value != "gpt-4o" {
+		t.Errorf("GetSetting(ai.model) = %q, %v, %v", value, ok, err)
+	}
+	if value, ok, _ := GetSetting(path, "ai.fallback"); !ok || value != "- model: gpt-4o-mini" {
+		t.Errorf("GetSetting(ai.fallback) = %q, %v", value, ok)
+	}
+	for _, key := range []string{"ai.provider", "ai.model.name", "theme"} {
+		if _, ok, err := GetSetting(path, key); ok || err != nil {
+			t.Errorf("GetSetting(%s) = %v, %v, want unset", key, ok, err)
+		}
+	}
+}
+
+func TestReloader(t *testing.T) {
+	originalSettings := UserSettings
+	defer func() { UserSettings = originalSettings }()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	reloader := NewReloader(path)
+	if changed, err := reloader.Reload(); changed || err != nil {
+		t.Errorf("Reload() without changes = %v, %v", changed, err)
+	}
+
+	if err := os.WriteFile(path, []byte("ai:\n  model: gpt-4o\n"), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	if changed, err := reloader.Reload(); !changed || err != nil || UserSettings.AI.Model != "gpt-4o" {
+		t.Errorf("Reload() = %v, %v, model %q", changed, err, UserSettings.AI.Model)
+	}
+
+	if err := os.WriteFile(path, []byte("ai: [broken\n"), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	if _, err := reloader.Reload(); err == nil || UserSettings.AI.Model != "gpt-4o" {
+		t.Errorf("Expected error and previous settings kept, got %v, model %q", err, UserSettings.AI.Model)
+	}
+}
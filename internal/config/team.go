@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TeamFile is the name of the settings file a team commits to its
+// repository.
+const TeamFile = ".aura.yaml"
+
+// TeamSettings are the settings a team shares in the TeamFile of its
+// repository. They rank below the settings of the user: values set in
+// config.yaml, flags or environment variables win over them. Actions add to
+// the detected ones, and banned commands and allowed models apply whatever
+// the user set.
+//
+// Settings that send data elsewhere or run programs on their own, such as
+// API URLs or the editor, can't be set by a repository. Its actions are
+// only offered once the user approved their commands.
+type TeamSettings struct {
+	AI  TeamAISettings  `yaml:"ai"`
+	Git TeamGitSettings `yaml:"git"`
+	// Actions are offered by aura do, before the detected ones.
+	Actions []TeamAction `yaml:"actions"`
+	// BannedCommands are commands Aura refuses to run, such as
+	// "git push --force*". * matches any text; commands joined by &&, ||,
+	// ; or | are checked one by one.
+	BannedCommands []string `yaml:"banned_commands"`
+	// Variables are the default values of template variables of aura
+	// project, by name.
+	Variables map[string]string `yaml:"variables"`
+}
+
+// TeamAISettings is the model policy of a team.
+type TeamAISettings struct {
+	// Model is used unless the user chose one.
+	Model string `yaml:"model"`
+	// AllowedModels are the only chat models requests may be sent to, such
+	// as gpt-4o*. * matches any text. Empty allows all models.
+	AllowedModels []string `yaml:"allowed_models"`
+}
+
+// TeamGitSettings configures aura git for a team, see GitSettings.
+type TeamGitSettings struct {
+	CommitStyle  string `yaml:"commit_style"`
+	IssuePattern string `yaml:"issue_pattern"`
+	IssueTrailer string `yaml:"issue_trailer"`
+}
+
+// TeamAction is an action of aura do.
+type TeamAction struct {
+	Name    string `yaml:"name"`
+	Command string `yaml:"command"`
+	// Shell runs the command in the calling shell, such as cd or source.
+	Shell bool `yaml:"shell"`
+}
+
+// Team holds the team settings loaded by Initialize, TeamSettingsFile is
+// their file, "" if there is none.
+var (
+	Team             TeamSettings
+	TeamSettingsFile string
+)
+
+// TeamProblems are the unknown settings and invalid values Initialize found
+// in TeamSettingsFile, like SettingsProblems.
+var TeamProblems []Problem
+
+// FindTeamFile returns the TeamFile in dir or the closest of its parents
+// within the same repository, or "" if there is none. Outside repositories
+// only dir is looked at.
+func FindTeamFile(dir string) string {
+	root := repositoryRoot(dir)
+	for {
+		path := filepath.Join(dir, TeamFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if root == "" || dir == root || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// repositoryRoot returns dir or the closest of its parents holding a .git,
+// or "" if there is none.
+func repositoryRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadTeamSettings reads the team settings file at path, like LoadSettings.
+func LoadTeamSettings(path string) (TeamSettings, error) {
+	var settings TeamSettings
+	err := loadSettingsFile(path, &settings)
+	return settings, err
+}
+
+// ModelAllowed reports whether the team settings allow requests to model.
+func ModelAllowed(model string) bool {
+	if len(Team.AI.AllowedModels) == 0 {
+		return true
+	}
+	for _, pattern := range Team.AI.AllowedModels {
+		if globMatch(pattern, model) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandSeparator splits compound commands such as "make && git push".
+var commandSeparator = regexp.MustCompile(`&&|\|\||[;|\n]`)
+
+// BannedCommand returns the banned command of the team settings command
+// matches, or "" if it may run.
+func BannedCommand(command string) string {
+	for _, part := range commandSeparator.Split(command, -1) {
+		part = strings.Join(strings.Fields(part), " ")
+		if part == "" {
+			continue
+		}
+		for _, banned := range Team.BannedCommands {
+			if globMatch(strings.Join(strings.Fields(banned), " "), part) {
+				return banned
+			}
+		}
+	}
+	return ""
+}
+
+// globMatch reports whether text matches pattern as a whole, where *
+// matches any text.
+func globMatch(pattern, text string) bool {
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(text)
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindTeamFile(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "shop")
+	service := filepath.Join(repo, "services", "api")
+	os.MkdirAll(filepath.Join(repo, ".git"), 0755)
+	os.MkdirAll(service, 0755)
+
+	if got := FindTeamFile(service); got != "" {
+		t.Errorf("FindTeamFile() = %s without a team file", got)
+	}
+
+	// Files above the repository don't belong to it
+	os.WriteFile(filepath.Join(root, TeamFile), []byte("ai:\n  model: gpt-4o\n"), 0644)
+	if got := FindTeamFile(service); got != "" {
+		t.Errorf("FindTeamFile() = %s, outside the repository", got)
+	}
+
+	os.WriteFile(filepath.Join(repo, TeamFile), nil, 0644)
+	if got := FindTeamFile(service); got != filepath.Join(repo, TeamFile) {
+		t.Errorf("FindTeamFile() = %s, want the file at the repository root", got)
+	}
+	os.WriteFile(filepath.Join(service, TeamFile), nil, 0644)
+	if got := FindTeamFile(service); got != filepath.Join(service, TeamFile) {
+		t.Errorf("FindTeamFile() = %s, want the closest file", got)
+	}
+
+	// Outside repositories only the directory itself counts
+	plain := filepath.Join(root, "notes")
+	os.MkdirAll(plain, 0755)
+	if got := FindTeamFile(plain); got != "" {
+		t.Errorf("FindTeamFile() = %s outside repositories", got)
+	}
+	if got := FindTeamFile(root); got != filepath.Join(root, TeamFile) {
+		t.Errorf("FindTeamFile() = %s, want the file of the directory", got)
+	}
+}
+
+func TestLoadTeamSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), TeamFile)
+	content := `ai:
+  model: gpt-4o
+  allowed_models: [gpt-4o*, "bad model"]
+git:
+  commit_style: plain
+actions:
+  - name: Run the API
+    command: make run-api
+banned_commands:
+  - git push --force*
+variables:
+  license: MIT
+editor: rm -rf ~
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	settings, err := LoadTeamSettings(path)
+	var invalid *SettingsError
+	if !errors.As(err, &invalid) || len(invalid.Problems) != 2 {
+		t.Fatalf("LoadTeamSettings() error = %v, want the model and editor reported", err)
+	}
+	if invalid.Problems[0].Key != "ai.allowed_models[1]" || invalid.Problems[1].Key != "editor" {
+		t.Errorf("problems = %+v", invalid.Problems)
+	}
+	if settings.AI.Model != "gpt-4o" || settings.Git.CommitStyle != "plain" || len(settings.Actions) != 1 || settings.Variables["license"] != "MIT" {
+		t.Errorf("LoadTeamSettings() = %+v", settings)
+	}
+}
+
+func TestTeamPolicies(t *testing.T) {
+	original := Team
+	defer func() { Team = original }()
+
+	Team = TeamSettings{}
+	if !ModelAllowed("gpt-4o") || BannedCommand("git push --force") != "" {
+		t.Error("Expected everything allowed without team settings")
+	}
+
+	Team.AI.AllowedModels = []string{"gpt-4o*", "llama3.1:8b"}
+	for model, want := range map[string]bool{"gpt-4o": true, "gpt-4o-mini": true, "llama3.1:8b": true, "gpt-3.5-turbo": false, "llama3.1:70b": false} {
+		if got := ModelAllowed(model); got != want {
+			t.Errorf("ModelAllowed(%s) = %v, want %v", model, got, want)
+		}
+	}
+
+	Team.BannedCommands = []string{"git push --force*", "rm -rf /", "terraform  destroy*"}
+	for command, want := range map[string]string{
+		"git push --force":                      "git push --force*",
+		"make test && git push --force origin":  "git push --force*",
+		"git  push   --force-with-lease":        "git push --force*",
+		"rm -rf /":                              "rm -rf /",
+		"rm -rf /tmp/build":                     "",
+		"terraform destroy -auto-approve | tee": "terraform  destroy*",
+		"git push origin main":                  "",
+		"echo 'git push --force'":               "",
+	} {
+		if got := BannedCommand(command); got != want {
+			t.Errorf("BannedCommand(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestGetCommitStyle(t *testing.T) {
+	originalUser, originalTeam := UserSettings, Team
+	defer func() { UserSettings, Team = originalUser, originalTeam }()
+
+	UserSettings, Team = Settings{}, TeamSettings{}
+	if got := GetCommitStyle(); got != CommitConventional {
+		t.Errorf("GetCommitStyle() = %s by default", got)
+	}
+	Team.Git.CommitStyle = "Plain"
+	if got := GetCommitStyle(); got != CommitPlain {
+		t.Errorf("GetCommitStyle() = %s, want the team style", got)
+	}
+	UserSettings.Git.CommitStyle = CommitConventional
+	if got := GetCommitStyle(); got != CommitConventional {
+		t.Errorf("GetCommitStyle() = %s, want the user's style over the team's", got)
+	}
+}
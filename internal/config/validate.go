@@ -0,0 +1,313 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/i18n"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+// Problem is an invalid or unknown setting in the settings file.
+type Problem struct {
+	// Key is the dotted key of the setting, with the index of list items,
+	// such as "ai.fallback[1].url".
+	Key     string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Location returns where the problem is in the settings file at path, as
+// path:line:column.
+func (p Problem) Location(path string) string {
+	return fmt.Sprintf("%s:%d:%d", path, p.Line, p.Column)
+}
+
+// SettingsError reports the problems of a settings file whose values are
+// otherwise readable. Invalid values fall back to their defaults.
+type SettingsError struct {
+	Path     string
+	Problems []Problem
+}
+
+// Kind returns failure.Config, see failure.KindOf.
+func (e *SettingsError) Kind() failure.Kind {
+	return failure.Config
+}
+
+func (e *SettingsError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = fmt.Sprintf("%s: %s: %s", problem.Location(e.Path), problem.Key, problem.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// settingRules check the values of settings by key. Items of lists are
+// written key[] and values of maps key.*.
+var settingRules = map[string]func(value string) error{
+	"ai.model":                    checkModel,
+	"ai.fallback[].model":         checkModel,
+	"ai.fallback[].url":           checkURL,
+	"ai.fallback[].api_key_env":   checkEnvName,
+	"ai.embeddings.model":         checkModel,
+	"ai.embeddings.url":           checkURL,
+	"ai.embeddings.api_key_env":   checkEnvName,
+	"navigation.scan_depth":       checkRange(1, 20),
+	"navigation.refresh_interval": checkDuration(time.Minute, 30*24*time.Hour),
+	"project.host":                checkHost,
+	"git.issue_pattern":           checkIssuePattern,
+	"git.forges.*":                checkOneOf("github", "gitlab"),
+	"git.commit_style":            checkOneOf(CommitConventional, CommitPlain),
+	"ai.allowed_models[]":         checkModelGlob,
+	"notifications.after":         checkNotifyAfter,
+	"notifications.commands.*":    checkNotifyAfter,
+	"confirm.policy":              checkOneOf(ConfirmAlways, ConfirmNever, ConfirmDestructive),
+	"confirm.commands.*":          checkOneOf(ConfirmAlways, ConfirmNever, ConfirmDestructive),
+	"theme":                       func(value string) error { return checkOneOf(theme.Names()...)(value) },
+	"locale":                      checkLocale,
+	"disable[]":                   checkOneOf(Features...),
+	"api_url":                     checkURL,
+}
+
+// validateSettings checks the settings in node, the root of a settings file
+// decoded into the struct type t, against the known settings and the rules
+// of their values. Invalid values are replaced by null, so they decode to
+// their defaults.
+func validateSettings(node *yaml.Node, t reflect.Type) []Problem {
+	var problems []Problem
+	validateNode(node, t, "", "", &problems)
+	return problems
+}
+
+// validateNode checks node, the value of the setting key of type t. rule is
+// the key in the form of settingRules.
+func validateNode(node *yaml.Node, t reflect.Type, key, rule string, problems *[]Problem) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Tag == "!!null" {
+		return
+	}
+	join := func(parent, child string) string {
+		if parent == "" {
+			return child
+		}
+		return parent + "." + child
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := make(map[string]reflect.Type)
+		var names []string
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			fields[name] = t.Field(i).Type
+			names = append(names, name)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i]
+			fieldType, ok := fields[name.Value]
+			if !ok {
+				message := "unknown setting"
+				if suggestion := closestName(name.Value, names); suggestion != "" {
+					message += fmt.Sprintf(", did you mean %s?", join(key, suggestion))
+				}
+				*problems = append(*problems, Problem{Key: join(key, name.Value), Line: name.Line, Column: name.Column, Message: message})
+				continue
+			}
+			validateNode(node.Content[i+1], fieldType, join(key, name.Value), join(rule, name.Value), problems)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			validateNode(node.Content[i+1], t.Elem(), join(key, node.Content[i].Value), rule+".*", problems)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			validateNode(item, t.Elem(), fmt.Sprintf("%s[%d]", key, i), rule+"[]", problems)
+		}
+	default:
+		check, ok := settingRules[rule]
+		if !ok || node.Kind != yaml.ScalarNode {
+			return
+		}
+		if err := check(node.Value); err != nil {
+			*problems = append(*problems, Problem{Key: key, Line: node.Line, Column: node.Column, Message: err.Error()})
+			*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+		}
+	}
+}
+
+// modelPattern matches model names, such as gpt-4o-mini, llama3.1:8b or
+// openai/gpt-4o.
+var modelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/@+-]*$`)
+
+func checkModel(value string) error {
+	if !modelPattern.MatchString(value) {
+		return fmt.Errorf("'%s' is not a model name, see 'aura models' for those of your provider", value)
+	}
+	return nil
+}
+
+// checkModelGlob accepts model names in which * stands for any text, such
+// as gpt-4o*.
+func checkModelGlob(value string) error {
+	if !modelPattern.MatchString(strings.ReplaceAll(value, "*", "x")) {
+		return fmt.Errorf("'%s' is not a model name, where * matches any text", value)
+	}
+	return nil
+}
+
+func checkURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("'%s' is not an http or https URL, such as http://localhost:11434/v1", value)
+	}
+	return nil
+}
+
+// envNamePattern matches names of environment variables.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func checkEnvName(value string) error {
+	if !envNamePattern.MatchString(value) {
+		return fmt.Errorf("'%s' is not the name of an environment variable, such as OLLAMA_API_KEY", value)
+	}
+	return nil
+}
+
+func checkHost(value string) error {
+	if strings.Contains(value, "://") || strings.ContainsAny(value, " /") {
+		return fmt.Errorf("'%s' is not a host name, such as gitlab.com", value)
+	}
+	return nil
+}
+
+func checkIssuePattern(value string) error {
+	if value == "off" {
+		return nil
+	}
+	if _, err := regexp.Compile(value); err != nil {
+		return fmt.Errorf("invalid regular expression: %v", err)
+	}
+	return nil
+}
+
+func checkNotifyAfter(value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "off", "never", "false", "no":
+		return nil
+	}
+	return checkDuration(0, 24*time.Hour)(value)
+}
+
+func checkLocale(value string) error {
+	language := strings.ToLower(value)
+	if i := strings.IndexAny(language, "_-.@"); i >= 0 {
+		language = language[:i]
+	}
+	if i18n.Normalize(value) != language {
+		return fmt.Errorf("no messages in '%s', available: %s", value, strings.Join(i18n.Locales(), ", "))
+	}
+	return nil
+}
+
+// checkOneOf returns a rule accepting the values, ignoring case.
+func checkOneOf(values ...string) func(string) error {
+	return func(value string) error {
+		for _, allowed := range values {
+			if strings.EqualFold(strings.TrimSpace(value), allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("'%s' is not one of %s", value, strings.Join(values, ", "))
+	}
+}
+
+// checkRange returns a rule accepting whole numbers from min to max.
+func checkRange(min, max int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < min || n > max {
+			return fmt.Errorf("'%s' is not a number from %d to %d", value, min, max)
+		}
+		return nil
+	}
+}
+
+// checkDuration returns a rule accepting durations such as "30s" or "1h"
+// from min to max.
+func checkDuration(min, max time.Duration) func(string) error {
+	return func(value string) error {
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("'%s' is not a duration, such as 30s, 15m or 2h", value)
+		}
+		if d < min || d > max {
+			return fmt.Errorf("%s is out of range, use %s to %s", value, formatDuration(min), formatDuration(max))
+		}
+		return nil
+	}
+}
+
+// formatDuration formats d without zero minutes and seconds, such as "1h".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	s = strings.TrimSuffix(s, "0s")
+	s = strings.TrimSuffix(s, "0m")
+	if s == "" {
+		return "0s"
+	}
+	return s
+}
+
+// closestName returns the name in names that name is most likely a typo
+// of, or "" if none is close.
+func closestName(name string, names []string) string {
+	best, bestDistance := "", len(name)/2+1
+	for _, candidate := range names {
+		if distance := editDistance(strings.ToLower(name), candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance of a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
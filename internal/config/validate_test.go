@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestLoadSettingsProblems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `ai:
+  model: gpt-4o
+  modle: gpt-4o-mini
+  fallback:
+    - model: llama3.1:8b
+      url: localhost:11434
+navigation:
+  scan_depth: 50
+  refresh_interval: 10s
+confirm:
+  policy: sometimes
+  commands:
+    exec: never
+    do: ALWAYS
+notifications:
+  commands:
+    watch: soon
+git:
+  issue_pattern: "([A-Z]+"
+  forges:
+    git.example.com: gitea
+theme: neon
+locale: de_DE.UTF-8
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	settings, err := LoadSettings(path)
+	var invalid *SettingsError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("LoadSettings() error = %v, want a *SettingsError", err)
+	}
+	if failure.KindOf(err) != failure.Config {
+		t.Errorf("KindOf() = %v, want %v", failure.KindOf(err), failure.Config)
+	}
+
+	want := []struct {
+		key       string
+		line, col int
+		message   string
+	}{
+		{"ai.modle", 3, 3, "did you mean ai.model?"},
+		{"ai.fallback[0].url", 6, 12, "not an http or https URL"},
+		{"navigation.scan_depth", 8, 15, "not a number from 1 to 20"},
+		{"navigation.refresh_interval", 9, 21, "out of range, use 1m to 720h"},
+		{"confirm.policy", 11, 11, "not one of always, never, destructive"},
+		{"notifications.commands.watch", 17, 12, "not a duration"},
+		{"git.issue_pattern", 19, 18, "invalid regular expression"},
+		{"git.forges.git.example.com", 21, 22, "not one of github, gitlab"},
+		{"theme", 22, 8, "not one of"},
+	}
+	if len(invalid.Problems) != len(want) {
+		t.Fatalf("Problems = %+v, want %d", invalid.Problems, len(want))
+	}
+	for i, problem := range invalid.Problems {
+		w := want[i]
+		if problem.Key != w.key || problem.Line != w.line || problem.Column != w.col || !strings.Contains(problem.Message, w.message) {
+			t.Errorf("Problems[%d] = %+v, want %s at %d:%d with %q", i, problem, w.key, w.line, w.col, w.message)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), path+":3:3: ai.modle: unknown setting") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	// Valid values are kept, invalid ones left at their defaults
+	if settings.AI.Model != "gpt-4o" || settings.AI.Fallback[0].Model != "llama3.1:8b" || settings.AI.Fallback[0].URL != "" {
+		t.Errorf("AI = %+v", settings.AI)
+	}
+	if settings.Navigation.ScanDepth != 0 || settings.Confirm.Policy != "" || settings.Confirm.Commands["do"] != "ALWAYS" || settings.Theme != "" {
+		t.Errorf("settings = %+v", settings)
+	}
+	if settings.Locale != "de_DE.UTF-8" {
+		t.Errorf("Locale = %q, want de_DE.UTF-8", settings.Locale)
+	}
+}
+
+func TestSettingRules(t *testing.T) {
+	tests := []struct {
+		rule  string
+		value string
+		valid bool
+	}{
+		{"ai.model", "gpt-4o-mini", true},
+		{"ai.model", "openai/gpt-4o", true},
+		{"ai.model", "gpt 4", false},
+		{"ai.embeddings.url", "https://api.example.com/v1", true},
+		{"ai.embeddings.url", "ftp://example.com", false},
+		{"ai.embeddings.api_key_env", "OLLAMA_API_KEY", true},
+		{"ai.embeddings.api_key_env", "$OLLAMA_API_KEY", false},
+		{"navigation.scan_depth", "1", true},
+		{"navigation.scan_depth", "0", false},
+		{"navigation.refresh_interval", "30m", true},
+		{"project.host", "gitlab.example.com", true},
+		{"project.host", "https://gitlab.com", false},
+		{"git.issue_pattern", "off", true},
+		{"notifications.after", "off", true},
+		{"notifications.after", "30s", true},
+		{"notifications.after", "-1s", false},
+		{"confirm.commands.*", "Never", true},
+		{"theme", "solarized", true},
+		{"locale", "en_US", true},
+		{"locale", "de", true},
+		{"locale", "tlh", false},
+	}
+	for _, test := range tests {
+		if err := settingRules[test.rule](test.value); (err == nil) != test.valid {
+			t.Errorf("%s = %q: error = %v, want valid %v", test.rule, test.value, err, test.valid)
+		}
+	}
+}
+
+func TestSetSettingValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("theme: neon\n"), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	// Other invalid settings don't block changes
+	if err := SetSetting(path, "ai.model", "gpt-4o"); err != nil {
+		t.Fatalf("SetSetting() error = %v", err)
+	}
+
+	for key, value := range map[string]string{
+		"confirm.policy": "sometimes",
+		"ai.modle":       "gpt-4o",
+		"theme":          "midnight",
+	} {
+		err := SetSetting(path, key, value)
+		if err == nil || failure.KindOf(err) != failure.UserInput || !strings.HasPrefix(err.Error(), key+": ") {
+			t.Errorf("SetSetting(%s, %s) error = %v, want a user input error", key, value, err)
+		}
+	}
+	if value, _, _ := GetSetting(path, "confirm.policy"); value != "" {
+		t.Errorf("confirm.policy = %q after an invalid change", value)
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+var (
+	// windowsDrivePath matches Windows paths such as C:\Users\me.
+	windowsDrivePath = regexp.MustCompile(`^([A-Za-z]):(?:[\\/](.*))?$`)
+	// wslMountPath matches Windows drives mounted in WSL, such as /mnt/c/Users.
+	wslMountPath = regexp.MustCompile(`^/mnt/([A-Za-z])(?:/(.*))?$`)
+	// wslSharePath matches files of a WSL distribution as seen from Windows,
+	// such as \\wsl$\Ubuntu\home\me.
+	wslSharePath = regexp.MustCompile(`(?i)^\\\\wsl(?:\$|\.localhost)\\([^\\]+)(?:\\(.*))?$`)
+)
+
+var (
+	wslOnce     sync.Once
+	wslDetected bool
+)
+
+// IsWSL reports whether Aura runs in the Windows Subsystem for Linux.
+func IsWSL() bool {
+	wslOnce.Do(func() {
+		if os.Getenv("WSL_DISTRO_NAME") != "" {
+			wslDetected = true
+			return
+		}
+		release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+		wslDetected = err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+	})
+	return wslDetected
+}
+
+// LocalPath translates path between its Windows and WSL forms into the form
+// of the environment Aura runs in, so bookmarks added in PowerShell work in
+// WSL and the other way around. C:\Users\me becomes /mnt/c/Users/me in WSL
+// and back on Windows. Other paths are returned unchanged.
+func LocalPath(path string) string {
+	return translatePath(path, platform.Current().IsWindows(), IsWSL(), os.Getenv("WSL_DISTRO_NAME"))
+}
+
+// translatePath translates path for Windows if windows is set, or for the
+// WSL distribution distro if wsl is set.
+func translatePath(path string, windows, wsl bool, distro string) string {
+	switch {
+	case windows:
+		if match := wslMountPath.FindStringSubmatch(path); match != nil {
+			return strings.ToUpper(match[1]) + `:\` + strings.ReplaceAll(match[2], "/", `\`)
+		}
+	case wsl:
+		if match := windowsDrivePath.FindStringSubmatch(path); match != nil {
+			return strings.TrimSuffix("/mnt/"+strings.ToLower(match[1])+"/"+strings.ReplaceAll(match[2], `\`, "/"), "/")
+		}
+		if match := wslSharePath.FindStringSubmatch(path); match != nil && strings.EqualFold(match[1], distro) {
+			return "/" + strings.ReplaceAll(match[2], `\`, "/")
+		}
+	}
+	return path
+}
+
+// isForeignPath reports whether path is a path of another operating system,
+// such as a Linux path on Windows, that can't be resolved here.
+func isForeignPath(path string, windows bool) bool {
+	if windows {
+		return strings.HasPrefix(path, "/")
+	}
+	return windowsDrivePath.MatchString(path) || strings.HasPrefix(path, `\\`)
+}
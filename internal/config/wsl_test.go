@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestTranslatePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		windows bool
+		wsl     bool
+		want    string
+	}{
+		{`C:\Users\me\code`, false, true, "/mnt/c/Users/me/code"},
+		{`D:/data`, false, true, "/mnt/d/data"},
+		{`C:\`, false, true, "/mnt/c"},
+		{`\\wsl$\Ubuntu\home\me`, false, true, "/home/me"},
+		{`\\wsl.localhost\ubuntu\home\me`, false, true, "/home/me"},
+		{`\\wsl$\Debian\home\me`, false, true, `\\wsl$\Debian\home\me`},
+		{"/home/me", false, true, "/home/me"},
+		{"/mnt/c/Users/me/code", true, false, `C:\Users\me\code`},
+		{"/mnt/d", true, false, `D:\`},
+		{"/home/me", true, false, "/home/me"},
+		{`C:\Users\me`, false, false, `C:\Users\me`},
+		{"/mnt/c/Users", false, false, "/mnt/c/Users"},
+	}
+
+	for _, tt := range tests {
+		if got := translatePath(tt.path, tt.windows, tt.wsl, "Ubuntu"); got != tt.want {
+			t.Errorf("translatePath(%q, windows=%v, wsl=%v) = %q, want %q", tt.path, tt.windows, tt.wsl, got, tt.want)
+		}
+	}
+}
+
+func TestIsForeignPath(t *testing.T) {
+	for _, tt := range []struct {
+		path    string
+		windows bool
+		want    bool
+	}{
+		{"/home/me", true, true},
+		{`C:\Users\me`, true, false},
+		{`C:\Users\me`, false, true},
+		{`\\server\share`, false, true},
+		{"/home/me", false, false},
+	} {
+		if got := isForeignPath(tt.path, tt.windows); got != tt.want {
+			t.Errorf("isForeignPath(%q, %v) = %v, want %v", tt.path, tt.windows, got, tt.want)
+		}
+	}
+}
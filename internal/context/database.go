@@ -0,0 +1,201 @@
+package context
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/dotenv"
+)
+
+// DatabaseTarget describes a database detected in the current directory.
+type DatabaseTarget struct {
+	// Name is a short human readable label for the database.
+	Name string
+	// Engine is the database engine: sqlite, postgres or mysql.
+	Engine string
+	// Ref is the reference understood by `aura db`, for example a file path,
+	// "env:DATABASE_URL" or "compose:<service>".
+	Ref string
+}
+
+// databaseFilePatterns lists glob patterns of SQLite database files.
+var databaseFilePatterns = []string{"*.db", "*.sqlite", "*.sqlite3"}
+
+// databaseFileDirs lists directories searched for SQLite database files.
+var databaseFileDirs = []string{".", "data", "db"}
+
+// DetectDatabaseContext checks for database files, compose database services
+// and DATABASE_URL settings and returns relevant actions.
+func DetectDatabaseContext() []Action {
+	targets := DetectDatabaseTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	var actions []Action
+	for _, target := range targets {
+		actions = append(actions,
+			Action{Name: fmt.Sprintf("Open SQL shell (%s)", target.Name), Command: "aura db shell " + target.Ref},
+			Action{Name: fmt.Sprintf("Dump schema (%s)", target.Name), Command: "aura db schema " + target.Ref},
+			Action{Name: fmt.Sprintf("Explain schema with AI (%s)", target.Name), Command: "aura db schema --explain " + target.Ref},
+		)
+	}
+
+	return actions
+}
+
+// DetectDatabaseTargets returns all databases detected in the current directory.
+func DetectDatabaseTargets() []DatabaseTarget {
+	var targets []DatabaseTarget
+
+	for _, dir := range databaseFileDirs {
+		for _, pattern := range databaseFilePatterns {
+			files, err := filepath.Glob(filepath.Join(dir, pattern))
+			if err != nil {
+				continue
+			}
+			for _, file := range files {
+				if strings.ContainsAny(file, " \t") {
+					// Action commands are split on whitespace
+					continue
+				}
+				targets = append(targets, DatabaseTarget{Name: filepath.ToSlash(file), Engine: "sqlite", Ref: filepath.ToSlash(file)})
+			}
+		}
+	}
+
+	for _, composeFile := range []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"} {
+		services, err := ParseComposeDatabaseServices(composeFile)
+		if err != nil {
+			continue
+		}
+		for _, service := range services {
+			targets = append(targets, DatabaseTarget{
+				Name:   fmt.Sprintf("%s service %s", service.Engine, service.Name),
+				Engine: service.Engine,
+				Ref:    "compose:" + service.Name,
+			})
+		}
+		break
+	}
+
+	if env, err := dotenv.Read(".env"); err == nil {
+		if engine := DatabaseURLEngine(env["DATABASE_URL"]); engine != "" {
+			targets = append(targets, DatabaseTarget{Name: engine + " from DATABASE_URL", Engine: engine, Ref: "env:DATABASE_URL"})
+		}
+	}
+
+	return targets
+}
+
+// ComposeService is a database service declared in a docker-compose file.
+type ComposeService struct {
+	Name   string
+	Engine string
+}
+
+// ParseComposeDatabaseServices returns Postgres and MySQL services declared in
+// the given docker-compose file. It understands the common block layout of
+// compose files without requiring a full YAML parser.
+func ParseComposeDatabaseServices(path string) ([]ComposeService, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var services []ComposeService
+	var current *ComposeService
+	inServices := false
+	serviceIndent := -1
+
+	flush := func() {
+		if current != nil && current.Engine != "" {
+			services = append(services, *current)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if indent == 0 {
+			flush()
+			inServices = trimmed == "services:"
+			serviceIndent = -1
+			continue
+		}
+		if !inServices {
+			continue
+		}
+
+		if serviceIndent == -1 {
+			serviceIndent = indent
+		}
+
+		if indent == serviceIndent && strings.HasSuffix(trimmed, ":") {
+			flush()
+			current = &ComposeService{Name: strings.Trim(strings.TrimSuffix(trimmed, ":"), `"'`)}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if image, ok := strings.CutPrefix(trimmed, "image:"); ok {
+			current.Engine = imageEngine(strings.Trim(strings.TrimSpace(image), `"'`))
+		}
+	}
+	flush()
+
+	return services, scanner.Err()
+}
+
+// imageEngine returns the database engine for a container image name.
+func imageEngine(image string) string {
+	name := image
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name, _, _ = strings.Cut(name, ":")
+
+	switch name {
+	case "postgres", "postgis", "timescaledb":
+		return "postgres"
+	case "mysql", "mariadb":
+		return "mysql"
+	}
+	return ""
+}
+
+// DatabaseURLEngine returns the database engine for a connection URL, or an
+// empty string if the URL is not a supported database URL.
+func DatabaseURLEngine(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		return "postgres"
+	case "mysql":
+		return "mysql"
+	case "sqlite", "sqlite3", "file":
+		return "sqlite"
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package context
+
+import "regexp"
+
+// destructivePatterns match commands that delete files or data, or discard
+// changes, in a way that can't be undone.
+var destructivePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(^|[;&|]|\bsudo|\bxargs)\s*(rm|rmdir|del|rd|shred|mkfs\S*|dd|truncate)(\s|$)`),
+	regexp.MustCompile(`(?i)\bRemove-Item\b`),
+	regexp.MustCompile(`\bgit\s+(reset\s+--hard|clean\b|checkout\s+--\s|restore\b|branch\s+-D\b|stash\s+(drop|clear)\b)`),
+	regexp.MustCompile(`\bgit\s+push\b.*\s(-f|--force(-with-lease)?)\b`),
+	regexp.MustCompile(`\bdocker(-compose|\s+compose)?\s+(\S+\s+)?(prune|rm|rmi|down\s+.*(-v|--volumes))\b`),
+	regexp.MustCompile(`\b(go\s+clean\s+-modcache|make\s+clean|npm\s+cache\s+clean|dropdb)\b`),
+	regexp.MustCompile(`(?i)\b(drop\s+(table|database|schema)|truncate\s+table|delete\s+from)\b`),
+}
+
+// IsDestructive reports whether command deletes files or data, or discards
+// changes, such as rm, git reset --hard or docker system prune.
+func IsDestructive(command string) bool {
+	for _, pattern := range destructivePatterns {
+		if pattern.MatchString(command) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package context
+
+import "testing"
+
+func TestIsDestructive(t *testing.T) {
+	for command, want := range map[string]bool{
+		"rm -rf build":                  true,
+		"make && rm out.txt":            true,
+		"find . -name '*.o' | xargs rm": true,
+		"git reset --hard HEAD~1":       true,
+		"git clean -fdx":                true,
+		"git push --force origin main":  true,
+		"git branch -D feature":         true,
+		"docker system prune -af":       true,
+		"docker compose down -v":        true,
+		"go clean -modcache":            true,
+		"make clean":                    true,
+		"psql -c 'DROP TABLE users'":    true,
+		"Remove-Item -Recurse build":    true,
+		"git status":                    false,
+		"git push":                      false,
+		"docker compose down":           false,
+		"go test ./...":                 false,
+		"npm run format":                false,
+		"echo rm":                       false,
+		"grep -r term .":                false,
+	} {
+		if got := IsDestructive(command); got != want {
+			t.Errorf("IsDestructive(%q) = %v, want %v", command, got, want)
+		}
+	}
+}
@@ -9,6 +9,9 @@ import (
 type Action struct {
 	Name    string
 	Command string
+	// Shell marks commands that must run in the calling shell rather than a
+	// child process, such as activating a virtual environment.
+	Shell bool
 }
 
 // DetectGitContext checks for Git repository and returns relevant actions.
@@ -27,8 +30,7 @@ func DetectGitContext() []Action {
 		{Name: "Pull latest changes", Command: "git pull"},
 		{Name: "View commit history", Command: "git log --oneline -10"},
 		{Name: "Create new branch", Command: "git checkout -b"},
-		{Name: "Stash changes", Command: "git stash"},
-		{Name: "Pop stash", Command: "git stash pop"},
+		{Name: "Manage stashes", Command: "aura git stash"},
 	}
 }
 
@@ -194,6 +196,9 @@ func DetectDockerContext() []Action {
 		)
 	}
 
+	// Add actions for containers and images that currently exist
+	actions = append(actions, detectDockerLiveActions()...)
+
 	return actions
 }
 
@@ -219,3 +224,32 @@ func hasFilePattern(pattern string) bool {
 	files, err := filepath.Glob(pattern)
 	return err == nil && len(files) > 0
 }
+
+// Stack is a project type or tool recognized by a detector.
+type Stack struct {
+	Name   string
+	Detect func() []Action
+}
+
+// Stacks are the detectors of project types and tools by name.
+var Stacks = []Stack{
+	{Name: "Git", Detect: DetectGitContext},
+	{Name: "Node.js", Detect: DetectNodeContext},
+	{Name: "Python", Detect: DetectPythonContext},
+	{Name: "Go", Detect: DetectGoContext},
+	{Name: "Docker", Detect: DetectDockerContext},
+	{Name: "Make", Detect: DetectMakeContext},
+	{Name: "Databases", Detect: DetectDatabaseContext},
+}
+
+// DetectStacks returns the names of the stacks found in the current
+// directory.
+func DetectStacks() []string {
+	var names []string
+	for _, stack := range Stacks {
+		if len(stack.Detect()) > 0 {
+			names = append(names, stack.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,182 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dockerQueryTimeout bounds every call to the Docker daemon so a slow or
+// unresponsive daemon never stalls the action menu.
+const dockerQueryTimeout = 2 * time.Second
+
+// runDocker executes a docker CLI command and returns its trimmed output.
+// It is a variable so tests can replace it without a running daemon.
+var runDocker = func(args ...string) (string, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dockerQueryTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "docker", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// dockerContainer is a container reported by `docker ps`.
+type dockerContainer struct {
+	Name   string
+	Status string
+}
+
+// running reports whether the container is currently up.
+func (c dockerContainer) running() bool {
+	return strings.HasPrefix(strings.ToLower(c.Status), "up")
+}
+
+// detectDockerLiveActions queries the Docker daemon for containers and images
+// belonging to the current project and returns actions for their live state.
+func detectDockerLiveActions() []Action {
+	project := dockerProjectName()
+	if project == "" {
+		return nil
+	}
+
+	var actions []Action
+
+	for _, container := range listProjectContainers(project) {
+		if container.running() {
+			actions = append(actions,
+				Action{
+					Name:    fmt.Sprintf("Stop running container %s (%s)", container.Name, shortDockerStatus(container.Status)),
+					Command: "docker stop " + container.Name,
+				},
+				Action{
+					Name:    fmt.Sprintf("View logs of container %s", container.Name),
+					Command: "docker logs --tail 100 " + container.Name,
+				},
+			)
+		} else {
+			actions = append(actions,
+				Action{
+					Name:    fmt.Sprintf("Start stopped container %s", container.Name),
+					Command: "docker start " + container.Name,
+				},
+				Action{
+					Name:    fmt.Sprintf("Remove stopped container %s", container.Name),
+					Command: "docker rm " + container.Name,
+				},
+			)
+		}
+	}
+
+	if output, err := runDocker("images", "--filter", "dangling=true", "--quiet"); err == nil && output != "" {
+		count := len(strings.Fields(output))
+		actions = append(actions, Action{
+			Name:    fmt.Sprintf("Remove dangling images (%d)", count),
+			Command: "docker image prune -f",
+		})
+	}
+
+	return actions
+}
+
+// listProjectContainers returns containers that belong to the compose project
+// or were started from an image named after the project.
+func listProjectContainers(project string) []dockerContainer {
+	seen := make(map[string]bool)
+	var containers []dockerContainer
+
+	filters := []string{
+		"label=com.docker.compose.project=" + project,
+		"ancestor=" + project,
+	}
+
+	for _, filter := range filters {
+		output, err := runDocker("ps", "--all", "--filter", filter, "--format", "{{.Names}}\t{{.Status}}")
+		if err != nil || output == "" {
+			continue
+		}
+
+		for _, container := range parseDockerPS(output) {
+			if seen[container.Name] {
+				continue
+			}
+			seen[container.Name] = true
+			containers = append(containers, container)
+		}
+	}
+
+	return containers
+}
+
+// parseDockerPS parses tab separated `docker ps` output of names and statuses.
+func parseDockerPS(output string) []dockerContainer {
+	var containers []dockerContainer
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "\t", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+		containers = append(containers, dockerContainer{Name: parts[0], Status: parts[1]})
+	}
+	return containers
+}
+
+// dockerProjectName returns the compose project name for the current
+// directory, following the same rules Docker Compose uses by default.
+func dockerProjectName() string {
+	if name := os.Getenv("COMPOSE_PROJECT_NAME"); name != "" {
+		return name
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(wd)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+var dockerUptimePattern = regexp.MustCompile(`^Up (About )?(an?|\d+) (second|minute|hour|day|week|month|year)s?`)
+
+// shortDockerStatus condenses a docker status such as "Up 2 hours (healthy)"
+// into a compact form like "up 2h".
+func shortDockerStatus(status string) string {
+	match := dockerUptimePattern.FindStringSubmatch(status)
+	if match == nil {
+		return strings.ToLower(status)
+	}
+
+	amount := match[2]
+	if amount == "a" || amount == "an" {
+		amount = "1"
+	}
+
+	units := map[string]string{
+		"second": "s",
+		"minute": "m",
+		"hour":   "h",
+		"day":    "d",
+		"week":   "w",
+		"month":  "mo",
+		"year":   "y",
+	}
+
+	return "up " + amount + units[match[3]]
+}
@@ -0,0 +1,133 @@
+package context
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestShortDockerStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"Up 2 hours", "up 2h"},
+		{"Up 5 minutes (healthy)", "up 5m"},
+		{"Up About an hour", "up 1h"},
+		{"Up 3 days", "up 3d"},
+		{"Up Less than a second", "up less than a second"},
+		{"Exited (0) 3 hours ago", "exited (0) 3 hours ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := shortDockerStatus(tt.status); got != tt.want {
+				t.Errorf("shortDockerStatus(%q) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDockerPS(t *testing.T) {
+	output := "myapp-web-1\tUp 2 hours\nmyapp-db-1\tExited (1) 5 minutes ago\n\ninvalid-line"
+
+	containers := parseDockerPS(output)
+	if len(containers) != 2 {
+		t.Fatalf("Expected 2 containers, got %d", len(containers))
+	}
+
+	if containers[0].Name != "myapp-web-1" || !containers[0].running() {
+		t.Errorf("Unexpected first container: %+v", containers[0])
+	}
+	if containers[1].Name != "myapp-db-1" || containers[1].running() {
+		t.Errorf("Unexpected second container: %+v", containers[1])
+	}
+}
+
+func TestDetectDockerLiveActions(t *testing.T) {
+	originalRunDocker := runDocker
+	defer func() { runDocker = originalRunDocker }()
+
+	t.Setenv("COMPOSE_PROJECT_NAME", "myapp")
+
+	runDocker = func(args ...string) (string, error) {
+		command := strings.Join(args, " ")
+		switch {
+		case strings.Contains(command, "label=com.docker.compose.project=myapp"):
+			return "myapp-web-1\tUp 2 hours\nmyapp-db-1\tExited (0) 1 hour ago", nil
+		case strings.Contains(command, "ancestor=myapp"):
+			return "myapp-web-1\tUp 2 hours", nil
+		case strings.Contains(command, "dangling=true"):
+			return "sha256:aaa\nsha256:bbb", nil
+		}
+		return "", nil
+	}
+
+	actions := detectDockerLiveActions()
+
+	expected := map[string]string{
+		"Stop running container myapp-web-1 (up 2h)": "docker stop myapp-web-1",
+		"Start stopped container myapp-db-1":         "docker start myapp-db-1",
+		"Remove dangling images (2)":                 "docker image prune -f",
+	}
+
+	found := make(map[string]string)
+	for _, action := range actions {
+		found[action.Name] = action.Command
+	}
+
+	for name, command := range expected {
+		if found[name] != command {
+			t.Errorf("Expected action %q with command %q, got %q", name, command, found[name])
+		}
+	}
+
+	// Containers matched by several filters must only be listed once
+	stopCount := 0
+	for _, action := range actions {
+		if strings.HasPrefix(action.Name, "Stop running container myapp-web-1") {
+			stopCount++
+		}
+	}
+	if stopCount != 1 {
+		t.Errorf("Expected container to be listed once, got %d", stopCount)
+	}
+}
+
+func TestDetectDockerLiveActionsWithoutDaemon(t *testing.T) {
+	originalRunDocker := runDocker
+	defer func() { runDocker = originalRunDocker }()
+
+	runDocker = func(args ...string) (string, error) {
+		return "", errors.New("docker not available")
+	}
+
+	if actions := detectDockerLiveActions(); len(actions) != 0 {
+		t.Errorf("Expected no live actions without a daemon, got %d", len(actions))
+	}
+}
+
+func TestDockerProjectName(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "My_Project.v2-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change to temp dir: %v", err)
+	}
+
+	t.Setenv("COMPOSE_PROJECT_NAME", "")
+	name := dockerProjectName()
+	if !strings.HasPrefix(name, "my_projectv2-") {
+		t.Errorf("dockerProjectName() = %q, want prefix %q", name, "my_projectv2-")
+	}
+}
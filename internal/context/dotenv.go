@@ -0,0 +1,115 @@
+package context
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// exampleEnvFiles lists the names used for committed dotenv templates.
+var exampleEnvFiles = []string{".env.example", ".env.sample", ".env.template", ".env.dist"}
+
+// DetectEnvContext checks for .env files and returns actions for comparing
+// them with their example file and keeping secrets out of the repository.
+func DetectEnvContext() []Action {
+	envFiles := findEnvFiles()
+	if len(envFiles) == 0 {
+		return nil
+	}
+
+	var actions []Action
+
+	example := findExampleEnvFile()
+	for _, envFile := range envFiles {
+		if IsTrackedOrUnignored(envFile) {
+			actions = append(actions, Action{
+				Name:    "⚠ " + envFile + " is not in .gitignore (add it)",
+				Command: "aura dotenv ignore " + envFile,
+			})
+		}
+	}
+
+	if _, err := os.Stat(".env"); err == nil {
+		if example != "" {
+			actions = append(actions, Action{
+				Name:    "Compare .env with " + example,
+				Command: "aura dotenv diff .env " + example,
+			})
+		}
+
+		target := example
+		if target == "" {
+			target = ".env.example"
+		}
+		actions = append(actions, Action{
+			Name:    "Generate redacted " + target,
+			Command: "aura dotenv example .env " + target,
+		})
+	}
+
+	if _, err := os.Stat(".git"); err == nil {
+		actions = append(actions, Action{Name: "Scan tracked files for secrets", Command: "aura secrets scan"})
+	}
+
+	return actions
+}
+
+// findEnvFiles returns the dotenv files in the current directory, excluding
+// example templates.
+func findEnvFiles() []string {
+	files, err := filepath.Glob(".env*")
+	if err != nil {
+		return nil
+	}
+
+	var envFiles []string
+	for _, file := range files {
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			continue
+		}
+		if isExampleEnvFile(file) || strings.ContainsAny(file, " \t") {
+			continue
+		}
+		envFiles = append(envFiles, file)
+	}
+	return envFiles
+}
+
+// findExampleEnvFile returns the first example dotenv file that exists.
+func findExampleEnvFile() string {
+	for _, name := range exampleEnvFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// isExampleEnvFile reports whether name is a dotenv template file.
+func isExampleEnvFile(name string) bool {
+	for _, example := range exampleEnvFiles {
+		if name == example {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTrackedOrUnignored reports whether the file inside a Git repository is
+// either tracked or not covered by .gitignore, meaning it may be committed.
+// It returns false outside of Git repositories.
+func IsTrackedOrUnignored(path string) bool {
+	if err := exec.Command("git", "ls-files", "--error-unmatch", path).Run(); err == nil {
+		return true
+	}
+
+	err := exec.Command("git", "check-ignore", "-q", path).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// Exit code 1 means not ignored, 128 means not a repository
+		return exitErr.ExitCode() == 1
+	}
+	return false
+}
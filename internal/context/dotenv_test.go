@@ -0,0 +1,56 @@
+package context
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetectEnvContext(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "aura_dotenv_test_*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(originalDir); err != nil {
+			t.Errorf("Failed to restore original directory: %v", err)
+		}
+	}()
+
+	err = os.Chdir(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to change to temp dir: %v", err)
+	}
+
+	// Only an example file is not an env context
+	if err := os.WriteFile(".env.example", []byte("API_KEY=\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .env.example: %v", err)
+	}
+	if actions := DetectEnvContext(); len(actions) != 0 {
+		t.Errorf("Expected no env actions without .env, got %d", len(actions))
+	}
+
+	if err := os.WriteFile(".env", []byte("API_KEY=abc\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .env: %v", err)
+	}
+
+	actions := DetectEnvContext()
+	commands := make(map[string]bool)
+	for _, action := range actions {
+		commands[action.Command] = true
+	}
+
+	for _, expected := range []string{
+		"aura dotenv diff .env .env.example",
+		"aura dotenv example .env .env.example",
+	} {
+		if !commands[expected] {
+			t.Errorf("Missing expected env action command: %s", expected)
+		}
+	}
+}
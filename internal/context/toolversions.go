@@ -0,0 +1,112 @@
+package context
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// DetectToolVersionContext checks for files pinning tool versions, such as
+// .nvmrc, .python-version, .tool-versions and virtual environments, and
+// returns actions that activate them in the calling shell.
+func DetectToolVersionContext() []Action {
+	shell := ShellKind()
+	var actions []Action
+
+	if version := readVersionFile(".nvmrc"); version != "" {
+		actions = append(actions, Action{
+			Name:    "Use Node.js " + version + " (nvm)",
+			Command: "nvm use " + version,
+			Shell:   true,
+		})
+	}
+
+	if version := readVersionFile(".python-version"); version != "" {
+		actions = append(actions, Action{
+			Name:    "Use Python " + version + " (pyenv)",
+			Command: "pyenv shell " + version,
+			Shell:   true,
+		})
+	}
+
+	if _, err := os.Stat(".tool-versions"); err == nil {
+		for _, manager := range []string{"asdf", "mise"} {
+			if _, err := exec.LookPath(manager); err == nil {
+				actions = append(actions, Action{
+					Name:    "Install tool versions (" + manager + ")",
+					Command: manager + " install",
+				})
+				break
+			}
+		}
+	}
+
+	for _, venv := range []string{"venv", ".venv"} {
+		if command := venvActivateCommand(venv, shell); command != "" {
+			actions = append(actions, Action{
+				Name:    "Activate virtual environment (" + venv + ")",
+				Command: command,
+				Shell:   true,
+			})
+			break
+		}
+	}
+
+	return actions
+}
+
+// ShellKind returns the shell Aura's shell integration runs in: "bash",
+// "zsh", "fish" or "powershell". The integration sets AURA_SHELL; otherwise
+// it is guessed from the environment.
+func ShellKind() string {
+	return platform.Current().UserShell()
+}
+
+// venvActivateCommand returns the statement activating the virtual
+// environment in dir for the shell, or an empty string if there is none.
+func venvActivateCommand(dir, shell string) string {
+	var script string
+	switch shell {
+	case "powershell", "pwsh":
+		script = filepath.Join(dir, "Scripts", "Activate.ps1")
+		if _, err := os.Stat(script); err != nil {
+			script = filepath.Join(dir, "bin", "Activate.ps1")
+		}
+		if _, err := os.Stat(script); err != nil {
+			return ""
+		}
+		return "& ./" + filepath.ToSlash(script)
+	case "fish":
+		script = filepath.Join(dir, "bin", "activate.fish")
+	default:
+		script = filepath.Join(dir, "bin", "activate")
+	}
+
+	if _, err := os.Stat(script); err != nil {
+		return ""
+	}
+	return "source " + filepath.ToSlash(script)
+}
+
+// versionPattern matches versions that are safe to pass to the shell, such
+// as "18.17.0" or "lts/hydrogen".
+var versionPattern = regexp.MustCompile(`^[A-Za-z0-9._/+-]+$`)
+
+// readVersionFile returns the version in the first line of a version file
+// such as .nvmrc, or an empty string if there is no valid version.
+func readVersionFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(content)), "\n")
+	version := strings.TrimSpace(line)
+	if !versionPattern.MatchString(version) {
+		return ""
+	}
+	return version
+}
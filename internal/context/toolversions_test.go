@@ -0,0 +1,65 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectToolVersionContext(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change to temp dir: %v", err)
+	}
+	t.Setenv("AURA_SHELL", "bash")
+
+	if actions := DetectToolVersionContext(); len(actions) != 0 {
+		t.Errorf("Expected no actions in empty directory, got %v", actions)
+	}
+
+	files := map[string]string{
+		".nvmrc":                 "18.17.0\n",
+		".python-version":        "3.12.1; rm -rf /\n",
+		"venv/bin/activate":      "",
+		"venv/bin/activate.fish": "",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	commands := make(map[string]bool)
+	for _, action := range DetectToolVersionContext() {
+		if !action.Shell {
+			t.Errorf("Expected %q to run in the calling shell", action.Name)
+		}
+		commands[action.Command] = true
+	}
+
+	if !commands["nvm use 18.17.0"] {
+		t.Errorf("Expected nvm action, got %v", commands)
+	}
+	if !commands["source venv/bin/activate"] {
+		t.Errorf("Expected venv activation, got %v", commands)
+	}
+	for command := range commands {
+		if command == "pyenv shell 3.12.1; rm -rf /" {
+			t.Error("Invalid versions must not be passed to the shell")
+		}
+	}
+
+	t.Setenv("AURA_SHELL", "fish")
+	if command := venvActivateCommand("venv", ShellKind()); command != "source venv/bin/activate.fish" {
+		t.Errorf("venvActivateCommand() for fish = %q", command)
+	}
+}
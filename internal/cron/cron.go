@@ -0,0 +1,350 @@
+// Package cron parses cron expressions, describes them in plain English,
+// converts them to systemd OnCalendar expressions and Task Scheduler
+// triggers and computes their next runs.
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// field is one of the five fields of a cron expression.
+type field struct {
+	name     string
+	min, max int
+	// names maps names such as MON to values, for months and weekdays.
+	names map[string]int
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day of month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: nameValues(monthNames, 1)}
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: nameValues(weekdayNames, 0)}
+)
+
+var monthNames = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+var weekdayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// macros are the shorthands of common expressions.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// nameValues maps the first three letters of names, upper case, to their
+// values counting from first.
+func nameValues(names []string, first int) map[string]int {
+	values := map[string]int{}
+	for i, name := range names {
+		values[strings.ToUpper(name[:3])] = first + i
+	}
+	return values
+}
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	// Expression is the expression as parsed, macros expanded.
+	Expression string
+	minutes    []int
+	hours      []int
+	days       []int
+	months     []int
+	// weekdays count from Sunday, 0.
+	weekdays []int
+	// anyDay and anyWeekday tell whether the day fields are unrestricted,
+	// since cron runs on either of the days when both are restricted.
+	anyDay, anyWeekday bool
+}
+
+// Parse parses a cron expression of five fields or a macro such as @daily.
+func Parse(expression string) (Schedule, error) {
+	expression = strings.Join(strings.Fields(expression), " ")
+	if expanded, ok := macros[strings.ToLower(expression)]; ok {
+		expression = expanded
+	} else if strings.HasPrefix(expression, "@") {
+		return Schedule{}, failure.New(failure.UserInput, "unsupported macro %s, use @yearly, @monthly, @weekly, @daily or @hourly", expression)
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return Schedule{}, failure.New(failure.UserInput, "'%s' has %d fields, cron expressions have 5: minute, hour, day of month, month and day of week", expression, len(fields))
+	}
+
+	schedule := Schedule{Expression: expression}
+	var err error
+	for i, target := range []struct {
+		field  field
+		values *[]int
+	}{
+		{minuteField, &schedule.minutes},
+		{hourField, &schedule.hours},
+		{dayField, &schedule.days},
+		{monthField, &schedule.months},
+		{weekdayField, &schedule.weekdays},
+	} {
+		if *target.values, err = target.field.parse(fields[i]); err != nil {
+			return Schedule{}, err
+		}
+	}
+	schedule.weekdays = normalizeWeekdays(schedule.weekdays)
+	schedule.anyDay = strings.HasPrefix(fields[2], "*")
+	schedule.anyWeekday = strings.HasPrefix(fields[4], "*")
+	return schedule, nil
+}
+
+// parse returns the sorted values of a field such as "1-5", "*/15" or
+// "MON,WED".
+func (f field) parse(text string) ([]int, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return nil, failure.New(failure.UserInput, "invalid step '%s' in the %s field", stepText, f.name)
+			}
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rangeText == "*":
+		case strings.Contains(rangeText, "-"):
+			from, to, _ := strings.Cut(rangeText, "-")
+			var err error
+			if start, err = f.value(from); err != nil {
+				return nil, err
+			}
+			if end, err = f.value(to); err != nil {
+				return nil, err
+			}
+			if end < start {
+				return nil, failure.New(failure.UserInput, "range '%s' of the %s field ends before it starts", rangeText, f.name)
+			}
+		default:
+			var err error
+			if start, err = f.value(rangeText); err != nil {
+				return nil, err
+			}
+			// "5/10" steps from 5 to the end, a plain "5" is just 5.
+			if !hasStep {
+				end = start
+			}
+		}
+		for value := start; value <= end; value += step {
+			set[value] = true
+		}
+	}
+
+	values := make([]int, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+// value parses a number or name of the field.
+func (f field) value(text string) (int, error) {
+	if value, ok := f.names[strings.ToUpper(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < f.min || value > f.max {
+		return 0, failure.New(failure.UserInput, "invalid value '%s' in the %s field, use %d to %d", text, f.name, f.min, f.max)
+	}
+	return value, nil
+}
+
+// normalizeWeekdays maps Sunday as 7 to 0 and sorts weekdays.
+func normalizeWeekdays(weekdays []int) []int {
+	set := map[int]bool{}
+	for _, day := range weekdays {
+		set[day%7] = true
+	}
+	normalized := make([]int, 0, len(set))
+	for day := range set {
+		normalized = append(normalized, day)
+	}
+	sort.Ints(normalized)
+	return normalized
+}
+
+// matchesDay reports whether the schedule runs on the day of t.
+func (s Schedule) matchesDay(t time.Time) bool {
+	if !contains(s.months, int(t.Month())) {
+		return false
+	}
+	day := contains(s.days, t.Day())
+	weekday := contains(s.weekdays, int(t.Weekday()))
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// searchDays bounds how far Next looks ahead, enough for two February 29ths.
+const searchDays = 8 * 366
+
+// Next returns the next n runs after after, in its location. Expressions
+// that never run, such as on February 30, have none.
+func (s Schedule) Next(after time.Time, n int) []time.Time {
+	var runs []time.Time
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+	for i := 0; i < searchDays && len(runs) < n; i++ {
+		if s.matchesDay(day) {
+			for _, hour := range s.hours {
+				for _, minute := range s.minutes {
+					run := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, after.Location())
+					if run.After(after) && len(runs) < n {
+						runs = append(runs, run)
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return runs
+}
+
+// BothDaysRestricted reports whether the expression restricts both the day
+// of month and the day of week, so that it runs on either; systemd runs
+// only on days matching both.
+func (s Schedule) BothDaysRestricted() bool {
+	return !s.anyDay && !s.anyWeekday
+}
+
+// OnCalendar returns the systemd OnCalendar expression of the schedule,
+// such as "Mon..Fri *-*-* 07:00:00".
+func (s Schedule) OnCalendar() string {
+	var b strings.Builder
+	if !s.anyWeekday {
+		var names []string
+		for _, r := range ranges(s.weekdays) {
+			name := weekdayNames[r[0]][:3]
+			if r[1] > r[0] {
+				name += ".." + weekdayNames[r[1]][:3]
+			}
+			names = append(names, name)
+		}
+		b.WriteString(strings.Join(names, ",") + " ")
+	}
+	fmt.Fprintf(&b, "*-%s-%s %s:%s:00",
+		calendarValues(s.months, monthField), calendarValues(s.days, dayField),
+		calendarValues(s.hours, hourField), calendarValues(s.minutes, minuteField))
+	return b.String()
+}
+
+// TaskScheduler returns the schtasks arguments running the schedule in the
+// Windows Task Scheduler, which runs tasks every few minutes, hourly, daily,
+// on days of the week or on days of the month, but not combinations of them.
+func (s Schedule) TaskScheduler() ([]string, error) {
+	every := func(values []int, f field) bool { return len(values) == f.max-f.min+1 }
+	unsupported := failure.New(failure.UserInput, "the Task Scheduler can't run '%s', use a schedule every few minutes, hourly, daily, on days of the week or on days of the month", s.Expression)
+	if !every(s.months, monthField) || (!s.anyDay && !s.anyWeekday) {
+		return nil, unsupported
+	}
+
+	if every(s.hours, hourField) && s.anyDay && s.anyWeekday {
+		if step, ok := repetition(s.minutes, minuteField); ok && s.minutes[0] == 0 && 60%step == 0 {
+			return []string{"/SC", "MINUTE", "/MO", strconv.Itoa(step)}, nil
+		}
+		if len(s.minutes) == 1 {
+			return []string{"/SC", "HOURLY", "/ST", fmt.Sprintf("00:%02d", s.minutes[0])}, nil
+		}
+	}
+	if len(s.minutes) != 1 || len(s.hours) != 1 {
+		return nil, unsupported
+	}
+
+	start := fmt.Sprintf("%02d:%02d", s.hours[0], s.minutes[0])
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return []string{"/SC", "DAILY", "/ST", start}, nil
+	case s.anyDay:
+		var days []string
+		for _, weekday := range s.weekdays {
+			days = append(days, strings.ToUpper(weekdayNames[weekday][:3]))
+		}
+		return []string{"/SC", "WEEKLY", "/D", strings.Join(days, ","), "/ST", start}, nil
+	default:
+		var days []string
+		for _, day := range s.days {
+			days = append(days, strconv.Itoa(day))
+		}
+		return []string{"/SC", "MONTHLY", "/D", strings.Join(days, ","), "/ST", start}, nil
+	}
+}
+
+// calendarValues formats the values of a field for OnCalendar: * for all,
+// a repetition such as 00/15, or a list of values and ranges.
+func calendarValues(values []int, f field) string {
+	if len(values) == f.max-f.min+1 {
+		return "*"
+	}
+	if step, ok := repetition(values, f); ok {
+		return fmt.Sprintf("%02d/%d", values[0], step)
+	}
+	var parts []string
+	for _, r := range ranges(values) {
+		part := fmt.Sprintf("%02d", r[0])
+		if r[1] > r[0] {
+			part += fmt.Sprintf("..%02d", r[1])
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ",")
+}
+
+// repetition reports whether values step evenly from the first through the
+// end of the field, such as 0, 15, 30, 45 for minutes, and returns the step.
+func repetition(values []int, f field) (int, bool) {
+	if len(values) < 2 {
+		return 0, false
+	}
+	step := values[1] - values[0]
+	for i := 1; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0, false
+		}
+	}
+	return step, step > 1 && values[len(values)-1]+step > f.max
+}
+
+// ranges groups sorted values into runs of consecutive values, as pairs of
+// the first and last.
+func ranges(values []int) [][2]int {
+	var result [][2]int
+	for _, value := range values {
+		if n := len(result); n > 0 && result[n-1][1] == value-1 {
+			result[n-1][1] = value
+			continue
+		}
+		result = append(result, [2]int{value, value})
+	}
+	return result
+}
+
+func contains(values []int, value int) bool {
+	i := sort.SearchInts(values, value)
+	return i < len(values) && values[i] == value
+}
@@ -0,0 +1,157 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{name: "too few fields", expression: "0 7 * *"},
+		{name: "seconds", expression: "0 0 7 * * *"},
+		{name: "out of range", expression: "60 * * * *"},
+		{name: "backwards range", expression: "0 17-9 * * *"},
+		{name: "zero step", expression: "*/0 * * * *"},
+		{name: "unknown name", expression: "0 7 * * MONDAY"},
+		{name: "unknown macro", expression: "@reboot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expression)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want an error", tt.expression)
+			}
+			if failure.KindOf(err) != failure.UserInput {
+				t.Errorf("Parse(%q) error kind = %v, want UserInput", tt.expression, failure.KindOf(err))
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"* * * * *", "Every minute"},
+		{"*/15 * * * *", "Every 15 minutes"},
+		{"*/10 9-17 * * MON-FRI", "Every 10 minutes from 09:00 through 17:59 on Monday through Friday"},
+		{"0 * * * *", "Every hour, on the hour"},
+		{"30 */2 * * *", "Every 2 hours from 00:30"},
+		{"0 7 * * 1-5", "At 07:00 on Monday through Friday"},
+		{"0 3 * * 1", "At 03:00 on Monday"},
+		{"0 9,18 * * 0,6", "At 09:00 and 18:00 on Sunday and Saturday"},
+		{"@monthly", "At 00:00 on the 1st of the month"},
+		{"0 0 1,15 * 5", "At 00:00 on the 1st and 15th of the month or on Friday"},
+		{"15 4 * 3-5 *", "At 04:15 in March through May"},
+		{"0 12 * */3 *", "At 12:00 in every 3rd month"},
+		{"0 0 * * 7", "At 00:00 on Sunday"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expression, err)
+			}
+			if got := schedule.Describe(); got != tt.want {
+				t.Errorf("Describe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnCalendar(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"0 7 * * 1-5", "Mon..Fri *-*-* 07:00:00"},
+		{"*/15 * * * *", "*-*-* *:00/15:00"},
+		{"30 2 1 * *", "*-*-01 02:30:00"},
+		{"0 9,13-15 * JAN,JUL SAT,SUN", "Sun,Sat *-01/6-* 09,13..15:00:00"},
+		{"@yearly", "*-01-01 00:00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expression, err)
+			}
+			if got := schedule.OnCalendar(); got != tt.want {
+				t.Errorf("OnCalendar() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskScheduler(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"*/15 * * * *", "/SC MINUTE /MO 15"},
+		{"@hourly", "/SC HOURLY /ST 00:00"},
+		{"30 2 * * *", "/SC DAILY /ST 02:30"},
+		{"0 7 * * 1-5", "/SC WEEKLY /D MON,TUE,WED,THU,FRI /ST 07:00"},
+		{"0 3 1,15 * *", "/SC MONTHLY /D 1,15 /ST 03:00"},
+		{"0 9,17 * * *", ""},
+		{"0 0 1 1 *", ""},
+		{"*/7 * * * *", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expression, err)
+			}
+			args, err := schedule.TaskScheduler()
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("TaskScheduler() = %q, want an error", args)
+				}
+				return
+			}
+			if got := strings.Join(args, " "); err != nil || got != tt.want {
+				t.Errorf("TaskScheduler() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Friday, 16 October 2026.
+	now := time.Date(2026, time.October, 16, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"0 7 * * 1-5", []string{"2026-10-19 07:00", "2026-10-20 07:00", "2026-10-21 07:00"}},
+		{"*/30 8 * * *", []string{"2026-10-16 08:30", "2026-10-17 08:00", "2026-10-17 08:30"}},
+		{"0 0 13 * 5", []string{"2026-10-23 00:00", "2026-10-30 00:00", "2026-11-06 00:00"}},
+		{"0 0 29 2 *", []string{"2028-02-29 00:00", "2032-02-29 00:00"}},
+		{"0 0 30 2 *", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expression, err)
+			}
+			runs := schedule.Next(now, 3)
+			if len(runs) != len(tt.want) {
+				t.Fatalf("Next() = %v, want %v", runs, tt.want)
+			}
+			for i, run := range runs {
+				if got := run.Format("2006-01-02 15:04"); got != tt.want[i] {
+					t.Errorf("Next()[%d] = %s, want %s", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+)
+
+// listLimit is the number of values listed one by one before a description
+// switches to steps or ranges.
+const listLimit = 6
+
+// Describe describes when the schedule runs in plain English, such as "At
+// 07:00 on Monday through Friday".
+func (s Schedule) Describe() string {
+	parts := []string{s.describeTime()}
+	switch {
+	case s.BothDaysRestricted():
+		parts = append(parts, "on "+s.describeDays()+" of the month or on "+s.describeWeekdays())
+	case !s.anyDay:
+		parts = append(parts, "on "+s.describeDays()+" of the month")
+	case !s.anyWeekday:
+		parts = append(parts, "on "+s.describeWeekdays())
+	}
+	if len(s.months) < 12 {
+		months := describeList(s.months, func(month int) string { return monthNames[month-1] })
+		if step, ok := repetition(s.months, monthField); ok && s.months[0] == 1 && len(s.months) > listLimit/2 {
+			months = fmt.Sprintf("every %s month", ordinal(step))
+		}
+		parts = append(parts, "in "+months)
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeTime describes the minutes and hours of the schedule.
+func (s Schedule) describeTime() string {
+	allMinutes, allHours := len(s.minutes) == 60, len(s.hours) == 24
+	minuteStep, minutesRepeat := repetition(s.minutes, minuteField)
+	minutesRepeat = minutesRepeat && s.minutes[0] == 0
+	hourStep, hoursRepeat := repetition(s.hours, hourField)
+
+	switch {
+	case allMinutes && allHours:
+		return "Every minute"
+	case allMinutes:
+		return "Every minute " + s.describeHours()
+	case minutesRepeat && allHours:
+		return fmt.Sprintf("Every %d minutes", minuteStep)
+	case minutesRepeat:
+		return fmt.Sprintf("Every %d minutes %s", minuteStep, s.describeHours())
+	case len(s.minutes) == 1 && allHours:
+		if s.minutes[0] == 0 {
+			return "Every hour, on the hour"
+		}
+		return fmt.Sprintf("At minute %d of every hour", s.minutes[0])
+	case len(s.minutes) == 1 && hoursRepeat && len(s.hours) > listLimit/2:
+		return fmt.Sprintf("Every %d hours from %02d:%02d", hourStep, s.hours[0], s.minutes[0])
+	case len(s.minutes)*len(s.hours) <= listLimit:
+		var times []string
+		for _, hour := range s.hours {
+			for _, minute := range s.minutes {
+				times = append(times, fmt.Sprintf("%02d:%02d", hour, minute))
+			}
+		}
+		return "At " + joinList(times)
+	}
+
+	minutes := "minutes " + describeList(s.minutes, func(minute int) string { return fmt.Sprint(minute) })
+	if len(s.minutes) == 1 {
+		minutes = fmt.Sprintf("minute %d", s.minutes[0])
+	}
+	if allHours {
+		return "At " + minutes + " of every hour"
+	}
+	return "At " + minutes + " " + s.describeHours()
+}
+
+// describeHours describes the hours of the schedule, such as "from 09:00
+// through 17:59" or "of hours 9 and 17".
+func (s Schedule) describeHours() string {
+	if r := ranges(s.hours); len(r) == 1 {
+		return fmt.Sprintf("from %02d:00 through %02d:59", r[0][0], r[0][1])
+	}
+	return "of hours " + describeList(s.hours, func(hour int) string { return fmt.Sprint(hour) })
+}
+
+// describeDays describes the days of the month, such as "the 1st and 15th".
+func (s Schedule) describeDays() string {
+	if step, ok := repetition(s.days, dayField); ok && s.days[0] == 1 && len(s.days) > listLimit {
+		return fmt.Sprintf("every %s day", ordinal(step))
+	}
+	return "the " + describeList(s.days, ordinal)
+}
+
+// describeWeekdays describes the days of the week, such as "Monday through
+// Friday".
+func (s Schedule) describeWeekdays() string {
+	return describeList(s.weekdays, func(day int) string { return weekdayNames[day] })
+}
+
+// describeList names sorted values, runs of three or more as ranges, such
+// as "Monday through Friday" or "1, 3 and 5".
+func describeList(values []int, name func(int) string) string {
+	var items []string
+	for _, r := range ranges(values) {
+		switch {
+		case r[1]-r[0] >= 2:
+			items = append(items, name(r[0])+" through "+name(r[1]))
+		case r[1] > r[0]:
+			items = append(items, name(r[0]), name(r[1]))
+		default:
+			items = append(items, name(r[0]))
+		}
+	}
+	return joinList(items)
+}
+
+// joinList joins items as in "a, b and c".
+func joinList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// ordinal returns n as an English ordinal such as 1st or 22nd.
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,477 @@
+// Package dataprofile profiles tabular data in CSV, TSV, JSON or JSON Lines:
+// its rows, the types of its columns, their nulls and simple statistics.
+package dataprofile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// Types of columns.
+const (
+	Integer = "integer"
+	Number  = "number"
+	Boolean = "boolean"
+	Date    = "date"
+	String  = "string"
+	Object  = "object"
+	Array   = "array"
+	Mixed   = "mixed"
+	Empty   = "empty"
+)
+
+// distinctLimit bounds the distinct values counted per column.
+const distinctLimit = 10000
+
+// Profile describes a table.
+type Profile struct {
+	// Name is the file the table was read from, or stdin.
+	Name string
+	// Format is CSV, TSV, JSON or JSON Lines.
+	Format  string
+	Rows    int
+	Columns []*Column
+
+	index map[string]*Column
+}
+
+// Column describes a column of a table. Statistics are set as its type
+// calls for: Min, Max, Mean and Median for numbers, Earliest and Latest for
+// dates, True for booleans and the lengths for strings.
+type Column struct {
+	Name string
+	Type string
+	// Nulls counts the empty and missing values.
+	Nulls int
+	// Distinct counts the distinct values, up to a limit reported by
+	// DistinctCapped.
+	Distinct       int
+	DistinctCapped bool
+
+	Min, Max, Mean, Median float64
+	Earliest, Latest       time.Time
+	True                   int
+	MinLength, MaxLength   int
+
+	kinds    map[string]int
+	numbers  []float64
+	distinct map[string]bool
+}
+
+// Read profiles the table in r. The format is told by the extension of
+// name, or else by the content.
+func Read(name string, r io.Reader) (*Profile, error) {
+	input := bufio.NewReader(r)
+	profile := &Profile{Name: name, Format: detectFormat(name, input)}
+	var err error
+	switch profile.Format {
+	case "JSON", "JSON Lines":
+		err = profile.readJSON(input)
+	case "TSV":
+		err = profile.readCSV(input, '\t')
+	default:
+		err = profile.readCSV(input, sniffDelimiter(input))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if profile.Rows == 0 {
+		return nil, failure.New(failure.UserInput, "%s has no rows", name)
+	}
+	for _, column := range profile.Columns {
+		column.finish(profile.Rows)
+	}
+	return profile, nil
+}
+
+// detectFormat tells the format from the extension of name, or else from
+// the first character of input.
+func detectFormat(name string, input *bufio.Reader) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "JSON"
+	case ".jsonl", ".ndjson":
+		return "JSON Lines"
+	case ".tsv", ".tab":
+		return "TSV"
+	case ".csv":
+		return "CSV"
+	}
+	start, _ := input.Peek(512)
+	switch trimmed := bytes.TrimSpace(start); {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return "JSON"
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return "JSON Lines"
+	}
+	return "CSV"
+}
+
+// sniffDelimiter returns the most frequent of comma, semicolon and tab in
+// the first line of input, as spreadsheets in some locales export
+// semicolons.
+func sniffDelimiter(input *bufio.Reader) rune {
+	start, _ := input.Peek(4096)
+	line, _, _ := bytes.Cut(start, []byte("\n"))
+	delimiter, most := ',', 0
+	for _, candidate := range []rune{',', ';', '\t'} {
+		if n := bytes.Count(line, []byte(string(candidate))); n > most {
+			delimiter, most = candidate, n
+		}
+	}
+	return delimiter
+}
+
+func (p *Profile) readCSV(input io.Reader, delimiter rune) error {
+	reader := csv.NewReader(input)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return failure.New(failure.UserInput, "failed to read %s as %s: %v", p.Name, p.Format, err)
+	}
+	for i, name := range header {
+		p.addColumn(columnName(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), i))
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return failure.New(failure.UserInput, "failed to read %s as %s: %v", p.Name, p.Format, err)
+		}
+		for i, cell := range record {
+			if i == len(p.Columns) {
+				p.addColumn(columnName("", i))
+			}
+			p.Columns[i].observeText(cell)
+		}
+		p.Rows++
+	}
+}
+
+// readJSON reads an array of objects, or objects one after another as in
+// JSON Lines. Columns are the keys of the objects in the order they first
+// appear.
+func (p *Profile) readJSON(input *bufio.Reader) error {
+	decoder := json.NewDecoder(input)
+	decoder.UseNumber()
+	invalid := func(err error) error {
+		return failure.New(failure.UserInput, "failed to read %s as %s, an array of objects or one object per line: %v", p.Name, p.Format, err)
+	}
+
+	inArray := false
+	if start, _ := input.Peek(512); bytes.HasPrefix(bytes.TrimSpace(start), []byte("[")) {
+		if _, err := decoder.Token(); err != nil {
+			return invalid(err)
+		}
+		inArray = true
+	}
+	for !inArray || decoder.More() {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if !inArray && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return invalid(err)
+		}
+		if err := p.readObject(raw); err != nil {
+			return invalid(err)
+		}
+		p.Rows++
+	}
+	return nil
+}
+
+// readObject observes the values of an object of the table.
+func (p *Profile) readObject(raw json.RawMessage) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("row %d is not an object", p.Rows+1)
+	}
+	seen := map[*Column]bool{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		column := p.column(token.(string))
+		if seen[column] {
+			continue
+		}
+		seen[column] = true
+		column.observeJSON(value)
+	}
+	// Columns missing from the object count as nulls when finishing.
+	return nil
+}
+
+// column returns the column called name, adding it if it is new.
+func (p *Profile) column(name string) *Column {
+	if column, ok := p.index[name]; ok {
+		return column
+	}
+	return p.addColumn(name)
+}
+
+// addColumn adds a column called name. CSV headers may repeat names, so
+// the first one is the one found by column.
+func (p *Profile) addColumn(name string) *Column {
+	column := &Column{Name: name, kinds: map[string]int{}, distinct: map[string]bool{}}
+	p.Columns = append(p.Columns, column)
+	if p.index == nil {
+		p.index = map[string]*Column{}
+	}
+	if _, ok := p.index[name]; !ok {
+		p.index[name] = column
+	}
+	return column
+}
+
+// columnName names unnamed columns after their position.
+func columnName(name string, i int) string {
+	if name == "" {
+		return fmt.Sprintf("column %d", i+1)
+	}
+	return name
+}
+
+// nullTexts are the texts standing for missing values in CSV.
+var nullTexts = map[string]bool{"": true, "null": true, "na": true, "n/a": true, "nan": true}
+
+// dateLayouts are the layouts of the texts recognized as dates.
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// observeText observes a value of CSV, whose type is told by its text.
+func (c *Column) observeText(text string) {
+	text = strings.TrimSpace(text)
+	if nullTexts[strings.ToLower(text)] {
+		return
+	}
+	if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+		c.observeNumber(Integer, text)
+		return
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		c.observeNumber(Number, text)
+		return
+	}
+	if b, err := strconv.ParseBool(text); err == nil && len(text) > 1 {
+		c.observeBool(b)
+		return
+	}
+	c.observeString(text)
+}
+
+// observeJSON observes a value of JSON, whose type is its JSON type, or a
+// date for strings holding one.
+func (c *Column) observeJSON(value any) {
+	switch v := value.(type) {
+	case nil:
+	case json.Number:
+		kind := Number
+		if _, err := v.Int64(); err == nil {
+			kind = Integer
+		}
+		c.observeNumber(kind, v.String())
+	case bool:
+		c.observeBool(v)
+	case string:
+		c.observeString(v)
+	case map[string]any:
+		text, _ := json.Marshal(v)
+		c.observe(Object, string(text))
+	case []any:
+		text, _ := json.Marshal(v)
+		c.observe(Array, string(text))
+	}
+}
+
+func (c *Column) observeNumber(kind, text string) {
+	number, _ := strconv.ParseFloat(text, 64)
+	c.numbers = append(c.numbers, number)
+	c.observe(kind, text)
+}
+
+func (c *Column) observeBool(b bool) {
+	if b {
+		c.True++
+	}
+	c.observe(Boolean, strconv.FormatBool(b))
+}
+
+// observeString observes a string, which is a date if it parses as one.
+func (c *Column) observeString(text string) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			if c.Earliest.IsZero() || t.Before(c.Earliest) {
+				c.Earliest = t
+			}
+			if t.After(c.Latest) {
+				c.Latest = t
+			}
+			c.observe(Date, text)
+			return
+		}
+	}
+	length := len([]rune(text))
+	if c.kinds[String] == 0 || length < c.MinLength {
+		c.MinLength = length
+	}
+	c.MaxLength = max(c.MaxLength, length)
+	c.observe(String, text)
+}
+
+// observe counts a value of kind.
+func (c *Column) observe(kind, text string) {
+	c.kinds[kind]++
+	if len(c.distinct) < distinctLimit {
+		c.distinct[text] = true
+	} else if !c.distinct[text] {
+		c.DistinctCapped = true
+	}
+}
+
+// finish sets the type and statistics of the column of a table of rows.
+func (c *Column) finish(rows int) {
+	values := 0
+	for _, n := range c.kinds {
+		values += n
+	}
+	c.Nulls = rows - values
+	c.Distinct = len(c.distinct)
+
+	switch {
+	case len(c.kinds) == 0:
+		c.Type = Empty
+	case len(c.kinds) == 1:
+		for kind := range c.kinds {
+			c.Type = kind
+		}
+	case len(c.kinds) == 2 && c.kinds[Integer] > 0 && c.kinds[Number] > 0:
+		c.Type = Number
+	default:
+		c.Type = Mixed
+	}
+
+	if (c.Type == Integer || c.Type == Number) && len(c.numbers) > 0 {
+		sort.Float64s(c.numbers)
+		c.Min, c.Max = c.numbers[0], c.numbers[len(c.numbers)-1]
+		sum := 0.0
+		for _, number := range c.numbers {
+			sum += number
+		}
+		c.Mean = sum / float64(len(c.numbers))
+		middle := len(c.numbers) / 2
+		c.Median = c.numbers[middle]
+		if len(c.numbers)%2 == 0 {
+			c.Median = (c.numbers[middle-1] + c.numbers[middle]) / 2
+		}
+	}
+	c.numbers, c.distinct, c.kinds = nil, nil, nil
+}
+
+// Stats summarizes the statistics of the column, such as "min 1, max 9,
+// mean 4.5, median 4".
+func (c *Column) Stats(rows int) string {
+	switch c.Type {
+	case Integer, Number:
+		return fmt.Sprintf("min %s, max %s, mean %s, median %s",
+			formatNumber(c.Min), formatNumber(c.Max), formatNumber(c.Mean), formatNumber(c.Median))
+	case Date:
+		return formatDate(c.Earliest) + " to " + formatDate(c.Latest)
+	case Boolean:
+		return fmt.Sprintf("%s true", percent(c.True, rows-c.Nulls))
+	case String:
+		if c.MinLength == c.MaxLength {
+			return fmt.Sprintf("length %d", c.MinLength)
+		}
+		return fmt.Sprintf("length %d to %d", c.MinLength, c.MaxLength)
+	}
+	return ""
+}
+
+// String formats the profile as a table of its columns, which is all that
+// the AI assistant is shown of the data.
+func (p *Profile) String() string {
+	header := []string{"COLUMN", "TYPE", "NULLS", "DISTINCT", "STATS"}
+	rows := [][]string{header}
+	for _, column := range p.Columns {
+		nulls := "0"
+		if column.Nulls > 0 {
+			nulls = fmt.Sprintf("%d (%s)", column.Nulls, percent(column.Nulls, p.Rows))
+		}
+		distinct := strconv.Itoa(column.Distinct)
+		if column.DistinctCapped {
+			distinct += "+"
+		}
+		rows = append(rows, []string{column.Name, column.Type, nulls, distinct, column.Stats(p.Rows)})
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s, %d rows, %d columns\n\n", p.Name, p.Format, p.Rows, len(p.Columns))
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-len([]rune(cell))+2))
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return b.String()
+}
+
+// formatNumber formats a number with at most two decimals.
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(math.Round(number*100)/100, 'f', -1, 64)
+}
+
+// formatDate formats a date, with its time unless it is midnight.
+func formatDate(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// percent formats part of whole as a percentage.
+func percent(part, whole int) string {
+	if whole == 0 {
+		return "0%"
+	}
+	return formatNumber(100*float64(part)/float64(whole)) + "%"
+}
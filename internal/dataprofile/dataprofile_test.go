@@ -0,0 +1,124 @@
+package dataprofile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestReadCSV(t *testing.T) {
+	data := "id,email,score,active,joined\n" +
+		"1,ada@example.com,9.5,true,2024-01-04\n" +
+		"2,bob@example.com,,false,2024-03-01\n" +
+		"3,NULL,7,true,2023-12-24\n" +
+		"4,eve@example.org,8.5,TRUE,2024-06-30\n"
+	profile, err := Read("users.csv", strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := `users.csv: CSV, 4 rows, 5 columns
+
+COLUMN  TYPE     NULLS    DISTINCT  STATS
+id      integer  0        4         min 1, max 4, mean 2.5, median 2.5
+email   string   1 (25%)  3         length 15
+score   number   1 (25%)  3         min 7, max 9.5, mean 8.33, median 8.5
+active  boolean  0        2         75% true
+joined  date     0        4         2023-12-24 to 2024-06-30
+`
+	if got := profile.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReadDelimiters(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		data   string
+		format string
+	}{
+		{name: "semicolons", file: "export.csv", data: "a;b\n1;2\n", format: "CSV"},
+		{name: "tabs", file: "export.tsv", data: "a\tb\n1\t2\n", format: "TSV"},
+		{name: "sniffed tabs", file: "stdin", data: "a\tb\n1\t2\n", format: "CSV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := Read(tt.file, strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if profile.Format != tt.format || len(profile.Columns) != 2 || profile.Columns[1].Type != Integer {
+				t.Errorf("Read() = %s with %d columns, want %s with 2 integer columns", profile.Format, len(profile.Columns), tt.format)
+			}
+		})
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		data   string
+		format string
+	}{
+		{
+			name:   "array",
+			file:   "stdin",
+			data:   `[{"id": 1, "tags": ["a"], "zip": "01234"}, {"id": 2.5, "extra": {"x": 1}, "zip": null}]`,
+			format: "JSON",
+		},
+		{
+			name:   "lines",
+			file:   "events.jsonl",
+			data:   "{\"id\": 1, \"tags\": [], \"zip\": \"01234\"}\n{\"id\": 2.5, \"extra\": {}}\n",
+			format: "JSON Lines",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := Read(tt.file, strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if profile.Format != tt.format || profile.Rows != 2 {
+				t.Fatalf("Read() = %s with %d rows, want %s with 2", profile.Format, profile.Rows, tt.format)
+			}
+			var got []string
+			for _, column := range profile.Columns {
+				got = append(got, column.Name+":"+column.Type)
+			}
+			want := "id:number tags:array zip:string extra:object"
+			if strings.Join(got, " ") != want {
+				t.Errorf("Read() columns = %s, want %s", strings.Join(got, " "), want)
+			}
+			if extra := profile.Columns[3]; extra.Nulls != 1 {
+				t.Errorf("Read() extra nulls = %d, want 1", extra.Nulls)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{name: "header only", file: "empty.csv", data: "a,b\n"},
+		{name: "empty", file: "stdin", data: ""},
+		{name: "not objects", file: "stdin", data: "[1, 2]"},
+		{name: "broken json", file: "data.json", data: `[{"a": 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(tt.file, strings.NewReader(tt.data))
+			if err == nil {
+				t.Fatal("Read() succeeded, want an error")
+			}
+			if failure.KindOf(err) != failure.UserInput {
+				t.Errorf("Read() error kind = %v, want UserInput", failure.KindOf(err))
+			}
+		})
+	}
+}
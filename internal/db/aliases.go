@@ -0,0 +1,132 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ShellAlias is a shell alias managed by Aura.
+type ShellAlias struct {
+	Name    string
+	Command string
+}
+
+// aliasesVersionKey is the meta key of the aliases version, which is bumped on
+// every change so generated alias files can tell whether they are current.
+const aliasesVersionKey = "aliases_version"
+
+// bumpAliasesVersion is the statement incrementing the aliases version.
+var bumpAliasesVersion = fmt.Sprintf(`INSERT INTO meta (key, value) VALUES (%s, '1')
+	ON CONFLICT(key) DO UPDATE SET value = CAST(value AS INTEGER) + 1;`, sqlString(aliasesVersionKey))
+
+// SetShellAlias adds a shell alias or replaces its command.
+func (db *DB) SetShellAlias(name, command string) error {
+	return db.changeShellAliases(
+		fmt.Sprintf(`INSERT OR REPLACE INTO shell_aliases (name, command, updated_at) VALUES (%s, %s, CURRENT_TIMESTAMP);`,
+			sqlString(name), sqlString(command)),
+		`INSERT OR REPLACE INTO shell_aliases (name, command, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)`,
+		name, command)
+}
+
+// RemoveShellAlias removes the shell alias with the given name.
+func (db *DB) RemoveShellAlias(name string) error {
+	return db.changeShellAliases(
+		fmt.Sprintf(`DELETE FROM shell_aliases WHERE name = %s;`, sqlString(name)),
+		`DELETE FROM shell_aliases WHERE name = ?`,
+		name)
+}
+
+// changeShellAliases runs a change of the shell aliases and bumps their
+// version in one transaction.
+func (db *DB) changeShellAliases(dockerStatement, query string, args ...any) error {
+	if db.isDockerMode {
+		cmd := db.dockerSQLite()
+		cmd.Stdin = strings.NewReader(strings.Join([]string{"BEGIN;", dockerStatement, bumpAliasesVersion, "COMMIT;"}, "\n"))
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to update shell aliases: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to update shell aliases: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to update shell aliases: %w", err)
+	}
+	if _, err := tx.Exec(bumpAliasesVersion); err != nil {
+		return fmt.Errorf("failed to bump aliases version: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ListShellAliases returns all shell aliases sorted by name.
+func (db *DB) ListShellAliases() ([]ShellAlias, error) {
+	if db.isDockerMode {
+		// Commands may contain the column separator, so they are read hex
+		// encoded.
+		results, err := db.queryDockerSQL(`SELECT name, hex(command) FROM shell_aliases ORDER BY name;`)
+		if err != nil {
+			return nil, err
+		}
+
+		var aliases []ShellAlias
+		for _, parts := range results {
+			if len(parts) < 2 {
+				continue
+			}
+			command, _ := hex.DecodeString(parts[1])
+			aliases = append(aliases, ShellAlias{Name: parts[0], Command: string(command)})
+		}
+		return aliases, nil
+	}
+
+	rows, err := db.conn.Query(`SELECT name, command FROM shell_aliases ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shell aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []ShellAlias
+	for rows.Next() {
+		var alias ShellAlias
+		if err := rows.Scan(&alias.Name, &alias.Command); err != nil {
+			return nil, fmt.Errorf("failed to scan shell alias: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, nil
+}
+
+// ShellAliasesVersion returns the version of the shell aliases, 0 if they were
+// never changed.
+func (db *DB) ShellAliasesVersion() (int, error) {
+	var value string
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT value FROM meta WHERE key = %s;`, sqlString(aliasesVersionKey)))
+		if err != nil {
+			return 0, err
+		}
+		if len(results) == 0 || len(results[0]) == 0 {
+			return 0, nil
+		}
+		value = results[0][0]
+	} else {
+		err := db.conn.QueryRow(`SELECT COALESCE((SELECT value FROM meta WHERE key = ?), '0')`, aliasesVersionKey).Scan(&value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read aliases version: %w", err)
+		}
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid aliases version '%s'", value)
+	}
+	return version, nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import "testing"
+
+func TestShellAliases(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	before, err := db.ShellAliasesVersion()
+	if err != nil {
+		t.Fatalf("ShellAliasesVersion() error = %v", err)
+	}
+
+	if err := db.SetShellAlias("gl", "git log --oneline | head"); err != nil {
+		t.Fatalf("SetShellAlias() error = %v", err)
+	}
+	if err := db.SetShellAlias("gs", "git status"); err != nil {
+		t.Fatalf("SetShellAlias() error = %v", err)
+	}
+	if err := db.SetShellAlias("gs", "git status -sb"); err != nil {
+		t.Fatalf("SetShellAlias() error = %v", err)
+	}
+
+	aliases, err := db.ListShellAliases()
+	if err != nil {
+		t.Fatalf("ListShellAliases() error = %v", err)
+	}
+	commands := make(map[string]string)
+	for _, alias := range aliases {
+		commands[alias.Name] = alias.Command
+	}
+	if commands["gl"] != "git log --oneline | head" || commands["gs"] != "git status -sb" {
+		t.Errorf("Unexpected aliases %+v", aliases)
+	}
+
+	if err := db.RemoveShellAlias("gl"); err != nil {
+		t.Fatalf("RemoveShellAlias() error = %v", err)
+	}
+	aliases, _ = db.ListShellAliases()
+	for _, alias := range aliases {
+		if alias.Name == "gl" {
+			t.Error("Expected alias gl to be removed")
+		}
+	}
+
+	after, err := db.ShellAliasesVersion()
+	if err != nil {
+		t.Fatalf("ShellAliasesVersion() error = %v", err)
+	}
+	if after != before+4 {
+		t.Errorf("ShellAliasesVersion() = %d, want %d", after, before+4)
+	}
+}
@@ -2,36 +2,105 @@ package db
 
 import (
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
 )
 
 // Bookmark represents a directory bookmark.
 type Bookmark struct {
-	ID        int       `json:"id"`
-	Alias     string    `json:"alias"`
+	ID    int    `json:"id"`
+	Alias string `json:"alias"`
+	// Path is in the form of the environment Aura runs in, Windows or WSL,
+	// see config.LocalPath.
 	Path      string    `json:"path"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// AddBookmark adds a new bookmark to the database.
+// Statements run for every bookmark added or looked up, and every visit.
+const (
+	addBookmarkQuery          = `INSERT INTO bookmarks (alias, path) VALUES (?, ?)`
+	getBookmarkQuery          = `SELECT id, alias, path, created_at FROM bookmarks WHERE alias = ?`
+	setBookmarkMetadataQuery  = `INSERT OR REPLACE INTO bookmark_metadata (alias, key, value) VALUES (?, ?, ?)`
+	addNavigationHistoryQuery = `INSERT INTO navigation_history (path) VALUES (?)`
+)
+
+// AddBookmark adds a new bookmark to the database. The path is stored
+// normalized, see config.NormalizePath.
 func (db *DB) AddBookmark(alias, path string) error {
+	path = config.NormalizePath(path)
 	if db.isDockerMode {
 		return db.addBookmarkDocker(alias, path)
 	}
 
-	query := `INSERT INTO bookmarks (alias, path) VALUES (?, ?)`
-	_, err := db.conn.Exec(query, alias, path)
+	stmt, err := db.prepared(addBookmarkQuery)
+	if err == nil {
+		_, err = stmt.Exec(alias, path)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to add bookmark: %w", err)
 	}
 	return nil
 }
 
+// AddBookmarks adds bookmarks with their metadata, by alias, in one
+// transaction, such as those restored by aura undo: all of them are added
+// or, if one fails, none.
+func (db *DB) AddBookmarks(bookmarks []*Bookmark, metadata map[string]map[string]string) error {
+	if db.isDockerMode {
+		statements := []string{"BEGIN;"}
+		for _, bookmark := range bookmarks {
+			alias := sqlString(bookmark.Alias)
+			statements = append(statements, fmt.Sprintf("INSERT INTO bookmarks (alias, path) VALUES (%s, %s);",
+				alias, sqlString(config.NormalizePath(bookmark.Path))))
+			for key, value := range metadata[bookmark.Alias] {
+				statements = append(statements, fmt.Sprintf("INSERT OR REPLACE INTO bookmark_metadata (alias, key, value) VALUES (%s, %s, %s);",
+					alias, sqlString(key), sqlString(value)))
+			}
+		}
+		cmd := db.dockerSQLite("-bail")
+		cmd.Stdin = strings.NewReader(strings.Join(append(statements, "COMMIT;"), "\n"))
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to add bookmarks: %w", err)
+		}
+		return nil
+	}
+
+	add, err := db.prepared(addBookmarkQuery)
+	if err != nil {
+		return fmt.Errorf("failed to add bookmarks: %w", err)
+	}
+	set, err := db.prepared(setBookmarkMetadataQuery)
+	if err != nil {
+		return fmt.Errorf("failed to add bookmarks: %w", err)
+	}
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to add bookmarks: %w", err)
+	}
+	defer tx.Rollback()
+	add, set = tx.Stmt(add), tx.Stmt(set)
+	for _, bookmark := range bookmarks {
+		if _, err := add.Exec(bookmark.Alias, config.NormalizePath(bookmark.Path)); err != nil {
+			return fmt.Errorf("failed to add bookmark '%s': %w", bookmark.Alias, err)
+		}
+		for key, value := range metadata[bookmark.Alias] {
+			if _, err := set.Exec(bookmark.Alias, key, value); err != nil {
+				return fmt.Errorf("failed to set metadata of bookmark '%s': %w", bookmark.Alias, err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to add bookmarks: %w", err)
+	}
+	return nil
+}
+
 func (db *DB) addBookmarkDocker(alias, path string) error {
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db",
+	cmd := db.dockerSQLite(
 		fmt.Sprintf("INSERT INTO bookmarks (alias, path) VALUES ('%s', '%s');",
 			strings.ReplaceAll(alias, "'", "''"),
 			strings.ReplaceAll(path, "'", "''")))
@@ -45,23 +114,26 @@ func (db *DB) GetBookmark(alias string) (*Bookmark, error) {
 		return db.getBookmarkDocker(alias)
 	}
 
-	query := `SELECT id, alias, path, created_at FROM bookmarks WHERE alias = ?`
-	row := db.conn.QueryRow(query, alias)
+	stmt, err := db.prepared(getBookmarkQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmark: %w", err)
+	}
 
 	var bookmark Bookmark
-	err := row.Scan(&bookmark.ID, &bookmark.Alias, &bookmark.Path, &bookmark.CreatedAt)
+	err = stmt.QueryRow(alias).Scan(&bookmark.ID, &bookmark.Alias, &bookmark.Path, &bookmark.CreatedAt)
 	if err != nil {
 		if strings.Contains(err.Error(), "no rows") {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get bookmark: %w", err)
 	}
+	bookmark.Path = config.LocalPath(bookmark.Path)
 
 	return &bookmark, nil
 }
 
 func (db *DB) getBookmarkDocker(alias string) (*Bookmark, error) {
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db",
+	cmd := db.dockerSQLite(
 		fmt.Sprintf("SELECT id, alias, path, created_at FROM bookmarks WHERE alias = '%s';",
 			strings.ReplaceAll(alias, "'", "''")))
 
@@ -86,7 +158,7 @@ func (db *DB) getBookmarkDocker(alias string) (*Bookmark, error) {
 	return &Bookmark{
 		ID:        id,
 		Alias:     parts[1],
-		Path:      parts[2],
+		Path:      config.LocalPath(parts[2]),
 		CreatedAt: createdAt,
 	}, nil
 }
@@ -111,6 +183,7 @@ func (db *DB) ListBookmarks() ([]*Bookmark, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan bookmark: %w", err)
 		}
+		bookmark.Path = config.LocalPath(bookmark.Path)
 		bookmarks = append(bookmarks, &bookmark)
 	}
 
@@ -135,7 +208,7 @@ func (db *DB) listBookmarksDocker() ([]*Bookmark, error) {
 		bookmarks = append(bookmarks, &Bookmark{
 			ID:        id,
 			Alias:     parts[1],
-			Path:      parts[2],
+			Path:      config.LocalPath(parts[2]),
 			CreatedAt: createdAt,
 		})
 	}
@@ -161,10 +234,13 @@ func (db *DB) RemoveBookmark(alias string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("bookmark '%s' not found", alias)
+		return failure.New(failure.NotFound, "bookmark '%s' not found", alias)
 	}
 
-	return nil
+	if err := db.removeBookmarkMetadata(alias); err != nil {
+		return err
+	}
+	return db.removeBookmarkUsage(alias)
 }
 
 func (db *DB) removeBookmarkDocker(alias string) error {
@@ -174,39 +250,74 @@ func (db *DB) removeBookmarkDocker(alias string) error {
 		return err
 	}
 	if existing == nil {
-		return fmt.Errorf("bookmark '%s' not found", alias)
+		return failure.New(failure.NotFound, "bookmark '%s' not found", alias)
 	}
 
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db",
+	cmd := db.dockerSQLite(
 		fmt.Sprintf("DELETE FROM bookmarks WHERE alias = '%s';",
 			strings.ReplaceAll(alias, "'", "''")))
 
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	if err := db.removeBookmarkMetadata(alias); err != nil {
+		return err
+	}
+	return db.removeBookmarkUsage(alias)
 }
 
-// AddNavigationHistory adds a path to navigation history.
+// UpdateBookmarkPath points an existing bookmark to a new path, keeping its
+// metadata.
+func (db *DB) UpdateBookmarkPath(alias, path string) error {
+	path = config.NormalizePath(path)
+	if db.isDockerMode {
+		cmd := db.dockerSQLite(
+			fmt.Sprintf("UPDATE bookmarks SET path = %s WHERE alias = %s;", sqlString(path), sqlString(alias)))
+		return cmd.Run()
+	}
+
+	result, err := db.conn.Exec(`UPDATE bookmarks SET path = ? WHERE alias = ?`, path, alias)
+	if err != nil {
+		return fmt.Errorf("failed to update bookmark: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return failure.New(failure.NotFound, "bookmark '%s' not found", alias)
+	}
+	return nil
+}
+
+// AddNavigationHistory adds a path to navigation history, normalized so
+// visits through symbolic links count for the same directory. Nothing is
+// added in read-only mode.
 func (db *DB) AddNavigationHistory(path string) error {
+	if db.readOnly {
+		return nil
+	}
+	path = config.NormalizePath(path)
 	if db.isDockerMode {
 		return db.addNavigationHistoryDocker(path)
 	}
 
-	query := `INSERT INTO navigation_history (path) VALUES (?)`
-	_, err := db.conn.Exec(query, path)
+	add, err := db.prepared(addNavigationHistoryQuery)
 	if err != nil {
 		return fmt.Errorf("failed to add navigation history: %w", err)
 	}
+	if _, err := add.Exec(path); err != nil {
+		return fmt.Errorf("failed to add navigation history: %w", err)
+	}
 	return nil
 }
 
 func (db *DB) addNavigationHistoryDocker(path string) error {
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db",
+	cmd := db.dockerSQLite(
 		fmt.Sprintf("INSERT INTO navigation_history (path) VALUES ('%s');",
 			strings.ReplaceAll(path, "'", "''")))
 
 	return cmd.Run()
 }
 
-// FuzzySearch performs fuzzy searching on both bookmarks and history.
+// FuzzySearch performs fuzzy searching on bookmarks, history and the
+// directory index, in that order of preference.
 func (db *DB) FuzzySearch(query string) ([]*Bookmark, error) {
 	// Normalize the query
 	query = strings.ToLower(strings.TrimSpace(query))
@@ -226,7 +337,16 @@ func (db *DB) FuzzySearch(query string) ([]*Bookmark, error) {
 	}
 
 	// If no bookmarks found, search in navigation history
-	return db.searchHistory(query)
+	history, err := db.searchHistory(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > 0 {
+		return history, nil
+	}
+
+	// Finally fall back to directories found by the indexer
+	return db.searchIndex(query)
 }
 
 func (db *DB) searchBookmarks(query string) ([]*Bookmark, error) {
@@ -263,6 +383,7 @@ func (db *DB) searchBookmarks(query string) ([]*Bookmark, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan bookmark: %w", err)
 		}
+		bookmark.Path = config.LocalPath(bookmark.Path)
 		bookmarks = append(bookmarks, &bookmark)
 	}
 
@@ -272,7 +393,7 @@ func (db *DB) searchBookmarks(query string) ([]*Bookmark, error) {
 func (db *DB) searchBookmarksDocker(query string) ([]*Bookmark, error) {
 	queryPattern := "%" + strings.ToLower(query) + "%"
 
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db",
+	cmd := db.dockerSQLite(
 		fmt.Sprintf(`SELECT id, alias, path, created_at FROM bookmarks 
 		WHERE LOWER(alias) LIKE '%s' OR LOWER(path) LIKE '%s' 
 		ORDER BY alias;`, queryPattern, queryPattern))
@@ -301,7 +422,7 @@ func (db *DB) searchBookmarksDocker(query string) ([]*Bookmark, error) {
 		bookmarks = append(bookmarks, &Bookmark{
 			ID:        id,
 			Alias:     parts[1],
-			Path:      parts[2],
+			Path:      config.LocalPath(parts[2]),
 			CreatedAt: createdAt,
 		})
 	}
@@ -338,7 +459,7 @@ func (db *DB) searchHistory(query string) ([]*Bookmark, error) {
 		historyResults = append(historyResults, &Bookmark{
 			ID:    id,
 			Alias: fmt.Sprintf("history:%s", path),
-			Path:  path,
+			Path:  config.LocalPath(path),
 		})
 		id--
 	}
@@ -349,7 +470,7 @@ func (db *DB) searchHistory(query string) ([]*Bookmark, error) {
 func (db *DB) searchHistoryDocker(query string) ([]*Bookmark, error) {
 	queryPattern := "%" + strings.ToLower(query) + "%"
 
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db",
+	cmd := db.dockerSQLite(
 		fmt.Sprintf(`SELECT DISTINCT path FROM navigation_history 
 		WHERE LOWER(path) LIKE '%s' 
 		ORDER BY accessed_at DESC LIMIT 10;`, queryPattern))
@@ -371,10 +492,186 @@ func (db *DB) searchHistoryDocker(query string) ([]*Bookmark, error) {
 		historyResults = append(historyResults, &Bookmark{
 			ID:    id,
 			Alias: fmt.Sprintf("history:%s", line),
-			Path:  line,
+			Path:  config.LocalPath(line),
 		})
 		id--
 	}
 
 	return historyResults, nil
 }
+
+// RecentDirectories returns up to limit distinct paths from the navigation
+// history, most recently visited first.
+func (db *DB) RecentDirectories(limit int) ([]string, error) {
+	query := `
+		SELECT path
+		FROM navigation_history
+		GROUP BY path
+		ORDER BY MAX(accessed_at) DESC, MAX(id) DESC
+		LIMIT %d`
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(query, limit) + ";")
+		if err != nil {
+			return nil, err
+		}
+		var paths []string
+		for _, parts := range results {
+			paths = append(paths, config.LocalPath(parts[0]))
+		}
+		return paths, nil
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(query, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan history: %w", err)
+		}
+		paths = append(paths, config.LocalPath(path))
+	}
+	return paths, nil
+}
+
+// FrequentDirectories returns the most visited directories, most visited first.
+func (db *DB) FrequentDirectories(limit int) ([]string, error) {
+	query := `
+		SELECT path
+		FROM navigation_history
+		GROUP BY path
+		ORDER BY COUNT(*) DESC, MAX(id) DESC
+		LIMIT %d`
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(query, limit) + ";")
+		if err != nil {
+			return nil, err
+		}
+		var paths []string
+		for _, parts := range results {
+			paths = append(paths, config.LocalPath(parts[0]))
+		}
+		return paths, nil
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(query, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan history: %w", err)
+		}
+		paths = append(paths, config.LocalPath(path))
+	}
+	return paths, nil
+}
+
+// DirectoryVisits is how often a directory was visited.
+type DirectoryVisits struct {
+	Path   string
+	Visits int
+}
+
+// VisitedDirectories returns the directories visited since since, most
+// visited first.
+func (db *DB) VisitedDirectories(since time.Time) ([]DirectoryVisits, error) {
+	query := `
+		SELECT path, COUNT(*)
+		FROM navigation_history
+		WHERE accessed_at >= %s
+		GROUP BY path
+		ORDER BY COUNT(*) DESC, MAX(id) DESC`
+	stamp := since.UTC().Format("2006-01-02 15:04:05")
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(query, sqlString(stamp)) + ";")
+		if err != nil {
+			return nil, err
+		}
+		var dirs []DirectoryVisits
+		for _, parts := range results {
+			if len(parts) < 2 {
+				continue
+			}
+			visits, _ := strconv.Atoi(parts[1])
+			dirs = append(dirs, DirectoryVisits{Path: config.LocalPath(parts[0]), Visits: visits})
+		}
+		return dirs, nil
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(query, "?"), stamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer rows.Close()
+
+	var dirs []DirectoryVisits
+	for rows.Next() {
+		var dir DirectoryVisits
+		if err := rows.Scan(&dir.Path, &dir.Visits); err != nil {
+			return nil, fmt.Errorf("failed to scan history: %w", err)
+		}
+		dir.Path = config.LocalPath(dir.Path)
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// NavigationEntry is a visit of a directory.
+type NavigationEntry struct {
+	Path string
+	Time time.Time
+}
+
+// NavigationHistory returns the visits of directories since since, oldest
+// first.
+func (db *DB) NavigationHistory(since time.Time) ([]NavigationEntry, error) {
+	query := `SELECT path, accessed_at FROM navigation_history WHERE accessed_at >= %s ORDER BY accessed_at, id`
+	stamp := since.UTC().Format("2006-01-02 15:04:05")
+
+	var entries []NavigationEntry
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(query, sqlString(stamp)) + ";")
+		if err != nil {
+			return nil, err
+		}
+		for _, parts := range results {
+			if len(parts) < 2 {
+				continue
+			}
+			accessed, err := time.Parse("2006-01-02 15:04:05", parts[1])
+			if err != nil {
+				continue
+			}
+			entries = append(entries, NavigationEntry{Path: config.LocalPath(parts[0]), Time: accessed.Local()})
+		}
+		return entries, nil
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(query, "?"), stamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var entry NavigationEntry
+		if err := rows.Scan(&entry.Path, &entry.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan history: %w", err)
+		}
+		entry.Path = config.LocalPath(entry.Path)
+		entry.Time = entry.Time.Local()
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -1,9 +1,31 @@
 package db
 
 import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
 )
 
+// newEmptyDB returns a database of its own for tb, for tests and benchmarks
+// that fill tables.
+func newEmptyDB(tb testing.TB) *DB {
+	tb.Helper()
+	original := config.DatabasePath
+	config.DatabasePath = filepath.Join(tb.TempDir(), "aura.db")
+	defer func() { config.DatabasePath = original }()
+	db, err := New()
+	if err != nil {
+		tb.Fatalf("Failed to create database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return db
+}
+
 func TestAddBookmark(t *testing.T) {
 	db, err := New()
 	if err != nil {
@@ -205,6 +227,121 @@ func TestAddNavigationHistory(t *testing.T) {
 	}
 }
 
+func TestAddBookmarks(t *testing.T) {
+	db := newEmptyDB(t)
+
+	bookmarks := []*Bookmark{{Alias: "web", Path: "/test/batch/web"}, {Alias: "api", Path: "/test/batch/api"}}
+	metadata := map[string]map[string]string{"web": {"on_enter": "npm start"}}
+	if err := db.AddBookmarks(bookmarks, metadata); err != nil {
+		t.Fatalf("AddBookmarks() error = %v", err)
+	}
+	if got, _ := db.ListBookmarks(); len(got) != 2 {
+		t.Errorf("ListBookmarks() = %d bookmarks, want 2", len(got))
+	}
+	if got, _ := db.GetBookmarkMetadata("web"); got["on_enter"] != "npm start" {
+		t.Errorf("GetBookmarkMetadata() = %v, want the on_enter command", got)
+	}
+
+	// A duplicate alias fails all of them
+	err := db.AddBookmarks([]*Bookmark{{Alias: "docs", Path: "/test/batch/docs"}, {Alias: "web", Path: "/test/batch/other"}}, nil)
+	if err == nil {
+		t.Error("AddBookmarks() with an existing alias succeeded")
+	}
+	if got, _ := db.GetBookmark("docs"); got != nil {
+		t.Errorf("GetBookmark() = %+v after a failed AddBookmarks(), want none", got)
+	}
+}
+
+func TestRecentDirectories(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, path := range []string{"/test/recent/a", "/test/recent/b", "/test/recent/a"} {
+		if err := db.AddNavigationHistory(path); err != nil {
+			t.Fatalf("AddNavigationHistory() error = %v", err)
+		}
+	}
+
+	paths, err := db.RecentDirectories(2)
+	if err != nil {
+		t.Fatalf("RecentDirectories() error = %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/test/recent/a" || paths[1] != "/test/recent/b" {
+		t.Errorf("RecentDirectories() = %v, want [/test/recent/a /test/recent/b]", paths)
+	}
+}
+
+func TestVisitedDirectories(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, path := range []string{"/test/visited/a", "/test/visited/b", "/test/visited/b"} {
+		if err := db.AddNavigationHistory(path); err != nil {
+			t.Fatalf("AddNavigationHistory() error = %v", err)
+		}
+	}
+	if _, err := db.conn.Exec(`INSERT INTO navigation_history (path, accessed_at) VALUES ('/test/visited/old', '2020-01-01 10:00:00')`); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs, err := db.VisitedDirectories(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("VisitedDirectories() error = %v", err)
+	}
+	want := []DirectoryVisits{{Path: "/test/visited/b", Visits: 2}, {Path: "/test/visited/a", Visits: 1}}
+	var got []DirectoryVisits
+	for _, dir := range dirs {
+		if strings.HasPrefix(dir.Path, "/test/visited/") {
+			got = append(got, dir)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VisitedDirectories() = %v, want %v", got, want)
+	}
+}
+
+func TestNavigationHistory(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.conn.Exec(`INSERT INTO navigation_history (path, accessed_at) VALUES ('/test/timeline/old', '2020-01-01 10:00:00')`); err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now().Add(-time.Minute)
+	if err := db.AddNavigationHistory("/test/timeline/new"); err != nil {
+		t.Fatalf("AddNavigationHistory() error = %v", err)
+	}
+
+	entries, err := db.NavigationHistory(before)
+	if err != nil {
+		t.Fatalf("NavigationHistory() error = %v", err)
+	}
+	var found bool
+	for _, entry := range entries {
+		if entry.Path == "/test/timeline/old" {
+			t.Errorf("NavigationHistory() returned a visit before since")
+		}
+		if entry.Path == "/test/timeline/new" {
+			found = true
+			if entry.Time.Before(before) || entry.Time.After(time.Now().Add(time.Minute)) {
+				t.Errorf("NavigationHistory() time = %s, want about now", entry.Time)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("NavigationHistory() = %v, want the new visit", entries)
+	}
+}
+
 func TestFuzzySearch(t *testing.T) {
 	db, err := New()
 	if err != nil {
@@ -316,3 +453,91 @@ func TestFuzzySearch(t *testing.T) {
 		})
 	}
 }
+
+// benchmarkRows is the number of bookmarks and visits the benchmarks work
+// with, more than most users collect.
+const benchmarkRows = 10000
+
+// benchmarkBookmarks returns benchmarkRows bookmarks, with a prefix unique to
+// run so they can be added again.
+func benchmarkBookmarks(run int) []*Bookmark {
+	bookmarks := make([]*Bookmark, benchmarkRows)
+	for i := range bookmarks {
+		bookmarks[i] = &Bookmark{Alias: fmt.Sprintf("b%d_%d", run, i), Path: fmt.Sprintf("/bench/%d/%d", run, i)}
+	}
+	return bookmarks
+}
+
+// BenchmarkAddBookmarks compares adding benchmarkRows bookmarks in one
+// transaction with adding them one by one.
+func BenchmarkAddBookmarks(b *testing.B) {
+	b.Run("batch", func(b *testing.B) {
+		db := newEmptyDB(b)
+		for n := 0; n < b.N; n++ {
+			if err := db.AddBookmarks(benchmarkBookmarks(n), nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("each", func(b *testing.B) {
+		db := newEmptyDB(b)
+		for n := 0; n < b.N; n++ {
+			for _, bookmark := range benchmarkBookmarks(n) {
+				if err := db.AddBookmark(bookmark.Alias, bookmark.Path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkGetBookmark compares looking up bookmarks among benchmarkRows
+// with the prepared statement and with the query parsed every time.
+func BenchmarkGetBookmark(b *testing.B) {
+	db := newEmptyDB(b)
+	if err := db.AddBookmarks(benchmarkBookmarks(0), nil); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("prepared", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if bookmark, err := db.GetBookmark(fmt.Sprintf("b0_%d", n%benchmarkRows)); err != nil || bookmark == nil {
+				b.Fatalf("GetBookmark() = %v, %v", bookmark, err)
+			}
+		}
+	})
+	b.Run("unprepared", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			var bookmark Bookmark
+			if err := db.conn.QueryRow(getBookmarkQuery, fmt.Sprintf("b0_%d", n%benchmarkRows)).Scan(&bookmark.ID, &bookmark.Alias, &bookmark.Path, &bookmark.CreatedAt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkAddNavigationHistory compares adding visits to a long history
+// with the prepared statement and with the query parsed each time.
+func BenchmarkAddNavigationHistory(b *testing.B) {
+	db := newEmptyDB(b)
+	fill := fmt.Sprintf(`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < %d)
+		INSERT INTO navigation_history (path) SELECT '/bench/history/' || (i %% 100) FROM n`, benchmarkRows)
+	if _, err := db.conn.Exec(fill); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("prepared", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if err := db.AddNavigationHistory(fmt.Sprintf("/bench/history/%d", n%100)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unprepared", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if _, err := db.conn.Exec(addNavigationHistoryQuery, fmt.Sprintf("/bench/history/%d", n%100)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Conversation is a question asked to the AI assistant and its answer.
+type Conversation struct {
+	ID        int64
+	Question  string
+	Answer    string
+	Model     string
+	CreatedAt time.Time
+}
+
+// SaveConversation stores a question and the answer of model.
+func (db *DB) SaveConversation(question, answer, model string) error {
+	if db.isDockerMode {
+		cmd := db.dockerSQLite(
+			fmt.Sprintf("INSERT INTO conversations (question, answer, model) VALUES (%s, %s, %s);",
+				sqlString(question), sqlString(answer), sqlString(model)))
+		return cmd.Run()
+	}
+
+	query := `INSERT INTO conversations (question, answer, model) VALUES (?, ?, ?)`
+	if _, err := db.conn.Exec(query, question, answer, model); err != nil {
+		return fmt.Errorf("failed to save conversation: %w", err)
+	}
+	return nil
+}
+
+// ListConversations returns the conversations since the given time, newest
+// first. A zero time returns all conversations.
+func (db *DB) ListConversations(since time.Time) ([]Conversation, error) {
+	sinceValue := since.UTC().Format("2006-01-02 15:04:05")
+
+	if db.isDockerMode {
+		// Questions and answers span lines and may contain the column
+		// separator, so they are read hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT id, hex(question), hex(answer), model, created_at
+			FROM conversations WHERE created_at >= %s ORDER BY id DESC;`, sqlString(sinceValue)))
+		if err != nil {
+			return nil, err
+		}
+
+		var conversations []Conversation
+		for _, parts := range results {
+			if len(parts) < 5 {
+				continue
+			}
+			id, _ := strconv.ParseInt(parts[0], 10, 64)
+			question, _ := hex.DecodeString(parts[1])
+			answer, _ := hex.DecodeString(parts[2])
+			createdAt, _ := time.Parse("2006-01-02 15:04:05", parts[4])
+			conversations = append(conversations, Conversation{
+				ID:        id,
+				Question:  string(question),
+				Answer:    string(answer),
+				Model:     parts[3],
+				CreatedAt: createdAt,
+			})
+		}
+		return conversations, nil
+	}
+
+	rows, err := db.conn.Query(`SELECT id, question, answer, model, created_at
+		FROM conversations WHERE created_at >= ? ORDER BY id DESC`, sinceValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var conversations []Conversation
+	for rows.Next() {
+		var c Conversation
+		if err := rows.Scan(&c.ID, &c.Question, &c.Answer, &c.Model, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		conversations = append(conversations, c)
+	}
+	return conversations, nil
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConversations(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SaveConversation("how do systemd timers work?", "Create a .timer unit |\nnext to the .service unit.", "gpt-4o"); err != nil {
+		t.Fatalf("SaveConversation() error = %v", err)
+	}
+	if err := db.SaveConversation("how to find large files", "find . -size +100M", "gpt-4o-mini"); err != nil {
+		t.Fatalf("SaveConversation() error = %v", err)
+	}
+
+	conversations, err := db.ListConversations(time.Time{})
+	if err != nil {
+		t.Fatalf("ListConversations() error = %v", err)
+	}
+	if len(conversations) < 2 {
+		t.Fatalf("Expected 2 conversations, got %d", len(conversations))
+	}
+
+	latest := conversations[0]
+	if latest.Question != "how to find large files" || latest.Model != "gpt-4o-mini" {
+		t.Errorf("Expected newest conversation first, got %+v", latest)
+	}
+	if conversations[1].Answer != "Create a .timer unit |\nnext to the .service unit." {
+		t.Errorf("Answer not preserved: %q", conversations[1].Answer)
+	}
+	if latest.CreatedAt.IsZero() {
+		t.Error("Expected creation time to be set")
+	}
+
+	future, err := db.ListConversations(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListConversations() error = %v", err)
+	}
+	if len(future) != 0 {
+		t.Errorf("Expected no conversations since the future, got %d", len(future))
+	}
+}
@@ -3,12 +3,15 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
 
 	_ "modernc.org/sqlite"
 
 	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/failure"
 )
 
 // DB represents the database connection.
@@ -16,13 +19,22 @@ type DB struct {
 	conn          *sql.DB
 	isDockerMode  bool
 	containerName string
+	// readOnly opens the database read-only, so changes fail, see
+	// config.ReadOnly.
+	readOnly bool
+
+	// stmts caches the statements prepared by prepared, by query.
+	stmts   map[string]*sql.Stmt
+	stmtsMu sync.Mutex
 }
 
-// New creates a new database connection and initializes tables.
+// New creates a new database connection and initializes tables. In
+// read-only mode the database must exist and is used as it is.
 func New() (*DB, error) {
 	db := &DB{
 		isDockerMode:  config.IsDockerMode(),
 		containerName: "aura-db",
+		readOnly:      config.ReadOnly(),
 	}
 
 	if db.isDockerMode {
@@ -36,13 +48,24 @@ func New() (*DB, error) {
 		db.conn = nil
 	} else {
 		// Traditional file-based SQLite connection
-		conn, err := sql.Open("sqlite", config.DatabasePath)
+		dsn := config.DatabasePath
+		if db.readOnly {
+			if _, err := os.Stat(dsn); err != nil {
+				return nil, failure.New(failure.NotFound, "no database at %s to open read-only", dsn)
+			}
+			dsn = "file:" + dsn + "?mode=ro"
+		}
+		conn, err := sql.Open("sqlite", dsn)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open database: %w", err)
 		}
 		db.conn = conn
 	}
 
+	if db.readOnly {
+		// Creating tables and migrating them are changes, too
+		return db, nil
+	}
 	if err := db.initialize(); err != nil {
 		if db.conn != nil {
 			db.conn.Close()
@@ -53,14 +76,51 @@ func New() (*DB, error) {
 	return db, nil
 }
 
-// Close closes the database connection.
+// Close closes the prepared statements and the database connection.
 func (db *DB) Close() error {
+	db.stmtsMu.Lock()
+	for _, stmt := range db.stmts {
+		stmt.Close()
+	}
+	db.stmts = nil
+	db.stmtsMu.Unlock()
 	if db.conn != nil {
 		return db.conn.Close()
 	}
 	return nil
 }
 
+// prepared returns query prepared on the connection, preparing it on first
+// use, so statements run for every navigation or on every row of a batch
+// are parsed once. Not for Docker mode, which has no connection.
+func (db *DB) prepared(query string) (*sql.Stmt, error) {
+	db.stmtsMu.Lock()
+	defer db.stmtsMu.Unlock()
+	if stmt, ok := db.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := db.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if db.stmts == nil {
+		db.stmts = make(map[string]*sql.Stmt)
+	}
+	db.stmts[query] = stmt
+	return stmt, nil
+}
+
+// dockerSQLite returns the sqlite3 command on the database in the container
+// with args, such as a statement, reading further statements from its stdin.
+func (db *DB) dockerSQLite(args ...string) *exec.Cmd {
+	command := []string{"exec", "-i", db.containerName, "sqlite3"}
+	if db.readOnly {
+		command = append(command, "-readonly")
+	}
+	command = append(command, "/data/aura.db")
+	return exec.Command("docker", append(command, args...)...)
+}
+
 // execSQL executes SQL in either Docker or local mode
 func (db *DB) execSQL(query string, args ...any) error {
 	if db.isDockerMode {
@@ -80,7 +140,7 @@ func (db *DB) execDockerSQL(query string, args ...any) error {
 		sqlCmd = strings.ReplaceAll(sqlCmd, placeholder, fmt.Sprintf("'%v'", arg))
 	}
 
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db", sqlCmd)
+	cmd := db.dockerSQLite(sqlCmd)
 	return cmd.Run()
 }
 
@@ -93,7 +153,7 @@ func (db *DB) queryDockerSQL(query string, args ...any) ([][]string, error) {
 		sqlCmd = strings.ReplaceAll(sqlCmd, placeholder, fmt.Sprintf("'%v'", arg))
 	}
 
-	cmd := exec.Command("docker", "exec", db.containerName, "sqlite3", "/data/aura.db", sqlCmd)
+	cmd := db.dockerSQLite(sqlCmd)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -130,6 +190,134 @@ func (db *DB) initialize() error {
 		accessed_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);`
 
+	createDirectoriesTable := `
+	CREATE TABLE IF NOT EXISTS directories (
+		path TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		depth INTEGER NOT NULL DEFAULT 0,
+		is_project INTEGER NOT NULL DEFAULT 0
+	);`
+
+	createBookmarkMetadataTable := `
+	CREATE TABLE IF NOT EXISTS bookmark_metadata (
+		alias TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (alias, key)
+	);`
+
+	createBookmarkUsageTable := `
+	CREATE TABLE IF NOT EXISTS bookmark_usage (
+		alias TEXT PRIMARY KEY,
+		hits INTEGER NOT NULL DEFAULT 0,
+		last_used DATETIME
+	);`
+
+	createMetaTable := `
+	CREATE TABLE IF NOT EXISTS meta (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);`
+
+	createEmbeddingsTable := `
+	CREATE TABLE IF NOT EXISTS embeddings (
+		path TEXT NOT NULL,
+		model TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		vector TEXT NOT NULL,
+		PRIMARY KEY (path, model)
+	);`
+
+	createConversationsTable := `
+	CREATE TABLE IF NOT EXISTS conversations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		question TEXT NOT NULL,
+		answer TEXT NOT NULL,
+		model TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createShellAliasesTable := `
+	CREATE TABLE IF NOT EXISTS shell_aliases (
+		name TEXT PRIMARY KEY,
+		command TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createOperationsTable := `
+	CREATE TABLE IF NOT EXISTS operations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		description TEXT NOT NULL,
+		data TEXT NOT NULL,
+		undone INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createHTTPRequestsTable := `
+	CREATE TABLE IF NOT EXISTS http_requests (
+		name TEXT PRIMARY KEY,
+		method TEXT NOT NULL,
+		url TEXT NOT NULL,
+		items TEXT NOT NULL DEFAULT '[]',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createRemindersTable := `
+	CREATE TABLE IF NOT EXISTS reminders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL DEFAULT 'remind',
+		message TEXT NOT NULL,
+		due_at INTEGER NOT NULL,
+		delivered INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createPadsTable := `
+	CREATE TABLE IF NOT EXISTS pads (
+		name TEXT PRIMARY KEY,
+		content BLOB NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createSessionsTable := `
+	CREATE TABLE IF NOT EXISTS sessions (
+		name TEXT PRIMARY KEY,
+		dir TEXT NOT NULL,
+		commands TEXT NOT NULL DEFAULT '[]',
+		editor_file TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createSchedulesTable := `
+	CREATE TABLE IF NOT EXISTS schedules (
+		name TEXT PRIMARY KEY,
+		cron TEXT NOT NULL,
+		command TEXT NOT NULL,
+		targets TEXT NOT NULL DEFAULT '[]',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	createScheduleRunsTable := `
+	CREATE TABLE IF NOT EXISTS schedule_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		dir TEXT NOT NULL,
+		started_at INTEGER NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		exit_code INTEGER NOT NULL,
+		output TEXT NOT NULL DEFAULT ''
+	);`
+
+	createNotesTable := `
+	CREATE TABLE IF NOT EXISTS notes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		project TEXT NOT NULL DEFAULT '',
+		body TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
 	if err := db.execSQL(createBookmarksTable); err != nil {
 		return fmt.Errorf("failed to create bookmarks table: %w", err)
 	}
@@ -138,5 +326,107 @@ func (db *DB) initialize() error {
 		return fmt.Errorf("failed to create history table: %w", err)
 	}
 
+	if err := db.execSQL(createDirectoriesTable); err != nil {
+		return fmt.Errorf("failed to create directories table: %w", err)
+	}
+
+	if err := db.execSQL(createBookmarkMetadataTable); err != nil {
+		return fmt.Errorf("failed to create bookmark metadata table: %w", err)
+	}
+
+	if err := db.execSQL(createBookmarkUsageTable); err != nil {
+		return fmt.Errorf("failed to create bookmark usage table: %w", err)
+	}
+
+	if err := db.execSQL(createMetaTable); err != nil {
+		return fmt.Errorf("failed to create meta table: %w", err)
+	}
+
+	if err := db.execSQL(createEmbeddingsTable); err != nil {
+		return fmt.Errorf("failed to create embeddings table: %w", err)
+	}
+
+	if err := db.execSQL(createConversationsTable); err != nil {
+		return fmt.Errorf("failed to create conversations table: %w", err)
+	}
+
+	if err := db.execSQL(createShellAliasesTable); err != nil {
+		return fmt.Errorf("failed to create shell aliases table: %w", err)
+	}
+
+	if err := db.execSQL(createOperationsTable); err != nil {
+		return fmt.Errorf("failed to create operations table: %w", err)
+	}
+
+	if err := db.execSQL(createHTTPRequestsTable); err != nil {
+		return fmt.Errorf("failed to create HTTP requests table: %w", err)
+	}
+
+	if err := db.execSQL(createRemindersTable); err != nil {
+		return fmt.Errorf("failed to create reminders table: %w", err)
+	}
+
+	if err := db.execSQL(createPadsTable); err != nil {
+		return fmt.Errorf("failed to create pads table: %w", err)
+	}
+
+	if err := db.execSQL(createSessionsTable); err != nil {
+		return fmt.Errorf("failed to create sessions table: %w", err)
+	}
+
+	if err := db.execSQL(createSchedulesTable); err != nil {
+		return fmt.Errorf("failed to create schedules table: %w", err)
+	}
+
+	if err := db.execSQL(createScheduleRunsTable); err != nil {
+		return fmt.Errorf("failed to create schedule runs table: %w", err)
+	}
+
+	if err := db.execSQL(createNotesTable); err != nil {
+		return fmt.Errorf("failed to create notes table: %w", err)
+	}
+
+	if err := db.prepareSearchIndex(); err != nil {
+		return err
+	}
+
+	// Paths are normalized on a later start if another process holds the
+	// database now
+	db.normalizePaths()
+
 	return nil
 }
+
+// DumpSQLiteSchema returns the schema of the SQLite database file at path as
+// a sequence of SQL statements.
+func DumpSQLiteSchema(path string) (string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("failed to open database file: %w", err)
+	}
+
+	conn, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return "", fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.Query(`SELECT sql FROM sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' ORDER BY type DESC, name`)
+	if err != nil {
+		return "", fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			return "", fmt.Errorf("failed to scan schema: %w", err)
+		}
+		statements = append(statements, statement+";")
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	return strings.Join(statements, "\n\n"), nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/timfewi/aura-cli-go/internal/config"
@@ -101,17 +102,54 @@ func TestInitialize(t *testing.T) {
 	defer db.Close()
 
 	// Test that tables exist by trying to query them
-	_, err = db.conn.Query("SELECT COUNT(*) FROM bookmarks")
+	var count int
+	err = db.conn.QueryRow("SELECT COUNT(*) FROM bookmarks").Scan(&count)
 	if err != nil {
 		t.Errorf("Bookmarks table not initialized: %v", err)
 	}
 
-	_, err = db.conn.Query("SELECT COUNT(*) FROM navigation_history")
+	err = db.conn.QueryRow("SELECT COUNT(*) FROM navigation_history").Scan(&count)
 	if err != nil {
 		t.Errorf("Navigation history table not initialized: %v", err)
 	}
 }
 
+func TestNewReadOnly(t *testing.T) {
+	writable, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	writable.Close()
+
+	t.Setenv("AURA_READ_ONLY", "1")
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to open database read-only: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ListBookmarks(); err != nil {
+		t.Errorf("ListBookmarks() failed read-only: %v", err)
+	}
+	err = db.AddBookmark("readonly", t.TempDir())
+	if err == nil || !config.IsReadOnlyError(err) {
+		t.Errorf("AddBookmark() = %v, want a read-only error", err)
+	}
+	if err := db.AddNavigationHistory(t.TempDir()); err != nil {
+		t.Errorf("AddNavigationHistory() = %v, want nothing recorded", err)
+	}
+
+	originalPath := config.DatabasePath
+	defer func() { config.DatabasePath = originalPath }()
+	config.DatabasePath = filepath.Join(t.TempDir(), "missing.db")
+	if _, err := New(); err == nil {
+		t.Error("New() opened a missing database read-only")
+	}
+	if _, err := os.Stat(config.DatabasePath); err == nil {
+		t.Error("New() created the database in read-only mode")
+	}
+}
+
 // Helper function to check if Docker is available and running
 func isDockerAvailable() bool {
 	// Check if docker command exists
@@ -123,3 +161,26 @@ func isDockerAvailable() bool {
 	cmd := exec.Command("docker", "ps")
 	return cmd.Run() == nil
 }
+
+func TestDumpSQLiteSchema(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	db.Close()
+
+	schema, err := DumpSQLiteSchema(config.DatabasePath)
+	if err != nil {
+		t.Fatalf("DumpSQLiteSchema() error = %v", err)
+	}
+
+	for _, table := range []string{"bookmarks", "navigation_history"} {
+		if !strings.Contains(schema, table) {
+			t.Errorf("Expected schema to contain table %s, got: %s", table, schema)
+		}
+	}
+
+	if _, err := DumpSQLiteSchema(filepath.Join(config.ConfigDir, "missing.db")); err == nil {
+		t.Error("Expected error for missing database file")
+	}
+}
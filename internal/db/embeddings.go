@@ -0,0 +1,124 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Embedding is the embedding vector of the description of a directory.
+type Embedding struct {
+	Path  string
+	Model string
+	// Hash identifies the description the vector was computed from, so
+	// changed descriptions are embedded again.
+	Hash   string
+	Vector []float32
+}
+
+// SaveEmbeddings stores embeddings, replacing those of the same path and model.
+func (db *DB) SaveEmbeddings(embeddings []Embedding) error {
+	if db.isDockerMode {
+		statements := []string{"BEGIN;"}
+		for _, e := range embeddings {
+			statements = append(statements, fmt.Sprintf("INSERT OR REPLACE INTO embeddings (path, model, hash, vector) VALUES (%s, %s, %s, %s);",
+				sqlString(e.Path), sqlString(e.Model), sqlString(e.Hash), sqlString(encodeVector(e.Vector))))
+		}
+		statements = append(statements, "COMMIT;")
+
+		cmd := db.dockerSQLite()
+		cmd.Stdin = strings.NewReader(strings.Join(statements, "\n"))
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to save embeddings: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save embeddings: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.Prepare(`INSERT OR REPLACE INTO embeddings (path, model, hash, vector) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to save embeddings: %w", err)
+	}
+	defer insert.Close()
+
+	for _, e := range embeddings {
+		if _, err := insert.Exec(e.Path, e.Model, e.Hash, encodeVector(e.Vector)); err != nil {
+			return fmt.Errorf("failed to save embedding: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListEmbeddings returns the stored embeddings computed with model.
+func (db *DB) ListEmbeddings(model string) ([]Embedding, error) {
+	var rows [][]string
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf("SELECT path, hash, vector FROM embeddings WHERE model = %s;", sqlString(model)))
+		if err != nil {
+			return nil, err
+		}
+		rows = results
+	} else {
+		result, err := db.conn.Query(`SELECT path, hash, vector FROM embeddings WHERE model = ?`, model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list embeddings: %w", err)
+		}
+		defer result.Close()
+
+		for result.Next() {
+			var path, hash, vector string
+			if err := result.Scan(&path, &hash, &vector); err != nil {
+				return nil, fmt.Errorf("failed to scan embedding: %w", err)
+			}
+			rows = append(rows, []string{path, hash, vector})
+		}
+	}
+
+	var embeddings []Embedding
+	for _, row := range rows {
+		if len(row) < 3 {
+			continue
+		}
+		vector, err := decodeVector(row[2])
+		if err != nil {
+			continue
+		}
+		embeddings = append(embeddings, Embedding{Path: row[0], Model: model, Hash: row[1], Vector: vector})
+	}
+	return embeddings, nil
+}
+
+// encodeVector stores a vector as base64 of little endian float32 values, so
+// it survives the text output of the sqlite3 command line in Docker mode.
+func encodeVector(vector []float32) string {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func decodeVector(encoded string) ([]float32, error) {
+	buf, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf)%4 != 0 {
+		return nil, fmt.Errorf("invalid vector length %d", len(buf))
+	}
+
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector, nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import "testing"
+
+func TestEmbeddings(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	embeddings := []Embedding{
+		{Path: "/test/embed/billing", Model: "test-model", Hash: "h1", Vector: []float32{0.5, -1.25, 3}},
+		{Path: "/test/embed/docs", Model: "test-model", Hash: "h2", Vector: []float32{1, 0, 0}},
+		{Path: "/test/embed/billing", Model: "other-model", Hash: "h3", Vector: []float32{1}},
+	}
+	if err := db.SaveEmbeddings(embeddings); err != nil {
+		t.Fatalf("SaveEmbeddings() error = %v", err)
+	}
+
+	// Saving again replaces the embedding of the same path and model
+	updated := Embedding{Path: "/test/embed/docs", Model: "test-model", Hash: "h4", Vector: []float32{0, 1}}
+	if err := db.SaveEmbeddings([]Embedding{updated}); err != nil {
+		t.Fatalf("SaveEmbeddings() error = %v", err)
+	}
+
+	stored, err := db.ListEmbeddings("test-model")
+	if err != nil {
+		t.Fatalf("ListEmbeddings() error = %v", err)
+	}
+
+	byPath := make(map[string]Embedding)
+	for _, e := range stored {
+		byPath[e.Path] = e
+	}
+	if len(byPath) != 2 {
+		t.Fatalf("Expected 2 embeddings, got %+v", stored)
+	}
+
+	billing := byPath["/test/embed/billing"]
+	if billing.Hash != "h1" || len(billing.Vector) != 3 || billing.Vector[1] != -1.25 {
+		t.Errorf("Unexpected embedding %+v", billing)
+	}
+	if docs := byPath["/test/embed/docs"]; docs.Hash != "h4" || len(docs.Vector) != 2 {
+		t.Errorf("Expected updated embedding, got %+v", docs)
+	}
+}
+
+func TestFrequentDirectories(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, path := range []string{"/test/frequent/a", "/test/frequent/b", "/test/frequent/b", "/test/frequent/b", "/test/frequent/a"} {
+		if err := db.AddNavigationHistory(path); err != nil {
+			t.Fatalf("AddNavigationHistory() error = %v", err)
+		}
+	}
+
+	paths, err := db.FrequentDirectories(1)
+	if err != nil {
+		t.Fatalf("FrequentDirectories() error = %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/test/frequent/b" {
+		t.Errorf("FrequentDirectories() = %v, want [/test/frequent/b]", paths)
+	}
+}
@@ -0,0 +1,239 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IndexedDirectory is a directory stored in the directory index.
+type IndexedDirectory struct {
+	Path    string
+	Name    string
+	Depth   int
+	Project bool
+}
+
+// IndexStats summarizes the directory index.
+type IndexStats struct {
+	Directories int
+	Projects    int
+	BuiltAt     time.Time
+	Duration    time.Duration
+}
+
+// Meta keys describing the last index build.
+const (
+	indexBuiltKey    = "index_built_at"
+	indexDurationKey = "index_duration"
+)
+
+// ReplaceDirectories replaces the directory index with dirs and records when
+// and how long it took to build.
+func (db *DB) ReplaceDirectories(dirs []IndexedDirectory, duration time.Duration) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	if db.isDockerMode {
+		statements := []string{"BEGIN;", "DELETE FROM directories;"}
+		for _, dir := range dirs {
+			statements = append(statements, fmt.Sprintf("INSERT OR IGNORE INTO directories (path, name, depth, is_project) VALUES (%s, %s, %d, %d);",
+				sqlString(dir.Path), sqlString(dir.Name), dir.Depth, boolToInt(dir.Project)))
+		}
+		statements = append(statements,
+			fmt.Sprintf("INSERT OR REPLACE INTO meta (key, value) VALUES (%s, %s);", sqlString(indexBuiltKey), sqlString(now)),
+			fmt.Sprintf("INSERT OR REPLACE INTO meta (key, value) VALUES (%s, %s);", sqlString(indexDurationKey), sqlString(duration.String())),
+			"COMMIT;")
+
+		cmd := db.dockerSQLite()
+		cmd.Stdin = strings.NewReader(strings.Join(statements, "\n"))
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to replace directory index: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to replace directory index: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM directories`); err != nil {
+		return fmt.Errorf("failed to replace directory index: %w", err)
+	}
+
+	insert, err := tx.Prepare(`INSERT OR IGNORE INTO directories (path, name, depth, is_project) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to replace directory index: %w", err)
+	}
+	defer insert.Close()
+
+	for _, dir := range dirs {
+		if _, err := insert.Exec(dir.Path, dir.Name, dir.Depth, dir.Project); err != nil {
+			return fmt.Errorf("failed to add directory: %w", err)
+		}
+	}
+
+	meta := map[string]string{indexBuiltKey: now, indexDurationKey: duration.String()}
+	for key, value := range meta {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?)`, key, value); err != nil {
+			return fmt.Errorf("failed to record index build: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetIndexStats returns the size of the directory index and when it was
+// last built. BuiltAt is the zero time if it never was.
+func (db *DB) GetIndexStats() (IndexStats, error) {
+	var stats IndexStats
+	var builtAt, duration string
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT
+			(SELECT COUNT(*) FROM directories),
+			(SELECT COUNT(*) FROM directories WHERE is_project = 1),
+			COALESCE((SELECT value FROM meta WHERE key = %s), ''),
+			COALESCE((SELECT value FROM meta WHERE key = %s), '');`,
+			sqlString(indexBuiltKey), sqlString(indexDurationKey)))
+		if err != nil {
+			return stats, err
+		}
+		if len(results) == 0 || len(results[0]) < 4 {
+			return stats, fmt.Errorf("invalid index data")
+		}
+		stats.Directories, _ = strconv.Atoi(results[0][0])
+		stats.Projects, _ = strconv.Atoi(results[0][1])
+		builtAt, duration = results[0][2], results[0][3]
+	} else {
+		query := `SELECT
+			(SELECT COUNT(*) FROM directories),
+			(SELECT COUNT(*) FROM directories WHERE is_project = 1),
+			COALESCE((SELECT value FROM meta WHERE key = ?), ''),
+			COALESCE((SELECT value FROM meta WHERE key = ?), '')`
+		err := db.conn.QueryRow(query, indexBuiltKey, indexDurationKey).Scan(&stats.Directories, &stats.Projects, &builtAt, &duration)
+		if err != nil {
+			return stats, fmt.Errorf("failed to read index stats: %w", err)
+		}
+	}
+
+	if builtAt != "" {
+		stats.BuiltAt, _ = time.Parse(time.RFC3339, builtAt)
+	}
+	if duration != "" {
+		stats.Duration, _ = time.ParseDuration(duration)
+	}
+	return stats, nil
+}
+
+// ListIndexedDirectories returns the paths of all indexed directories,
+// project roots first.
+func (db *DB) ListIndexedDirectories() ([]string, error) {
+	query := `SELECT path FROM directories ORDER BY is_project DESC, depth, path`
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(query + ";")
+		if err != nil {
+			return nil, err
+		}
+		var paths []string
+		for _, parts := range results {
+			paths = append(paths, parts[0])
+		}
+		return paths, nil
+	}
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory index: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan directory: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// searchIndex returns indexed directories whose name matches query. Project
+// roots and exact matches come first, then shallower directories.
+func (db *DB) searchIndex(query string) ([]*Bookmark, error) {
+	queryPattern := "%" + query + "%"
+	prefixPattern := query + "%"
+
+	var paths []string
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT path FROM directories
+		WHERE LOWER(name) LIKE %s
+		ORDER BY CASE WHEN LOWER(name) = %s THEN 1 WHEN LOWER(name) LIKE %s THEN 2 ELSE 3 END, is_project DESC, depth, LENGTH(path)
+		LIMIT 10;`, sqlString(queryPattern), sqlString(query), sqlString(prefixPattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, parts := range results {
+			paths = append(paths, parts[0])
+		}
+	} else {
+		searchQuery := `
+		SELECT path
+		FROM directories
+		WHERE LOWER(name) LIKE ?
+		ORDER BY
+			CASE
+				WHEN LOWER(name) = ? THEN 1
+				WHEN LOWER(name) LIKE ? THEN 2
+				ELSE 3
+			END,
+			is_project DESC,
+			depth,
+			LENGTH(path)
+		LIMIT 10`
+
+		rows, err := db.conn.Query(searchQuery, queryPattern, query, prefixPattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search directory index: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var path string
+			if err := rows.Scan(&path); err != nil {
+				return nil, fmt.Errorf("failed to scan directory: %w", err)
+			}
+			paths = append(paths, path)
+		}
+	}
+
+	var results []*Bookmark
+	id := -1 // Use negative IDs to distinguish from real bookmarks
+	for _, path := range paths {
+		results = append(results, &Bookmark{
+			ID:    id,
+			Alias: fmt.Sprintf("index:%s", path),
+			Path:  path,
+		})
+		id--
+	}
+
+	return results, nil
+}
+
+// sqlString quotes s as an SQL string literal for statements run via the
+// sqlite3 command line in Docker mode.
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplaceDirectories(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.ReplaceDirectories([]IndexedDirectory{{Path: "/srv/code/old-service", Name: "old-service", Depth: 1}}, time.Second); err != nil {
+		t.Fatalf("ReplaceDirectories() error = %v", err)
+	}
+
+	dirs := []IndexedDirectory{
+		{Path: "/srv/code/billing-api", Name: "billing-api", Depth: 1, Project: true},
+		{Path: "/srv/code/tools/api", Name: "api", Depth: 2},
+		{Path: "/srv/code/api", Name: "api", Depth: 1, Project: true},
+		{Path: "/srv/code/o'brien", Name: "o'brien", Depth: 1},
+	}
+	if err := db.ReplaceDirectories(dirs, 2*time.Second); err != nil {
+		t.Fatalf("ReplaceDirectories() error = %v", err)
+	}
+
+	stats, err := db.GetIndexStats()
+	if err != nil {
+		t.Fatalf("GetIndexStats() error = %v", err)
+	}
+	if stats.Directories != 4 || stats.Projects != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if time.Since(stats.BuiltAt) > time.Minute || stats.Duration != 2*time.Second {
+		t.Errorf("Unexpected build info: %+v", stats)
+	}
+
+	results, err := db.FuzzySearch("api")
+	if err != nil {
+		t.Fatalf("FuzzySearch() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 directories, got %d", len(results))
+	}
+	if results[0].Path != "/srv/code/api" || results[1].Path != "/srv/code/tools/api" {
+		t.Errorf("Expected exact name matches first, projects before others, got %s, %s", results[0].Path, results[1].Path)
+	}
+	if results[0].Alias != "index:/srv/code/api" {
+		t.Errorf("Unexpected alias %q", results[0].Alias)
+	}
+
+	results, err = db.FuzzySearch("old-service")
+	if err != nil {
+		t.Fatalf("FuzzySearch() error = %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected replaced directories to be removed, got %+v", results)
+	}
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"fmt"
+)
+
+// SetBookmarkMetadata stores a metadata value, such as a startup command, for
+// the bookmark with the given alias.
+func (db *DB) SetBookmarkMetadata(alias, key, value string) error {
+	bookmark, err := db.GetBookmark(alias)
+	if err != nil {
+		return err
+	}
+	if bookmark == nil {
+		return fmt.Errorf("bookmark '%s' not found", alias)
+	}
+
+	if db.isDockerMode {
+		cmd := db.dockerSQLite(
+			fmt.Sprintf("INSERT OR REPLACE INTO bookmark_metadata (alias, key, value) VALUES (%s, %s, %s);",
+				sqlString(alias), sqlString(key), sqlString(value)))
+		return cmd.Run()
+	}
+
+	stmt, err := db.prepared(setBookmarkMetadataQuery)
+	if err == nil {
+		_, err = stmt.Exec(alias, key, value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set bookmark metadata: %w", err)
+	}
+	return nil
+}
+
+// GetBookmarkMetadata returns the metadata of the bookmark with the given alias.
+func (db *DB) GetBookmarkMetadata(alias string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf("SELECT key, value FROM bookmark_metadata WHERE alias = %s;", sqlString(alias)))
+		if err != nil {
+			return nil, err
+		}
+		for _, parts := range results {
+			if len(parts) >= 2 {
+				metadata[parts[0]] = parts[1]
+			}
+		}
+		return metadata, nil
+	}
+
+	rows, err := db.conn.Query(`SELECT key, value FROM bookmark_metadata WHERE alias = ?`, alias)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmark metadata: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan bookmark metadata: %w", err)
+		}
+		metadata[key] = value
+	}
+	return metadata, nil
+}
+
+// UnsetBookmarkMetadata removes a metadata value of the bookmark with the
+// given alias.
+func (db *DB) UnsetBookmarkMetadata(alias, key string) error {
+	if db.isDockerMode {
+		cmd := db.dockerSQLite(
+			fmt.Sprintf("DELETE FROM bookmark_metadata WHERE alias = %s AND key = %s;", sqlString(alias), sqlString(key)))
+		return cmd.Run()
+	}
+
+	if _, err := db.conn.Exec(`DELETE FROM bookmark_metadata WHERE alias = ? AND key = ?`, alias, key); err != nil {
+		return fmt.Errorf("failed to unset bookmark metadata: %w", err)
+	}
+	return nil
+}
+
+// removeBookmarkMetadata removes all metadata of the bookmark with the given
+// alias.
+func (db *DB) removeBookmarkMetadata(alias string) error {
+	if db.isDockerMode {
+		cmd := db.dockerSQLite(
+			fmt.Sprintf("DELETE FROM bookmark_metadata WHERE alias = %s;", sqlString(alias)))
+		return cmd.Run()
+	}
+
+	if _, err := db.conn.Exec(`DELETE FROM bookmark_metadata WHERE alias = ?`, alias); err != nil {
+		return fmt.Errorf("failed to remove bookmark metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import "testing"
+
+func TestBookmarkMetadata(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetBookmarkMetadata("missing", "tmux", "make dev"); err == nil {
+		t.Error("Expected error for unknown bookmark")
+	}
+
+	if err := db.AddBookmark("testmeta", "/test/meta"); err != nil {
+		t.Fatalf("Failed to add bookmark: %v", err)
+	}
+	if err := db.SetBookmarkMetadata("testmeta", "tmux", "make dev"); err != nil {
+		t.Fatalf("SetBookmarkMetadata() error = %v", err)
+	}
+	if err := db.SetBookmarkMetadata("testmeta", "tmux", "npm run dev"); err != nil {
+		t.Fatalf("SetBookmarkMetadata() error = %v", err)
+	}
+
+	// Metadata survives moving the bookmark
+	if err := db.UpdateBookmarkPath("testmeta", "/test/meta/moved"); err != nil {
+		t.Fatalf("UpdateBookmarkPath() error = %v", err)
+	}
+
+	metadata, err := db.GetBookmarkMetadata("testmeta")
+	if err != nil {
+		t.Fatalf("GetBookmarkMetadata() error = %v", err)
+	}
+	if metadata["tmux"] != "npm run dev" {
+		t.Errorf("Expected updated tmux command, got %v", metadata)
+	}
+
+	if err := db.UnsetBookmarkMetadata("testmeta", "tmux"); err != nil {
+		t.Fatalf("UnsetBookmarkMetadata() error = %v", err)
+	}
+	if metadata, _ := db.GetBookmarkMetadata("testmeta"); len(metadata) != 0 {
+		t.Errorf("Expected no metadata after unset, got %v", metadata)
+	}
+
+	// Removing a bookmark removes its metadata
+	if err := db.SetBookmarkMetadata("testmeta", "tmux", "make dev"); err != nil {
+		t.Fatalf("SetBookmarkMetadata() error = %v", err)
+	}
+	if err := db.RemoveBookmark("testmeta"); err != nil {
+		t.Fatalf("RemoveBookmark() error = %v", err)
+	}
+	if metadata, _ := db.GetBookmarkMetadata("testmeta"); len(metadata) != 0 {
+		t.Errorf("Expected metadata to be removed with the bookmark, got %v", metadata)
+	}
+}
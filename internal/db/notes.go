@@ -0,0 +1,202 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Note is a note of 'aura note', kept for a project or globally.
+type Note struct {
+	ID int64
+	// Project is the root directory of the project the note belongs to, or
+	// empty for a global note.
+	Project   string
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Snippet is the matching part of the body with the matches between
+	// SnippetStart and SnippetEnd, set by SearchNotes.
+	Snippet string
+}
+
+// Markers of the matches in the snippets of SearchNotes.
+const (
+	SnippetStart = "\x02"
+	SnippetEnd   = "\x03"
+)
+
+// AddNote adds a note to project, empty for a global note, and returns its
+// ID.
+func (db *DB) AddNote(project, body string) (int64, error) {
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(`INSERT INTO notes (project, body) VALUES (%s, %s); SELECT last_insert_rowid();`,
+			sqlString(project), sqlString(body)))
+		if err != nil {
+			return 0, err
+		}
+		if len(results) == 0 {
+			return 0, fmt.Errorf("failed to add note: no ID returned")
+		}
+		return strconv.ParseInt(results[0][0], 10, 64)
+	}
+
+	result, err := db.conn.Exec(`INSERT INTO notes (project, body) VALUES (?, ?)`, project, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add note: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// GetNote returns the note with id, or nil if there is none.
+func (db *DB) GetNote(id int64) (*Note, error) {
+	notes, err := db.queryNotes("''", fmt.Sprintf(`FROM notes WHERE id = %d`, id))
+	if err != nil || len(notes) == 0 {
+		return nil, err
+	}
+	return &notes[0], nil
+}
+
+// ListNotes returns the notes of projects, the most recently updated first.
+// An empty project stands for the global notes; without projects the notes
+// of all of them are returned.
+func (db *DB) ListNotes(projects ...string) ([]Note, error) {
+	return db.queryNotes("''", fmt.Sprintf(`FROM notes %s ORDER BY notes.updated_at DESC, notes.id DESC`, projectFilter("WHERE", projects)))
+}
+
+// CountNotes returns how many notes project has.
+func (db *DB) CountNotes(project string) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM notes WHERE project = %s`, sqlString(project))
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(query + ";")
+		if err != nil || len(results) == 0 {
+			return 0, err
+		}
+		return strconv.Atoi(results[0][0])
+	}
+
+	var count int
+	if err := db.conn.QueryRow(query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count notes: %w", err)
+	}
+	return count, nil
+}
+
+// SearchNotes returns up to limit notes of projects, or of all projects
+// without any, matching all words of query as prefixes, the best matches
+// first.
+func (db *DB) SearchNotes(query string, limit int, projects ...string) ([]Note, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	snippet := fmt.Sprintf(`snippet(search_index, -1, '%s', '%s', '…', 12)`, SnippetStart, SnippetEnd)
+	return db.queryNotes(snippet, fmt.Sprintf(`FROM search_index JOIN notes ON notes.id = CAST(search_index.ref AS INTEGER)
+		WHERE search_index MATCH %s AND search_index.kind = 'note' %s ORDER BY bm25(search_index) LIMIT %d`, sqlString(match), projectFilter("AND", projects), limit))
+}
+
+// UpdateNote replaces the body of a note and reports whether there was one.
+func (db *DB) UpdateNote(id int64, body string) (bool, error) {
+	if db.isDockerMode {
+		changed, err := db.dockerChanges(fmt.Sprintf(`UPDATE notes SET body = %s, updated_at = CURRENT_TIMESTAMP WHERE id = %d`, sqlString(body), id))
+		return changed > 0, err
+	}
+	result, err := db.conn.Exec(`UPDATE notes SET body = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, body, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update note: %w", err)
+	}
+	changed, err := result.RowsAffected()
+	return changed > 0, err
+}
+
+// RemoveNote removes a note and reports whether there was one.
+func (db *DB) RemoveNote(id int64) (bool, error) {
+	statement := fmt.Sprintf(`DELETE FROM notes WHERE id = %d`, id)
+	if db.isDockerMode {
+		changed, err := db.dockerChanges(statement)
+		return changed > 0, err
+	}
+	result, err := db.conn.Exec(statement)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove note: %w", err)
+	}
+	changed, err := result.RowsAffected()
+	return changed > 0, err
+}
+
+// projectFilter returns the condition limiting notes to projects, joined
+// with keyword, or nothing without projects.
+func projectFilter(keyword string, projects []string) string {
+	if len(projects) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(projects))
+	for i, project := range projects {
+		quoted[i] = sqlString(project)
+	}
+	return fmt.Sprintf("%s notes.project IN (%s)", keyword, strings.Join(quoted, ", "))
+}
+
+// ftsQuery turns the words of query into an FTS5 query matching entries
+// with all of them as prefixes. The words are quoted, so characters of the
+// query syntax in them are searched for instead of interpreted.
+func ftsQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// queryNotes selects notes with snippet, an expression of the snippet, and
+// rest, the FROM clause and any after it.
+func (db *DB) queryNotes(snippet, rest string) ([]Note, error) {
+	if db.isDockerMode {
+		// Projects, bodies and snippets may contain the column separator, so
+		// they are read hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT notes.id, hex(notes.project), hex(notes.body), notes.created_at, notes.updated_at, hex(%s) %s;`, snippet, rest))
+		if err != nil {
+			return nil, err
+		}
+
+		var notes []Note
+		for _, parts := range results {
+			if len(parts) < 6 {
+				continue
+			}
+			id, _ := strconv.ParseInt(parts[0], 10, 64)
+			project, _ := hex.DecodeString(parts[1])
+			body, _ := hex.DecodeString(parts[2])
+			createdAt, _ := time.Parse("2006-01-02 15:04:05", parts[3])
+			updatedAt, _ := time.Parse("2006-01-02 15:04:05", parts[4])
+			snippet, _ := hex.DecodeString(parts[5])
+			notes = append(notes, Note{
+				ID:        id,
+				Project:   string(project),
+				Body:      string(body),
+				CreatedAt: createdAt,
+				UpdatedAt: updatedAt,
+				Snippet:   string(snippet),
+			})
+		}
+		return notes, nil
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(`SELECT notes.id, notes.project, notes.body, notes.created_at, notes.updated_at, %s %s`, snippet, rest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var n Note
+		if err := rows.Scan(&n.ID, &n.Project, &n.Body, &n.CreatedAt, &n.UpdatedAt, &n.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	return notes, nil
+}
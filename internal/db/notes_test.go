@@ -0,0 +1,77 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotes(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	deploy, err := db.AddNote("/src/api", "How to deploy: run make release, then tag the commit")
+	if err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+	global, err := db.AddNote("", "Deployment freeze on Fridays")
+	if err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+	if _, err := db.AddNote("/src/web", `The "staging" server restarts nightly`); err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+
+	notes, err := db.ListNotes("/src/api", "")
+	if err != nil {
+		t.Fatalf("ListNotes() error = %v", err)
+	}
+	if len(notes) != 2 || notes[0].ID != global || notes[1].ID != deploy {
+		t.Errorf("ListNotes() = %+v, want the global note and the api note", notes)
+	}
+	if all, err := db.ListNotes(); err != nil || len(all) != 3 {
+		t.Errorf("ListNotes() of all projects = %d notes, %v, want 3", len(all), err)
+	}
+	if count, err := db.CountNotes("/src/api"); err != nil || count != 1 {
+		t.Errorf("CountNotes() = %d, %v, want 1", count, err)
+	}
+
+	matches, err := db.SearchNotes("deplo", 10)
+	if err != nil {
+		t.Fatalf("SearchNotes() error = %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("SearchNotes() = %+v, want both deploy notes", matches)
+	}
+	if !strings.Contains(matches[0].Snippet+matches[1].Snippet, SnippetStart+"deploy"+SnippetEnd) {
+		t.Errorf("SearchNotes() snippets = %q, %q, want the match marked", matches[0].Snippet, matches[1].Snippet)
+	}
+	if matches, err := db.SearchNotes("deploy release", 10, "/src/api"); err != nil || len(matches) != 1 || matches[0].ID != deploy {
+		t.Errorf("SearchNotes() of all words in a project = %+v, %v", matches, err)
+	}
+	if matches, err := db.SearchNotes(`"staging`, 10); err != nil || len(matches) != 1 {
+		t.Errorf("SearchNotes() with a quote = %+v, %v, want the staging note", matches, err)
+	}
+
+	if updated, err := db.UpdateNote(deploy, "Deploy with the release workflow"); err != nil || !updated {
+		t.Fatalf("UpdateNote() = %v, %v, want true", updated, err)
+	}
+	if matches, err := db.SearchNotes("make", 10); err != nil || len(matches) != 0 {
+		t.Errorf("SearchNotes() of replaced text = %+v, %v, want none", matches, err)
+	}
+	if note, err := db.GetNote(deploy); err != nil || note == nil || note.Body != "Deploy with the release workflow" || note.Project != "/src/api" {
+		t.Errorf("GetNote() = %+v, %v", note, err)
+	}
+
+	if removed, err := db.RemoveNote(global); err != nil || !removed {
+		t.Errorf("RemoveNote() = %v, %v, want true", removed, err)
+	}
+	if matches, err := db.SearchNotes("freeze", 10); err != nil || len(matches) != 0 {
+		t.Errorf("SearchNotes() of a removed note = %+v, %v, want none", matches, err)
+	}
+	if note, err := db.GetNote(global); err != nil || note != nil {
+		t.Errorf("GetNote() of a removed note = %+v, %v, want nil", note, err)
+	}
+}
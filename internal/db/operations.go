@@ -0,0 +1,106 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Operation is a reversible change made by Aura, recorded in the operations
+// journal so 'aura undo' can roll it back.
+type Operation struct {
+	ID          int64
+	Kind        string
+	Description string
+	// Data holds what is needed to roll the operation back, as JSON.
+	Data      string
+	CreatedAt time.Time
+}
+
+// RecordOperation adds an operation to the journal.
+func (db *DB) RecordOperation(kind, description, data string) error {
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`INSERT INTO operations (kind, description, data) VALUES (%s, %s, %s);`,
+			sqlString(kind), sqlString(description), sqlString(data)))
+	}
+
+	query := `INSERT INTO operations (kind, description, data) VALUES (?, ?, ?)`
+	if _, err := db.conn.Exec(query, kind, description, data); err != nil {
+		return fmt.Errorf("failed to record operation: %w", err)
+	}
+	return nil
+}
+
+// ListOperations returns up to limit operations that were not undone, most
+// recent first.
+func (db *DB) ListOperations(limit int) ([]Operation, error) {
+	if db.isDockerMode {
+		// Descriptions and data may contain the column separator, so they
+		// are read hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT id, kind, hex(description), hex(data), created_at
+			FROM operations WHERE undone = 0 ORDER BY id DESC LIMIT %d;`, limit))
+		if err != nil {
+			return nil, err
+		}
+
+		var operations []Operation
+		for _, parts := range results {
+			if len(parts) < 5 {
+				continue
+			}
+			id, _ := strconv.ParseInt(parts[0], 10, 64)
+			description, _ := hex.DecodeString(parts[2])
+			data, _ := hex.DecodeString(parts[3])
+			createdAt, _ := time.Parse("2006-01-02 15:04:05", parts[4])
+			operations = append(operations, Operation{
+				ID:          id,
+				Kind:        parts[1],
+				Description: string(description),
+				Data:        string(data),
+				CreatedAt:   createdAt,
+			})
+		}
+		return operations, nil
+	}
+
+	rows, err := db.conn.Query(`SELECT id, kind, description, data, created_at
+		FROM operations WHERE undone = 0 ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operations: %w", err)
+	}
+	defer rows.Close()
+
+	var operations []Operation
+	for rows.Next() {
+		var o Operation
+		if err := rows.Scan(&o.ID, &o.Kind, &o.Description, &o.Data, &o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan operation: %w", err)
+		}
+		operations = append(operations, o)
+	}
+	return operations, nil
+}
+
+// LastOperation returns the most recent operation that was not undone, or nil
+// if there is none.
+func (db *DB) LastOperation() (*Operation, error) {
+	operations, err := db.ListOperations(1)
+	if err != nil || len(operations) == 0 {
+		return nil, err
+	}
+	return &operations[0], nil
+}
+
+// MarkOperationUndone removes an operation from the operations that can be
+// undone.
+func (db *DB) MarkOperationUndone(id int64) error {
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`UPDATE operations SET undone = 1 WHERE id = %d;`, id))
+	}
+
+	if _, err := db.conn.Exec(`UPDATE operations SET undone = 1 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to mark operation undone: %w", err)
+	}
+	return nil
+}
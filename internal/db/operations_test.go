@@ -0,0 +1,38 @@
+package db
+
+import "testing"
+
+func TestOperations(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.RecordOperation("bookmark.add", "Add bookmark 'a'", `{"alias":"a"}`); err != nil {
+		t.Fatalf("RecordOperation() error = %v", err)
+	}
+	if err := db.RecordOperation("file.create", "Create file 'x|y'", "{\"path\":\"/tmp/x|y\"}"); err != nil {
+		t.Fatalf("RecordOperation() error = %v", err)
+	}
+
+	last, err := db.LastOperation()
+	if err != nil || last == nil {
+		t.Fatalf("LastOperation() = %v, %v", last, err)
+	}
+	if last.Kind != "file.create" || last.Description != "Create file 'x|y'" || last.Data != "{\"path\":\"/tmp/x|y\"}" {
+		t.Errorf("Unexpected last operation %+v", last)
+	}
+
+	if err := db.MarkOperationUndone(last.ID); err != nil {
+		t.Fatalf("MarkOperationUndone() error = %v", err)
+	}
+	previous, err := db.LastOperation()
+	if err != nil || previous == nil {
+		t.Fatalf("LastOperation() = %v, %v", previous, err)
+	}
+	if previous.ID == last.ID || previous.Kind != "bookmark.add" {
+		t.Errorf("Expected the undone operation to be skipped, got %+v", previous)
+	}
+	db.MarkOperationUndone(previous.ID)
+}
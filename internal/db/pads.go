@@ -0,0 +1,123 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pad is a named scratch buffer of 'aura pad'.
+type Pad struct {
+	Name      string
+	Size      int64
+	UpdatedAt time.Time
+	// Content is only set by GetPad.
+	Content []byte
+}
+
+// SavePad stores content as the pad called name, replacing any with the same
+// name.
+func (db *DB) SavePad(name string, content []byte) error {
+	if db.isDockerMode {
+		// The content can be larger than a command line argument, so the
+		// statement is passed on standard input.
+		cmd := db.dockerSQLite()
+		cmd.Stdin = strings.NewReader(fmt.Sprintf(`INSERT OR REPLACE INTO pads (name, content, updated_at) VALUES (%s, X'%s', CURRENT_TIMESTAMP);`,
+			sqlString(name), hex.EncodeToString(content)))
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to save pad: %w", err)
+		}
+		return nil
+	}
+
+	if content == nil {
+		content = []byte{}
+	}
+	if _, err := db.conn.Exec(`INSERT OR REPLACE INTO pads (name, content, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)`, name, content); err != nil {
+		return fmt.Errorf("failed to save pad: %w", err)
+	}
+	return nil
+}
+
+// GetPad returns the pad called name with its content, or nil if there is
+// none.
+func (db *DB) GetPad(name string) (*Pad, error) {
+	query := fmt.Sprintf(`SELECT name, length(content), updated_at, hex(content) FROM pads WHERE name = %s`, sqlString(name))
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(query + ";")
+		if err != nil || len(results) == 0 || len(results[0]) < 4 {
+			return nil, err
+		}
+		size, _ := strconv.ParseInt(results[0][1], 10, 64)
+		updatedAt, _ := time.Parse("2006-01-02 15:04:05", results[0][2])
+		content, err := hex.DecodeString(results[0][3])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode pad: %w", err)
+		}
+		return &Pad{Name: results[0][0], Size: size, UpdatedAt: updatedAt, Content: content}, nil
+	}
+
+	var pad Pad
+	err := db.conn.QueryRow(`SELECT name, length(content), updated_at, content FROM pads WHERE name = ?`, name).
+		Scan(&pad.Name, &pad.Size, &pad.UpdatedAt, &pad.Content)
+	if err != nil {
+		if strings.Contains(err.Error(), "no rows") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pad: %w", err)
+	}
+	return &pad, nil
+}
+
+// ListPads returns the pads without their content, sorted by name.
+func (db *DB) ListPads() ([]Pad, error) {
+	query := `SELECT name, length(content), updated_at FROM pads ORDER BY name`
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(query + ";")
+		if err != nil {
+			return nil, err
+		}
+		var pads []Pad
+		for _, parts := range results {
+			if len(parts) < 3 {
+				continue
+			}
+			size, _ := strconv.ParseInt(parts[1], 10, 64)
+			updatedAt, _ := time.Parse("2006-01-02 15:04:05", parts[2])
+			pads = append(pads, Pad{Name: parts[0], Size: size, UpdatedAt: updatedAt})
+		}
+		return pads, nil
+	}
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pads: %w", err)
+	}
+	defer rows.Close()
+
+	var pads []Pad
+	for rows.Next() {
+		var pad Pad
+		if err := rows.Scan(&pad.Name, &pad.Size, &pad.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pad: %w", err)
+		}
+		pads = append(pads, pad)
+	}
+	return pads, nil
+}
+
+// RemovePad removes the pad called name and reports whether there was one.
+func (db *DB) RemovePad(name string) (bool, error) {
+	if db.isDockerMode {
+		changed, err := db.dockerChanges(fmt.Sprintf(`DELETE FROM pads WHERE name = %s`, sqlString(name)))
+		return changed > 0, err
+	}
+	result, err := db.conn.Exec(`DELETE FROM pads WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove pad: %w", err)
+	}
+	changed, err := result.RowsAffected()
+	return changed > 0, err
+}
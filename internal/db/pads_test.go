@@ -0,0 +1,51 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPads(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	content := []byte("build failed\x00\nline 2 | with a separator\n")
+	if err := db.SavePad("build-log", content); err != nil {
+		t.Fatalf("SavePad() error = %v", err)
+	}
+	if err := db.SavePad("empty", nil); err != nil {
+		t.Fatalf("SavePad() of nothing error = %v", err)
+	}
+
+	pad, err := db.GetPad("build-log")
+	if err != nil || pad == nil {
+		t.Fatalf("GetPad() = %+v, %v", pad, err)
+	}
+	if !bytes.Equal(pad.Content, content) || pad.Size != int64(len(content)) {
+		t.Errorf("GetPad() = %q (%d bytes), want %q", pad.Content, pad.Size, content)
+	}
+	if pad, err := db.GetPad("missing"); err != nil || pad != nil {
+		t.Errorf("GetPad() of a missing pad = %+v, %v, want nil", pad, err)
+	}
+
+	if err := db.SavePad("build-log", []byte("ok")); err != nil {
+		t.Fatalf("SavePad() error = %v", err)
+	}
+	pads, err := db.ListPads()
+	if err != nil {
+		t.Fatalf("ListPads() error = %v", err)
+	}
+	if len(pads) != 2 || pads[0].Name != "build-log" || pads[0].Size != 2 || pads[1].Name != "empty" || pads[1].Size != 0 {
+		t.Errorf("ListPads() = %+v", pads)
+	}
+
+	if removed, err := db.RemovePad("build-log"); err != nil || !removed {
+		t.Errorf("RemovePad() = %v, %v, want true", removed, err)
+	}
+	if removed, err := db.RemovePad("build-log"); err != nil || removed {
+		t.Errorf("RemovePad() of a removed pad = %v, %v, want false", removed, err)
+	}
+}
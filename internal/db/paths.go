@@ -0,0 +1,92 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+// pathsNormalizedKey is the meta key recording that the stored paths were
+// normalized.
+const pathsNormalizedKey = "paths_normalized"
+
+// normalizePaths rewrites the paths of bookmarks and navigation history
+// stored before paths were normalized, once. Visits of one directory through
+// different paths, such as a symbolic link, are merged.
+func (db *DB) normalizePaths() error {
+	const pathsQuery = `SELECT path FROM bookmarks UNION SELECT path FROM navigation_history`
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT value FROM meta WHERE key = %s;`, sqlString(pathsNormalizedKey)))
+		if err != nil || len(results) > 0 {
+			return err
+		}
+		results, err = db.queryDockerSQL(pathsQuery + ";")
+		if err != nil {
+			return err
+		}
+
+		statements := []string{"BEGIN;"}
+		for _, parts := range results {
+			if normalized := config.NormalizePath(parts[0]); normalized != parts[0] {
+				statements = append(statements,
+					fmt.Sprintf("UPDATE bookmarks SET path = %s WHERE path = %s;", sqlString(normalized), sqlString(parts[0])),
+					fmt.Sprintf("UPDATE navigation_history SET path = %s WHERE path = %s;", sqlString(normalized), sqlString(parts[0])))
+			}
+		}
+		statements = append(statements,
+			fmt.Sprintf("INSERT OR REPLACE INTO meta (key, value) VALUES (%s, '1');", sqlString(pathsNormalizedKey)),
+			"COMMIT;")
+
+		cmd := db.dockerSQLite()
+		cmd.Stdin = strings.NewReader(strings.Join(statements, "\n"))
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to normalize paths: %w", err)
+		}
+		return nil
+	}
+
+	var done int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM meta WHERE key = ?`, pathsNormalizedKey).Scan(&done); err != nil || done > 0 {
+		return err
+	}
+
+	rows, err := db.conn.Query(pathsQuery)
+	if err != nil {
+		return fmt.Errorf("failed to read paths: %w", err)
+	}
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan path: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	rows.Close()
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to normalize paths: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, path := range paths {
+		normalized := config.NormalizePath(path)
+		if normalized == path {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE bookmarks SET path = ? WHERE path = ?`, normalized, path); err != nil {
+			return fmt.Errorf("failed to normalize bookmarks: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE navigation_history SET path = ? WHERE path = ?`, normalized, path); err != nil {
+			return fmt.Errorf("failed to normalize history: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES (?, '1')`, pathsNormalizedKey); err != nil {
+		return fmt.Errorf("failed to record path normalization: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizePaths(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	project := filepath.Join(dir, "project")
+	link := filepath.Join(dir, "link")
+	os.Mkdir(project, 0755)
+	if err := os.Symlink(project, link); err != nil {
+		t.Skipf("Symlinks unsupported: %v", err)
+	}
+
+	// Paths stored before they were normalized
+	db.conn.Exec(`INSERT INTO bookmarks (alias, path) VALUES ('paths-link', ?)`, link+"/")
+	db.conn.Exec(`INSERT INTO navigation_history (path) VALUES (?), (?)`, project, link)
+	db.conn.Exec(`DELETE FROM meta WHERE key = ?`, pathsNormalizedKey)
+
+	if err := db.normalizePaths(); err != nil {
+		t.Fatalf("normalizePaths() error = %v", err)
+	}
+
+	bookmark, err := db.GetBookmark("paths-link")
+	if err != nil || bookmark == nil || bookmark.Path != project {
+		t.Errorf("GetBookmark() = %+v, %v, want path %s", bookmark, err, project)
+	}
+	var visits int
+	db.conn.QueryRow(`SELECT COUNT(*) FROM navigation_history WHERE path = ?`, project).Scan(&visits)
+	if visits != 2 {
+		t.Errorf("Expected both visits merged into %s, got %d", project, visits)
+	}
+
+	// New paths are stored normalized
+	if err := db.AddNavigationHistory(link); err != nil {
+		t.Fatalf("AddNavigationHistory() error = %v", err)
+	}
+	db.conn.QueryRow(`SELECT COUNT(*) FROM navigation_history WHERE path = ?`, project).Scan(&visits)
+	if visits != 3 {
+		t.Errorf("Expected the visit through the link to be stored as %s, got %d visits", project, visits)
+	}
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Reminder is a message 'aura remind' or 'aura pomodoro' delivers at a set
+// time.
+type Reminder struct {
+	ID int64
+	// Kind is "remind" or "pomodoro".
+	Kind    string
+	Message string
+	Due     time.Time
+}
+
+// AddReminder schedules a reminder and returns its ID. Due times are stored
+// as Unix seconds, so they compare correctly in SQL.
+func (db *DB) AddReminder(kind, message string, due time.Time) (int64, error) {
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(`INSERT INTO reminders (kind, message, due_at) VALUES (%s, %s, %d); SELECT last_insert_rowid();`,
+			sqlString(kind), sqlString(message), due.Unix()))
+		if err != nil {
+			return 0, err
+		}
+		if len(results) == 0 {
+			return 0, fmt.Errorf("failed to add reminder: no ID returned")
+		}
+		return strconv.ParseInt(results[0][0], 10, 64)
+	}
+
+	result, err := db.conn.Exec(`INSERT INTO reminders (kind, message, due_at) VALUES (?, ?, ?)`, kind, message, due.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to add reminder: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// PendingReminders returns the reminders not delivered yet, the earliest
+// first.
+func (db *DB) PendingReminders() ([]Reminder, error) {
+	if db.isDockerMode {
+		// Messages may contain the column separator, so they are read hex
+		// encoded.
+		results, err := db.queryDockerSQL(`SELECT id, kind, hex(message), due_at FROM reminders WHERE delivered = 0 ORDER BY due_at, id;`)
+		if err != nil {
+			return nil, err
+		}
+
+		var reminders []Reminder
+		for _, parts := range results {
+			if len(parts) < 4 {
+				continue
+			}
+			id, _ := strconv.ParseInt(parts[0], 10, 64)
+			message, _ := hex.DecodeString(parts[2])
+			due, _ := strconv.ParseInt(parts[3], 10, 64)
+			reminders = append(reminders, Reminder{ID: id, Kind: parts[1], Message: string(message), Due: time.Unix(due, 0)})
+		}
+		return reminders, nil
+	}
+
+	rows, err := db.conn.Query(`SELECT id, kind, message, due_at FROM reminders WHERE delivered = 0 ORDER BY due_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		var r Reminder
+		var due int64
+		if err := rows.Scan(&r.ID, &r.Kind, &r.Message, &due); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		r.Due = time.Unix(due, 0)
+		reminders = append(reminders, r)
+	}
+	return reminders, nil
+}
+
+// ClaimReminder marks a pending reminder delivered and reports whether this
+// call did, so a reminder cancelled or delivered meanwhile isn't delivered
+// again.
+func (db *DB) ClaimReminder(id int64) (bool, error) {
+	return db.changeReminders(fmt.Sprintf(`UPDATE reminders SET delivered = 1 WHERE id = %d AND delivered = 0`, id))
+}
+
+// CancelReminder removes a pending reminder and reports whether there was
+// one.
+func (db *DB) CancelReminder(id int64) (bool, error) {
+	return db.changeReminders(fmt.Sprintf(`DELETE FROM reminders WHERE id = %d AND delivered = 0`, id))
+}
+
+// CancelReminders removes the pending reminders of kind and returns how many
+// there were.
+func (db *DB) CancelReminders(kind string) (int64, error) {
+	statement := fmt.Sprintf(`DELETE FROM reminders WHERE kind = %s AND delivered = 0`, sqlString(kind))
+	if db.isDockerMode {
+		return db.dockerChanges(statement)
+	}
+	result, err := db.conn.Exec(statement)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel reminders: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// changeReminders runs statement and reports whether it changed a reminder.
+func (db *DB) changeReminders(statement string) (bool, error) {
+	if db.isDockerMode {
+		changed, err := db.dockerChanges(statement)
+		return changed > 0, err
+	}
+	result, err := db.conn.Exec(statement)
+	if err != nil {
+		return false, fmt.Errorf("failed to update reminder: %w", err)
+	}
+	changed, err := result.RowsAffected()
+	return changed > 0, err
+}
+
+// dockerChanges runs statement in the container and returns how many rows
+// it changed.
+func (db *DB) dockerChanges(statement string) (int64, error) {
+	results, err := db.queryDockerSQL(statement + `; SELECT changes();`)
+	if err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(results[0][0], 10, 64)
+}
+
+// reminderLeaseKey is the meta key of the lease of the process delivering
+// reminders, "<holder>|<expiry in Unix seconds>".
+const reminderLeaseKey = "reminders_deliverer"
+
+// LeaseReminders makes holder the process delivering reminders until the
+// given time and reports whether it is. The lease is only taken from another
+// holder once it expired, so a single process delivers them.
+func (db *DB) LeaseReminders(holder string, until, now time.Time) (bool, error) {
+	value := fmt.Sprintf("%s|%d", holder, until.Unix())
+	statement := fmt.Sprintf(`INSERT INTO meta (key, value) VALUES (%s, %s)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+		WHERE meta.value LIKE %s OR CAST(substr(meta.value, instr(meta.value, '|') + 1) AS INTEGER) < %d`,
+		sqlString(reminderLeaseKey), sqlString(value), sqlString(holder+"|%"), now.Unix())
+	return db.changeReminders(statement)
+}
+
+// ReleaseReminders gives up the lease of holder.
+func (db *DB) ReleaseReminders(holder string) error {
+	statement := fmt.Sprintf(`DELETE FROM meta WHERE key = %s AND value LIKE %s`, sqlString(reminderLeaseKey), sqlString(holder+"|%"))
+	_, err := db.changeReminders(statement)
+	return err
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminders(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	later, err := db.AddReminder("remind", "standup | daily", now.Add(25*time.Minute))
+	if err != nil {
+		t.Fatalf("AddReminder() error = %v", err)
+	}
+	sooner, err := db.AddReminder("pomodoro", "Take a break", now.Add(5*time.Minute))
+	if err != nil {
+		t.Fatalf("AddReminder() error = %v", err)
+	}
+
+	pending, err := db.PendingReminders()
+	if err != nil {
+		t.Fatalf("PendingReminders() error = %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != sooner || pending[1].ID != later {
+		t.Fatalf("PendingReminders() = %+v, want the pomodoro first", pending)
+	}
+	if pending[1].Message != "standup | daily" || !pending[1].Due.Equal(now.Add(25*time.Minute)) || pending[1].Kind != "remind" {
+		t.Errorf("PendingReminders()[1] = %+v", pending[1])
+	}
+
+	if claimed, err := db.ClaimReminder(sooner); err != nil || !claimed {
+		t.Errorf("ClaimReminder() = %v, %v, want true", claimed, err)
+	}
+	if claimed, err := db.ClaimReminder(sooner); err != nil || claimed {
+		t.Errorf("ClaimReminder() of a delivered reminder = %v, %v, want false", claimed, err)
+	}
+	if cancelled, err := db.CancelReminder(sooner); err != nil || cancelled {
+		t.Errorf("CancelReminder() of a delivered reminder = %v, %v, want false", cancelled, err)
+	}
+
+	if _, err := db.AddReminder("pomodoro", "Back to work", now.Add(10*time.Minute)); err != nil {
+		t.Fatalf("AddReminder() error = %v", err)
+	}
+	if count, err := db.CancelReminders("pomodoro"); err != nil || count != 1 {
+		t.Errorf("CancelReminders() = %d, %v, want 1", count, err)
+	}
+	if cancelled, err := db.CancelReminder(later); err != nil || !cancelled {
+		t.Errorf("CancelReminder() = %v, %v, want true", cancelled, err)
+	}
+	if pending, err := db.PendingReminders(); err != nil || len(pending) != 0 {
+		t.Errorf("PendingReminders() after cancelling = %+v, %v", pending, err)
+	}
+}
+
+func TestLeaseReminders(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	if leased, err := db.LeaseReminders("100", now.Add(time.Minute), now); err != nil || !leased {
+		t.Fatalf("LeaseReminders() = %v, %v, want true", leased, err)
+	}
+	if leased, err := db.LeaseReminders("200", now.Add(time.Minute), now); err != nil || leased {
+		t.Errorf("LeaseReminders() of a held lease = %v, %v, want false", leased, err)
+	}
+	if leased, err := db.LeaseReminders("100", now.Add(2*time.Minute), now); err != nil || !leased {
+		t.Errorf("LeaseReminders() renewing = %v, %v, want true", leased, err)
+	}
+	later := now.Add(3 * time.Minute)
+	if leased, err := db.LeaseReminders("200", later.Add(time.Minute), later); err != nil || !leased {
+		t.Errorf("LeaseReminders() of an expired lease = %v, %v, want true", leased, err)
+	}
+
+	if err := db.ReleaseReminders("100"); err != nil {
+		t.Fatalf("ReleaseReminders() error = %v", err)
+	}
+	if leased, err := db.LeaseReminders("100", later.Add(time.Minute), later); err != nil || leased {
+		t.Errorf("LeaseReminders() after releasing another holder = %v, %v, want false", leased, err)
+	}
+	if err := db.ReleaseReminders("200"); err != nil {
+		t.Fatalf("ReleaseReminders() error = %v", err)
+	}
+	if leased, err := db.LeaseReminders("100", later.Add(time.Minute), later); err != nil || !leased {
+		t.Errorf("LeaseReminders() after release = %v, %v, want true", leased, err)
+	}
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// SavedRequest is an HTTP request saved by 'aura http --save' under a name.
+// Its URL and items keep their {{VAR}} placeholders.
+type SavedRequest struct {
+	Name   string
+	Method string
+	URL    string
+	Items  []string
+}
+
+// SaveRequest saves a request, replacing any with the same name.
+func (db *DB) SaveRequest(request SavedRequest) error {
+	items, err := json.Marshal(request.Items)
+	if err != nil {
+		return fmt.Errorf("failed to encode request items: %w", err)
+	}
+	if request.Items == nil {
+		items = []byte("[]")
+	}
+
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`INSERT OR REPLACE INTO http_requests (name, method, url, items, updated_at) VALUES (%s, %s, %s, %s, CURRENT_TIMESTAMP);`,
+			sqlString(request.Name), sqlString(request.Method), sqlString(request.URL), sqlString(string(items))))
+	}
+
+	query := `INSERT OR REPLACE INTO http_requests (name, method, url, items, updated_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	if _, err := db.conn.Exec(query, request.Name, request.Method, request.URL, string(items)); err != nil {
+		return fmt.Errorf("failed to save request: %w", err)
+	}
+	return nil
+}
+
+// GetRequest returns the saved request called name, or nil if there is
+// none.
+func (db *DB) GetRequest(name string) (*SavedRequest, error) {
+	requests, err := db.listRequests(fmt.Sprintf(`WHERE name = %s`, sqlString(name)))
+	if err != nil || len(requests) == 0 {
+		return nil, err
+	}
+	return &requests[0], nil
+}
+
+// ListRequests returns the saved requests sorted by name.
+func (db *DB) ListRequests() ([]SavedRequest, error) {
+	return db.listRequests("")
+}
+
+// listRequests returns the saved requests matching where, a WHERE clause
+// with its values quoted.
+func (db *DB) listRequests(where string) ([]SavedRequest, error) {
+	var rows [][]string
+	if db.isDockerMode {
+		// URLs and items may contain the column separator, so they are read
+		// hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT name, method, hex(url), hex(items) FROM http_requests %s ORDER BY name;`, where))
+		if err != nil {
+			return nil, err
+		}
+		for _, parts := range results {
+			if len(parts) < 4 {
+				continue
+			}
+			url, _ := hex.DecodeString(parts[2])
+			items, _ := hex.DecodeString(parts[3])
+			rows = append(rows, []string{parts[0], parts[1], string(url), string(items)})
+		}
+	} else {
+		result, err := db.conn.Query(fmt.Sprintf(`SELECT name, method, url, items FROM http_requests %s ORDER BY name`, where))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list requests: %w", err)
+		}
+		defer result.Close()
+		for result.Next() {
+			row := make([]string, 4)
+			if err := result.Scan(&row[0], &row[1], &row[2], &row[3]); err != nil {
+				return nil, fmt.Errorf("failed to scan request: %w", err)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var requests []SavedRequest
+	for _, row := range rows {
+		request := SavedRequest{Name: row[0], Method: row[1], URL: row[2]}
+		if err := json.Unmarshal([]byte(row[3]), &request.Items); err != nil {
+			return nil, fmt.Errorf("failed to decode the items of request %s: %w", request.Name, err)
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// RemoveRequest removes the saved request called name and reports whether
+// there was one.
+func (db *DB) RemoveRequest(name string) (bool, error) {
+	existing, err := db.GetRequest(name)
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	if db.isDockerMode {
+		return true, db.execDockerSQL(fmt.Sprintf(`DELETE FROM http_requests WHERE name = %s;`, sqlString(name)))
+	}
+	if _, err := db.conn.Exec(`DELETE FROM http_requests WHERE name = ?`, name); err != nil {
+		return false, fmt.Errorf("failed to remove request: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSavedRequests(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	users := SavedRequest{Name: "users", Method: "GET", URL: "{{API}}/users", Items: []string{"Authorization:Bearer {{TOKEN}}", "page==2"}}
+	if err := db.SaveRequest(users); err != nil {
+		t.Fatalf("SaveRequest() error = %v", err)
+	}
+	if err := db.SaveRequest(SavedRequest{Name: "health", Method: "HEAD", URL: ":8080/health"}); err != nil {
+		t.Fatalf("SaveRequest() error = %v", err)
+	}
+
+	got, err := db.GetRequest("users")
+	if err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if got == nil || !reflect.DeepEqual(*got, users) {
+		t.Errorf("GetRequest() = %+v, want %+v", got, users)
+	}
+
+	requests, err := db.ListRequests()
+	if err != nil {
+		t.Fatalf("ListRequests() error = %v", err)
+	}
+	if len(requests) != 2 || requests[0].Name != "health" || len(requests[0].Items) != 0 {
+		t.Errorf("ListRequests() = %+v", requests)
+	}
+
+	removed, err := db.RemoveRequest("users")
+	if err != nil || !removed {
+		t.Fatalf("RemoveRequest() = %v, %v, want true", removed, err)
+	}
+	if removed, _ := db.RemoveRequest("users"); removed {
+		t.Error("RemoveRequest() of a removed request = true")
+	}
+	if got, _ := db.GetRequest("users"); got != nil {
+		t.Errorf("GetRequest() after removal = %+v", got)
+	}
+}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Schedule is a command 'aura schedule' runs at the times of a cron
+// expression.
+type Schedule struct {
+	Name    string
+	Cron    string
+	Command string
+	// Targets are the bookmarks the command runs in, "*" standing for all
+	// bookmarked Git repositories. Without targets it runs in the home
+	// directory.
+	Targets   []string
+	CreatedAt time.Time
+}
+
+// ScheduleRun is the outcome of running a schedule in a directory.
+type ScheduleRun struct {
+	ID        int64
+	Name      string
+	Dir       string
+	StartedAt time.Time
+	Duration  time.Duration
+	ExitCode  int
+	// Output is the end of what the command printed.
+	Output string
+}
+
+// scheduleRunsKept is how many runs are kept per schedule; older ones are
+// removed as new ones are recorded.
+const scheduleRunsKept = 100
+
+// SaveSchedule saves a schedule, replacing any with the same name.
+func (db *DB) SaveSchedule(schedule Schedule) error {
+	targets, err := json.Marshal(schedule.Targets)
+	if err != nil {
+		return fmt.Errorf("failed to encode schedule targets: %w", err)
+	}
+	if schedule.Targets == nil {
+		targets = []byte("[]")
+	}
+
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`INSERT OR REPLACE INTO schedules (name, cron, command, targets) VALUES (%s, %s, %s, %s);`,
+			sqlString(schedule.Name), sqlString(schedule.Cron), sqlString(schedule.Command), sqlString(string(targets))))
+	}
+
+	query := `INSERT OR REPLACE INTO schedules (name, cron, command, targets) VALUES (?, ?, ?, ?)`
+	if _, err := db.conn.Exec(query, schedule.Name, schedule.Cron, schedule.Command, string(targets)); err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+	return nil
+}
+
+// GetSchedule returns the schedule called name, or nil if there is none.
+func (db *DB) GetSchedule(name string) (*Schedule, error) {
+	schedules, err := db.listSchedules(fmt.Sprintf(`WHERE name = %s`, sqlString(name)))
+	if err != nil || len(schedules) == 0 {
+		return nil, err
+	}
+	return &schedules[0], nil
+}
+
+// ListSchedules returns the schedules sorted by name.
+func (db *DB) ListSchedules() ([]Schedule, error) {
+	return db.listSchedules("")
+}
+
+// listSchedules returns the schedules matching where, a WHERE clause with
+// its values quoted.
+func (db *DB) listSchedules(where string) ([]Schedule, error) {
+	var rows [][]string
+	if db.isDockerMode {
+		// Commands and targets may contain the column separator, so they
+		// are read hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT name, cron, hex(command), hex(targets), created_at FROM schedules %s ORDER BY name;`, where))
+		if err != nil {
+			return nil, err
+		}
+		for _, parts := range results {
+			if len(parts) < 5 {
+				continue
+			}
+			command, _ := hex.DecodeString(parts[2])
+			targets, _ := hex.DecodeString(parts[3])
+			rows = append(rows, []string{parts[0], parts[1], string(command), string(targets), parts[4]})
+		}
+	} else {
+		result, err := db.conn.Query(fmt.Sprintf(`SELECT name, cron, command, targets, strftime('%%Y-%%m-%%d %%H:%%M:%%S', created_at) FROM schedules %s ORDER BY name`, where))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list schedules: %w", err)
+		}
+		defer result.Close()
+		for result.Next() {
+			row := make([]string, 5)
+			if err := result.Scan(&row[0], &row[1], &row[2], &row[3], &row[4]); err != nil {
+				return nil, fmt.Errorf("failed to scan schedule: %w", err)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var schedules []Schedule
+	for _, row := range rows {
+		schedule := Schedule{Name: row[0], Cron: row[1], Command: row[2]}
+		if err := json.Unmarshal([]byte(row[3]), &schedule.Targets); err != nil {
+			return nil, fmt.Errorf("failed to decode the targets of schedule %s: %w", schedule.Name, err)
+		}
+		schedule.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", row[4])
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// RemoveSchedule removes the schedule called name with its runs and reports
+// whether there was one.
+func (db *DB) RemoveSchedule(name string) (bool, error) {
+	existing, err := db.GetSchedule(name)
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	if db.isDockerMode {
+		return true, db.execDockerSQL(fmt.Sprintf(`DELETE FROM schedules WHERE name = %[1]s; DELETE FROM schedule_runs WHERE name = %[1]s;`, sqlString(name)))
+	}
+	if _, err := db.conn.Exec(`DELETE FROM schedules WHERE name = ?`, name); err != nil {
+		return false, fmt.Errorf("failed to remove schedule: %w", err)
+	}
+	if _, err := db.conn.Exec(`DELETE FROM schedule_runs WHERE name = ?`, name); err != nil {
+		return false, fmt.Errorf("failed to remove schedule runs: %w", err)
+	}
+	return true, nil
+}
+
+// AddScheduleRun records a run of a schedule, dropping its oldest runs
+// beyond the last scheduleRunsKept.
+func (db *DB) AddScheduleRun(run ScheduleRun) error {
+	prune := `DELETE FROM schedule_runs WHERE name = %[1]s AND id NOT IN (SELECT id FROM schedule_runs WHERE name = %[1]s ORDER BY id DESC LIMIT %[2]d)`
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`INSERT INTO schedule_runs (name, dir, started_at, duration_ms, exit_code, output) VALUES (%s, %s, %d, %d, %d, %s); `+prune+`;`,
+			sqlString(run.Name), sqlString(run.Dir), run.StartedAt.Unix(), run.Duration.Milliseconds(), run.ExitCode, sqlString(run.Output), scheduleRunsKept))
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to record schedule run: %w", err)
+	}
+	defer tx.Rollback()
+	query := `INSERT INTO schedule_runs (name, dir, started_at, duration_ms, exit_code, output) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := tx.Exec(query, run.Name, run.Dir, run.StartedAt.Unix(), run.Duration.Milliseconds(), run.ExitCode, run.Output); err != nil {
+		return fmt.Errorf("failed to record schedule run: %w", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf(prune, sqlString(run.Name), scheduleRunsKept)); err != nil {
+		return fmt.Errorf("failed to prune schedule runs: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record schedule run: %w", err)
+	}
+	return nil
+}
+
+// ScheduleRuns returns up to limit runs of the schedule called name, or of
+// all schedules if name is empty, most recent first.
+func (db *DB) ScheduleRuns(name string, limit int) ([]ScheduleRun, error) {
+	where := ""
+	if name != "" {
+		where = "WHERE name = " + sqlString(name)
+	}
+
+	var rows [][]string
+	if db.isDockerMode {
+		// Directories and output may contain the column separator, so they
+		// are read hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT id, name, hex(dir), started_at, duration_ms, exit_code, hex(output) FROM schedule_runs %s ORDER BY id DESC LIMIT %d;`, where, limit))
+		if err != nil {
+			return nil, err
+		}
+		for _, parts := range results {
+			if len(parts) < 7 {
+				continue
+			}
+			dir, _ := hex.DecodeString(parts[2])
+			output, _ := hex.DecodeString(parts[6])
+			rows = append(rows, []string{parts[0], parts[1], string(dir), parts[3], parts[4], parts[5], string(output)})
+		}
+	} else {
+		result, err := db.conn.Query(fmt.Sprintf(`SELECT id, name, dir, started_at, duration_ms, exit_code, output FROM schedule_runs %s ORDER BY id DESC LIMIT %d`, where, limit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list schedule runs: %w", err)
+		}
+		defer result.Close()
+		for result.Next() {
+			row := make([]string, 7)
+			if err := result.Scan(&row[0], &row[1], &row[2], &row[3], &row[4], &row[5], &row[6]); err != nil {
+				return nil, fmt.Errorf("failed to scan schedule run: %w", err)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var runs []ScheduleRun
+	for _, row := range rows {
+		id, _ := strconv.ParseInt(row[0], 10, 64)
+		started, _ := strconv.ParseInt(row[3], 10, 64)
+		duration, _ := strconv.ParseInt(row[4], 10, 64)
+		exitCode, _ := strconv.Atoi(row[5])
+		runs = append(runs, ScheduleRun{
+			ID:        id,
+			Name:      row[1],
+			Dir:       row[2],
+			StartedAt: time.Unix(started, 0),
+			Duration:  time.Duration(duration) * time.Millisecond,
+			ExitCode:  exitCode,
+			Output:    row[6],
+		})
+	}
+	return runs, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedules(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	fetch := Schedule{Name: "fetch", Cron: "0 2 * * *", Command: "git fetch --all --prune", Targets: []string{"*"}}
+	if err := db.SaveSchedule(fetch); err != nil {
+		t.Fatalf("SaveSchedule() error = %v", err)
+	}
+	if err := db.SaveSchedule(Schedule{Name: "backup", Cron: "@daily", Command: "aura pack ~/notes"}); err != nil {
+		t.Fatalf("SaveSchedule() error = %v", err)
+	}
+
+	got, err := db.GetSchedule("fetch")
+	if err != nil || got == nil {
+		t.Fatalf("GetSchedule() = %v, %v", got, err)
+	}
+	if got.Cron != fetch.Cron || got.Command != fetch.Command || len(got.Targets) != 1 || got.Targets[0] != "*" {
+		t.Errorf("GetSchedule() = %+v, want %+v", got, fetch)
+	}
+	schedules, err := db.ListSchedules()
+	if err != nil || len(schedules) != 2 || schedules[0].Name != "backup" || len(schedules[0].Targets) != 0 {
+		t.Errorf("ListSchedules() = %+v, %v", schedules, err)
+	}
+
+	start := time.Unix(time.Now().Unix(), 0)
+	for i := 0; i < scheduleRunsKept+2; i++ {
+		run := ScheduleRun{Name: "fetch", Dir: "/test/schedules/api", StartedAt: start, Duration: 1500 * time.Millisecond, ExitCode: i % 2, Output: "From github.com:a/b\n | x"}
+		if err := db.AddScheduleRun(run); err != nil {
+			t.Fatalf("AddScheduleRun() error = %v", err)
+		}
+	}
+	if err := db.AddScheduleRun(ScheduleRun{Name: "backup", Dir: "/test/schedules", StartedAt: start}); err != nil {
+		t.Fatalf("AddScheduleRun() error = %v", err)
+	}
+
+	runs, err := db.ScheduleRuns("fetch", 1000)
+	if err != nil || len(runs) != scheduleRunsKept {
+		t.Fatalf("ScheduleRuns() returned %d runs, %v, want %d", len(runs), err, scheduleRunsKept)
+	}
+	last := runs[0]
+	if !last.StartedAt.Equal(start) || last.Duration != 1500*time.Millisecond || last.ExitCode != 1 || last.Output != "From github.com:a/b\n | x" {
+		t.Errorf("ScheduleRuns()[0] = %+v", last)
+	}
+	if runs, _ := db.ScheduleRuns("", 2); len(runs) != 2 || runs[0].Name != "backup" {
+		t.Errorf("ScheduleRuns(\"\", 2) = %+v, want the backup run first", runs)
+	}
+
+	if removed, err := db.RemoveSchedule("fetch"); err != nil || !removed {
+		t.Fatalf("RemoveSchedule() = %v, %v, want true", removed, err)
+	}
+	if runs, _ := db.ScheduleRuns("fetch", 10); len(runs) != 0 {
+		t.Errorf("ScheduleRuns() after RemoveSchedule() = %d runs, want none", len(runs))
+	}
+	if removed, _ := db.RemoveSchedule("fetch"); removed {
+		t.Error("RemoveSchedule() of a removed schedule = true")
+	}
+}
@@ -0,0 +1,216 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// searchSource is a table indexed for full-text search. Its columns are
+// SQL expressions over a row of the table.
+type searchSource struct {
+	kind  string
+	table string
+	// key identifies a row, its text is the ref of the indexed entry.
+	key string
+	// label gives context shown with results but not searched, such as the
+	// project of a note.
+	label string
+	title string
+	body  string
+	// dependents are other tables whose rows change an entry, with the
+	// column holding its key, such as the metadata of a bookmark.
+	dependents map[string]string
+}
+
+// searchSources are the tables 'aura search' searches.
+var searchSources = []searchSource{
+	{kind: "note", table: "notes", key: "id", label: "project", title: "''", body: "body"},
+	{kind: "chat", table: "conversations", key: "id", label: "substr(created_at, 1, 10)", title: "question", body: "answer"},
+	{kind: "alias", table: "shell_aliases", key: "name", label: "''", title: "name", body: "command"},
+	{kind: "request", table: "http_requests", key: "name", label: "method", title: "name", body: "method || ' ' || url || ' ' || items"},
+	{
+		kind: "bookmark", table: "bookmarks", key: "alias", label: "path", title: "alias",
+		body:       "path || COALESCE((SELECT ' ' || group_concat(key || ' ' || value, ' ') FROM bookmark_metadata WHERE bookmark_metadata.alias = bookmarks.alias), '')",
+		dependents: map[string]string{"bookmark_metadata": "alias"},
+	},
+}
+
+// SearchKinds are the kinds of entries Search finds.
+var SearchKinds = []string{"note", "chat", "alias", "request", "bookmark"}
+
+// searchIndexVersion is the version of the search index, stored in the meta
+// table. Changing searchSources requires a new version, which recreates the
+// triggers and rebuilds the index on the next start.
+const searchIndexVersion = "1"
+
+// searchIndexKey is the meta key of the version of the search index.
+const searchIndexKey = "search_index_version"
+
+// createSearchIndex is the full-text index of the search sources. The kind,
+// ref and label are stored with the entries, but not searched.
+const createSearchIndex = `CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(kind UNINDEXED, ref UNINDEXED, label UNINDEXED, title, body);`
+
+// reindex returns the statements replacing the entry of source with the key
+// ref, an SQL expression. If the row is gone the entry is only removed.
+func (s searchSource) reindex(ref string) string {
+	return fmt.Sprintf(`DELETE FROM search_index WHERE kind = '%s' AND ref = CAST(%s AS TEXT);
+		INSERT INTO search_index (kind, ref, label, title, body)
+		SELECT '%s', CAST(%s AS TEXT), %s, %s, %s FROM %s WHERE %s = %s;`,
+		s.kind, ref, s.kind, s.key, s.label, s.title, s.body, s.table, s.key, ref)
+}
+
+// triggers returns the statements creating the triggers keeping the entries
+// of source current.
+func (s searchSource) triggers() []string {
+	tables := map[string]string{s.table: s.key}
+	for table, column := range s.dependents {
+		tables[table] = column
+	}
+
+	var statements []string
+	for table, column := range tables {
+		for _, event := range []string{"insert", "update", "delete"} {
+			name := fmt.Sprintf("search_%s_%s_%s", s.kind, table, event)
+			body := ""
+			switch event {
+			case "insert":
+				body = s.reindex("new." + column)
+			case "update":
+				body = s.reindex("old."+column) + "\n" + s.reindex("new."+column)
+			case "delete":
+				body = s.reindex("old." + column)
+			}
+			statements = append(statements,
+				fmt.Sprintf("DROP TRIGGER IF EXISTS %s;", name),
+				fmt.Sprintf("CREATE TRIGGER %s AFTER %s ON %s BEGIN\n%s\nEND;", name, strings.ToUpper(event), table, body))
+		}
+	}
+	return statements
+}
+
+// prepareSearchIndex creates the search index and its triggers and indexes
+// the existing rows, unless the index is current.
+func (db *DB) prepareSearchIndex() error {
+	if err := db.execSQL(createSearchIndex); err != nil {
+		return fmt.Errorf("failed to create search index: %w", err)
+	}
+
+	versionQuery := fmt.Sprintf(`SELECT COALESCE((SELECT value FROM meta WHERE key = %s), '')`, sqlString(searchIndexKey))
+	var version string
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(versionQuery + ";")
+		if err != nil {
+			return err
+		}
+		if len(results) > 0 {
+			version = results[0][0]
+		}
+	} else if err := db.conn.QueryRow(versionQuery).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read the search index version: %w", err)
+	}
+	if version == searchIndexVersion {
+		return nil
+	}
+
+	statements := []string{`DELETE FROM search_index;`}
+	for _, source := range searchSources {
+		statements = append(statements, source.triggers()...)
+		statements = append(statements, fmt.Sprintf(`INSERT INTO search_index (kind, ref, label, title, body)
+			SELECT '%s', CAST(%s AS TEXT), %s, %s, %s FROM %s;`, source.kind, source.key, source.label, source.title, source.body, source.table))
+	}
+	statements = append(statements, fmt.Sprintf(`INSERT OR REPLACE INTO meta (key, value) VALUES (%s, %s);`, sqlString(searchIndexKey), sqlString(searchIndexVersion)))
+
+	if db.isDockerMode {
+		cmd := db.dockerSQLite()
+		cmd.Stdin = strings.NewReader("BEGIN;\n" + strings.Join(statements, "\n") + "\nCOMMIT;")
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to build search index: %w", err)
+	}
+	defer tx.Rollback()
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SearchResult is an entry found by Search.
+type SearchResult struct {
+	// Kind is one of SearchKinds.
+	Kind string
+	// Ref identifies the entry within its kind, such as the ID of a note or
+	// the alias of a bookmark.
+	Ref   string
+	Label string
+	Title string
+	// Snippet is the matching part of the entry with the matches between
+	// SnippetStart and SnippetEnd.
+	Snippet string
+}
+
+// Search returns up to limit entries of kinds, or of all kinds without any,
+// matching all words of query as prefixes, the best matches first. Matches
+// in titles count twice.
+func (db *DB) Search(query string, limit int, kinds ...string) ([]SearchResult, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	where := fmt.Sprintf("search_index MATCH %s", sqlString(match))
+	if len(kinds) > 0 {
+		quoted := make([]string, len(kinds))
+		for i, kind := range kinds {
+			quoted[i] = sqlString(kind)
+		}
+		where += fmt.Sprintf(" AND kind IN (%s)", strings.Join(quoted, ", "))
+	}
+	snippet := fmt.Sprintf(`snippet(search_index, -1, '%s', '%s', '…', 12)`, SnippetStart, SnippetEnd)
+	rest := fmt.Sprintf(`FROM search_index WHERE %s ORDER BY bm25(search_index, 0, 0, 0, 2, 1) LIMIT %d`, where, limit)
+
+	if db.isDockerMode {
+		// Every column but the kind may contain the column separator, so
+		// they are read hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT kind, hex(ref), hex(label), hex(title), hex(%s) %s;`, snippet, rest))
+		if err != nil {
+			return nil, err
+		}
+		var found []SearchResult
+		for _, parts := range results {
+			if len(parts) < 5 {
+				continue
+			}
+			decoded := make([]string, 4)
+			for i, part := range parts[1:5] {
+				value, _ := hex.DecodeString(part)
+				decoded[i] = string(value)
+			}
+			found = append(found, SearchResult{Kind: parts[0], Ref: decoded[0], Label: decoded[1], Title: decoded[2], Snippet: decoded[3]})
+		}
+		return found, nil
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(`SELECT kind, ref, label, title, %s %s`, snippet, rest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	var found []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.Kind, &r.Ref, &r.Label, &r.Title, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		found = append(found, r)
+	}
+	return found, nil
+}
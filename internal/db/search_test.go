@@ -0,0 +1,87 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.AddNote("/src/zephyr", "Rotate the zephyr signing key yearly"); err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+	if err := db.SaveConversation("How do I rotate a zephyr key?", "Run zephyr rotate", "test"); err != nil {
+		t.Fatalf("SaveConversation() error = %v", err)
+	}
+	if err := db.SetShellAlias("zr", "zephyr rotate --all"); err != nil {
+		t.Fatalf("SetShellAlias() error = %v", err)
+	}
+	if err := db.SaveRequest(SavedRequest{Name: "zephyr-keys", Method: "GET", URL: "https://zephyr.test/keys"}); err != nil {
+		t.Fatalf("SaveRequest() error = %v", err)
+	}
+	if err := db.AddBookmark("zephyr", "/src/zephyr"); err != nil {
+		t.Fatalf("AddBookmark() error = %v", err)
+	}
+
+	results, err := db.Search("zephyr", 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	kinds := map[string]SearchResult{}
+	for _, result := range results {
+		kinds[result.Kind] = result
+	}
+	for _, kind := range SearchKinds {
+		if _, ok := kinds[kind]; !ok {
+			t.Errorf("Search() found no %s in %+v", kind, results)
+		}
+	}
+	if bookmark := kinds["bookmark"]; bookmark.Ref != "zephyr" || bookmark.Label != "/src/zephyr" {
+		t.Errorf("Search() bookmark = %+v", bookmark)
+	}
+	if !strings.Contains(kinds["alias"].Snippet, SnippetStart+"zephyr"+SnippetEnd) {
+		t.Errorf("Search() alias snippet = %q, want the match marked", kinds["alias"].Snippet)
+	}
+
+	if results, err := db.Search("zephyr", 10, "alias", "request"); err != nil || len(results) != 2 {
+		t.Errorf("Search() of aliases and requests = %+v, %v, want 2", results, err)
+	}
+
+	// Metadata of a bookmark is found with it.
+	if err := db.SetBookmarkMetadata("zephyr", "deploy", "quasar push"); err != nil {
+		t.Fatalf("SetBookmarkMetadata() error = %v", err)
+	}
+	if results, err := db.Search("quasar", 10); err != nil || len(results) != 1 || results[0].Ref != "zephyr" {
+		t.Errorf("Search() of bookmark metadata = %+v, %v", results, err)
+	}
+
+	// Replaced and removed rows leave no entries behind.
+	if err := db.SetShellAlias("zr", "nebula rotate"); err != nil {
+		t.Fatalf("SetShellAlias() error = %v", err)
+	}
+	if results, err := db.Search("zephyr", 10, "alias"); err != nil || len(results) != 0 {
+		t.Errorf("Search() of a replaced alias = %+v, %v, want none", results, err)
+	}
+	if err := db.RemoveBookmark("zephyr"); err != nil {
+		t.Fatalf("RemoveBookmark() error = %v", err)
+	}
+	if results, err := db.Search("quasar", 10); err != nil || len(results) != 0 {
+		t.Errorf("Search() of a removed bookmark = %+v, %v, want none", results, err)
+	}
+
+	// A stale index is rebuilt from the tables.
+	if err := db.execSQL(`DELETE FROM search_index; DELETE FROM meta WHERE key = 'search_index_version';`); err != nil {
+		t.Fatalf("execSQL() error = %v", err)
+	}
+	if err := db.prepareSearchIndex(); err != nil {
+		t.Fatalf("prepareSearchIndex() error = %v", err)
+	}
+	if results, err := db.Search("nebula", 10); err != nil || len(results) != 1 || results[0].Kind != "alias" {
+		t.Errorf("Search() after a rebuild = %+v, %v, want the alias", results, err)
+	}
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+// Session is a working context saved by 'aura session save': a directory,
+// the dev commands running in it and the file open in the editor.
+type Session struct {
+	Name       string
+	Dir        string
+	Commands   []string
+	EditorFile string
+	UpdatedAt  time.Time
+}
+
+// SaveSession saves a session, replacing any with the same name.
+func (db *DB) SaveSession(session Session) error {
+	commands, err := json.Marshal(session.Commands)
+	if err != nil {
+		return fmt.Errorf("failed to encode session commands: %w", err)
+	}
+	if session.Commands == nil {
+		commands = []byte("[]")
+	}
+	dir := config.NormalizePath(session.Dir)
+
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`INSERT OR REPLACE INTO sessions (name, dir, commands, editor_file, updated_at) VALUES (%s, %s, %s, %s, CURRENT_TIMESTAMP);`,
+			sqlString(session.Name), sqlString(dir), sqlString(string(commands)), sqlString(session.EditorFile)))
+	}
+
+	query := `INSERT OR REPLACE INTO sessions (name, dir, commands, editor_file, updated_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	if _, err := db.conn.Exec(query, session.Name, dir, string(commands), session.EditorFile); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// GetSession returns the session called name, or nil if there is none.
+func (db *DB) GetSession(name string) (*Session, error) {
+	sessions, err := db.listSessions(fmt.Sprintf(`WHERE name = %s`, sqlString(name)))
+	if err != nil || len(sessions) == 0 {
+		return nil, err
+	}
+	return &sessions[0], nil
+}
+
+// ListSessions returns the saved sessions sorted by name.
+func (db *DB) ListSessions() ([]Session, error) {
+	return db.listSessions("")
+}
+
+// listSessions returns the sessions matching where, a WHERE clause with its
+// values quoted.
+func (db *DB) listSessions(where string) ([]Session, error) {
+	var rows [][]string
+	if db.isDockerMode {
+		// Paths and commands may contain the column separator, so they are
+		// read hex encoded.
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT name, hex(dir), hex(commands), hex(editor_file), updated_at FROM sessions %s ORDER BY name;`, where))
+		if err != nil {
+			return nil, err
+		}
+		for _, parts := range results {
+			if len(parts) < 5 {
+				continue
+			}
+			row := []string{parts[0], "", "", "", parts[4]}
+			for i := 1; i <= 3; i++ {
+				decoded, _ := hex.DecodeString(parts[i])
+				row[i] = string(decoded)
+			}
+			rows = append(rows, row)
+		}
+	} else {
+		result, err := db.conn.Query(fmt.Sprintf(`SELECT name, dir, commands, editor_file, strftime('%%Y-%%m-%%d %%H:%%M:%%S', updated_at) FROM sessions %s ORDER BY name`, where))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		defer result.Close()
+		for result.Next() {
+			row := make([]string, 5)
+			if err := result.Scan(&row[0], &row[1], &row[2], &row[3], &row[4]); err != nil {
+				return nil, fmt.Errorf("failed to scan session: %w", err)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var sessions []Session
+	for _, row := range rows {
+		session := Session{Name: row[0], Dir: config.LocalPath(row[1]), EditorFile: row[3]}
+		if err := json.Unmarshal([]byte(row[2]), &session.Commands); err != nil {
+			return nil, fmt.Errorf("failed to decode the commands of session %s: %w", session.Name, err)
+		}
+		session.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", row[4])
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// RemoveSession removes the session called name and reports whether there
+// was one.
+func (db *DB) RemoveSession(name string) (bool, error) {
+	existing, err := db.GetSession(name)
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	if db.isDockerMode {
+		return true, db.execDockerSQL(fmt.Sprintf(`DELETE FROM sessions WHERE name = %s;`, sqlString(name)))
+	}
+	if _, err := db.conn.Exec(`DELETE FROM sessions WHERE name = ?`, name); err != nil {
+		return false, fmt.Errorf("failed to remove session: %w", err)
+	}
+	return true, nil
+}
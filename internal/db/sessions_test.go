@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessions(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	api := Session{Name: "api", Dir: "/test/sessions/api", Commands: []string{"npm run dev", "docker compose up | tee 'up.log'"}, EditorFile: "/test/sessions/api/src/server.ts"}
+	if err := db.SaveSession(api); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	if err := db.SaveSession(Session{Name: "docs", Dir: "/test/sessions/docs"}); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	got, err := db.GetSession("api")
+	if err != nil || got == nil {
+		t.Fatalf("GetSession() = %v, %v", got, err)
+	}
+	if got.Dir != api.Dir || len(got.Commands) != 2 || got.Commands[1] != api.Commands[1] || got.EditorFile != api.EditorFile {
+		t.Errorf("GetSession() = %+v, want %+v", got, api)
+	}
+	if time.Since(got.UpdatedAt) > time.Hour {
+		t.Errorf("GetSession() updated at %s, want about now", got.UpdatedAt)
+	}
+
+	sessions, err := db.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 2 || sessions[1].Name != "docs" || len(sessions[1].Commands) != 0 {
+		t.Errorf("ListSessions() = %+v", sessions)
+	}
+
+	if removed, err := db.RemoveSession("api"); err != nil || !removed {
+		t.Fatalf("RemoveSession() = %v, %v, want true", removed, err)
+	}
+	if removed, _ := db.RemoveSession("api"); removed {
+		t.Error("RemoveSession() of a removed session = true")
+	}
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// teamActionsTrustedPrefix prefixes the meta keys holding the approved
+// actions of team settings files, by path.
+const teamActionsTrustedPrefix = "team_actions_trusted:"
+
+// TrustedTeamActions returns the digest of the actions of the team settings
+// file at path the user approved, or "" if they approved none.
+func (db *DB) TrustedTeamActions(path string) (string, error) {
+	key := teamActionsTrustedPrefix + path
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(fmt.Sprintf(`SELECT value FROM meta WHERE key = %s;`, sqlString(key)))
+		if err != nil || len(results) == 0 {
+			return "", err
+		}
+		return results[0][0], nil
+	}
+
+	var digest string
+	err := db.conn.QueryRow(`SELECT value FROM meta WHERE key = ?`, key).Scan(&digest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read approved team actions: %w", err)
+	}
+	return digest, nil
+}
+
+// TrustTeamActions records that the user approved the actions with digest
+// of the team settings file at path.
+func (db *DB) TrustTeamActions(path, digest string) error {
+	key := teamActionsTrustedPrefix + path
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`INSERT OR REPLACE INTO meta (key, value) VALUES (%s, %s);`, sqlString(key), sqlString(digest)))
+	}
+
+	if _, err := db.conn.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?)`, key, digest); err != nil {
+		return fmt.Errorf("failed to approve team actions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package db
+
+import "testing"
+
+func TestTrustTeamActions(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	path := "/test/trust/api/.aura.yaml"
+	if digest, err := db.TrustedTeamActions(path); err != nil || digest != "" {
+		t.Errorf("TrustedTeamActions() = %q, %v, want none", digest, err)
+	}
+	for _, digest := range []string{"3f2a", "9c1e"} {
+		if err := db.TrustTeamActions(path, digest); err != nil {
+			t.Fatalf("TrustTeamActions() error = %v", err)
+		}
+		if got, err := db.TrustedTeamActions(path); err != nil || got != digest {
+			t.Errorf("TrustedTeamActions() = %q, %v, want %q", got, err, digest)
+		}
+	}
+	if digest, _ := db.TrustedTeamActions("/test/trust/web/.aura.yaml"); digest != "" {
+		t.Errorf("TrustedTeamActions() of another file = %q, want none", digest)
+	}
+}
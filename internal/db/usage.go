@@ -0,0 +1,102 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+// BookmarkUsage is how often and how recently a bookmark was used.
+type BookmarkUsage struct {
+	Bookmark *Bookmark
+	Hits     int
+	// LastUsed is zero if the bookmark was never used.
+	LastUsed time.Time
+}
+
+// RecordBookmarkUse counts a use of the bookmark with the given alias. Uses
+// are not counted in read-only mode.
+func (db *DB) RecordBookmarkUse(alias string) error {
+	if db.readOnly {
+		return nil
+	}
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`INSERT INTO bookmark_usage (alias, hits, last_used) VALUES (%s, 1, CURRENT_TIMESTAMP)
+			ON CONFLICT(alias) DO UPDATE SET hits = hits + 1, last_used = CURRENT_TIMESTAMP;`, sqlString(alias)))
+	}
+
+	stmt, err := db.prepared(`INSERT INTO bookmark_usage (alias, hits, last_used) VALUES (?, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT(alias) DO UPDATE SET hits = hits + 1, last_used = CURRENT_TIMESTAMP`)
+	if err == nil {
+		_, err = stmt.Exec(alias)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record bookmark use: %w", err)
+	}
+	return nil
+}
+
+// ListBookmarkUsage returns the usage of all bookmarks, the most used first.
+func (db *DB) ListBookmarkUsage() ([]BookmarkUsage, error) {
+	query := `SELECT b.id, b.alias, b.path, b.created_at, COALESCE(u.hits, 0), u.last_used
+		FROM bookmarks b LEFT JOIN bookmark_usage u ON u.alias = b.alias
+		ORDER BY COALESCE(u.hits, 0) DESC, u.last_used DESC, b.alias`
+
+	if db.isDockerMode {
+		results, err := db.queryDockerSQL(query + ";")
+		if err != nil {
+			return nil, err
+		}
+
+		var usage []BookmarkUsage
+		for _, parts := range results {
+			if len(parts) < 6 {
+				continue
+			}
+			id, _ := strconv.Atoi(parts[0])
+			createdAt, _ := time.Parse("2006-01-02 15:04:05", parts[3])
+			hits, _ := strconv.Atoi(parts[4])
+			lastUsed, _ := time.Parse("2006-01-02 15:04:05", parts[5])
+			usage = append(usage, BookmarkUsage{
+				Bookmark: &Bookmark{ID: id, Alias: parts[1], Path: config.LocalPath(parts[2]), CreatedAt: createdAt},
+				Hits:     hits,
+				LastUsed: lastUsed,
+			})
+		}
+		return usage, nil
+	}
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmark usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []BookmarkUsage
+	for rows.Next() {
+		var bookmark Bookmark
+		var hits int
+		var lastUsed sql.NullTime
+		if err := rows.Scan(&bookmark.ID, &bookmark.Alias, &bookmark.Path, &bookmark.CreatedAt, &hits, &lastUsed); err != nil {
+			return nil, fmt.Errorf("failed to scan bookmark usage: %w", err)
+		}
+		bookmark.Path = config.LocalPath(bookmark.Path)
+		usage = append(usage, BookmarkUsage{Bookmark: &bookmark, Hits: hits, LastUsed: lastUsed.Time})
+	}
+	return usage, nil
+}
+
+// removeBookmarkUsage forgets the usage of the bookmark with the given alias.
+func (db *DB) removeBookmarkUsage(alias string) error {
+	if db.isDockerMode {
+		return db.execDockerSQL(fmt.Sprintf(`DELETE FROM bookmark_usage WHERE alias = %s;`, sqlString(alias)))
+	}
+
+	if _, err := db.conn.Exec(`DELETE FROM bookmark_usage WHERE alias = ?`, alias); err != nil {
+		return fmt.Errorf("failed to remove bookmark usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import "testing"
+
+func TestBookmarkUsage(t *testing.T) {
+	db, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, alias := range []string{"usage-often", "usage-once", "usage-never"} {
+		if err := db.AddBookmark(alias, "/test/"+alias); err != nil {
+			t.Fatalf("AddBookmark() error = %v", err)
+		}
+	}
+	for _, alias := range []string{"usage-often", "usage-once", "usage-often"} {
+		if err := db.RecordBookmarkUse(alias); err != nil {
+			t.Fatalf("RecordBookmarkUse() error = %v", err)
+		}
+	}
+
+	usage, err := db.ListBookmarkUsage()
+	if err != nil {
+		t.Fatalf("ListBookmarkUsage() error = %v", err)
+	}
+	hits := map[string]int{}
+	var order []string
+	for _, entry := range usage {
+		if len(entry.Bookmark.Alias) > 6 && entry.Bookmark.Alias[:6] == "usage-" {
+			hits[entry.Bookmark.Alias] = entry.Hits
+			order = append(order, entry.Bookmark.Alias)
+			if (entry.Hits == 0) != entry.LastUsed.IsZero() {
+				t.Errorf("Unexpected last use of %s: %v", entry.Bookmark.Alias, entry.LastUsed)
+			}
+		}
+	}
+	if hits["usage-often"] != 2 || hits["usage-once"] != 1 || hits["usage-never"] != 0 {
+		t.Errorf("Unexpected hits %v", hits)
+	}
+	if len(order) != 3 || order[0] != "usage-often" || order[2] != "usage-never" {
+		t.Errorf("Expected the most used first, got %v", order)
+	}
+
+	// Usage is forgotten with the bookmark
+	if err := db.RemoveBookmark("usage-often"); err != nil {
+		t.Fatalf("RemoveBookmark() error = %v", err)
+	}
+	db.AddBookmark("usage-often", "/test/usage-often")
+	usage, _ = db.ListBookmarkUsage()
+	for _, entry := range usage {
+		if entry.Bookmark.Alias == "usage-often" && entry.Hits != 0 {
+			t.Errorf("Expected usage to be removed with the bookmark, got %d hits", entry.Hits)
+		}
+	}
+}
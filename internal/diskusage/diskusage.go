@@ -0,0 +1,144 @@
+// Package diskusage measures how much space the files and directories of a
+// tree take, to find what makes a working tree big.
+package diskusage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Node is a file or a directory with the total size of the files below it.
+type Node struct {
+	Name string
+	// Path is the slash separated path relative to the scanned root, "" for
+	// the root itself.
+	Path string
+	Dir  bool
+	// Size is the size in bytes, of all files below a directory.
+	Size int64
+	// Files is the number of files, 1 for a file.
+	Files    int
+	Children []*Node
+}
+
+// Order is an order of the children of a directory.
+type Order string
+
+// Orders of the children of a directory.
+const (
+	BySize  Order = "size"
+	ByFiles Order = "files"
+	ByName  Order = "name"
+)
+
+// ParseOrder parses the name of an order.
+func ParseOrder(name string) (Order, error) {
+	switch order := Order(strings.ToLower(name)); order {
+	case BySize, ByFiles, ByName:
+		return order, nil
+	}
+	return "", fmt.Errorf("unknown order '%s', use size, files or name", name)
+}
+
+// Scan measures the tree below root. Symbolic links count as small files
+// and are not followed; directories that can't be read count as empty.
+func Scan(root string) (*Node, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Lstat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return &Node{Name: filepath.Base(root), Size: info.Size(), Files: 1}, nil
+	}
+
+	node := &Node{Name: filepath.Base(root), Dir: true}
+	scanDir(root, node)
+	return node, nil
+}
+
+// scanDir adds the entries of dir to node.
+func scanDir(dir string, node *Node) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		child := &Node{Name: entry.Name(), Path: joinPath(node.Path, entry.Name())}
+		if entry.IsDir() {
+			child.Dir = true
+			scanDir(filepath.Join(dir, entry.Name()), child)
+		} else {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			child.Size = info.Size()
+			child.Files = 1
+		}
+		node.Size += child.Size
+		node.Files += child.Files
+		node.Children = append(node.Children, child)
+	}
+}
+
+func joinPath(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// Sort orders the children of n and of all directories below it, the
+// biggest first for BySize and ByFiles.
+func (n *Node) Sort(order Order) {
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		a, b := n.Children[i], n.Children[j]
+		switch order {
+		case BySize:
+			if a.Size != b.Size {
+				return a.Size > b.Size
+			}
+		case ByFiles:
+			if a.Files != b.Files {
+				return a.Files > b.Files
+			}
+		}
+		return a.Name < b.Name
+	})
+	for _, child := range n.Children {
+		child.Sort(order)
+	}
+}
+
+// Largest returns the count biggest files below n, the biggest first.
+func (n *Node) Largest(count int) []*Node {
+	var files []*Node
+	var collect func(node *Node)
+	collect = func(node *Node) {
+		if !node.Dir {
+			files = append(files, node)
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(n)
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Size != files[j].Size {
+			return files[i].Size > files[j].Size
+		}
+		return files[i].Path < files[j].Path
+	})
+	if len(files) > count {
+		files = files[:count]
+	}
+	return files
+}
@@ -0,0 +1,74 @@
+package diskusage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "README.md"), 100)
+	writeFile(t, filepath.Join(root, "assets", "logo.png"), 3000)
+	writeFile(t, filepath.Join(root, "assets", "icons", "a.svg"), 50)
+	writeFile(t, filepath.Join(root, "src", "main.go"), 400)
+	writeFile(t, filepath.Join(root, "src", "util.go"), 300)
+	writeFile(t, filepath.Join(root, "src", "more.go"), 200)
+
+	tree, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if tree.Size != 4050 || tree.Files != 6 || !tree.Dir {
+		t.Fatalf("Scan() = size %d, %d files", tree.Size, tree.Files)
+	}
+
+	names := func(node *Node) string {
+		var result []string
+		for _, child := range node.Children {
+			result = append(result, child.Name)
+		}
+		return strings.Join(result, " ")
+	}
+
+	tree.Sort(BySize)
+	if got := names(tree); got != "assets src README.md" {
+		t.Errorf("children by size = %s", got)
+	}
+	tree.Sort(ByFiles)
+	if got := names(tree); got != "src assets README.md" {
+		t.Errorf("children by files = %s", got)
+	}
+	tree.Sort(ByName)
+	if got := names(tree); got != "README.md assets src" {
+		t.Errorf("children by name = %s", got)
+	}
+
+	var paths []string
+	for _, file := range tree.Largest(3) {
+		paths = append(paths, file.Path)
+	}
+	if got := strings.Join(paths, " "); got != "assets/logo.png src/main.go src/util.go" {
+		t.Errorf("Largest(3) = %s", got)
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	if order, err := ParseOrder("Files"); err != nil || order != ByFiles {
+		t.Errorf("ParseOrder(Files) = %q, %v", order, err)
+	}
+	if _, err := ParseOrder("age"); err == nil {
+		t.Error("ParseOrder(age) should fail")
+	}
+}
@@ -0,0 +1,141 @@
+// Package docgen finds the exported declarations of Go, Python, JavaScript
+// and TypeScript files with their doc comments, and inserts or replaces
+// those comments line by line, leaving the rest of the file untouched.
+package docgen
+
+import (
+	"bytes"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+)
+
+// Symbol is an exported declaration of a source file.
+type Symbol struct {
+	// Name identifies the symbol in the file, methods as Type.Method.
+	Name string
+	// Kind is the kind of declaration, such as func, type or class.
+	Kind string
+	// Line is the line of the declaration, counting from 1.
+	Line int
+	// Doc is the text of the doc comment, without comment markers, or ""
+	// if the symbol has none.
+	Doc string
+
+	// start and end are the lines, counting from 0, the doc comment spans
+	// and is replaced by. They are equal where a missing one is inserted.
+	start, end int
+	// indent is the indentation of the doc comment.
+	indent string
+}
+
+// Supported reports whether docgen handles the files of language.
+func Supported(language string) bool {
+	switch language {
+	case "Go", "Python", "JavaScript", "TypeScript":
+		return true
+	}
+	return false
+}
+
+// Symbols returns the exported symbols of file with content src, in the
+// order of their lines.
+func Symbols(file string, src []byte) ([]Symbol, error) {
+	var (
+		symbols []Symbol
+		err     error
+	)
+	switch language := projectinfo.Language(file); language {
+	case "Go":
+		symbols, err = goSymbols(file, src)
+	case "Python":
+		symbols = pythonSymbols(src)
+	case "JavaScript", "TypeScript":
+		symbols = scriptSymbols(src)
+	default:
+		return nil, failure.New(failure.UserInput, "documenting %s is not supported, only Go, Python, JavaScript and TypeScript", file)
+	}
+	sort.SliceStable(symbols, func(i, j int) bool { return symbols[i].Line < symbols[j].Line })
+	return symbols, err
+}
+
+// Apply sets the doc comments of symbols of file to docs, keyed by symbol
+// name, and returns the new content. Symbols without an entry in docs keep
+// their comment. Go files are formatted with gofmt afterwards.
+func Apply(file string, src []byte, symbols []Symbol, docs map[string]string) []byte {
+	language := projectinfo.Language(file)
+	var edits []Symbol
+	for _, symbol := range symbols {
+		if strings.TrimSpace(docs[symbol.Name]) != "" {
+			edits = append(edits, symbol)
+		}
+	}
+	// Edit from the bottom up so that the lines of the edits above stay put.
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+
+	lines := strings.Split(string(src), "\n")
+	crlf := bytes.Contains(src, []byte("\r\n"))
+	for _, symbol := range edits {
+		comment := render(language, docs[symbol.Name], symbol.indent)
+		if crlf {
+			for i := range comment {
+				comment[i] += "\r"
+			}
+		}
+		lines = append(append(append([]string{}, lines[:symbol.start]...), comment...), lines[symbol.end:]...)
+	}
+	result := []byte(strings.Join(lines, "\n"))
+
+	if language == "Go" {
+		if formatted, err := format.Source(result); err == nil {
+			return formatted
+		}
+	}
+	return result
+}
+
+// render returns the lines of a doc comment with text in the syntax of
+// language.
+func render(language, text, indent string) []string {
+	text = strings.TrimSpace(text)
+	paragraphs := strings.Split(text, "\n")
+	var lines []string
+	switch language {
+	case "Go":
+		for _, line := range paragraphs {
+			lines = append(lines, strings.TrimRight(indent+"// "+strings.TrimSpace(line), " "))
+		}
+	case "Python":
+		text = strings.ReplaceAll(text, `"""`, `\"\"\"`)
+		paragraphs = strings.Split(text, "\n")
+		if len(paragraphs) == 1 {
+			return []string{indent + `"""` + text + `"""`}
+		}
+		lines = append(lines, indent+`"""`+strings.TrimSpace(paragraphs[0]))
+		for _, line := range paragraphs[1:] {
+			lines = append(lines, strings.TrimRight(indent+strings.TrimSpace(line), " "))
+		}
+		lines = append(lines, indent+`"""`)
+	default:
+		text = strings.ReplaceAll(text, "*/", "* /")
+		lines = append(lines, indent+"/**")
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, strings.TrimRight(indent+" * "+strings.TrimSpace(line), " "))
+		}
+		lines = append(lines, indent+" */")
+	}
+	return lines
+}
+
+// leadingSpace returns the indentation of line.
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// splitLines splits src into lines without their line endings.
+func splitLines(src []byte) []string {
+	return strings.Split(string(bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))), "\n")
+}
@@ -0,0 +1,182 @@
+package docgen
+
+import (
+	"testing"
+)
+
+func TestSymbols(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		src  string
+		want []Symbol
+	}{
+		{
+			name: "go",
+			file: "client.go",
+			src: `package client
+
+// Client talks to the API.
+type Client struct{}
+
+func (c *Client) Send() {}
+
+func (c *Client) retry() {}
+
+type helper struct{}
+
+func (helper) Run() {}
+
+const (
+	// Version of the client.
+	Version = "1"
+	debug   = false
+)
+
+//go:embed schema.sql
+var Schema string
+
+func New() *Client { return nil }
+`,
+			want: []Symbol{
+				{Name: "Client", Kind: "type", Line: 4, Doc: "Client talks to the API."},
+				{Name: "Client.Send", Kind: "method", Line: 6},
+				{Name: "Version", Kind: "const", Line: 16, Doc: "Version of the client."},
+				{Name: "Schema", Kind: "var", Line: 21},
+				{Name: "New", Kind: "func", Line: 23},
+			},
+		},
+		{
+			name: "python",
+			file: "models.py",
+			src: `import os
+
+
+class User(Base):
+    """A user of the app."""
+
+    name = "x"
+
+    def save(self,
+             force=False):
+        def inner():
+            pass
+        return force
+
+    def _hidden(self):
+        pass
+
+
+def load(path): return None
+
+
+async def fetch(url):
+    '''
+    Fetch url.
+    '''
+    return url
+`,
+			want: []Symbol{
+				{Name: "User", Kind: "class", Line: 4, Doc: "A user of the app."},
+				{Name: "User.save", Kind: "def", Line: 9},
+				{Name: "fetch", Kind: "def", Line: 22, Doc: "Fetch url."},
+			},
+		},
+		{
+			name: "typescript",
+			file: "format.ts",
+			src: `import { x } from "./x";
+
+/**
+ * Formats a date.
+ */
+export function formatDate(d: Date): string { return ""; }
+
+/* not a doc */
+export const LIMIT = 10;
+function local() {}
+export default class Formatter {}
+export type Options = { short: boolean };
+`,
+			want: []Symbol{
+				{Name: "formatDate", Kind: "function", Line: 6, Doc: "Formats a date."},
+				{Name: "LIMIT", Kind: "const", Line: 9},
+				{Name: "Formatter", Kind: "class", Line: 11},
+				{Name: "Options", Kind: "type", Line: 12},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Symbols(tt.file, []byte(tt.src))
+			if err != nil {
+				t.Fatalf("Symbols() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Symbols() = %+v", got)
+			}
+			for i, want := range tt.want {
+				g := got[i]
+				if g.Name != want.Name || g.Kind != want.Kind || g.Line != want.Line || g.Doc != want.Doc {
+					t.Errorf("Symbols()[%d] = %+v, want %+v", i, g, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSymbolsUnsupported(t *testing.T) {
+	if _, err := Symbols("main.rs", []byte("fn main() {}")); err == nil {
+		t.Error("Symbols() of a Rust file error = nil")
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		src  string
+		docs map[string]string
+		want string
+	}{
+		{
+			name: "go inserts and replaces",
+			file: "client.go",
+			src:  "package client\n\n// Client old.\ntype Client struct{}\n\n//go:embed schema.sql\nvar Schema string\n\nfunc New() *Client { return nil }\n",
+			docs: map[string]string{
+				"Client": "Client talks to the API.\nIt is safe for concurrent use.",
+				"Schema": "Schema is the database schema.",
+				"New":    "New returns a Client.",
+			},
+			want: "package client\n\n// Client talks to the API.\n// It is safe for concurrent use.\ntype Client struct{}\n\n// Schema is the database schema.\n//\n//go:embed schema.sql\nvar Schema string\n\n// New returns a Client.\nfunc New() *Client { return nil }\n",
+		},
+		{
+			name: "python docstrings",
+			file: "models.py",
+			src:  "class User:\n    '''Old.'''\n\n    def save(self):\n        return 1\n",
+			docs: map[string]string{
+				"User":      "A user of the app.",
+				"User.save": "Save the user.\n\nReturns 1.",
+			},
+			want: "class User:\n    \"\"\"A user of the app.\"\"\"\n\n    def save(self):\n        \"\"\"Save the user.\n\n        Returns 1.\n        \"\"\"\n        return 1\n",
+		},
+		{
+			name: "jsdoc",
+			file: "format.js",
+			src:  "/** Old. */\nexport function format(d) {}\nexport const LIMIT = 10;\n",
+			docs: map[string]string{"format": "Formats d.", "LIMIT": "Maximum length.", "missing": "Ignored."},
+			want: "/**\n * Formats d.\n */\nexport function format(d) {}\n/**\n * Maximum length.\n */\nexport const LIMIT = 10;\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbols, err := Symbols(tt.file, []byte(tt.src))
+			if err != nil {
+				t.Fatalf("Symbols() error = %v", err)
+			}
+			if got := string(Apply(tt.file, []byte(tt.src), symbols, tt.docs)); got != tt.want {
+				t.Errorf("Apply() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
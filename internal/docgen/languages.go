@@ -0,0 +1,286 @@
+package docgen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// goSymbols returns the exported functions, methods of exported types,
+// types, constants and variables of a Go file.
+func goSymbols(file string, src []byte) ([]Symbol, error) {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+	if err != nil {
+		return nil, failure.New(failure.UserInput, "failed to parse %s: %w", file, err)
+	}
+	lines := splitLines(src)
+
+	symbol := func(name, kind string, pos token.Pos, doc *ast.CommentGroup) Symbol {
+		line := fset.Position(pos).Line
+		s := Symbol{Name: name, Kind: kind, Line: line, start: line - 1, end: line - 1, indent: leadingSpace(lines[line-1])}
+		if doc == nil {
+			return s
+		}
+		s.Doc = strings.TrimSpace(doc.Text())
+		s.start = fset.Position(doc.Pos()).Line - 1
+		// Directives such as //go:embed below the comment are kept.
+		for _, comment := range doc.List {
+			if isDirective(comment.Text) {
+				s.end = fset.Position(comment.Pos()).Line - 1
+				break
+			}
+		}
+		if s.Doc == "" {
+			s.start = s.end
+		}
+		return s
+	}
+
+	var symbols []Symbol
+	for _, decl := range parsed.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Name.IsExported() {
+				continue
+			}
+			name, kind := decl.Name.Name, "func"
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				receiver := receiverType(decl.Recv.List[0].Type)
+				if !ast.IsExported(receiver) {
+					continue
+				}
+				name, kind = receiver+"."+name, "method"
+			}
+			symbols = append(symbols, symbol(name, kind, decl.Pos(), decl.Doc))
+		case *ast.GenDecl:
+			kind := decl.Tok.String()
+			grouped := decl.Lparen.IsValid()
+			for _, spec := range decl.Specs {
+				var (
+					names []*ast.Ident
+					doc   *ast.CommentGroup
+				)
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names, doc = []*ast.Ident{spec.Name}, spec.Doc
+				case *ast.ValueSpec:
+					names, doc = spec.Names, spec.Doc
+				default:
+					continue
+				}
+				if len(names) == 0 || !names[0].IsExported() {
+					continue
+				}
+				if !grouped {
+					symbols = append(symbols, symbol(names[0].Name, kind, decl.Pos(), decl.Doc))
+					break
+				}
+				symbols = append(symbols, symbol(names[0].Name, kind, spec.Pos(), doc))
+			}
+		}
+	}
+	return symbols, nil
+}
+
+// isDirective reports whether comment is a directive rather than
+// documentation, such as //go:generate or //export.
+func isDirective(comment string) bool {
+	for _, prefix := range []string{"//go:", "//line ", "//export ", "//extern ", "//nolint"} {
+		if strings.HasPrefix(comment, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// receiverType returns the name of the type of a method receiver, such as
+// Client for *Client or List for List[T].
+func receiverType(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverType(expr.X)
+	case *ast.IndexExpr:
+		return receiverType(expr.X)
+	case *ast.IndexListExpr:
+		return receiverType(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// pythonDefinition matches function and class definitions, capturing the
+// indentation, the keyword and the name.
+var pythonDefinition = regexp.MustCompile(`^([ \t]*)(?:async[ \t]+)?(def|class)[ \t]+([A-Za-z_]\w*)`)
+
+// pythonSymbols returns the public top-level functions and classes of a
+// Python file and the public methods of those classes. Their docstrings are
+// the first statement of their bodies.
+func pythonSymbols(src []byte) []Symbol {
+	lines := splitLines(src)
+	var (
+		symbols    []Symbol
+		class      string
+		bodyIndent string
+	)
+	for i := 0; i < len(lines); i++ {
+		match := pythonDefinition.FindStringSubmatch(lines[i])
+		if match == nil {
+			// Any other top-level statement ends the class before it.
+			trimmed := strings.TrimSpace(lines[i])
+			if leadingSpace(lines[i]) == "" && trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, "@") {
+				class = ""
+			}
+			continue
+		}
+		indent, keyword, name := match[1], match[2], match[3]
+
+		qualified := name
+		switch {
+		case indent == "":
+			class, bodyIndent = "", ""
+			if keyword == "class" {
+				class = name
+			}
+		case class != "":
+			if bodyIndent == "" {
+				bodyIndent = indent
+			}
+			if indent != bodyIndent {
+				continue
+			}
+			qualified = class + "." + name
+		default:
+			continue
+		}
+
+		line := i + 1
+		end, ok := pythonSignatureEnd(lines, i)
+		if !ok {
+			continue
+		}
+		i = end
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		next := end + 1
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next >= len(lines) || len(leadingSpace(lines[next])) <= len(indent) {
+			continue
+		}
+
+		symbol := Symbol{Name: qualified, Kind: keyword, Line: line, start: end + 1, end: end + 1, indent: leadingSpace(lines[next])}
+		if docEnd, doc, ok := pythonDocstring(lines, next); ok {
+			symbol.Doc, symbol.start, symbol.end = doc, next, docEnd+1
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// pythonSignatureEnd returns the line ending the signature of the
+// definition at line start with a colon, after any lines its parentheses
+// span. Definitions with their body on the same line are not ok.
+func pythonSignatureEnd(lines []string, start int) (int, bool) {
+	depth := 0
+	for end := start; end < len(lines); end++ {
+		line := stripPythonComment(lines[end])
+		depth += strings.Count(line, "(") + strings.Count(line, "[") - strings.Count(line, ")") - strings.Count(line, "]")
+		if depth > 0 {
+			continue
+		}
+		return end, strings.HasSuffix(line, ":")
+	}
+	return 0, false
+}
+
+// stripPythonComment returns line without a trailing comment and space.
+func stripPythonComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 && !strings.ContainsAny(line[:i], `"'`) {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
+
+// pythonDocstring returns the last line and the text of the docstring
+// starting at line start, if there is one.
+func pythonDocstring(lines []string, start int) (end int, doc string, ok bool) {
+	first := strings.TrimSpace(lines[start])
+	first = strings.TrimLeft(first, "rRuU")
+	var quote string
+	for _, q := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(first, q) {
+			quote = q
+		}
+	}
+	if quote == "" {
+		return 0, "", false
+	}
+	rest := first[len(quote):]
+	if i := strings.Index(rest, quote); i >= 0 {
+		return start, strings.TrimSpace(rest[:i]), true
+	}
+	text := []string{rest}
+	for end = start + 1; end < len(lines); end++ {
+		line := strings.TrimSpace(lines[end])
+		if i := strings.Index(line, quote); i >= 0 {
+			text = append(text, line[:i])
+			return end, strings.TrimSpace(strings.Join(text, "\n")), true
+		}
+		text = append(text, line)
+	}
+	return 0, "", false
+}
+
+// scriptExport matches the top-level exported declarations of JavaScript
+// and TypeScript, capturing the kind and the name.
+var scriptExport = regexp.MustCompile(`^export[ \t]+(?:default[ \t]+)?(?:declare[ \t]+)?(?:abstract[ \t]+)?(?:async[ \t]+)?(function\*?|class|const|let|var|interface|type|enum)[ \t]+([A-Za-z_$][\w$]*)`)
+
+// scriptSymbols returns the exported declarations of a JavaScript or
+// TypeScript file with their JSDoc comments.
+func scriptSymbols(src []byte) []Symbol {
+	lines := splitLines(src)
+	var symbols []Symbol
+	for i, line := range lines {
+		match := scriptExport.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		symbol := Symbol{Name: match[2], Kind: strings.TrimSuffix(match[1], "*"), Line: i + 1, start: i, end: i}
+		if i > 0 && strings.HasSuffix(strings.TrimSpace(lines[i-1]), "*/") {
+			for start := i - 1; start >= 0; start-- {
+				trimmed := strings.TrimSpace(lines[start])
+				if !strings.HasPrefix(trimmed, "/*") {
+					continue
+				}
+				if strings.HasPrefix(trimmed, "/**") {
+					symbol.start = start
+					symbol.Doc = jsdocText(lines[start:i])
+				}
+				break
+			}
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// jsdocText returns the text of the lines of a JSDoc comment.
+func jsdocText(lines []string) string {
+	var text []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "/**")
+		line = strings.TrimSuffix(line, "*/")
+		line = strings.TrimPrefix(strings.TrimSpace(line), "*")
+		text = append(text, strings.TrimSpace(line))
+	}
+	return strings.TrimSpace(strings.Join(text, "\n"))
+}
@@ -0,0 +1,161 @@
+// Package dotenv reads, compares and redacts dotenv (.env) files.
+package dotenv
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Line is a single line of a dotenv file.
+type Line struct {
+	// Raw is the line as it appears in the file.
+	Raw string
+	// Key is the variable name, empty for comments and blank lines.
+	Key string
+	// Value is the unquoted variable value.
+	Value string
+}
+
+// File is a parsed dotenv file that keeps comments and line order.
+type File struct {
+	Lines []Line
+}
+
+// Parse parses dotenv content. Blank lines and comments are kept, an optional
+// "export " prefix is accepted and surrounding quotes are removed from values.
+func Parse(r io.Reader) (*File, error) {
+	file := &File{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := Line{Raw: raw}
+
+		trimmed := strings.TrimSpace(raw)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			trimmed = strings.TrimPrefix(trimmed, "export ")
+			if key, value, found := strings.Cut(trimmed, "="); found {
+				line.Key = strings.TrimSpace(key)
+				line.Value = unquote(strings.TrimSpace(value))
+			}
+		}
+
+		file.Lines = append(file.Lines, line)
+	}
+	return file, scanner.Err()
+}
+
+// ParseFile parses the dotenv file at path.
+func ParseFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Read parses the dotenv file at path into a map of variables.
+func Read(path string) (map[string]string, error) {
+	file, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return file.Map(), nil
+}
+
+// Map returns the variables of the file. Later definitions win.
+func (f *File) Map() map[string]string {
+	env := make(map[string]string)
+	for _, line := range f.Lines {
+		if line.Key != "" {
+			env[line.Key] = line.Value
+		}
+	}
+	return env
+}
+
+// Redacted returns the file content with the values of sensitive variables
+// removed, suitable for committing as .env.example.
+func (f *File) Redacted() string {
+	var b strings.Builder
+	for _, line := range f.Lines {
+		if line.Key == "" {
+			b.WriteString(line.Raw)
+		} else {
+			b.WriteString(line.Key + "=" + RedactValue(line.Key, line.Value))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// sensitiveKeyWords lists name fragments of variables that hold secrets.
+var sensitiveKeyWords = []string{
+	"SECRET", "TOKEN", "PASSWORD", "PASSWD", "PASS", "PWD", "KEY",
+	"CREDENTIAL", "PRIVATE", "AUTH", "SALT", "SIGNATURE", "DSN",
+}
+
+// IsSensitiveKey reports whether a variable name suggests a secret value.
+func IsSensitiveKey(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, word := range sensitiveKeyWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactValue returns a value safe to share for the given variable. Secrets
+// are removed entirely and passwords embedded in URLs are replaced.
+func RedactValue(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	if IsSensitiveKey(key) {
+		return ""
+	}
+
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "password")
+			return u.String()
+		}
+	}
+
+	if strings.ContainsAny(value, " #") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// Diff compares the variables of env against example and returns the sorted
+// names missing from env and the names not documented in example.
+func Diff(env, example map[string]string) (missing, undocumented []string) {
+	for key := range example {
+		if _, ok := env[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	for key := range env {
+		if _, ok := example[key]; !ok {
+			undocumented = append(undocumented, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(undocumented)
+	return missing, undocumented
+}
+
+// unquote removes matching single or double quotes around a value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
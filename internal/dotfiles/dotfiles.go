@@ -0,0 +1,351 @@
+// Package dotfiles links the files of a dotfiles repository into the home
+// directory, as described by the repository's mapping file. Files ending in
+// .tmpl are rendered with machine-specific variables instead of linked.
+package dotfiles
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// MappingFile is the name of the mapping file at the root of a dotfiles
+// repository.
+const MappingFile = "aura-dotfiles.yaml"
+
+// TemplateExtension marks files that are rendered instead of linked.
+const TemplateExtension = ".tmpl"
+
+// BackupSuffix is appended to files that are replaced when linking with force.
+const BackupSuffix = ".aura-backup"
+
+// DefaultMapping is written by `aura dotfiles init` to repositories without a
+// mapping file.
+const DefaultMapping = `# Files of this repository and where they belong, relative to the home
+# directory. Files ending in .tmpl are rendered with the variables below
+# and written instead of linked.
+links:
+  # bashrc: .bashrc
+  # config/nvim: .config/nvim
+  # gitconfig.tmpl: .gitconfig
+
+# Variables available in templates as {{ .Vars.name }}, next to {{ .Hostname }},
+# {{ .OS }}, {{ .Arch }}, {{ .User }} and {{ .Home }}.
+variables:
+  # email: me@example.com
+
+# Variables overriding the ones above on a machine, by hostname.
+machines:
+  # work-laptop:
+  #   email: me@work.example.com
+`
+
+// Mapping is the content of a mapping file.
+type Mapping struct {
+	// Links maps paths in the repository to paths relative to the home
+	// directory.
+	Links     map[string]string            `yaml:"links"`
+	Variables map[string]string            `yaml:"variables"`
+	Machines  map[string]map[string]string `yaml:"machines"`
+}
+
+// LoadMapping reads the mapping file of the repository at dir.
+func LoadMapping(dir string) (*Mapping, error) {
+	content, err := os.ReadFile(filepath.Join(dir, MappingFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no %s in %s, run 'aura dotfiles init' first", MappingFile, dir)
+		}
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+
+	mapping := &Mapping{}
+	if err := yaml.Unmarshal(content, mapping); err != nil {
+		return nil, fmt.Errorf("invalid mapping file: %w", err)
+	}
+	return mapping, nil
+}
+
+// Machine describes the machine templates are rendered for.
+type Machine struct {
+	Hostname string
+	OS       string
+	Arch     string
+	User     string
+	Home     string
+	Vars     map[string]string
+}
+
+// CurrentMachine returns the current machine with the variables of mapping,
+// overridden by the ones for its hostname.
+func CurrentMachine(mapping *Mapping, home string) Machine {
+	hostname, _ := os.Hostname()
+	machine := Machine{
+		Hostname: hostname,
+		OS:       platform.Current().OS,
+		Arch:     runtime.GOARCH,
+		User:     os.Getenv("USER"),
+		Home:     home,
+		Vars:     make(map[string]string),
+	}
+	if machine.User == "" {
+		machine.User = os.Getenv("USERNAME")
+	}
+
+	for name, value := range mapping.Variables {
+		machine.Vars[name] = value
+	}
+	for name, value := range mapping.Machines[hostname] {
+		machine.Vars[name] = value
+	}
+	return machine
+}
+
+// Entry is a file or directory of the repository and where it belongs.
+type Entry struct {
+	Source string
+	Target string
+}
+
+// Template reports whether the entry is rendered instead of linked.
+func (e Entry) Template() bool {
+	return strings.HasSuffix(e.Source, TemplateExtension)
+}
+
+// Entries returns the entries of mapping for the repository at dir and the
+// home directory, sorted by target.
+func Entries(mapping *Mapping, dir, home string) ([]Entry, error) {
+	var entries []Entry
+	for source, target := range mapping.Links {
+		if target == "" {
+			return nil, fmt.Errorf("no target for '%s'", source)
+		}
+		if filepath.IsAbs(source) || strings.HasPrefix(filepath.Clean(source), "..") {
+			return nil, fmt.Errorf("'%s' is outside the repository", source)
+		}
+
+		target = strings.TrimPrefix(strings.TrimPrefix(target, "~/"), `~\`)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(home, target)
+		}
+		entries = append(entries, Entry{Source: filepath.Join(dir, source), Target: target})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Target < entries[j].Target })
+	return entries, nil
+}
+
+// State is how the target of an entry relates to its source.
+type State int
+
+const (
+	// Linked means the target is a link to the source or the rendered template.
+	Linked State = iota
+	// Missing means the target does not exist.
+	Missing
+	// Outdated means the target is a template rendered by Aura that changed
+	// since.
+	Outdated
+	// Conflict means the target is an unrelated file.
+	Conflict
+	// SourceMissing means the source does not exist in the repository.
+	SourceMissing
+)
+
+func (s State) String() string {
+	switch s {
+	case Linked:
+		return "linked"
+	case Missing:
+		return "missing"
+	case Outdated:
+		return "outdated"
+	case Conflict:
+		return "conflict"
+	default:
+		return "source missing"
+	}
+}
+
+// Renders remembers, by target, the hash of the template last rendered there,
+// so a rendered target can be told apart from a file of the user.
+type Renders map[string]string
+
+// LoadRenders reads the renders remembered in the file at path. A missing or
+// broken file means there are none.
+func LoadRenders(path string) Renders {
+	renders := Renders{}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &renders)
+	}
+	return renders
+}
+
+// Save writes the renders to the file at path.
+func (r Renders) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// rendered reports whether content is what was last rendered at target.
+func (r Renders) rendered(target string, content []byte) bool {
+	return r[target] != "" && r[target] == hash(content)
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Check returns the state of entry on machine. A template target is only
+// outdated if renders has it as rendered by Aura, and a conflict otherwise.
+func Check(entry Entry, machine Machine, renders Renders) (State, error) {
+	if _, err := os.Stat(entry.Source); err != nil {
+		return SourceMissing, nil
+	}
+
+	info, err := os.Lstat(entry.Target)
+	if os.IsNotExist(err) {
+		return Missing, nil
+	}
+	if err != nil {
+		return Conflict, err
+	}
+
+	if entry.Template() {
+		if !info.Mode().IsRegular() {
+			return Conflict, nil
+		}
+		rendered, err := Render(entry.Source, machine)
+		if err != nil {
+			return Conflict, err
+		}
+		current, err := os.ReadFile(entry.Target)
+		if err != nil {
+			return Conflict, err
+		}
+		if bytes.Equal(current, rendered) {
+			return Linked, nil
+		}
+		if renders.rendered(entry.Target, current) {
+			return Outdated, nil
+		}
+		return Conflict, nil
+	}
+
+	if info.Mode()&os.ModeSymlink == 0 {
+		return Conflict, nil
+	}
+	destination, err := os.Readlink(entry.Target)
+	if err != nil {
+		return Conflict, err
+	}
+	if destination == entry.Source {
+		return Linked, nil
+	}
+	return Conflict, nil
+}
+
+// Render renders the template at path for machine.
+func Render(path string, machine Machine) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", filepath.Base(path), err)
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, machine); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", filepath.Base(path), err)
+	}
+	return output.Bytes(), nil
+}
+
+// Link links or renders entry and records rendered templates in renders. A
+// conflicting target is moved aside with BackupSuffix if force is set, and is
+// an error otherwise.
+func Link(entry Entry, machine Machine, renders Renders, force bool) error {
+	state, err := Check(entry, machine, renders)
+	if err != nil {
+		return err
+	}
+
+	switch state {
+	case Linked:
+		if entry.Template() {
+			// The target is the render, so it is Aura's to update from now on
+			if current, err := os.ReadFile(entry.Target); err == nil {
+				renders[entry.Target] = hash(current)
+			}
+		}
+		return nil
+	case SourceMissing:
+		return fmt.Errorf("'%s' does not exist", entry.Source)
+	case Conflict:
+		if !force {
+			return fmt.Errorf("'%s' already exists, use --force to back it up and replace it", entry.Target)
+		}
+		if err := os.Rename(entry.Target, backupPath(entry.Target)); err != nil {
+			return fmt.Errorf("failed to back up '%s': %w", entry.Target, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(entry.Target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if entry.Template() {
+		rendered, err := Render(entry.Source, machine)
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(entry.Source); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(entry.Target, rendered, mode); err != nil {
+			return err
+		}
+		renders[entry.Target] = hash(rendered)
+		return nil
+	}
+
+	if err := os.Symlink(entry.Source, entry.Target); err != nil {
+		return fmt.Errorf("failed to link '%s': %w", entry.Target, err)
+	}
+	return nil
+}
+
+// backupPath returns the first path next to target with BackupSuffix, and a
+// number after the first, that doesn't exist yet, so earlier backups are kept.
+func backupPath(target string) string {
+	path := target + BackupSuffix
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s%s.%d", target, BackupSuffix, i)
+	}
+}
@@ -0,0 +1,173 @@
+package dotfiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setupRepo(t *testing.T) (string, string) {
+	t.Helper()
+	repo := t.TempDir()
+	home := t.TempDir()
+
+	mapping := `links:
+  bashrc: .bashrc
+  config/nvim: ~/.config/nvim
+  gitconfig.tmpl: .gitconfig
+variables:
+  email: me@example.com
+machines:
+  other-host:
+    email: me@work.example.com
+`
+	files := map[string]string{
+		MappingFile:            mapping,
+		"bashrc":               "export EDITOR=vim\n",
+		"config/nvim/init.lua": "vim.opt.number = true\n",
+		"gitconfig.tmpl":       "[user]\n\temail = {{ .Vars.email }}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return repo, home
+}
+
+func TestLinkAndCheck(t *testing.T) {
+	repo, home := setupRepo(t)
+
+	mapping, err := LoadMapping(repo)
+	if err != nil {
+		t.Fatalf("LoadMapping() error = %v", err)
+	}
+	machine := CurrentMachine(mapping, home)
+	entries, err := Entries(mapping, repo, home)
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 3 || entries[0].Target != filepath.Join(home, ".bashrc") {
+		t.Fatalf("Unexpected entries %+v", entries)
+	}
+
+	// Existing unrelated files conflict, also at the target of a template
+	os.WriteFile(filepath.Join(home, ".bashrc"), []byte("old\n"), 0644)
+	os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[user]\n\tname = me\n"), 0644)
+	renders := Renders{}
+
+	for _, entry := range entries {
+		state, err := Check(entry, machine, renders)
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		want := Missing
+		if entry.Target == filepath.Join(home, ".bashrc") || entry.Template() {
+			want = Conflict
+		}
+		if state != want {
+			t.Errorf("Check(%s) = %v, want %v", entry.Target, state, want)
+		}
+	}
+
+	for _, entry := range []Entry{entries[0], entries[2]} {
+		if err := Link(entry, machine, renders, false); err == nil {
+			t.Errorf("Expected error when linking over %s without force", entry.Target)
+		}
+	}
+	for _, entry := range entries {
+		if err := Link(entry, machine, renders, true); err != nil {
+			t.Fatalf("Link(%s) error = %v", entry.Target, err)
+		}
+		if state, _ := Check(entry, machine, renders); state != Linked {
+			t.Errorf("Check(%s) after Link = %v, want linked", entry.Target, state)
+		}
+	}
+
+	if backup, err := os.ReadFile(filepath.Join(home, ".bashrc"+BackupSuffix)); err != nil || string(backup) != "old\n" {
+		t.Errorf("Expected backup of the replaced file, got %q, %v", backup, err)
+	}
+	if backup, err := os.ReadFile(filepath.Join(home, ".gitconfig"+BackupSuffix)); err != nil || string(backup) != "[user]\n\tname = me\n" {
+		t.Errorf("Expected backup of the replaced template target, got %q, %v", backup, err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(home, ".config/nvim/init.lua")); string(content) != "vim.opt.number = true\n" {
+		t.Errorf("Expected linked directory, got %q", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(home, ".gitconfig")); string(content) != "[user]\n\temail = me@example.com\n" {
+		t.Errorf("Unexpected rendered template %q", content)
+	}
+
+	// Changing a variable makes the rendered template outdated
+	machine.Vars["email"] = "other@example.com"
+	if state, _ := Check(entries[2], machine, renders); state != Outdated {
+		t.Errorf("Check() after variable change = %v, want outdated", state)
+	}
+	if err := Link(entries[2], machine, renders, false); err != nil {
+		t.Errorf("Link() of an outdated template error = %v", err)
+	}
+
+	// A file edited since it was rendered conflicts again
+	os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("edited\n"), 0644)
+	if state, _ := Check(entries[2], machine, renders); state != Conflict {
+		t.Errorf("Check() after edit = %v, want conflict", state)
+	}
+}
+
+func TestLinkKeepsEarlierBackups(t *testing.T) {
+	repo, home := setupRepo(t)
+	entry := Entry{Source: filepath.Join(repo, "bashrc"), Target: filepath.Join(home, ".bashrc")}
+
+	for _, content := range []string{"first\n", "second\n"} {
+		os.Remove(entry.Target)
+		os.WriteFile(entry.Target, []byte(content), 0644)
+		if err := Link(entry, Machine{}, Renders{}, true); err != nil {
+			t.Fatalf("Link() error = %v", err)
+		}
+	}
+
+	for path, want := range map[string]string{entry.Target + BackupSuffix: "first\n", entry.Target + BackupSuffix + ".2": "second\n"} {
+		if content, err := os.ReadFile(path); err != nil || string(content) != want {
+			t.Errorf("Backup %s = %q, %v, want %q", path, content, err, want)
+		}
+	}
+}
+
+func TestRendersRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "rendered.json")
+	if renders := LoadRenders(path); len(renders) != 0 {
+		t.Errorf("LoadRenders() of a missing file = %v, want none", renders)
+	}
+
+	renders := Renders{"/home/me/.gitconfig": hash([]byte("content"))}
+	if err := renders.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if !LoadRenders(path).rendered("/home/me/.gitconfig", []byte("content")) {
+		t.Error("Expected the saved render to be remembered")
+	}
+}
+
+func TestCurrentMachineOverrides(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip("no hostname")
+	}
+	mapping := &Mapping{
+		Variables: map[string]string{"email": "me@example.com", "editor": "vim"},
+		Machines:  map[string]map[string]string{hostname: {"email": "me@work.example.com"}},
+	}
+
+	machine := CurrentMachine(mapping, "/home/me")
+	if machine.Vars["email"] != "me@work.example.com" || machine.Vars["editor"] != "vim" {
+		t.Errorf("Unexpected variables %v", machine.Vars)
+	}
+}
+
+func TestEntriesRejectsPathsOutsideRepository(t *testing.T) {
+	mapping := &Mapping{Links: map[string]string{"../secret": ".secret"}}
+	if _, err := Entries(mapping, "/repo", "/home/me"); err == nil {
+		t.Error("Expected error for a source outside the repository")
+	}
+}
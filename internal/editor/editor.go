@@ -0,0 +1,164 @@
+// Package editor picks the editor Aura opens files in: the --editor flag, the
+// editor setting, $VISUAL or $EDITOR, or else the first installed candidate.
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// DefaultCandidates are the editors looked for when none is configured, in
+// order of preference.
+var DefaultCandidates = []string{"code", "nvim", "vim", "subl", "notepad++"}
+
+// guiEditors open their own window, so Aura doesn't hand them the terminal.
+// The value is the flag making the editor block until the file is closed,
+// empty if it blocks anyway or has none.
+var guiEditors = map[string]string{
+	"code": "--wait", "code-insiders": "--wait", "codium": "--wait", "cursor": "--wait",
+	"subl": "--wait", "notepad++": "", "notepad": "", "gedit": "--wait",
+	"kate": "--block", "mate": "--wait", "zed": "--wait", "gvim": "--nofork", "idea": "--wait",
+}
+
+// blockingGUIEditors block until the file is closed without a flag.
+var blockingGUIEditors = map[string]bool{"notepad": true}
+
+// pollInterval is how often WaitForSave checks the file.
+var pollInterval = 500 * time.Millisecond
+
+// Resolve returns the editor command, which may include arguments, or "" if
+// no editor is configured or installed. A non-empty override wins.
+func Resolve(override string) string {
+	for _, editor := range []string{
+		override,
+		config.UserSettings.Editor,
+		os.Getenv("VISUAL"),
+		os.Getenv("EDITOR"),
+	} {
+		if editor = strings.TrimSpace(editor); editor != "" {
+			return editor
+		}
+	}
+
+	for _, candidate := range Candidates() {
+		if platform.Current().Installed(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Candidates returns the editors looked for when none is configured.
+func Candidates() []string {
+	if len(config.UserSettings.EditorCandidates) > 0 {
+		return config.UserSettings.EditorCandidates
+	}
+	return DefaultCandidates
+}
+
+// IsGUI reports whether the editor opens its own window instead of running
+// in the terminal.
+func IsGUI(editor string) bool {
+	_, ok := guiEditors[editorName(editor)]
+	return ok
+}
+
+// editorName returns the name of the editor's executable.
+func editorName(editor string) string {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(filepath.Base(fields[0]), ".exe"))
+}
+
+// WithWait returns the editor command with the flag making it block until
+// the file is closed, and whether it blocks. Terminal editors always block.
+func WithWait(editor string) (string, bool) {
+	name := editorName(editor)
+	flag, gui := guiEditors[name]
+	switch {
+	case !gui, blockingGUIEditors[name]:
+		return editor, true
+	case flag == "":
+		return editor, false
+	case slices.Contains(strings.Fields(editor)[1:], flag):
+		return editor, true
+	default:
+		return editor + " " + flag, true
+	}
+}
+
+// Open opens file in the editor. Terminal editors get the terminal and are
+// waited for. GUI editors are started in the background, unless wait is set:
+// then Open returns when the editor exits if it can block, or else when the
+// file is saved.
+func Open(editor, file string, wait bool) error {
+	if !IsGUI(editor) {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("%s needs a terminal", editor)
+		}
+		cmd := Command(editor, file)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if !wait {
+		return Command(editor, file).Start()
+	}
+
+	command, blocks := WithWait(editor)
+	if blocks {
+		return Command(command, file).Run()
+	}
+	return StartAndWaitForSave(Command(command, file), file)
+}
+
+// StartAndWaitForSave starts cmd, which opens file without blocking, and
+// waits until the file is saved.
+func StartAndWaitForSave(cmd *exec.Cmd, file string) error {
+	before := modTime(file)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+
+	fmt.Fprintf(os.Stderr, "Waiting for %s to be saved, press Ctrl+C to stop...\n", filepath.Base(file))
+	WaitForSave(file, before)
+	return nil
+}
+
+// WaitForSave blocks until the modification time of file differs from
+// before.
+func WaitForSave(file string, before time.Time) {
+	for modTime(file).Equal(before) {
+		time.Sleep(pollInterval)
+	}
+}
+
+// modTime returns the modification time of file, or the zero time.
+func modTime(file string) time.Time {
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Command returns the command opening files in the editor.
+func Command(editor string, files ...string) *exec.Cmd {
+	fields := strings.Fields(editor)
+	return exec.Command(fields[0], append(fields[1:], files...)...)
+}
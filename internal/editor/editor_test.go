@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+)
+
+func TestResolve(t *testing.T) {
+	originalSettings := config.UserSettings
+	defer func() { config.UserSettings = originalSettings }()
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "vi")
+
+	config.UserSettings.Editor = ""
+	if got := Resolve(""); got != "vi" {
+		t.Errorf("Resolve() = %q, want $EDITOR", got)
+	}
+
+	config.UserSettings.Editor = "nvim"
+	if got := Resolve(""); got != "nvim" {
+		t.Errorf("Resolve() = %q, want configured editor", got)
+	}
+	if got := Resolve("subl -w"); got != "subl -w" {
+		t.Errorf("Resolve() = %q, want override", got)
+	}
+
+	t.Setenv("EDITOR", "")
+	config.UserSettings.Editor = ""
+	config.UserSettings.EditorCandidates = []string{"surely-not-an-editor-12345"}
+	if got := Resolve(""); got != "" {
+		t.Errorf("Resolve() = %q, want no editor", got)
+	}
+}
+
+func TestIsGUI(t *testing.T) {
+	for editor, want := range map[string]bool{
+		"code --wait":         true,
+		"notepad++.exe":       true,
+		"/usr/local/bin/subl": true,
+		"nvim":                false,
+		"vim -u NONE":         false,
+		"":                    false,
+	} {
+		if got := IsGUI(editor); got != want {
+			t.Errorf("IsGUI(%q) = %v, want %v", editor, got, want)
+		}
+	}
+}
+
+func TestWithWait(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   string
+		blocks bool
+	}{
+		{"code", "code --wait", true},
+		{"code --wait", "code --wait", true},
+		{"subl -n", "subl -n --wait", true},
+		{"nvim", "nvim", true},
+		{"notepad", "notepad", true},
+		{"notepad++", "notepad++", false},
+	}
+
+	for _, tt := range tests {
+		got, blocks := WithWait(tt.editor)
+		if got != tt.want || blocks != tt.blocks {
+			t.Errorf("WithWait(%q) = %q, %v, want %q, %v", tt.editor, got, blocks, tt.want, tt.blocks)
+		}
+	}
+}
+
+func TestWaitForSave(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	file := filepath.Join(t.TempDir(), "notes.md")
+	os.WriteFile(file, []byte("draft"), 0644)
+	before := modTime(file)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		os.Chtimes(file, before.Add(time.Second), before.Add(time.Second))
+	}()
+
+	done := make(chan bool)
+	go func() {
+		WaitForSave(file, before)
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForSave() did not return after the file was saved")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	cmd := Command("code --wait", "a.txt")
+	if !reflect.DeepEqual(cmd.Args, []string{"code", "--wait", "a.txt"}) {
+		t.Errorf("Command() args = %v", cmd.Args)
+	}
+}
@@ -0,0 +1,212 @@
+// Package envvar is the registry of the environment variables Aura reads.
+// Code reads them through their Var, so every variable is documented in one
+// place and listed by aura config env.
+package envvar
+
+import (
+	"os"
+	"sort"
+)
+
+// Var is an environment variable read by Aura.
+type Var struct {
+	// Name is the name of the variable, such as AURA_MODEL.
+	Name string
+	// Subsystem is the part of Aura that reads the variable.
+	Subsystem string
+	// Default describes what is used when the variable is not set.
+	Default string
+	// Description says what the variable does.
+	Description string
+	// Secret hides the value, such as that of API keys.
+	Secret bool
+}
+
+// Get returns the value of the variable, "" if it is not set.
+func (v Var) Get() string {
+	return os.Getenv(v.Name)
+}
+
+// registry holds the variables in the order they are declared.
+var registry []Var
+
+// register adds v to the registry and returns it.
+func register(v Var) Var {
+	registry = append(registry, v)
+	return v
+}
+
+// All returns the registered variables, sorted by name.
+func All() []Var {
+	vars := append([]Var(nil), registry...)
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// Variables read by the AI assistant.
+var (
+	APIKey = register(Var{
+		Name:        "AURA_API_KEY",
+		Subsystem:   "ai",
+		Default:     "OPENAI_API_KEY",
+		Description: "API key of the AI provider",
+		Secret:      true,
+	})
+	OpenAIAPIKey = register(Var{
+		Name:        "OPENAI_API_KEY",
+		Subsystem:   "ai",
+		Default:     "none, AI features are off",
+		Description: "API key used when AURA_API_KEY is not set",
+		Secret:      true,
+	})
+	APIURL = register(Var{
+		Name:        "AURA_API_URL",
+		Subsystem:   "ai",
+		Default:     "https://api.openai.com/v1",
+		Description: "Base URL of an OpenAI-compatible API, such as a local Ollama server",
+	})
+	Model = register(Var{
+		Name:        "AURA_MODEL",
+		Subsystem:   "ai",
+		Default:     "ai.model, else gpt-3.5-turbo",
+		Description: "Model of the AI assistant",
+	})
+	FallbackModels = register(Var{
+		Name:        "AURA_FALLBACK_MODELS",
+		Subsystem:   "ai",
+		Default:     "ai.fallback",
+		Description: "Comma-separated models of the same provider tried when the model is unavailable",
+	})
+	EmbeddingModel = register(Var{
+		Name:        "AURA_EMBEDDING_MODEL",
+		Subsystem:   "ai",
+		Default:     "ai.embeddings.model, else text-embedding-3-small",
+		Description: "Model of the embeddings used by semantic search",
+	})
+	Language = register(Var{
+		Name:        "AURA_LANGUAGE",
+		Subsystem:   "ai",
+		Default:     "ai.language, else the language of the question",
+		Description: "Language of answers, such as de or Japanese",
+	})
+	CommitLanguage = register(Var{
+		Name:        "AURA_COMMIT_LANGUAGE",
+		Subsystem:   "ai",
+		Default:     "ai.commit_language, else English",
+		Description: "Language of generated commit messages",
+	})
+	Deterministic = register(Var{
+		Name:        "AURA_DETERMINISTIC",
+		Subsystem:   "ai",
+		Default:     "ai.deterministic, else off",
+		Description: "1 asks for reproducible answers, set by --deterministic",
+	})
+	AICassette = register(Var{
+		Name:        "AURA_AI_CASSETTE",
+		Subsystem:   "ai",
+		Default:     "none, the API is called",
+		Description: "File of recorded AI exchanges replayed instead of calling the API, for tests",
+	})
+	AIRecord = register(Var{
+		Name:        "AURA_AI_RECORD",
+		Subsystem:   "ai",
+		Default:     "off",
+		Description: "1 records the AI exchanges into AURA_AI_CASSETTE",
+	})
+)
+
+// Variables read by the configuration.
+var (
+	Env = register(Var{
+		Name:        "AURA_ENV",
+		Subsystem:   "config",
+		Default:     "production",
+		Description: "Environment, development turns on debug logging",
+	})
+	DBPath = register(Var{
+		Name:        "AURA_DB_PATH",
+		Subsystem:   "database",
+		Default:     "the aura-db container if running, else aura.db in the data directory",
+		Description: "Database file; its directory then holds config.yaml and all other files",
+	})
+	LogLevel = register(Var{
+		Name:        "AURA_LOG_LEVEL",
+		Subsystem:   "logging",
+		Default:     "debug in development, else info",
+		Description: "Level of log messages",
+	})
+	LogFile = register(Var{
+		Name:        "AURA_LOG_FILE",
+		Subsystem:   "logging",
+		Default:     "aura.log in the state directory",
+		Description: "File log messages are written to",
+	})
+	ProjectRoots = register(Var{
+		Name:        "AURA_PROJECT_ROOTS",
+		Subsystem:   "navigation",
+		Default:     "navigation.project_roots, else ~/code, ~/projects and others",
+		Description: "Directories indexed by aura go, separated like PATH",
+	})
+)
+
+// Variables restricting what Aura does.
+var (
+	Offline = register(Var{
+		Name:        "AURA_OFFLINE",
+		Subsystem:   "network",
+		Default:     "off",
+		Description: "1 turns the AI assistant and all other network requests off, set by --offline",
+	})
+	ReadOnly = register(Var{
+		Name:        "AURA_READ_ONLY",
+		Subsystem:   "database",
+		Default:     "off",
+		Description: "1 opens the database read-only, set by --read-only",
+	})
+	DryRun = register(Var{
+		Name:        "AURA_DRY_RUN",
+		Subsystem:   "database",
+		Default:     "off",
+		Description: "1 shows what commands would change instead of changing it, set by --dry-run",
+	})
+)
+
+// Variables read by the shell integration and the terminal UI.
+var (
+	Shell = register(Var{
+		Name:        "AURA_SHELL",
+		Subsystem:   "shell",
+		Default:     "the base name of $SHELL",
+		Description: "Shell commands are generated for, set by the shell integration",
+	})
+	EvalFile = register(Var{
+		Name:        "AURA_EVAL_FILE",
+		Subsystem:   "shell",
+		Default:     "none, commands are printed",
+		Description: "File of commands the shell integration runs in the shell, such as cd",
+	})
+	Session = register(Var{
+		Name:        "AURA_SESSION",
+		Subsystem:   "marks",
+		Default:     "the parent process ID",
+		Description: "Shell session quick marks belong to, set by the shell integration",
+	})
+	Finder = register(Var{
+		Name:        "AURA_FINDER",
+		Subsystem:   "finder",
+		Default:     "fzf if installed",
+		Description: "builtin uses the built-in fuzzy finder instead of fzf",
+	})
+	Pager = register(Var{
+		Name:        "AURA_PAGER",
+		Subsystem:   "pager",
+		Default:     "$PAGER, or less if installed",
+		Description: "Pager long output is shown in, such as \"less -S\"; cat turns it off, as --no-pager does",
+	})
+	ErrorFormat = register(Var{
+		Name:        "AURA_ERROR_FORMAT",
+		Subsystem:   "errors",
+		Default:     "text",
+		Description: "Format of errors on stderr, text or json, overridden by --error-format",
+	})
+)
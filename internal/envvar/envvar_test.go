@@ -0,0 +1,57 @@
+package envvar
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	vars := All()
+	if !sort.SliceIsSorted(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name }) {
+		t.Error("All() is not sorted by name")
+	}
+	seen := make(map[string]bool)
+	for _, v := range vars {
+		if seen[v.Name] {
+			t.Errorf("%s is registered twice", v.Name)
+		}
+		seen[v.Name] = true
+		if v.Subsystem == "" || v.Default == "" || v.Description == "" {
+			t.Errorf("%s is not documented: %+v", v.Name, v)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Setenv("AURA_MODEL", "gpt-4o")
+	if got := Model.Get(); got != "gpt-4o" {
+		t.Errorf("Model.Get() = %q, want gpt-4o", got)
+	}
+}
+
+// TestRegistryIsUsed checks that variables are read through the registry, so
+// none is missing from aura config env.
+func TestRegistryIsUsed(t *testing.T) {
+	literal := regexp.MustCompile(`Getenv\("AURA_`)
+	err := filepath.WalkDir("..", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if literal.Match(content) {
+			t.Errorf("%s reads an AURA_ variable by name instead of through envvar", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
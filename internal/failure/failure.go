@@ -0,0 +1,112 @@
+// Package failure classifies errors into kinds, such as configuration or
+// network errors, each with its own exit code, so scripts can branch on why
+// Aura failed.
+package failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+)
+
+// Kind is the category of an error.
+type Kind string
+
+// Kinds of errors.
+const (
+	// General is any error without a more specific kind.
+	General   Kind = "error"
+	UserInput Kind = "user-input"
+	Config    Kind = "config"
+	Network   Kind = "network"
+	API       Kind = "api"
+	NotFound  Kind = "not-found"
+)
+
+// exitCodes maps kinds to the exit codes of the aura process.
+var exitCodes = map[Kind]int{
+	General:   1,
+	UserInput: 2,
+	Config:    3,
+	Network:   4,
+	API:       5,
+	NotFound:  6,
+}
+
+// Error is an error of a kind.
+type Error struct {
+	kind Kind
+	err  error
+}
+
+// New returns an error of kind with a message formatted like fmt.Errorf.
+func New(kind Kind, format string, args ...any) error {
+	return &Error{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// Wrap returns err as an error of kind, or nil if err is nil.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{kind: kind, err: err}
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Kind returns the kind of the error.
+func (e *Error) Kind() Kind {
+	return e.kind
+}
+
+// kinded is implemented by errors that know their kind, such as Error.
+type kinded interface {
+	Kind() Kind
+}
+
+// KindOf returns the kind of err: the kind of the first error in its chain
+// that has one, otherwise a kind guessed from well-known errors such as
+// failed connections, or General.
+func KindOf(err error) Kind {
+	var known kinded
+	if errors.As(err, &known) {
+		return known.Kind()
+	}
+
+	// Check files first, errno values look like net.Error too
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return NotFound
+	case errors.As(err, &opErr), errors.As(err, &dnsErr), errors.As(err, &urlErr), errors.Is(err, context.DeadlineExceeded):
+		return Network
+	}
+	return General
+}
+
+// ExitCode returns the exit code of err, 0 if it is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return KindExitCode(KindOf(err))
+}
+
+// KindExitCode returns the exit code of errors of kind.
+func KindExitCode(kind Kind) int {
+	if code, ok := exitCodes[kind]; ok {
+		return code
+	}
+	return exitCodes[General]
+}
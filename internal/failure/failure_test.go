@@ -0,0 +1,47 @@
+package failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	_, statErr := os.Stat("/does/not/exist")
+
+	tests := []struct {
+		name string
+		err  error
+		kind Kind
+		code int
+	}{
+		{"plain", errors.New("boom"), General, 1},
+		{"kinded", New(UserInput, "invalid name '%s'", "a b"), UserInput, 2},
+		{"wrapped", fmt.Errorf("failed to load: %w", Wrap(Config, errors.New("bad yaml"))), Config, 3},
+		{"connection", fmt.Errorf("failed to make request: %w", &net.OpError{Op: "dial", Err: errors.New("refused")}), Network, 4},
+		{"timeout", fmt.Errorf("failed to make request: %w", context.DeadlineExceeded), Network, 4},
+		{"missing file", fmt.Errorf("failed to read: %w", statErr), NotFound, 6},
+		{"explicit over guessed", Wrap(API, fmt.Errorf("x: %w", statErr)), API, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if kind := KindOf(tt.err); kind != tt.kind {
+				t.Errorf("KindOf() = %s, want %s", kind, tt.kind)
+			}
+			if code := ExitCode(tt.err); code != tt.code {
+				t.Errorf("ExitCode() = %d, want %d", code, tt.code)
+			}
+		})
+	}
+
+	if code := ExitCode(nil); code != 0 {
+		t.Errorf("ExitCode(nil) = %d, want 0", code)
+	}
+	if Wrap(Config, nil) != nil {
+		t.Error("Expected Wrap(nil) to be nil")
+	}
+}
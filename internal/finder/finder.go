@@ -0,0 +1,224 @@
+// Package finder lets the user pick one of many candidates by typing a fuzzy
+// query, using fzf when it is installed and a built-in finder otherwise.
+package finder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/manifoldco/promptui"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+// Item is a candidate shown in the finder.
+type Item struct {
+	// Label is displayed and matched against the query.
+	Label string
+	// Value is passed to the preview, e.g. a path or a command.
+	Value string
+}
+
+// Options configures a finder session.
+type Options struct {
+	// Prompt is shown in front of the query.
+	Prompt string
+	// Query is the initial query.
+	Query string
+	// Preview renders the preview pane of the built-in finder.
+	Preview func(Item) string
+	// FzfPreview is the shell command fzf runs to render the preview pane,
+	// with {} replaced by the quoted item value.
+	FzfPreview string
+}
+
+// ErrCancelled is returned when the user closes the finder without a selection.
+var ErrCancelled = errors.New("selection cancelled")
+
+// lookPath finds external programs; replaced in tests.
+var lookPath = exec.LookPath
+
+// Select shows the items and returns the index of the selected one.
+func Select(items []Item, opts Options) (int, error) {
+	if len(items) == 0 {
+		return -1, fmt.Errorf("nothing to select")
+	}
+
+	if path, err := lookPath("fzf"); err == nil && envvar.Finder.Get() != "builtin" {
+		return selectFzf(path, items, opts)
+	}
+	return selectBuiltin(items, opts)
+}
+
+// selectFzf streams the items to fzf. Each line holds the index, the label
+// and the value separated by tabs, of which only the label is displayed.
+func selectFzf(path string, items []Item, opts Options) (int, error) {
+	args := []string{
+		"--delimiter", "\t",
+		"--with-nth", "2",
+		"--height", "40%",
+		"--reverse",
+		"--prompt", opts.Prompt + "> ",
+		"--query", opts.Query,
+	}
+	if opts.FzfPreview != "" {
+		args = append(args, "--preview", strings.ReplaceAll(opts.FzfPreview, "{}", "{3}"), "--preview-window", "right:50%")
+	}
+
+	fzf := exec.Command(path, args...)
+	fzf.Stderr = os.Stderr
+
+	stdin, err := fzf.StdinPipe()
+	if err != nil {
+		return -1, err
+	}
+	var output strings.Builder
+	fzf.Stdout = &output
+
+	if err := fzf.Start(); err != nil {
+		return -1, fmt.Errorf("failed to start fzf: %w", err)
+	}
+	go func() {
+		defer stdin.Close()
+		for i, item := range items {
+			fmt.Fprintf(stdin, "%d\t%s\t%s\n", i, sanitize(item.Label), sanitize(item.Value))
+		}
+	}()
+
+	if err := fzf.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		// fzf exits with 1 without a match and 130 when interrupted
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
+			return -1, ErrCancelled
+		}
+		return -1, fmt.Errorf("fzf failed: %w", err)
+	}
+
+	index, _, _ := strings.Cut(strings.TrimSpace(output.String()), "\t")
+	selected, err := strconv.Atoi(index)
+	if err != nil || selected < 0 || selected >= len(items) {
+		return -1, fmt.Errorf("unexpected fzf output %q", output.String())
+	}
+	return selected, nil
+}
+
+// selectBuiltin shows the items in a searchable prompt on stderr, so the
+// selection can be printed to stdout for the shell wrapper.
+func selectBuiltin(items []Item, opts Options) (int, error) {
+	// Apply the initial query up front, the prompt can't be pre-filled
+	var candidates []int
+	for i, item := range items {
+		if Match(opts.Query, item.Label) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return -1, fmt.Errorf("no matches for '%s'", opts.Query)
+	}
+
+	funcs := template.FuncMap{}
+	for name, fn := range promptui.FuncMap {
+		funcs[name] = fn
+	}
+	funcs["preview"] = func(item Item) string {
+		if opts.Preview == nil {
+			return ""
+		}
+		return opts.Preview(item)
+	}
+
+	visible := make([]Item, len(candidates))
+	for i, index := range candidates {
+		visible[i] = items[index]
+	}
+
+	templates := theme.SelectTemplates(".Label")
+	templates.Details = "{{ preview . }}"
+	templates.FuncMap = funcs
+
+	prompt := promptui.Select{
+		Label: opts.Prompt,
+		Items: visible,
+		Size:  10,
+		Searcher: func(input string, index int) bool {
+			return Match(input, visible[index].Label)
+		},
+		StartInSearchMode: true,
+		Stdout:            nopCloser{os.Stderr},
+		Templates:         templates,
+	}
+
+	selected, _, err := prompt.Run()
+	if err != nil {
+		if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+			return -1, ErrCancelled
+		}
+		return -1, fmt.Errorf("prompt failed: %w", err)
+	}
+	return candidates[selected], nil
+}
+
+// Match reports whether all space separated terms of query occur in text as
+// case-insensitive subsequences, like fzf's default matching.
+func Match(query, text string) bool {
+	text = strings.ToLower(text)
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if !matchSubsequence(term, text) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchSubsequence(term, text string) bool {
+	remaining := []rune(term)
+	for _, r := range text {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// DirectoryPreview lists up to limit entries of the directory at path, with
+// a trailing slash after directories.
+func DirectoryPreview(path string, limit int) string {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fmt.Sprintf("%s\n  (not readable)", path)
+	}
+
+	lines := []string{path}
+	for i, entry := range entries {
+		if i == limit {
+			lines = append(lines, fmt.Sprintf("  ... %d more", len(entries)-limit))
+			break
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			name += string(filepath.Separator)
+		}
+		lines = append(lines, "  "+name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sanitize removes characters that would break the line format used with fzf.
+func sanitize(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(s)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,90 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		want  bool
+	}{
+		{"", "anything", true},
+		{"prj", "my-project", true},
+		{"PRJ", "my-project", true},
+		{"jrp", "my-project", false},
+		{"api src", "/home/me/src/billing-api", true},
+		{"api docs", "/home/me/src/billing-api", false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.query, tt.text); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.query, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDirectoryPreview(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	preview := DirectoryPreview(dir, 2)
+	lines := strings.Split(preview, "\n")
+	if lines[0] != dir || len(lines) != 4 {
+		t.Fatalf("Unexpected preview:\n%s", preview)
+	}
+	if lines[3] != "  ... 2 more" {
+		t.Errorf("Expected truncation note, got %q", lines[3])
+	}
+
+	if preview := DirectoryPreview(dir, 10); !strings.Contains(preview, "src"+string(filepath.Separator)) {
+		t.Errorf("Expected directories to end with a separator:\n%s", preview)
+	}
+}
+
+func TestSelectFzf(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake fzf is a shell script")
+	}
+
+	// The fake fzf picks the second candidate
+	dir := t.TempDir()
+	fake := filepath.Join(dir, "fzf")
+	if err := os.WriteFile(fake, []byte("#!/bin/sh\nsed -n 2p\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake fzf: %v", err)
+	}
+
+	originalLookPath := lookPath
+	defer func() { lookPath = originalLookPath }()
+	lookPath = func(string) (string, error) { return fake, nil }
+	t.Setenv("AURA_FINDER", "")
+
+	items := []Item{{Label: "notes", Value: "/notes"}, {Label: "work\tlabel", Value: "/work"}}
+	selected, err := Select(items, Options{Prompt: "Go to", FzfPreview: "ls {}"})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if selected != 1 {
+		t.Errorf("Select() = %d, want 1", selected)
+	}
+
+	// fzf exits with 130 when cancelled
+	if err := os.WriteFile(fake, []byte("#!/bin/sh\nexit 130\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake fzf: %v", err)
+	}
+	if _, err := Select(items, Options{}); err != ErrCancelled {
+		t.Errorf("Expected ErrCancelled, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+// Package forge fetches the open issues and pull requests of a repository
+// from the forge hosting it, GitHub or GitLab, authenticated with a token
+// from the environment.
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// Kind is the software of a forge.
+type Kind string
+
+// Kinds of forges.
+const (
+	GitHub Kind = "github"
+	GitLab Kind = "gitlab"
+)
+
+// tokenVariables are the environment variables holding the token of each
+// kind of forge, in order of precedence.
+var tokenVariables = map[Kind][]string{
+	GitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	GitLab: {"GITLAB_TOKEN"},
+}
+
+// listLimit is the number of open issues and pull requests fetched.
+const listLimit = 20
+
+// Repo is a repository on a forge.
+type Repo struct {
+	Kind Kind
+	Host string
+	// Path is the owner and name of the repository, such as
+	// timfewi/aura-cli-go, with all groups on GitLab.
+	Path string
+}
+
+// Issue is an issue, pull request or merge request.
+type Issue struct {
+	Number int
+	Title  string
+	Body   string
+	URL    string
+	// PullRequest marks pull requests and merge requests.
+	PullRequest bool
+}
+
+// Reference returns the reference of the issue, such as #42, or !42 for
+// merge requests on GitLab.
+func (r Repo) Reference(issue Issue) string {
+	if issue.PullRequest && r.Kind == GitLab {
+		return "!" + strconv.Itoa(issue.Number)
+	}
+	return "#" + strconv.Itoa(issue.Number)
+}
+
+// CheckoutCommand returns the command checking out the pull request number
+// in a local branch, fetched from remote.
+func (r Repo) CheckoutCommand(remote string, number int) string {
+	if r.Kind == GitLab {
+		return fmt.Sprintf("git fetch %s merge-requests/%d/head:mr-%d && git checkout mr-%d", remote, number, number, number)
+	}
+	return fmt.Sprintf("git fetch %s pull/%d/head:pr-%d && git checkout pr-%d", remote, number, number, number)
+}
+
+// apiURL returns the base URL of the forge's REST API.
+func (r Repo) apiURL() string {
+	switch {
+	case r.Kind == GitLab:
+		return "https://" + r.Host + "/api/v4"
+	case r.Host == "github.com":
+		return "https://api.github.com"
+	}
+	// GitHub Enterprise Server
+	return "https://" + r.Host + "/api/v3"
+}
+
+// ParseRemote parses the URL of a Git remote, such as
+// git@github.com:owner/name.git or https://gitlab.com/group/sub/name, and
+// reports whether it is on a known forge. hosts maps the hosts of
+// self-hosted forges to their kind.
+func ParseRemote(remote string, hosts map[string]string) (Repo, bool) {
+	remote = strings.TrimSpace(remote)
+	var host, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return Repo{}, false
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		address, rest, ok := strings.Cut(remote, ":")
+		if !ok {
+			return Repo{}, false
+		}
+		if _, h, found := strings.Cut(address, "@"); found {
+			address = h
+		}
+		host, path = address, rest
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || strings.Count(path, "/") < 1 {
+		return Repo{}, false
+	}
+
+	kind, ok := hostKind(strings.ToLower(host), hosts)
+	if !ok {
+		return Repo{}, false
+	}
+	return Repo{Kind: kind, Host: strings.ToLower(host), Path: path}, true
+}
+
+// hostKind returns the kind of the forge at host.
+func hostKind(host string, hosts map[string]string) (Kind, bool) {
+	if kind, ok := hosts[host]; ok {
+		switch Kind(strings.ToLower(kind)) {
+		case GitHub:
+			return GitHub, true
+		case GitLab:
+			return GitLab, true
+		}
+		return "", false
+	}
+	switch {
+	case host == "github.com" || strings.HasPrefix(host, "github."):
+		return GitHub, true
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return GitLab, true
+	}
+	return "", false
+}
+
+// Origin returns the repository the origin remote of the Git repository in
+// the current directory points to, if it is on a known forge.
+func Origin(hosts map[string]string) (Repo, bool) {
+	output, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return Repo{}, false
+	}
+	return ParseRemote(string(output), hosts)
+}
+
+// Token returns the token for forges of kind from the environment, or ""
+// if none is set.
+func Token(kind Kind) string {
+	for _, name := range tokenVariables[kind] {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// Client talks to the API of the forge hosting a repository.
+type Client struct {
+	repo    Repo
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient returns a client for repo with the token from the environment.
+func NewClient(repo Repo) (*Client, error) {
+	token := Token(repo.Kind)
+	if token == "" {
+		return nil, failure.New(failure.Config, "no %s token found, set %s", repo.Kind, strings.Join(tokenVariables[repo.Kind], " or "))
+	}
+	return &Client{
+		repo:    repo,
+		token:   token,
+		baseURL: repo.apiURL(),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Repo returns the repository of the client.
+func (c *Client) Repo() Repo {
+	return c.repo
+}
+
+// Open returns the open issues and pull requests of the repository, most
+// recently updated first.
+func (c *Client) Open(ctx context.Context) ([]Issue, error) {
+	if c.repo.Kind == GitLab {
+		issues, err := c.gitlabList(ctx, "issues", false)
+		if err != nil {
+			return nil, err
+		}
+		requests, err := c.gitlabList(ctx, "merge_requests", true)
+		if err != nil {
+			return nil, err
+		}
+		return append(requests, issues...), nil
+	}
+
+	var items []githubIssue
+	query := fmt.Sprintf("/repos/%s/issues?state=open&sort=updated&per_page=%d", c.repo.Path, listLimit)
+	if err := c.get(ctx, query, &items); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, len(items))
+	for i, item := range items {
+		issues[i] = item.issue()
+	}
+	return issues, nil
+}
+
+// Issue returns the issue or pull request number.
+func (c *Client) Issue(ctx context.Context, number int) (Issue, error) {
+	if c.repo.Kind == GitLab {
+		var item gitlabIssue
+		if err := c.get(ctx, fmt.Sprintf("%s/issues/%d", c.gitlabProject(), number), &item); err != nil {
+			return Issue{}, err
+		}
+		return item.issue(false), nil
+	}
+
+	var item githubIssue
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/issues/%d", c.repo.Path, number), &item); err != nil {
+		return Issue{}, err
+	}
+	return item.issue(), nil
+}
+
+// githubIssue is an issue or pull request in the GitHub API.
+type githubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	HTMLURL     string          `json:"html_url"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+func (i githubIssue) issue() Issue {
+	return Issue{Number: i.Number, Title: i.Title, Body: i.Body, URL: i.HTMLURL, PullRequest: i.PullRequest != nil}
+}
+
+// gitlabIssue is an issue or merge request in the GitLab API.
+type gitlabIssue struct {
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	WebURL      string `json:"web_url"`
+}
+
+func (i gitlabIssue) issue(pullRequest bool) Issue {
+	return Issue{Number: i.IID, Title: i.Title, Body: i.Description, URL: i.WebURL, PullRequest: pullRequest}
+}
+
+// gitlabProject returns the API path of the repository on GitLab.
+func (c *Client) gitlabProject() string {
+	return "/projects/" + url.PathEscape(c.repo.Path)
+}
+
+// gitlabList returns the open issues or merge requests on GitLab.
+func (c *Client) gitlabList(ctx context.Context, kind string, pullRequest bool) ([]Issue, error) {
+	var items []gitlabIssue
+	query := fmt.Sprintf("%s/%s?state=opened&order_by=updated_at&per_page=%d", c.gitlabProject(), kind, listLimit)
+	if err := c.get(ctx, query, &items); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, len(items))
+	for i, item := range items {
+		issues[i] = item.issue(pullRequest)
+	}
+	return issues, nil
+}
+
+// get requests path from the API and decodes the JSON answer into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.repo.Kind == GitLab {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", c.repo.Host, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return failure.New(failure.NotFound, "%s not found on %s", c.repo.Path, c.repo.Host)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return failure.New(failure.Config, "%s rejected the token: %s", c.repo.Host, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return failure.New(failure.API, "%s API request failed: %s", c.repo.Host, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return failure.Wrap(failure.API, fmt.Errorf("failed to decode %s response: %w", c.repo.Host, err))
+	}
+	return nil
+}
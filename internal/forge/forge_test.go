@@ -0,0 +1,137 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestParseRemote(t *testing.T) {
+	hosts := map[string]string{"git.example.com": "gitlab"}
+	tests := []struct {
+		remote string
+		ok     bool
+		kind   Kind
+		host   string
+		path   string
+	}{
+		{"git@github.com:timfewi/aura-cli-go.git", true, GitHub, "github.com", "timfewi/aura-cli-go"},
+		{"https://github.com/timfewi/aura-cli-go\n", true, GitHub, "github.com", "timfewi/aura-cli-go"},
+		{"ssh://git@gitlab.com:22/group/sub/project.git", true, GitLab, "gitlab.com", "group/sub/project"},
+		{"https://GitHub.acme.io/team/app.git", true, GitHub, "github.acme.io", "team/app"},
+		{"git@git.example.com:ops/infra.git", true, GitLab, "git.example.com", "ops/infra"},
+		{"https://bitbucket.org/team/app.git", false, "", "", ""},
+		{"https://github.com/timfewi", false, "", "", ""},
+		{"/srv/git/app.git", false, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		repo, ok := ParseRemote(tt.remote, hosts)
+		if ok != tt.ok {
+			t.Errorf("ParseRemote(%q) ok = %v, want %v", tt.remote, ok, tt.ok)
+			continue
+		}
+		if ok && (repo.Kind != tt.kind || repo.Host != tt.host || repo.Path != tt.path) {
+			t.Errorf("ParseRemote(%q) = %+v", tt.remote, repo)
+		}
+	}
+}
+
+func TestCheckoutCommand(t *testing.T) {
+	github := Repo{Kind: GitHub, Host: "github.com", Path: "a/b"}
+	if got, want := github.CheckoutCommand("origin", 42), "git fetch origin pull/42/head:pr-42 && git checkout pr-42"; got != want {
+		t.Errorf("CheckoutCommand() = %q, want %q", got, want)
+	}
+	gitlab := Repo{Kind: GitLab, Host: "gitlab.com", Path: "a/b"}
+	if got, want := gitlab.CheckoutCommand("origin", 7), "git fetch origin merge-requests/7/head:mr-7 && git checkout mr-7"; got != want {
+		t.Errorf("CheckoutCommand() = %q, want %q", got, want)
+	}
+	if got := gitlab.Reference(Issue{Number: 7, PullRequest: true}); got != "!7" {
+		t.Errorf("Reference() = %q, want !7", got)
+	}
+}
+
+func TestClientOpenGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/a/b/issues" || r.URL.Query().Get("state") != "open" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`[
+			{"number": 42, "title": "Add open", "html_url": "https://github.com/a/b/pull/42", "pull_request": {"url": "x"}},
+			{"number": 101, "title": "Crash", "body": "It crashes", "html_url": "https://github.com/a/b/issues/101"}
+		]`))
+	}))
+	defer server.Close()
+
+	client := &Client{repo: Repo{Kind: GitHub, Host: "github.com", Path: "a/b"}, token: "secret", baseURL: server.URL, client: server.Client()}
+	issues, err := client.Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if len(issues) != 2 || !issues[0].PullRequest || issues[1].PullRequest || issues[1].Body != "It crashes" {
+		t.Errorf("Open() = %+v", issues)
+	}
+}
+
+func TestClientGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			t.Errorf("PRIVATE-TOKEN = %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		switch r.URL.EscapedPath() {
+		case "/projects/group%2Fapp/issues":
+			w.Write([]byte(`[{"iid": 3, "title": "Bug", "web_url": "https://gitlab.com/group/app/-/issues/3"}]`))
+		case "/projects/group%2Fapp/merge_requests":
+			w.Write([]byte(`[{"iid": 5, "title": "Fix bug", "web_url": "https://gitlab.com/group/app/-/merge_requests/5"}]`))
+		case "/projects/group%2Fapp/issues/3":
+			w.Write([]byte(`{"iid": 3, "title": "Bug", "description": "Steps"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{repo: Repo{Kind: GitLab, Host: "gitlab.com", Path: "group/app"}, token: "secret", baseURL: server.URL, client: server.Client()}
+	issues, err := client.Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if len(issues) != 2 || issues[0].Number != 5 || !issues[0].PullRequest || issues[1].Number != 3 {
+		t.Errorf("Open() = %+v", issues)
+	}
+
+	issue, err := client.Issue(context.Background(), 3)
+	if err != nil || issue.Body != "Steps" {
+		t.Errorf("Issue(3) = %+v, %v", issue, err)
+	}
+
+	_, err = client.Issue(context.Background(), 9)
+	var kinded *failure.Error
+	if !errors.As(err, &kinded) || kinded.Kind() != failure.NotFound {
+		t.Errorf("Issue(9) error = %v, want a not-found error", err)
+	}
+}
+
+func TestNewClientWithoutToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	if _, err := NewClient(Repo{Kind: GitHub, Host: "github.com", Path: "a/b"}); failure.KindOf(err) != failure.Config {
+		t.Errorf("NewClient() error = %v, want a config error", err)
+	}
+
+	t.Setenv("GH_TOKEN", "token")
+	client, err := NewClient(Repo{Kind: GitHub, Host: "github.acme.io", Path: "a/b"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.baseURL != "https://github.acme.io/api/v3" {
+		t.Errorf("baseURL = %q", client.baseURL)
+	}
+}
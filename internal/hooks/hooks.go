@@ -0,0 +1,128 @@
+// Package hooks installs Aura commands as Git hooks.
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Hook is an Aura command that can be installed as a Git hook.
+type Hook struct {
+	// Name identifies the hook on the command line.
+	Name string
+	// GitHook is the Git hook the command runs in, e.g. "pre-commit".
+	GitHook string
+	// Description explains what the hook does.
+	Description string
+	// Command is the shell command executed by the hook.
+	Command string
+}
+
+// Available lists all hooks that can be installed.
+var Available = []Hook{
+	{
+		Name:        "secrets",
+		GitHook:     "pre-commit",
+		Description: "Block commits that add likely secrets",
+		Command:     "aura secrets scan --staged",
+	},
+}
+
+// marker identifies hook scripts written by Aura.
+const marker = "# Installed by aura:"
+
+// Find returns the available hook with the given name.
+func Find(name string) (Hook, error) {
+	for _, hook := range Available {
+		if hook.Name == name {
+			return hook, nil
+		}
+	}
+	return Hook{}, fmt.Errorf("unknown hook '%s'", name)
+}
+
+// Dir returns the hooks directory of the Git repository in the current
+// directory, honoring core.hooksPath and worktrees.
+func Dir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository")
+	}
+	return filepath.Abs(strings.TrimSpace(string(output)))
+}
+
+// Script returns the shell script installed for the hook.
+func (h Hook) Script() string {
+	return fmt.Sprintf("#!/bin/sh\n%s %s\n# %s\nexec %s\n", marker, h.Name, h.Description, h.Command)
+}
+
+// Installed reports whether the hook is installed in dir.
+func (h Hook) Installed(dir string) bool {
+	content, err := os.ReadFile(filepath.Join(dir, h.GitHook))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), marker+" "+h.Name+"\n")
+}
+
+// Changes describes the files Install would write into dir, failing as it
+// would.
+func (h Hook) Changes(dir string, force bool) ([]string, error) {
+	path := filepath.Join(dir, h.GitHook)
+	content, err := os.ReadFile(path)
+	switch {
+	case err != nil:
+		return []string{"create " + path}, nil
+	case strings.Contains(string(content), marker):
+		return []string{"replace " + path + ", installed by Aura"}, nil
+	case !force:
+		return nil, fmt.Errorf("a %s hook already exists, use --force to replace it", h.GitHook)
+	}
+	return []string{"back up " + path + " to " + path + ".bak", "replace " + path}, nil
+}
+
+// Install writes the hook script into dir. An existing hook that was not
+// installed by Aura is only replaced when force is set, after backing it up.
+func (h Hook) Install(dir string, force bool) error {
+	path := filepath.Join(dir, h.GitHook)
+
+	if _, err := h.Changes(dir, force); err != nil {
+		return err
+	}
+	if content, err := os.ReadFile(path); err == nil && !strings.Contains(string(content), marker) {
+		if err := os.WriteFile(path+".bak", content, 0755); err != nil {
+			return fmt.Errorf("failed to back up existing hook: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(h.Script()), 0755); err != nil {
+		return fmt.Errorf("failed to write hook: %w", err)
+	}
+	return nil
+}
+
+// Uninstall removes the hook from dir if it was installed by Aura.
+func (h Hook) Uninstall(dir string) error {
+	if !h.Installed(dir) {
+		return fmt.Errorf("hook '%s' is not installed", h.Name)
+	}
+
+	path := filepath.Join(dir, h.GitHook)
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove hook: %w", err)
+	}
+
+	// Restore a hook that was replaced during installation
+	if _, err := os.Stat(path + ".bak"); err == nil {
+		if err := os.Rename(path+".bak", path); err != nil {
+			return fmt.Errorf("failed to restore previous hook: %w", err)
+		}
+	}
+	return nil
+}
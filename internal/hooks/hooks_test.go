@@ -0,0 +1,121 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	hook, err := Find("secrets")
+	if err != nil {
+		t.Fatalf("Find(secrets) error = %v", err)
+	}
+	if hook.GitHook != "pre-commit" {
+		t.Errorf("Expected pre-commit hook, got %s", hook.GitHook)
+	}
+
+	if _, err := Find("nonexistent"); err == nil {
+		t.Error("Expected error for unknown hook")
+	}
+}
+
+func TestInstallAndUninstall(t *testing.T) {
+	dir, err := os.MkdirTemp("", "aura_hooks_test_*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	hook, _ := Find("secrets")
+
+	if hook.Installed(dir) {
+		t.Error("Hook should not be installed initially")
+	}
+
+	if err := hook.Install(dir, false); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !hook.Installed(dir) {
+		t.Error("Hook should be installed")
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "pre-commit"))
+	if err != nil {
+		t.Fatalf("Failed to read hook: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "#!/bin/sh") || !strings.Contains(string(content), hook.Command) {
+		t.Errorf("Unexpected hook script: %s", content)
+	}
+
+	// Reinstalling an Aura hook is allowed without force
+	if err := hook.Install(dir, false); err != nil {
+		t.Errorf("Reinstall error = %v", err)
+	}
+
+	if err := hook.Uninstall(dir); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pre-commit")); !os.IsNotExist(err) {
+		t.Error("Hook file should be removed")
+	}
+}
+
+func TestInstallKeepsForeignHook(t *testing.T) {
+	dir, err := os.MkdirTemp("", "aura_hooks_test_*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pre-commit")
+	foreign := "#!/bin/sh\nnpm test\n"
+	if err := os.WriteFile(path, []byte(foreign), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	hook, _ := Find("secrets")
+
+	if err := hook.Install(dir, false); err == nil {
+		t.Error("Expected error when replacing a foreign hook without force")
+	}
+
+	if err := hook.Install(dir, true); err != nil {
+		t.Fatalf("Install(force) error = %v", err)
+	}
+
+	if err := hook.Uninstall(dir); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected previous hook to be restored: %v", err)
+	}
+	if string(content) != foreign {
+		t.Errorf("Restored hook = %q, want %q", content, foreign)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pre-commit")
+	hook, _ := Find("secrets")
+
+	if changes, err := hook.Changes(dir, false); err != nil || len(changes) != 1 || changes[0] != "create "+path {
+		t.Errorf("Changes() = %v, %v, want the hook created", changes, err)
+	}
+
+	os.WriteFile(path, []byte("#!/bin/sh\nnpm test\n"), 0755)
+	if _, err := hook.Changes(dir, false); err == nil {
+		t.Error("Changes() would replace a foreign hook without force")
+	}
+	changes, err := hook.Changes(dir, true)
+	if err != nil || len(changes) != 2 || !strings.HasPrefix(changes[0], "back up ") {
+		t.Errorf("Changes() = %v, %v, want the foreign hook backed up", changes, err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "#!/bin/sh\nnpm test\n" {
+		t.Error("Changes() changed the hook")
+	}
+}
@@ -0,0 +1,274 @@
+// Package httpreq builds HTTP requests from HTTPie style arguments, such as
+// "POST api.example.com/users name=ada X-Auth:token", and formats their
+// responses.
+package httpreq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// Request is a request as typed, before variables are substituted.
+type Request struct {
+	Method string
+	URL    string
+	// Items are the headers, query parameters and fields, such as
+	// "X-Auth:token", "page==2", "name=ada" or "admin:=true".
+	Items []string
+}
+
+// methods are the methods recognized as the first argument.
+var methods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// Parse parses the arguments of a request: an optional method, the URL and
+// items. Without a method requests with fields are POST, others GET.
+func Parse(args []string) (Request, error) {
+	var request Request
+	if len(args) > 0 && methods[strings.ToUpper(args[0])] && len(args) > 1 {
+		request.Method = strings.ToUpper(args[0])
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return Request{}, failure.New(failure.UserInput, "no URL given")
+	}
+	request.URL = args[0]
+	request.Items = args[1:]
+
+	for _, item := range request.Items {
+		if _, _, _, err := splitItem(item); err != nil {
+			return Request{}, err
+		}
+	}
+	if request.Method == "" {
+		request.Method = http.MethodGet
+		if request.hasFields() {
+			request.Method = http.MethodPost
+		}
+	}
+	return request, nil
+}
+
+// Item separators, longest first so that ":=" wins over ":" at the same
+// position.
+const (
+	rawField = ":="
+	param    = "=="
+	field    = "="
+	header   = ":"
+)
+
+// splitItem splits an item at its first separator.
+func splitItem(item string) (key, separator, value string, err error) {
+	for i := 0; i < len(item); i++ {
+		for _, candidate := range []string{rawField, param, field, header} {
+			if i > 0 && strings.HasPrefix(item[i:], candidate) {
+				return item[:i], candidate, item[i+len(candidate):], nil
+			}
+		}
+	}
+	return "", "", "", failure.New(failure.UserInput, "invalid item '%s', use Header:value, param==value, field=value or field:=json", item)
+}
+
+func (r Request) hasFields() bool {
+	for _, item := range r.Items {
+		if _, separator, _, _ := splitItem(item); separator == field || separator == rawField {
+			return true
+		}
+	}
+	return false
+}
+
+// variable matches the {{NAME}} placeholders of variables.
+var variable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Substitute replaces the {{NAME}} placeholders of text with the values of
+// lookup. Unknown variables are an error.
+func Substitute(text string, lookup func(string) (string, bool)) (string, error) {
+	var missing []string
+	result := variable.ReplaceAllStringFunc(text, func(match string) string {
+		name := variable.FindStringSubmatch(match)[1]
+		value, ok := lookup(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", failure.New(failure.UserInput, "variable %s is not set", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// Build builds the HTTP request of r with its variables substituted from
+// lookup. Fields are sent as a JSON object.
+func (r Request) Build(ctx context.Context, lookup func(string) (string, bool)) (*http.Request, error) {
+	rawURL, err := Substitute(r.URL, lookup)
+	if err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(normalizeURL(rawURL))
+	if err != nil || target.Host == "" {
+		return nil, failure.New(failure.UserInput, "invalid URL '%s'", rawURL)
+	}
+
+	headers := http.Header{}
+	query := target.Query()
+	fields := map[string]any{}
+	var order []string
+	for _, item := range r.Items {
+		item, err := Substitute(item, lookup)
+		if err != nil {
+			return nil, err
+		}
+		key, separator, value, err := splitItem(item)
+		if err != nil {
+			return nil, err
+		}
+		switch separator {
+		case header:
+			headers.Add(key, strings.TrimSpace(value))
+		case param:
+			query.Add(key, value)
+		case field, rawField:
+			var fieldValue any = value
+			if separator == rawField {
+				if err := json.Unmarshal([]byte(value), &fieldValue); err != nil {
+					return nil, failure.New(failure.UserInput, "invalid JSON in '%s': %v", item, err)
+				}
+			}
+			if _, ok := fields[key]; !ok {
+				order = append(order, key)
+			}
+			fields[key] = fieldValue
+		}
+	}
+	target.RawQuery = query.Encode()
+
+	var body io.Reader
+	if len(fields) > 0 {
+		body = bytes.NewReader(encodeFields(order, fields))
+		if headers.Get("Content-Type") == "" {
+			headers.Set("Content-Type", "application/json")
+		}
+	}
+	request, err := http.NewRequestWithContext(ctx, r.Method, target.String(), body)
+	if err != nil {
+		return nil, failure.New(failure.UserInput, "invalid request: %v", err)
+	}
+	if headers.Get("Accept") == "" {
+		headers.Set("Accept", "application/json, */*;q=0.5")
+	}
+	if headers.Get("User-Agent") == "" {
+		headers.Set("User-Agent", "aura-cli")
+	}
+	request.Header = headers
+	return request, nil
+}
+
+// encodeFields encodes fields as a JSON object with the keys in order.
+func encodeFields(order []string, fields map[string]any) []byte {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, key := range order {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		name, _ := json.Marshal(key)
+		value, _ := json.Marshal(fields[key])
+		fmt.Fprintf(&b, "%s: %s", name, value)
+	}
+	b.WriteString("}")
+	return b.Bytes()
+}
+
+// Curl returns the curl command sending r in a POSIX shell. Placeholders
+// become shell variables, so secrets don't show up in the command.
+func (r Request) Curl() string {
+	target := r.URL
+	if !strings.HasPrefix(target, "{{") {
+		target = normalizeURL(target)
+	}
+	words := []string{"curl"}
+	if r.Method != http.MethodGet && !(r.Method == http.MethodPost && r.hasFields()) {
+		words = append(words, "-X", r.Method)
+	}
+	words = append(words, shellWord(target))
+
+	var fields []string
+	for _, item := range r.Items {
+		key, separator, value, _ := splitItem(item)
+		switch separator {
+		case header:
+			words = append(words, "-H", shellWord(key+": "+strings.TrimSpace(value)))
+		case param:
+			words = append(words, "--url-query", shellWord(key+"="+value))
+		case field:
+			name, _ := json.Marshal(key)
+			text, _ := json.Marshal(value)
+			fields = append(fields, fmt.Sprintf("%s: %s", name, text))
+		case rawField:
+			name, _ := json.Marshal(key)
+			fields = append(fields, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	if len(fields) > 0 {
+		words = append(words, "--json", shellWord("{"+strings.Join(fields, ", ")+"}"))
+	}
+	return strings.Join(words, " ")
+}
+
+// shellWord quotes s for a POSIX shell: in single quotes, or in double
+// quotes with its placeholders turned into variables.
+func shellWord(s string) string {
+	if !variable.MatchString(s) {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+	return `"` + variable.ReplaceAllString(escaped, "$${$1}") + `"`
+}
+
+// normalizeURL adds the scheme to URLs without one: http for localhost and
+// ":3000/path", which is short for localhost, https for other hosts.
+func normalizeURL(rawURL string) string {
+	switch {
+	case strings.Contains(rawURL, "://"):
+		return rawURL
+	case strings.HasPrefix(rawURL, ":"):
+		return "http://localhost" + rawURL
+	}
+	host := rawURL
+	if end := strings.IndexAny(host, "/?#"); end >= 0 {
+		host = host[:end]
+	}
+	if hostname := strings.Split(host, ":")[0]; hostname == "localhost" || strings.HasPrefix(hostname, "127.") {
+		return "http://" + rawURL
+	}
+	return "https://" + rawURL
+}
+
+// Pretty formats a response body for reading: JSON indented, anything else
+// as it is.
+func Pretty(contentType string, body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	looksJSON := bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("["))
+	var indented bytes.Buffer
+	if (strings.Contains(contentType, "json") || looksJSON) && json.Indent(&indented, trimmed, "", "  ") == nil {
+		return indented.String() + "\n"
+	}
+	if len(body) > 0 && !bytes.HasSuffix(body, []byte("\n")) {
+		return string(body) + "\n"
+	}
+	return string(body)
+}
@@ -0,0 +1,163 @@
+package httpreq
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantMethod string
+		wantURL    string
+		wantErr    bool
+	}{
+		{name: "get", args: []string{"api.example.com/users", "X-Auth:token"}, wantMethod: "GET", wantURL: "api.example.com/users"},
+		{name: "fields mean post", args: []string{":3000/users", "name=ada"}, wantMethod: "POST", wantURL: ":3000/users"},
+		{name: "params stay get", args: []string{":3000/users", "page==2"}, wantMethod: "GET", wantURL: ":3000/users"},
+		{name: "explicit method", args: []string{"delete", ":3000/users/1"}, wantMethod: "DELETE", wantURL: ":3000/users/1"},
+		{name: "method alone is a URL", args: []string{"head"}, wantMethod: "GET", wantURL: "head"},
+		{name: "invalid item", args: []string{":3000", "oops"}, wantErr: true},
+		{name: "no URL", args: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if failure.KindOf(err) != failure.UserInput {
+					t.Errorf("Parse() error kind = %v, want user input", failure.KindOf(err))
+				}
+				return
+			}
+			if got.Method != tt.wantMethod || got.URL != tt.wantURL {
+				t.Errorf("Parse() = %s %s, want %s %s", got.Method, got.URL, tt.wantMethod, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestSplitItem(t *testing.T) {
+	tests := []struct {
+		item, key, separator, value string
+	}{
+		{"X-Auth:token", "X-Auth", ":", "token"},
+		{"Authorization:Bearer a:b", "Authorization", ":", "Bearer a:b"},
+		{"page==2", "page", "==", "2"},
+		{"name=ada", "name", "=", "ada"},
+		{"query=a==b", "query", "=", "a==b"},
+		{"admin:=true", "admin", ":=", "true"},
+		{"tags:=[1,2]", "tags", ":=", "[1,2]"},
+	}
+	for _, tt := range tests {
+		key, separator, value, err := splitItem(tt.item)
+		if err != nil || key != tt.key || separator != tt.separator || value != tt.value {
+			t.Errorf("splitItem(%q) = %q, %q, %q, %v, want %q, %q, %q", tt.item, key, separator, value, err, tt.key, tt.separator, tt.value)
+		}
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		value, ok := map[string]string{"API": "https://api.example.com", "EMPTY": ""}[name]
+		return value, ok
+	}
+
+	got, err := Substitute("{{API}}/users?x={{ EMPTY }}", lookup)
+	if err != nil || got != "https://api.example.com/users?x=" {
+		t.Errorf("Substitute() = %q, %v", got, err)
+	}
+	if _, err := Substitute("{{TOKEN}} {{SECRET}}", lookup); err == nil || err.Error() != "variable TOKEN, SECRET is not set" {
+		t.Errorf("Substitute() of unknown variables error = %v", err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	request, err := Parse([]string{"{{HOST}}/users?sort=name", "X-Auth:{{TOKEN}}", "page==2", "name=ada", "admin:=true", "age:=36"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := map[string]string{"HOST": "localhost:3000", "TOKEN": "secret"}[name]
+		return value, ok
+	}
+
+	req, err := request.Build(context.Background(), lookup)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if req.Method != "POST" || req.URL.String() != "http://localhost:3000/users?page=2&sort=name" {
+		t.Errorf("Build() = %s %s", req.Method, req.URL)
+	}
+	if req.Header.Get("X-Auth") != "secret" || req.Header.Get("Content-Type") != "application/json" || req.Header.Get("User-Agent") != "aura-cli" {
+		t.Errorf("Build() headers = %v", req.Header)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if want := `{"name": "ada", "admin": true, "age": 36}`; string(body) != want {
+		t.Errorf("Build() body = %s, want %s", body, want)
+	}
+
+	invalid, _ := Parse([]string{":3000", "tags:=[1,"})
+	if _, err := invalid.Build(context.Background(), lookup); err == nil {
+		t.Error("Build() with invalid JSON succeeded")
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := map[string]string{
+		":3000/users":           "http://localhost:3000/users",
+		"localhost:8080":        "http://localhost:8080",
+		"127.0.0.1/health":      "http://127.0.0.1/health",
+		"api.example.com/users": "https://api.example.com/users",
+		"http://example.com":    "http://example.com",
+	}
+	for rawURL, want := range tests {
+		if got := normalizeURL(rawURL); got != want {
+			t.Errorf("normalizeURL(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+func TestPretty(t *testing.T) {
+	tests := []struct {
+		contentType, body, want string
+	}{
+		{"application/json", `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}\n"},
+		{"text/plain", `[1]`, "[\n  1\n]\n"},
+		{"application/json", `{broken`, "{broken\n"},
+		{"text/html", "<p>hi</p>\n", "<p>hi</p>\n"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := Pretty(tt.contentType, []byte(tt.body)); got != tt.want {
+			t.Errorf("Pretty(%q, %q) = %q, want %q", tt.contentType, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestCurl(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"api.example.com/users", "page==2"}, `curl 'https://api.example.com/users' --url-query 'page=2'`},
+		{[]string{":3000/users", "name=O'Brien", "admin:=true"}, `curl 'http://localhost:3000/users' --json '{"name": "O'\''Brien", "admin": true}'`},
+		{[]string{"DELETE", "{{API}}/users/7", "Authorization:Bearer {{TOKEN}}"}, `curl -X DELETE "${API}/users/7" -H "Authorization: Bearer ${TOKEN}"`},
+		{[]string{"PUT", ":3000/price", "note=costs $5 {{CURRENCY}}"}, `curl -X PUT 'http://localhost:3000/price' --json "{\"note\": \"costs \$5 ${CURRENCY}\"}"`},
+	}
+	for _, tt := range tests {
+		request, err := Parse(tt.args)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.args, err)
+		}
+		if got := request.Curl(); got != tt.want {
+			t.Errorf("Curl() of %q =\n%s\nwant\n%s", tt.args, got, tt.want)
+		}
+	}
+}
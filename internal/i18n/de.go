@@ -0,0 +1,118 @@
+package i18n
+
+// german translates the messages and the short descriptions of the commands,
+// keyed "cmd.<command path>.short".
+var german = Catalog{
+	"help.usage":           "Verwendung:",
+	"help.aliases":         "Aliasse:",
+	"help.examples":        "Beispiele:",
+	"help.commands":        "Verfügbare Befehle:",
+	"help.flags":           "Optionen:",
+	"help.global_flags":    "Globale Optionen:",
+	"help.topics":          "Weitere Hilfethemen:",
+	"help.more":            "Mit \"%s [Befehl] --help\" gibt es mehr Informationen zu einem Befehl.",
+	"error.prefix":         "Fehler:",
+	"confirm.needs_yes":    "Zum Bestätigen erneut mit --yes ausführen.",
+	"ai.thinking":          "Denke nach",
+	"ai.done":              "Der KI-Assistent ist fertig",
+	"ai.fallback_answered": "Antwort vom Ersatzmodell '%s'",
+
+	"bookmark.add_intent":     "Lesezeichen '%s' hinzufügen für: %s",
+	"bookmark.exists":         "Lesezeichen '%s' existiert bereits und zeigt auf: %s",
+	"bookmark.updating":       "Es zeigt jetzt auf: %s",
+	"bookmark.added":          "Lesezeichen '%s' hinzugefügt für: %s",
+	"bookmark.none":           "Keine Lesezeichen gefunden. Neues Lesezeichen mit: aura bookmark add <alias> <pfad>",
+	"bookmark.saved":          "Gespeicherte Lesezeichen:",
+	"bookmark.no_match":       "Keine Lesezeichen passen zu '%s'",
+	"bookmark.to_remove":      "Zu entfernende Lesezeichen:",
+	"bookmark.confirm_remove": "%d Lesezeichen entfernen",
+	"bookmark.removed":        "Lesezeichen '%s' entfernt",
+	"bookmark.meta_set":       "%s von Lesezeichen '%s' gesetzt auf: %s",
+	"bookmark.meta_removed":   "%s von Lesezeichen '%s' entfernt",
+	"bookmark.usage":          "📊 Nutzung der Lesezeichen:",
+	"bookmark.never_used":     "nie",
+	"bookmark.no_stale":       "✓ Alle Lesezeichen wurden in den letzten %d Monaten benutzt",
+	"bookmark.stale":          "💡 %d Lesezeichen seit %d Monaten unbenutzt: %s",
+	"bookmark.stale_hint":     "   Entfernen mit: aura bookmark stats --clean",
+	"bookmark.clean":          "Unbenutzte Lesezeichen aufräumen",
+	"bookmark.clean_all":      "Alle %d entfernen",
+	"bookmark.clean_choose":   "Auswählen, welche entfernt werden",
+	"bookmark.clean_keep":     "Behalten",
+	"bookmark.clean_one":      "'%s' entfernen (%s, zuletzt benutzt %s)",
+
+	"undo.nothing":        "Nichts rückgängig zu machen",
+	"undo.list":           "Änderungen, die rückgängig gemacht werden können, neueste zuerst:",
+	"undo.done":           "✓ Rückgängig gemacht: %s",
+	"undo.restored":       "%s wiederhergestellt",
+	"undo.gone":           "ℹ️  %s existiert nicht mehr",
+	"undo.confirm_file":   "%s löschen",
+	"undo.confirm_folder": "%s mit allen Dateien löschen",
+
+	"cmd.aura.short":            "Aura - Intelligenter CLI-Assistent",
+	"cmd.aura alias.short":      "Shell-Aliasse verwalten",
+	"cmd.aura analyze.short":    "Das aktuelle Projekt analysieren",
+	"cmd.aura api.short":        "Die API des Projekts anhand ihrer OpenAPI-Spezifikation aufrufen",
+	"cmd.aura ask.short":        "Den KI-Assistenten um Hilfe bitten",
+	"cmd.aura bookmark.short":   "Verzeichnis-Lesezeichen verwalten",
+	"cmd.aura cheat.short":      "Spickzettel für Kommandozeilenwerkzeuge anzeigen",
+	"cmd.aura checksum.short":   "Prüfsumme einer Datei berechnen oder prüfen",
+	"cmd.aura completion.short": "Skript für die Autovervollständigung einer Shell erzeugen",
+	"cmd.aura config.short":     "Einstellungen lesen und ändern",
+	"cmd.aura cron.short":       "Cron-Ausdrücke aus einer Beschreibung schreiben",
+	"cmd.aura data.short":       "Mit Datendateien arbeiten",
+	"cmd.aura db.short":         "Mit Projektdatenbanken arbeiten",
+	"cmd.aura deps.short":       "Die Abhängigkeiten des Projekts prüfen",
+	"cmd.aura do.short":         "Passende Aktionen für das aktuelle Projekt vorschlagen",
+	"cmd.aura dotenv.short":     ".env-Dateien abgleichen und aus der Versionskontrolle heraushalten",
+	"cmd.aura dotfiles.short":   "Ein Dotfiles-Repository ins Home-Verzeichnis verlinken",
+	"cmd.aura each.short":       "Einen Befehl in mehreren Lesezeichen ausführen",
+	"cmd.aura exec.short":       "Einen Befehl ausführen und Fehler mit KI analysieren",
+	"cmd.aura gen.short":        "Code mit dem KI-Assistenten erzeugen",
+	"cmd.aura git.short":        "Git-Operationen mit KI-Unterstützung",
+	"cmd.aura go.short":         "Zu Verzeichnissen mit Lesezeichen wechseln",
+	"cmd.aura help.short":       "Hilfe zu einem Befehl",
+	"cmd.aura hooks.short":      "Aura-Prüfungen als Git-Hooks installieren",
+	"cmd.aura http.short":       "HTTP-Anfragen senden und die Antworten lesbar ausgeben",
+	"cmd.aura index.short":      "Den Verzeichnisindex von 'aura go' verwalten",
+	"cmd.aura info.short":       "Das Projekt im aktuellen Verzeichnis zusammenfassen",
+	"cmd.aura mark.short":       "Verzeichnisse für diese Shell-Sitzung markieren",
+	"cmd.aura models.short":     "Die Modelle des KI-Anbieters auflisten",
+	"cmd.aura new.short":        "Eine neue Datei anlegen und im Editor öffnen",
+	"cmd.aura note.short":       "Notizen zu einem Projekt oder global festhalten",
+	"cmd.aura open.short":       "Ein Lesezeichen, eine Datei oder eine URL öffnen",
+	"cmd.aura pack.short":       "Ein Verzeichnis in ein Archiv packen",
+	"cmd.aura pad.short":        "Weitergeleiteten Text in benannten Notizpuffern aufbewahren",
+	"cmd.aura pomodoro.short":   "Pomodoro-Arbeits- und Pausentimer mit Benachrichtigungen starten",
+	"cmd.aura ports.short":      "Belegte Ports und die Prozesse dahinter auflisten",
+	"cmd.aura project.short":    "Ein Projekt aus einer Vorlage erstellen",
+	"cmd.aura query.short":      "Fragen zu JSON- oder YAML-Daten aus einer Pipe beantworten",
+	"cmd.aura recall.short":     "Antworten früherer 'aura ask'-Gespräche finden",
+	"cmd.aura receive.short":    "Eine mit aura send gesendete Datei empfangen",
+	"cmd.aura refactor.short":   "Eine Datei mit dem KI-Assistenten umbauen",
+	"cmd.aura regex.short":      "Einen regulären Ausdruck aus einer Beschreibung bauen",
+	"cmd.aura remind.short":     "Nach einer Weile oder zu einer Uhrzeit per Benachrichtigung erinnern",
+	"cmd.aura repos.short":      "Überblick über die Git-Repositories der Lesezeichen",
+	"cmd.aura rm.short":         "Dateien in den Papierkorb verschieben statt sie zu löschen",
+	"cmd.aura schedule.short":   "Befehle nach Zeitplan ausführen",
+	"cmd.aura search.short":     "Notizen, Snippets, Chats und Lesezeichen auf einmal durchsuchen",
+	"cmd.aura secrets.short":    "Geheimnisse finden, bevor sie committet werden",
+	"cmd.aura send.short":       "Eine Datei an einen anderen Rechner im Netzwerk senden",
+	"cmd.aura session.short":    "Arbeitskontexte speichern und wiederherstellen",
+	"cmd.aura sql.short":        "Eine SQL-Konsole auf der Aura-Datenbank oder einer SQLite-Datei öffnen",
+	"cmd.aura standup.short":    "Die eigene Arbeit der letzten Zeit für das Standup zusammenfassen",
+	"cmd.aura sys.short":        "CPU-, Speicher-, Festplatten- und Akkunutzung und die aktivsten Prozesse anzeigen",
+	"cmd.aura time.short":       "Die für Projekte aufgewendete Zeit schätzen",
+	"cmd.aura tldr.short":       "Kurze Anwendungsbeispiele eines Befehls anzeigen",
+	"cmd.aura tmux.short":       "Eine tmux-Sitzung für ein Lesezeichen öffnen",
+	"cmd.aura todo.short":       "TODO-, FIXME- und HACK-Kommentare verfolgen",
+	"cmd.aura trash.short":      "Mit 'aura rm' gelöschte Dateien verwalten",
+	"cmd.aura undo.short":       "Die letzte Änderung von Aura rückgängig machen",
+	"cmd.aura uninstall.short":  "Aura CLI und alle zugehörigen Dateien entfernen",
+	"cmd.aura unpack.short":     "Ein Archiv ansehen und sicher entpacken",
+	"cmd.aura watch.short":      "Einen Befehl bei Dateiänderungen erneut ausführen",
+
+	"cmd.aura bookmark add.short":    "Ein Lesezeichen hinzufügen",
+	"cmd.aura bookmark list.short":   "Alle Lesezeichen auflisten",
+	"cmd.aura bookmark remove.short": "Ein Lesezeichen entfernen",
+	"cmd.aura bookmark stats.short":  "Anzeigen, wie oft Lesezeichen benutzt werden",
+}
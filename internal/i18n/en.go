@@ -0,0 +1,51 @@
+package i18n
+
+// english is the catalog every other catalog falls back to. Keys group
+// messages by command.
+var english = Catalog{
+	// Help output of all commands
+	"help.usage":           "Usage:",
+	"help.aliases":         "Aliases:",
+	"help.examples":        "Examples:",
+	"help.commands":        "Available Commands:",
+	"help.flags":           "Flags:",
+	"help.global_flags":    "Global Flags:",
+	"help.topics":          "Additional help topics:",
+	"help.more":            "Use \"%s [command] --help\" for more information about a command.",
+	"error.prefix":         "Error:",
+	"confirm.needs_yes":    "Run again with --yes to confirm.",
+	"ai.thinking":          "Thinking",
+	"ai.done":              "The AI assistant is done",
+	"ai.fallback_answered": "Answered by fallback model '%s'",
+
+	"bookmark.add_intent":     "Add bookmark '%s' for: %s",
+	"bookmark.exists":         "Bookmark '%s' already exists, pointing to: %s",
+	"bookmark.updating":       "Updating to point to: %s",
+	"bookmark.added":          "Bookmark '%s' added for: %s",
+	"bookmark.none":           "No bookmarks found. Add one with: aura bookmark add <alias> <path>",
+	"bookmark.saved":          "Saved bookmarks:",
+	"bookmark.no_match":       "No bookmarks match '%s'",
+	"bookmark.to_remove":      "Bookmarks to remove:",
+	"bookmark.confirm_remove": "Remove %d bookmarks",
+	"bookmark.removed":        "Bookmark '%s' removed",
+	"bookmark.meta_set":       "Set %s of bookmark '%s' to: %s",
+	"bookmark.meta_removed":   "Removed %s of bookmark '%s'",
+	"bookmark.usage":          "📊 Bookmark usage:",
+	"bookmark.never_used":     "never",
+	"bookmark.no_stale":       "✓ All bookmarks were used in the last %d months",
+	"bookmark.stale":          "💡 %d bookmarks unused for %d months: %s",
+	"bookmark.stale_hint":     "   Remove them with: aura bookmark stats --clean",
+	"bookmark.clean":          "Clean up unused bookmarks",
+	"bookmark.clean_all":      "Remove all %d",
+	"bookmark.clean_choose":   "Choose which to remove",
+	"bookmark.clean_keep":     "Keep them",
+	"bookmark.clean_one":      "Remove '%s' (%s, last used %s)",
+
+	"undo.nothing":        "Nothing to undo",
+	"undo.list":           "Changes that can be undone, most recent first:",
+	"undo.done":           "✓ Undone: %s",
+	"undo.restored":       "Restored %s",
+	"undo.gone":           "ℹ️  %s no longer exists",
+	"undo.confirm_file":   "Delete %s",
+	"undo.confirm_folder": "Delete %s and all its files",
+}
@@ -0,0 +1,93 @@
+// Package i18n translates Aura's messages. Messages are looked up by key in
+// the catalog of the current locale, falling back to English and then to the
+// key itself, and formatted like fmt.Sprintf.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Catalog maps message keys to translated format strings.
+type Catalog map[string]string
+
+// DefaultLocale is the locale messages fall back to.
+const DefaultLocale = "en"
+
+// Catalogs holds the catalogs of the shipped locales.
+var Catalogs = map[string]Catalog{
+	"en": english,
+	"de": german,
+}
+
+var current = DefaultLocale
+
+// Locales returns the shipped locales, sorted.
+func Locales() []string {
+	locales := make([]string, 0, len(Catalogs))
+	for locale := range Catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Detect returns the locale to use: the configured one if set, otherwise the
+// first of LC_ALL, LC_MESSAGES and LANG that is set. Locales without a
+// catalog yield DefaultLocale.
+func Detect(configured string) string {
+	value := configured
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value != "" {
+			break
+		}
+		value = os.Getenv(name)
+	}
+	return Normalize(value)
+}
+
+// Normalize reduces a locale such as "de_DE.UTF-8" to its language, "de",
+// or DefaultLocale if there is no catalog for it.
+func Normalize(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "_-.@"); i >= 0 {
+		language = language[:i]
+	}
+	if _, ok := Catalogs[language]; !ok {
+		return DefaultLocale
+	}
+	return language
+}
+
+// SetLocale sets the locale of messages.
+func SetLocale(locale string) {
+	current = Normalize(locale)
+}
+
+// Locale returns the locale of messages.
+func Locale() string {
+	return current
+}
+
+// T returns the message of key in the current locale, formatted with args.
+func T(key string, args ...any) string {
+	format, ok := Catalogs[current][key]
+	if !ok {
+		if format, ok = english[key]; !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Lookup returns the message of key in the current locale only, without
+// falling back to English.
+func Lookup(key string) (string, bool) {
+	message, ok := Catalogs[current][key]
+	return message, ok
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for locale, want := range map[string]string{
+		"de":          "de",
+		"de_DE.UTF-8": "de",
+		"de-AT":       "de",
+		"DE_ch":       "de",
+		"en_US.UTF-8": "en",
+		"C":           "en",
+		"POSIX":       "en",
+		"fr_FR":       "en",
+		"":            "en",
+	} {
+		if got := Normalize(locale); got != want {
+			t.Errorf("Normalize(%q) = %s, want %s", locale, got, want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+
+	if got := Detect(""); got != "de" {
+		t.Errorf("Detect() from LANG = %s, want de", got)
+	}
+	if got := Detect("en"); got != "en" {
+		t.Errorf("Detect() with configured locale = %s, want en", got)
+	}
+
+	t.Setenv("LC_ALL", "C")
+	if got := Detect(""); got != "en" {
+		t.Errorf("Detect() with LC_ALL=C = %s, want en", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	SetLocale("de_DE")
+	if got := T("bookmark.removed", "notes"); got != "Lesezeichen 'notes' entfernt" {
+		t.Errorf("T() = %q", got)
+	}
+
+	english["test.only_english"] = "only %s"
+	defer delete(english, "test.only_english")
+	if got := T("test.only_english", "english"); got != "only english" {
+		t.Errorf("T() without translation = %q, want the English message", got)
+	}
+
+	if got := T("missing.key"); got != "missing.key" {
+		t.Errorf("T() of unknown key = %q, want the key", got)
+	}
+}
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for locale, catalog := range Catalogs {
+		for key, message := range catalog {
+			if strings.HasPrefix(key, "cmd.") {
+				continue
+			}
+			original, ok := english[key]
+			if !ok {
+				t.Errorf("%s: key %s is not in the English catalog", locale, key)
+				continue
+			}
+			if strings.Count(message, "%") != strings.Count(original, "%") {
+				t.Errorf("%s: %s has other arguments than %q: %q", locale, key, original, message)
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package index
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is a single pattern of a .gitignore file.
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreFile holds the rules of a .gitignore file. Patterns are relative to
+// the directory containing the file.
+type ignoreFile struct {
+	dir   string
+	rules []ignoreRule
+}
+
+// readIgnoreFile parses the .gitignore file in dir. It returns nil when there
+// is none.
+func readIgnoreFile(dir string) *ignoreFile {
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	ignore := &ignoreFile{dir: dir}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			ignore.rules = append(ignore.rules, rule)
+		}
+	}
+	return ignore
+}
+
+// parseIgnoreRule parses a line of a .gitignore file.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	// A slash anywhere but at the end anchors the pattern to the directory
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.pattern = line
+	return rule, true
+}
+
+// match reports whether the rules decide about rel, a slash separated path
+// relative to the directory of the file. The last matching rule wins.
+func (f *ignoreFile) match(rel string, isDir bool) (ignored bool, matched bool) {
+	for _, rule := range f.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		var ok bool
+		if rule.anchored {
+			ok = matchGlob(rule.pattern, rel)
+		} else {
+			ok = matchGlob(rule.pattern, path.Base(rel))
+		}
+		if ok {
+			ignored, matched = !rule.negate, true
+		}
+	}
+	return ignored, matched
+}
+
+// matchGlob matches a slash separated name against pattern. Besides the
+// syntax of path.Match, a "**" segment matches any number of segments.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// IgnoreRules are the rules of a .gitignore file, for packages that walk
+// directories themselves.
+type IgnoreRules struct {
+	file *ignoreFile
+}
+
+// ReadIgnoreRules parses the .gitignore file in dir. It returns nil when
+// there is none.
+func ReadIgnoreRules(dir string) *IgnoreRules {
+	file := readIgnoreFile(dir)
+	if file == nil {
+		return nil
+	}
+	return &IgnoreRules{file: file}
+}
+
+// Ignored reports whether the rules ignore rel, a slash separated path
+// relative to the directory of the .gitignore file.
+func (r *IgnoreRules) Ignored(rel string, isDir bool) bool {
+	if r == nil {
+		return false
+	}
+	ignored, _ := r.file.match(rel, isDir)
+	return ignored
+}
+
+// MatchGlob matches a slash separated name against pattern, where a "**"
+// segment matches any number of segments.
+func MatchGlob(pattern, name string) bool {
+	return matchGlob(pattern, name)
+}
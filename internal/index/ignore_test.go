@@ -0,0 +1,59 @@
+package index
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"build", "build", true},
+		{"tmp-*", "tmp-cache", true},
+		{"tmp-*", "src/tmp-cache", false},
+		{"docs/*", "docs/api", true},
+		{"**/fixtures", "test/unit/fixtures", true},
+		{"**/fixtures", "fixtures", true},
+		{"src/**/gen", "src/a/b/gen", true},
+		{"src/**/gen", "lib/a/gen", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreFileMatch(t *testing.T) {
+	ignore := &ignoreFile{dir: "/repo"}
+	for _, line := range []string{"# comment", "", "logs/", "/out", "*.tmp", "!keep.tmp", "docs/generated"} {
+		if rule, ok := parseIgnoreRule(line); ok {
+			ignore.rules = append(ignore.rules, rule)
+		}
+	}
+
+	tests := []struct {
+		rel     string
+		isDir   bool
+		ignored bool
+		matched bool
+	}{
+		{"logs", true, true, true},
+		{"logs", false, false, false},
+		{"app/logs", true, true, true},
+		{"out", true, true, true},
+		{"app/out", true, false, false},
+		{"cache.tmp", true, true, true},
+		{"keep.tmp", true, false, true},
+		{"docs/generated", true, true, true},
+		{"src", true, false, false},
+	}
+
+	for _, tt := range tests {
+		ignored, matched := ignore.match(tt.rel, tt.isDir)
+		if ignored != tt.ignored || matched != tt.matched {
+			t.Errorf("match(%q) = %v, %v, want %v, %v", tt.rel, ignored, matched, tt.ignored, tt.matched)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package index builds the directory index used by `aura go` to find
+// directories that were never bookmarked or visited.
+package index
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Entry is an indexed directory.
+type Entry struct {
+	Path string
+	Name string
+	// Depth is the number of levels below the root the directory was found at.
+	Depth int
+	// Project reports whether the directory is a project root.
+	Project bool
+}
+
+// Options configures which directories are indexed.
+type Options struct {
+	// Roots are the base directories to index.
+	Roots []string
+	// MaxDepth limits how many levels below a root are indexed.
+	MaxDepth int
+	// Exclude lists glob patterns of directories to skip, matched against
+	// the directory name and its path relative to the root.
+	Exclude []string
+}
+
+// DefaultExclude lists glob patterns that are always excluded.
+var DefaultExclude = []string{".*", "node_modules", "vendor", "venv", "__pycache__", "target", "dist", "build"}
+
+// ProjectMarkers are files or directories that identify a project root.
+var ProjectMarkers = []string{
+	".git",
+	"go.mod",
+	"package.json",
+	"Cargo.toml",
+	"pyproject.toml",
+	"setup.py",
+	"pom.xml",
+	"build.gradle",
+	"composer.json",
+	"Gemfile",
+	"mix.exs",
+}
+
+// MaxEntries caps the size of the index.
+const MaxEntries = 100000
+
+// ErrTooManyDirectories is returned with the entries collected so far when
+// the index would exceed MaxEntries.
+var ErrTooManyDirectories = errors.New("too many directories, index truncated")
+
+// Build walks the roots and returns the directories to index. Directories
+// matching an exclude pattern or ignored by a .gitignore file are skipped
+// together with their contents. Symbolic links are not followed.
+func Build(opts Options) ([]Entry, error) {
+	w := &walker{opts: opts, seen: make(map[string]bool)}
+
+	for _, root := range opts.Roots {
+		root, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		if err := w.walk(root, root, 0, nil); err != nil {
+			return w.entries, err
+		}
+	}
+
+	return w.entries, nil
+}
+
+type walker struct {
+	opts    Options
+	entries []Entry
+	seen    map[string]bool
+}
+
+func (w *walker) walk(root, dir string, depth int, ignores []*ignoreFile) error {
+	if w.seen[dir] {
+		return nil
+	}
+	w.seen[dir] = true
+
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		// Unreadable directories are skipped rather than aborting the walk
+		return nil
+	}
+
+	w.entries = append(w.entries, Entry{
+		Path:    dir,
+		Name:    filepath.Base(dir),
+		Depth:   depth,
+		Project: hasProjectMarker(items),
+	})
+	if len(w.entries) >= MaxEntries {
+		return ErrTooManyDirectories
+	}
+	if depth >= w.opts.MaxDepth {
+		return nil
+	}
+
+	if ignore := readIgnoreFile(dir); ignore != nil {
+		// Copy so sibling directories don't share the appended rules
+		ignores = append(ignores[:len(ignores):len(ignores)], ignore)
+	}
+
+	for _, item := range items {
+		if !item.IsDir() {
+			continue
+		}
+		child := filepath.Join(dir, item.Name())
+		if w.excluded(root, child, ignores) {
+			continue
+		}
+		if err := w.walk(root, child, depth+1, ignores); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excluded reports whether the directory at path is skipped.
+func (w *walker) excluded(root, path string, ignores []*ignoreFile) bool {
+	name := filepath.Base(path)
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return true
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, patterns := range [][]string{DefaultExclude, w.opts.Exclude} {
+		for _, pattern := range patterns {
+			if matchGlob(pattern, name) || matchGlob(pattern, rel) {
+				return true
+			}
+		}
+	}
+
+	ignored := false
+	for _, ignore := range ignores {
+		rel, err := filepath.Rel(ignore.dir, path)
+		if err != nil {
+			continue
+		}
+		if result, ok := ignore.match(filepath.ToSlash(rel), true); ok {
+			ignored = result
+		}
+	}
+	return ignored
+}
+
+// hasProjectMarker reports whether items contain a project marker.
+func hasProjectMarker(items []os.DirEntry) bool {
+	for _, item := range items {
+		for _, marker := range ProjectMarkers {
+			if item.Name() == marker {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+
+	mkdir := func(parts ...string) string {
+		path := filepath.Join(append([]string{root}, parts...)...)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", path, err)
+		}
+		return path
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	api := mkdir("work", "api")
+	mkdir("work", "api", ".git")
+	mkdir("work", "api", "cmd")
+	mkdir("work", "api", "generated")
+	mkdir("work", "api", "tmp-cache")
+	write(filepath.Join(api, ".gitignore"), "# build output\ngenerated/\n")
+	mkdir("web", "node_modules", "lib")
+	mkdir("a", "b", "c", "deep")
+	mkdir("archive", "old")
+
+	entries, err := Build(Options{Roots: []string{root}, MaxDepth: 3, Exclude: []string{"archive", "tmp-*"}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	indexed := make(map[string]Entry)
+	for _, entry := range entries {
+		rel, _ := filepath.Rel(root, entry.Path)
+		indexed[filepath.ToSlash(rel)] = entry
+	}
+
+	for _, want := range []string{".", "work", "work/api", "work/api/cmd", "web", "a/b/c"} {
+		if _, ok := indexed[want]; !ok {
+			t.Errorf("Expected %s to be indexed, got %v", want, entries)
+		}
+	}
+	for _, unwanted := range []string{"work/api/.git", "work/api/generated", "work/api/tmp-cache", "web/node_modules", "a/b/c/deep", "archive"} {
+		if _, ok := indexed[unwanted]; ok {
+			t.Errorf("Expected %s to be skipped", unwanted)
+		}
+	}
+
+	if entry := indexed["work/api"]; !entry.Project || entry.Depth != 2 || entry.Name != "api" {
+		t.Errorf("Unexpected entry for project: %+v", entry)
+	}
+	if indexed["work"].Project {
+		t.Error("Directory without marker should not be a project")
+	}
+}
+
+func TestBuildOverlappingRoots(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "nested")
+	if err := os.Mkdir(nested, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	entries, err := Build(Options{Roots: []string{root, nested}, MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected directories to be indexed once, got %v", entries)
+	}
+}
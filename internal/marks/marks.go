@@ -0,0 +1,133 @@
+// Package marks keeps quick marks, directories marked by number for hopping
+// between them in one shell session. Marks are stored per session in the
+// temporary directory and are never shared with other sessions.
+package marks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+)
+
+// maxAge is how long marks of a session live after its last change, so marks
+// of closed shells don't pile up or reappear for a reused process ID.
+const maxAge = 7 * 24 * time.Hour
+
+// Session is the marks of a shell session.
+type Session struct {
+	// Dir holds the marks files of all sessions.
+	Dir string
+	// ID identifies the shell session.
+	ID string
+}
+
+// Current returns the session of the shell running Aura. The shell
+// integration passes the shell's process ID in AURA_SESSION, otherwise the
+// parent process is taken as the shell.
+func Current() Session {
+	id := envvar.Session.Get()
+	if id == "" {
+		id = strconv.Itoa(os.Getppid())
+	}
+
+	dir := "aura-marks"
+	if uid := os.Getuid(); uid >= 0 {
+		dir = fmt.Sprintf("aura-marks-%d", uid)
+	}
+	return Session{Dir: filepath.Join(os.TempDir(), dir), ID: id}
+}
+
+// file returns the path of the marks file of the session.
+func (s Session) file() string {
+	return filepath.Join(s.Dir, filepath.Base(s.ID)+".json")
+}
+
+// Load returns the marks of the session, mapping names to directories.
+func (s Session) Load() (map[string]string, error) {
+	marks := make(map[string]string)
+
+	info, err := os.Stat(s.file())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return marks, nil
+		}
+		return nil, fmt.Errorf("failed to read marks: %w", err)
+	}
+	if time.Since(info.ModTime()) > maxAge {
+		return marks, nil
+	}
+
+	content, err := os.ReadFile(s.file())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read marks: %w", err)
+	}
+	if err := json.Unmarshal(content, &marks); err != nil {
+		return nil, fmt.Errorf("invalid marks file %s: %w", s.file(), err)
+	}
+	return marks, nil
+}
+
+// Set marks path with name, replacing an earlier mark of the same name.
+func (s Session) Set(name, path string) error {
+	marks, err := s.Load()
+	if err != nil {
+		return err
+	}
+	marks[name] = path
+	return s.save(marks)
+}
+
+// Remove removes the mark name and reports whether it existed.
+func (s Session) Remove(name string) (bool, error) {
+	marks, err := s.Load()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := marks[name]; !ok {
+		return false, nil
+	}
+	delete(marks, name)
+	return true, s.save(marks)
+}
+
+// Clear removes all marks of the session.
+func (s Session) Clear() error {
+	if err := os.Remove(s.file()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear marks: %w", err)
+	}
+	return nil
+}
+
+func (s Session) save(marks map[string]string) error {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create marks directory: %w", err)
+	}
+	s.prune()
+
+	content, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode marks: %w", err)
+	}
+	if err := os.WriteFile(s.file(), content, 0600); err != nil {
+		return fmt.Errorf("failed to save marks: %w", err)
+	}
+	return nil
+}
+
+// prune removes the marks files of sessions unchanged for maxAge.
+func (s Session) prune() {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > maxAge {
+			os.Remove(filepath.Join(s.Dir, entry.Name()))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package marks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSession(t *testing.T) {
+	dir := t.TempDir()
+	session := Session{Dir: dir, ID: "100"}
+	other := Session{Dir: dir, ID: "200"}
+
+	if err := session.Set("1", "/tmp/a"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := session.Set("2", "/tmp/b"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := session.Set("1", "/tmp/c"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	marks, err := session.Load()
+	if err != nil || len(marks) != 2 || marks["1"] != "/tmp/c" || marks["2"] != "/tmp/b" {
+		t.Errorf("Load() = %v, %v", marks, err)
+	}
+	if marks, _ := other.Load(); len(marks) != 0 {
+		t.Errorf("Expected no marks in another session, got %v", marks)
+	}
+
+	if removed, err := session.Remove("2"); !removed || err != nil {
+		t.Errorf("Remove() = %v, %v", removed, err)
+	}
+	if removed, _ := session.Remove("2"); removed {
+		t.Error("Expected removing a missing mark to report false")
+	}
+
+	if err := session.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if marks, _ := session.Load(); len(marks) != 0 {
+		t.Errorf("Expected no marks after Clear(), got %v", marks)
+	}
+}
+
+func TestSessionExpires(t *testing.T) {
+	dir := t.TempDir()
+	old := Session{Dir: dir, ID: "100"}
+	if err := old.Set("1", "/tmp/a"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	past := time.Now().Add(-maxAge - time.Hour)
+	os.Chtimes(old.file(), past, past)
+
+	if marks, _ := old.Load(); len(marks) != 0 {
+		t.Errorf("Expected expired marks to be ignored, got %v", marks)
+	}
+
+	// Saving any session removes expired ones
+	if err := (Session{Dir: dir, ID: "200"}).Set("1", "/tmp/b"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "100.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected expired marks file to be removed, got %v", err)
+	}
+}
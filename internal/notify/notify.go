@@ -0,0 +1,79 @@
+// Package notify shows desktop notifications with the tools of each platform:
+// a toast through PowerShell on Windows, osascript on macOS and notify-send on
+// Linux.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/config"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// ErrUnavailable is returned when the platform has no way to show
+// notifications.
+var ErrUnavailable = errors.New("desktop notifications are unavailable, install notify-send")
+
+// Due reports whether an operation of command that ran for elapsed is long
+// enough to notify its end, as configured in the notification settings.
+func Due(command string, elapsed time.Duration) bool {
+	after, enabled := config.GetNotifyAfter(command)
+	return enabled && elapsed >= after
+}
+
+// Send shows a desktop notification. It returns ErrUnavailable if the
+// platform has no way to show one, such as a Linux system without notify-send.
+func Send(title, message string) error {
+	cmd, err := command(platform.Current().OS, title, message)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to show notification: %w", err)
+	}
+	return nil
+}
+
+// command returns the command showing a notification on goos.
+func command(goos, title, message string) (*exec.Cmd, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return exec.Command("osascript", "-e", script), nil
+	case "windows":
+		return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript(title, message)), nil
+	default:
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return nil, ErrUnavailable
+		}
+		return exec.Command(path, "--app-name=Aura", title, message), nil
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powerShellString quotes s as a PowerShell string literal without
+// expansion.
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// toastScript returns the PowerShell script showing a toast notification.
+func toastScript(title, message string) string {
+	return fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode(%s)) | Out-Null
+$text.Item(1).AppendChild($template.CreateTextNode(%s)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('Aura').Show($toast)`,
+		powerShellString(title), powerShellString(message))
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	cmd, err := command("darwin", `Build "done"`, `it's \ ok`)
+	if err != nil {
+		t.Fatalf("command() error = %v", err)
+	}
+	expected := `display notification "it's \\ ok" with title "Build \"done\""`
+	if cmd.Args[len(cmd.Args)-1] != expected {
+		t.Errorf("Unexpected script %q", cmd.Args[len(cmd.Args)-1])
+	}
+
+	cmd, err = command("windows", "Tests", "it's done")
+	if err != nil {
+		t.Fatalf("command() error = %v", err)
+	}
+	if script := cmd.Args[len(cmd.Args)-1]; !strings.Contains(script, "CreateTextNode('it''s done')") {
+		t.Errorf("Expected quoted message in:\n%s", script)
+	}
+}
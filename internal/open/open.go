@@ -0,0 +1,71 @@
+// Package open opens files, directories and URLs in the application the
+// desktop associates with them: the file manager, the browser or the default
+// application of a file type.
+package open
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// Command returns the command opening target, a file, directory or URL, in
+// the application the desktop associates with it.
+func Command(target string) *exec.Cmd {
+	return exec.Command(platform.Current().Opener(), target)
+}
+
+// ShellCommand returns the command opening target as text, for actions run
+// or shown as shell commands.
+func ShellCommand(target string) string {
+	if strings.ContainsAny(target, " \t'\"") {
+		target = `"` + target + `"`
+	}
+	return platform.Current().Opener() + " " + target
+}
+
+// Start opens target without waiting for the application to exit.
+func Start(target string) error {
+	cmd := Command(target)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// urlPattern matches URLs such as https://example.com or mailto:me@host.
+var urlPattern = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]+://\S+|mailto:\S+)$`)
+
+// IsURL reports whether target is a URL rather than a path.
+func IsURL(target string) bool {
+	return urlPattern.MatchString(target)
+}
+
+// IsText reports whether the file at path holds text, judged by its first
+// bytes, so it is better opened in an editor than in its default application.
+func IsText(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, _ := file.Read(head)
+	head = head[:n]
+	if n == 0 {
+		return true
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	contentType := http.DetectContentType(head)
+	return strings.HasPrefix(contentType, "text/") || strings.Contains(contentType, "json") || strings.Contains(contentType, "xml")
+}
@@ -0,0 +1,50 @@
+package open
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsURL(t *testing.T) {
+	for target, want := range map[string]bool{
+		"https://example.com/docs": true,
+		"http://localhost:8080":    true,
+		"mailto:me@example.com":    true,
+		"s3://bucket/logs":         true,
+		"~/notes":                  false,
+		"/home/me/code":            false,
+		`C:\Users\me`:              false,
+		"README.md":                false,
+	} {
+		if got := IsURL(target); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestIsText(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"notes.md":   []byte("# Notes\n\nSome text"),
+		"main.go":    []byte("package main\n"),
+		"data.json":  []byte(`{"a": 1}`),
+		"empty.txt":  nil,
+		"image.png":  {0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 0x0d},
+		"binary.bin": {0x7f, 'E', 'L', 'F', 2, 1, 1, 0},
+	}
+	want := map[string]bool{"notes.md": true, "main.go": true, "data.json": true, "empty.txt": true}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if got := IsText(path); got != want[name] {
+			t.Errorf("IsText(%s) = %v, want %v", name, got, want[name])
+		}
+	}
+	if IsText(filepath.Join(dir, "missing")) {
+		t.Error("Expected missing files not to be text")
+	}
+}
@@ -0,0 +1,400 @@
+// Package openapi reads OpenAPI 3 and Swagger 2 specs into a compact outline
+// of their operations, small enough to ground the AI assistant on the API.
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileNames are the names of spec files, in order of preference.
+var fileNames = []string{"openapi.yaml", "openapi.yml", "openapi.json", "swagger.yaml", "swagger.yml", "swagger.json"}
+
+// searchDirs are the directories of a project specs are looked for in.
+var searchDirs = []string{".", "api", "docs", "spec", "openapi"}
+
+// Find returns the path of the spec of the project at dir, or "" if there
+// is none.
+func Find(dir string) string {
+	for _, sub := range searchDirs {
+		for _, name := range fileNames {
+			path := filepath.Join(dir, sub, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// Spec is the outline of an API.
+type Spec struct {
+	Title   string
+	Version string
+	// Servers are the base URLs of the API.
+	Servers []string
+	// Auth describes the security schemes, such as "bearer token in the
+	// Authorization header".
+	Auth       []string
+	Operations []Operation
+}
+
+// Operation is an operation of the API.
+type Operation struct {
+	Method  string
+	Path    string
+	Summary string
+	Params  []Param
+	// Body outlines the JSON request body, such as "{name*: string}", with
+	// required properties marked with *.
+	Body string
+}
+
+// Param is a path, query or header parameter.
+type Param struct {
+	Name     string
+	In       string
+	Type     string
+	Required bool
+}
+
+// methods are the operations of a path item, in the order they are listed.
+var methods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// maxDepth limits how deep schemas are outlined.
+const maxDepth = 4
+
+// Load reads the spec at path.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Parse parses an OpenAPI 3 or Swagger 2 spec in YAML or JSON.
+func Parse(data []byte) (*Spec, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if root["openapi"] == nil && root["swagger"] == nil {
+		return nil, fmt.Errorf("not an OpenAPI or Swagger spec")
+	}
+
+	r := &reader{root: root, expanding: map[string]bool{}}
+	info := mapOf(root["info"])
+	spec := &Spec{Title: stringOf(info["title"]), Version: stringOf(info["version"])}
+	spec.Servers = r.servers()
+	spec.Auth = r.auth()
+
+	paths := mapOf(root["paths"])
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, path := range names {
+		item := mapOf(r.resolve(paths[path]))
+		shared := listOf(item["parameters"])
+		for _, method := range methods {
+			if operation, ok := item[method].(map[string]any); ok {
+				spec.Operations = append(spec.Operations, r.operation(strings.ToUpper(method), path, operation, shared))
+			}
+		}
+	}
+	return spec, nil
+}
+
+// Outline returns the spec as text for the AI assistant, cut after limit
+// bytes at an operation boundary.
+func (s *Spec) Outline(limit int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API: %s %s\n", s.Title, s.Version)
+	if len(s.Servers) > 0 {
+		fmt.Fprintf(&b, "Servers: %s\n", strings.Join(s.Servers, ", "))
+	}
+	for _, auth := range s.Auth {
+		fmt.Fprintf(&b, "Auth: %s\n", auth)
+	}
+	b.WriteString("Operations (* marks required):\n")
+
+	for i, operation := range s.Operations {
+		text := operation.String()
+		if b.Len()+len(text) > limit {
+			fmt.Fprintf(&b, "... %d more operations not shown\n", len(s.Operations)-i)
+			break
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// String returns the operation as lines of its outline.
+func (o Operation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", o.Method, o.Path)
+	if o.Summary != "" {
+		fmt.Fprintf(&b, " - %s", o.Summary)
+	}
+	b.WriteString("\n")
+	for _, in := range []string{"path", "query", "header"} {
+		var params []string
+		for _, param := range o.Params {
+			if param.In == in {
+				params = append(params, param.String())
+			}
+		}
+		if len(params) > 0 {
+			fmt.Fprintf(&b, "  %s: %s\n", in, strings.Join(params, ", "))
+		}
+	}
+	if o.Body != "" {
+		fmt.Fprintf(&b, "  body: %s\n", o.Body)
+	}
+	return b.String()
+}
+
+// String returns the parameter as "name*: type".
+func (p Param) String() string {
+	name := p.Name
+	if p.Required {
+		name += "*"
+	}
+	return name + ": " + p.Type
+}
+
+// reader reads the parts of a spec, resolving their references.
+type reader struct {
+	root map[string]any
+	// expanding holds the references of the schemas being outlined, to
+	// stop at recursive ones.
+	expanding map[string]bool
+}
+
+// servers returns the base URLs of the API.
+func (r *reader) servers() []string {
+	var servers []string
+	for _, server := range listOf(r.root["servers"]) {
+		if url := stringOf(mapOf(server)["url"]); url != "" {
+			servers = append(servers, url)
+		}
+	}
+	if host := stringOf(r.root["host"]); host != "" {
+		scheme := "https"
+		if schemes := listOf(r.root["schemes"]); len(schemes) > 0 && !slices.Contains(schemes, any("https")) {
+			scheme = stringOf(schemes[0])
+		}
+		servers = append(servers, scheme+"://"+host+stringOf(r.root["basePath"]))
+	}
+	return servers
+}
+
+// auth describes the security schemes of the API.
+func (r *reader) auth() []string {
+	schemes := mapOf(mapOf(r.root["components"])["securitySchemes"])
+	if len(schemes) == 0 {
+		schemes = mapOf(r.root["securityDefinitions"])
+	}
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var auth []string
+	for _, name := range names {
+		scheme := mapOf(r.resolve(schemes[name]))
+		var description string
+		switch kind := stringOf(scheme["type"]); {
+		case kind == "apiKey":
+			description = fmt.Sprintf("API key in the %s %s", stringOf(scheme["name"]), stringOf(scheme["in"]))
+		case kind == "http" && strings.EqualFold(stringOf(scheme["scheme"]), "bearer"), kind == "oauth2", kind == "openIdConnect":
+			description = "bearer token in the Authorization header"
+		case kind == "http" || kind == "basic":
+			description = "basic auth in the Authorization header"
+		default:
+			description = kind
+		}
+		auth = append(auth, fmt.Sprintf("%s (%s)", name, description))
+	}
+	return auth
+}
+
+// operation outlines an operation with the parameters shared by its path.
+func (r *reader) operation(method, path string, operation map[string]any, shared []any) Operation {
+	result := Operation{Method: method, Path: path, Summary: stringOf(operation["summary"])}
+	if result.Summary == "" {
+		result.Summary = stringOf(operation["operationId"])
+	}
+
+	var formFields []string
+	for _, value := range append(slices.Clone(shared), listOf(operation["parameters"])...) {
+		param := mapOf(r.resolve(value))
+		name, in := stringOf(param["name"]), stringOf(param["in"])
+		required := param["required"] == true
+		switch in {
+		case "path", "query", "header":
+			schema := param["schema"]
+			if schema == nil {
+				// Swagger 2 puts the type on the parameter itself.
+				schema = param
+			}
+			result.Params = slices.DeleteFunc(result.Params, func(p Param) bool { return p.Name == name && p.In == in })
+			result.Params = append(result.Params, Param{Name: name, In: in, Type: r.schema(schema, 0), Required: required})
+		case "body":
+			result.Body = r.schema(param["schema"], 0)
+		case "formData":
+			field := name
+			if required {
+				field += "*"
+			}
+			formFields = append(formFields, field+": "+r.schema(param, 0))
+		}
+	}
+	if len(formFields) > 0 {
+		result.Body = "form {" + strings.Join(formFields, ", ") + "}"
+	}
+
+	content := mapOf(mapOf(r.resolve(operation["requestBody"]))["content"])
+	for _, mediaType := range []string{"application/json", "application/x-www-form-urlencoded", "multipart/form-data"} {
+		if media, ok := content[mediaType].(map[string]any); ok {
+			result.Body = r.schema(media["schema"], 0)
+			if mediaType != "application/json" {
+				result.Body = "form " + result.Body
+			}
+			break
+		}
+	}
+	return result
+}
+
+// schema outlines a schema, such as "{name*: string, tags: [string]}".
+func (r *reader) schema(value any, depth int) string {
+	schema := mapOf(value)
+	if ref := stringOf(schema["$ref"]); ref != "" {
+		if depth >= maxDepth || r.expanding[ref] {
+			return ref[strings.LastIndex(ref, "/")+1:]
+		}
+		r.expanding[ref] = true
+		defer delete(r.expanding, ref)
+		schema = mapOf(r.resolve(schema))
+	}
+
+	for _, combinator := range []string{"oneOf", "anyOf"} {
+		if options := listOf(schema[combinator]); len(options) > 0 {
+			outlines := make([]string, len(options))
+			for i, option := range options {
+				outlines[i] = r.schema(option, depth+1)
+			}
+			return strings.Join(outlines, " | ")
+		}
+	}
+	if parts := listOf(schema["allOf"]); len(parts) > 0 {
+		merged := map[string]any{"type": "object", "properties": map[string]any{}}
+		var required []any
+		for _, part := range parts {
+			part := mapOf(r.resolve(part))
+			for name, property := range mapOf(part["properties"]) {
+				merged["properties"].(map[string]any)[name] = property
+			}
+			required = append(required, listOf(part["required"])...)
+		}
+		merged["required"] = required
+		schema = merged
+	}
+
+	kind := stringOf(schema["type"])
+	switch {
+	case kind == "array":
+		return "[" + r.schema(schema["items"], depth+1) + "]"
+	case kind == "object" || schema["properties"] != nil:
+		properties := mapOf(schema["properties"])
+		if len(properties) == 0 {
+			return "object"
+		}
+		if depth >= maxDepth {
+			return "{...}"
+		}
+		required := listOf(schema["required"])
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		fields := make([]string, len(names))
+		for i, name := range names {
+			field := name
+			if slices.Contains(required, any(name)) {
+				field += "*"
+			}
+			fields[i] = field + ": " + r.schema(properties[name], depth+1)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case kind == "":
+		return "any"
+	}
+
+	if format := stringOf(schema["format"]); format != "" {
+		kind += "(" + format + ")"
+	}
+	if values := listOf(schema["enum"]); len(values) > 0 {
+		options := make([]string, len(values))
+		for i, value := range values {
+			options[i] = fmt.Sprint(value)
+		}
+		kind += " " + strings.Join(options, "|")
+	}
+	return kind
+}
+
+// resolve returns the value a local "$ref" refers to, or value itself.
+// References to other files are left unresolved.
+func (r *reader) resolve(value any) any {
+	for range maxDepth {
+		ref, ok := mapOf(value)["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return value
+		}
+		var target any = r.root
+		for _, key := range strings.Split(ref[2:], "/") {
+			key = strings.NewReplacer("~1", "/", "~0", "~").Replace(key)
+			target = mapOf(target)[key]
+		}
+		if target == nil {
+			return value
+		}
+		value = target
+	}
+	return value
+}
+
+func mapOf(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
+
+func listOf(value any) []any {
+	l, _ := value.([]any)
+	return l
+}
+
+func stringOf(value any) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
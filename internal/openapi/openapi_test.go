@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const petstore = `openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.2.0
+servers:
+  - url: https://api.example.com/v1
+components:
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+    key:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  parameters:
+    PetID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+        format: int64
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        status:
+          type: string
+          enum: [available, sold]
+        tags:
+          type: array
+          items:
+            type: string
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/NewPet'
+        - type: object
+          required: [id]
+          properties:
+            id:
+              type: integer
+            owner:
+              $ref: '#/components/schemas/Pet'
+paths:
+  /pets/{id}:
+    parameters:
+      - $ref: '#/components/parameters/PetID'
+    delete:
+      summary: Delete a pet
+    get:
+      operationId: getPet
+  /pets:
+    get:
+      summary: List pets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+    post:
+      summary: Add a pet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+`
+
+func TestParseOpenAPI(t *testing.T) {
+	spec, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := `API: Petstore 1.2.0
+Servers: https://api.example.com/v1
+Auth: key (API key in the X-API-Key header)
+Auth: token (bearer token in the Authorization header)
+Operations (* marks required):
+GET /pets - List pets
+  query: limit: integer
+POST /pets - Add a pet
+  body: {name*: string, status: string available|sold, tags: [string]}
+GET /pets/{id} - getPet
+  path: id*: integer(int64)
+DELETE /pets/{id} - Delete a pet
+  path: id*: integer(int64)
+`
+	if got := spec.Outline(10000); got != want {
+		t.Errorf("Outline() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := spec.Outline(260); !strings.HasSuffix(got, "... 3 more operations not shown\n") {
+		t.Errorf("Outline(260) =\n%s", got)
+	}
+}
+
+func TestSchemaRecursion(t *testing.T) {
+	var root map[string]any
+	if err := yaml.Unmarshal([]byte(petstore), &root); err != nil {
+		t.Fatal(err)
+	}
+	r := &reader{root: root, expanding: map[string]bool{}}
+
+	got := r.schema(map[string]any{"$ref": "#/components/schemas/Pet"}, 0)
+	if want := "{id*: integer, name*: string, owner: Pet, status: string available|sold, tags: [string]}"; got != want {
+		t.Errorf("schema(Pet) = %s, want %s", got, want)
+	}
+}
+
+func TestParseSwagger(t *testing.T) {
+	swagger := `{
+  "swagger": "2.0",
+  "info": {"title": "Users", "version": "1"},
+  "host": "users.example.com",
+  "basePath": "/api",
+  "schemes": ["http", "https"],
+  "securityDefinitions": {"basic": {"type": "basic"}},
+  "paths": {
+    "/users": {
+      "post": {
+        "summary": "Create a user",
+        "parameters": [
+          {"name": "dryRun", "in": "query", "type": "boolean"},
+          {"name": "user", "in": "body", "schema": {"$ref": "#/definitions/User"}}
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "User": {"type": "object", "required": ["email"], "properties": {"email": {"type": "string", "format": "email"}}}
+  }
+}`
+	spec, err := Parse([]byte(swagger))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(spec.Servers) != 1 || spec.Servers[0] != "https://users.example.com/api" {
+		t.Errorf("Servers = %v", spec.Servers)
+	}
+	if len(spec.Auth) != 1 || spec.Auth[0] != "basic (basic auth in the Authorization header)" {
+		t.Errorf("Auth = %v", spec.Auth)
+	}
+	want := "POST /users - Create a user\n  query: dryRun: boolean\n  body: {email*: string(email)}\n"
+	if len(spec.Operations) != 1 || spec.Operations[0].String() != want {
+		t.Errorf("Operations = %v, want %q", spec.Operations, want)
+	}
+}
+
+func TestParseNotASpec(t *testing.T) {
+	if _, err := Parse([]byte("name: app\nversion: 1\n")); err == nil {
+		t.Error("Parse() of a file that is no spec succeeded")
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	if got := Find(dir); got != "" {
+		t.Errorf("Find() in an empty directory = %q", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(dir, "docs", "swagger.json")
+	if err := os.WriteFile(nested, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Find(dir); got != nested {
+		t.Errorf("Find() = %q, want %q", got, nested)
+	}
+
+	top := filepath.Join(dir, "openapi.yml")
+	if err := os.WriteFile(top, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Find(dir); got != top {
+		t.Errorf("Find() = %q, want %q", got, top)
+	}
+}
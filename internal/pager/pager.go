@@ -0,0 +1,70 @@
+// Package pager shows long output in a pager: AURA_PAGER, $PAGER, or else
+// less if it is installed.
+package pager
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// Resolve returns the pager command, which may include arguments, or "" if
+// the pager is turned off or none is installed. cat and an empty $PAGER
+// turn it off.
+func Resolve() string {
+	if pager := strings.TrimSpace(envvar.Pager.Get()); pager != "" {
+		return enabled(pager)
+	}
+	if pager, ok := os.LookupEnv("PAGER"); ok {
+		return enabled(strings.TrimSpace(pager))
+	}
+	if platform.Current().Installed("less") {
+		return "less"
+	}
+	return ""
+}
+
+// enabled returns pager, or "" if it turns the pager off.
+func enabled(pager string) string {
+	if pager == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// ansiEscape matches the color codes of the theme.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Fits reports whether text fits on a screen of width columns and height
+// rows, with long lines wrapped and without the color codes.
+func Fits(text string, width, height int) bool {
+	if width < 1 {
+		width = 1
+	}
+	rows := 0
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		length := utf8.RuneCountInString(ansiEscape.ReplaceAllString(line, ""))
+		rows += max(1, (length+width-1)/width)
+		if rows > height {
+			return false
+		}
+	}
+	return true
+}
+
+// Page shows text in pager and waits until it quits. less shows the colors
+// of the theme, unless $LESS is set.
+func Page(pager, text string) error {
+	cmd := platform.Current().ShellCommand(pager)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,61 @@
+package pager
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		aura, pager, want string
+	}{
+		{"", "more", "more"},
+		{"less -S", "more", "less -S"},
+		{"cat", "more", ""},
+		{"", "cat", ""},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		t.Setenv("AURA_PAGER", test.aura)
+		t.Setenv("PAGER", test.pager)
+		if got := Resolve(); got != test.want {
+			t.Errorf("Resolve() = %q with AURA_PAGER=%q PAGER=%q, want %q", got, test.aura, test.pager, test.want)
+		}
+	}
+}
+
+func TestFits(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"short", "a\nb\nc\n", true},
+		{"too many lines", "a\nb\nc\nd\ne\n", false},
+		{"wrapped lines", strings.Repeat("x", 25) + "\nb\nc\n", false},
+		{"colors take no room", "\x1b[32m" + strings.Repeat("x", 10) + "\x1b[0m\nb\nc\nd\n", true},
+		{"wide runes", strings.Repeat("ä", 10) + "\n", true},
+	}
+	for _, test := range tests {
+		if got := Fits(test.text, 10, 4); got != test.want {
+			t.Errorf("Fits(%s) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("LESS", "")
+	out := filepath.Join(t.TempDir(), "paged")
+	if err := Page("cat > "+out+"; echo $LESS >> "+out, "long output\n"); err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if paged, _ := os.ReadFile(out); string(paged) != "long output\nFRX\n" {
+		t.Errorf("the pager got %q, want the text and LESS for colors", paged)
+	}
+}
@@ -0,0 +1,139 @@
+// Package platform describes the operating system Aura runs on and the
+// programs it uses there to run shell commands, open files and edit them.
+// Commands take a Platform instead of checking runtime.GOOS, so tests can
+// pretend to run anywhere.
+package platform
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/envvar"
+)
+
+// Platform is an operating system and its environment.
+type Platform struct {
+	// OS is the operating system as in runtime.GOOS, such as "windows".
+	OS string
+	// Getenv looks up environment variables.
+	Getenv func(key string) string
+	// LookPath finds installed programs.
+	LookPath func(file string) (string, error)
+}
+
+// Current returns the platform Aura runs on. It is a variable so tests can
+// replace it.
+var Current = func() Platform {
+	return Detect(runtime.GOOS, os.Getenv)
+}
+
+// Detect returns the platform of goos with the environment of getenv. The OS
+// and OSTYPE variables, set by Windows and by shells such as bash, override
+// goos, so tests and environments like MSYS are detected by what they claim
+// to be.
+func Detect(goos string, getenv func(string) string) Platform {
+	if name := strings.ToLower(getenv("OS")); name != "" {
+		if strings.Contains(name, "windows") {
+			goos = "windows"
+		} else if goos == "windows" {
+			goos = "linux"
+		}
+	}
+	if ostype := strings.ToLower(getenv("OSTYPE")); ostype != "" && goos != "windows" {
+		if strings.Contains(ostype, "darwin") {
+			goos = "darwin"
+		} else if goos == "darwin" {
+			goos = "linux"
+		}
+	}
+	return Platform{OS: goos, Getenv: getenv, LookPath: exec.LookPath}
+}
+
+// IsWindows reports whether the platform is Windows.
+func (p Platform) IsWindows() bool {
+	return p.OS == "windows"
+}
+
+// IsMacOS reports whether the platform is macOS.
+func (p Platform) IsMacOS() bool {
+	return p.OS == "darwin"
+}
+
+// Shell returns the program and its arguments running a command line, cmd
+// /c on Windows and sh -c elsewhere.
+func (p Platform) Shell() (string, []string) {
+	if p.IsWindows() {
+		return "cmd", []string{"/c"}
+	}
+	return "sh", []string{"-c"}
+}
+
+// ShellCommand returns the command running command in the shell of Shell.
+func (p Platform) ShellCommand(command string) *exec.Cmd {
+	name, args := p.Shell()
+	return exec.Command(name, append(args, command)...)
+}
+
+// ShellCommandContext is like ShellCommand, but the command is killed when
+// ctx is done.
+func (p Platform) ShellCommandContext(ctx context.Context, command string) *exec.Cmd {
+	name, args := p.Shell()
+	return exec.CommandContext(ctx, name, append(args, command)...)
+}
+
+// UserShell returns the name of the interactive shell of the user, such as
+// "bash" or "powershell": $AURA_SHELL, set by the shell integration, the
+// base name of $SHELL, or else the default shell of the platform.
+func (p Platform) UserShell() string {
+	if shell := p.Getenv(envvar.Shell.Name); shell != "" {
+		return shell
+	}
+	if shell := p.Getenv("SHELL"); shell != "" {
+		return filepath.Base(shell)
+	}
+	if p.IsWindows() {
+		return "powershell"
+	}
+	return "bash"
+}
+
+// Opener returns the program opening files, directories and URLs in the
+// application the desktop associates with them.
+func (p Platform) Opener() string {
+	switch p.OS {
+	case "windows":
+		return "explorer"
+	case "darwin":
+		return "open"
+	default:
+		return "xdg-open"
+	}
+}
+
+// FallbackEditor returns the editor that is always there, for when none is
+// configured or installed.
+func (p Platform) FallbackEditor() string {
+	if p.IsWindows() {
+		return "notepad"
+	}
+	return "nano"
+}
+
+// TerminalDevice returns the device to talk to the user through when the
+// standard input is redirected.
+func (p Platform) TerminalDevice() string {
+	if p.IsWindows() {
+		return "CONIN$"
+	}
+	return "/dev/tty"
+}
+
+// Installed reports whether program is on the PATH.
+func (p Platform) Installed(program string) bool {
+	_, err := p.LookPath(program)
+	return err == nil
+}
@@ -0,0 +1,103 @@
+package platform
+
+import (
+	"errors"
+	"testing"
+)
+
+// env returns a Getenv looking up vars.
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		goos string
+		vars map[string]string
+		want string
+	}{
+		{"linux", nil, "linux"},
+		{"darwin", nil, "darwin"},
+		{"windows", nil, "windows"},
+		{"linux", map[string]string{"OS": "Windows_NT"}, "windows"},
+		{"windows", map[string]string{"OS": "Windows_NT", "OSTYPE": "msys"}, "windows"},
+		{"windows", map[string]string{"OS": "Linux"}, "linux"},
+		{"linux", map[string]string{"OSTYPE": "darwin21"}, "darwin"},
+		{"darwin", map[string]string{"OSTYPE": "linux-gnu"}, "linux"},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.goos, env(tt.vars)).OS; got != tt.want {
+			t.Errorf("Detect(%s, %v).OS = %s, want %s", tt.goos, tt.vars, got, tt.want)
+		}
+	}
+}
+
+func TestShell(t *testing.T) {
+	for goos, want := range map[string]string{"windows": "cmd /c dir", "linux": "sh -c dir", "darwin": "sh -c dir"} {
+		cmd := Detect(goos, env(nil)).ShellCommand("dir")
+		if got := cmd.Args[0] + " " + cmd.Args[1] + " " + cmd.Args[2]; got != want {
+			t.Errorf("ShellCommand on %s = %s, want %s", goos, got, want)
+		}
+	}
+}
+
+func TestUserShell(t *testing.T) {
+	tests := []struct {
+		goos string
+		vars map[string]string
+		want string
+	}{
+		{"linux", map[string]string{"AURA_SHELL": "fish", "SHELL": "/bin/zsh"}, "fish"},
+		{"linux", map[string]string{"SHELL": "/bin/zsh"}, "zsh"},
+		{"linux", nil, "bash"},
+		{"windows", nil, "powershell"},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.goos, env(tt.vars)).UserShell(); got != tt.want {
+			t.Errorf("UserShell() on %s with %v = %s, want %s", tt.goos, tt.vars, got, tt.want)
+		}
+	}
+}
+
+func TestOpener(t *testing.T) {
+	for goos, want := range map[string]string{"windows": "explorer", "darwin": "open", "linux": "xdg-open", "freebsd": "xdg-open"} {
+		if got := Detect(goos, env(nil)).Opener(); got != want {
+			t.Errorf("Opener() on %s = %s, want %s", goos, got, want)
+		}
+	}
+}
+
+func TestFallbackEditor(t *testing.T) {
+	for goos, want := range map[string]string{"windows": "notepad", "darwin": "nano", "linux": "nano"} {
+		if got := Detect(goos, env(nil)).FallbackEditor(); got != want {
+			t.Errorf("FallbackEditor() on %s = %s, want %s", goos, got, want)
+		}
+	}
+}
+
+func TestTerminalDevice(t *testing.T) {
+	for goos, want := range map[string]string{"windows": "CONIN$", "darwin": "/dev/tty", "linux": "/dev/tty"} {
+		if got := Detect(goos, env(nil)).TerminalDevice(); got != want {
+			t.Errorf("TerminalDevice() on %s = %s, want %s", goos, got, want)
+		}
+	}
+}
+
+func TestInstalled(t *testing.T) {
+	p := Detect("linux", env(nil))
+	p.LookPath = func(file string) (string, error) {
+		if file == "nvim" {
+			return "/usr/bin/nvim", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	if !p.Installed("nvim") {
+		t.Error("Installed(nvim) = false, want true")
+	}
+	if p.Installed("code") {
+		t.Error("Installed(code) = true, want false")
+	}
+}
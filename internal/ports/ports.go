@@ -0,0 +1,339 @@
+// Package ports lists the TCP ports processes listen on, finds the port a
+// command failed to listen on in its output, and stops the processes
+// holding ports.
+package ports
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// Listener is a process listening on a TCP port.
+type Listener struct {
+	Port int
+	// Address is the address listened on, such as "0.0.0.0", "::" or
+	// "127.0.0.1".
+	Address string
+	// PID and Process are 0 and "" for processes of other users, which
+	// are not visible without more privileges.
+	PID     int
+	Process string
+}
+
+// List returns the listening TCP ports of p sorted by port: read from /proc
+// on Linux, from lsof on macOS and from netstat and tasklist on Windows.
+func List(ctx context.Context, p platform.Platform) ([]Listener, error) {
+	var listeners []Listener
+	var err error
+	switch {
+	case p.IsWindows():
+		listeners, err = listWindows(ctx)
+	case p.IsMacOS():
+		listeners, err = listLsof(ctx)
+	default:
+		listeners, err = listProc("/proc")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(listeners, func(a, b Listener) int {
+		if a.Port != b.Port {
+			return a.Port - b.Port
+		}
+		if a.Address != b.Address {
+			return strings.Compare(a.Address, b.Address)
+		}
+		return a.PID - b.PID
+	})
+	return slices.Compact(listeners), nil
+}
+
+// OnPort returns the listeners of listeners on port.
+func OnPort(listeners []Listener, port int) []Listener {
+	var result []Listener
+	for _, listener := range listeners {
+		if listener.Port == port {
+			result = append(result, listener)
+		}
+	}
+	return result
+}
+
+// listProc reads the listening sockets of /proc/net/tcp and tcp6 and finds
+// their processes by the socket inodes of /proc/<pid>/fd.
+func listProc(root string) ([]Listener, error) {
+	var listeners []Listener
+	inodes := map[string][]int{}
+	for _, name := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile(filepath.Join(root, "net", name))
+		if err != nil {
+			if os.IsNotExist(err) && name == "tcp6" {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read listening ports: %w", err)
+		}
+		sockets, err := parseProcNet(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, socket := range sockets {
+			inodes[socket.inode] = append(inodes[socket.inode], len(listeners))
+			listeners = append(listeners, socket.Listener)
+		}
+	}
+
+	processes, _ := filepath.Glob(filepath.Join(root, "[0-9]*"))
+	for _, dir := range processes {
+		fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(target, "socket:[") {
+				continue
+			}
+			for _, i := range inodes[strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]")] {
+				listeners[i].PID, _ = strconv.Atoi(filepath.Base(dir))
+				comm, _ := os.ReadFile(filepath.Join(dir, "comm"))
+				listeners[i].Process = strings.TrimSpace(string(comm))
+			}
+		}
+	}
+	return listeners, nil
+}
+
+// procSocket is a listening socket of /proc/net/tcp.
+type procSocket struct {
+	Listener
+	inode string
+}
+
+// procListen is the state of listening sockets in /proc/net/tcp.
+const procListen = "0A"
+
+// parseProcNet parses the listening sockets of a /proc/net/tcp or tcp6
+// table.
+func parseProcNet(data []byte) ([]procSocket, error) {
+	var sockets []procSocket
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != procListen {
+			continue
+		}
+		hexAddress, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(hexPort, 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port in %q", fields[1])
+		}
+		address, err := procAddress(hexAddress)
+		if err != nil {
+			return nil, err
+		}
+		sockets = append(sockets, procSocket{Listener: Listener{Port: int(port), Address: address}, inode: fields[9]})
+	}
+	return sockets, scanner.Err()
+}
+
+// procAddress decodes an address of /proc/net/tcp, hex encoded in 32 bit
+// words of host byte order, which is little endian on the platforms Aura
+// runs on.
+func procAddress(text string) (string, error) {
+	raw, err := hex.DecodeString(text)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", fmt.Errorf("invalid address %q", text)
+	}
+	for i := 0; i < len(raw); i += 4 {
+		slices.Reverse(raw[i : i+4])
+	}
+	return net.IP(raw).String(), nil
+}
+
+// listLsof lists the listening ports with lsof.
+func listLsof(ctx context.Context) ([]Listener, error) {
+	output, err := exec.CommandContext(ctx, "lsof", "-nP", "-iTCP", "-sTCP:LISTEN", "-F", "pcn").Output()
+	// lsof exits with 1 when nothing matches.
+	if err != nil && len(output) == 0 {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to run lsof: %w", err)
+	}
+	return parseLsof(output), nil
+}
+
+// parseLsof parses the field output of lsof -F pcn: a p line with the PID
+// and a c line with the command for each process, followed by an n line
+// with the address of each of its sockets.
+func parseLsof(output []byte) []Listener {
+	var listeners []Listener
+	var pid int
+	var process string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line == "" {
+			continue
+		}
+		value := strings.TrimSpace(line[1:])
+		switch line[0] {
+		case 'p':
+			pid, _ = strconv.Atoi(value)
+			process = ""
+		case 'c':
+			process = value
+		case 'n':
+			if listener, ok := parseAddress(value); ok {
+				listener.PID, listener.Process = pid, process
+				listeners = append(listeners, listener)
+			}
+		}
+	}
+	return listeners
+}
+
+// listWindows lists the listening ports with netstat and names their
+// processes with tasklist.
+func listWindows(ctx context.Context) ([]Listener, error) {
+	output, err := exec.CommandContext(ctx, "netstat", "-ano", "-p", "TCP").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run netstat: %w", err)
+	}
+	listeners := parseNetstat(output)
+	if v6, err := exec.CommandContext(ctx, "netstat", "-ano", "-p", "TCPv6").Output(); err == nil {
+		listeners = append(listeners, parseNetstat(v6)...)
+	}
+
+	if tasks, err := exec.CommandContext(ctx, "tasklist", "/FO", "CSV", "/NH").Output(); err == nil {
+		names := parseTasklist(bytes.NewReader(tasks))
+		for i := range listeners {
+			listeners[i].Process = names[listeners[i].PID]
+		}
+	}
+	return listeners, nil
+}
+
+// parseNetstat parses the listening sockets of netstat -ano. They are told
+// by their remote port 0 rather than their state, whose name is
+// translated.
+func parseNetstat(output []byte) []Listener {
+	var listeners []Listener
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 || !strings.HasPrefix(fields[0], "TCP") || !strings.HasSuffix(fields[2], ":0") {
+			continue
+		}
+		listener, ok := parseAddress(fields[1])
+		if !ok {
+			continue
+		}
+		listener.PID, _ = strconv.Atoi(fields[4])
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// parseTasklist parses the process names of tasklist /FO CSV /NH by PID.
+func parseTasklist(r io.Reader) map[int]string {
+	names := map[int]string{}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			break
+		}
+		if len(record) < 2 {
+			continue
+		}
+		if pid, err := strconv.Atoi(record[1]); err == nil {
+			names[pid] = record[0]
+		}
+	}
+	return names
+}
+
+// parseAddress parses a socket address such as "127.0.0.1:3000",
+// "[::1]:3000" or "*:3000".
+func parseAddress(text string) (Listener, bool) {
+	i := strings.LastIndex(text, ":")
+	if i < 0 {
+		return Listener{}, false
+	}
+	port, err := strconv.Atoi(text[i+1:])
+	if err != nil || port <= 0 || port > 65535 {
+		return Listener{}, false
+	}
+	address := strings.Trim(text[:i], "[]")
+	if address == "*" {
+		address = "0.0.0.0"
+	}
+	return Listener{Port: port, Address: address}, true
+}
+
+// conflictLine matches lines reporting that a port is taken, as written by
+// Node.js, Go, Python, Vite, Docker and Windows.
+var conflictLine = regexp.MustCompile(`(?i)address already in use|EADDRINUSE|is already in use|only one usage of each socket address|port is already allocated`)
+
+// portPatterns find the port in a conflict line: "port 3000", or a port
+// after an address or at the start of a word, such as ":::3000",
+// "127.0.0.1:8000" or "tcp :8080", but not in times such as 12:30.
+var portPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bport\s+(\d{1,5})\b`),
+	regexp.MustCompile(`(?:localhost|\d+\.\d+\.\d+\.\d+|\]|::|\*|^|\s):(\d{1,5})\b`),
+}
+
+// conflictLookback is how many lines before a conflict are searched for its
+// port, for messages like Django's "That port is already in use." after
+// the address it tried.
+const conflictLookback = 5
+
+// Conflict returns the port a command failed to listen on because it was
+// taken, found in the output of the command.
+func Conflict(output string) (int, bool) {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if !conflictLine.MatchString(line) {
+			continue
+		}
+		for j := i; j >= 0 && j >= i-conflictLookback; j-- {
+			if port, ok := findPort(lines[j]); ok {
+				return port, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// findPort returns the first port in line.
+func findPort(line string) (int, bool) {
+	for _, pattern := range portPatterns {
+		for _, match := range pattern.FindAllStringSubmatch(line, -1) {
+			if port, err := strconv.Atoi(match[1]); err == nil && port > 0 && port <= 65535 {
+				return port, true
+			}
+		}
+	}
+	return 0, false
+}
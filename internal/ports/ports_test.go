@@ -0,0 +1,157 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const procTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4343 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:1F90 0100007F:D2F0 01 00000000:00000000 00:00000000 00000000  1000        0 4444 1 0000000000000000 20 4 30 10 -1
+`
+
+const procTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:1435 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 5151 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:0BB8 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0000000000000000 100 0 0 10 0
+`
+
+func TestParseProcNet(t *testing.T) {
+	sockets, err := parseProcNet([]byte(procTCP))
+	if err != nil {
+		t.Fatalf("parseProcNet() error = %v", err)
+	}
+	want := []procSocket{
+		{Listener: Listener{Port: 3000, Address: "0.0.0.0"}, inode: "4242"},
+		{Listener: Listener{Port: 8080, Address: "127.0.0.1"}, inode: "4343"},
+	}
+	if !reflect.DeepEqual(sockets, want) {
+		t.Errorf("parseProcNet() = %+v, want %+v", sockets, want)
+	}
+
+	sockets, err = parseProcNet([]byte(procTCP6))
+	if err != nil {
+		t.Fatalf("parseProcNet() of tcp6 error = %v", err)
+	}
+	if len(sockets) != 2 || sockets[0].Address != "::" || sockets[0].Port != 5173 || sockets[1].Address != "::1" {
+		t.Errorf("parseProcNet() of tcp6 = %+v", sockets)
+	}
+}
+
+func TestListProc(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{"net/tcp": procTCP, "net/tcp6": procTCP6, "4242/comm": "node\n", "77/comm": "sleep\n"}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{"4242/fd/3": "socket:[4242]", "4242/fd/4": "/dev/null", "77/fd/0": "socket:[9999]"}
+	for name, target := range links {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("symbolic links are not supported: %v", err)
+		}
+	}
+
+	listeners, err := listProc(root)
+	if err != nil {
+		t.Fatalf("listProc() error = %v", err)
+	}
+	want := []Listener{
+		{Port: 3000, Address: "0.0.0.0", PID: 4242, Process: "node"},
+		{Port: 8080, Address: "127.0.0.1"},
+		{Port: 5173, Address: "::"},
+		{Port: 3000, Address: "::1", PID: 4242, Process: "node"},
+	}
+	if !reflect.DeepEqual(listeners, want) {
+		t.Errorf("listProc() = %+v, want %+v", listeners, want)
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	output := "p812\ncnode\nf23\nn*:3000\nf24\nn[::1]:3000\np90\ncpostgres\nf7\nn127.0.0.1:5432\n"
+	want := []Listener{
+		{Port: 3000, Address: "0.0.0.0", PID: 812, Process: "node"},
+		{Port: 3000, Address: "::1", PID: 812, Process: "node"},
+		{Port: 5432, Address: "127.0.0.1", PID: 90, Process: "postgres"},
+	}
+	if got := parseLsof([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLsof() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseNetstat(t *testing.T) {
+	output := `
+Active Connections
+
+  Proto  Local Address          Foreign Address        State           PID
+  TCP    0.0.0.0:135            0.0.0.0:0              LISTENING       1044
+  TCP    127.0.0.1:3000         0.0.0.0:0              ABHÖREN         7312
+  TCP    127.0.0.1:3000         127.0.0.1:52100        ESTABLISHED     7312
+  TCP    [::]:5173              [::]:0                 LISTENING       880
+`
+	want := []Listener{
+		{Port: 135, Address: "0.0.0.0", PID: 1044},
+		{Port: 3000, Address: "127.0.0.1", PID: 7312},
+		{Port: 5173, Address: "::", PID: 880},
+	}
+	if got := parseNetstat([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNetstat() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTasklist(t *testing.T) {
+	output := `"svchost.exe","1044","Services","0","12,345 K"
+"node.exe","7312","Console","1","45,000 K"
+`
+	want := map[int]string{1044: "svchost.exe", 7312: "node.exe"}
+	if got := parseTasklist(strings.NewReader(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTasklist() = %v, want %v", got, want)
+	}
+}
+
+func TestConflict(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int
+	}{
+		{"node", "node:events:497\n      throw er;\nError: listen EADDRINUSE: address already in use :::3000\n    at Server.setupListenHandle", 3000},
+		{"go", "2026/10/16 12:30:45 listen tcp :8080: bind: address already in use\nexit status 1", 8080},
+		{"vite", "error when starting dev server:\nError: Port 5173 is already in use", 5173},
+		{"docker", "Error response from daemon: driver failed programming external connectivity: Bind for 0.0.0.0:5432 failed: port is already allocated", 5432},
+		{"windows", "listen tcp 127.0.0.1:9000: bind: Only one usage of each socket address (protocol/network address/port) is normally permitted.", 9000},
+		{"django", "Starting development server at http://127.0.0.1:8000/\nQuit the server with CONTROL-C.\nError: That port is already in use.", 8000},
+		{"no port", "12:30:45 OSError: [Errno 98] Address already in use", 0},
+		{"other failure", "Error: listen on :3000 failed: permission denied", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Conflict(tt.output)
+			if got != tt.want || ok != (tt.want != 0) {
+				t.Errorf("Conflict() = %d, %v, want %d", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnPort(t *testing.T) {
+	listeners := []Listener{{Port: 22}, {Port: 3000, PID: 1}, {Port: 3000, PID: 2}}
+	if got := OnPort(listeners, 3000); len(got) != 2 || got[0].PID != 1 {
+		t.Errorf("OnPort() = %+v", got)
+	}
+	if got := OnPort(listeners, 80); got != nil {
+		t.Errorf("OnPort() of a free port = %+v", got)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package ports
+
+import "syscall"
+
+// Stop asks the process pid to terminate, or kills it if force is set.
+func Stop(pid int, force bool) error {
+	signal := syscall.SIGTERM
+	if force {
+		signal = syscall.SIGKILL
+	}
+	return syscall.Kill(pid, signal)
+}
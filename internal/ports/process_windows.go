@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// Stop asks the process pid and the processes it started to terminate, or
+// kills them if force is set.
+func Stop(pid int, force bool) error {
+	args := []string{"/T", "/PID", strconv.Itoa(pid)}
+	if force {
+		args = append(args, "/F")
+	}
+	return exec.Command("taskkill", args...).Run()
+}
@@ -0,0 +1,188 @@
+// Package progress shows spinners for operations of unknown length and step
+// counters for multi-step flows. Animations are only drawn on terminals;
+// other output, such as a pipe or a log file, gets plain lines instead.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// unicodeFrames animate spinners on terminals that can display them.
+var unicodeFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// asciiFrames animate spinners on the legacy Windows console, which lacks the
+// braille characters.
+var asciiFrames = []string{"|", "/", "-", `\`}
+
+// Output is where progress is written: standard error, so the output of
+// commands can be piped without it.
+var Output io.Writer = os.Stderr
+
+// isTerminal reports whether w is a terminal that can be animated.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// UnicodeSupported reports whether the terminal displays characters beyond
+// ASCII. On Windows only Windows Terminal and VS Code are known to.
+func UnicodeSupported() bool {
+	if !platform.Current().IsWindows() {
+		return true
+	}
+	return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") == "vscode"
+}
+
+// Spinner animates a message until it is stopped.
+type Spinner struct {
+	w        io.Writer
+	message  string
+	frames   []string
+	animated bool
+	started  time.Time
+
+	mu      sync.Mutex
+	width   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewSpinner returns a spinner showing message on Output.
+func NewSpinner(message string) *Spinner {
+	frames := unicodeFrames
+	if !UnicodeSupported() {
+		frames = asciiFrames
+	}
+	return &Spinner{w: Output, message: message, frames: frames, animated: isTerminal(Output)}
+}
+
+// Start shows the spinner. Without a terminal the message is written once.
+func (s *Spinner) Start() *Spinner {
+	s.started = time.Now()
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+
+	if !s.animated {
+		fmt.Fprintf(s.w, "%s...\n", s.message)
+		close(s.stopped)
+		return s
+	}
+
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
+		for i := 0; ; i++ {
+			s.draw(s.frames[i%len(s.frames)])
+			select {
+			case <-s.stop:
+				s.clear()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Update changes the message of a running spinner.
+func (s *Spinner) Update(message string) {
+	s.mu.Lock()
+	s.message = message
+	s.mu.Unlock()
+	if !s.animated {
+		fmt.Fprintf(s.w, "%s...\n", message)
+	}
+}
+
+// Stop removes the spinner. It may be called more than once.
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.stopped
+}
+
+// Elapsed returns how long the spinner has been running.
+func (s *Spinner) Elapsed() time.Duration {
+	return time.Since(s.started)
+}
+
+func (s *Spinner) draw(frame string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := fmt.Sprintf("%s %s", s.message, frame)
+	// Pad over the previous line instead of using escape sequences, which the
+	// legacy Windows console doesn't support
+	padding := ""
+	if width := len([]rune(line)); width < s.width {
+		padding = strings.Repeat(" ", s.width-width)
+	} else {
+		s.width = width
+	}
+	fmt.Fprintf(s.w, "\r%s%s", line, padding)
+}
+
+func (s *Spinner) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, "\r%s\r", strings.Repeat(" ", s.width))
+}
+
+// Steps counts through the steps of a multi-step flow, such as generating a
+// project. Each step is written on its own line, so output of the steps
+// themselves stays readable.
+type Steps struct {
+	w        io.Writer
+	total    int
+	current  int
+	animated bool
+	unicode  bool
+}
+
+// NewSteps returns a counter of total steps writing to Output.
+func NewSteps(total int) *Steps {
+	return &Steps{w: Output, total: total, animated: isTerminal(Output), unicode: UnicodeSupported()}
+}
+
+// Next starts the next step.
+func (s *Steps) Next(name string) {
+	s.current++
+	if s.animated {
+		fmt.Fprintf(s.w, "%s %d/%d %s\n", Bar(s.current, s.total, 12, s.unicode), s.current, s.total, name)
+		return
+	}
+	fmt.Fprintf(s.w, "[%d/%d] %s\n", s.current, s.total, name)
+}
+
+// Bar renders a progress bar of width cells for current of total.
+func Bar(current, total, width int, unicode bool) string {
+	filled := width
+	if total > 0 && current < total {
+		filled = width * current / total
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	full, empty := "█", "░"
+	if !unicode {
+		full, empty = "#", "-"
+	}
+	return "[" + strings.Repeat(full, filled) + strings.Repeat(empty, width-filled) + "]"
+}
@@ -0,0 +1,68 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpinnerWithoutTerminal(t *testing.T) {
+	var out bytes.Buffer
+	spinner := &Spinner{w: &out, message: "Thinking", frames: asciiFrames}
+	spinner.Start()
+	spinner.Update("Still thinking")
+	spinner.Stop()
+	spinner.Stop()
+
+	if out.String() != "Thinking...\nStill thinking...\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestSpinnerAnimated(t *testing.T) {
+	var out bytes.Buffer
+	spinner := &Spinner{w: &out, message: "Working", frames: asciiFrames, animated: true}
+	spinner.Start()
+	time.Sleep(150 * time.Millisecond)
+	spinner.Stop()
+
+	output := out.String()
+	if !strings.HasPrefix(output, "\rWorking |") || !strings.HasSuffix(output, "\r") {
+		t.Errorf("Unexpected output %q", output)
+	}
+	if spinner.Elapsed() < 150*time.Millisecond {
+		t.Errorf("Elapsed() = %v", spinner.Elapsed())
+	}
+}
+
+func TestSteps(t *testing.T) {
+	var out bytes.Buffer
+	steps := &Steps{w: &out, total: 2}
+	steps.Next("Render files")
+	steps.Next("Initialize Git")
+
+	if out.String() != "[1/2] Render files\n[2/2] Initialize Git\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		current, total int
+		unicode        bool
+		expected       string
+	}{
+		{0, 4, false, "[--------]"},
+		{1, 4, false, "[##------]"},
+		{4, 4, true, "[████████]"},
+		{5, 4, false, "[########]"},
+		{1, 0, false, "[########]"},
+	}
+
+	for _, tt := range tests {
+		if got := Bar(tt.current, tt.total, 8, tt.unicode); got != tt.expected {
+			t.Errorf("Bar(%d, %d) = %s, want %s", tt.current, tt.total, got, tt.expected)
+		}
+	}
+}
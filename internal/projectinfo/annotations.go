@@ -0,0 +1,122 @@
+package projectinfo
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AnnotationKinds are the kinds of annotations ScanAnnotations finds.
+var AnnotationKinds = []string{"TODO", "FIXME", "HACK"}
+
+// annotationPattern matches annotations such as "// TODO: flags" or
+// "# FIXME(ana) racy", capturing the kind and the text after it.
+var annotationPattern = regexp.MustCompile(`\b(TODO|FIXME|HACK)\b(?:\([^)]*\))?:?\s*(.*)`)
+
+// commentEnd matches the ends of block comments after an annotation's text.
+var commentEnd = regexp.MustCompile(`\s*(\*/|-->|#\})\s*$`)
+
+// Annotation is a TODO, FIXME or HACK comment in a source file.
+type Annotation struct {
+	// File is the slash separated path relative to the project root.
+	File string
+	Line int
+	Kind string
+	Text string
+	// Author is the author of the line by git blame, if known.
+	Author string
+}
+
+// ScanAnnotations returns the annotations in files, relative to root, in the
+// order of the files and lines. Files of unknown languages, binary and very
+// large files are left out.
+func ScanAnnotations(root string, files []string) []Annotation {
+	var annotations []Annotation
+	for _, file := range files {
+		if Language(file) == "" {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(file))
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileSize {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(content, 0) >= 0 {
+			continue
+		}
+		annotations = append(annotations, parseAnnotations(file, content)...)
+	}
+	return annotations
+}
+
+// parseAnnotations returns the annotations in the content of file.
+func parseAnnotations(file string, content []byte) []Annotation {
+	var annotations []Annotation
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		match := annotationPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		annotations = append(annotations, Annotation{
+			File: file,
+			Line: line,
+			Kind: match[1],
+			Text: strings.TrimSpace(commentEnd.ReplaceAllString(match[2], "")),
+		})
+	}
+	return annotations
+}
+
+// Blame sets the authors of annotations from git blame in the repository at
+// root. Lines not committed yet keep no author.
+func Blame(root string, annotations []Annotation) {
+	byFile := map[string][]int{}
+	for i, annotation := range annotations {
+		byFile[annotation.File] = append(byFile[annotation.File], i)
+	}
+
+	for file, indexes := range byFile {
+		blame := exec.Command("git", "blame", "--line-porcelain", "--", file)
+		blame.Dir = root
+		output, err := blame.Output()
+		if err != nil {
+			continue
+		}
+		authors := parseBlameAuthors(string(output))
+		for _, i := range indexes {
+			annotations[i].Author = authors[annotations[i].Line]
+		}
+	}
+}
+
+// parseBlameAuthors maps the line numbers in the output of
+// `git blame --line-porcelain` to their authors.
+func parseBlameAuthors(output string) map[int]string {
+	authors := map[int]string{}
+	line := 0
+	for _, row := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(row, "\t"):
+			line = 0
+		case line == 0:
+			// Header of an entry: <sha> <original line> <final line> [<count>]
+			fields := strings.Fields(row)
+			if len(fields) >= 3 && len(fields[0]) >= 40 {
+				line, _ = strconv.Atoi(fields[2])
+			}
+		case strings.HasPrefix(row, "author "):
+			if author := strings.TrimPrefix(row, "author "); author != "Not Committed Yet" {
+				authors[line] = author
+			}
+		}
+	}
+	return authors
+}
@@ -0,0 +1,53 @@
+package projectinfo
+
+import "testing"
+
+func TestParseAnnotations(t *testing.T) {
+	content := "package main\n" +
+		"// TODO: parse flags\n" +
+		"x := 1 // FIXME(ana) racy under load\n" +
+		"/* HACK work around the proxy */\n" +
+		"// TODOS are not annotations\n" +
+		"# TODO\n"
+
+	want := []Annotation{
+		{File: "main.go", Line: 2, Kind: "TODO", Text: "parse flags"},
+		{File: "main.go", Line: 3, Kind: "FIXME", Text: "racy under load"},
+		{File: "main.go", Line: 4, Kind: "HACK", Text: "work around the proxy"},
+		{File: "main.go", Line: 6, Kind: "TODO", Text: ""},
+	}
+	got := parseAnnotations("main.go", []byte(content))
+	if len(got) != len(want) {
+		t.Fatalf("parseAnnotations() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseAnnotations()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseBlameAuthors(t *testing.T) {
+	output := "1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d 1 1 2\n" +
+		"author Ana\n" +
+		"author-mail <ana@example.com>\n" +
+		"summary Initial\n" +
+		"filename main.go\n" +
+		"\tpackage main\n" +
+		"1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d 2 2\n" +
+		"author Ana\n" +
+		"filename main.go\n" +
+		"\t// TODO: flags\n" +
+		"0000000000000000000000000000000000000000 3 3 1\n" +
+		"author Not Committed Yet\n" +
+		"filename main.go\n" +
+		"\t// FIXME: racy\n"
+
+	authors := parseBlameAuthors(output)
+	if authors[1] != "Ana" || authors[2] != "Ana" {
+		t.Errorf("parseBlameAuthors() = %v", authors)
+	}
+	if author, ok := authors[3]; ok {
+		t.Errorf("Uncommitted line has author %q", author)
+	}
+}
@@ -0,0 +1,304 @@
+// Package projectinfo gathers statistics about the source code of a project:
+// lines per language, TODO and FIXME markers and the number of
+// dependencies declared in its manifests.
+package projectinfo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/index"
+)
+
+// maxFileSize is the size above which files are not counted, as they are
+// rarely source code.
+const maxFileSize = 2 << 20
+
+// skippedDirs are not walked outside Git repositories.
+var skippedDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, ".venv": true, "venv": true,
+	"__pycache__": true, "dist": true, "build": true, "target": true,
+}
+
+// languages maps file extensions and names to languages.
+var languages = map[string]string{
+	".go": "Go", ".py": "Python", ".js": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript",
+	".jsx": "JavaScript", ".ts": "TypeScript", ".tsx": "TypeScript", ".rs": "Rust", ".java": "Java",
+	".kt": "Kotlin", ".c": "C", ".h": "C", ".cc": "C++", ".cpp": "C++", ".hpp": "C++", ".cs": "C#",
+	".rb": "Ruby", ".php": "PHP", ".swift": "Swift", ".scala": "Scala", ".lua": "Lua", ".dart": "Dart",
+	".sh": "Shell", ".bash": "Shell", ".zsh": "Shell", ".fish": "Shell", ".ps1": "PowerShell",
+	".sql": "SQL", ".html": "HTML", ".css": "CSS", ".scss": "CSS", ".vue": "Vue", ".svelte": "Svelte",
+	".md": "Markdown", ".yaml": "YAML", ".yml": "YAML", ".json": "JSON", ".toml": "TOML", ".xml": "XML",
+	".tf": "Terraform", ".proto": "Protocol Buffers",
+	"Dockerfile": "Dockerfile", "Makefile": "Makefile",
+}
+
+// markerPattern matches TODO and FIXME comments.
+var markerPattern = regexp.MustCompile(`\b(TODO|FIXME)\b`)
+
+// Language returns the language of the file at path, or "" if it is unknown.
+func Language(path string) string {
+	name := filepath.Base(path)
+	if language, ok := languages[name]; ok {
+		return language
+	}
+	return languages[strings.ToLower(filepath.Ext(name))]
+}
+
+// LanguageStats counts the files and lines of a language.
+type LanguageStats struct {
+	Language string
+	Files    int
+	Lines    int
+}
+
+// Stats are the statistics of the source code of a project.
+type Stats struct {
+	// Languages are sorted by lines, the most first.
+	Languages []LanguageStats
+	Todos     int
+	Fixmes    int
+}
+
+// ListFiles returns the files of the project in root, relative to it: the
+// files Git tracks, or outside a repository the files not ignored by the
+// .gitignore of root nor in directories such as node_modules.
+func ListFiles(root string) ([]string, error) {
+	gitFiles := exec.Command("git", "ls-files", "-z")
+	gitFiles.Dir = root
+	if output, err := gitFiles.Output(); err == nil {
+		var files []string
+		for _, file := range strings.Split(string(output), "\x00") {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+		return files, nil
+	}
+
+	gitignore := index.ReadIgnoreRules(root)
+	var files []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if skippedDirs[entry.Name()] || gitignore.Ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() && !gitignore.Ignored(rel, false) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// Count counts the lines and markers of files, relative to root. Files of
+// unknown languages, binary and very large files are left out.
+func Count(root string, files []string) Stats {
+	byLanguage := map[string]*LanguageStats{}
+	var stats Stats
+	for _, file := range files {
+		language := Language(file)
+		if language == "" {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(file))
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileSize {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(content, 0) >= 0 {
+			continue
+		}
+
+		entry := byLanguage[language]
+		if entry == nil {
+			entry = &LanguageStats{Language: language}
+			byLanguage[language] = entry
+		}
+		entry.Files++
+		entry.Lines += countLines(content)
+
+		for _, match := range markerPattern.FindAll(content, -1) {
+			if string(match) == "TODO" {
+				stats.Todos++
+			} else {
+				stats.Fixmes++
+			}
+		}
+	}
+
+	for _, entry := range byLanguage {
+		stats.Languages = append(stats.Languages, *entry)
+	}
+	sort.Slice(stats.Languages, func(i, j int) bool {
+		if stats.Languages[i].Lines != stats.Languages[j].Lines {
+			return stats.Languages[i].Lines > stats.Languages[j].Lines
+		}
+		return stats.Languages[i].Language < stats.Languages[j].Language
+	})
+	return stats
+}
+
+// countLines counts the lines of content, including a last line without a
+// newline.
+func countLines(content []byte) int {
+	lines := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// Dependencies is the number of dependencies declared in a manifest.
+type Dependencies struct {
+	// Manifest is the file name, such as package.json.
+	Manifest string
+	Direct   int
+	// Dev counts development dependencies, or indirect ones for go.mod.
+	Dev int
+}
+
+// manifestParsers count the dependencies of the manifests they are keyed by.
+var manifestParsers = map[string]func(content []byte) (direct, dev int){
+	"package.json":     countPackageJSON,
+	"composer.json":    countComposerJSON,
+	"go.mod":           countGoMod,
+	"requirements.txt": countRequirements,
+	"Cargo.toml":       countCargoToml,
+	"Gemfile":          countGemfile,
+}
+
+// CountDependencies returns the dependencies of the manifests in root, in
+// the order of their names.
+func CountDependencies(root string) []Dependencies {
+	var manifests []string
+	for manifest := range manifestParsers {
+		manifests = append(manifests, manifest)
+	}
+	sort.Strings(manifests)
+
+	var result []Dependencies
+	for _, manifest := range manifests {
+		content, err := os.ReadFile(filepath.Join(root, manifest))
+		if err != nil {
+			continue
+		}
+		direct, dev := manifestParsers[manifest](content)
+		result = append(result, Dependencies{Manifest: manifest, Direct: direct, Dev: dev})
+	}
+	return result
+}
+
+func countPackageJSON(content []byte) (direct, dev int) {
+	var manifest struct {
+		Dependencies    map[string]any `json:"dependencies"`
+		DevDependencies map[string]any `json:"devDependencies"`
+	}
+	if json.Unmarshal(content, &manifest) != nil {
+		return 0, 0
+	}
+	return len(manifest.Dependencies), len(manifest.DevDependencies)
+}
+
+func countComposerJSON(content []byte) (direct, dev int) {
+	var manifest struct {
+		Require    map[string]any `json:"require"`
+		RequireDev map[string]any `json:"require-dev"`
+	}
+	if json.Unmarshal(content, &manifest) != nil {
+		return 0, 0
+	}
+	return len(manifest.Require), len(manifest.RequireDev)
+}
+
+// countGoMod counts the required modules, the ones marked indirect as dev.
+func countGoMod(content []byte) (direct, indirect int) {
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		var requirement string
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+			requirement = line
+		case strings.HasPrefix(line, "require "):
+			requirement = strings.TrimPrefix(line, "require ")
+		}
+		if requirement == "" || strings.HasPrefix(requirement, "//") {
+			continue
+		}
+		if strings.Contains(requirement, "// indirect") {
+			indirect++
+		} else {
+			direct++
+		}
+	}
+	return direct, indirect
+}
+
+func countRequirements(content []byte) (direct, dev int) {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "-") {
+			direct++
+		}
+	}
+	return direct, 0
+}
+
+// countCargoToml counts the entries of the dependency tables.
+func countCargoToml(content []byte) (direct, dev int) {
+	var counter *int
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			switch line {
+			case "[dependencies]":
+				counter = &direct
+			case "[dev-dependencies]":
+				counter = &dev
+			default:
+				counter = nil
+			}
+			continue
+		}
+		if counter != nil && strings.Contains(line, "=") && !strings.HasPrefix(line, "#") {
+			*counter++
+		}
+	}
+	return direct, dev
+}
+
+func countGemfile(content []byte) (direct, dev int) {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "gem ") {
+			direct++
+		}
+	}
+	return direct, 0
+}
@@ -0,0 +1,111 @@
+package projectinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLanguage(t *testing.T) {
+	for path, want := range map[string]string{
+		"main.go":              "Go",
+		"web/App.TSX":          "TypeScript",
+		"deploy/Dockerfile":    "Dockerfile",
+		"scripts/install.sh":   "Shell",
+		"assets/logo.png":      "",
+		"LICENSE":              "",
+		"config/app.yml":       "YAML",
+		"internal/db/init.sql": "SQL",
+	} {
+		if got := Language(path); got != want {
+			t.Errorf("Language(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":       "package main\n\n// TODO: flags\nfunc main() {}\n",
+		"util.go":       "package main\n// FIXME: racy\n// TODO(me): tests",
+		"web/app.js":    "console.log(1)\n",
+		"image.png":     "\x89PNG\x00\x00",
+		"data/blob.sql": "SELECT 1;\x00",
+	}
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(content), 0o644)
+		paths = append(paths, name)
+	}
+
+	stats := Count(root, paths)
+	if len(stats.Languages) != 2 {
+		t.Fatalf("Count() languages = %+v, want Go and JavaScript", stats.Languages)
+	}
+	if stats.Languages[0] != (LanguageStats{Language: "Go", Files: 2, Lines: 7}) {
+		t.Errorf("Languages[0] = %+v", stats.Languages[0])
+	}
+	if stats.Languages[1] != (LanguageStats{Language: "JavaScript", Files: 1, Lines: 1}) {
+		t.Errorf("Languages[1] = %+v", stats.Languages[1])
+	}
+	if stats.Todos != 2 || stats.Fixmes != 1 {
+		t.Errorf("Count() markers = %d TODO, %d FIXME, want 2 and 1", stats.Todos, stats.Fixmes)
+	}
+}
+
+func TestListFilesWithoutGit(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(root))
+	for _, name := range []string{"main.go", "node_modules/lib/index.js", "out/app.bin", "src/app.py"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte("x\n"), 0o644)
+	}
+	os.WriteFile(filepath.Join(root, ".gitignore"), []byte("out/\n"), 0o644)
+
+	files, err := ListFiles(root)
+	if err != nil {
+		t.Fatalf("ListFiles() error = %v", err)
+	}
+	want := map[string]bool{".gitignore": true, "main.go": true, "src/app.py": true}
+	if len(files) != len(want) {
+		t.Fatalf("ListFiles() = %v", files)
+	}
+	for _, file := range files {
+		if !want[file] {
+			t.Errorf("ListFiles() lists %s", file)
+		}
+	}
+}
+
+func TestCountDependencies(t *testing.T) {
+	root := t.TempDir()
+	manifests := map[string]string{
+		"package.json": `{"dependencies": {"react": "^18", "zod": "^3"}, "devDependencies": {"vitest": "^1"}}`,
+		"go.mod": "module example.com/app\n\ngo 1.23\n\nrequire github.com/spf13/cobra v1.8.0\n\n" +
+			"require (\n\tgolang.org/x/term v0.20.0\n\tgithub.com/inconshreveable/mousetrap v1.1.0 // indirect\n)\n",
+		"requirements.txt": "# web\nflask==3.0\n-r dev.txt\nrequests\n",
+		"Cargo.toml":       "[package]\nname = \"app\"\n\n[dependencies]\nserde = \"1\"\n\n[dev-dependencies]\ninsta = \"1\"\n",
+	}
+	for name, content := range manifests {
+		os.WriteFile(filepath.Join(root, name), []byte(content), 0o644)
+	}
+
+	want := []Dependencies{
+		{Manifest: "Cargo.toml", Direct: 1, Dev: 1},
+		{Manifest: "go.mod", Direct: 2, Dev: 1},
+		{Manifest: "package.json", Direct: 2, Dev: 1},
+		{Manifest: "requirements.txt", Direct: 2},
+	}
+	got := CountDependencies(root)
+	if len(got) != len(want) {
+		t.Fatalf("CountDependencies() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CountDependencies()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package query detects the format of JSON and YAML data, outlines its
+// structure and runs jq and yq expressions on it.
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+// Format is the format of the queried data.
+type Format string
+
+const (
+	JSON Format = "JSON"
+	YAML Format = "YAML"
+)
+
+// Tool returns the program that runs queries on data of the format.
+func (f Format) Tool() string {
+	if f == YAML {
+		return "yq"
+	}
+	return "jq"
+}
+
+// Detect returns the format of data and its values: JSON when it is one or
+// more JSON values, such as JSON Lines, YAML when it is a YAML mapping or
+// sequence.
+func Detect(data []byte) (Format, []any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "", nil, failure.New(failure.UserInput, "the input is empty")
+	}
+
+	var values []any
+	jsonDecoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var value any
+		err := jsonDecoder.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			return JSON, values, nil
+		}
+		if err != nil {
+			break
+		}
+		values = append(values, value)
+	}
+
+	values = nil
+	yamlDecoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var value any
+		err := yamlDecoder.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", nil, failure.New(failure.UserInput, "the input is neither JSON nor YAML: %v", err)
+		}
+		switch value.(type) {
+		case map[string]any, map[any]any, []any:
+			values = append(values, value)
+		default:
+			return "", nil, failure.New(failure.UserInput, "the input is neither JSON nor YAML")
+		}
+	}
+	return YAML, values, nil
+}
+
+// outlinePaths bounds the lines of an outline, and outlineItems the
+// elements of each array that are looked at.
+const (
+	outlinePaths = 200
+	outlineItems = 50
+)
+
+// Outline describes the structure of values as the jq paths found in them
+// with their types, one per line, such as ".users[].name: string". Values
+// themselves are left out.
+func Outline(values []any) string {
+	o := &outline{types: map[string][]string{}}
+	for i, value := range values {
+		if i == outlineItems {
+			break
+		}
+		o.walk(".", value)
+	}
+
+	var b strings.Builder
+	for i, path := range o.paths {
+		if i == outlinePaths {
+			fmt.Fprintf(&b, "... and %d more paths\n", len(o.paths)-outlinePaths)
+			break
+		}
+		fmt.Fprintf(&b, "%s: %s\n", path, strings.Join(o.types[path], " or "))
+	}
+	return b.String()
+}
+
+// outline collects the paths of values in the order they are found.
+type outline struct {
+	paths []string
+	types map[string][]string
+}
+
+// identifier matches keys that jq accepts after a dot.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (o *outline) walk(path string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		o.add(path, "object")
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			o.walk(child(path, key), v[key])
+		}
+	case map[any]any:
+		o.add(path, "object")
+		keys := make([]string, 0, len(v))
+		values := map[string]any{}
+		for key, item := range v {
+			name := fmt.Sprint(key)
+			keys = append(keys, name)
+			values[name] = item
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			o.walk(child(path, key), values[key])
+		}
+	case []any:
+		o.add(path, "array")
+		element := path + "[]"
+		if path == "." {
+			element = ".[]"
+		}
+		for i, item := range v {
+			if i == outlineItems {
+				break
+			}
+			o.walk(element, item)
+		}
+	case string, time.Time:
+		o.add(path, "string")
+	case float64, int, int64, uint64:
+		o.add(path, "number")
+	case bool:
+		o.add(path, "boolean")
+	case nil:
+		o.add(path, "null")
+	default:
+		o.add(path, fmt.Sprintf("%T", v))
+	}
+}
+
+// add records that path holds a value of kind.
+func (o *outline) add(path, kind string) {
+	types, seen := o.types[path]
+	if !seen {
+		o.paths = append(o.paths, path)
+	}
+	for _, t := range types {
+		if t == kind {
+			return
+		}
+	}
+	o.types[path] = append(types, kind)
+}
+
+// child returns the path of key in the object at path.
+func child(path, key string) string {
+	if path == "." {
+		path = ""
+	}
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	if path == "" {
+		path = "."
+	}
+	return path + "[" + string(quoted) + "]"
+}
+
+// Run runs expression on data with the tool of format and returns its
+// output. With raw, jq prints strings without quotes.
+func Run(ctx context.Context, format Format, expression string, raw bool, data []byte) (string, error) {
+	var args []string
+	if raw && format == JSON {
+		args = append(args, "-r")
+	}
+	args = append(args, expression)
+
+	var stdout, stderr bytes.Buffer
+	query := exec.CommandContext(ctx, format.Tool(), args...)
+	query.Stdin = bytes.NewReader(data)
+	query.Stdout = &stdout
+	query.Stderr = &stderr
+	if err := query.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("failed to run %s: %w", format.Tool(), err)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", failure.New(failure.API, "%s failed: %s", format.Tool(), message)
+	}
+	return stdout.String(), nil
+}
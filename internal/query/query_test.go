@@ -0,0 +1,91 @@
+package query
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Format
+		values  int
+		wantErr bool
+	}{
+		{name: "json object", data: `{"a": 1}`, want: JSON, values: 1},
+		{name: "json lines", data: "{\"a\": 1}\n{\"a\": 2}\n", want: JSON, values: 2},
+		{name: "yaml", data: "services:\n  web:\n    image: nginx\n", want: YAML, values: 1},
+		{name: "yaml documents", data: "a: 1\n---\na: 2\n", want: YAML, values: 2},
+		{name: "plain text", data: "hello world\n", wantErr: true},
+		{name: "empty", data: "  \n", wantErr: true},
+		{name: "broken json", data: `{"a": [1, 2}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, values, err := Detect([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if failure.KindOf(err) != failure.UserInput {
+					t.Errorf("Detect() error kind = %v, want UserInput", failure.KindOf(err))
+				}
+				return
+			}
+			if format != tt.want || len(values) != tt.values {
+				t.Errorf("Detect() = %s with %d values, want %s with %d", format, len(values), tt.want, tt.values)
+			}
+		})
+	}
+}
+
+func TestOutline(t *testing.T) {
+	_, values, err := Detect([]byte(`{
+  "total": 2,
+  "users": [
+    {"name": "ada", "admin": true},
+    {"name": "bob", "email": null, "tags": ["x"]}
+  ],
+  "next page": "/users?page=2"
+}`))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	want := `.: object
+.["next page"]: string
+.total: number
+.users: array
+.users[]: object
+.users[].admin: boolean
+.users[].name: string
+.users[].email: null
+.users[].tags: array
+.users[].tags[]: string
+`
+	if got := Outline(values); got != want {
+		t.Errorf("Outline() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq is not installed")
+	}
+	data := []byte(`{"users": [{"name": "ada"}, {"name": "bob"}]}`)
+
+	got, err := Run(context.Background(), JSON, ".users[].name", true, data)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got != "ada\nbob\n" {
+		t.Errorf("Run() = %q, want %q", got, "ada\nbob\n")
+	}
+
+	if _, err := Run(context.Background(), JSON, ".users[", false, data); failure.KindOf(err) != failure.API {
+		t.Errorf("Run() with a broken expression error = %v, want an API error", err)
+	}
+}
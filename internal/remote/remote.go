@@ -0,0 +1,150 @@
+// Package remote handles bookmarks of cloud storage locations such as
+// s3://bucket/prefix: which provider serves them, how to list them with the
+// provider's CLI, whether the CLI is logged in and where they are mounted.
+package remote
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Provider is a cloud storage service.
+type Provider struct {
+	// Scheme is the URI scheme of its locations, such as "s3".
+	Scheme string
+	// Name is the name shown to users.
+	Name string
+	// CLI is the command line tool used to list locations.
+	CLI string
+	// Login tells how to log the CLI in.
+	Login string
+	// check is the command that fails unless the CLI has credentials.
+	check []string
+	// list returns the command listing a location.
+	list func(l Location) []string
+	// console returns the URL of a location in the provider's web console,
+	// if it has one without further settings.
+	console func(l Location) string
+}
+
+// Providers are the supported cloud storage services.
+var Providers = []Provider{
+	{
+		Scheme: "s3", Name: "Amazon S3", CLI: "aws", Login: "aws configure or aws sso login",
+		check: []string{"aws", "sts", "get-caller-identity"},
+		list:  func(l Location) []string { return []string{"aws", "s3", "ls", l.URI()} },
+		console: func(l Location) string {
+			return "https://s3.console.aws.amazon.com/s3/buckets/" + l.Bucket + "?prefix=" + url.QueryEscape(l.Prefix)
+		},
+	},
+	{
+		Scheme: "gs", Name: "Google Cloud Storage", CLI: "gcloud", Login: "gcloud auth login",
+		check: []string{"gcloud", "auth", "print-access-token"},
+		list:  func(l Location) []string { return []string{"gcloud", "storage", "ls", l.URI()} },
+		console: func(l Location) string {
+			return "https://console.cloud.google.com/storage/browser/" + l.Bucket + "/" + l.Prefix
+		},
+	},
+	{
+		Scheme: "az", Name: "Azure Blob Storage", CLI: "az", Login: "az login",
+		check: []string{"az", "account", "show"},
+		list: func(l Location) []string {
+			args := []string{"az", "storage", "blob", "list", "--container-name", l.Bucket, "--delimiter", "/", "--output", "table", "--auth-mode", "login"}
+			if l.Prefix != "" {
+				args = append(args, "--prefix", l.Prefix)
+			}
+			return args
+		},
+		console: func(l Location) string { return "" },
+	},
+}
+
+// Location is a bucket, or Azure container, and a prefix in it.
+type Location struct {
+	Provider Provider
+	Bucket   string
+	// Prefix is the path below the bucket, ending with / unless empty.
+	Prefix string
+}
+
+// Parse parses uri, such as s3://bucket/logs, and reports whether it is the
+// location of a supported provider.
+func Parse(uri string) (Location, bool) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return Location{}, false
+	}
+	for _, provider := range Providers {
+		if strings.EqualFold(scheme, provider.Scheme) {
+			bucket, prefix, _ := strings.Cut(rest, "/")
+			if bucket == "" {
+				return Location{}, false
+			}
+			prefix = strings.Trim(prefix, "/")
+			if prefix != "" {
+				prefix += "/"
+			}
+			return Location{Provider: provider, Bucket: bucket, Prefix: prefix}, true
+		}
+	}
+	return Location{}, false
+}
+
+// IsRemote reports whether path is a cloud storage location.
+func IsRemote(path string) bool {
+	_, ok := Parse(path)
+	return ok
+}
+
+// URI returns the location as a URI, such as s3://bucket/logs/.
+func (l Location) URI() string {
+	return l.Provider.Scheme + "://" + l.Bucket + "/" + l.Prefix
+}
+
+// ListCommand returns the command listing the location.
+func (l Location) ListCommand() []string {
+	return l.Provider.list(l)
+}
+
+// ConsoleURL returns the URL of the location in the provider's web console,
+// or "" if the provider has none without further settings.
+func (l Location) ConsoleURL() string {
+	return l.Provider.console(l)
+}
+
+// CheckCredentials returns an error telling how to fix it if the CLI of the
+// provider is missing or has no credentials.
+func (l Location) CheckCredentials() error {
+	if _, err := exec.LookPath(l.Provider.CLI); err != nil {
+		return fmt.Errorf("%s locations need the %s CLI, which is not installed", l.Provider.Name, l.Provider.CLI)
+	}
+	if err := exec.Command(l.Provider.check[0], l.Provider.check[1:]...).Run(); err != nil {
+		return fmt.Errorf("the %s CLI has no valid credentials, log in with: %s", l.Provider.CLI, l.Provider.Login)
+	}
+	return nil
+}
+
+// MountedPath returns the local directory of the location if a configured
+// mount, mapping a URI prefix such as s3://bucket to a local directory,
+// contains it. The longest matching mount wins.
+func (l Location) MountedPath(mounts map[string]string) (string, bool) {
+	uris := make([]string, 0, len(mounts))
+	for uri := range mounts {
+		uris = append(uris, uri)
+	}
+	sort.Slice(uris, func(i, j int) bool { return len(uris[i]) > len(uris[j]) })
+
+	for _, uri := range uris {
+		mount, ok := Parse(uri)
+		if !ok || mount.Provider.Scheme != l.Provider.Scheme || mount.Bucket != l.Bucket || !strings.HasPrefix(l.Prefix, mount.Prefix) {
+			continue
+		}
+		rest := strings.TrimSuffix(strings.TrimPrefix(l.Prefix, mount.Prefix), "/")
+		return filepath.Join(mounts[uri], filepath.FromSlash(rest)), true
+	}
+	return "", false
+}
@@ -0,0 +1,91 @@
+package remote
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		uri    string
+		ok     bool
+		scheme string
+		bucket string
+		prefix string
+	}{
+		{"s3://logs", true, "s3", "logs", ""},
+		{"s3://logs/2024/app", true, "s3", "logs", "2024/app/"},
+		{"GS://data/raw/", true, "gs", "data", "raw/"},
+		{"az://container/blobs", true, "az", "container", "blobs/"},
+		{"s3://", false, "", "", ""},
+		{"ftp://host/path", false, "", "", ""},
+		{"/home/me/s3", false, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		location, ok := Parse(tt.uri)
+		if ok != tt.ok {
+			t.Errorf("Parse(%q) ok = %v, want %v", tt.uri, ok, tt.ok)
+			continue
+		}
+		if ok && (location.Provider.Scheme != tt.scheme || location.Bucket != tt.bucket || location.Prefix != tt.prefix) {
+			t.Errorf("Parse(%q) = %s %s %q", tt.uri, location.Provider.Scheme, location.Bucket, location.Prefix)
+		}
+	}
+}
+
+func TestListCommand(t *testing.T) {
+	tests := map[string]string{
+		"s3://logs/app":  "aws s3 ls s3://logs/app/",
+		"gs://data":      "gcloud storage ls gs://data/",
+		"az://blobs/raw": "az storage blob list --container-name blobs --delimiter / --output table --auth-mode login --prefix raw/",
+	}
+	for uri, want := range tests {
+		location, _ := Parse(uri)
+		if got := strings.Join(location.ListCommand(), " "); got != want {
+			t.Errorf("ListCommand(%s) = %s, want %s", uri, got, want)
+		}
+	}
+}
+
+func TestMountedPath(t *testing.T) {
+	mounts := map[string]string{
+		"s3://logs":         "/mnt/logs",
+		"s3://logs/archive": "/mnt/archive",
+		"gs://data":         "/mnt/data",
+	}
+
+	tests := []struct {
+		uri  string
+		want string
+		ok   bool
+	}{
+		{"s3://logs", "/mnt/logs", true},
+		{"s3://logs/app/2024", filepath.Join("/mnt/logs", "app", "2024"), true},
+		{"s3://logs/archive/old", filepath.Join("/mnt/archive", "old"), true},
+		{"s3://other", "", false},
+		{"gs://logs", "", false},
+	}
+	for _, tt := range tests {
+		location, _ := Parse(tt.uri)
+		got, ok := location.MountedPath(mounts)
+		if ok != tt.ok || got != filepath.FromSlash(tt.want) {
+			t.Errorf("MountedPath(%s) = %q, %v, want %q, %v", tt.uri, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestConsoleURL(t *testing.T) {
+	tests := map[string]string{
+		"s3://logs/app": "https://s3.console.aws.amazon.com/s3/buckets/logs?prefix=app%2F",
+		"gs://data":     "https://console.cloud.google.com/storage/browser/data/",
+		"az://blobs":    "",
+	}
+	for uri, want := range tests {
+		location, _ := Parse(uri)
+		if got := location.ConsoleURL(); got != want {
+			t.Errorf("ConsoleURL(%s) = %s, want %s", uri, got, want)
+		}
+	}
+}
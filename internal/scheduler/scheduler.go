@@ -0,0 +1,173 @@
+// Package scheduler installs commands in the scheduler of the system: the
+// crontab of the user, or the Task Scheduler on Windows. Entries are named
+// so they can be replaced and removed again.
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/cron"
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// Entry is a command run by the scheduler of the system.
+type Entry struct {
+	Name     string
+	Schedule cron.Schedule
+	// Command is the program and its arguments.
+	Command []string
+	// Env holds KEY=value variables set for the command in the crontab.
+	// Tasks run with the environment of the user.
+	Env []string
+}
+
+// taskFolder is the Task Scheduler folder of the tasks.
+const taskFolder = `Aura\`
+
+// Install adds entry to the scheduler of the system, replacing any entry
+// with the same name.
+func Install(entry Entry) error {
+	if platform.Current().IsWindows() {
+		trigger, err := entry.Schedule.TaskScheduler()
+		if err != nil {
+			return err
+		}
+		args := append([]string{"/Create", "/F", "/TN", taskFolder + entry.Name, "/TR", taskCommand(entry.Command)}, trigger...)
+		return runTool("schtasks", args...)
+	}
+
+	crontab, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	return writeCrontab(updateCrontab(crontab, entry.Name, crontabLine(entry)))
+}
+
+// Uninstall removes the entry called name from the scheduler of the system,
+// if there is one.
+func Uninstall(name string) error {
+	if platform.Current().IsWindows() {
+		if exec.Command("schtasks", "/Query", "/TN", taskFolder+name).Run() != nil {
+			return nil
+		}
+		return runTool("schtasks", "/Delete", "/F", "/TN", taskFolder+name)
+	}
+
+	crontab, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	updated := updateCrontab(crontab, name, "")
+	if updated == crontab {
+		return nil
+	}
+	return writeCrontab(updated)
+}
+
+// marker returns the comment ending the crontab line of the entry called
+// name.
+func marker(name string) string {
+	return "# aura schedule " + name
+}
+
+// crontabLine returns the crontab line running entry, discarding its output
+// and tagged with its marker. Percent signs are escaped, cron turns them
+// into newlines.
+func crontabLine(entry Entry) string {
+	var words []string
+	for _, variable := range entry.Env {
+		key, value, _ := strings.Cut(variable, "=")
+		words = append(words, key+"="+shellQuote(value))
+	}
+	for _, arg := range entry.Command {
+		words = append(words, shellQuote(arg))
+	}
+	command := strings.ReplaceAll(strings.Join(words, " "), "%", `\%`)
+	return fmt.Sprintf("%s %s >/dev/null 2>&1 %s", entry.Schedule.Expression, command, marker(entry.Name))
+}
+
+// updateCrontab returns crontab with the line of the entry called name
+// replaced by line, or removed if line is empty. A new line is appended.
+func updateCrontab(crontab, name, line string) string {
+	var lines []string
+	replaced := false
+	if crontab = strings.TrimRight(crontab, "\n"); crontab != "" {
+		for _, existing := range strings.Split(crontab, "\n") {
+			if !strings.HasSuffix(strings.TrimSpace(existing), marker(name)) {
+				lines = append(lines, existing)
+			} else if line != "" && !replaced {
+				lines = append(lines, line)
+				replaced = true
+			}
+		}
+	}
+	if line != "" && !replaced {
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// readCrontab returns the crontab of the user, empty if there is none.
+func readCrontab() (string, error) {
+	if _, err := exec.LookPath("crontab"); err != nil {
+		return "", failure.New(failure.Config, "crontab not found, install cron to schedule commands")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("crontab", "-l")
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(strings.ToLower(stderr.String()), "no crontab") {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read the crontab: %s", strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// writeCrontab replaces the crontab of the user.
+func writeCrontab(crontab string) error {
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(crontab)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update the crontab: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// runTool runs a scheduler tool, returning its output as the error if it
+// fails.
+func runTool(name string, args ...string) error {
+	if output, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// shellQuote quotes s for sh unless it only has safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@+,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// taskCommand returns the command line of a task running command, with
+// arguments containing spaces quoted.
+func taskCommand(command []string) string {
+	var words []string
+	for _, arg := range command {
+		if arg == "" || strings.ContainsAny(arg, " \t") {
+			arg = `"` + arg + `"`
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/cron"
+)
+
+func TestCrontabLine(t *testing.T) {
+	schedule, err := cron.Parse("@daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := Entry{
+		Name:     "fetch",
+		Schedule: schedule,
+		Command:  []string{"/opt/my tools/aura", "schedule", "run", "50%"},
+		Env:      []string{"AURA_DB_PATH=/home/me/it's.db"},
+	}
+	want := `0 0 * * * AURA_DB_PATH='/home/me/it'\''s.db' '/opt/my tools/aura' schedule run '50\%' >/dev/null 2>&1 # aura schedule fetch`
+	if got := crontabLine(entry); got != want {
+		t.Errorf("crontabLine() = %q, want %q", got, want)
+	}
+}
+
+func TestUpdateCrontab(t *testing.T) {
+	crontab := "MAILTO=me\n*/5 * * * * backup.sh\n0 2 * * * aura schedule run fetch # aura schedule fetch\n"
+	tests := []struct {
+		name    string
+		crontab string
+		entry   string
+		line    string
+		want    string
+	}{
+		{"add to empty", "", "fetch", "0 3 * * * x # aura schedule fetch", "0 3 * * * x # aura schedule fetch\n"},
+		{"add", crontab, "backup", "@daily y # aura schedule backup", crontab + "@daily y # aura schedule backup\n"},
+		{"replace", crontab, "fetch", "0 3 * * * x # aura schedule fetch", "MAILTO=me\n*/5 * * * * backup.sh\n0 3 * * * x # aura schedule fetch\n"},
+		{"remove", crontab, "fetch", "", "MAILTO=me\n*/5 * * * * backup.sh\n"},
+		{"remove last", "0 2 * * * x # aura schedule fetch\n", "fetch", "", ""},
+		{"other names stay", "0 2 * * * x # aura schedule fetch-all\n", "fetch", "", "0 2 * * * x # aura schedule fetch-all\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updateCrontab(tt.crontab, tt.entry, tt.line); got != tt.want {
+				t.Errorf("updateCrontab() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskCommand(t *testing.T) {
+	got := taskCommand([]string{`C:\Program Files\Aura\aura.exe`, "schedule", "run", "fetch"})
+	if want := `"C:\Program Files\Aura\aura.exe" schedule run fetch`; got != want {
+		t.Errorf("taskCommand() = %q, want %q", got, want)
+	}
+}
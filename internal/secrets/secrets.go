@@ -0,0 +1,354 @@
+// Package secrets detects likely secrets such as API keys, tokens and private
+// keys in files.
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rule is a named pattern that matches a kind of secret.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Rules lists the patterns used to detect secrets.
+var Rules = []Rule{
+	{Name: "AWS access key ID", Pattern: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "AWS secret access key", Pattern: regexp.MustCompile(`(?i)aws.{0,20}secret.{0,20}[:=]\s*['"]?[0-9a-zA-Z/+]{40}\b`)},
+	{Name: "GitHub token", Pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`)},
+	{Name: "GitLab token", Pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{Name: "Slack token", Pattern: regexp.MustCompile(`\bxox[baprs]-[0-9A-Za-z-]{10,}\b`)},
+	{Name: "OpenAI API key", Pattern: regexp.MustCompile(`\bsk-(proj-)?[A-Za-z0-9_-]{20,}\b`)},
+	{Name: "Google API key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{Name: "Stripe live key", Pattern: regexp.MustCompile(`\b[sr]k_live_[0-9a-zA-Z]{24,}\b`)},
+	{Name: "Private key", Pattern: privateKeyPattern},
+	{Name: "Hardcoded credential", Pattern: regexp.MustCompile(`(?i)\b(password|passwd|secret|token|api_?key)\b\s*[:=]\s*['"][^'"\s]{8,}['"]`)},
+}
+
+// privateKeyPattern matches the first line of a private key.
+var privateKeyPattern = regexp.MustCompile(`-----BEGIN ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----`)
+
+// scrubRules detect secrets in text sent elsewhere, besides Rules: values of
+// secret-sounding environment variables as in `env` or .env files, bearer
+// tokens and passwords in URLs. Their first group is the secret.
+var scrubRules = []Rule{
+	{Name: "Secret variable", Pattern: regexp.MustCompile(`(?i)\b[A-Z0-9_]*(?:PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_KEY|CREDENTIALS?)[A-Z0-9_]*=['"]?([^\s'"()$][^\s'"()]{5,})(?:[\s'"]|$)`)},
+	{Name: "Bearer token", Pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{16,}=*)`)},
+	{Name: "Password in URL", Pattern: regexp.MustCompile(`://[^/\s:@]+:([^/\s@]{4,})@`)},
+}
+
+// Finding is a likely secret found in a file.
+type Finding struct {
+	File  string
+	Line  int
+	Rule  string
+	Match string
+}
+
+// String formats the finding as "file:line: rule (match)".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Rule, f.Match)
+}
+
+// maxLineLength skips lines longer than this, typically minified assets.
+const maxLineLength = 4096
+
+// Scan reads r line by line and returns all findings. The name is used as
+// the file name of the findings.
+func Scan(name string, r io.Reader) ([]Finding, error) {
+	var findings []Finding
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if len(line) > maxLineLength {
+			continue
+		}
+		findings = append(findings, ScanLine(name, lineNumber, line)...)
+	}
+
+	return findings, scanner.Err()
+}
+
+// ScanLine returns the findings of a single line.
+func ScanLine(name string, lineNumber int, line string) []Finding {
+	if strings.Contains(line, "aura:allow-secret") {
+		return nil
+	}
+
+	var findings []Finding
+	for _, rule := range Rules {
+		if match := rule.Pattern.FindString(line); match != "" && !isPlaceholder(match) {
+			findings = append(findings, Finding{
+				File:  name,
+				Line:  lineNumber,
+				Rule:  rule.Name,
+				Match: Redact(match),
+			})
+		}
+	}
+
+	// Fall back to entropy when no known pattern matched
+	if len(findings) == 0 {
+		if value := highEntropyValue(line); value != "" {
+			findings = append(findings, Finding{
+				File:  name,
+				Line:  lineNumber,
+				Rule:  "High entropy secret",
+				Match: Redact(value),
+			})
+		}
+	}
+	return findings
+}
+
+// assignmentPattern matches values assigned to secret-sounding names.
+var assignmentPattern = regexp.MustCompile(`(?i)(key|secret|token|passw\w*|credential|auth)\w*['"]?\s*[:=]\s*['"]?([A-Za-z0-9+/=_\-]{20,})`)
+
+// Minimum Shannon entropy in bits per character for values to be reported.
+// Hex strings have a lower maximum entropy than base64 strings.
+const (
+	minBase64Entropy = 4.0
+	minHexEntropy    = 3.0
+)
+
+// highEntropyValue returns a random looking value assigned to a secret-sounding
+// name on the line, or an empty string.
+func highEntropyValue(line string) string {
+	for _, match := range assignmentPattern.FindAllStringSubmatch(line, -1) {
+		value := match[2]
+		if isPlaceholder(value) {
+			continue
+		}
+
+		threshold := minBase64Entropy
+		if isHex(value) {
+			threshold = minHexEntropy
+		}
+		if Entropy(value) >= threshold {
+			return value
+		}
+	}
+	return ""
+}
+
+// Entropy returns the Shannon entropy of s in bits per character.
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	length := float64(len([]rune(s)))
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// isHex reports whether s consists of hexadecimal digits only.
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ScanFile scans the file at path. Binary files are skipped.
+func ScanFile(path string) ([]Finding, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 512)
+	n, err := file.Read(header)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if isBinary(header[:n]) {
+		return nil, nil
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return Scan(path, file)
+}
+
+// ScanDiff returns the findings of lines added in a unified diff, such as the
+// output of `git diff --staged`. Line numbers refer to the new file version.
+func ScanDiff(diff string) []Finding {
+	var findings []Finding
+	var file string
+	lineNumber := 0
+	inHunk, afterOld := false, false
+
+	for _, line := range strings.Split(diff, "\n") {
+		// Inside a hunk "+++ " adds a line starting with "++ ", the header
+		// of the new file only follows the one of the old file.
+		header := strings.HasPrefix(line, "+++ ") && !inHunk && afterOld
+		afterOld = strings.HasPrefix(line, "--- ") && !inHunk
+		switch {
+		case header:
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "@@"):
+			lineNumber = hunkStart(line)
+			inHunk = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			if file != "/dev/null" && len(line) <= maxLineLength {
+				findings = append(findings, ScanLine(file, lineNumber, line[1:])...)
+			}
+			lineNumber++
+		case inHunk && strings.HasPrefix(line, " "):
+			lineNumber++
+		case inHunk && (strings.HasPrefix(line, "-") || strings.HasPrefix(line, `\`)):
+		default:
+			inHunk = false
+		}
+	}
+
+	return findings
+}
+
+// Scrub replaces the likely secrets in text, such as a diff or a dump of
+// environment variables, with a note naming their kind, and returns the
+// findings of what was replaced. The lines of private keys are replaced
+// whole. Lines containing "aura:allow-secret" and placeholders are kept.
+func Scrub(name, text string) (string, []Finding) {
+	var findings []Finding
+	lines := strings.Split(text, "\n")
+	// keyPrefix precedes the lines of a private key, such as the + of a diff
+	inKey, keyPrefix := false, ""
+	for i, line := range lines {
+		if inKey {
+			if strings.Contains(line, "-----END") {
+				inKey = false
+			} else {
+				lines[i] = keyPrefix + "[redacted private key]"
+			}
+			continue
+		}
+		if strings.Contains(line, "aura:allow-secret") {
+			continue
+		}
+		if location := privateKeyPattern.FindStringIndex(line); location != nil {
+			findings = append(findings, Finding{File: name, Line: i + 1, Rule: "Private key", Match: line[location[0]:location[1]]})
+			if end := strings.Index(line[location[1]:], "-----END"); end >= 0 {
+				// The whole key on one line, such as in JSON
+				lines[i] = line[:location[1]] + "[redacted private key]" + line[location[1]+end:]
+			} else {
+				inKey, keyPrefix = true, line[:location[0]]
+			}
+			continue
+		}
+
+		redact := func(rule Rule, byGroup bool) {
+			line = rule.Pattern.ReplaceAllStringFunc(line, func(match string) string {
+				start, end := secretValue(rule.Pattern, match, byGroup)
+				value := match[start:end]
+				if value == "" || isPlaceholder(value) || strings.HasPrefix(value, "[redacted") {
+					return match
+				}
+				findings = append(findings, Finding{File: name, Line: i + 1, Rule: rule.Name, Match: Redact(value)})
+				return match[:start] + "[redacted " + strings.ToLower(rule.Name) + "]" + match[end:]
+			})
+		}
+		for _, rule := range Rules {
+			if rule.Pattern != privateKeyPattern {
+				redact(rule, false)
+			}
+		}
+		for _, rule := range scrubRules {
+			redact(rule, true)
+		}
+		if value := highEntropyValue(line); value != "" {
+			findings = append(findings, Finding{File: name, Line: i + 1, Rule: "High entropy secret", Match: Redact(value)})
+			line = strings.Replace(line, value, "[redacted high entropy secret]", 1)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), findings
+}
+
+// secretValue returns where the secret is in match of pattern: its first
+// group if byGroup, or else match without the name and quotes of an
+// assignment such as password = "…".
+func secretValue(pattern *regexp.Regexp, match string, byGroup bool) (int, int) {
+	if byGroup {
+		if groups := pattern.FindStringSubmatchIndex(match); len(groups) >= 4 && groups[2] >= 0 {
+			return groups[2], groups[3]
+		}
+	}
+	end := len(strings.TrimRight(match, `'"`))
+	return strings.LastIndexAny(match[:end], `=:'" `) + 1, end
+}
+
+// hunkStart returns the first new-file line number of a hunk header such as
+// "@@ -10,4 +12,6 @@".
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	for _, field := range fields {
+		if strings.HasPrefix(field, "+") {
+			start, _, _ := strings.Cut(strings.TrimPrefix(field, "+"), ",")
+			if n, err := strconv.Atoi(start); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// Redact shortens a secret so it can be displayed without leaking it.
+func Redact(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", 4) + secret[len(secret)-2:]
+}
+
+// placeholderWords lists fragments that mark values as documentation or test
+// fixtures rather than real secrets.
+var placeholderWords = []string{"test", "example", "dummy", "fake", "sample", "placeholder", "invalid", "changeme", "your", "xxxx", "<", "${"}
+
+// isPlaceholder reports whether a matched value is an obvious placeholder.
+func isPlaceholder(match string) bool {
+	lower := strings.ToLower(match)
+	for _, word := range placeholderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinary reports whether data looks like binary content.
+func isBinary(data []byte) bool {
+	for _, b := range data {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+// Package semantic finds directories and past conversations by meaning rather
+// than by name, using embedding vectors of short descriptions of them.
+package semantic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+// Document is a directory, or another item, that can be found by semantic
+// search.
+type Document struct {
+	// Path is the directory, or a key such as "conversation:12" for other items.
+	Path string
+	// Alias is the bookmark alias, empty for directories from the history.
+	Alias string
+	// Text is the description that is embedded.
+	Text string
+}
+
+// Result is a document matching a query.
+type Result struct {
+	Document
+	// Score is the cosine similarity of the query and the document, higher
+	// is better.
+	Score float64
+}
+
+// Embedder computes embedding vectors, such as an AI provider.
+type Embedder interface {
+	Embed(ctx context.Context, inputs []string) ([][]float32, error)
+}
+
+// Store persists embedding vectors, such as the Aura database.
+type Store interface {
+	ListEmbeddings(model string) ([]db.Embedding, error)
+	SaveEmbeddings(embeddings []db.Embedding) error
+}
+
+// Index keeps the embeddings of documents computed with a model up to date
+// and searches them.
+type Index struct {
+	Store    Store
+	Embedder Embedder
+	Model    string
+}
+
+// batchSize limits how many descriptions are embedded per request.
+const batchSize = 64
+
+// readmeExcerptLength limits how much of a README describes a directory.
+const readmeExcerptLength = 600
+
+// Describe builds the description of a directory from its name, path,
+// bookmark alias and project manifest, and its README if readme is set.
+func Describe(path, alias string, readme bool) string {
+	var lines []string
+	lines = append(lines, "Directory: "+filepath.Base(path), "Path: "+path)
+	if alias != "" {
+		lines = append(lines, "Bookmark: "+alias)
+	}
+
+	if content, err := os.ReadFile(filepath.Join(path, "go.mod")); err == nil {
+		if module, _, _ := strings.Cut(string(content), "\n"); strings.HasPrefix(module, "module ") {
+			lines = append(lines, "Go module: "+strings.TrimSpace(strings.TrimPrefix(module, "module ")))
+		}
+	}
+
+	if content, err := os.ReadFile(filepath.Join(path, "package.json")); err == nil {
+		var manifest struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		if json.Unmarshal(content, &manifest) == nil && manifest.Name != "" {
+			lines = append(lines, strings.TrimSpace("Node package: "+manifest.Name+" "+manifest.Description))
+		}
+	}
+
+	for _, name := range []string{"README.md", "README", "README.txt", "readme.md"} {
+		if !readme {
+			break
+		}
+		content, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			continue
+		}
+		excerpt := strings.Join(strings.Fields(string(content)), " ")
+		if len(excerpt) > readmeExcerptLength {
+			excerpt = excerpt[:readmeExcerptLength]
+		}
+		lines = append(lines, "README: "+excerpt)
+		break
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Update embeds the documents whose description changed since they were last
+// embedded and returns how many were embedded.
+func (i Index) Update(ctx context.Context, docs []Document) (int, error) {
+	stored, err := i.Store.ListEmbeddings(i.Model)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embeddings: %w", err)
+	}
+	hashes := make(map[string]string, len(stored))
+	for _, e := range stored {
+		hashes[e.Path] = e.Hash
+	}
+
+	var stale []Document
+	for _, doc := range docs {
+		if hashes[doc.Path] != hash(doc.Text) {
+			stale = append(stale, doc)
+		}
+	}
+
+	for start := 0; start < len(stale); start += batchSize {
+		batch := stale[start:min(start+batchSize, len(stale))]
+
+		texts := make([]string, len(batch))
+		for j, doc := range batch {
+			texts[j] = doc.Text
+		}
+		vectors, err := i.Embedder.Embed(ctx, texts)
+		if err != nil {
+			return start, fmt.Errorf("failed to embed documents: %w", err)
+		}
+
+		embeddings := make([]db.Embedding, len(batch))
+		for j, doc := range batch {
+			embeddings[j] = db.Embedding{Path: doc.Path, Model: i.Model, Hash: hash(doc.Text), Vector: vectors[j]}
+		}
+		if err := i.Store.SaveEmbeddings(embeddings); err != nil {
+			return start, err
+		}
+	}
+	return len(stale), nil
+}
+
+// Search returns up to limit documents most similar to the query, best first.
+// Documents without an embedding are skipped, so call Update first.
+func (i Index) Search(ctx context.Context, docs []Document, query string, limit int) ([]Result, error) {
+	vectors, err := i.Embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	queryVector := vectors[0]
+
+	stored, err := i.Store.ListEmbeddings(i.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embeddings: %w", err)
+	}
+	byPath := make(map[string][]float32, len(stored))
+	for _, e := range stored {
+		byPath[e.Path] = e.Vector
+	}
+
+	var results []Result
+	for _, doc := range docs {
+		vector, ok := byPath[doc.Path]
+		if !ok {
+			continue
+		}
+		results = append(results, Result{Document: doc, Score: Cosine(queryVector, vector)})
+	}
+
+	sort.SliceStable(results, func(a, b int) bool { return results[a].Score > results[b].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// Cosine returns the cosine similarity of two vectors, 0 if their lengths
+// differ or one is zero.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func hash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:8])
+}
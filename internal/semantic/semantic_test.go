@@ -0,0 +1,132 @@
+package semantic
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/db"
+)
+
+// memoryStore keeps embeddings in memory.
+type memoryStore struct {
+	embeddings map[string]db.Embedding
+}
+
+func (s *memoryStore) ListEmbeddings(model string) ([]db.Embedding, error) {
+	var embeddings []db.Embedding
+	for _, e := range s.embeddings {
+		if e.Model == model {
+			embeddings = append(embeddings, e)
+		}
+	}
+	return embeddings, nil
+}
+
+func (s *memoryStore) SaveEmbeddings(embeddings []db.Embedding) error {
+	for _, e := range embeddings {
+		s.embeddings[e.Path+"|"+e.Model] = e
+	}
+	return nil
+}
+
+// keywordEmbedder embeds texts as counts of a few keywords.
+type keywordEmbedder struct {
+	calls  int
+	inputs int
+}
+
+var keywords = []string{"billing", "invoice", "docs", "website"}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	e.calls++
+	e.inputs += len(inputs)
+	vectors := make([][]float32, len(inputs))
+	for i, input := range inputs {
+		vector := make([]float32, len(keywords))
+		for j, keyword := range keywords {
+			vector[j] = float32(strings.Count(strings.ToLower(input), keyword))
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+func TestIndexUpdateAndSearch(t *testing.T) {
+	store := &memoryStore{embeddings: make(map[string]db.Embedding)}
+	embedder := &keywordEmbedder{}
+	index := Index{Store: store, Embedder: embedder, Model: "keywords"}
+
+	docs := []Document{
+		{Path: "/code/payments", Alias: "pay", Text: "Billing service that sends invoice emails"},
+		{Path: "/code/site", Text: "Marketing website and docs"},
+		{Path: "/code/empty", Text: "nothing relevant"},
+	}
+
+	count, err := index.Update(context.Background(), docs)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Update() = %d, want 3", count)
+	}
+
+	// Unchanged documents are not embedded again
+	docs[1].Text = "Marketing website, docs and blog"
+	if count, _ := index.Update(context.Background(), docs); count != 1 {
+		t.Errorf("Update() = %d, want 1 changed document", count)
+	}
+
+	results, err := index.Search(context.Background(), docs, "that repo with the billing service", 2)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Path != "/code/payments" {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("Expected results sorted by score, got %+v", results)
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		a, b []float32
+		want float64
+	}{
+		{[]float32{1, 0}, []float32{1, 0}, 1},
+		{[]float32{1, 0}, []float32{0, 1}, 0},
+		{[]float32{1, 1}, []float32{-1, -1}, -1},
+		{[]float32{1, 0}, []float32{1, 0, 0}, 0},
+		{[]float32{0, 0}, []float32{1, 0}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Cosine(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "payments")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/billing\n\ngo 1.23\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Payments\n\nHandles   billing\nand invoices.\n"), 0644)
+
+	description := Describe(dir, "pay", true)
+	for _, expected := range []string{"Directory: payments", "Bookmark: pay", "Go module: example.com/billing", "README: # Payments Handles billing and invoices."} {
+		if !strings.Contains(description, expected) {
+			t.Errorf("Expected %q in description:\n%s", expected, description)
+		}
+	}
+
+	if description := Describe(dir, "pay", false); strings.Contains(description, "README") || !strings.Contains(description, "Go module: example.com/billing") {
+		t.Errorf("Expected the description without the README:\n%s", description)
+	}
+}
@@ -0,0 +1,150 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command is a command from the history of a shell.
+type Command struct {
+	Line  string
+	Time  time.Time
+	Shell string
+}
+
+// History returns the commands run since since, oldest first, from the
+// histories in home of the shells recording when commands ran: zsh with
+// EXTENDED_HISTORY, bash with HISTTIMEFORMAT and fish. Commands without a
+// time are left out, as are histories that can't be read.
+func History(home string, since time.Time) []Command {
+	fishDir := filepath.Join(home, ".local", "share", "fish")
+	if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+		fishDir = filepath.Join(data, "fish")
+	}
+	sources := []struct {
+		shell string
+		path  string
+		parse func([]byte) []Command
+	}{
+		{"zsh", filepath.Join(home, ".zsh_history"), parseZshHistory},
+		{"zsh", filepath.Join(home, ".histfile"), parseZshHistory},
+		{"bash", filepath.Join(home, ".bash_history"), parseBashHistory},
+		{"fish", filepath.Join(fishDir, "fish_history"), parseFishHistory},
+	}
+
+	var commands []Command
+	for _, source := range sources {
+		content, err := os.ReadFile(source.path)
+		if err != nil {
+			continue
+		}
+		for _, command := range source.parse(content) {
+			if !command.Time.Before(since) {
+				command.Shell = source.shell
+				commands = append(commands, command)
+			}
+		}
+	}
+	sort.SliceStable(commands, func(i, j int) bool { return commands[i].Time.Before(commands[j].Time) })
+	return commands
+}
+
+// parseZshHistory parses the extended history of zsh, lines such as
+// ": 1700000000:0;git push", continued by a trailing backslash.
+func parseZshHistory(content []byte) []Command {
+	var commands []Command
+	var current *Command
+	for _, line := range strings.Split(string(unmetafy(content)), "\n") {
+		if current != nil {
+			current.Line += "\n" + strings.TrimSuffix(line, `\`)
+			if !strings.HasSuffix(line, `\`) {
+				current = nil
+			}
+			continue
+		}
+		header, command, ok := strings.Cut(line, ";")
+		if !ok || !strings.HasPrefix(header, ": ") {
+			continue
+		}
+		stamp, _, _ := strings.Cut(strings.TrimPrefix(header, ": "), ":")
+		seconds, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		commands = append(commands, Command{Line: strings.TrimSuffix(command, `\`), Time: time.Unix(seconds, 0)})
+		if strings.HasSuffix(command, `\`) {
+			current = &commands[len(commands)-1]
+		}
+	}
+	return commands
+}
+
+// unmetafy decodes the bytes zsh escapes in its history file, such as those
+// of non-ASCII characters.
+func unmetafy(content []byte) []byte {
+	const meta = 0x83
+	if bytes.IndexByte(content, meta) < 0 {
+		return content
+	}
+	decoded := make([]byte, 0, len(content))
+	for i := 0; i < len(content); i++ {
+		if content[i] == meta && i+1 < len(content) {
+			i++
+			decoded = append(decoded, content[i]^32)
+			continue
+		}
+		decoded = append(decoded, content[i])
+	}
+	return decoded
+}
+
+// parseBashHistory parses the history of bash, in which HISTTIMEFORMAT
+// precedes commands by their time as "#1700000000".
+func parseBashHistory(content []byte) []Command {
+	var commands []Command
+	var stamp time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "#"), 10, 64); err == nil && strings.HasPrefix(line, "#") {
+			stamp = time.Unix(seconds, 0)
+			continue
+		}
+		if stamp.IsZero() || strings.TrimSpace(line) == "" {
+			continue
+		}
+		commands = append(commands, Command{Line: line, Time: stamp})
+		stamp = time.Time{}
+	}
+	return commands
+}
+
+// parseFishHistory parses the history of fish, entries such as
+// "- cmd: git push" followed by "  when: 1700000000".
+func parseFishHistory(content []byte) []Command {
+	var commands []Command
+	line := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if command, ok := strings.CutPrefix(text, "- cmd: "); ok {
+			line = strings.NewReplacer(`\n`, "\n", `\\`, `\`).Replace(command)
+			continue
+		}
+		if stamp, ok := strings.CutPrefix(text, "  when: "); ok && line != "" {
+			if seconds, err := strconv.ParseInt(strings.TrimSpace(stamp), 10, 64); err == nil {
+				commands = append(commands, Command{Line: line, Time: time.Unix(seconds, 0)})
+			}
+			line = ""
+		}
+	}
+	return commands
+}
@@ -0,0 +1,54 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", "")
+	files := map[string]string{
+		".zsh_history": ": 1700000100:0;git push\n" +
+			": 1700000200:3;for f in *.go; do\\\n  gofmt -l $f\\\ndone\n" +
+			"echo without extended history\n" +
+			": 1600000000:0;make old\n",
+		".bash_history": "ls -la\n#1700000150\ndocker compose up -d\n#1700000250\n\n",
+		".local/share/fish/fish_history": "- cmd: go test ./...\n  when: 1700000300\n" +
+			"- cmd: echo a\\nb\n  when: 1700000400\n  paths:\n    - a\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(home, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, command := range History(home, time.Unix(1700000000, 0)) {
+		got = append(got, command.Shell+": "+command.Line)
+	}
+	want := []string{
+		"zsh: git push",
+		"bash: docker compose up -d",
+		"zsh: for f in *.go; do\n  gofmt -l $f\ndone",
+		"fish: go test ./...",
+		"fish: echo a\nb",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("History() = %q, want %q", got, want)
+	}
+}
+
+func TestUnmetafy(t *testing.T) {
+	// zsh writes the 0x9C byte of "Ü" as 0x83 0xBC.
+	if got := string(unmetafy([]byte{0xC3, 0x83, 0xBC, 'b', 'e', 'r'})); got != "Über" {
+		t.Errorf("unmetafy() = %q, want Über", got)
+	}
+}
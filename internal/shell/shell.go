@@ -0,0 +1,171 @@
+// Package shell installs the Aura shell integration, the `aura` shell
+// function that changes the directory after `aura go`, runs the commands
+// `aura do` hands to the shell and loads the aliases. install.sh writes the
+// same function.
+package shell
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Detect returns the name of the user's shell, such as "bash", from $SHELL.
+func Detect() string {
+	return filepath.Base(os.Getenv("SHELL"))
+}
+
+// RCFile returns the startup file of shell in home.
+func RCFile(shell, home string) (string, error) {
+	switch shell {
+	case "bash":
+		return filepath.Join(home, ".bashrc"), nil
+	case "zsh":
+		return filepath.Join(home, ".zshrc"), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "config.fish"), nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s'", shell)
+}
+
+// Integration returns the script defining the aura function in shell.
+func Integration(shell string) (string, error) {
+	switch shell {
+	case "bash", "zsh":
+		return posixIntegration, nil
+	case "fish":
+		return fishIntegration, nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s'", shell)
+}
+
+// Installed reports whether the startup file at rcFile defines the aura
+// function.
+func Installed(shell, rcFile string) bool {
+	content, err := os.ReadFile(rcFile)
+	if err != nil {
+		return false
+	}
+	if shell == "fish" {
+		return strings.Contains(string(content), "function aura")
+	}
+	return strings.Contains(string(content), "aura()")
+}
+
+// Install appends the integration of shell to rcFile, unless it is there
+// already.
+func Install(shell, rcFile string) error {
+	if Installed(shell, rcFile) {
+		return nil
+	}
+	script, err := Integration(shell)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rcFile), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(rcFile), err)
+	}
+	file, err := os.OpenFile(rcFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", rcFile, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString("\n" + script); err != nil {
+		return fmt.Errorf("failed to write %s: %w", rcFile, err)
+	}
+	return nil
+}
+
+// Verify starts a new interactive shell and checks that aura is a function
+// in it.
+func Verify(shell string) error {
+	var check string
+	switch shell {
+	case "bash":
+		check = "type -t aura"
+	case "zsh":
+		check = "whence -w aura"
+	case "fish":
+		check = "functions -q aura; and echo function"
+	default:
+		return fmt.Errorf("unsupported shell '%s'", shell)
+	}
+
+	output, err := exec.Command(shell, "-i", "-c", check).Output()
+	if err != nil || !strings.Contains(string(output), "function") {
+		return fmt.Errorf("aura is not a function in a new %s shell", shell)
+	}
+	return nil
+}
+
+// ExportLine returns the line of shell's startup file that sets the
+// environment variable name to value.
+func ExportLine(shell, name, value string) string {
+	quoted := "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	if shell == "fish" {
+		quoted = "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+		return fmt.Sprintf("set -gx %s %s", name, quoted)
+	}
+	return fmt.Sprintf("export %s=%s", name, quoted)
+}
+
+const posixIntegration = `# Aura CLI integration
+aura() {
+    # Quick marks belong to this shell
+    local -x AURA_SESSION=$$
+    if [[ "$1" == "go" ]]; then
+        local result
+        result=$(command aura "$@") || return
+        # Nothing to enter when cloud storage was listed
+        if [[ -n "$result" ]]; then
+            cd "$result"
+            eval "$(command aura on-enter)"
+        fi
+    elif [[ "$1" == "do" || "$1" == "session" || $# -eq 0 ]]; then
+        # Commands such as activating a virtualenv must run in this shell
+        local eval_file ret
+        eval_file=$(mktemp)
+        AURA_EVAL_FILE="$eval_file" command aura "$@"
+        ret=$?
+        source "$eval_file"
+        rm -f "$eval_file"
+        return $ret
+    elif [[ "$1" == "alias" ]]; then
+        # Load changed aliases into this shell
+        command aura "$@" && eval "$(command aura alias init bash)"
+    else
+        command aura "$@"
+    fi
+}
+eval "$(command aura alias init bash 2>/dev/null)"
+`
+
+const fishIntegration = `# Aura CLI integration
+function aura
+    set -lx AURA_SESSION $fish_pid
+    if test "$argv[1]" = 'go'
+        set result (command aura $argv); or return
+        if test -n "$result"
+            cd "$result"
+            command aura on-enter | source
+        end
+        return 0
+    else if test (count $argv) -eq 0; or test "$argv[1]" = 'do'; or test "$argv[1]" = 'session'
+        set eval_file (mktemp)
+        AURA_SHELL=fish AURA_EVAL_FILE=$eval_file command aura $argv
+        set ret $status
+        source $eval_file
+        rm -f $eval_file
+        return $ret
+    else if test "$argv[1]" = 'alias'
+        command aura $argv; and command aura alias init fish | source
+    else
+        command aura $argv
+    end
+end
+command aura alias init fish 2>/dev/null | source
+`
@@ -0,0 +1,58 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstall(t *testing.T) {
+	tests := []struct {
+		shell string
+		rc    string
+	}{
+		{"bash", ".bashrc"},
+		{"zsh", ".zshrc"},
+		{"fish", filepath.Join(".config", "fish", "config.fish")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			home := t.TempDir()
+			rcFile, err := RCFile(tt.shell, home)
+			if err != nil || rcFile != filepath.Join(home, tt.rc) {
+				t.Fatalf("RCFile() = %s, %v", rcFile, err)
+			}
+			if Installed(tt.shell, rcFile) {
+				t.Fatal("Expected integration to be missing")
+			}
+
+			for i := 0; i < 2; i++ {
+				if err := Install(tt.shell, rcFile); err != nil {
+					t.Fatalf("Install() error = %v", err)
+				}
+			}
+			if !Installed(tt.shell, rcFile) {
+				t.Error("Expected integration to be installed")
+			}
+			content, _ := os.ReadFile(rcFile)
+			if count := strings.Count(string(content), "# Aura CLI integration"); count != 1 {
+				t.Errorf("Expected integration once, found it %d times", count)
+			}
+		})
+	}
+
+	if _, err := RCFile("tcsh", t.TempDir()); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+func TestExportLine(t *testing.T) {
+	if got := ExportLine("bash", "AURA_API_KEY", "sk-it's"); got != `export AURA_API_KEY='sk-it'\''s'` {
+		t.Errorf("ExportLine(bash) = %s", got)
+	}
+	if got := ExportLine("fish", "AURA_API_KEY", "sk-it's"); got != `set -gx AURA_API_KEY 'sk-it\'s'` {
+		t.Errorf("ExportLine(fish) = %s", got)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+package sysinfo
+
+import (
+	"os"
+	"syscall"
+)
+
+// diskUsage returns the use of the file system holding path and its device.
+func diskUsage(path string) (Disk, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return Disk{}, 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Disk{}, 0, err
+	}
+	var device uint64
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		device = uint64(sys.Dev)
+	}
+
+	blockSize := uint64(stat.Bsize)
+	total := stat.Blocks * blockSize
+	// Blocks reserved for root count as used, as df counts them.
+	free := stat.Bavail * blockSize
+	return Disk{Path: path, Total: total, Used: total - min(free, total)}, device, nil
+}
@@ -0,0 +1,9 @@
+package sysinfo
+
+import "errors"
+
+// diskUsage is not used on Windows, where the disks come from PowerShell
+// with the rest of the snapshot.
+func diskUsage(path string) (Disk, uint64, error) {
+	return Disk{}, 0, errors.ErrUnsupported
+}
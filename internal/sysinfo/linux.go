@@ -0,0 +1,220 @@
+package sysinfo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procSample is what /proc reports at one moment: the CPU time spent in
+// total and idle, and by each process, in clock ticks.
+type procSample struct {
+	total, idle uint64
+	processes   map[int]procProcess
+}
+
+// procProcess is a process as /proc/<pid>/stat reports it.
+type procProcess struct {
+	name  string
+	ticks uint64
+	// pages is the resident memory in pages.
+	pages uint64
+}
+
+func (s *Sampler) takeLinux(ctx context.Context) (*Snapshot, error) {
+	previous := s.previous
+	if previous == nil {
+		first, err := readProcSample(s.procRoot)
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(sampleInterval):
+		}
+		previous = first
+	}
+	current, err := readProcSample(s.procRoot)
+	if err != nil {
+		return nil, err
+	}
+	s.previous = current
+
+	snapshot := &Snapshot{}
+	cores := 0
+	if data, err := os.ReadFile(filepath.Join(s.procRoot, "stat")); err == nil {
+		for _, line := range fields(string(data)) {
+			if strings.HasPrefix(line[0], "cpu") && line[0] != "cpu" {
+				cores++
+			}
+		}
+	}
+	snapshot.CPU.Cores = cores
+	snapshot.CPU.Usage, snapshot.Processes = procUsage(previous, current, cores, uint64(os.Getpagesize()))
+
+	if data, err := os.ReadFile(filepath.Join(s.procRoot, "loadavg")); err == nil {
+		snapshot.CPU.Load = parseLoadavg(string(data))
+	}
+	data, err := os.ReadFile(filepath.Join(s.procRoot, "meminfo"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory use: %w", err)
+	}
+	snapshot.Memory = parseMeminfo(string(data))
+	snapshot.Battery = readSysBattery(filepath.Join(filepath.Dir(s.procRoot), "sys", "class", "power_supply"))
+	return snapshot, nil
+}
+
+// readProcSample reads the CPU time of the system and its processes.
+func readProcSample(root string) (*procSample, error) {
+	data, err := os.ReadFile(filepath.Join(root, "stat"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CPU use: %w", err)
+	}
+	sample := &procSample{processes: map[int]procProcess{}}
+	sample.total, sample.idle = parseProcStat(string(data))
+
+	dirs, _ := filepath.Glob(filepath.Join(root, "[0-9]*"))
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(filepath.Base(dir))
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+		if err != nil {
+			// The process ended meanwhile.
+			continue
+		}
+		if process, ok := parsePidStat(string(stat)); ok {
+			sample.processes[pid] = process
+		}
+	}
+	return sample, nil
+}
+
+// parseProcStat returns the total and idle clock ticks of the cpu line of
+// /proc/stat: user, nice, system, idle, iowait, irq, softirq and steal.
+func parseProcStat(text string) (total, idle uint64) {
+	for _, line := range fields(text) {
+		if line[0] != "cpu" {
+			continue
+		}
+		for i, field := range line[1:min(len(line), 9)] {
+			ticks, _ := strconv.ParseUint(field, 10, 64)
+			total += ticks
+			if i == 3 || i == 4 {
+				idle += ticks
+			}
+		}
+		break
+	}
+	return total, idle
+}
+
+// parsePidStat parses /proc/<pid>/stat. The name is in parentheses and may
+// contain spaces, so the other fields are counted from its end.
+func parsePidStat(text string) (procProcess, bool) {
+	open, end := strings.Index(text, "("), strings.LastIndex(text, ")")
+	if open < 0 || end < open {
+		return procProcess{}, false
+	}
+	rest := strings.Fields(text[end+1:])
+	// utime, stime and rss are the 14th, 15th and 24th fields, counted
+	// from the state, the 3rd.
+	if len(rest) < 22 {
+		return procProcess{}, false
+	}
+	utime, _ := strconv.ParseUint(rest[11], 10, 64)
+	stime, _ := strconv.ParseUint(rest[12], 10, 64)
+	pages, _ := strconv.ParseUint(rest[21], 10, 64)
+	return procProcess{name: text[open+1 : end], ticks: utime + stime, pages: pages}, true
+}
+
+// procUsage returns the CPU usage of the system and the processes with
+// their share of one core between two samples.
+func procUsage(previous, current *procSample, cores int, pageSize uint64) (float64, []Process) {
+	var usage float64
+	elapsed := current.total - previous.total
+	if current.total > previous.total {
+		usage = 100 - float64(current.idle-previous.idle)*100/float64(elapsed)
+	}
+
+	processes := make([]Process, 0, len(current.processes))
+	for pid, process := range current.processes {
+		var cpu float64
+		if before, ok := previous.processes[pid]; ok && elapsed > 0 && process.ticks >= before.ticks {
+			// elapsed counts the ticks of all cores.
+			cpu = float64(process.ticks-before.ticks) * 100 * float64(max(cores, 1)) / float64(elapsed)
+		}
+		processes = append(processes, Process{PID: pid, Name: process.name, CPU: cpu, Memory: process.pages * pageSize})
+	}
+	return usage, processes
+}
+
+// parseMeminfo parses /proc/meminfo, whose values are in KiB.
+func parseMeminfo(text string) Memory {
+	values := map[string]uint64{}
+	for _, line := range fields(text) {
+		if len(line) >= 2 {
+			value, _ := strconv.ParseUint(line[1], 10, 64)
+			values[strings.TrimSuffix(line[0], ":")] = value * 1024
+		}
+	}
+	available, ok := values["MemAvailable"]
+	if !ok {
+		// Kernels before 3.14
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	return Memory{
+		Total:     values["MemTotal"],
+		Used:      values["MemTotal"] - min(available, values["MemTotal"]),
+		SwapTotal: values["SwapTotal"],
+		SwapUsed:  values["SwapTotal"] - min(values["SwapFree"], values["SwapTotal"]),
+	}
+}
+
+// parseLoadavg parses the load averages of /proc/loadavg or of sysctl
+// vm.loadavg, "{ 1.52 1.73 1.80 }" on macOS.
+func parseLoadavg(text string) [3]float64 {
+	var load [3]float64
+	i := 0
+	for _, field := range strings.Fields(text) {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			continue
+		}
+		load[i] = value
+		if i++; i == len(load) {
+			break
+		}
+	}
+	return load
+}
+
+// readSysBattery reads the first battery of /sys/class/power_supply.
+func readSysBattery(dir string) *Battery {
+	supplies, _ := os.ReadDir(dir)
+	for _, supply := range supplies {
+		read := func(name string) string {
+			data, _ := os.ReadFile(filepath.Join(dir, supply.Name(), name))
+			return strings.TrimSpace(string(data))
+		}
+		if read("type") != "Battery" {
+			continue
+		}
+		percent, err := strconv.Atoi(read("capacity"))
+		if err != nil {
+			continue
+		}
+		status := strings.ToLower(read("status"))
+		if status == "not charging" {
+			status = "plugged in"
+		}
+		return &Battery{Percent: percent, Status: status}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package sysinfo
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+func takeMacOS(ctx context.Context) (*Snapshot, error) {
+	run := func(name string, args ...string) string {
+		output, _ := exec.CommandContext(ctx, name, args...).Output()
+		return string(output)
+	}
+
+	processes, err := exec.CommandContext(ctx, "ps", "-Ao", "pid=,pcpu=,rss=,comm=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run ps: %w", err)
+	}
+	snapshot := &Snapshot{Processes: parsePS(string(processes))}
+
+	snapshot.CPU.Cores = runtime.NumCPU()
+	var busy float64
+	for _, process := range snapshot.Processes {
+		busy += process.CPU
+	}
+	snapshot.CPU.Usage = min(busy/float64(snapshot.CPU.Cores), 100)
+	snapshot.CPU.Load = parseLoadavg(run("sysctl", "-n", "vm.loadavg"))
+
+	total, _ := strconv.ParseUint(strings.TrimSpace(run("sysctl", "-n", "hw.memsize")), 10, 64)
+	snapshot.Memory = Memory{Total: total, Used: min(parseVMStat(run("vm_stat")), total)}
+	snapshot.Memory.SwapTotal, snapshot.Memory.SwapUsed = parseSwapUsage(run("sysctl", "-n", "vm.swapusage"))
+	snapshot.Battery = parsePmset(run("pmset", "-g", "batt"))
+	return snapshot, nil
+}
+
+// parsePS parses the output of ps -Ao pid=,pcpu=,rss=,comm=, with the
+// resident memory in KiB.
+func parsePS(text string) []Process {
+	var processes []Process
+	for _, line := range fields(text) {
+		if len(line) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(line[0])
+		if err != nil {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(line[1], 64)
+		rss, _ := strconv.ParseUint(line[2], 10, 64)
+		name := filepath.Base(strings.Join(line[3:], " "))
+		processes = append(processes, Process{PID: pid, Name: name, CPU: cpu, Memory: rss * 1024})
+	}
+	return processes
+}
+
+// vmStatPage matches the page size in the header of vm_stat.
+var vmStatPage = regexp.MustCompile(`page size of (\d+) bytes`)
+
+// parseVMStat returns the used memory of the output of vm_stat as Activity
+// Monitor counts it: active, wired and compressed pages.
+func parseVMStat(text string) uint64 {
+	pageSize := uint64(4096)
+	if match := vmStatPage.FindStringSubmatch(text); match != nil {
+		pageSize, _ = strconv.ParseUint(match[1], 10, 64)
+	}
+	var pages uint64
+	for _, line := range strings.Split(text, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "Pages active", "Pages wired down", "Pages occupied by compressor":
+			count, _ := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), "."), 10, 64)
+			pages += count
+		}
+	}
+	return pages * pageSize
+}
+
+// swapValue matches the values of sysctl vm.swapusage, such as
+// "total = 2048.00M".
+var swapValue = regexp.MustCompile(`(total|used) = ([\d.]+)([KMG])`)
+
+// parseSwapUsage parses the total and used swap of sysctl vm.swapusage.
+func parseSwapUsage(text string) (total, used uint64) {
+	for _, match := range swapValue.FindAllStringSubmatch(text, -1) {
+		value, _ := strconv.ParseFloat(match[2], 64)
+		value *= map[string]float64{"K": 1 << 10, "M": 1 << 20, "G": 1 << 30}[match[3]]
+		if match[1] == "total" {
+			total = uint64(value)
+		} else {
+			used = uint64(value)
+		}
+	}
+	return total, used
+}
+
+// pmsetBattery matches the battery line of pmset -g batt, such as
+// "-InternalBattery-0 (id=123)	82%; charging; 1:20 remaining".
+var pmsetBattery = regexp.MustCompile(`(\d+)%;\s*([^;]+);`)
+
+// parsePmset parses the battery of pmset -g batt, or nil without one.
+func parsePmset(text string) *Battery {
+	match := pmsetBattery.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	percent, _ := strconv.Atoi(match[1])
+	status := strings.TrimSpace(match[2])
+	switch status {
+	case "charged":
+		status = "full"
+	case "AC attached", "finishing charge":
+		status = "plugged in"
+	}
+	return &Battery{Percent: percent, Status: status}
+}
@@ -0,0 +1,174 @@
+// Package sysinfo takes snapshots of the CPU, memory, disks, battery and
+// busiest processes of the system: from /proc and /sys on Linux, from
+// sysctl, vm_stat, pmset and ps on macOS and from PowerShell on Windows.
+package sysinfo
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// Snapshot is the state of the system at one moment.
+type Snapshot struct {
+	CPU    CPU
+	Memory Memory
+	Disks  []Disk
+	// Battery is nil on machines without one.
+	Battery   *Battery
+	Processes []Process
+}
+
+// CPU is the load of the processors.
+type CPU struct {
+	Cores int
+	// Usage is the busy share of all cores in percent.
+	Usage float64
+	// Load is the load average over 1, 5 and 15 minutes, zero on Windows.
+	Load [3]float64
+}
+
+// Memory is the use of the memory and swap in bytes.
+type Memory struct {
+	Total     uint64
+	Used      uint64
+	SwapTotal uint64
+	SwapUsed  uint64
+}
+
+// Disk is the use of a file system in bytes.
+type Disk struct {
+	// Path is where the file system is mounted, or its drive on Windows.
+	Path  string
+	Total uint64
+	Used  uint64
+}
+
+// Battery is the charge of the battery.
+type Battery struct {
+	Percent int
+	// Status is "charging", "discharging", "full" or "plugged in".
+	Status string
+}
+
+// Process is a running process.
+type Process struct {
+	PID  int
+	Name string
+	// CPU is the share of one core the process used recently, in percent.
+	CPU float64
+	// Memory is its resident memory in bytes.
+	Memory uint64
+}
+
+// Percent returns part of total in percent.
+func Percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// sampleInterval is how long CPU use is measured over by the first
+// snapshot.
+const sampleInterval = 500 * time.Millisecond
+
+// Sampler takes snapshots of a platform. CPU use is measured since the
+// previous snapshot, so a sampler refreshing a view reports the use between
+// refreshes.
+type Sampler struct {
+	platform platform.Platform
+	// Top is how many of the busiest processes snapshots list.
+	Top int
+	// procRoot is the mount point of /proc, replaced by tests.
+	procRoot string
+	previous *procSample
+}
+
+// NewSampler returns a sampler of p listing the top busiest processes.
+func NewSampler(p platform.Platform, top int) *Sampler {
+	return &Sampler{platform: p, Top: top, procRoot: "/proc"}
+}
+
+// Take takes a snapshot of the system.
+func (s *Sampler) Take(ctx context.Context) (*Snapshot, error) {
+	var snapshot *Snapshot
+	var err error
+	switch {
+	case s.platform.IsWindows():
+		snapshot, err = takeWindows(ctx)
+	case s.platform.IsMacOS():
+		snapshot, err = takeMacOS(ctx)
+	default:
+		snapshot, err = s.takeLinux(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot.CPU.Cores == 0 {
+		snapshot.CPU.Cores = runtime.NumCPU()
+	}
+	if !s.platform.IsWindows() {
+		snapshot.Disks = localDisks()
+	}
+	slices.SortStableFunc(snapshot.Processes, func(a, b Process) int {
+		switch {
+		case a.CPU != b.CPU:
+			if a.CPU > b.CPU {
+				return -1
+			}
+			return 1
+		case a.Memory != b.Memory:
+			if a.Memory > b.Memory {
+				return -1
+			}
+			return 1
+		}
+		return a.PID - b.PID
+	})
+	if len(snapshot.Processes) > s.Top {
+		snapshot.Processes = snapshot.Processes[:s.Top]
+	}
+	return snapshot, nil
+}
+
+// localDisks returns the file systems of the root and the home and working
+// directories, each once.
+func localDisks() []Disk {
+	paths := []string{"/"}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, home)
+	}
+	if dir, err := os.Getwd(); err == nil {
+		paths = append(paths, dir)
+	}
+
+	var disks []Disk
+	seen := map[uint64]bool{}
+	for _, path := range paths {
+		disk, device, err := diskUsage(path)
+		if err != nil || seen[device] || disk.Total == 0 {
+			continue
+		}
+		seen[device] = true
+		disks = append(disks, disk)
+	}
+	return disks
+}
+
+// fields splits text into lines of fields, skipping empty lines.
+func fields(text string) [][]string {
+	var lines [][]string
+	for _, line := range strings.Split(text, "\n") {
+		if f := strings.Fields(line); len(f) > 0 {
+			lines = append(lines, f)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,234 @@
+package sysinfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+const procStat = `cpu  100 0 50 800 50 0 0 0 0 0
+cpu0 50 0 25 400 25 0 0 0 0 0
+cpu1 50 0 25 400 25 0 0 0 0 0
+intr 12345
+`
+
+const procMeminfo = `MemTotal:       16000000 kB
+MemFree:         2000000 kB
+MemAvailable:    6000000 kB
+Buffers:          500000 kB
+Cached:          3000000 kB
+SwapTotal:       4000000 kB
+SwapFree:        3000000 kB
+`
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	total, idle := parseProcStat(procStat)
+	if total != 1000 || idle != 850 {
+		t.Errorf("parseProcStat() = %d, %d, want 1000, 850", total, idle)
+	}
+}
+
+func TestParsePidStat(t *testing.T) {
+	stat := "4242 (Web Content) S 1 4242 4242 0 -1 4194560 100 0 0 0 30 12 0 0 20 0 1 0 500 100000 2560 18446744073709551615"
+	process, ok := parsePidStat(stat)
+	if !ok {
+		t.Fatal("parsePidStat() ok = false")
+	}
+	want := procProcess{name: "Web Content", ticks: 42, pages: 2560}
+	if process != want {
+		t.Errorf("parsePidStat() = %+v, want %+v", process, want)
+	}
+
+	if _, ok := parsePidStat("4242 (short) S 1"); ok {
+		t.Error("parsePidStat() of a truncated line ok = true")
+	}
+}
+
+func TestProcUsage(t *testing.T) {
+	previous := &procSample{total: 1000, idle: 800, processes: map[int]procProcess{
+		1: {name: "busy", ticks: 100, pages: 10},
+		2: {name: "idle", ticks: 5, pages: 20},
+	}}
+	current := &procSample{total: 1200, idle: 900, processes: map[int]procProcess{
+		1: {name: "busy", ticks: 180, pages: 10},
+		2: {name: "idle", ticks: 5, pages: 20},
+		3: {name: "new", ticks: 50, pages: 1},
+	}}
+
+	usage, processes := procUsage(previous, current, 2, 4096)
+	if usage != 50 {
+		t.Errorf("procUsage() usage = %v, want 50", usage)
+	}
+	cpu := map[string]float64{}
+	for _, process := range processes {
+		cpu[process.Name] = process.CPU
+	}
+	// busy used 80 of the 100 ticks each core had.
+	want := map[string]float64{"busy": 80, "idle": 0, "new": 0}
+	if !reflect.DeepEqual(cpu, want) {
+		t.Errorf("procUsage() processes CPU = %v, want %v", cpu, want)
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	want := Memory{Total: 16000000 * 1024, Used: 10000000 * 1024, SwapTotal: 4000000 * 1024, SwapUsed: 1000000 * 1024}
+	if got := parseMeminfo(procMeminfo); got != want {
+		t.Errorf("parseMeminfo() = %+v, want %+v", got, want)
+	}
+
+	old := "MemTotal: 1000 kB\nMemFree: 100 kB\nBuffers: 100 kB\nCached: 200 kB\n"
+	if got := parseMeminfo(old); got.Used != 600*1024 {
+		t.Errorf("parseMeminfo() without MemAvailable Used = %d, want %d", got.Used, 600*1024)
+	}
+}
+
+func TestParseLoadavg(t *testing.T) {
+	want := [3]float64{1.52, 1.73, 1.8}
+	for _, text := range []string{"1.52 1.73 1.80 2/345 6789\n", "{ 1.52 1.73 1.80 }\n"} {
+		if got := parseLoadavg(text); got != want {
+			t.Errorf("parseLoadavg(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestReadSysBattery(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"AC/type":          "Mains\n",
+		"BAT0/type":        "Battery\n",
+		"BAT0/capacity":    "82\n",
+		"BAT0/status":      "Not charging\n",
+		"hidpp_0/type":     "Battery\n",
+		"hidpp_0/capacity": "unknown\n",
+	})
+	battery := readSysBattery(dir)
+	if battery == nil || *battery != (Battery{Percent: 82, Status: "plugged in"}) {
+		t.Errorf("readSysBattery() = %+v", battery)
+	}
+
+	if battery := readSysBattery(filepath.Join(dir, "missing")); battery != nil {
+		t.Errorf("readSysBattery() without batteries = %+v, want nil", battery)
+	}
+}
+
+func TestSamplerLinux(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "proc")
+	writeFiles(t, root, map[string]string{
+		"stat":    procStat,
+		"meminfo": procMeminfo,
+		"loadavg": "0.50 0.40 0.30 1/100 4242\n",
+		"1/stat":  "1 (init) S 0 1 1 0 -1 0 0 0 0 0 1 1 0 0 20 0 1 0 1 1000 100 0",
+		"42/stat": "42 (node) S 1 42 42 0 -1 0 0 0 0 0 10 5 0 0 20 0 1 0 1 1000 300 0",
+		"99/stat": "99 (sh) S 1 99 99 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 1 1000 200 0",
+	})
+
+	sampler := NewSampler(platform.Platform{OS: "linux"}, 2)
+	sampler.procRoot = root
+	sampler.previous = &procSample{processes: map[int]procProcess{1: {}, 42: {}, 99: {}}}
+	snapshot, err := sampler.Take(context.Background())
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if snapshot.CPU.Cores != 2 || snapshot.CPU.Load != [3]float64{0.5, 0.4, 0.3} {
+		t.Errorf("Take() CPU = %+v", snapshot.CPU)
+	}
+	if len(snapshot.Processes) != 2 || snapshot.Processes[0].Name != "node" || snapshot.Processes[1].Name != "init" {
+		t.Errorf("Take() processes = %+v, want node and init", snapshot.Processes)
+	}
+	if snapshot.Memory.Total != 16000000*1024 {
+		t.Errorf("Take() memory = %+v", snapshot.Memory)
+	}
+}
+
+func TestParsePS(t *testing.T) {
+	output := `    1   0.0  12000 /sbin/launchd
+  512  25.3 204800 /Applications/Google Chrome.app/Contents/MacOS/Google Chrome
+ bad
+`
+	want := []Process{
+		{PID: 1, Name: "launchd", CPU: 0, Memory: 12000 * 1024},
+		{PID: 512, Name: "Google Chrome", CPU: 25.3, Memory: 204800 * 1024},
+	}
+	if got := parsePS(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePS() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseMacOSMemory(t *testing.T) {
+	vmStat := `Mach Virtual Memory Statistics: (page size of 16384 bytes)
+Pages free:                               10000.
+Pages active:                            100000.
+Pages inactive:                           90000.
+Pages wired down:                         50000.
+Pages occupied by compressor:             25000.
+`
+	if got, want := parseVMStat(vmStat), uint64(175000*16384); got != want {
+		t.Errorf("parseVMStat() = %d, want %d", got, want)
+	}
+
+	total, used := parseSwapUsage("total = 2048.00M  used = 512.50M  free = 1535.50M  (encrypted)\n")
+	if total != 2048<<20 || used != uint64(512.5*(1<<20)) {
+		t.Errorf("parseSwapUsage() = %d, %d", total, used)
+	}
+}
+
+func TestParsePmset(t *testing.T) {
+	tests := []struct {
+		output string
+		want   *Battery
+	}{
+		{"Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t82%; charging; 1:20 remaining present: true\n", &Battery{Percent: 82, Status: "charging"}},
+		{"Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n", &Battery{Percent: 100, Status: "full"}},
+		{"Now drawing from 'AC Power'\n", nil},
+	}
+	for _, tt := range tests {
+		if got := parsePmset(tt.output); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePmset(%q) = %+v, want %+v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	output := `{"Cores":8,"Usage":34,"TotalKB":16000000,"FreeKB":6000000,"SwapTotalKB":2000000,"SwapFreeKB":1500000,"Battery":64,"BatteryStatus":2,` +
+		`"Disks":[{"DeviceID":"C:","Size":500000000000,"FreeSpace":200000000000}],` +
+		`"Processes":[{"Id":1234,"Name":"Code","CPU":12.5,"Memory":400000000},{"Id":4,"Name":"System","CPU":-1,"Memory":100}]}`
+	snapshot, err := parseWindows([]byte(output))
+	if err != nil {
+		t.Fatalf("parseWindows() error = %v", err)
+	}
+	want := &Snapshot{
+		CPU:     CPU{Cores: 8, Usage: 34},
+		Memory:  Memory{Total: 16000000 * 1024, Used: 10000000 * 1024, SwapTotal: 2000000 * 1024, SwapUsed: 500000 * 1024},
+		Disks:   []Disk{{Path: "C:", Total: 500000000000, Used: 300000000000}},
+		Battery: &Battery{Percent: 64, Status: "plugged in"},
+		Processes: []Process{
+			{PID: 1234, Name: "Code", CPU: 12.5, Memory: 400000000},
+			{PID: 4, Name: "System", CPU: 0, Memory: 100},
+		},
+	}
+	if !reflect.DeepEqual(snapshot, want) {
+		t.Errorf("parseWindows() = %+v, want %+v", snapshot, want)
+	}
+
+	snapshot, err = parseWindows([]byte(`{"Cores":4,"Battery":null,"Disks":[],"Processes":[]}`))
+	if err != nil || snapshot.Battery != nil {
+		t.Errorf("parseWindows() without a battery = %+v, %v", snapshot, err)
+	}
+}
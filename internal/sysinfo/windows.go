@@ -0,0 +1,103 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// windowsScript prints the snapshot as JSON. Process CPU use is measured
+// over half a second, as Get-Process only reports the CPU time so far.
+const windowsScript = `$ErrorActionPreference = 'SilentlyContinue'
+$before = @{}; Get-Process | ForEach-Object { $before[$_.Id] = [double]$_.CPU }
+Start-Sleep -Milliseconds 500
+$processes = Get-Process | ForEach-Object {
+  [pscustomobject]@{ Id = $_.Id; Name = $_.ProcessName; CPU = ([double]$_.CPU - [double]$before[$_.Id]) * 200; Memory = $_.WorkingSet64 }
+}
+$os = Get-CimInstance Win32_OperatingSystem
+$battery = Get-CimInstance Win32_Battery | Select-Object -First 1
+[pscustomobject]@{
+  Cores = [int]$env:NUMBER_OF_PROCESSORS
+  Usage = (Get-CimInstance Win32_Processor | Measure-Object -Property LoadPercentage -Average).Average
+  TotalKB = $os.TotalVisibleMemorySize
+  FreeKB = $os.FreePhysicalMemory
+  SwapTotalKB = $os.SizeStoredInPagingFiles
+  SwapFreeKB = $os.FreeSpaceInPagingFiles
+  Battery = $battery.EstimatedChargeRemaining
+  BatteryStatus = $battery.BatteryStatus
+  Disks = @(Get-CimInstance Win32_LogicalDisk -Filter 'DriveType=3' | Select-Object DeviceID, Size, FreeSpace)
+  Processes = @($processes)
+} | ConvertTo-Json -Depth 3 -Compress`
+
+// windowsSnapshot is the JSON printed by windowsScript.
+type windowsSnapshot struct {
+	Cores         int
+	Usage         float64
+	TotalKB       uint64
+	FreeKB        uint64
+	SwapTotalKB   uint64
+	SwapFreeKB    uint64
+	Battery       *int
+	BatteryStatus int
+	Disks         []struct {
+		DeviceID  string
+		Size      uint64
+		FreeSpace uint64
+	}
+	Processes []struct {
+		ID     int `json:"Id"`
+		Name   string
+		CPU    float64
+		Memory uint64
+	}
+}
+
+func takeWindows(ctx context.Context) (*Snapshot, error) {
+	output, err := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsScript).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run PowerShell: %w", err)
+	}
+	return parseWindows(output)
+}
+
+// parseWindows parses the output of windowsScript.
+func parseWindows(output []byte) (*Snapshot, error) {
+	var raw windowsSnapshot
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse the system information: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		CPU: CPU{Cores: raw.Cores, Usage: raw.Usage},
+		Memory: Memory{
+			Total:     raw.TotalKB * 1024,
+			Used:      (raw.TotalKB - min(raw.FreeKB, raw.TotalKB)) * 1024,
+			SwapTotal: raw.SwapTotalKB * 1024,
+			SwapUsed:  (raw.SwapTotalKB - min(raw.SwapFreeKB, raw.SwapTotalKB)) * 1024,
+		},
+	}
+	for _, disk := range raw.Disks {
+		snapshot.Disks = append(snapshot.Disks, Disk{Path: disk.DeviceID, Total: disk.Size, Used: disk.Size - min(disk.FreeSpace, disk.Size)})
+	}
+	if raw.Battery != nil {
+		snapshot.Battery = &Battery{Percent: *raw.Battery, Status: windowsBatteryStatus(raw.BatteryStatus)}
+	}
+	for _, process := range raw.Processes {
+		snapshot.Processes = append(snapshot.Processes, Process{PID: process.ID, Name: process.Name, CPU: max(process.CPU, 0), Memory: process.Memory})
+	}
+	return snapshot, nil
+}
+
+// windowsBatteryStatus names a BatteryStatus of Win32_Battery.
+func windowsBatteryStatus(status int) string {
+	switch status {
+	case 1:
+		return "discharging"
+	case 3:
+		return "full"
+	case 6, 7, 8, 9:
+		return "charging"
+	}
+	return "plugged in"
+}
@@ -0,0 +1,204 @@
+// Package testgen works out where the tests of a source file belong and
+// which framework they use, following the conventions of its project, so
+// that generated tests fit in next to the existing ones.
+package testgen
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/timfewi/aura-cli-go/internal/failure"
+	"github.com/timfewi/aura-cli-go/internal/projectinfo"
+)
+
+// Target is where and how the tests of a source file are written.
+type Target struct {
+	Language string
+	// Framework is the test framework, such as pytest or Vitest.
+	Framework string
+	// Path is the slash separated path of the test file relative to the
+	// project root.
+	Path string
+	// Example is an existing test file of the project to take the style
+	// from, or "" if there is none.
+	Example string
+}
+
+// Plan returns the target of the tests of file, a slash separated path
+// relative to root. files are the files of the project, as returned by
+// projectinfo.ListFiles, among which existing tests are looked for.
+func Plan(root, file string, files []string) (Target, error) {
+	file = path.Clean(filepath.ToSlash(file))
+	language := projectinfo.Language(file)
+	if IsTestFile(language, file) {
+		return Target{}, failure.New(failure.UserInput, "%s is a test file already", file)
+	}
+
+	var tests []string
+	for _, candidate := range files {
+		if sameFamily(projectinfo.Language(candidate), language) && IsTestFile(language, candidate) {
+			tests = append(tests, candidate)
+		}
+	}
+
+	target := Target{Language: language, Example: closest(file, tests)}
+	dir, base := path.Split(file)
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	switch language {
+	case "Go":
+		target.Framework = "testing"
+		target.Path = dir + stem + "_test.go"
+	case "Python":
+		target.Framework = pythonFramework(root)
+		target.Path = dir + "test_" + base
+		if inTestsDir(tests) || isDir(filepath.Join(root, "tests")) {
+			target.Path = "tests/test_" + base
+		}
+	case "JavaScript", "TypeScript":
+		target.Framework = javascriptFramework(root)
+		infix := ".test"
+		if count(tests, ".spec.") > count(tests, ".test.") {
+			infix = ".spec"
+		}
+		if count(tests, "__tests__/") > len(tests)/2 {
+			dir += "__tests__/"
+		}
+		target.Path = dir + stem + infix + ext
+	default:
+		return Target{}, failure.New(failure.UserInput, "generating tests for %s is not supported, only Go, Python, JavaScript and TypeScript", describeLanguage(language, file))
+	}
+	return target, nil
+}
+
+// describeLanguage names the language of file in errors.
+func describeLanguage(language, file string) string {
+	if language == "" {
+		return path.Base(file)
+	}
+	return language
+}
+
+// IsTestFile reports whether file is a test file of language.
+func IsTestFile(language, file string) bool {
+	base := path.Base(file)
+	switch language {
+	case "Go":
+		return strings.HasSuffix(base, "_test.go")
+	case "Python":
+		return strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py")
+	case "JavaScript", "TypeScript":
+		return strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") || strings.Contains(file, "__tests__/")
+	}
+	return false
+}
+
+// sameFamily reports whether tests in language b can serve as an example
+// for language a. JavaScript and TypeScript projects often mix both.
+func sameFamily(a, b string) bool {
+	script := map[string]bool{"JavaScript": true, "TypeScript": true}
+	return a == b || script[a] && script[b]
+}
+
+// closest returns the test among tests sharing the longest directory
+// prefix with file, or "" if there are none.
+func closest(file string, tests []string) string {
+	best, bestShared := "", -1
+	for _, test := range tests {
+		shared := sharedPrefix(path.Dir(file), path.Dir(test))
+		if shared > bestShared {
+			best, bestShared = test, shared
+		}
+	}
+	return best
+}
+
+// sharedPrefix counts the leading directories a and b have in common.
+func sharedPrefix(a, b string) int {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	n := 0
+	for n < len(as) && n < len(bs) && as[n] == bs[n] {
+		n++
+	}
+	return n
+}
+
+// count counts the paths containing substr.
+func count(paths []string, substr string) int {
+	n := 0
+	for _, p := range paths {
+		if strings.Contains(p, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+// inTestsDir reports whether any of tests is in a tests directory at the
+// project root.
+func inTestsDir(tests []string) bool {
+	for _, test := range tests {
+		if strings.HasPrefix(test, "tests/") {
+			return true
+		}
+	}
+	return false
+}
+
+func isDir(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.IsDir()
+}
+
+// pythonFramework returns pytest if the project in root uses it, else
+// unittest.
+func pythonFramework(root string) string {
+	for _, marker := range []string{"pytest.ini", "conftest.py", "tests/conftest.py"} {
+		if _, err := os.Stat(filepath.Join(root, marker)); err == nil {
+			return "pytest"
+		}
+	}
+	for _, manifest := range []string{"pyproject.toml", "setup.cfg", "requirements.txt", "requirements-dev.txt", "tox.ini"} {
+		content, err := os.ReadFile(filepath.Join(root, manifest))
+		if err == nil && strings.Contains(string(content), "pytest") {
+			return "pytest"
+		}
+	}
+	return "unittest"
+}
+
+// javascriptFrameworks are the test frameworks looked for in package.json,
+// in order of precedence.
+var javascriptFrameworks = []struct{ pkg, name string }{
+	{"vitest", "Vitest"},
+	{"jest", "Jest"},
+	{"mocha", "Mocha"},
+}
+
+// javascriptFramework returns the test framework of the package.json in
+// root, or node:test, the runner built into Node.js.
+func javascriptFramework(root string) string {
+	content, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return "node:test"
+	}
+	var manifest struct {
+		Dependencies    map[string]any `json:"dependencies"`
+		DevDependencies map[string]any `json:"devDependencies"`
+	}
+	if json.Unmarshal(content, &manifest) != nil {
+		return "node:test"
+	}
+	for _, framework := range javascriptFrameworks {
+		_, dep := manifest.Dependencies[framework.pkg]
+		_, dev := manifest.DevDependencies[framework.pkg]
+		if dep || dev {
+			return framework.name
+		}
+	}
+	return "node:test"
+}
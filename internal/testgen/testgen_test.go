@@ -0,0 +1,74 @@
+package testgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest map[string]string
+		files    []string
+		file     string
+		want     Target
+	}{
+		{
+			name:  "go",
+			files: []string{"internal/cmd/root.go", "internal/cmd/root_test.go", "internal/ai/client_test.go"},
+			file:  "internal/ai/client.go",
+			want:  Target{Language: "Go", Framework: "testing", Path: "internal/ai/client_test.go", Example: "internal/ai/client_test.go"},
+		},
+		{
+			name:     "pytest in tests directory",
+			manifest: map[string]string{"pyproject.toml": "[tool.pytest.ini_options]\n"},
+			files:    []string{"app/models.py", "tests/test_views.py"},
+			file:     "app/models.py",
+			want:     Target{Language: "Python", Framework: "pytest", Path: "tests/test_models.py", Example: "tests/test_views.py"},
+		},
+		{
+			name:  "unittest next to the module",
+			files: []string{"tool/cli.py"},
+			file:  "tool/cli.py",
+			want:  Target{Language: "Python", Framework: "unittest", Path: "tool/test_cli.py"},
+		},
+		{
+			name:     "vitest spec files",
+			manifest: map[string]string{"package.json": `{"devDependencies": {"vitest": "^1", "jest": "^29"}}`},
+			files:    []string{"src/utils/format.ts", "src/api/client.spec.ts", "src/utils/date.spec.js"},
+			file:     "src/utils/format.ts",
+			want:     Target{Language: "TypeScript", Framework: "Vitest", Path: "src/utils/format.spec.ts", Example: "src/utils/date.spec.js"},
+		},
+		{
+			name:     "jest in __tests__",
+			manifest: map[string]string{"package.json": `{"devDependencies": {"jest": "^29"}}`},
+			files:    []string{"lib/__tests__/parse.test.js"},
+			file:     "lib/format.js",
+			want:     Target{Language: "JavaScript", Framework: "Jest", Path: "lib/__tests__/format.test.js", Example: "lib/__tests__/parse.test.js"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.manifest {
+				os.WriteFile(filepath.Join(root, name), []byte(content), 0o644)
+			}
+			got, err := Plan(root, tt.file, tt.files)
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Plan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanRejects(t *testing.T) {
+	for _, file := range []string{"internal/ai/client_test.go", "src/app.test.ts", "README.md", "main.rs"} {
+		if _, err := Plan(t.TempDir(), file, nil); err == nil {
+			t.Errorf("Plan(%q) error = nil", file)
+		}
+	}
+}
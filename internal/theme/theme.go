@@ -0,0 +1,184 @@
+// Package theme colors Aura's output by role, such as success messages or
+// the active item of a prompt, with the styles of the configured theme.
+// Colors are left out when NO_COLOR is set, with --no-color, for dumb
+// terminals and when the output is not a terminal.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+)
+
+// Role is what a piece of output is, which decides its style.
+type Role string
+
+// Roles of output.
+const (
+	Active   Role = "active"
+	Inactive Role = "inactive"
+	Selected Role = "selected"
+	Success  Role = "success"
+	Warning  Role = "warning"
+	Error    Role = "error"
+	Info     Role = "info"
+	Muted    Role = "muted"
+	Heading  Role = "heading"
+	Command  Role = "command"
+)
+
+// roles lists all roles.
+var roles = []Role{Active, Inactive, Selected, Success, Warning, Error, Info, Muted, Heading, Command}
+
+// Theme maps roles to ANSI SGR parameters such as "1;32".
+type Theme struct {
+	Name   string
+	Styles map[Role]string
+}
+
+// DefaultName is the theme used when none is configured.
+const DefaultName = "default"
+
+// Themes are the built-in themes.
+var Themes = map[string]Theme{
+	"default": {Name: "default", Styles: map[Role]string{
+		Active:   "36",
+		Inactive: "37",
+		Selected: "32",
+		Success:  "32",
+		Warning:  "33",
+		Error:    "31",
+		Info:     "34",
+		Muted:    "2",
+		Heading:  "1",
+		Command:  "36",
+	}},
+	// Solarized accent colors in the 256 color palette
+	"solarized": {Name: "solarized", Styles: map[Role]string{
+		Active:   "38;5;37",
+		Inactive: "38;5;244",
+		Selected: "38;5;64",
+		Success:  "38;5;64",
+		Warning:  "38;5;136",
+		Error:    "38;5;160",
+		Info:     "38;5;33",
+		Muted:    "38;5;240",
+		Heading:  "1;38;5;166",
+		Command:  "38;5;37",
+	}},
+	"monochrome": {Name: "monochrome", Styles: map[Role]string{
+		Active:   "1",
+		Selected: "1",
+		Error:    "1",
+		Muted:    "2",
+		Heading:  "1;4",
+		Command:  "1",
+	}},
+}
+
+// Output is plain until Apply is called.
+var (
+	current = Themes[DefaultName]
+	colored = false
+)
+
+// Names returns the names of the built-in themes, sorted.
+func Names() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ColorEnabled reports whether output may be colored: not disabled by a
+// --no-color flag or NO_COLOR, on a terminal that isn't dumb.
+func ColorEnabled(noColorFlag bool) bool {
+	if noColorFlag || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Apply selects the theme with the given name, or turns colors off. It
+// updates the template functions and icons of prompts to match.
+func Apply(name string, color bool) error {
+	selected, ok := Themes[strings.ToLower(name)]
+	if !ok && name != "" {
+		return fmt.Errorf("unknown theme '%s', available: %s", name, strings.Join(Names(), ", "))
+	}
+	if !ok {
+		selected = Themes[DefaultName]
+	}
+	current, colored = selected, color
+
+	for _, role := range roles {
+		role := role
+		promptui.FuncMap[string(role)] = func(v interface{}) string {
+			return Paint(role, fmt.Sprint(v))
+		}
+	}
+
+	// Prompts use the colors of promptui in their templates and icons
+	for name, fn := range promptuiFuncs {
+		if color {
+			promptui.FuncMap[name] = fn
+		} else {
+			promptui.FuncMap[name] = func(v interface{}) string { return fmt.Sprint(v) }
+		}
+	}
+	icons := promptuiIcons
+	if !color {
+		icons = [5]string{"?", "✔", "⚠", "✗", "▸"}
+	}
+	promptui.IconInitial, promptui.IconGood, promptui.IconWarn, promptui.IconBad, promptui.IconSelect = icons[0], icons[1], icons[2], icons[3], icons[4]
+	return nil
+}
+
+// promptuiFuncs and promptuiIcons are the colored originals of promptui.
+var (
+	promptuiFuncs = func() map[string]interface{} {
+		funcs := make(map[string]interface{})
+		for name, fn := range promptui.FuncMap {
+			funcs[name] = fn
+		}
+		return funcs
+	}()
+	promptuiIcons = [5]string{promptui.IconInitial, promptui.IconGood, promptui.IconWarn, promptui.IconBad, promptui.IconSelect}
+)
+
+// Current returns the name of the theme in use.
+func Current() string {
+	return current.Name
+}
+
+// Colored reports whether output is colored.
+func Colored() bool {
+	return colored
+}
+
+// Paint returns s in the style of role, or unchanged without colors.
+func Paint(role Role, s string) string {
+	style := current.Styles[role]
+	if !colored || style == "" || s == "" {
+		return s
+	}
+	return "\033[" + style + "m" + s + "\033[0m"
+}
+
+// SelectTemplates returns the templates of a select prompt in the theme's
+// styles. field is the template expression of an item's label, such as "."
+// or ".Label".
+func SelectTemplates(field string) *promptui.SelectTemplates {
+	return &promptui.SelectTemplates{
+		Label:    "{{ . }}?",
+		Active:   "▸ {{ " + field + " | active }}",
+		Inactive: "  {{ " + field + " | inactive }}",
+		Selected: "✓ {{ " + field + " | selected }}",
+	}
+}
@@ -0,0 +1,68 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/manifoldco/promptui"
+)
+
+func TestApplyAndPaint(t *testing.T) {
+	defer Apply(DefaultName, false)
+
+	if err := Apply("solarized", true); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := Paint(Success, "done"); got != "\033[38;5;64mdone\033[0m" {
+		t.Errorf("Paint() = %q", got)
+	}
+	if got := promptui.FuncMap["active"].(func(interface{}) string)("item"); got != "\033[38;5;37mitem\033[0m" {
+		t.Errorf("Prompt function active = %q", got)
+	}
+
+	if err := Apply("Monochrome", true); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := Paint(Success, "done"); got != "done" {
+		t.Errorf("Expected no style for success in monochrome, got %q", got)
+	}
+
+	if err := Apply("neon", true); err == nil {
+		t.Error("Expected error for unknown theme")
+	}
+
+	if err := Apply(DefaultName, false); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := Paint(Error, "failed"); got != "failed" {
+		t.Errorf("Expected no colors, got %q", got)
+	}
+	if got := promptui.FuncMap["cyan"].(func(interface{}) string)("item"); got != "item" {
+		t.Errorf("Expected plain promptui colors, got %q", got)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(false) {
+		t.Error("Expected no colors with NO_COLOR")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if ColorEnabled(true) {
+		t.Error("Expected no colors with --no-color")
+	}
+}
+
+func TestThemesDefineKnownRoles(t *testing.T) {
+	known := make(map[Role]bool)
+	for _, role := range roles {
+		known[role] = true
+	}
+	for name, theme := range Themes {
+		for role := range theme.Styles {
+			if !known[role] {
+				t.Errorf("Theme %s styles unknown role %s", name, role)
+			}
+		}
+	}
+}
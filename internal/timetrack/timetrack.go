@@ -0,0 +1,104 @@
+// Package timetrack estimates the time spent in directories from moments of
+// activity: entering a directory and running commands. Activity less than
+// an idle threshold apart counts as time spent in the directory entered
+// last; longer gaps are breaks.
+package timetrack
+
+import (
+	"sort"
+	"time"
+)
+
+// Event is a moment of activity. Events with a directory enter it, those
+// without one, such as commands, continue the work in the current one.
+type Event struct {
+	Time time.Time
+	Dir  string
+}
+
+// Span is a stretch of time spent in a directory.
+type Span struct {
+	Dir   string
+	Start time.Time
+	End   time.Time
+}
+
+// Entry is the time spent on a project on a day.
+type Entry struct {
+	Day      time.Time
+	Project  string
+	Duration time.Duration
+}
+
+// Spans returns the time spent in directories according to events. Time
+// between two events counts if they are at most idle apart.
+func Spans(events []Event, idle time.Duration) []Span {
+	sorted := append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var spans []Span
+	dir := ""
+	for i, event := range sorted {
+		if i > 0 && dir != "" {
+			previous := sorted[i-1].Time
+			if gap := event.Time.Sub(previous); gap > 0 && gap <= idle {
+				if last := len(spans) - 1; last >= 0 && spans[last].Dir == dir && spans[last].End.Equal(previous) {
+					spans[last].End = event.Time
+				} else {
+					spans = append(spans, Span{Dir: dir, Start: previous, End: event.Time})
+				}
+			}
+		}
+		if event.Dir != "" {
+			dir = event.Dir
+		}
+	}
+	return spans
+}
+
+// ByDay sums spans between from and to per day and project, the project of
+// a span's directory. Entries are sorted by day, then by project.
+func ByDay(spans []Span, from, to time.Time, project func(dir string) string) []Entry {
+	type key struct {
+		day     time.Time
+		project string
+	}
+	totals := make(map[key]time.Duration)
+	for _, span := range spans {
+		start, end := span.Start, span.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		name := project(span.Dir)
+		// Spans over midnight count for both days.
+		for start.Before(end) {
+			day := midnight(start)
+			next := day.AddDate(0, 0, 1)
+			stop := end
+			if next.Before(stop) {
+				stop = next
+			}
+			totals[key{day, name}] += stop.Sub(start)
+			start = stop
+		}
+	}
+
+	entries := make([]Entry, 0, len(totals))
+	for k, duration := range totals {
+		entries = append(entries, Entry{Day: k.day, Project: k.project, Duration: duration})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Day.Equal(entries[j].Day) {
+			return entries[i].Day.Before(entries[j].Day)
+		}
+		return entries[i].Project < entries[j].Project
+	})
+	return entries
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,56 @@
+package timetrack
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 5, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestSpans(t *testing.T) {
+	events := []Event{
+		{Time: at(13, 9, 5)},                    // Before entering anything
+		{Time: at(13, 9, 10), Dir: "/code/api"}, // Enter api
+		{Time: at(13, 9, 20)},
+		{Time: at(13, 9, 30)},
+		{Time: at(13, 9, 40), Dir: "/code/web"}, // Switch to web
+		{Time: at(13, 9, 50)},
+		{Time: at(13, 11, 0)}, // After a break
+		{Time: at(13, 11, 5)},
+	}
+	want := []Span{
+		{Dir: "/code/api", Start: at(13, 9, 10), End: at(13, 9, 40)},
+		{Dir: "/code/web", Start: at(13, 9, 40), End: at(13, 9, 50)},
+		{Dir: "/code/web", Start: at(13, 11, 0), End: at(13, 11, 5)},
+	}
+	if got := Spans(events, 15*time.Minute); !reflect.DeepEqual(got, want) {
+		t.Errorf("Spans() = %v, want %v", got, want)
+	}
+}
+
+func TestByDay(t *testing.T) {
+	spans := []Span{
+		{Dir: "/code/api/cmd", Start: at(13, 8, 0), End: at(13, 10, 0)},
+		{Dir: "/code/api", Start: at(13, 23, 30), End: at(14, 0, 30)},
+		{Dir: "/tmp", Start: at(14, 9, 0), End: at(14, 9, 45)},
+	}
+	project := func(dir string) string {
+		if dir == "/tmp" {
+			return "tmp"
+		}
+		return "api"
+	}
+
+	got := ByDay(spans, at(13, 9, 0), at(15, 0, 0), project)
+	want := []Entry{
+		{Day: at(13, 0, 0), Project: "api", Duration: 90 * time.Minute},
+		{Day: at(14, 0, 0), Project: "api", Duration: 30 * time.Minute},
+		{Day: at(14, 0, 0), Project: "tmp", Duration: 45 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ByDay() = %v, want %v", got, want)
+	}
+}
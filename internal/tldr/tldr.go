@@ -0,0 +1,182 @@
+// Package tldr fetches, caches and renders pages in the tldr-pages format:
+// a title, a short description and a list of annotated usage examples.
+//
+//	# tar
+//
+//	> Archiving utility.
+//
+//	- Extract an archive:
+//
+//	`tar xf {{source.tar}}`
+package tldr
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+	"github.com/timfewi/aura-cli-go/internal/theme"
+)
+
+// Page is a parsed tldr page.
+type Page struct {
+	Name        string
+	Description []string
+	Examples    []Example
+}
+
+// Example is a usage example of a page.
+type Example struct {
+	Description string
+	// Command may contain {{placeholders}} for arguments.
+	Command string
+}
+
+// DefaultPagesURL is the base URL the pages of the tldr-pages project are
+// downloaded from.
+const DefaultPagesURL = "https://raw.githubusercontent.com/tldr-pages/tldr/main/pages"
+
+// ErrNotFound is returned when no page exists for a command.
+var ErrNotFound = errors.New("page not found")
+
+// namePattern matches command names that are safe to use in paths and URLs.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._+-]*$`)
+
+// ValidName reports whether name can be looked up.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Parse reads a page in the tldr-pages markdown format.
+func Parse(r io.Reader) (*Page, error) {
+	page := &Page{}
+	var pending string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "# "):
+			page.Name = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		case strings.HasPrefix(line, ">"):
+			page.Description = append(page.Description, strings.TrimSpace(strings.TrimPrefix(line, ">")))
+		case strings.HasPrefix(line, "- "):
+			pending = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		case strings.HasPrefix(line, "`") && strings.HasSuffix(line, "`") && len(line) > 1:
+			page.Examples = append(page.Examples, Example{Description: pending, Command: line[1 : len(line)-1]})
+			pending = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if page.Name == "" || len(page.Examples) == 0 {
+		return nil, fmt.Errorf("invalid tldr page")
+	}
+	return page, nil
+}
+
+// Render writes the page for the terminal, with placeholders shown without
+// their braces.
+func Render(w io.Writer, page *Page) {
+	fmt.Fprintf(w, "📘 %s\n\n", theme.Paint(theme.Heading, page.Name))
+	for _, line := range page.Description {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	if len(page.Description) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	for _, example := range page.Examples {
+		fmt.Fprintf(w, "  • %s\n", example.Description)
+		fmt.Fprintf(w, "    %s\n\n", theme.Paint(theme.Command, strings.NewReplacer("{{", "", "}}", "").Replace(example.Command)))
+	}
+}
+
+// Platforms returns the tldr-pages platform directories searched on this
+// system, most specific first.
+func Platforms() []string {
+	switch platform.Current().OS {
+	case "darwin":
+		return []string{"osx", "common"}
+	case "windows":
+		return []string{"windows", "common"}
+	default:
+		return []string{"linux", "common"}
+	}
+}
+
+// Cache stores pages on disk, one directory per source such as a platform.
+type Cache struct {
+	Dir string
+}
+
+// Get returns the cached page of name and its source, or ErrNotFound.
+func (c Cache) Get(name string, sources []string) ([]byte, string, error) {
+	for _, source := range sources {
+		content, err := os.ReadFile(filepath.Join(c.Dir, source, name+".md"))
+		if err == nil {
+			return content, source, nil
+		}
+	}
+	return nil, "", ErrNotFound
+}
+
+// Put stores the page of name from source.
+func (c Cache) Put(name, source string, content []byte) error {
+	dir := filepath.Join(c.Dir, source)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, name+".md"), content, 0644)
+}
+
+// Fetcher downloads pages of the tldr-pages project.
+type Fetcher struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewFetcher returns a fetcher of the official pages.
+func NewFetcher() *Fetcher {
+	return &Fetcher{BaseURL: DefaultPagesURL, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Fetch downloads the page of name from the first platform that has one,
+// and returns it with the platform.
+func (f *Fetcher) Fetch(ctx context.Context, name string, platforms []string) ([]byte, string, error) {
+	for _, platform := range platforms {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/%s.md", f.BaseURL, platform, name), nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := f.Client.Do(req)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to download page: %w", err)
+		}
+		content, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			continue
+		case resp.StatusCode != http.StatusOK:
+			return nil, "", fmt.Errorf("failed to download page: %s", resp.Status)
+		case err != nil:
+			return nil, "", fmt.Errorf("failed to download page: %w", err)
+		}
+		return content, platform, nil
+	}
+	return nil, "", ErrNotFound
+}
@@ -0,0 +1,89 @@
+package tldr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const tarPage = "# tar\n\n> Archiving utility.\n> More information: <https://www.gnu.org/software/tar>.\n\n- Create an archive from files:\n\n`tar cf {{target.tar}} {{file1 file2}}`\n\n- Extract an archive:\n\n`tar xf {{source.tar}}`\n"
+
+func TestParseAndRender(t *testing.T) {
+	page, err := Parse(strings.NewReader(tarPage))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if page.Name != "tar" || len(page.Description) != 2 || len(page.Examples) != 2 {
+		t.Fatalf("Unexpected page %+v", page)
+	}
+	if page.Examples[1].Description != "Extract an archive:" || page.Examples[1].Command != "tar xf {{source.tar}}" {
+		t.Errorf("Unexpected example %+v", page.Examples[1])
+	}
+
+	var out bytes.Buffer
+	Render(&out, page)
+	if !strings.Contains(out.String(), "tar xf source.tar") {
+		t.Errorf("Expected placeholders without braces, got:\n%s", out.String())
+	}
+
+	if _, err := Parse(strings.NewReader("just some text")); err == nil {
+		t.Error("Expected error for text that is no page")
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := Cache{Dir: t.TempDir()}
+
+	if _, _, err := cache.Get("tar", []string{"linux", "common"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := cache.Put("tar", "common", []byte(tarPage)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	content, source, err := cache.Get("tar", []string{"linux", "common"})
+	if err != nil || source != "common" || string(content) != tarPage {
+		t.Errorf("Get() = %q, %s, %v", content, source, err)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/common/tar.md" {
+			w.Write([]byte(tarPage))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	fetcher := &Fetcher{BaseURL: server.URL, Client: server.Client()}
+
+	content, platform, err := fetcher.Fetch(context.Background(), "tar", []string{"linux", "common"})
+	if err != nil || platform != "common" || string(content) != tarPage {
+		t.Errorf("Fetch() = %q, %s, %v", content, platform, err)
+	}
+
+	if _, _, err := fetcher.Fetch(context.Background(), "missing", []string{"linux", "common"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"tar":            true,
+		"docker-compose": true,
+		"7z":             true,
+		"../etc":         false,
+		"":               false,
+		"a b":            false,
+	} {
+		if got := ValidName(name); got != want {
+			t.Errorf("ValidName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
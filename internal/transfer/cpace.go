@@ -0,0 +1,126 @@
+package transfer
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+)
+
+// The key of a session is agreed with CPace, a password-authenticated key
+// exchange, on X25519: both sides derive a generator from the secret of the
+// code and exchange a random multiple of it. Someone watching the exchange
+// learns nothing to test guesses of the code with, and a peer with a wrong
+// code gets a different key, so each connection tests one guess.
+
+// exchangeSize is the size of the share each side sends.
+const exchangeSize = 32
+
+// errExchange is returned for a share that doesn't lead to a key, as sent by
+// a broken or malicious peer.
+var errExchange = errors.New("invalid key exchange")
+
+// curve25519 constants for the Elligator 2 map: the field prime 2^255-19
+// and the coefficient A of the curve.
+var (
+	fieldPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	curveA     = big.NewInt(486662)
+)
+
+// exchange is one side of a key exchange.
+type exchange struct {
+	private *ecdh.PrivateKey
+	// share is sent to the other side.
+	share []byte
+}
+
+// newExchange starts a key exchange on secret, bound to the session id sid.
+func newExchange(secret string, sid []byte) (*exchange, error) {
+	generator, err := ecdh.X25519().NewPublicKey(cpaceGenerator(secret, sid))
+	if err != nil {
+		return nil, err
+	}
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	share, err := private.ECDH(generator)
+	if err != nil {
+		return nil, err
+	}
+	return &exchange{private: private, share: share}, nil
+}
+
+// key returns the key agreed with the other side, which sent peer. The
+// shares of the sender and the receiver are bound in this order.
+func (e *exchange) key(sid, senderShare, receiverShare, peer []byte) ([]byte, error) {
+	public, err := ecdh.X25519().NewPublicKey(peer)
+	if err != nil {
+		return nil, errExchange
+	}
+	shared, err := e.private.ECDH(public)
+	if err != nil {
+		return nil, errExchange
+	}
+	h := sha512.New()
+	for _, part := range [][]byte{[]byte("aura-transfer-key"), sid, shared, senderShare, receiverShare} {
+		h.Write(lengthPrefixed(part))
+	}
+	return h.Sum(nil)[:32], nil
+}
+
+// cpaceGenerator returns the generator, a curve25519 u-coordinate, of
+// secret and sid.
+func cpaceGenerator(secret string, sid []byte) []byte {
+	h := sha512.New()
+	for _, part := range [][]byte{[]byte("CPace255"), []byte(secret), []byte("aura-transfer"), sid} {
+		h.Write(lengthPrefixed(part))
+	}
+	return elligator2(h.Sum(nil)[:32])
+}
+
+// elligator2 maps the little-endian field element r, of which the top bit
+// is ignored, to a u-coordinate of curve25519 (RFC 9380, section 6.7.1).
+func elligator2(r []byte) []byte {
+	p := fieldPrime
+	u := new(big.Int).SetBytes(reversed(r))
+	u.SetBit(u, 255, 0)
+	u.Mod(u, p)
+
+	// x1 = -A / (1 + 2u²), or -A where 1 + 2u² is 0
+	tv1 := new(big.Int).Mul(u, u)
+	tv1.Lsh(tv1, 1).Mod(tv1, p)
+	if tv1.Cmp(new(big.Int).Sub(p, big.NewInt(1))) == 0 {
+		tv1.SetInt64(0)
+	}
+	x1 := new(big.Int).Add(tv1, big.NewInt(1))
+	x1.ModInverse(x1, p)
+	x1.Mul(x1, new(big.Int).Neg(curveA)).Mod(x1, p)
+
+	// g(x1) = x1³ + A·x1² + x1 decides between x1 and -x1 - A
+	gx1 := new(big.Int).Add(x1, curveA)
+	gx1.Mul(gx1, x1).Add(gx1, big.NewInt(1)).Mul(gx1, x1).Mod(gx1, p)
+	x := x1
+	if big.Jacobi(gx1, p) < 0 {
+		x = new(big.Int).Neg(x1)
+		x.Sub(x, curveA).Mod(x, p)
+	}
+	return reversed(x.FillBytes(make([]byte, 32)))
+}
+
+// reversed returns a copy of b in reverse order, between the little-endian
+// encoding of curve25519 and the big-endian one of big.Int.
+func reversed(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
+
+// lengthPrefixed returns b after its length, so concatenated parts can't be
+// shifted into each other.
+func lengthPrefixed(b []byte) []byte {
+	return append([]byte{byte(len(b) >> 8), byte(len(b))}, b...)
+}
@@ -0,0 +1,245 @@
+package transfer
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mdnsGroup is the multicast group and port of multicast DNS.
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// serviceSuffix is the service type offers are found under.
+const serviceSuffix = "._aura-transfer._tcp.local."
+
+// DNS record types and flags used.
+const (
+	typeSRV     = 33
+	typeANY     = 255
+	flagQR      = 0x8000
+	flagAA      = 0x0400
+	classIN     = 1
+	classUnique = 0x8000
+)
+
+// serviceName returns the name the file offered under code is found by: the
+// public ID of the code, random and separate from its secret.
+func serviceName(code string) string {
+	return codeID(code) + serviceSuffix
+}
+
+// Advertise answers multicast DNS queries for the file offered under code
+// with port until ctx is done.
+func Advertise(ctx context.Context, code string, port int) error {
+	code, err := NormalizeCode(code)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroup)
+	if err != nil {
+		return fmt.Errorf("failed to listen for multicast DNS: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	name := serviceName(code)
+	buffer := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read multicast DNS: %w", err)
+		}
+		id, unicast, ok := asksFor(buffer[:n], name)
+		if !ok {
+			continue
+		}
+		// Queries from other ports than the multicast DNS one come from
+		// simple resolvers, which only read answers sent to them.
+		to := mdnsGroup
+		if unicast || from.Port != mdnsGroup.Port {
+			to = from
+		}
+		conn.WriteToUDP(srvResponse(id, name, port), to)
+	}
+}
+
+// Discover returns the address of the file offered under code, asking the
+// network again every second until ctx is done.
+func Discover(ctx context.Context, code string) (string, error) {
+	code, err := NormalizeCode(code)
+	if err != nil {
+		return "", err
+	}
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return "", fmt.Errorf("failed to open multicast DNS socket: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	name := serviceName(code)
+	query := srvQuery(name)
+	buffer := make([]byte, 9000)
+	for {
+		if _, err := conn.WriteToUDP(query, mdnsGroup); err != nil && ctx.Err() == nil {
+			return "", fmt.Errorf("failed to send multicast DNS query: %w", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			n, from, err := conn.ReadFromUDP(buffer)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			var timeout net.Error
+			if errors.As(err, &timeout) && timeout.Timeout() {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to read multicast DNS: %w", err)
+			}
+			if port, ok := parseSRV(buffer[:n], name); ok {
+				return net.JoinHostPort(from.IP.String(), strconv.Itoa(port)), nil
+			}
+		}
+	}
+}
+
+// srvQuery returns a query for the SRV record of name asking for a unicast
+// answer.
+func srvQuery(name string) []byte {
+	packet := make([]byte, 12)
+	binary.BigEndian.PutUint16(packet[4:], 1)
+	packet = appendName(packet, name)
+	packet = binary.BigEndian.AppendUint16(packet, typeSRV)
+	return binary.BigEndian.AppendUint16(packet, classIN|classUnique)
+}
+
+// srvResponse returns the answer to query id with the SRV record of name,
+// pointing to port on this host.
+func srvResponse(id uint16, name string, port int) []byte {
+	packet := make([]byte, 12)
+	binary.BigEndian.PutUint16(packet, id)
+	binary.BigEndian.PutUint16(packet[2:], flagQR|flagAA)
+	binary.BigEndian.PutUint16(packet[6:], 1)
+	packet = appendName(packet, name)
+	packet = binary.BigEndian.AppendUint16(packet, typeSRV)
+	packet = binary.BigEndian.AppendUint16(packet, classIN)
+	packet = binary.BigEndian.AppendUint32(packet, 120)
+
+	target := strings.TrimSuffix(name, serviceSuffix) + ".local."
+	data := make([]byte, 6)
+	binary.BigEndian.PutUint16(data[4:], uint16(port))
+	data = appendName(data, target)
+	packet = binary.BigEndian.AppendUint16(packet, uint16(len(data)))
+	return append(packet, data...)
+}
+
+// asksFor returns the ID of packet if it is a query for the SRV record of
+// name, and whether it asks for a unicast answer.
+func asksFor(packet []byte, name string) (id uint16, unicast, ok bool) {
+	if len(packet) < 12 || binary.BigEndian.Uint16(packet[2:])&flagQR != 0 {
+		return 0, false, false
+	}
+	offset := 12
+	for i := 0; i < int(binary.BigEndian.Uint16(packet[4:])); i++ {
+		question, next, err := readName(packet, offset)
+		if err != nil || next+4 > len(packet) {
+			return 0, false, false
+		}
+		qtype := binary.BigEndian.Uint16(packet[next:])
+		qclass := binary.BigEndian.Uint16(packet[next+2:])
+		offset = next + 4
+		if strings.EqualFold(question, name) && (qtype == typeSRV || qtype == typeANY) {
+			return binary.BigEndian.Uint16(packet), qclass&classUnique != 0, true
+		}
+	}
+	return 0, false, false
+}
+
+// parseSRV returns the port of the SRV record of name in packet, if it is an
+// answer with one.
+func parseSRV(packet []byte, name string) (int, bool) {
+	if len(packet) < 12 || binary.BigEndian.Uint16(packet[2:])&flagQR == 0 {
+		return 0, false
+	}
+	offset := 12
+	for i := 0; i < int(binary.BigEndian.Uint16(packet[4:])); i++ {
+		_, next, err := readName(packet, offset)
+		if err != nil {
+			return 0, false
+		}
+		offset = next + 4
+	}
+	records := int(binary.BigEndian.Uint16(packet[6:])) + int(binary.BigEndian.Uint16(packet[8:])) + int(binary.BigEndian.Uint16(packet[10:]))
+	for i := 0; i < records; i++ {
+		owner, next, err := readName(packet, offset)
+		if err != nil || next+10 > len(packet) {
+			return 0, false
+		}
+		rtype := binary.BigEndian.Uint16(packet[next:])
+		length := int(binary.BigEndian.Uint16(packet[next+8:]))
+		data := next + 10
+		if data+length > len(packet) {
+			return 0, false
+		}
+		if rtype == typeSRV && length >= 6 && strings.EqualFold(owner, name) {
+			return int(binary.BigEndian.Uint16(packet[data+4:])), true
+		}
+		offset = data + length
+	}
+	return 0, false
+}
+
+// appendName appends name, dot separated and ending in a dot, to packet.
+func appendName(packet []byte, name string) []byte {
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		packet = append(packet, byte(len(label)))
+		packet = append(packet, label...)
+	}
+	return append(packet, 0)
+}
+
+// readName reads the name at offset of packet, following compression
+// pointers, and returns it with the offset after it.
+func readName(packet []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	for jumps := 0; ; {
+		if offset >= len(packet) {
+			return "", 0, errors.New("name beyond the packet")
+		}
+		length := int(packet[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, ".") + ".", next, nil
+		case length&0xC0 == 0xC0:
+			if offset+1 >= len(packet) {
+				return "", 0, errors.New("pointer beyond the packet")
+			}
+			if jumps++; jumps > 16 {
+				return "", 0, errors.New("too many pointers")
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(packet[offset:]) & 0x3FFF)
+		default:
+			if offset+1+length > len(packet) {
+				return "", 0, errors.New("label beyond the packet")
+			}
+			labels = append(labels, string(packet[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
@@ -0,0 +1,418 @@
+// Package transfer sends a file between two machines on the same network.
+// The sender offers the file under a one-time code, receivers find it by the
+// first group of the code through multicast DNS and fetch it over TCP. The
+// rest of the code is a secret that never leaves the machines: everything
+// after the greeting is encrypted with a key agreed on it with CPace, see
+// newExchange, so only a receiver knowing the code gets the file, and the
+// sender only sends to one.
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// codeAlphabet leaves out characters easily mistaken for others, such as 0
+// and o or 1 and l.
+const codeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+// Lengths of a code: its first group is the public ID the offer is found
+// by, the others are the secret, about 40 bits. Guesses of the secret can
+// only be tested by connecting, maxAttempts of them.
+const (
+	codeLength   = 12
+	codeIDLength = 4
+	codeGroup    = 4
+)
+
+// NewCode returns a random one-time code, such as "k7m2-p9xq-h3td".
+func NewCode() (string, error) {
+	var b strings.Builder
+	for i := 0; i < codeLength; i++ {
+		if i > 0 && i%codeGroup == 0 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(codeAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate code: %w", err)
+		}
+		b.WriteByte(codeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// NormalizeCode returns code without case, dashes and spaces, or an error if
+// it can't be a code of NewCode.
+func NormalizeCode(code string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case r == '-' || r == ' ':
+		case strings.ContainsRune(codeAlphabet, r):
+			b.WriteRune(r)
+		default:
+			return "", fmt.Errorf("invalid code '%s'", code)
+		}
+	}
+	if b.Len() != codeLength {
+		return "", fmt.Errorf("invalid code '%s', it has %d characters", code, codeLength)
+	}
+	return b.String(), nil
+}
+
+// codeID returns the public ID of the normalized code, which the offer is
+// advertised under.
+func codeID(code string) string {
+	return code[:codeIDLength]
+}
+
+// codeSecret returns the secret of the normalized code, which only the key
+// exchange uses.
+func codeSecret(code string) string {
+	return code[codeIDLength:]
+}
+
+// File describes the file offered.
+type File struct {
+	Name string      `json:"name"`
+	Size int64       `json:"size"`
+	Mode os.FileMode `json:"mode"`
+}
+
+// reply is what the receiver answers the offer and the end of the content
+// with.
+type reply struct {
+	Error string `json:"error,omitempty"`
+}
+
+// greeting starts every connection, followed by the session ID and the
+// share of the sender in the key exchange.
+const greeting = "AURA-TRANSFER-2\n"
+
+// sidSize is the size of the random session ID.
+const sidSize = 16
+
+// chunkSize is the most content sent in one frame.
+const chunkSize = 64 << 10
+
+// maxFrame bounds the frames read, so a broken peer can't make the reader
+// allocate without limit.
+const maxFrame = chunkSize + 1024
+
+// ErrWrongCode is returned when the other side used a different code.
+var ErrWrongCode = errors.New("the code doesn't match, check it on the sending machine")
+
+// handshakeTimeout is how long the sender waits for a connection to prove
+// it knows the code.
+const handshakeTimeout = 10 * time.Second
+
+// maxAttempts is how many connections with a wrong code the sender tolerates
+// before giving up, so the code can't be guessed.
+const maxAttempts = 3
+
+// Serve offers file on listener until a receiver knowing code connects, then
+// sends it the content read from r. Progress is called with the number of
+// bytes sent so far.
+func Serve(ctx context.Context, listener net.Listener, code string, file File, r io.Reader, progress func(int64)) error {
+	code, err := NormalizeCode(code)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer stop()
+
+	for attempts := 0; ; {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		session, err := accept(conn, code)
+		if errors.Is(err, ErrWrongCode) {
+			conn.Close()
+			if attempts++; attempts == maxAttempts {
+				return fmt.Errorf("gave up after %d connections with a wrong code", maxAttempts)
+			}
+			continue
+		}
+		if err != nil {
+			conn.Close()
+			continue
+		}
+		err = session.send(file, r, progress)
+		conn.Close()
+		return err
+	}
+}
+
+// accept greets a connecting receiver, agrees on a key with it and checks it
+// knows code.
+func accept(conn net.Conn, code string) (*session, error) {
+	sid := make([]byte, sidSize)
+	if _, err := rand.Read(sid); err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	ex, err := newExchange(codeSecret(code), sid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start key exchange: %w", err)
+	}
+	// Connections that never say anything, such as port scans, don't hold
+	// up the transfer.
+	conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	hello := append(append([]byte(greeting), sid...), ex.share...)
+	if _, err := conn.Write(hello); err != nil {
+		return nil, err
+	}
+	peer := make([]byte, exchangeSize)
+	if _, err := io.ReadFull(conn, peer); err != nil {
+		return nil, err
+	}
+	key, err := ex.key(sid, ex.share, peer, peer)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newSession(conn, key, senderSide)
+	if err != nil {
+		return nil, err
+	}
+	// The receiver proves it knows the code by sending a frame the key
+	// opens.
+	if _, err := s.read(); err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return s, nil
+}
+
+func (s *session) send(file File, r io.Reader, progress func(int64)) error {
+	header, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := s.write(header); err != nil {
+		return fmt.Errorf("failed to send offer: %w", err)
+	}
+	if err := s.readReply(); err != nil {
+		return err
+	}
+
+	buffer := make([]byte, chunkSize)
+	var sent int64
+	for {
+		n, err := io.ReadFull(r, buffer)
+		if n > 0 {
+			if err := s.write(buffer[:n]); err != nil {
+				return fmt.Errorf("failed to send: %w", err)
+			}
+			sent += int64(n)
+			if progress != nil {
+				progress(sent)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+	if sent != file.Size {
+		return fmt.Errorf("the file changed size while sending it")
+	}
+	// An empty frame ends the content, so a cut connection can't pass for
+	// a shorter file.
+	if err := s.write(nil); err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+	return s.readReply()
+}
+
+// Receive fetches the file offered on conn under code. Accept is called with
+// the offered file and returns where to write its content, or an error to
+// decline it. Progress is called with the number of bytes received so far.
+func Receive(conn net.Conn, code string, accept func(File) (io.Writer, error), progress func(int64)) (File, error) {
+	code, err := NormalizeCode(code)
+	if err != nil {
+		return File{}, err
+	}
+	reader := bufio.NewReader(conn)
+	hello := make([]byte, len(greeting)+sidSize+exchangeSize)
+	if _, err := io.ReadFull(reader, hello); err != nil || string(hello[:len(greeting)]) != greeting {
+		return File{}, fmt.Errorf("%s doesn't offer a file", conn.RemoteAddr())
+	}
+	sid, peer := hello[len(greeting):len(greeting)+sidSize], hello[len(greeting)+sidSize:]
+	ex, err := newExchange(codeSecret(code), sid)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to start key exchange: %w", err)
+	}
+	if _, err := conn.Write(ex.share); err != nil {
+		return File{}, err
+	}
+	key, err := ex.key(sid, peer, ex.share, peer)
+	if err != nil {
+		return File{}, err
+	}
+	s, err := newSession(conn, key, receiverSide)
+	if err != nil {
+		return File{}, err
+	}
+	s.r = reader
+	if err := s.write([]byte("hello")); err != nil {
+		return File{}, err
+	}
+
+	header, err := s.read()
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return File{}, ErrWrongCode
+		}
+		return File{}, err
+	}
+	var file File
+	if err := json.Unmarshal(header, &file); err != nil {
+		return File{}, fmt.Errorf("invalid offer: %w", err)
+	}
+	// The name becomes a path on this machine, so it may not lead elsewhere.
+	if file.Name == "" || file.Name == "." || file.Name == ".." || strings.ContainsAny(file.Name, "/\\\x00") {
+		s.writeReply(fmt.Errorf("invalid file name"))
+		return File{}, fmt.Errorf("the sender offered the invalid file name '%s'", file.Name)
+	}
+	w, err := accept(file)
+	if err != nil {
+		s.writeReply(err)
+		return file, err
+	}
+	if err := s.writeReply(nil); err != nil {
+		return file, err
+	}
+
+	var received int64
+	for {
+		chunk, err := s.read()
+		if err != nil {
+			return file, fmt.Errorf("transfer interrupted: %w", err)
+		}
+		if len(chunk) == 0 {
+			break
+		}
+		if _, err := w.Write(chunk); err != nil {
+			s.writeReply(err)
+			return file, fmt.Errorf("failed to write file: %w", err)
+		}
+		received += int64(len(chunk))
+		if progress != nil {
+			progress(received)
+		}
+	}
+	if received != file.Size {
+		err := fmt.Errorf("received %d of %d bytes", received, file.Size)
+		s.writeReply(err)
+		return file, err
+	}
+	return file, s.writeReply(nil)
+}
+
+// Sides of a session, which keep the nonces of both directions apart.
+const (
+	senderSide byte = iota
+	receiverSide
+)
+
+// session is an encrypted connection. Frames are a 4 byte length and the
+// sealed content, their nonces count up per direction.
+type session struct {
+	conn     net.Conn
+	r        io.Reader
+	aead     cipher.AEAD
+	side     byte
+	sent     uint64
+	received uint64
+}
+
+func newSession(conn net.Conn, key []byte, side byte) (*session, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &session{conn: conn, r: conn, aead: aead, side: side}, nil
+}
+
+func (s *session) nonce(side byte, counter uint64) []byte {
+	nonce := make([]byte, s.aead.NonceSize())
+	nonce[0] = side
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+	return nonce
+}
+
+func (s *session) write(data []byte) error {
+	sealed := s.aead.Seal(nil, s.nonce(s.side, s.sent), data, nil)
+	s.sent++
+	frame := make([]byte, 4, 4+len(sealed))
+	binary.BigEndian.PutUint32(frame, uint32(len(sealed)))
+	_, err := s.conn.Write(append(frame, sealed...))
+	return err
+}
+
+func (s *session) read() ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(s.r, length[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxFrame {
+		return nil, fmt.Errorf("frame of %d bytes is too large", size)
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(s.r, sealed); err != nil {
+		return nil, err
+	}
+	data, err := s.aead.Open(nil, s.nonce(1-s.side, s.received), sealed, nil)
+	if err != nil {
+		return nil, ErrWrongCode
+	}
+	s.received++
+	return data, nil
+}
+
+func (s *session) writeReply(err error) error {
+	var r reply
+	if err != nil {
+		r.Error = err.Error()
+	}
+	data, _ := json.Marshal(r)
+	return s.write(data)
+}
+
+func (s *session) readReply() error {
+	data, err := s.read()
+	if err != nil {
+		return fmt.Errorf("the receiver closed the connection: %w", err)
+	}
+	var r reply
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("invalid reply: %w", err)
+	}
+	if r.Error != "" {
+		return fmt.Errorf("the receiver declined: %s", r.Error)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodes(t *testing.T) {
+	code, err := NewCode()
+	if err != nil {
+		t.Fatalf("NewCode() error = %v", err)
+	}
+	if len(code) != 14 || code[4] != '-' || code[9] != '-' {
+		t.Errorf("NewCode() = %q, want three groups of %d characters", code, codeGroup)
+	}
+	if normalized, err := NormalizeCode(" K7M2 p9xq-H3TD"); err != nil || normalized != "k7m2p9xqh3td" {
+		t.Errorf("NormalizeCode() = %q, %v, want k7m2p9xqh3td", normalized, err)
+	}
+	for _, invalid := range []string{"k7m2-p9xq", "k7m2-p9xq-h3to", "k7m2-p9xq-h3td-2"} {
+		if _, err := NormalizeCode(invalid); err == nil {
+			t.Errorf("NormalizeCode(%q) succeeded", invalid)
+		}
+	}
+}
+
+// serve offers content under code on a local listener and returns its
+// address and the result of Serve.
+func serve(t *testing.T, code string, content []byte) (string, <-chan error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	result := make(chan error, 1)
+	go func() {
+		file := File{Name: "build.log", Size: int64(len(content)), Mode: 0o644}
+		result <- Serve(ctx, listener, code, file, bytes.NewReader(content), nil)
+	}()
+	return listener.Addr().String(), result
+}
+
+func receive(t *testing.T, address, code string, accept func(File) (io.Writer, error)) (File, error) {
+	t.Helper()
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	return Receive(conn, code, accept, nil)
+}
+
+func TestTransfer(t *testing.T) {
+	content := bytes.Repeat([]byte("line of the build log\n"), 10000)
+	address, result := serve(t, "k7m2-p9xq-h3td", content)
+
+	var received bytes.Buffer
+	file, err := receive(t, address, "K7M2P9XQH3TD", func(file File) (io.Writer, error) { return &received, nil })
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if file.Name != "build.log" || file.Size != int64(len(content)) || file.Mode != 0o644 {
+		t.Errorf("Receive() file = %+v", file)
+	}
+	if !bytes.Equal(received.Bytes(), content) {
+		t.Errorf("Receive() got %d bytes, want %d", received.Len(), len(content))
+	}
+	if err := <-result; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestTransferWrongCode(t *testing.T) {
+	address, result := serve(t, "k7m2-p9xq-h3td", []byte("secret"))
+
+	accept := func(File) (io.Writer, error) {
+		t.Error("the file was offered to a receiver with a wrong code")
+		return io.Discard, nil
+	}
+	for i := 0; i < maxAttempts; i++ {
+		if _, err := receive(t, address, "k7m2-p9xq-h3tf", accept); !errors.Is(err, ErrWrongCode) {
+			t.Errorf("Receive() error = %v, want ErrWrongCode", err)
+		}
+	}
+	if err := <-result; err == nil || !strings.Contains(err.Error(), "wrong code") {
+		t.Errorf("Serve() error = %v, want giving up", err)
+	}
+}
+
+func TestTransferDeclined(t *testing.T) {
+	address, result := serve(t, "k7m2-p9xq-h3td", []byte("content"))
+
+	declined := errors.New("build.log already exists")
+	if _, err := receive(t, address, "k7m2-p9xq-h3td", func(File) (io.Writer, error) { return nil, declined }); err != declined {
+		t.Errorf("Receive() error = %v, want %v", err, declined)
+	}
+	if err := <-result; err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Serve() error = %v, want the reason of the receiver", err)
+	}
+}
+
+func TestKeyExchange(t *testing.T) {
+	sid := []byte("0123456789abcdef")
+	agree := func(senderSecret, receiverSecret string) (senderKey, receiverKey []byte) {
+		sender, err := newExchange(senderSecret, sid)
+		if err != nil {
+			t.Fatalf("newExchange() error = %v", err)
+		}
+		receiver, err := newExchange(receiverSecret, sid)
+		if err != nil {
+			t.Fatalf("newExchange() error = %v", err)
+		}
+		if bytes.Contains(sender.share, []byte(senderSecret)) || bytes.Equal(sender.share, receiver.share) {
+			t.Errorf("shares %x and %x aren't random", sender.share, receiver.share)
+		}
+		senderKey, err = sender.key(sid, sender.share, receiver.share, receiver.share)
+		if err != nil {
+			t.Fatalf("key() error = %v", err)
+		}
+		receiverKey, err = receiver.key(sid, sender.share, receiver.share, sender.share)
+		if err != nil {
+			t.Fatalf("key() error = %v", err)
+		}
+		return senderKey, receiverKey
+	}
+
+	if senderKey, receiverKey := agree("p9xqh3td", "p9xqh3td"); !bytes.Equal(senderKey, receiverKey) {
+		t.Error("the same secret led to different keys")
+	}
+	if senderKey, receiverKey := agree("p9xqh3td", "p9xqh3tf"); bytes.Equal(senderKey, receiverKey) {
+		t.Error("different secrets led to the same key")
+	}
+
+	// A share of all zeros, the identity, leads to no key
+	ex, _ := newExchange("p9xqh3td", sid)
+	if _, err := ex.key(sid, ex.share, make([]byte, exchangeSize), make([]byte, exchangeSize)); err == nil {
+		t.Error("key() with the identity as share succeeded")
+	}
+}
+
+func TestElligator2(t *testing.T) {
+	for _, secret := range []string{"p9xqh3td", "23456789", "zzzzzzzz"} {
+		u := new(big.Int).SetBytes(reversed(cpaceGenerator(secret, nil)))
+		// u is on the curve if u³ + A·u² + u is a square
+		gu := new(big.Int).Add(u, curveA)
+		gu.Mul(gu, u).Add(gu, big.NewInt(1)).Mul(gu, u).Mod(gu, fieldPrime)
+		if big.Jacobi(gu, fieldPrime) < 0 {
+			t.Errorf("cpaceGenerator(%q) = %s, not on the curve", secret, u)
+		}
+	}
+	if bytes.Equal(cpaceGenerator("p9xqh3td", nil), cpaceGenerator("p9xqh3tf", nil)) {
+		t.Error("cpaceGenerator() is the same for different secrets")
+	}
+}
+
+func TestMulticastDNSPackets(t *testing.T) {
+	name := serviceName("k7m2p9xqh3td")
+	if name != "k7m2"+serviceSuffix {
+		t.Errorf("serviceName() = %q, want only the ID of the code", name)
+	}
+
+	if _, unicast, ok := asksFor(srvQuery(name), strings.ToUpper(name)); !ok || !unicast {
+		t.Errorf("asksFor() of the query = %v, %v, want a unicast query", unicast, ok)
+	}
+	if _, _, ok := asksFor(srvQuery("other"+serviceSuffix), name); ok {
+		t.Error("asksFor() matched a query for another name")
+	}
+	if _, _, ok := asksFor(srvResponse(0, name, 4242), name); ok {
+		t.Error("asksFor() matched a response")
+	}
+
+	if port, ok := parseSRV(srvResponse(7, name, 4242), name); !ok || port != 4242 {
+		t.Errorf("parseSRV() = %d, %v, want 4242", port, ok)
+	}
+	if _, ok := parseSRV(srvQuery(name), name); ok {
+		t.Error("parseSRV() matched a query")
+	}
+	if _, ok := parseSRV(srvResponse(7, name, 4242)[:30], name); ok {
+		t.Error("parseSRV() matched a truncated response")
+	}
+}
+
+func TestReadNameCompressed(t *testing.T) {
+	packet := appendName(make([]byte, 12), "_tcp.local.")
+	offset := len(packet)
+	// "abc" followed by a pointer to "_tcp.local." at offset 12.
+	packet = append(packet, 3, 'a', 'b', 'c', 0xC0, 12)
+	name, next, err := readName(packet, offset)
+	if err != nil || name != "abc._tcp.local." || next != len(packet) {
+		t.Errorf("readName() = %q, %d, %v", name, next, err)
+	}
+
+	loop := append(make([]byte, 12), 0xC0, 12)
+	if _, _, err := readName(loop, 12); err == nil {
+		t.Error("readName() of a pointer loop succeeded")
+	}
+}
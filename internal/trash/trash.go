@@ -0,0 +1,265 @@
+// Package trash moves files into a managed trash directory instead of
+// deleting them, so they can be restored later.
+//
+// Each trashed file or directory is stored as files/<id>/<name> next to
+// info/<id>.json, which records where it came from and when it was trashed.
+package trash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Item is a file or directory in the trash.
+type Item struct {
+	ID           string    `json:"id"`
+	OriginalPath string    `json:"original_path"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	IsDir        bool      `json:"is_dir"`
+	Size         int64     `json:"size"`
+}
+
+// Name returns the base name of the item.
+func (i Item) Name() string {
+	return filepath.Base(i.OriginalPath)
+}
+
+// ErrNotFound is returned when no item matches a reference.
+var ErrNotFound = errors.New("not found in the trash")
+
+// Trash is a trash directory.
+type Trash struct {
+	Dir string
+}
+
+// Move moves path into the trash and returns its item.
+func (t Trash) Move(path string) (*Item, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	info, err := os.Lstat(absPath)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(absPath+string(filepath.Separator), filepath.Clean(t.Dir)+string(filepath.Separator)) {
+		return nil, fmt.Errorf("'%s' is inside the trash", path)
+	}
+
+	item := &Item{
+		ID:           newID(),
+		OriginalPath: absPath,
+		DeletedAt:    time.Now(),
+		IsDir:        info.IsDir(),
+		Size:         size(absPath),
+	}
+
+	dir := filepath.Join(t.Dir, "files", item.ID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	if err := move(absPath, filepath.Join(dir, item.Name())); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to move to the trash: %w", err)
+	}
+	if err := t.writeInfo(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// List returns the items in the trash, most recently trashed first.
+func (t Trash) List() ([]Item, error) {
+	entries, err := os.ReadDir(filepath.Join(t.Dir, "info"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var items []Item
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(t.Dir, "info", entry.Name()))
+		if err != nil {
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal(content, &item); err != nil || item.ID == "" {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items, nil
+}
+
+// Find returns the most recently trashed item whose ID, name or original
+// path is ref.
+func (t Trash) Find(ref string) (*Item, error) {
+	items, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+	absRef, _ := filepath.Abs(ref)
+	for _, item := range items {
+		if item.ID == ref || item.Name() == ref || item.OriginalPath == absRef {
+			return &item, nil
+		}
+	}
+	return nil, fmt.Errorf("'%s' %w", ref, ErrNotFound)
+}
+
+// Restore moves an item back to target, or to where it came from if target
+// is empty. It returns the restored path.
+func (t Trash) Restore(item *Item, target string) (string, error) {
+	if target == "" {
+		target = item.OriginalPath
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return "", fmt.Errorf("'%s' already exists", target)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := move(filepath.Join(t.Dir, "files", item.ID, item.Name()), target); err != nil {
+		return "", fmt.Errorf("failed to restore: %w", err)
+	}
+	return target, t.remove(item)
+}
+
+// Empty permanently deletes the items trashed before the given time, or all
+// items if before is zero, and returns the number of deleted items.
+func (t Trash) Empty(before time.Time) (int, error) {
+	items, err := t.List()
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, item := range items {
+		if !before.IsZero() && item.DeletedAt.After(before) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(t.Dir, "files", item.ID)); err != nil {
+			return deleted, fmt.Errorf("failed to delete '%s': %w", item.Name(), err)
+		}
+		if err := t.remove(&item); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (t Trash) writeInfo(item *Item) error {
+	dir := filepath.Join(t.Dir, "info")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	content, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, item.ID+".json"), content, 0600); err != nil {
+		return fmt.Errorf("failed to write trash info: %w", err)
+	}
+	return nil
+}
+
+// remove deletes the info of an item and its empty files directory.
+func (t Trash) remove(item *Item) error {
+	os.Remove(filepath.Join(t.Dir, "files", item.ID))
+	if err := os.Remove(filepath.Join(t.Dir, "info", item.ID+".json")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to update trash info: %w", err)
+	}
+	return nil
+}
+
+// newID returns a unique ID that sorts by time.
+func newID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// move renames src to dst, copying across file systems.
+func move(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// size returns the size of a file or the total size of a directory.
+func size(path string) int64 {
+	var total int64
+	filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			if info, err := entry.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
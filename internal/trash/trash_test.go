@@ -0,0 +1,101 @@
+package trash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMoveAndRestore(t *testing.T) {
+	trash := Trash{Dir: filepath.Join(t.TempDir(), "trash")}
+	work := t.TempDir()
+
+	file := filepath.Join(work, "notes.txt")
+	os.WriteFile(file, []byte("hello"), 0644)
+	dir := filepath.Join(work, "build")
+	os.MkdirAll(filepath.Join(dir, "out"), 0755)
+	os.WriteFile(filepath.Join(dir, "out", "app"), []byte("binary"), 0755)
+
+	fileItem, err := trash.Move(file)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be moved", file)
+	}
+	if fileItem.OriginalPath != file || fileItem.Size != 5 || fileItem.IsDir {
+		t.Errorf("Unexpected item %+v", fileItem)
+	}
+
+	dirItem, err := trash.Move(dir)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if !dirItem.IsDir || dirItem.Size != 6 {
+		t.Errorf("Unexpected item %+v", dirItem)
+	}
+
+	items, err := trash.List()
+	if err != nil || len(items) != 2 {
+		t.Fatalf("List() = %+v, %v", items, err)
+	}
+
+	found, err := trash.Find("notes.txt")
+	if err != nil || found.ID != fileItem.ID {
+		t.Fatalf("Find() = %+v, %v", found, err)
+	}
+	if _, err := trash.Find("missing.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	os.WriteFile(file, []byte("new"), 0644)
+	if _, err := trash.Restore(found, ""); err == nil {
+		t.Error("Expected error when restoring over an existing file")
+	}
+	os.Remove(file)
+	if restored, err := trash.Restore(found, ""); err != nil || restored != file {
+		t.Fatalf("Restore() = %s, %v", restored, err)
+	}
+	if content, _ := os.ReadFile(file); string(content) != "hello" {
+		t.Errorf("Restored content = %q", content)
+	}
+
+	target := filepath.Join(work, "restored")
+	if _, err := trash.Restore(dirItem, target); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "out", "app")); err != nil {
+		t.Errorf("Expected restored directory: %v", err)
+	}
+
+	if items, _ := trash.List(); len(items) != 0 {
+		t.Errorf("Expected empty trash, got %+v", items)
+	}
+	if _, err := trash.Move(trash.Dir); err == nil {
+		t.Error("Expected error when trashing the trash")
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	trash := Trash{Dir: t.TempDir()}
+	work := t.TempDir()
+
+	for _, name := range []string{"a", "b"} {
+		os.WriteFile(filepath.Join(work, name), []byte(name), 0644)
+		if _, err := trash.Move(filepath.Join(work, name)); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+	}
+
+	if deleted, err := trash.Empty(time.Now().Add(-time.Hour)); err != nil || deleted != 0 {
+		t.Errorf("Empty() of older items = %d, %v, want 0", deleted, err)
+	}
+	if deleted, err := trash.Empty(time.Time{}); err != nil || deleted != 2 {
+		t.Errorf("Empty() = %d, %v, want 2", deleted, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(trash.Dir, "files")); len(entries) != 0 {
+		t.Errorf("Expected no files left, got %d", len(entries))
+	}
+}
@@ -0,0 +1,22 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/platform"
+)
+
+// Command returns the command running command in the shell, stopped together
+// with the processes it started when ctx is cancelled. It doesn't read from
+// the terminal, which stays with the watcher.
+func Command(ctx context.Context, command string) *exec.Cmd {
+	cmd := platform.Current().ShellCommandContext(ctx, command)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	setProcessGroup(cmd)
+	cmd.WaitDelay = 2 * time.Second
+	return cmd
+}
@@ -0,0 +1,14 @@
+package watch
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup makes cancelling cmd kill the processes it started as
+// well, so they don't outlive a restart.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+	}
+}
@@ -0,0 +1,173 @@
+// Package watch polls a directory tree for changes, so commands can be rerun
+// when files change without platform specific file system notifications.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/timfewi/aura-cli-go/internal/index"
+)
+
+// DefaultIgnore lists glob patterns of files and directories that never
+// trigger a change, matched against the name and the path relative to the
+// root.
+var DefaultIgnore = []string{".git", ".hg", ".svn", ".idea", ".vscode", "node_modules", "vendor", "venv", ".venv", "__pycache__", "target", "dist", "build", ".DS_Store", "*.swp", "*~", "*.tmp"}
+
+// Options configures what is watched.
+type Options struct {
+	// Root is the watched directory.
+	Root string
+	// Ignore lists additional glob patterns to ignore. Files ignored by the
+	// .gitignore file of the root are ignored as well.
+	Ignore []string
+	// Interval is how often the tree is scanned.
+	Interval time.Duration
+	// Debounce is how long the tree must stay unchanged after a change
+	// before it is reported, so a burst of writes is reported once.
+	Debounce time.Duration
+}
+
+// Snapshot holds the modification time and size of the files of a tree.
+type Snapshot map[string]fileState
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Scan returns the snapshot of the files below opts.Root that are not
+// ignored.
+func Scan(opts Options) (Snapshot, error) {
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	gitignore := index.ReadIgnoreRules(root)
+	snapshot := make(Snapshot)
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files removed during the walk and unreadable directories are
+			// skipped
+			if entry != nil && entry.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if ignored(opts, gitignore, rel, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		snapshot[rel] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return snapshot, err
+}
+
+// ignored reports whether rel is ignored by the default or given patterns,
+// or the .gitignore file of the root.
+func ignored(opts Options, gitignore *index.IgnoreRules, rel string, isDir bool) bool {
+	name := filepath.Base(rel)
+	for _, patterns := range [][]string{DefaultIgnore, opts.Ignore} {
+		for _, pattern := range patterns {
+			if index.MatchGlob(pattern, name) || index.MatchGlob(pattern, rel) {
+				return true
+			}
+		}
+	}
+	return gitignore.Ignored(rel, isDir)
+}
+
+// Diff returns the sorted paths that were added, removed or changed between
+// two snapshots.
+func Diff(before, after Snapshot) []string {
+	var changed []string
+	for path, state := range after {
+		if previous, ok := before[path]; !ok || !previous.modTime.Equal(state.modTime) || previous.size != state.size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Watch scans the tree every interval and calls onChange with the changed
+// paths once the tree stays unchanged for the debounce time. It returns when
+// ctx is done.
+func Watch(ctx context.Context, opts Options, onChange func(paths []string)) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+
+	current, err := Scan(opts)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[string]bool)
+	var lastChange time.Time
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next, err := Scan(opts)
+		if err != nil {
+			if _, statErr := os.Stat(opts.Root); statErr != nil {
+				return err
+			}
+			continue
+		}
+		if changed := Diff(current, next); len(changed) > 0 {
+			for _, path := range changed {
+				pending[path] = true
+			}
+			lastChange = time.Now()
+		}
+		current = next
+
+		if len(pending) > 0 && time.Since(lastChange) >= opts.Debounce {
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			onChange(paths)
+		}
+	}
+}
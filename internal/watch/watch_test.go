@@ -0,0 +1,79 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanAndDiff(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	write("main.go", "package main")
+	write("pkg/util.go", "package pkg")
+	write("node_modules/lib/index.js", "")
+	write(".git/HEAD", "ref")
+	write("app.log", "")
+	write("bin/app", "")
+	write(".gitignore", "bin/\n")
+
+	opts := Options{Root: root, Ignore: []string{"*.log"}}
+	before, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	var paths []string
+	for path := range before {
+		paths = append(paths, path)
+	}
+	if len(before) != 3 || before["main.go"].size == 0 {
+		t.Errorf("Unexpected snapshot %v", paths)
+	}
+
+	write("main.go", "package main // changed")
+	write("pkg/new.go", "package pkg")
+	os.Remove(filepath.Join(root, "pkg", "util.go"))
+	write("app.log", "ignored")
+
+	after, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	expected := []string{"main.go", "pkg/new.go", "pkg/util.go"}
+	if changed := Diff(before, after); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Diff() = %v, want %v", changed, expected)
+	}
+}
+
+func TestWatchDebounces(t *testing.T) {
+	root := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changes := make(chan []string, 4)
+	go Watch(ctx, Options{Root: root, Interval: 20 * time.Millisecond, Debounce: 100 * time.Millisecond}, func(paths []string) {
+		changes <- paths
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		os.WriteFile(filepath.Join(root, name), []byte(name), 0644)
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	select {
+	case paths := <-changes:
+		if !reflect.DeepEqual(paths, []string{"a.txt", "b.txt"}) {
+			t.Errorf("Expected both files in one change, got %v", paths)
+		}
+	case <-ctx.Done():
+		t.Fatal("No change reported")
+	}
+}